@@ -136,3 +136,14 @@ Return the appropriate apiVersion for a networking object.
 {{- define "networking.apiVersion.supportIngressClassName" -}}
   {{- semverCompare ">=1.18-0" .Capabilities.KubeVersion.GitVersion -}}
 {{- end -}}
+
+{{/*
+Comma-delimited PHASE=DURATION pairs for the API server's retention policy.
+*/}}
+{{- define "brigade.apiserver.retentionPhaseMaxAges" -}}
+{{- $pairs := list -}}
+{{- range $phase, $maxAge := .Values.apiserver.retention.phaseMaxAges -}}
+{{- $pairs = append $pairs (printf "%s=%s" $phase $maxAge) -}}
+{{- end -}}
+{{- join "," $pairs -}}
+{{- end -}}
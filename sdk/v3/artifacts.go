@@ -0,0 +1,218 @@
+package sdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	rm "github.com/brigadecore/brigade/sdk/v3/internal/restmachinery"
+	"github.com/brigadecore/brigade/sdk/v3/meta"
+	"github.com/brigadecore/brigade/sdk/v3/restmachinery"
+)
+
+// ArtifactKind represents the canonical Artifact kind string
+const ArtifactKind = "Artifact"
+
+// Artifact represents a named file that was produced by a Job and retained by
+// Brigade for as long as the Event that spawned the Job.
+type Artifact struct {
+	// Name is a unique (per Job) identifier for the Artifact.
+	Name string `json:"name"`
+	// Size is the size of the Artifact in bytes.
+	Size int64 `json:"size"`
+	// Created indicates the time at which the Artifact was uploaded.
+	Created *time.Time `json:"created,omitempty"`
+}
+
+// MarshalJSON amends Artifact instances with type metadata so that clients do
+// not need to be concerned with the tedium of doing so.
+func (a Artifact) MarshalJSON() ([]byte, error) {
+	type Alias Artifact
+	return json.Marshal(
+		struct {
+			meta.TypeMeta `json:",inline"`
+			Alias         `json:",inline"`
+		}{
+			TypeMeta: meta.TypeMeta{
+				APIVersion: meta.APIVersion,
+				Kind:       ArtifactKind,
+			},
+			Alias: (Alias)(a),
+		},
+	)
+}
+
+// ArtifactList is an ordered and pageable list of Artifacts.
+type ArtifactList struct {
+	// ListMeta contains list metadata.
+	meta.ListMeta `json:"metadata"`
+	// Items is a slice of Artifacts.
+	Items []Artifact `json:"items,omitempty"`
+}
+
+// MarshalJSON amends ArtifactList instances with type metadata so that clients
+// do not need to be concerned with the tedium of doing so.
+func (a ArtifactList) MarshalJSON() ([]byte, error) {
+	type Alias ArtifactList
+	return json.Marshal(
+		struct {
+			meta.TypeMeta `json:",inline"`
+			Alias         `json:",inline"`
+		}{
+			TypeMeta: meta.TypeMeta{
+				APIVersion: meta.APIVersion,
+				Kind:       "ArtifactList",
+			},
+			Alias: (Alias)(a),
+		},
+	)
+}
+
+// ArtifactUploadOptions represents useful, optional settings for uploading an
+// Artifact. It currently has no fields, but exists to preserve the possibility
+// of future expansion without having to change client function signatures.
+type ArtifactUploadOptions struct{}
+
+// ArtifactListOptions represents useful, optional criteria for the retrieval of
+// a list of Artifacts. It currently has no fields, but exists to preserve the
+// possibility of future expansion without having to change client function
+// signatures.
+type ArtifactListOptions struct{}
+
+// ArtifactDownloadOptions represents useful, optional settings for downloading
+// an Artifact. It currently has no fields, but exists to preserve the
+// possibility of future expansion without having to change client function
+// signatures.
+type ArtifactDownloadOptions struct{}
+
+// ArtifactsClient is the specialized client for managing Job Artifacts with the
+// Brigade API.
+type ArtifactsClient interface {
+	// Upload, given an Event identifier, Job name, and Artifact name, stores the
+	// content read from the provided io.Reader as an Artifact of that Job,
+	// replacing any existing Artifact of the same name. This operation is only
+	// permitted to the Event's Worker.
+	Upload(
+		ctx context.Context,
+		eventID string,
+		jobName string,
+		artifactName string,
+		content io.Reader,
+		opts *ArtifactUploadOptions,
+	) (Artifact, error)
+	// List, given an Event identifier and Job name, returns an ArtifactList,
+	// with its Items (Artifacts) ordered alphabetically by name, containing all
+	// of that Job's Artifacts.
+	List(
+		ctx context.Context,
+		eventID string,
+		jobName string,
+		opts *ArtifactListOptions,
+	) (ArtifactList, error)
+	// Download, given an Event identifier, Job name, and Artifact name, returns
+	// an io.ReadCloser from which the Artifact's content can be read. Callers are
+	// responsible for closing the io.ReadCloser.
+	Download(
+		ctx context.Context,
+		eventID string,
+		jobName string,
+		artifactName string,
+		opts *ArtifactDownloadOptions,
+	) (io.ReadCloser, error)
+}
+
+type artifactsClient struct {
+	*rm.BaseClient
+}
+
+// NewArtifactsClient returns a specialized client for managing Job Artifacts.
+func NewArtifactsClient(
+	apiAddress string,
+	apiToken string,
+	opts *restmachinery.APIClientOptions,
+) ArtifactsClient {
+	return &artifactsClient{
+		BaseClient: rm.NewBaseClient(apiAddress, apiToken, opts),
+	}
+}
+
+func (a *artifactsClient) Upload(
+	ctx context.Context,
+	eventID string,
+	jobName string,
+	artifactName string,
+	content io.Reader,
+	_ *ArtifactUploadOptions,
+) (Artifact, error) {
+	artifact := Artifact{}
+	return artifact, a.ExecuteRequest(
+		ctx,
+		rm.OutboundRequest{
+			Method: http.MethodPut,
+			Path: fmt.Sprintf(
+				"v2/events/%s/worker/jobs/%s/artifacts/%s",
+				eventID,
+				jobName,
+				artifactName,
+			),
+			Headers: map[string]string{
+				"Content-Type": "application/octet-stream",
+			},
+			ReqBodyObj:  content,
+			SuccessCode: http.StatusOK,
+			RespObj:     &artifact,
+		},
+	)
+}
+
+func (a *artifactsClient) List(
+	ctx context.Context,
+	eventID string,
+	jobName string,
+	_ *ArtifactListOptions,
+) (ArtifactList, error) {
+	artifacts := ArtifactList{}
+	return artifacts, a.ExecuteRequest(
+		ctx,
+		rm.OutboundRequest{
+			Method: http.MethodGet,
+			Path: fmt.Sprintf(
+				"v2/events/%s/worker/jobs/%s/artifacts",
+				eventID,
+				jobName,
+			),
+			SuccessCode: http.StatusOK,
+			RespObj:     &artifacts,
+		},
+	)
+}
+
+func (a *artifactsClient) Download(
+	ctx context.Context,
+	eventID string,
+	jobName string,
+	artifactName string,
+	_ *ArtifactDownloadOptions,
+) (io.ReadCloser, error) {
+	resp, err := a.SubmitRequest( // nolint: bodyclose
+		ctx,
+		rm.OutboundRequest{
+			Method: http.MethodGet,
+			Path: fmt.Sprintf(
+				"v2/events/%s/worker/jobs/%s/artifacts/%s",
+				eventID,
+				jobName,
+				artifactName,
+			),
+			SuccessCode: http.StatusOK,
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	// The caller is responsible for closing the response body
+	return resp.Body, nil
+}
@@ -0,0 +1,160 @@
+package sdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	rmTesting "github.com/brigadecore/brigade/sdk/v3/internal/restmachinery/testing" // nolint: lll
+	metaTesting "github.com/brigadecore/brigade/sdk/v3/meta/testing"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArtifactMarshalJSON(t *testing.T) {
+	metaTesting.RequireAPIVersionAndType(t, Artifact{}, ArtifactKind)
+}
+
+func TestArtifactListMarshalJSON(t *testing.T) {
+	metaTesting.RequireAPIVersionAndType(t, ArtifactList{}, "ArtifactList")
+}
+
+func TestNewArtifactsClient(t *testing.T) {
+	client, ok := NewArtifactsClient(
+		rmTesting.TestAPIAddress,
+		rmTesting.TestAPIToken,
+		nil,
+	).(*artifactsClient)
+	require.True(t, ok)
+	rmTesting.RequireBaseClient(t, client.BaseClient)
+}
+
+func TestArtifactsClientUpload(t *testing.T) {
+	const testEventID = "12345"
+	const testJobName = "Italian"
+	const testArtifactName = "report.xml"
+	const testContent = "<report/>"
+	testArtifact := Artifact{
+		Name: testArtifactName,
+		Size: int64(len(testContent)),
+	}
+	server := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				require.Equal(t, http.MethodPut, r.Method)
+				require.Equal(
+					t,
+					fmt.Sprintf(
+						"/v2/events/%s/worker/jobs/%s/artifacts/%s",
+						testEventID,
+						testJobName,
+						testArtifactName,
+					),
+					r.URL.Path,
+				)
+				bodyBytes, err := ioutil.ReadAll(r.Body)
+				require.NoError(t, err)
+				require.Equal(t, testContent, string(bodyBytes))
+				bodyBytes, err = json.Marshal(testArtifact)
+				require.NoError(t, err)
+				w.WriteHeader(http.StatusOK)
+				fmt.Fprintln(w, string(bodyBytes))
+			},
+		),
+	)
+	defer server.Close()
+	client := NewArtifactsClient(server.URL, rmTesting.TestAPIToken, nil)
+	artifact, err := client.Upload(
+		context.Background(),
+		testEventID,
+		testJobName,
+		testArtifactName,
+		strings.NewReader(testContent),
+		nil,
+	)
+	require.NoError(t, err)
+	require.Equal(t, testArtifact, artifact)
+}
+
+func TestArtifactsClientList(t *testing.T) {
+	const testEventID = "12345"
+	const testJobName = "Italian"
+	testArtifacts := ArtifactList{
+		Items: []Artifact{
+			{
+				Name: "report.xml",
+			},
+		},
+	}
+	server := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				require.Equal(t, http.MethodGet, r.Method)
+				require.Equal(
+					t,
+					fmt.Sprintf(
+						"/v2/events/%s/worker/jobs/%s/artifacts",
+						testEventID,
+						testJobName,
+					),
+					r.URL.Path,
+				)
+				bodyBytes, err := json.Marshal(testArtifacts)
+				require.NoError(t, err)
+				w.WriteHeader(http.StatusOK)
+				fmt.Fprintln(w, string(bodyBytes))
+			},
+		),
+	)
+	defer server.Close()
+	client := NewArtifactsClient(server.URL, rmTesting.TestAPIToken, nil)
+	artifacts, err :=
+		client.List(context.Background(), testEventID, testJobName, nil)
+	require.NoError(t, err)
+	require.Equal(t, testArtifacts, artifacts)
+}
+
+func TestArtifactsClientDownload(t *testing.T) {
+	const testEventID = "12345"
+	const testJobName = "Italian"
+	const testArtifactName = "report.xml"
+	const testContent = "<report/>"
+	server := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				require.Equal(t, http.MethodGet, r.Method)
+				require.Equal(
+					t,
+					fmt.Sprintf(
+						"/v2/events/%s/worker/jobs/%s/artifacts/%s",
+						testEventID,
+						testJobName,
+						testArtifactName,
+					),
+					r.URL.Path,
+				)
+				w.Header().Set("Content-Type", "application/octet-stream")
+				w.WriteHeader(http.StatusOK)
+				fmt.Fprint(w, testContent)
+			},
+		),
+	)
+	defer server.Close()
+	client := NewArtifactsClient(server.URL, rmTesting.TestAPIToken, nil)
+	content, err := client.Download(
+		context.Background(),
+		testEventID,
+		testJobName,
+		testArtifactName,
+		nil,
+	)
+	require.NoError(t, err)
+	defer content.Close()
+	contentBytes, err := ioutil.ReadAll(content)
+	require.NoError(t, err)
+	require.Equal(t, testContent, string(contentBytes))
+}
@@ -33,4 +33,44 @@ type ContainerSpec struct {
 	// Environment is a map of key/value pairs that specify environment variables
 	// to be set within the OCI container.
 	Environment map[string]string `json:"environment,omitempty"`
+	// Resources optionally specifies the compute resources the OCI container
+	// requires and the most it may consume. Where these are unspecified,
+	// Project-level defaults apply.
+	Resources *ContainerResources `json:"resources,omitempty"`
+}
+
+// ContainerResources specifies the compute resources an OCI container requires
+// and the most it may consume.
+type ContainerResources struct {
+	// Requests specifies compute resources that must be available on a substrate
+	// node for the container to be hosted there.
+	Requests ResourceQuantities `json:"requests,omitempty"`
+	// Limits specifies the most compute resources the container may consume.
+	Limits ResourceQuantities `json:"limits,omitempty"`
+}
+
+// ResourceQuantities specifies amounts of compute resources.
+type ResourceQuantities struct {
+	// CPU specifies an amount of CPU, expressed in cores (e.g. "2" or "0.5") or
+	// millicores (e.g. "500m").
+	CPU string `json:"cpu,omitempty"`
+	// Memory specifies an amount of memory. The value can be expressed in bytes
+	// (as a plain integer) or as a fixed-point integer using one of these
+	// suffixes: E, P, T, G, M, K. Power-of-two equivalents may also be used: Ei,
+	// Pi, Ti, Gi, Mi, Ki.
+	Memory string `json:"memory,omitempty"`
+}
+
+// ContainerStatus represents the status of a single OCI container belonging to
+// a Worker or Job.
+type ContainerStatus struct {
+	// Name is the name of the container.
+	Name string `json:"name"`
+	// ExitCode is the exit code of the container, if it has exited.
+	ExitCode *int32 `json:"exitCode,omitempty"`
+	// Reason is a brief, machine-readable explanation of the container's current
+	// state, e.g. "OOMKilled" or "ImagePullBackOff".
+	Reason string `json:"reason,omitempty"`
+	// Message is a human-readable elaboration on Reason.
+	Message string `json:"message,omitempty"`
 }
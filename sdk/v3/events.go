@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
+	"time"
 
 	rm "github.com/brigadecore/brigade/sdk/v3/internal/restmachinery"
 	"github.com/brigadecore/brigade/sdk/v3/meta"
@@ -77,6 +78,11 @@ type Event struct {
 	ShortTitle string `json:"shortTitle,omitempty"`
 	// LongTitle is an optional, detailed title for the Event.
 	LongTitle string `json:"longTitle,omitempty"`
+	// Priority optionally specifies the relative urgency of the Event, from zero
+	// (the default) through nine. When Workers (and their Jobs) are waiting for
+	// capacity, those belonging to higher priority Events are scheduled first.
+	// The priority is capped by the MaxEventPriority of each subscribed Project.
+	Priority int `json:"priority,omitempty"`
 	// Git contains git-specific Event details. These can be used to override
 	// similar details defined at the Project level. This is useful for scenarios
 	// wherein an Event may need to convey an alternative source, branch, etc.
@@ -97,6 +103,19 @@ type Event struct {
 	Summary string `json:"summary,omitempty" bson:"summary,omitempty"`
 	// Worker contains details of the Worker assigned to handle the Event.
 	Worker *Worker `json:"worker,omitempty"`
+	// IdempotencyKey is the idempotency key, if any, that was specified when the
+	// Event was created. This is set by the system and cannot be set directly by
+	// clients. See EventCreateOptions.
+	IdempotencyKey string `json:"idempotencyKey,omitempty"`
+	// ConcurrencyGroup is the concurrency group, if any, to which the Event
+	// belongs. This is set by the system, by evaluating the ConcurrencyGroup
+	// template of the Project's WorkerSpec, and cannot be set directly by
+	// clients.
+	ConcurrencyGroup string `json:"concurrencyGroup,omitempty"`
+	// Pinned indicates when the Event was pinned. Pinned Events are exempt from
+	// deletion by retention policies. If this field's value is nil, the Event is
+	// not pinned.
+	Pinned *time.Time `json:"pinned,omitempty"`
 }
 
 // MarshalJSON amends Event instances with type metadata so that clients do not
@@ -251,10 +270,81 @@ type DeleteManyEventsResult struct {
 	Count int64 `json:"count"`
 }
 
+// EventMatchResult represents the outcome of evaluating which Projects would
+// receive a prospective Event.
+type EventMatchResult struct {
+	// Subscribers enumerates the IDs of all Projects that would receive the
+	// Event.
+	Subscribers []string `json:"subscribers"`
+	// NonSubscribers describes, for every Project that would NOT receive the
+	// Event, why none of its EventSubscriptions matched the Event.
+	NonSubscribers []ProjectMatchFailure `json:"nonSubscribers"`
+}
+
+// ProjectMatchFailure describes why a Project would not receive an Event.
+type ProjectMatchFailure struct {
+	// ProjectID is the ID of the Project that would not receive the Event.
+	ProjectID string `json:"projectID"`
+	// Subscriptions describes, for each of the Project's EventSubscriptions, the
+	// criteria the Event failed to satisfy. If the Project has no
+	// EventSubscriptions, this is empty.
+	Subscriptions []SubscriptionMatchFailure `json:"subscriptions"`
+}
+
+// SubscriptionMatchFailure describes why an EventSubscription did not match an
+// Event.
+type SubscriptionMatchFailure struct {
+	// Index is the position of the EventSubscription among the Project's
+	// EventSubscriptions.
+	Index int `json:"index"`
+	// Source is the EventSubscription's Source. It is included to help identify
+	// the EventSubscription.
+	Source string `json:"source"`
+	// Mismatches describes each of the EventSubscription's criteria that the
+	// Event failed to satisfy.
+	Mismatches []SubscriptionCriterionMismatch `json:"mismatches"`
+}
+
+// SubscriptionCriterion represents one of the criteria an Event must satisfy to
+// match an EventSubscription.
+type SubscriptionCriterion string
+
+const (
+	// SubscriptionCriterionSource represents the EventSubscription's Source.
+	SubscriptionCriterionSource SubscriptionCriterion = "Source"
+	// SubscriptionCriterionType represents the EventSubscription's Types.
+	SubscriptionCriterionType SubscriptionCriterion = "Type"
+	// SubscriptionCriterionQualifier represents one of the EventSubscription's
+	// Qualifiers.
+	SubscriptionCriterionQualifier SubscriptionCriterion = "Qualifier"
+	// SubscriptionCriterionLabel represents one of the EventSubscription's
+	// Labels.
+	SubscriptionCriterionLabel SubscriptionCriterion = "Label"
+	// SubscriptionCriterionExpression represents the EventSubscription's
+	// Expression.
+	SubscriptionCriterionExpression SubscriptionCriterion = "Expression"
+)
+
+// SubscriptionCriterionMismatch describes an EventSubscription criterion that
+// an Event failed to satisfy.
+type SubscriptionCriterionMismatch struct {
+	// Criterion indicates which criterion the Event failed to satisfy.
+	Criterion SubscriptionCriterion `json:"criterion"`
+	// Reason is a human-readable explanation of the mismatch.
+	Reason string `json:"reason"`
+}
+
 // EventCreateOptions represents useful, optional settings for creating a new
-// Event. It currently has no fields, but exists to preserve the possibility of
-// future expansion without having to change client function signatures.
-type EventCreateOptions struct{}
+// Event.
+type EventCreateOptions struct {
+	// IdempotencyKey is an optional, client-generated key that uniquely
+	// identifies a request to create Events from a given source. If a request
+	// bearing the same key for the same source was already accepted within the
+	// API server's configured idempotency window, the Events created by that
+	// request are returned and no new Events are created. This permits clients,
+	// such as gateways, to safely retry requests.
+	IdempotencyKey string
+}
 
 // EventGetOptions represents useful, optional criteria for retrieval of an
 // Event. It currently has no fields, but exists to preserve the possibility of
@@ -300,19 +390,38 @@ type EventDeleteOptions struct{}
 // future expansion without having to change client function signatures.
 type EventDeleteManyOptions struct{}
 
+// EventPinOptions represents useful, optional settings for pinning an Event.
+// It currently has no fields, but exists to preserve the possibility of future
+// expansion without having to change client function signatures.
+type EventPinOptions struct{}
+
+// EventUnpinOptions represents useful, optional settings for unpinning an
+// Event. It currently has no fields, but exists to preserve the possibility of
+// future expansion without having to change client function signatures.
+type EventUnpinOptions struct{}
+
 // EventRetryOptions represents useful, optional settings for retrying an
 // existing Event. It currently has no fields, but exists to preserve the
 // possibility of future expansion without having to change client function
 // signatures.
 type EventRetryOptions struct{}
 
+// EventMatchOptions represents useful, optional settings for matching a
+// prospective Event to subscribed Projects. It currently has no fields, but
+// exists to preserve the possibility of future expansion without having to
+// change client function signatures.
+type EventMatchOptions struct{}
+
 // EventsClient is the specialized client for managing Events with the Brigade
 // API.
 type EventsClient interface {
 	// Create creates one new Event if the Event provided references a Project by
 	// ID. Otherwise, the Event provided is treated as a template and zero or more
 	// discrete Events may be created-- one for each subscribed Project. An
-	// EventList is returned containing all newly created Events.
+	// EventList is returned containing all newly created Events. If the
+	// EventCreateOptions specify an idempotency key that was already used to
+	// create Events from the same source within the API server's configured
+	// idempotency window, the previously created Events are returned instead.
 	Create(context.Context, Event, *EventCreateOptions) (EventList, error)
 	// List returns an EventList, with its Items (Events) ordered by age, newest
 	// first. Criteria for which Events should be retrieved can be specified using
@@ -357,11 +466,22 @@ type EventsClient interface {
 		EventsSelector,
 		*EventDeleteManyOptions,
 	) (DeleteManyEventsResult, error)
+	// Pin exempts a single Event specified by its identifier from deletion by
+	// any retention policy.
+	Pin(context.Context, string, *EventPinOptions) error
+	// Unpin makes a single Event specified by its identifier subject to
+	// deletion by retention policies again.
+	Unpin(context.Context, string, *EventUnpinOptions) error
 	// Retry copies an Event, including Worker configuration and Jobs, and
 	// creates a new Event from this information. Where possible, job results
 	// are inherited and the job not re-scheduled, for example when a job has
 	// succeeded and does not make use of a shared workspace.
 	Retry(context.Context, string, *EventRetryOptions) (Event, error)
+	// Match determines, without creating the provided Event, which Projects
+	// would receive it and, for each Project that would not, which
+	// EventSubscription criteria the Event failed to satisfy. This is useful for
+	// troubleshooting Events that do not trigger any Workers.
+	Match(context.Context, Event, *EventMatchOptions) (EventMatchResult, error)
 
 	// Workers returns a specialized client for Worker management.
 	Workers() WorkersClient
@@ -392,14 +512,21 @@ func NewEventsClient(
 func (e *eventsClient) Create(
 	ctx context.Context,
 	event Event,
-	_ *EventCreateOptions,
+	opts *EventCreateOptions,
 ) (EventList, error) {
+	var headers map[string]string
+	if opts != nil && opts.IdempotencyKey != "" {
+		headers = map[string]string{
+			"Idempotency-Key": opts.IdempotencyKey,
+		}
+	}
 	events := EventList{}
 	return events, e.ExecuteRequest(
 		ctx,
 		rm.OutboundRequest{
 			Method:      http.MethodPost,
 			Path:        "v2/events",
+			Headers:     headers,
 			ReqBodyObj:  event,
 			SuccessCode: http.StatusCreated,
 			RespObj:     &events,
@@ -509,6 +636,36 @@ func (e *eventsClient) Cancel(
 	)
 }
 
+func (e *eventsClient) Pin(
+	ctx context.Context,
+	id string,
+	_ *EventPinOptions,
+) error {
+	return e.ExecuteRequest(
+		ctx,
+		rm.OutboundRequest{
+			Method:      http.MethodPut,
+			Path:        fmt.Sprintf("v2/events/%s/pin", id),
+			SuccessCode: http.StatusOK,
+		},
+	)
+}
+
+func (e *eventsClient) Unpin(
+	ctx context.Context,
+	id string,
+	_ *EventUnpinOptions,
+) error {
+	return e.ExecuteRequest(
+		ctx,
+		rm.OutboundRequest{
+			Method:      http.MethodDelete,
+			Path:        fmt.Sprintf("v2/events/%s/pin", id),
+			SuccessCode: http.StatusOK,
+		},
+	)
+}
+
 func (e *eventsClient) CancelMany(
 	ctx context.Context,
 	selector EventsSelector,
@@ -579,6 +736,24 @@ func (e *eventsClient) Retry(
 	)
 }
 
+func (e *eventsClient) Match(
+	ctx context.Context,
+	event Event,
+	_ *EventMatchOptions,
+) (EventMatchResult, error) {
+	result := EventMatchResult{}
+	return result, e.ExecuteRequest(
+		ctx,
+		rm.OutboundRequest{
+			Method:      http.MethodPost,
+			Path:        "v2/events/match",
+			ReqBodyObj:  event,
+			SuccessCode: http.StatusOK,
+			RespObj:     &result,
+		},
+	)
+}
+
 func (e *eventsClient) Workers() WorkersClient {
 	return e.workersClient
 }
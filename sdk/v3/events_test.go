@@ -42,6 +42,7 @@ func TestNewEventsClient(t *testing.T) {
 }
 
 func TestEventsClientCreate(t *testing.T) {
+	const testIdempotencyKey = "abc123"
 	testEvent := Event{
 		Payload: "a Tesla roadster",
 	}
@@ -65,6 +66,11 @@ func TestEventsClientCreate(t *testing.T) {
 				defer r.Body.Close()
 				require.Equal(t, http.MethodPost, r.Method)
 				require.Equal(t, "/v2/events", r.URL.Path)
+				require.Equal(
+					t,
+					testIdempotencyKey,
+					r.Header.Get("Idempotency-Key"),
+				)
 				bodyBytes, err := ioutil.ReadAll(r.Body)
 				require.NoError(t, err)
 				event := Event{}
@@ -83,7 +89,9 @@ func TestEventsClientCreate(t *testing.T) {
 	events, err := client.Create(
 		context.Background(),
 		testEvent,
-		nil,
+		&EventCreateOptions{
+			IdempotencyKey: testIdempotencyKey,
+		},
 	)
 	require.NoError(t, err)
 	require.Equal(t, testEvents, events)
@@ -335,6 +343,48 @@ func TestEventsClientCancel(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestEventsClientPin(t *testing.T) {
+	const testEventID = "12345"
+	server := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				require.Equal(t, http.MethodPut, r.Method)
+				require.Equal(
+					t,
+					fmt.Sprintf("/v2/events/%s/pin", testEventID),
+					r.URL.Path,
+				)
+				w.WriteHeader(http.StatusOK)
+			},
+		),
+	)
+	defer server.Close()
+	client := NewEventsClient(server.URL, rmTesting.TestAPIToken, nil)
+	err := client.Pin(context.Background(), testEventID, nil)
+	require.NoError(t, err)
+}
+
+func TestEventsClientUnpin(t *testing.T) {
+	const testEventID = "12345"
+	server := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				require.Equal(t, http.MethodDelete, r.Method)
+				require.Equal(
+					t,
+					fmt.Sprintf("/v2/events/%s/pin", testEventID),
+					r.URL.Path,
+				)
+				w.WriteHeader(http.StatusOK)
+			},
+		),
+	)
+	defer server.Close()
+	client := NewEventsClient(server.URL, rmTesting.TestAPIToken, nil)
+	err := client.Unpin(context.Background(), testEventID, nil)
+	require.NoError(t, err)
+}
+
 func TestEventsClientCancelMany(t *testing.T) {
 	const testProjectID = "bluebook"
 	const testSource = "foo-gateway"
@@ -553,3 +603,52 @@ func TestEventsClientRetry(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, testEvent, event)
 }
+
+func TestEventsClientMatch(t *testing.T) {
+	testEvent := Event{
+		Source: "brigade.sh/cli",
+		Type:   "exec",
+	}
+	testResult := EventMatchResult{
+		Subscribers: []string{"italian"},
+		NonSubscribers: []ProjectMatchFailure{
+			{
+				ProjectID: "greek",
+				Subscriptions: []SubscriptionMatchFailure{
+					{
+						Source: "brigade.sh/cli",
+						Mismatches: []SubscriptionCriterionMismatch{
+							{
+								Criterion: SubscriptionCriterionType,
+								Reason:    "event type \"exec\" is not among [\"foo\"]",
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	server := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				require.Equal(t, http.MethodPost, r.Method)
+				require.Equal(t, "/v2/events/match", r.URL.Path)
+				bodyBytes, err := ioutil.ReadAll(r.Body)
+				require.NoError(t, err)
+				event := Event{}
+				err = json.Unmarshal(bodyBytes, &event)
+				require.NoError(t, err)
+				require.Equal(t, testEvent, event)
+				bodyBytes, err = json.Marshal(testResult)
+				require.NoError(t, err)
+				w.WriteHeader(http.StatusOK)
+				fmt.Fprintln(w, string(bodyBytes))
+			},
+		),
+	)
+	defer server.Close()
+	client := NewEventsClient(server.URL, rmTesting.TestAPIToken, nil)
+	result, err := client.Match(context.Background(), testEvent, nil)
+	require.NoError(t, err)
+	require.Equal(t, testResult, result)
+}
@@ -0,0 +1,105 @@
+package sdk
+
+// TolerationOperator represents how a Toleration's value is compared to that
+// of a taint.
+type TolerationOperator string
+
+const (
+	// TolerationOperatorEqual represents a Toleration that matches taints having
+	// the same key and value.
+	TolerationOperatorEqual TolerationOperator = "Equal"
+	// TolerationOperatorExists represents a Toleration that matches taints
+	// having the same key, regardless of value.
+	TolerationOperatorExists TolerationOperator = "Exists"
+)
+
+// TaintEffect represents the effect a taint has on Workers and Jobs that do
+// not tolerate it.
+type TaintEffect string
+
+const (
+	// TaintEffectNoSchedule represents a taint that prevents Workers and Jobs
+	// that do not tolerate it from being hosted on a substrate node.
+	TaintEffectNoSchedule TaintEffect = "NoSchedule"
+	// TaintEffectPreferNoSchedule represents a taint that discourages Workers
+	// and Jobs that do not tolerate it from being hosted on a substrate node.
+	TaintEffectPreferNoSchedule TaintEffect = "PreferNoSchedule"
+	// TaintEffectNoExecute represents a taint that evicts Workers and Jobs that
+	// do not tolerate it from a substrate node.
+	TaintEffectNoExecute TaintEffect = "NoExecute"
+)
+
+// Toleration permits a Worker or Job to be hosted on substrate nodes having a
+// matching taint.
+type Toleration struct {
+	// Key is the taint key the Toleration applies to. An empty key with the
+	// Exists operator matches all taints.
+	Key string `json:"key,omitempty"`
+	// Operator specifies how the Toleration's value is compared to that of a
+	// taint. If not specified, this defaults to Equal.
+	Operator TolerationOperator `json:"operator,omitempty"`
+	// Value is the taint value the Toleration matches. It must be empty if the
+	// Operator is Exists.
+	Value string `json:"value,omitempty"`
+	// Effect optionally specifies the taint effect the Toleration matches. If
+	// not specified, the Toleration matches all taint effects.
+	Effect TaintEffect `json:"effect,omitempty"`
+}
+
+// NodeSelectorOperator represents how the value of a substrate node's label is
+// compared to the values in a NodeSelectorRequirement.
+type NodeSelectorOperator string
+
+const (
+	// NodeSelectorOperatorIn requires the label's value to be one of the
+	// requirement's values.
+	NodeSelectorOperatorIn NodeSelectorOperator = "In"
+	// NodeSelectorOperatorNotIn requires the label's value to be none of the
+	// requirement's values, or the label to be absent.
+	NodeSelectorOperatorNotIn NodeSelectorOperator = "NotIn"
+	// NodeSelectorOperatorExists requires the label to be present.
+	NodeSelectorOperatorExists NodeSelectorOperator = "Exists"
+	// NodeSelectorOperatorDoesNotExist requires the label to be absent.
+	NodeSelectorOperatorDoesNotExist NodeSelectorOperator = "DoesNotExist"
+	// NodeSelectorOperatorGt requires the label's value, interpreted as an
+	// integer, to be greater than the requirement's one value.
+	NodeSelectorOperatorGt NodeSelectorOperator = "Gt"
+	// NodeSelectorOperatorLt requires the label's value, interpreted as an
+	// integer, to be less than the requirement's one value.
+	NodeSelectorOperatorLt NodeSelectorOperator = "Lt"
+)
+
+// NodeSelectorRequirement represents a requirement placed on the value of a
+// substrate node's label.
+type NodeSelectorRequirement struct {
+	// Key is the label key the requirement applies to.
+	Key string `json:"key"`
+	// Operator specifies how the label's value is compared to Values.
+	Operator NodeSelectorOperator `json:"operator"`
+	// Values must be non-empty if the Operator is In or NotIn, must be empty if
+	// the Operator is Exists or DoesNotExist, and must contain exactly one
+	// integer if the Operator is Gt or Lt.
+	Values []string `json:"values,omitempty"`
+}
+
+// PreferredNodeSelectorRequirement represents a NodeSelectorRequirement that
+// substrate nodes should preferably, but need not, satisfy.
+type PreferredNodeSelectorRequirement struct {
+	// Weight indicates how strongly the requirement is preferred, relative to
+	// other preferred requirements. It must be between 1 and 100.
+	Weight int `json:"weight"`
+	// NodeSelectorRequirement is the preferred requirement.
+	NodeSelectorRequirement
+}
+
+// NodeAffinity represents requirements, beyond simple node selectors, that a
+// substrate node must or should satisfy to host a Worker or Job. Note that
+// anti-affinity is expressed using the NotIn and DoesNotExist operators.
+type NodeAffinity struct {
+	// Required enumerates requirements that a substrate node must satisfy, in
+	// their entirety, to host the Worker or Job.
+	Required []NodeSelectorRequirement `json:"required,omitempty"`
+	// Preferred enumerates requirements that substrate nodes should preferably
+	// satisfy to host the Worker or Job.
+	Preferred []PreferredNodeSelectorRequirement `json:"preferred,omitempty"`
+}
@@ -119,6 +119,8 @@ func (b *BaseClient) SubmitRequest(
 		switch rb := req.ReqBodyObj.(type) {
 		case []byte:
 			reqBodyReader = bytes.NewBuffer(rb)
+		case io.Reader:
+			reqBodyReader = rb
 		default:
 			reqBodyBytes, err := json.Marshal(req.ReqBodyObj)
 			if err != nil {
@@ -15,7 +15,8 @@ type OutboundRequest struct {
 	// Headers optionally specifies any miscellaneous HTTP headers to be used.
 	Headers map[string]string
 	// ReqBodyObj optionally provides an object that can be marshaled to create
-	// the body of the HTTP request.
+	// the body of the HTTP request. Raw bytes and io.Readers are used as the
+	// body as is.
 	ReqBodyObj interface{}
 	// SuccessCode specifies what HTTP response code should indicate a successful
 	// API call.
@@ -0,0 +1,58 @@
+package sdk
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	rm "github.com/brigadecore/brigade/sdk/v3/internal/restmachinery"
+	"github.com/brigadecore/brigade/sdk/v3/restmachinery"
+)
+
+// JobCachePurgeOptions represents useful, optional settings for purging a
+// Project's cached Job results. It currently has no fields, but exists to
+// preserve the possibility of future expansion without having to change client
+// function signatures.
+type JobCachePurgeOptions struct{}
+
+// JobCacheClient is the specialized client for managing Projects' cached Job
+// results with the Brigade API.
+type JobCacheClient interface {
+	// Purge discards all of the specified Project's cached Job results.
+	Purge(
+		ctx context.Context,
+		projectID string,
+		opts *JobCachePurgeOptions,
+	) error
+}
+
+type jobCacheClient struct {
+	*rm.BaseClient
+}
+
+// NewJobCacheClient returns a specialized client for managing Projects' cached
+// Job results.
+func NewJobCacheClient(
+	apiAddress string,
+	apiToken string,
+	opts *restmachinery.APIClientOptions,
+) JobCacheClient {
+	return &jobCacheClient{
+		BaseClient: rm.NewBaseClient(apiAddress, apiToken, opts),
+	}
+}
+
+func (j *jobCacheClient) Purge(
+	ctx context.Context,
+	projectID string,
+	_ *JobCachePurgeOptions,
+) error {
+	return j.ExecuteRequest(
+		ctx,
+		rm.OutboundRequest{
+			Method:      http.MethodDelete,
+			Path:        fmt.Sprintf("v2/projects/%s/job-cache", projectID),
+			SuccessCode: http.StatusOK,
+		},
+	)
+}
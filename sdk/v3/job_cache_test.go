@@ -0,0 +1,43 @@
+package sdk
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	rmTesting "github.com/brigadecore/brigade/sdk/v3/internal/restmachinery/testing" // nolint: lll
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewJobCacheClient(t *testing.T) {
+	client, ok := NewJobCacheClient(
+		rmTesting.TestAPIAddress,
+		rmTesting.TestAPIToken,
+		nil,
+	).(*jobCacheClient)
+	require.True(t, ok)
+	rmTesting.RequireBaseClient(t, client.BaseClient)
+}
+
+func TestJobCacheClientPurge(t *testing.T) {
+	const testProjectID = "bluebook"
+	server := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				require.Equal(t, http.MethodDelete, r.Method)
+				require.Equal(
+					t,
+					fmt.Sprintf("/v2/projects/%s/job-cache", testProjectID),
+					r.URL.Path,
+				)
+				w.WriteHeader(http.StatusOK)
+			},
+		),
+	)
+	defer server.Close()
+	client := NewJobCacheClient(server.URL, rmTesting.TestAPIToken, nil)
+	err := client.Purge(context.Background(), testProjectID, nil)
+	require.NoError(t, err)
+}
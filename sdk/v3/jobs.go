@@ -152,6 +152,57 @@ type JobSpec struct {
 	// schema-based validation will reject the unknown field) as long as it's not
 	// set to true.
 	Fallible bool `json:"fallible,omitempty"`
+	// RetryPolicy optionally specifies whether and how the Job should be
+	// automatically re-attempted if it does not succeed.
+	RetryPolicy *JobRetryPolicy `json:"retryPolicy,omitempty"`
+	// DependsOn optionally lists the names of other Jobs of the same Event that
+	// must succeed before this Job may be started. If any of them does not
+	// succeed, this Job is canceled without ever having been started.
+	DependsOn []string `json:"dependsOn,omitempty"`
+	// Cache optionally opts the Job into cross-Event result caching.
+	Cache *JobCachePolicy `json:"cache,omitempty"`
+}
+
+// JobRetryPolicy describes whether and how a Job should be automatically
+// re-attempted if it does not succeed.
+type JobRetryPolicy struct {
+	// MaxAttempts specifies how many times, in total, the Job may be attempted.
+	// It must be at least 1.
+	MaxAttempts int `json:"maxAttempts"`
+	// BackoffDuration optionally specifies how long to wait after the first
+	// unsuccessful attempt before attempting the Job again. The wait doubles
+	// after every subsequent unsuccessful attempt. This duration string is a
+	// sequence of decimal numbers, each with optional fraction and a unit suffix,
+	// such as "30s" or "1m30s". Valid time units are "ns", "us" (or "µs"), "ms",
+	// "s", "m", "h". If unspecified, the Job is attempted again right away.
+	BackoffDuration string `json:"backoffDuration,omitempty"`
+	// RetryablePhases optionally enumerates the terminal JobPhases in which an
+	// attempt may conclude and still be followed by another. Valid values are
+	// FAILED and TIMED_OUT. If unspecified, both are retryable.
+	RetryablePhases []JobPhase `json:"retryablePhases,omitempty"`
+	// RetryableExitCodes optionally enumerates exit codes of the Job's primary
+	// container. If specified, a FAILED attempt is only followed by another if
+	// the primary container exited with one of these codes. Attempts that TIMED
+	// OUT are unaffected by this setting.
+	RetryableExitCodes []int32 `json:"retryableExitCodes,omitempty"`
+}
+
+// JobCachePolicy opts a Job into cross-Event result caching. If an earlier
+// Event for the same Project already succeeded with an equivalent Job having
+// the same cache key, the Job is not run and the earlier Job's results are
+// reused instead.
+type JobCachePolicy struct {
+	// Key optionally specifies an arbitrary value that, along with the Job's
+	// name and specification, identifies the Job's results. If not specified,
+	// the git commit the Event's Worker is checked out at is used instead. If
+	// neither is available, the Job is not cached.
+	Key string `json:"key,omitempty"`
+	// TTLDuration optionally specifies how long the Job's results remain
+	// eligible for reuse. This duration string is a sequence of decimal numbers,
+	// each with optional fraction and a unit suffix, such as "12h" or "1h30m".
+	// Valid time units are "ns", "us" (or "µs"), "ms", "s", "m", "h". If
+	// unspecified, this defaults to seven days. It may not exceed thirty days.
+	TTLDuration string `json:"ttlDuration,omitempty"`
 }
 
 // JobContainerSpec amends the ContainerSpec type with additional Job-specific
@@ -177,6 +228,10 @@ type JobContainerSpec struct {
 	// for the container, but that may be disallowed by Project-level
 	// configuration.
 	Privileged bool `json:"privileged"`
+	// Volumes optionally specifies additional volumes to be mounted into the OCI
+	// container. Note the types of volumes that may be mounted are subject to
+	// Project-level configuration.
+	Volumes []JobVolume `json:"volumes,omitempty"`
 	// UseHostDockerSocket indicates whether the OCI container should mount the
 	// host's Docker socket into its own file system. This is commonly used to
 	// effect "Docker-out-of-Docker" ("DooD") scenarios wherein one of a Job's OCI
@@ -195,6 +250,81 @@ type JobContainerSpec struct {
 	// UseHostDockerSocket bool `json:"useHostDockerSocket"`
 }
 
+// JobVolumeType represents a type of additional volume that a Job's OCI
+// containers may mount.
+type JobVolumeType string
+
+const (
+	// JobVolumeTypeSecrets represents a volume containing selected Project
+	// secrets as files.
+	JobVolumeTypeSecrets JobVolumeType = "secrets"
+	// JobVolumeTypeConfigFiles represents a volume containing files whose
+	// content is specified inline.
+	JobVolumeTypeConfigFiles JobVolumeType = "configFiles"
+	// JobVolumeTypeTmpfs represents memory-backed scratch space.
+	JobVolumeTypeTmpfs JobVolumeType = "tmpfs"
+	// JobVolumeTypeCache represents a named, Project-wide volume whose contents
+	// persist across Events.
+	JobVolumeTypeCache JobVolumeType = "cache"
+)
+
+// JobVolume represents an additional volume to be mounted into one of a Job's
+// OCI containers. Exactly one of Secrets, ConfigFiles, Tmpfs, or Cache must be
+// specified. Note the types of volumes that may be mounted are subject to
+// Project-level configuration.
+type JobVolume struct {
+	// MountPath specifies the path in the OCI container's file system where the
+	// volume should be mounted.
+	MountPath string `json:"mountPath"`
+	// ReadOnly indicates whether the volume should be mounted read-only. Volumes
+	// containing secrets or config files are always mounted read-only.
+	ReadOnly bool `json:"readOnly,omitempty"`
+	// Secrets specifies a volume containing selected Project secrets as files.
+	Secrets *SecretsVolumeSource `json:"secrets,omitempty"`
+	// ConfigFiles specifies a volume containing files whose content is specified
+	// inline.
+	ConfigFiles *ConfigFilesVolumeSource `json:"configFiles,omitempty"`
+	// Tmpfs specifies memory-backed scratch space.
+	Tmpfs *TmpfsVolumeSource `json:"tmpfs,omitempty"`
+	// Cache specifies a named, Project-wide volume whose contents persist across
+	// Events.
+	Cache *CacheVolumeSource `json:"cache,omitempty"`
+}
+
+// SecretsVolumeSource represents a volume containing selected Project secrets
+// as files.
+type SecretsVolumeSource struct {
+	// Keys enumerates the keys of the Project secrets to include in the volume.
+	// Each secret is represented by a file named after its key.
+	Keys []string `json:"keys"`
+}
+
+// ConfigFilesVolumeSource represents a volume containing files whose content
+// is specified inline.
+type ConfigFilesVolumeSource struct {
+	// Files is a map of file names to file contents. File contents are redacted
+	// when the Job is retrieved via the API.
+	Files map[string]string `json:"files"`
+}
+
+// TmpfsVolumeSource represents memory-backed scratch space.
+type TmpfsVolumeSource struct {
+	// SizeLimit optionally limits how much memory the volume may consume.
+	SizeLimit string `json:"sizeLimit,omitempty"`
+}
+
+// CacheVolumeSource represents a named, Project-wide volume whose contents
+// persist across Events. Any of the Project's Jobs that mount a cache volume
+// having the same name share the same underlying storage.
+type CacheVolumeSource struct {
+	// Name identifies the cache volume.
+	Name string `json:"name"`
+	// Size optionally specifies the size of the cache volume. It is only
+	// honored when the cache volume is first used. If not specified, this
+	// defaults to 1G.
+	Size string `json:"size,omitempty"`
+}
+
 // JobHost represents criteria for selecting a suitable host (substrate node)
 // for a Job.
 type JobHost struct {
@@ -206,6 +336,18 @@ type JobHost struct {
 	// host a Job. This provides an opaque mechanism for communicating Job needs
 	// such as specific hardware like an SSD or GPU.
 	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+	// Tolerations permit the Job to be hosted on substrate nodes having matching
+	// taints. Note the Tolerations a Job may specify are subject to
+	// Project-level configuration.
+	Tolerations []Toleration `json:"tolerations,omitempty"`
+	// NodeAffinity specifies requirements, beyond NodeSelector, that a substrate
+	// node must or should satisfy to host the Job. Note whether a Job may specify
+	// NodeAffinity is subject to Project-level configuration.
+	NodeAffinity *NodeAffinity `json:"nodeAffinity,omitempty"`
+	// PriorityClassName specifies the substrate's priority class for the Job.
+	// Note the priority classes a Job may use are subject to Project-level
+	// configuration.
+	PriorityClassName string `json:"priorityClassName,omitempty"`
 }
 
 // JobStatus represents the status of a Job.
@@ -217,6 +359,48 @@ type JobStatus struct {
 	Ended *time.Time `json:"ended,omitempty"`
 	// Phase indicates where the Job is in its lifecycle.
 	Phase JobPhase `json:"phase,omitempty"`
+	// Attempt indicates which attempt at the Job this status describes. Attempts
+	// are numbered from 1. A value of 0 is equivalent to 1.
+	Attempt int `json:"attempt,omitempty"`
+	// ExitCode is the exit code of the Job's primary container, if it has exited.
+	ExitCode *int32 `json:"exitCode,omitempty"`
+	// Reason is a brief, machine-readable explanation of why the Job is in its
+	// current phase, e.g. "OOMKilled", "ImagePullBackOff", or "Unschedulable".
+	Reason string `json:"reason,omitempty"`
+	// Message is a human-readable elaboration on Reason.
+	Message string `json:"message,omitempty"`
+	// Containers describes the status of each of the Job's containers,
+	// including sidecars.
+	Containers []ContainerStatus `json:"containers,omitempty"`
+	// NextAttempt indicates when the Job, having been PENDING since its previous
+	// attempt did not succeed, will next be attempted.
+	NextAttempt *time.Time `json:"nextAttempt,omitempty"`
+	// Attempts lists every concluded attempt at a Job having a RetryPolicy,
+	// oldest first.
+	Attempts []JobAttempt `json:"attempts,omitempty"`
+	// Outputs is a map of key/value pairs reported by the Job itself. These
+	// allow a Job to hand values (e.g. a built image's digest) back to the
+	// Worker, to subsequent Jobs, and to other API consumers.
+	Outputs map[string]string `json:"outputs,omitempty"`
+}
+
+// JobAttempt represents a single concluded attempt at a Job.
+type JobAttempt struct {
+	// Attempt is the number of the attempt. Attempts are numbered from 1.
+	Attempt int `json:"attempt"`
+	// Started indicates the time the attempt began execution.
+	Started *time.Time `json:"started,omitempty"`
+	// Ended indicates the time the attempt concluded execution.
+	Ended *time.Time `json:"ended,omitempty"`
+	// Phase indicates the terminal phase in which the attempt concluded.
+	Phase JobPhase `json:"phase"`
+	// ExitCode is the exit code of the Job's primary container, if it exited.
+	ExitCode *int32 `json:"exitCode,omitempty"`
+	// Reason is a brief, machine-readable explanation of why the attempt
+	// concluded in the phase it did.
+	Reason string `json:"reason,omitempty"`
+	// Message is a human-readable elaboration on Reason.
+	Message string `json:"message,omitempty"`
 }
 
 // MarshalJSON amends JobStatus instances with type metadata so that clients do
@@ -237,6 +421,30 @@ func (j JobStatus) MarshalJSON() ([]byte, error) {
 	)
 }
 
+// JobOutputs encapsulates key/value pairs reported by a Job.
+type JobOutputs struct {
+	// Outputs is a map of key/value pairs reported by a Job.
+	Outputs map[string]string `json:"outputs,omitempty"`
+}
+
+// MarshalJSON amends JobOutputs instances with type metadata so that clients
+// do not need to be concerned with the tedium of doing so.
+func (j JobOutputs) MarshalJSON() ([]byte, error) {
+	type Alias JobOutputs
+	return json.Marshal(
+		struct {
+			meta.TypeMeta `json:",inline"`
+			Alias         `json:",inline"`
+		}{
+			TypeMeta: meta.TypeMeta{
+				APIVersion: meta.APIVersion,
+				Kind:       "JobOutputs",
+			},
+			Alias: (Alias)(j),
+		},
+	)
+}
+
 // JobCreateOptions represents useful, optional settings for creating a new
 // Job. It currently has no fields, but exists to preserve the possibility of
 // future expansion without having to change client function signatures.
@@ -265,6 +473,12 @@ type JobStatusWatchOptions struct{}
 // signatures.
 type JobStatusUpdateOptions struct{}
 
+// JobOutputsUpdateOptions represents useful, optional settings for updating a
+// Job's outputs. It currently has no fields, but exists to preserve the
+// possibility of future expansion without having to change client function
+// signatures.
+type JobOutputsUpdateOptions struct{}
+
 // JobCleanupOptions represents useful, optional settings for cleaning up after
 // a Job. It currently has no fields, but exists to preserve the possibility of
 // future expansion without having to change client function signatures.
@@ -275,6 +489,11 @@ type JobCleanupOptions struct{}
 // expansion without having to change client function signatures.
 type JobTimeoutOptions struct{}
 
+// JobAbortOptions represents useful, optional settings for aborting a Job. It
+// currently has no fields, but exists to preserve the possibility of future
+// expansion without having to change client function signatures.
+type JobAbortOptions struct{}
+
 // JobsClient is the specialized client for managing Event Jobs with the
 // Brigade API.
 type JobsClient interface {
@@ -319,6 +538,16 @@ type JobsClient interface {
 		status JobStatus,
 		opts *JobStatusUpdateOptions,
 	) error
+	// UpdateOutputs, given an Event identifier and Job name, merges the provided
+	// key/value pairs into that Job's outputs, replacing the values of any
+	// existing keys. This operation is only permitted to the Event's Worker.
+	UpdateOutputs(
+		ctx context.Context,
+		eventID string,
+		jobName string,
+		outputs JobOutputs,
+		opts *JobOutputsUpdateOptions,
+	) error
 	Cleanup(
 		ctx context.Context,
 		eventID,
@@ -333,10 +562,22 @@ type JobsClient interface {
 		jobName string,
 		opts *JobTimeoutOptions,
 	) error
+	// Abort, given an Event identifier and Job name, stops that Job and marks it
+	// as ABORTED. The Event's Worker and any other Jobs are unaffected.
+	Abort(
+		ctx context.Context,
+		eventID,
+		jobName string,
+		opts *JobAbortOptions,
+	) error
+
+	// Artifacts returns a specialized client for Artifact management.
+	Artifacts() ArtifactsClient
 }
 
 type jobsClient struct {
 	*rm.BaseClient
+	artifactsClient ArtifactsClient
 }
 
 // NewJobsClient returns a specialized client for managing Event Jobs.
@@ -346,7 +587,8 @@ func NewJobsClient(
 	opts *restmachinery.APIClientOptions,
 ) JobsClient {
 	return &jobsClient{
-		BaseClient: rm.NewBaseClient(apiAddress, apiToken, opts),
+		BaseClient:      rm.NewBaseClient(apiAddress, apiToken, opts),
+		artifactsClient: NewArtifactsClient(apiAddress, apiToken, opts),
 	}
 }
 
@@ -465,6 +707,28 @@ func (j *jobsClient) UpdateStatus(
 	)
 }
 
+func (j *jobsClient) UpdateOutputs(
+	ctx context.Context,
+	eventID string,
+	jobName string,
+	outputs JobOutputs,
+	_ *JobOutputsUpdateOptions,
+) error {
+	return j.ExecuteRequest(
+		ctx,
+		rm.OutboundRequest{
+			Method: http.MethodPut,
+			Path: fmt.Sprintf(
+				"v2/events/%s/worker/jobs/%s/outputs",
+				eventID,
+				jobName,
+			),
+			ReqBodyObj:  outputs,
+			SuccessCode: http.StatusOK,
+		},
+	)
+}
+
 func (j *jobsClient) Cleanup(
 	ctx context.Context,
 	eventID,
@@ -505,6 +769,30 @@ func (j *jobsClient) Timeout(
 	)
 }
 
+func (j *jobsClient) Abort(
+	ctx context.Context,
+	eventID,
+	jobName string,
+	_ *JobAbortOptions,
+) error {
+	return j.ExecuteRequest(
+		ctx,
+		rm.OutboundRequest{
+			Method: http.MethodPut,
+			Path: fmt.Sprintf(
+				"v2/events/%s/worker/jobs/%s/abort",
+				eventID,
+				jobName,
+			),
+			SuccessCode: http.StatusOK,
+		},
+	)
+}
+
+func (j *jobsClient) Artifacts() ArtifactsClient {
+	return j.artifactsClient
+}
+
 func (j *jobsClient) receiveStatusStream(
 	ctx context.Context,
 	reader io.ReadCloser,
@@ -23,6 +23,22 @@ func TestJobStatusMarshalJSON(t *testing.T) {
 	metaTesting.RequireAPIVersionAndType(t, JobStatus{}, "JobStatus")
 }
 
+func TestJobOutputsMarshalJSON(t *testing.T) {
+	metaTesting.RequireAPIVersionAndType(t, JobOutputs{}, "JobOutputs")
+}
+
+func TestNewJobsClient(t *testing.T) {
+	client, ok := NewJobsClient(
+		rmTesting.TestAPIAddress,
+		rmTesting.TestAPIToken,
+		nil,
+	).(*jobsClient)
+	require.True(t, ok)
+	rmTesting.RequireBaseClient(t, client.BaseClient)
+	require.NotNil(t, client.artifactsClient)
+	require.Equal(t, client.artifactsClient, client.Artifacts())
+}
+
 func TestJobsClientCreate(t *testing.T) {
 	const testEventID = "12345"
 	const testJobName = "Italian"
@@ -225,6 +241,51 @@ func TestJobClientUpdateStatus(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestJobClientUpdateOutputs(t *testing.T) {
+	const testEventID = "12345"
+	const testJobName = "Italian"
+	testJobOutputs := JobOutputs{
+		Outputs: map[string]string{
+			"digest": "sha256:abcdef",
+		},
+	}
+	server := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				defer r.Body.Close()
+				require.Equal(t, http.MethodPut, r.Method)
+				require.Equal(
+					t,
+					fmt.Sprintf(
+						"/v2/events/%s/worker/jobs/%s/outputs",
+						testEventID,
+						testJobName,
+					),
+					r.URL.Path,
+				)
+				bodyBytes, err := ioutil.ReadAll(r.Body)
+				require.NoError(t, err)
+				jobOutputs := JobOutputs{}
+				err = json.Unmarshal(bodyBytes, &jobOutputs)
+				require.NoError(t, err)
+				require.Equal(t, testJobOutputs, jobOutputs)
+				w.WriteHeader(http.StatusOK)
+				fmt.Fprintln(w, "{}")
+			},
+		),
+	)
+	defer server.Close()
+	client := NewJobsClient(server.URL, rmTesting.TestAPIToken, nil)
+	err := client.UpdateOutputs(
+		context.Background(),
+		testEventID,
+		testJobName,
+		testJobOutputs,
+		nil,
+	)
+	require.NoError(t, err)
+}
+
 func TestJobClientCleanup(t *testing.T) {
 	const testEventID = "12345"
 	const testJobName = "Italian"
@@ -290,3 +351,36 @@ func TestJobClientTimeout(t *testing.T) {
 	)
 	require.NoError(t, err)
 }
+
+func TestJobClientAbort(t *testing.T) {
+	const testEventID = "12345"
+	const testJobName = "Italian"
+	server := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				defer r.Body.Close()
+				require.Equal(t, http.MethodPut, r.Method)
+				require.Equal(
+					t,
+					fmt.Sprintf(
+						"/v2/events/%s/worker/jobs/%s/abort",
+						testEventID,
+						testJobName,
+					),
+					r.URL.Path,
+				)
+				w.WriteHeader(http.StatusOK)
+				fmt.Fprintln(w, "{}")
+			},
+		),
+	)
+	defer server.Close()
+	client := NewJobsClient(server.URL, rmTesting.TestAPIToken, nil)
+	err := client.Abort(
+		context.Background(),
+		testEventID,
+		testJobName,
+		nil,
+	)
+	require.NoError(t, err)
+}
@@ -6,9 +6,11 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"strconv"
 	"time"
 
 	rm "github.com/brigadecore/brigade/sdk/v3/internal/restmachinery"
+	"github.com/brigadecore/brigade/sdk/v3/meta"
 	"github.com/brigadecore/brigade/sdk/v3/restmachinery"
 )
 
@@ -18,6 +20,13 @@ type LogEntry struct {
 	Time *time.Time `json:"time,omitempty"`
 	// Message is a single line of log output from an OCI container.
 	Message string `json:"message,omitempty"`
+	// Job is the name of the Job whose logs the line belongs to. It is only
+	// populated when streaming logs from all of an Event's containers and is
+	// left empty for lines belonging to the Event's Worker.
+	Job string `json:"job,omitempty"`
+	// Container is the name of the container whose logs the line belongs to. It
+	// is only populated when streaming logs from all of an Event's containers.
+	Container string `json:"container,omitempty"`
 }
 
 // LogsSelector represents useful criteria for selecting logs to be streamed
@@ -32,6 +41,16 @@ type LogsSelector struct {
 	// presume logs are desired from a container having the same name as the
 	// selected Worker or Job.
 	Container string
+	// Attempt specifies, by number, an attempt at the Job specified by Job.
+	// Attempts are numbered from 1. If not specified, log streaming operations
+	// presume logs are desired from the Job's current or most recent attempt.
+	Attempt int
+	// All indicates that logs should be streamed from the Worker and from every
+	// container of every Job spawned by that Worker, interleaved by the time
+	// each line was written. Jobs spawned after streaming begins are picked up
+	// automatically if the stream is being followed. All may not be combined
+	// with Job, Container, or Attempt.
+	All bool
 }
 
 // LogStreamOptions represents useful options for streaming logs from some
@@ -42,8 +61,100 @@ type LogStreamOptions struct {
 	// until closed by the client (true), continuing to send new lines as they
 	// become available.
 	Follow bool `json:"follow"`
+	// TailLines, if greater than zero, limits the stream to beginning with the
+	// specified number of most recent lines of logs, instead of replaying all
+	// available lines.
+	TailLines int64 `json:"tailLines,omitempty"`
+	// Since, if specified, excludes lines of logs written before the specified
+	// time.
+	Since *time.Time `json:"since,omitempty"`
+	// Until, if specified, excludes lines of logs written after the specified
+	// time. When following, the stream concludes once a line written after the
+	// specified time is encountered.
+	Until *time.Time `json:"until,omitempty"`
 }
 
+// LogSearchCriteria represents criteria for selecting lines of logs in a
+// search.
+type LogSearchCriteria struct {
+	// Pattern is a substring or, if Regex is true, a regular expression that
+	// selected lines of logs must contain or match, respectively.
+	Pattern string
+	// Regex indicates whether Pattern is a regular expression.
+	Regex bool
+	// IgnoreCase indicates whether Pattern should be matched without regard to
+	// case.
+	IgnoreCase bool
+}
+
+// LogSearchOptions represents useful options for searching logs.
+type LogSearchOptions struct {
+	// ContextLines specifies how many lines of logs to return before and after
+	// each matching line. It may not exceed 10.
+	ContextLines int
+	// Limit specifies the most matching lines of logs to return. If not
+	// specified, this defaults to 100. It may not exceed 1000.
+	Limit int
+	// Events specifies how many of a Project's most recent Events to search when
+	// searching a Project's logs. If not specified, this defaults to 10. It may
+	// not exceed 100.
+	Events int
+}
+
+// LogSearchMatch represents a single line of logs matched by a search, along
+// with the surrounding lines and details of where it was found.
+type LogSearchMatch struct {
+	// EventID is the ID of the Event whose logs the line belongs to.
+	EventID string `json:"eventID"`
+	// Job is the name of the Job whose logs the line belongs to. If empty, the
+	// line belongs to the Event's Worker's logs.
+	Job string `json:"job,omitempty"`
+	// Attempt is the attempt at the Job whose logs the line belongs to.
+	Attempt int `json:"attempt,omitempty"`
+	// Container is the name of the container whose logs the line belongs to.
+	Container string `json:"container"`
+	// LogEntry is the matching line.
+	LogEntry LogEntry `json:"logEntry"`
+	// Before enumerates lines of logs written by the same container immediately
+	// before the matching line.
+	Before []LogEntry `json:"before,omitempty"`
+	// After enumerates lines of logs written by the same container immediately
+	// after the matching line.
+	After []LogEntry `json:"after,omitempty"`
+}
+
+// LogSearchMatchList is an ordered list of LogSearchMatches.
+type LogSearchMatchList struct {
+	// ListMeta contains list metadata.
+	meta.ListMeta `json:"metadata"`
+	// Items is a slice of LogSearchMatches.
+	Items []LogSearchMatch `json:"items,omitempty"`
+}
+
+// MarshalJSON amends LogSearchMatchList instances with type metadata so that
+// clients do not need to be concerned with the tedium of doing so.
+func (l LogSearchMatchList) MarshalJSON() ([]byte, error) {
+	type Alias LogSearchMatchList
+	return json.Marshal(
+		struct {
+			meta.TypeMeta `json:",inline"`
+			Alias         `json:",inline"`
+		}{
+			TypeMeta: meta.TypeMeta{
+				APIVersion: meta.APIVersion,
+				Kind:       "LogSearchMatchList",
+			},
+			Alias: (Alias)(l),
+		},
+	)
+}
+
+// LogArchiveDownloadOptions represents useful, optional settings for
+// downloading an archive of an Event's logs. It currently has no fields, but
+// exists to preserve the possibility of future expansion without having to
+// change client function signatures.
+type LogArchiveDownloadOptions struct{}
+
 // LogsClient is the specialized client for managing Logs with the Brigade API.
 type LogsClient interface {
 	// Stream returns a channel over which logs for an Event's Worker, or using
@@ -55,6 +166,33 @@ type LogsClient interface {
 		selector *LogsSelector,
 		opts *LogStreamOptions,
 	) (<-chan LogEntry, <-chan error, error)
+	// DownloadArchive returns an io.ReadCloser from which a gzipped tarball can
+	// be read. The tarball contains one file of logs for every container of an
+	// Event's Worker and of every attempt at every Job spawned by that Worker,
+	// along with a manifest.json file describing them. Callers are responsible
+	// for closing the io.ReadCloser.
+	DownloadArchive(
+		ctx context.Context,
+		eventID string,
+		opts *LogArchiveDownloadOptions,
+	) (io.ReadCloser, error)
+	// Search returns a list of lines of logs, belonging to an Event's Worker or
+	// any Job spawned by that Worker, that match the provided
+	// LogSearchCriteria.
+	Search(
+		ctx context.Context,
+		eventID string,
+		criteria LogSearchCriteria,
+		opts *LogSearchOptions,
+	) (LogSearchMatchList, error)
+	// SearchProject returns a list of lines of logs, belonging to any of a
+	// Project's most recent Events, that match the provided LogSearchCriteria.
+	SearchProject(
+		ctx context.Context,
+		projectID string,
+		criteria LogSearchCriteria,
+		opts *LogSearchOptions,
+	) (LogSearchMatchList, error)
 }
 
 type logsClient struct {
@@ -86,9 +224,26 @@ func (l *logsClient) Stream(
 		if selector.Container != "" {
 			queryParams["container"] = selector.Container
 		}
+		if selector.Attempt > 0 {
+			queryParams["attempt"] = strconv.Itoa(selector.Attempt)
+		}
+		if selector.All {
+			queryParams["all"] = trueStr
+		}
 	}
-	if opts != nil && opts.Follow {
-		queryParams["follow"] = trueStr
+	if opts != nil {
+		if opts.Follow {
+			queryParams["follow"] = trueStr
+		}
+		if opts.TailLines > 0 {
+			queryParams["tailLines"] = strconv.FormatInt(opts.TailLines, 10)
+		}
+		if opts.Since != nil {
+			queryParams["since"] = opts.Since.UTC().Format(time.RFC3339Nano)
+		}
+		if opts.Until != nil {
+			queryParams["until"] = opts.Until.UTC().Format(time.RFC3339Nano)
+		}
 	}
 
 	resp, err := l.SubmitRequest( // nolint: bodyclose
@@ -113,6 +268,93 @@ func (l *logsClient) Stream(
 	return logCh, errCh, nil
 }
 
+func (l *logsClient) DownloadArchive(
+	ctx context.Context,
+	eventID string,
+	_ *LogArchiveDownloadOptions,
+) (io.ReadCloser, error) {
+	resp, err := l.SubmitRequest( // nolint: bodyclose
+		ctx,
+		rm.OutboundRequest{
+			Method:      http.MethodGet,
+			Path:        fmt.Sprintf("v2/events/%s/logs/archive", eventID),
+			SuccessCode: http.StatusOK,
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	// The caller is responsible for closing the response body
+	return resp.Body, nil
+}
+
+func (l *logsClient) Search(
+	ctx context.Context,
+	eventID string,
+	criteria LogSearchCriteria,
+	opts *LogSearchOptions,
+) (LogSearchMatchList, error) {
+	matches := LogSearchMatchList{}
+	return matches, l.ExecuteRequest(
+		ctx,
+		rm.OutboundRequest{
+			Method:      http.MethodGet,
+			Path:        fmt.Sprintf("v2/events/%s/logs/search", eventID),
+			QueryParams: logSearchQueryParams(criteria, opts),
+			SuccessCode: http.StatusOK,
+			RespObj:     &matches,
+		},
+	)
+}
+
+func (l *logsClient) SearchProject(
+	ctx context.Context,
+	projectID string,
+	criteria LogSearchCriteria,
+	opts *LogSearchOptions,
+) (LogSearchMatchList, error) {
+	matches := LogSearchMatchList{}
+	return matches, l.ExecuteRequest(
+		ctx,
+		rm.OutboundRequest{
+			Method:      http.MethodGet,
+			Path:        fmt.Sprintf("v2/projects/%s/logs/search", projectID),
+			QueryParams: logSearchQueryParams(criteria, opts),
+			SuccessCode: http.StatusOK,
+			RespObj:     &matches,
+		},
+	)
+}
+
+// logSearchQueryParams returns query parameters representing the provided
+// LogSearchCriteria and LogSearchOptions.
+func logSearchQueryParams(
+	criteria LogSearchCriteria,
+	opts *LogSearchOptions,
+) map[string]string {
+	queryParams := map[string]string{
+		"pattern": criteria.Pattern,
+	}
+	if criteria.Regex {
+		queryParams["regex"] = trueStr
+	}
+	if criteria.IgnoreCase {
+		queryParams["ignoreCase"] = trueStr
+	}
+	if opts != nil {
+		if opts.ContextLines > 0 {
+			queryParams["context"] = strconv.Itoa(opts.ContextLines)
+		}
+		if opts.Limit > 0 {
+			queryParams["limit"] = strconv.Itoa(opts.Limit)
+		}
+		if opts.Events > 0 {
+			queryParams["events"] = strconv.Itoa(opts.Events)
+		}
+	}
+	return queryParams
+}
+
 // receiveStream is used to receive log messages as SSEs (server sent events),
 // decode those, and publish them to a channel.
 func (l *logsClient) receiveStream(
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"strconv"
@@ -29,6 +30,7 @@ func TestLogsClientStream(t *testing.T) {
 	testSelector := LogsSelector{
 		Job:       "farpoint",
 		Container: "enterprise",
+		Attempt:   2,
 	}
 	testOpts := LogStreamOptions{
 		Follow: true,
@@ -86,6 +88,96 @@ func TestLogsClientStream(t *testing.T) {
 		}
 	})
 
+	t.Run("time range and tail lines", func(t *testing.T) {
+		since := time.Date(2021, time.December, 1, 12, 0, 0, 0, time.UTC)
+		// Sub-second precision should be preserved
+		until := since.Add(time.Hour + 250*time.Millisecond)
+		server := httptest.NewServer(
+			http.HandlerFunc(
+				func(w http.ResponseWriter, r *http.Request) {
+					require.Equal(t, http.MethodGet, r.Method)
+					require.Equal(t, "100", r.URL.Query().Get("tailLines"))
+					require.Equal(
+						t,
+						"2021-12-01T12:00:00Z",
+						r.URL.Query().Get("since"),
+					)
+					require.Equal(
+						t,
+						"2021-12-01T13:00:00.25Z",
+						r.URL.Query().Get("until"),
+					)
+					bodyBytes, err := json.Marshal(testLogEntry)
+					require.NoError(t, err)
+					w.Header().Set("Content-Type", "text/event-stream")
+					flusher, ok := w.(http.Flusher)
+					require.True(t, ok)
+					flusher.Flush()
+					fmt.Fprintln(w, string(bodyBytes))
+					flusher.Flush()
+				},
+			),
+		)
+		defer server.Close()
+		client := NewLogsClient(server.URL, rmTesting.TestAPIToken, nil)
+		logsCh, _, err := client.Stream(
+			context.Background(),
+			testEventID,
+			nil,
+			&LogStreamOptions{
+				TailLines: 100,
+				Since:     &since,
+				Until:     &until,
+			},
+		)
+		require.NoError(t, err)
+		select {
+		case logEntry := <-logsCh:
+			require.Equal(t, testLogEntry, logEntry)
+		case <-time.After(3 * time.Second):
+			require.Fail(t, "timed out waiting for logs")
+		}
+	})
+
+	t.Run("all containers", func(t *testing.T) {
+		taggedLogEntry := testLogEntry
+		taggedLogEntry.Job = testSelector.Job
+		taggedLogEntry.Container = testSelector.Container
+		server := httptest.NewServer(
+			http.HandlerFunc(
+				func(w http.ResponseWriter, r *http.Request) {
+					require.Equal(t, http.MethodGet, r.Method)
+					require.Equal(t, "true", r.URL.Query().Get("all"))
+					require.Empty(t, r.URL.Query().Get("job"))
+					require.Empty(t, r.URL.Query().Get("container"))
+					bodyBytes, err := json.Marshal(taggedLogEntry)
+					require.NoError(t, err)
+					w.Header().Set("Content-Type", "text/event-stream")
+					flusher, ok := w.(http.Flusher)
+					require.True(t, ok)
+					flusher.Flush()
+					fmt.Fprintln(w, string(bodyBytes))
+					flusher.Flush()
+				},
+			),
+		)
+		defer server.Close()
+		client := NewLogsClient(server.URL, rmTesting.TestAPIToken, nil)
+		logsCh, _, err := client.Stream(
+			context.Background(),
+			testEventID,
+			&LogsSelector{All: true},
+			&testOpts,
+		)
+		require.NoError(t, err)
+		select {
+		case logEntry := <-logsCh:
+			require.Equal(t, taggedLogEntry, logEntry)
+		case <-time.After(3 * time.Second):
+			require.Fail(t, "timed out waiting for logs")
+		}
+	})
+
 	t.Run("non-nil logs selector", func(t *testing.T) {
 		server := httptest.NewServer(
 			http.HandlerFunc(
@@ -106,6 +198,11 @@ func TestLogsClientStream(t *testing.T) {
 						testSelector.Container,
 						r.URL.Query().Get("container"),
 					)
+					require.Equal(
+						t,
+						strconv.Itoa(testSelector.Attempt),
+						r.URL.Query().Get("attempt"),
+					)
 					require.Equal(
 						t,
 						strconv.FormatBool(testOpts.Follow),
@@ -139,3 +236,134 @@ func TestLogsClientStream(t *testing.T) {
 		}
 	})
 }
+
+func TestLogsClientDownloadArchive(t *testing.T) {
+	const testEventID = "12345"
+	const testContent = "not really a tarball"
+	server := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				require.Equal(t, http.MethodGet, r.Method)
+				require.Equal(
+					t,
+					fmt.Sprintf("/v2/events/%s/logs/archive", testEventID),
+					r.URL.Path,
+				)
+				w.Header().Set("Content-Type", "application/gzip")
+				w.WriteHeader(http.StatusOK)
+				fmt.Fprint(w, testContent)
+			},
+		),
+	)
+	defer server.Close()
+	client := NewLogsClient(server.URL, rmTesting.TestAPIToken, nil)
+	content, err := client.DownloadArchive(context.Background(), testEventID, nil)
+	require.NoError(t, err)
+	defer content.Close()
+	contentBytes, err := ioutil.ReadAll(content)
+	require.NoError(t, err)
+	require.Equal(t, testContent, string(contentBytes))
+}
+
+func TestLogsClientSearch(t *testing.T) {
+	const testEventID = "12345"
+	testCriteria := LogSearchCriteria{
+		Pattern:    "fail",
+		IgnoreCase: true,
+	}
+	testMatches := LogSearchMatchList{
+		Items: []LogSearchMatch{
+			{
+				EventID:   testEventID,
+				Job:       "farpoint",
+				Attempt:   1,
+				Container: "farpoint",
+				LogEntry: LogEntry{
+					Message: "FAIL",
+				},
+			},
+		},
+	}
+	server := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				require.Equal(t, http.MethodGet, r.Method)
+				require.Equal(
+					t,
+					fmt.Sprintf("/v2/events/%s/logs/search", testEventID),
+					r.URL.Path,
+				)
+				require.Equal(t, testCriteria.Pattern, r.URL.Query().Get("pattern"))
+				require.Equal(t, "true", r.URL.Query().Get("ignoreCase"))
+				require.Empty(t, r.URL.Query().Get("regex"))
+				require.Equal(t, "2", r.URL.Query().Get("context"))
+				bodyBytes, err := json.Marshal(testMatches)
+				require.NoError(t, err)
+				w.WriteHeader(http.StatusOK)
+				fmt.Fprintln(w, string(bodyBytes))
+			},
+		),
+	)
+	defer server.Close()
+	client := NewLogsClient(server.URL, rmTesting.TestAPIToken, nil)
+	matches, err := client.Search(
+		context.Background(),
+		testEventID,
+		testCriteria,
+		&LogSearchOptions{
+			ContextLines: 2,
+		},
+	)
+	require.NoError(t, err)
+	require.Equal(t, testMatches, matches)
+}
+
+func TestLogsClientSearchProject(t *testing.T) {
+	const testProjectID = "bluebook"
+	testCriteria := LogSearchCriteria{
+		Pattern: "^FAIL",
+		Regex:   true,
+	}
+	testMatches := LogSearchMatchList{
+		Items: []LogSearchMatch{
+			{
+				EventID:   "12345",
+				Container: "worker",
+				LogEntry: LogEntry{
+					Message: "FAIL",
+				},
+			},
+		},
+	}
+	server := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				require.Equal(t, http.MethodGet, r.Method)
+				require.Equal(
+					t,
+					fmt.Sprintf("/v2/projects/%s/logs/search", testProjectID),
+					r.URL.Path,
+				)
+				require.Equal(t, testCriteria.Pattern, r.URL.Query().Get("pattern"))
+				require.Equal(t, "true", r.URL.Query().Get("regex"))
+				require.Equal(t, "5", r.URL.Query().Get("events"))
+				bodyBytes, err := json.Marshal(testMatches)
+				require.NoError(t, err)
+				w.WriteHeader(http.StatusOK)
+				fmt.Fprintln(w, string(bodyBytes))
+			},
+		),
+	)
+	defer server.Close()
+	client := NewLogsClient(server.URL, rmTesting.TestAPIToken, nil)
+	matches, err := client.SearchProject(
+		context.Background(),
+		testProjectID,
+		testCriteria,
+		&LogSearchOptions{
+			Events: 5,
+		},
+	)
+	require.NoError(t, err)
+	require.Equal(t, testMatches, matches)
+}
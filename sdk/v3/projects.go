@@ -100,6 +100,90 @@ type ProjectSpec struct {
 	EventSubscriptions []EventSubscription `json:"eventSubscriptions,omitempty"`
 	// WorkerTemplate is a prototypical WorkerSpec.
 	WorkerTemplate WorkerSpec `json:"workerTemplate"`
+	// Schedules defines Events that should be emitted on behalf of the Project
+	// on a recurring basis. Note that, like any other Event, scheduled Events
+	// only trigger the execution of a new Worker if the Project also subscribes
+	// to them.
+	Schedules []EventSchedule `json:"schedules,omitempty"`
+	// MaxConcurrentWorkers optionally limits how many of the Project's Workers
+	// may execute concurrently. A value of zero indicates no Project-level limit.
+	// Regardless of this value, the system-wide limit always applies.
+	MaxConcurrentWorkers int `json:"maxConcurrentWorkers,omitempty"`
+	// MaxConcurrentJobs optionally limits how many of the Project's Jobs may
+	// execute concurrently. A value of zero indicates no Project-level limit.
+	// Regardless of this value, the system-wide limit always applies.
+	MaxConcurrentJobs int `json:"maxConcurrentJobs,omitempty"`
+	// SchedulingWeight optionally specifies the Project's share of the system's
+	// Worker and Job capacity, relative to other Projects, when Projects are
+	// competing for that capacity. A Project with a weight of two, for instance,
+	// is granted capacity twice as often as a Project with a weight of one. A
+	// value of zero is treated as a weight of one.
+	SchedulingWeight int `json:"schedulingWeight,omitempty"`
+	// MaxEventPriority optionally specifies the highest Event priority the
+	// Project honors. The priority of any Event the Project receives is lowered
+	// to this value if it exceeds it. The default of zero means Events cannot
+	// jump the queue.
+	MaxEventPriority int `json:"maxEventPriority,omitempty"`
+	// Retention optionally specifies which of the Project's Events should be
+	// automatically deleted. Any setting specified here takes precedence over
+	// the corresponding system-level setting.
+	Retention *RetentionPolicy `json:"retention,omitempty"`
+	// Webhooks optionally specifies HTTP endpoints that should be notified
+	// whenever one of the Project's Workers or Jobs enters a phase of interest.
+	Webhooks []Webhook `json:"webhooks,omitempty"`
+	// Resources optionally specifies defaults and maximums for the compute
+	// resources requested by and available to the Project's Workers and Jobs.
+	Resources *ResourcePolicy `json:"resources,omitempty"`
+}
+
+// ResourcePolicy specifies Project-level defaults and maximums for the compute
+// resources requested by and available to the Project's Workers and Jobs.
+type ResourcePolicy struct {
+	// Defaults specifies requests and limits applied to any Worker or Job
+	// container that does not specify its own.
+	Defaults ContainerResources `json:"defaults,omitempty"`
+	// Max specifies the most compute resources any single Worker or Job
+	// container may request or be limited to. Containers that specify no limit
+	// (and for which there is no default limit) are limited to this amount. Jobs
+	// exceeding these maximums are rejected.
+	Max ResourceQuantities `json:"max,omitempty"`
+}
+
+// RetentionPolicy describes which Events whose Workers have reached a terminal
+// phase should be automatically deleted. Events whose Workers have not reached
+// a terminal phase and pinned Events are never deleted.
+type RetentionPolicy struct {
+	// MaxAge optionally specifies how long after their creation Events are
+	// retained. This duration string is a sequence of decimal numbers, each with
+	// optional fraction and a unit suffix, such as "720h". Valid time units are
+	// "ns", "us" (or "µs"), "ms", "s", "m", "h".
+	MaxAge string `json:"maxAge,omitempty"`
+	// PhaseMaxAges optionally overrides MaxAge for Events whose Workers are in
+	// specific terminal phases. This permits, for instance, FAILED Events to be
+	// retained longer than SUCCEEDED ones.
+	PhaseMaxAges map[WorkerPhase]string `json:"phaseMaxAges,omitempty"`
+	// MaxCount optionally specifies the maximum number of Events retained per
+	// Project. When this is exceeded, the oldest Events are deleted first.
+	MaxCount int `json:"maxCount,omitempty"`
+}
+
+// EventSchedule describes an Event that should be emitted into Brigade's event
+// bus, on behalf of a Project, on a recurring basis. All such Events have the
+// source "brigade.sh/cron" and are labeled with "brigade.sh/schedule=<name>".
+type EventSchedule struct {
+	// Name is a name for the EventSchedule that is unique among all of a
+	// Project's EventSchedules.
+	Name string `json:"name"`
+	// Cron is a standard, five field cron expression (or a descriptor such as
+	// "@hourly") that specifies when an Event should be emitted. Unless the
+	// expression is prefixed with "CRON_TZ=<zone>", it is evaluated in UTC.
+	Cron string `json:"cron"`
+	// Type specifies the type of the Events emitted.
+	Type string `json:"type"`
+	// Payload optionally specifies a payload for the Events emitted.
+	Payload string `json:"payload,omitempty"`
+	// Labels optionally specifies additional labels for the Events emitted.
+	Labels map[string]string `json:"labels,omitempty"`
 }
 
 // EventSubscription defines a set of Events of interest. ProjectSpecs utilize
@@ -143,8 +227,37 @@ type EventSubscription struct {
 	// match" subscription semantics differ from the Qualifiers field's "MUST
 	// match" subscription semantics.
 	Labels map[string]string `json:"labels,omitempty"`
+	// MatchStrategy optionally specifies how the values of the Qualifiers and
+	// Labels fields are matched against the values of an Event's Qualifiers and
+	// Labels. If unspecified, values must match exactly. Note that, regardless
+	// of strategy, Qualifier KEYS must always match exactly.
+	MatchStrategy MatchStrategy `json:"matchStrategy,omitempty"`
+	// Expression optionally specifies a boolean filter expression that an Event
+	// MUST also satisfy for a Project to be considered subscribed. Expressions
+	// may reference the Event's source, type, labels.<key>, qualifiers.<key>,
+	// and payload.<path> (where the payload is treated as JSON). e.g.
+	// labels.env != "prod" && payload.ref =~ "^refs/heads/release/"
+	Expression string `json:"expression,omitempty"`
 }
 
+// MatchStrategy represents a strategy for matching the values of an
+// EventSubscription's Qualifiers and Labels against the values of an Event's
+// Qualifiers and Labels.
+type MatchStrategy string
+
+const (
+	// MatchStrategyExact represents a strategy wherein values must match
+	// exactly. This is the default.
+	MatchStrategyExact MatchStrategy = "Exact"
+	// MatchStrategyGlob represents a strategy wherein values are treated as
+	// shell-style glob patterns. e.g. "release/*"
+	MatchStrategyGlob MatchStrategy = "Glob"
+	// MatchStrategyRegex represents a strategy wherein values are treated as
+	// regular expressions. Expressions are implicitly anchored; i.e. they must
+	// match an ENTIRE value.
+	MatchStrategyRegex MatchStrategy = "Regex"
+)
+
 // KubernetesDetails represents Kubernetes-specific configuration.
 type KubernetesDetails struct {
 	// Namespace is the dedicated Kubernetes namespace for the Project. This is
@@ -177,6 +290,25 @@ type ProjectUpdateOptions struct {
 // of future expansion without having to change client function signatures.
 type ProjectDeleteOptions struct{}
 
+// ProjectQueueDepth represents the number of a Project's Workers and Jobs that
+// are waiting to be scheduled.
+type ProjectQueueDepth struct {
+	// ProjectID is the identifier of the Project.
+	ProjectID string `json:"projectID"`
+	// PendingWorkers is the number of the Project's Workers that are waiting to
+	// be scheduled.
+	PendingWorkers int64 `json:"pendingWorkers"`
+	// PendingJobs is the number of the Project's Jobs that are waiting to be
+	// scheduled.
+	PendingJobs int64 `json:"pendingJobs"`
+}
+
+// ProjectQueueDepthGetOptions represents useful, optional criteria for
+// retrieving a Project's queue depth. It currently has no fields, but exists to
+// preserve the possibility of future expansion without having to change client
+// function signatures.
+type ProjectQueueDepthGetOptions struct{}
+
 // ProjectsClient is the specialized client for managing Projects with the
 // Brigade API.
 type ProjectsClient interface {
@@ -220,13 +352,28 @@ type ProjectsClient interface {
 	) (Project, error)
 	// Delete deletes a single Project specified by its identifier.
 	Delete(context.Context, string, *ProjectDeleteOptions) error
+	// GetQueueDepth returns the number of the specified Project's Workers and
+	// Jobs that are waiting to be scheduled.
+	GetQueueDepth(
+		context.Context,
+		string,
+		*ProjectQueueDepthGetOptions,
+	) (ProjectQueueDepth, error)
 
 	// Authz returns a specialized client for managing project-level authorization
 	// concerns.
 	Authz() ProjectAuthzClient
 
+	// JobCache returns a specialized client for managing the Project's cached
+	// Job results.
+	JobCache() JobCacheClient
+
 	// Secrets returns a specialized client for Secret management.
 	Secrets() SecretsClient
+
+	// Webhooks returns a specialized client for inspecting the deliveries of
+	// Notifications to the Project's Webhooks.
+	Webhooks() WebhooksClient
 }
 
 type projectsClient struct {
@@ -234,8 +381,13 @@ type projectsClient struct {
 	// authzClient is a specialized client for managing project-level
 	// authorization concerns.
 	authzClient ProjectAuthzClient
+	// jobCacheClient is a specialized client for managing cached Job results.
+	jobCacheClient JobCacheClient
 	// secretsClient is a specialized client for Secret management.
 	secretsClient SecretsClient
+	// webhooksClient is a specialized client for inspecting the deliveries of
+	// Notifications to Webhooks.
+	webhooksClient WebhooksClient
 }
 
 // NewProjectsClient returns a specialized client for managing Projects.
@@ -245,9 +397,11 @@ func NewProjectsClient(
 	opts *restmachinery.APIClientOptions,
 ) ProjectsClient {
 	return &projectsClient{
-		BaseClient:    rm.NewBaseClient(apiAddress, apiToken, opts),
-		authzClient:   NewProjectAuthzClient(apiAddress, apiToken, opts),
-		secretsClient: NewSecretsClient(apiAddress, apiToken, opts),
+		BaseClient:     rm.NewBaseClient(apiAddress, apiToken, opts),
+		authzClient:    NewProjectAuthzClient(apiAddress, apiToken, opts),
+		jobCacheClient: NewJobCacheClient(apiAddress, apiToken, opts),
+		secretsClient:  NewSecretsClient(apiAddress, apiToken, opts),
+		webhooksClient: NewWebhooksClient(apiAddress, apiToken, opts),
 	}
 }
 
@@ -384,10 +538,35 @@ func (p *projectsClient) Delete(
 	)
 }
 
+func (p *projectsClient) GetQueueDepth(
+	ctx context.Context,
+	id string,
+	_ *ProjectQueueDepthGetOptions,
+) (ProjectQueueDepth, error) {
+	depth := ProjectQueueDepth{}
+	return depth, p.ExecuteRequest(
+		ctx,
+		rm.OutboundRequest{
+			Method:      http.MethodGet,
+			Path:        fmt.Sprintf("v2/projects/%s/queue-depth", id),
+			SuccessCode: http.StatusOK,
+			RespObj:     &depth,
+		},
+	)
+}
+
 func (p *projectsClient) Authz() ProjectAuthzClient {
 	return p.authzClient
 }
 
+func (p *projectsClient) JobCache() JobCacheClient {
+	return p.jobCacheClient
+}
+
 func (p *projectsClient) Secrets() SecretsClient {
 	return p.secretsClient
 }
+
+func (p *projectsClient) Webhooks() WebhooksClient {
+	return p.webhooksClient
+}
@@ -34,8 +34,12 @@ func TestNewProjectsClient(t *testing.T) {
 	rmTesting.RequireBaseClient(t, client.BaseClient)
 	require.NotNil(t, client.authzClient)
 	require.Equal(t, client.authzClient, client.Authz())
+	require.NotNil(t, client.jobCacheClient)
+	require.Equal(t, client.jobCacheClient, client.JobCache())
 	require.NotNil(t, client.secretsClient)
 	require.Equal(t, client.secretsClient, client.Secrets())
+	require.NotNil(t, client.webhooksClient)
+	require.Equal(t, client.webhooksClient, client.Webhooks())
 }
 
 func TestProjectsClientCreate(t *testing.T) {
@@ -262,3 +266,36 @@ func TestProjectsClientDelete(t *testing.T) {
 	err := client.Delete(context.Background(), testProjectID, nil)
 	require.NoError(t, err)
 }
+
+func TestProjectsClientGetQueueDepth(t *testing.T) {
+	testDepth := ProjectQueueDepth{
+		ProjectID:      "bluebook",
+		PendingWorkers: 2,
+		PendingJobs:    5,
+	}
+	server := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				require.Equal(t, http.MethodGet, r.Method)
+				require.Equal(
+					t,
+					fmt.Sprintf("/v2/projects/%s/queue-depth", testDepth.ProjectID),
+					r.URL.Path,
+				)
+				bodyBytes, err := json.Marshal(testDepth)
+				require.NoError(t, err)
+				w.WriteHeader(http.StatusOK)
+				fmt.Fprintln(w, string(bodyBytes))
+			},
+		),
+	)
+	defer server.Close()
+	client := NewProjectsClient(server.URL, rmTesting.TestAPIToken, nil)
+	depth, err := client.GetQueueDepth(
+		context.Background(),
+		testDepth.ProjectID,
+		nil,
+	)
+	require.NoError(t, err)
+	require.Equal(t, testDepth, depth)
+}
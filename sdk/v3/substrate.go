@@ -23,16 +23,22 @@ type SubstrateJobCount struct {
 }
 
 // RunningWorkerCountOptions represents useful, optional criteria for the
-// retrieval of a count of running Workers. It currently has no fields, but
-// exists to preserve the possibility of future expansion without having to
-// change client function signatures.
-type RunningWorkerCountOptions struct{}
+// retrieval of a count of running Workers.
+type RunningWorkerCountOptions struct {
+	// ProjectID specifies that only Workers belonging to the indicated Project
+	// should be counted. If left blank, Workers belonging to all Projects are
+	// counted.
+	ProjectID string
+}
 
 // RunningJobCountOptions represents useful, optional criteria for the retrieval
-// of a count of running Jobs. It currently has no fields, but exists to
-// preserve the possibility of future expansion without having to change client
-// function signatures.
-type RunningJobCountOptions struct{}
+// of a count of running Jobs.
+type RunningJobCountOptions struct {
+	// ProjectID specifies that only Jobs belonging to the indicated Project
+	// should be counted. If left blank, Jobs belonging to all Projects are
+	// counted.
+	ProjectID string
+}
 
 // SubstrateClient is the specialized client for monitoring the substrate.
 type SubstrateClient interface {
@@ -67,14 +73,19 @@ func NewSubstrateClient(
 
 func (s *substrateClient) CountRunningWorkers(
 	ctx context.Context,
-	_ *RunningWorkerCountOptions,
+	opts *RunningWorkerCountOptions,
 ) (SubstrateWorkerCount, error) {
+	queryParams := map[string]string{}
+	if opts != nil && opts.ProjectID != "" {
+		queryParams["projectID"] = opts.ProjectID
+	}
 	count := SubstrateWorkerCount{}
 	return count, s.ExecuteRequest(
 		ctx,
 		rm.OutboundRequest{
 			Method:      http.MethodGet,
 			Path:        "v2/substrate/running-workers",
+			QueryParams: queryParams,
 			SuccessCode: http.StatusOK,
 			RespObj:     &count,
 		},
@@ -83,14 +94,19 @@ func (s *substrateClient) CountRunningWorkers(
 
 func (s *substrateClient) CountRunningJobs(
 	ctx context.Context,
-	_ *RunningJobCountOptions,
+	opts *RunningJobCountOptions,
 ) (SubstrateJobCount, error) {
+	queryParams := map[string]string{}
+	if opts != nil && opts.ProjectID != "" {
+		queryParams["projectID"] = opts.ProjectID
+	}
 	count := SubstrateJobCount{}
 	return count, s.ExecuteRequest(
 		ctx,
 		rm.OutboundRequest{
 			Method:      http.MethodGet,
 			Path:        "v2/substrate/running-jobs",
+			QueryParams: queryParams,
 			SuccessCode: http.StatusOK,
 			RespObj:     &count,
 		},
@@ -23,6 +23,7 @@ func TestNewSubstrateClient(t *testing.T) {
 }
 
 func TestSubstrateClientCountRunningWorkers(t *testing.T) {
+	const testProjectID = "blue-book"
 	testCount := SubstrateWorkerCount{
 		Count: 5,
 	}
@@ -31,6 +32,7 @@ func TestSubstrateClientCountRunningWorkers(t *testing.T) {
 			func(w http.ResponseWriter, r *http.Request) {
 				require.Equal(t, http.MethodGet, r.Method)
 				require.Equal(t, "/v2/substrate/running-workers", r.URL.Path)
+				require.Equal(t, testProjectID, r.URL.Query().Get("projectID"))
 				bodyBytes, err := json.Marshal(testCount)
 				require.NoError(t, err)
 				w.WriteHeader(http.StatusOK)
@@ -40,12 +42,18 @@ func TestSubstrateClientCountRunningWorkers(t *testing.T) {
 	)
 	defer server.Close()
 	client := NewSubstrateClient(server.URL, rmTesting.TestAPIToken, nil)
-	count, err := client.CountRunningWorkers(context.Background(), nil)
+	count, err := client.CountRunningWorkers(
+		context.Background(),
+		&RunningWorkerCountOptions{
+			ProjectID: testProjectID,
+		},
+	)
 	require.NoError(t, err)
 	require.Equal(t, testCount, count)
 }
 
 func TestSubstrateClientCountRunningJobs(t *testing.T) {
+	const testProjectID = "blue-book"
 	testCount := SubstrateJobCount{
 		Count: 5,
 	}
@@ -54,6 +62,7 @@ func TestSubstrateClientCountRunningJobs(t *testing.T) {
 			func(w http.ResponseWriter, r *http.Request) {
 				require.Equal(t, http.MethodGet, r.Method)
 				require.Equal(t, "/v2/substrate/running-jobs", r.URL.Path)
+				require.Equal(t, testProjectID, r.URL.Query().Get("projectID"))
 				bodyBytes, err := json.Marshal(testCount)
 				require.NoError(t, err)
 				w.WriteHeader(http.StatusOK)
@@ -63,7 +72,12 @@ func TestSubstrateClientCountRunningJobs(t *testing.T) {
 	)
 	defer server.Close()
 	client := NewSubstrateClient(server.URL, rmTesting.TestAPIToken, nil)
-	count, err := client.CountRunningJobs(context.Background(), nil)
+	count, err := client.CountRunningJobs(
+		context.Background(),
+		&RunningJobCountOptions{
+			ProjectID: testProjectID,
+		},
+	)
 	require.NoError(t, err)
 	require.Equal(t, testCount, count)
 }
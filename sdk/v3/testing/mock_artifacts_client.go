@@ -0,0 +1,62 @@
+package testing
+
+import (
+	"context"
+	"io"
+
+	"github.com/brigadecore/brigade/sdk/v3"
+)
+
+type MockArtifactsClient struct {
+	UploadFn func(
+		ctx context.Context,
+		eventID string,
+		jobName string,
+		artifactName string,
+		content io.Reader,
+		opts *sdk.ArtifactUploadOptions,
+	) (sdk.Artifact, error)
+	ListFn func(
+		ctx context.Context,
+		eventID string,
+		jobName string,
+		opts *sdk.ArtifactListOptions,
+	) (sdk.ArtifactList, error)
+	DownloadFn func(
+		ctx context.Context,
+		eventID string,
+		jobName string,
+		artifactName string,
+		opts *sdk.ArtifactDownloadOptions,
+	) (io.ReadCloser, error)
+}
+
+func (m *MockArtifactsClient) Upload(
+	ctx context.Context,
+	eventID string,
+	jobName string,
+	artifactName string,
+	content io.Reader,
+	opts *sdk.ArtifactUploadOptions,
+) (sdk.Artifact, error) {
+	return m.UploadFn(ctx, eventID, jobName, artifactName, content, opts)
+}
+
+func (m *MockArtifactsClient) List(
+	ctx context.Context,
+	eventID string,
+	jobName string,
+	opts *sdk.ArtifactListOptions,
+) (sdk.ArtifactList, error) {
+	return m.ListFn(ctx, eventID, jobName, opts)
+}
+
+func (m *MockArtifactsClient) Download(
+	ctx context.Context,
+	eventID string,
+	jobName string,
+	artifactName string,
+	opts *sdk.ArtifactDownloadOptions,
+) (io.ReadCloser, error) {
+	return m.DownloadFn(ctx, eventID, jobName, artifactName, opts)
+}
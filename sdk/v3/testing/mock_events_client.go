@@ -52,11 +52,18 @@ type MockEventsClient struct {
 		sdk.EventsSelector,
 		*sdk.EventDeleteManyOptions,
 	) (sdk.DeleteManyEventsResult, error)
+	PinFn   func(context.Context, string, *sdk.EventPinOptions) error
+	UnpinFn func(context.Context, string, *sdk.EventUnpinOptions) error
 	RetryFn func(
 		context.Context,
 		string,
 		*sdk.EventRetryOptions,
 	) (sdk.Event, error)
+	MatchFn func(
+		context.Context,
+		sdk.Event,
+		*sdk.EventMatchOptions,
+	) (sdk.EventMatchResult, error)
 	WorkersClient sdk.WorkersClient
 	LogsClient    sdk.LogsClient
 }
@@ -119,6 +126,22 @@ func (m *MockEventsClient) Cancel(
 	return m.CancelFn(ctx, id, opts)
 }
 
+func (m *MockEventsClient) Pin(
+	ctx context.Context,
+	id string,
+	opts *sdk.EventPinOptions,
+) error {
+	return m.PinFn(ctx, id, opts)
+}
+
+func (m *MockEventsClient) Unpin(
+	ctx context.Context,
+	id string,
+	opts *sdk.EventUnpinOptions,
+) error {
+	return m.UnpinFn(ctx, id, opts)
+}
+
 func (m *MockEventsClient) CancelMany(
 	ctx context.Context,
 	selector sdk.EventsSelector,
@@ -151,6 +174,14 @@ func (m *MockEventsClient) Retry(
 	return m.RetryFn(ctx, id, opts)
 }
 
+func (m *MockEventsClient) Match(
+	ctx context.Context,
+	event sdk.Event,
+	opts *sdk.EventMatchOptions,
+) (sdk.EventMatchResult, error) {
+	return m.MatchFn(ctx, event, opts)
+}
+
 func (m *MockEventsClient) Workers() sdk.WorkersClient {
 	return m.WorkersClient
 }
@@ -0,0 +1,23 @@
+package testing
+
+import (
+	"context"
+
+	"github.com/brigadecore/brigade/sdk/v3"
+)
+
+type MockJobCacheClient struct {
+	PurgeFn func(
+		ctx context.Context,
+		projectID string,
+		opts *sdk.JobCachePurgeOptions,
+	) error
+}
+
+func (m *MockJobCacheClient) Purge(
+	ctx context.Context,
+	projectID string,
+	opts *sdk.JobCachePurgeOptions,
+) error {
+	return m.PurgeFn(ctx, projectID, opts)
+}
@@ -38,6 +38,13 @@ type MockJobsClient struct {
 		status sdk.JobStatus,
 		opts *sdk.JobStatusUpdateOptions,
 	) error
+	UpdateOutputsFn func(
+		ctx context.Context,
+		eventID string,
+		jobName string,
+		outputs sdk.JobOutputs,
+		opts *sdk.JobOutputsUpdateOptions,
+	) error
 	CleanupFn func(
 		ctx context.Context,
 		eventID string,
@@ -50,6 +57,13 @@ type MockJobsClient struct {
 		jobName string,
 		opts *sdk.JobTimeoutOptions,
 	) error
+	AbortFn func(
+		ctx context.Context,
+		eventID string,
+		jobName string,
+		opts *sdk.JobAbortOptions,
+	) error
+	ArtifactsClient sdk.ArtifactsClient
 }
 
 func (m *MockJobsClient) Create(
@@ -98,6 +112,16 @@ func (m *MockJobsClient) UpdateStatus(
 	return m.UpdateStatusFn(ctx, eventID, jobName, status, opts)
 }
 
+func (m *MockJobsClient) UpdateOutputs(
+	ctx context.Context,
+	eventID string,
+	jobName string,
+	outputs sdk.JobOutputs,
+	opts *sdk.JobOutputsUpdateOptions,
+) error {
+	return m.UpdateOutputsFn(ctx, eventID, jobName, outputs, opts)
+}
+
 func (m *MockJobsClient) Cleanup(
 	ctx context.Context,
 	eventID string,
@@ -115,3 +139,16 @@ func (m *MockJobsClient) Timeout(
 ) error {
 	return m.TimeoutFn(ctx, eventID, jobName, opts)
 }
+
+func (m *MockJobsClient) Abort(
+	ctx context.Context,
+	eventID string,
+	jobName string,
+	opts *sdk.JobAbortOptions,
+) error {
+	return m.AbortFn(ctx, eventID, jobName, opts)
+}
+
+func (m *MockJobsClient) Artifacts() sdk.ArtifactsClient {
+	return m.ArtifactsClient
+}
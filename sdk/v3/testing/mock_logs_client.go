@@ -2,6 +2,7 @@ package testing
 
 import (
 	"context"
+	"io"
 
 	"github.com/brigadecore/brigade/sdk/v3"
 )
@@ -13,6 +14,23 @@ type MockLogsClient struct {
 		selector *sdk.LogsSelector,
 		opts *sdk.LogStreamOptions,
 	) (<-chan sdk.LogEntry, <-chan error, error)
+	DownloadArchiveFn func(
+		ctx context.Context,
+		eventID string,
+		opts *sdk.LogArchiveDownloadOptions,
+	) (io.ReadCloser, error)
+	SearchFn func(
+		ctx context.Context,
+		eventID string,
+		criteria sdk.LogSearchCriteria,
+		opts *sdk.LogSearchOptions,
+	) (sdk.LogSearchMatchList, error)
+	SearchProjectFn func(
+		ctx context.Context,
+		projectID string,
+		criteria sdk.LogSearchCriteria,
+		opts *sdk.LogSearchOptions,
+	) (sdk.LogSearchMatchList, error)
 }
 
 func (m *MockLogsClient) Stream(
@@ -23,3 +41,29 @@ func (m *MockLogsClient) Stream(
 ) (<-chan sdk.LogEntry, <-chan error, error) {
 	return m.StreamFn(ctx, eventID, selector, opts)
 }
+
+func (m *MockLogsClient) DownloadArchive(
+	ctx context.Context,
+	eventID string,
+	opts *sdk.LogArchiveDownloadOptions,
+) (io.ReadCloser, error) {
+	return m.DownloadArchiveFn(ctx, eventID, opts)
+}
+
+func (m *MockLogsClient) Search(
+	ctx context.Context,
+	eventID string,
+	criteria sdk.LogSearchCriteria,
+	opts *sdk.LogSearchOptions,
+) (sdk.LogSearchMatchList, error) {
+	return m.SearchFn(ctx, eventID, criteria, opts)
+}
+
+func (m *MockLogsClient) SearchProject(
+	ctx context.Context,
+	projectID string,
+	criteria sdk.LogSearchCriteria,
+	opts *sdk.LogSearchOptions,
+) (sdk.LogSearchMatchList, error) {
+	return m.SearchProjectFn(ctx, projectID, criteria, opts)
+}
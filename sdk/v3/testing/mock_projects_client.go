@@ -39,9 +39,16 @@ type MockProjectsClient struct {
 		[]byte,
 		*sdk.ProjectUpdateOptions,
 	) (sdk.Project, error)
-	DeleteFn      func(context.Context, string, *sdk.ProjectDeleteOptions) error
-	AuthzClient   sdk.ProjectAuthzClient
-	SecretsClient sdk.SecretsClient
+	DeleteFn        func(context.Context, string, *sdk.ProjectDeleteOptions) error
+	GetQueueDepthFn func(
+		context.Context,
+		string,
+		*sdk.ProjectQueueDepthGetOptions,
+	) (sdk.ProjectQueueDepth, error)
+	AuthzClient    sdk.ProjectAuthzClient
+	JobCacheClient sdk.JobCacheClient
+	SecretsClient  sdk.SecretsClient
+	WebhooksClient sdk.WebhooksClient
 }
 
 func (m *MockProjectsClient) Create(
@@ -101,10 +108,26 @@ func (m *MockProjectsClient) Delete(
 	return m.DeleteFn(ctx, id, opts)
 }
 
+func (m *MockProjectsClient) GetQueueDepth(
+	ctx context.Context,
+	id string,
+	opts *sdk.ProjectQueueDepthGetOptions,
+) (sdk.ProjectQueueDepth, error) {
+	return m.GetQueueDepthFn(ctx, id, opts)
+}
+
 func (m *MockProjectsClient) Authz() sdk.ProjectAuthzClient {
 	return m.AuthzClient
 }
 
+func (m *MockProjectsClient) JobCache() sdk.JobCacheClient {
+	return m.JobCacheClient
+}
+
 func (m *MockProjectsClient) Secrets() sdk.SecretsClient {
 	return m.SecretsClient
 }
+
+func (m *MockProjectsClient) Webhooks() sdk.WebhooksClient {
+	return m.WebhooksClient
+}
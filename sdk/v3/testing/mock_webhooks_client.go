@@ -0,0 +1,26 @@
+package testing
+
+import (
+	"context"
+
+	"github.com/brigadecore/brigade/sdk/v3"
+	"github.com/brigadecore/brigade/sdk/v3/meta"
+)
+
+type MockWebhooksClient struct {
+	ListDeliveriesFn func(
+		ctx context.Context,
+		projectID string,
+		selector *sdk.WebhookDeliveriesSelector,
+		opts *meta.ListOptions,
+	) (sdk.WebhookDeliveryList, error)
+}
+
+func (m *MockWebhooksClient) ListDeliveries(
+	ctx context.Context,
+	projectID string,
+	selector *sdk.WebhookDeliveriesSelector,
+	opts *meta.ListOptions,
+) (sdk.WebhookDeliveryList, error) {
+	return m.ListDeliveriesFn(ctx, projectID, selector, opts)
+}
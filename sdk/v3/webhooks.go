@@ -0,0 +1,233 @@
+package sdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	rm "github.com/brigadecore/brigade/sdk/v3/internal/restmachinery"
+	"github.com/brigadecore/brigade/sdk/v3/meta"
+	"github.com/brigadecore/brigade/sdk/v3/restmachinery"
+)
+
+// Webhook describes an HTTP endpoint that should be notified whenever one of
+// a Project's Workers or Jobs enters any phase of interest.
+type Webhook struct {
+	// Name is a unique (per Project) identifier for the Webhook.
+	Name string `json:"name"`
+	// URL is the address to which notifications are POSTed.
+	URL string `json:"url"`
+	// WorkerPhases enumerates the WorkerPhases of interest. The Webhook is
+	// notified whenever one of the Project's Workers enters any of these phases.
+	WorkerPhases []WorkerPhase `json:"workerPhases,omitempty"`
+	// JobPhases enumerates the JobPhases of interest. The Webhook is notified
+	// whenever one of the Project's Jobs enters any of these phases.
+	JobPhases []JobPhase `json:"jobPhases,omitempty"`
+	// SecretKey optionally specifies the key of a Project Secret whose value is
+	// used to sign notifications. When specified, every notification bears an
+	// X-Brigade-Signature-256 header containing the hex-encoded HMAC-SHA256 of
+	// the request body.
+	SecretKey string `json:"secretKey,omitempty"`
+}
+
+// Notification is the payload delivered to a Webhook when a Worker or Job
+// enters a phase of interest.
+type Notification struct {
+	// ProjectID is the identifier of the Project the Event belongs to.
+	ProjectID string `json:"projectID"`
+	// EventID is the identifier of the Event whose Worker or Job changed phase.
+	EventID string `json:"eventID"`
+	// Source is the source of the Event.
+	Source string `json:"source"`
+	// Type is the type of the Event.
+	Type string `json:"type"`
+	// WorkerPhase is the phase the Event's Worker entered. It is empty for Job
+	// notifications.
+	WorkerPhase WorkerPhase `json:"workerPhase,omitempty"`
+	// JobName is the name of the Job that changed phase. It is empty for Worker
+	// notifications.
+	JobName string `json:"jobName,omitempty"`
+	// JobPhase is the phase the Job entered. It is empty for Worker
+	// notifications.
+	JobPhase JobPhase `json:"jobPhase,omitempty"`
+	// Time is the time at which the phase change was recorded.
+	Time time.Time `json:"time"`
+}
+
+// WebhookDeliveryPhase represents where a WebhookDelivery is within its
+// lifecycle.
+type WebhookDeliveryPhase string
+
+const (
+	// WebhookDeliveryPhasePending represents the state wherein a
+	// WebhookDelivery has not yet succeeded, but will be (re)attempted.
+	WebhookDeliveryPhasePending WebhookDeliveryPhase = "PENDING"
+	// WebhookDeliveryPhaseSucceeded represents the state wherein a
+	// WebhookDelivery's endpoint responded with a 2xx status code.
+	WebhookDeliveryPhaseSucceeded WebhookDeliveryPhase = "SUCCEEDED"
+	// WebhookDeliveryPhaseFailed represents the state wherein every permitted
+	// attempt at a WebhookDelivery has failed and no further attempts will be
+	// made.
+	WebhookDeliveryPhaseFailed WebhookDeliveryPhase = "FAILED"
+)
+
+// WebhookDelivery represents the delivery of a single Notification to a single
+// Webhook, including the history of every attempt at that delivery.
+type WebhookDelivery struct {
+	// ObjectMeta contains WebhookDelivery metadata.
+	meta.ObjectMeta `json:"metadata"`
+	// ProjectID is the identifier of the Project the Webhook belongs to.
+	ProjectID string `json:"projectID"`
+	// Webhook is the name of the Webhook being notified.
+	Webhook string `json:"webhook"`
+	// URL is the address to which the Notification is POSTed.
+	URL string `json:"url"`
+	// SecretKey is the key of the Project Secret used to sign the Notification,
+	// if any.
+	SecretKey string `json:"secretKey,omitempty"`
+	// Notification is the payload being delivered.
+	Notification Notification `json:"notification"`
+	// Status contains details of the WebhookDelivery's progress.
+	Status WebhookDeliveryStatus `json:"status"`
+}
+
+// MarshalJSON amends WebhookDelivery instances with type metadata so that
+// clients do not need to be concerned with the tedium of doing so.
+func (w WebhookDelivery) MarshalJSON() ([]byte, error) {
+	type Alias WebhookDelivery
+	return json.Marshal(
+		struct {
+			meta.TypeMeta `json:",inline"`
+			Alias         `json:",inline"`
+		}{
+			TypeMeta: meta.TypeMeta{
+				APIVersion: meta.APIVersion,
+				Kind:       "WebhookDelivery",
+			},
+			Alias: (Alias)(w),
+		},
+	)
+}
+
+// WebhookDeliveryStatus represents the status of a WebhookDelivery.
+type WebhookDeliveryStatus struct {
+	// Phase indicates where the WebhookDelivery is in its lifecycle.
+	Phase WebhookDeliveryPhase `json:"phase"`
+	// Attempts lists every attempt made at the WebhookDelivery, oldest first.
+	Attempts []WebhookDeliveryAttempt `json:"attempts,omitempty"`
+	// NextAttempt indicates when the WebhookDelivery will next be attempted. It
+	// is only meaningful while the WebhookDelivery is PENDING.
+	NextAttempt *time.Time `json:"nextAttempt,omitempty"`
+}
+
+// WebhookDeliveryAttempt represents a single attempt at a WebhookDelivery.
+type WebhookDeliveryAttempt struct {
+	// Time is the time at which the attempt was made.
+	Time time.Time `json:"time"`
+	// StatusCode is the HTTP status code the endpoint responded with. It is zero
+	// if no response was received.
+	StatusCode int `json:"statusCode,omitempty"`
+	// Error describes why the attempt failed, if it did.
+	Error string `json:"error,omitempty"`
+}
+
+// WebhookDeliveryList is an ordered and pageable list of WebhookDeliveries.
+type WebhookDeliveryList struct {
+	// ListMeta contains list metadata.
+	meta.ListMeta `json:"metadata"`
+	// Items is a slice of WebhookDeliveries.
+	Items []WebhookDelivery `json:"items,omitempty"`
+}
+
+// MarshalJSON amends WebhookDeliveryList instances with type metadata so that
+// clients do not need to be concerned with the tedium of doing so.
+func (w WebhookDeliveryList) MarshalJSON() ([]byte, error) {
+	type Alias WebhookDeliveryList
+	return json.Marshal(
+		struct {
+			meta.TypeMeta `json:",inline"`
+			Alias         `json:",inline"`
+		}{
+			TypeMeta: meta.TypeMeta{
+				APIVersion: meta.APIVersion,
+				Kind:       "WebhookDeliveryList",
+			},
+			Alias: (Alias)(w),
+		},
+	)
+}
+
+// WebhookDeliveriesSelector represents useful filter criteria when selecting
+// multiple WebhookDeliveries for API group operations like list.
+type WebhookDeliveriesSelector struct {
+	// EventID specifies that only WebhookDeliveries of Notifications concerning
+	// the specified Event should be selected.
+	EventID string
+	// Webhook specifies that only WebhookDeliveries to the Webhook with the
+	// specified name should be selected.
+	Webhook string
+}
+
+// WebhooksClient is the specialized client for inspecting the deliveries of
+// Notifications to Projects' Webhooks with the Brigade API.
+type WebhooksClient interface {
+	// ListDeliveries returns a WebhookDeliveryList, with its Items
+	// (WebhookDeliveries) ordered by age, newest first. Criteria for which
+	// WebhookDeliveries should be retrieved can be specified using the
+	// WebhookDeliveriesSelector parameter.
+	ListDeliveries(
+		ctx context.Context,
+		projectID string,
+		selector *WebhookDeliveriesSelector,
+		opts *meta.ListOptions,
+	) (WebhookDeliveryList, error)
+}
+
+type webhooksClient struct {
+	*rm.BaseClient
+}
+
+// NewWebhooksClient returns a specialized client for inspecting the deliveries
+// of Notifications to Projects' Webhooks.
+func NewWebhooksClient(
+	apiAddress string,
+	apiToken string,
+	opts *restmachinery.APIClientOptions,
+) WebhooksClient {
+	return &webhooksClient{
+		BaseClient: rm.NewBaseClient(apiAddress, apiToken, opts),
+	}
+}
+
+func (w *webhooksClient) ListDeliveries(
+	ctx context.Context,
+	projectID string,
+	selector *WebhookDeliveriesSelector,
+	opts *meta.ListOptions,
+) (WebhookDeliveryList, error) {
+	queryParams := map[string]string{}
+	if selector != nil {
+		if selector.EventID != "" {
+			queryParams["eventID"] = selector.EventID
+		}
+		if selector.Webhook != "" {
+			queryParams["webhook"] = selector.Webhook
+		}
+	}
+	deliveries := WebhookDeliveryList{}
+	return deliveries, w.ExecuteRequest(
+		ctx,
+		rm.OutboundRequest{
+			Method: http.MethodGet,
+			Path: fmt.Sprintf(
+				"v2/projects/%s/webhook-deliveries",
+				projectID,
+			),
+			QueryParams: w.AppendListQueryParams(queryParams, opts),
+			SuccessCode: http.StatusOK,
+			RespObj:     &deliveries,
+		},
+	)
+}
@@ -0,0 +1,99 @@
+package sdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	rmTesting "github.com/brigadecore/brigade/sdk/v3/internal/restmachinery/testing" // nolint: lll
+	"github.com/brigadecore/brigade/sdk/v3/meta"
+	metaTesting "github.com/brigadecore/brigade/sdk/v3/meta/testing"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookDeliveryMarshalJSON(t *testing.T) {
+	metaTesting.RequireAPIVersionAndType(
+		t,
+		WebhookDelivery{},
+		"WebhookDelivery",
+	)
+}
+
+func TestWebhookDeliveryListMarshalJSON(t *testing.T) {
+	metaTesting.RequireAPIVersionAndType(
+		t,
+		WebhookDeliveryList{},
+		"WebhookDeliveryList",
+	)
+}
+
+func TestNewWebhooksClient(t *testing.T) {
+	client, ok := NewWebhooksClient(
+		rmTesting.TestAPIAddress,
+		rmTesting.TestAPIToken,
+		nil,
+	).(*webhooksClient)
+	require.True(t, ok)
+	rmTesting.RequireBaseClient(t, client.BaseClient)
+}
+
+func TestWebhooksClientListDeliveries(t *testing.T) {
+	const testProjectID = "bluebook"
+	testSelector := &WebhookDeliveriesSelector{
+		EventID: "tunguska",
+		Webhook: "slack",
+	}
+	testDeliveries := WebhookDeliveryList{
+		Items: []WebhookDelivery{
+			{
+				ObjectMeta: meta.ObjectMeta{
+					ID: "123456789",
+				},
+				ProjectID: testProjectID,
+				Webhook:   "slack",
+				Status: WebhookDeliveryStatus{
+					Phase: WebhookDeliveryPhaseSucceeded,
+				},
+			},
+		},
+	}
+	server := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				require.Equal(t, http.MethodGet, r.Method)
+				require.Equal(
+					t,
+					fmt.Sprintf("/v2/projects/%s/webhook-deliveries", testProjectID),
+					r.URL.Path,
+				)
+				require.Equal(
+					t,
+					testSelector.EventID,
+					r.URL.Query().Get("eventID"),
+				)
+				require.Equal(
+					t,
+					testSelector.Webhook,
+					r.URL.Query().Get("webhook"),
+				)
+				bodyBytes, err := json.Marshal(testDeliveries)
+				require.NoError(t, err)
+				w.WriteHeader(http.StatusOK)
+				fmt.Fprintln(w, string(bodyBytes))
+			},
+		),
+	)
+	defer server.Close()
+	client := NewWebhooksClient(server.URL, rmTesting.TestAPIToken, nil)
+	deliveries, err := client.ListDeliveries(
+		context.Background(),
+		testProjectID,
+		testSelector,
+		nil,
+	)
+	require.NoError(t, err)
+	require.Equal(t, testDeliveries, deliveries)
+}
@@ -170,10 +170,20 @@ type WorkerSpec struct {
 	// fixed-point integer using one of these suffixes: E, P, T, G, M, K.
 	// Power-of-two equivalents may also be used: Ei, Pi, Ti, Gi, Mi, Ki.
 	WorkspaceSize string `json:"workspaceSize,omitempty"`
+	// ConcurrencyGroup is an optional template, evaluated against each new
+	// Event, that determines the concurrency group the Event belongs to. e.g.
+	// {{.Git.Ref}} groups Events by git ref. When a new Event joins a group, any
+	// older Events in the group whose Workers have not yet reached a terminal
+	// phase are canceled (or aborted). Events for which the template evaluates
+	// to an empty string don't belong to any group.
+	ConcurrencyGroup string `json:"concurrencyGroup,omitempty"`
 	// Git contains git-specific Worker details.
 	Git *GitConfig `json:"git,omitempty"`
 	// Kubernetes contains Kubernetes-specific Worker details.
 	Kubernetes *KubernetesConfig `json:"kubernetes,omitempty"`
+	// Host specifies criteria for selecting a suitable host (substrate node) for
+	// the Worker.
+	Host *WorkerHost `json:"host,omitempty"`
 	// JobPolicies specifies policies for any Jobs spawned by the Worker.
 	JobPolicies *JobPolicies `json:"jobPolicies,omitempty"`
 	// LogLevel specifies the desired granularity of Worker log output.
@@ -223,11 +233,44 @@ type KubernetesConfig struct {
 	ImagePullSecrets []string `json:"imagePullSecrets,omitempty"`
 }
 
+// WorkerHost represents criteria for selecting a suitable host (substrate
+// node) for a Worker.
+type WorkerHost struct {
+	// NodeSelector specifies labels that must be present on the substrate node to
+	// host the Worker.
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+	// Tolerations permit the Worker to be hosted on substrate nodes having
+	// matching taints.
+	Tolerations []Toleration `json:"tolerations,omitempty"`
+	// NodeAffinity specifies requirements, beyond NodeSelector, that a substrate
+	// node must or should satisfy to host the Worker.
+	NodeAffinity *NodeAffinity `json:"nodeAffinity,omitempty"`
+	// PriorityClassName specifies the substrate's priority class for the
+	// Worker.
+	PriorityClassName string `json:"priorityClassName,omitempty"`
+}
+
 // JobPolicies represents policies for any Jobs spawned by a Worker.
 type JobPolicies struct {
 	// AllowPrivileged specifies whether the Worker is permitted to launch Jobs
 	// that utilize privileged containers.
 	AllowPrivileged bool `json:"allowPrivileged"`
+	// AllowedVolumeTypes enumerates the types of additional volumes that Jobs
+	// launched by the Worker are permitted to mount. If empty, Jobs may not
+	// mount any additional volumes.
+	AllowedVolumeTypes []JobVolumeType `json:"allowedVolumeTypes,omitempty"`
+	// AllowedTolerations enumerates the Tolerations that Jobs launched by the
+	// Worker are permitted to specify. A Job's Toleration is permitted if every
+	// taint it matches is also matched by one of these. If empty, Jobs may not
+	// specify any Tolerations.
+	AllowedTolerations []Toleration `json:"allowedTolerations,omitempty"`
+	// AllowNodeAffinity specifies whether Jobs launched by the Worker are
+	// permitted to specify NodeAffinity.
+	AllowNodeAffinity bool `json:"allowNodeAffinity,omitempty"`
+	// AllowedPriorityClassNames enumerates the substrate priority classes that
+	// Jobs launched by the Worker are permitted to use. If empty, Jobs may not
+	// specify a priority class.
+	AllowedPriorityClassNames []string `json:"allowedPriorityClassNames,omitempty"`
 	// AllowDockerSocketMount specifies whether the Worker is permitted to launch
 	// Jobs that mount the underlying host's Docker socket into its own file
 	// system.
@@ -252,6 +295,16 @@ type WorkerStatus struct {
 	Ended *time.Time `json:"ended,omitempty"`
 	// Phase indicates where the Worker is in its lifecycle.
 	Phase WorkerPhase `json:"phase,omitempty"`
+	// ExitCode is the exit code of the Worker's container, if it has exited.
+	ExitCode *int32 `json:"exitCode,omitempty"`
+	// Reason is a brief, machine-readable explanation of why the Worker is in its
+	// current phase, e.g. "OOMKilled", "ImagePullBackOff", or "Unschedulable".
+	Reason string `json:"reason,omitempty"`
+	// Message is a human-readable elaboration on Reason.
+	Message string `json:"message,omitempty"`
+	// Containers describes the status of each of the Worker's containers,
+	// including init containers.
+	Containers []ContainerStatus `json:"containers,omitempty"`
 }
 
 // MarshalJSON amends WorkerStatus instances with type metadata so that clients
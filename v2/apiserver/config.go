@@ -10,12 +10,16 @@ import (
 	"github.com/brigadecore/brigade-foundations/crypto"
 	"github.com/brigadecore/brigade-foundations/os"
 	"github.com/brigadecore/brigade/v2/apiserver/internal/api"
+	"github.com/brigadecore/brigade/v2/apiserver/internal/api/filesystem"
 	"github.com/brigadecore/brigade/v2/apiserver/internal/api/github"
 	"github.com/brigadecore/brigade/v2/apiserver/internal/api/kubernetes"
+	"github.com/brigadecore/brigade/v2/apiserver/internal/api/mongodb"
 	myOIDC "github.com/brigadecore/brigade/v2/apiserver/internal/api/oidc"
 	"github.com/brigadecore/brigade/v2/apiserver/internal/api/rest"
+	apiS3 "github.com/brigadecore/brigade/v2/apiserver/internal/api/s3"
 	"github.com/brigadecore/brigade/v2/apiserver/internal/lib/queue/amqp"
 	"github.com/brigadecore/brigade/v2/apiserver/internal/lib/restmachinery"
+	"github.com/brigadecore/brigade/v2/apiserver/internal/lib/s3"
 	"github.com/coreos/go-oidc"
 	"github.com/pkg/errors"
 	"go.mongodb.org/mongo-driver/mongo"
@@ -31,6 +35,16 @@ const (
 	thirdPartyAuthStrategyGitHub   = "github"
 )
 
+const (
+	artifactsStoreBackendMongoDB    = "mongodb"
+	artifactsStoreBackendFilesystem = "filesystem"
+)
+
+const (
+	coolLogsStoreBackendMongoDB = "mongodb"
+	coolLogsStoreBackendS3      = "s3"
+)
+
 // databaseConnection returns a *mongo.Database connection based on
 // configuration obtained from environment variables.
 func databaseConnection(ctx context.Context) (*mongo.Database, error) {
@@ -173,6 +187,284 @@ func substrateConfig() (kubernetes.SubstrateConfig, error) {
 	return config, nil
 }
 
+// eventsServiceConfig returns an api.EventsServiceConfig based on configuration
+// obtained from environment variables.
+func eventsServiceConfig() (api.EventsServiceConfig, error) {
+	config := api.EventsServiceConfig{}
+	var err error
+	config.IdempotencyWindow, err =
+		os.GetDurationFromEnvVar("EVENT_IDEMPOTENCY_WINDOW", 24*time.Hour)
+	if err != nil {
+		return config, err
+	}
+	log.Println("EVENT_IDEMPOTENCY_WINDOW: ", config.IdempotencyWindow)
+	return config, nil
+}
+
+// cronServiceConfig returns an api.CronServiceConfig based on configuration
+// obtained from environment variables.
+func cronServiceConfig() (api.CronServiceConfig, error) {
+	config := api.CronServiceConfig{}
+	var err error
+	config.Interval, err =
+		os.GetDurationFromEnvVar("CRON_INTERVAL", 30*time.Second)
+	if err != nil {
+		return config, err
+	}
+	log.Println("CRON_INTERVAL: ", config.Interval)
+	return config, nil
+}
+
+// retentionServiceConfig returns an api.RetentionServiceConfig based on
+// configuration obtained from environment variables.
+func retentionServiceConfig() (api.RetentionServiceConfig, error) {
+	config := api.RetentionServiceConfig{}
+	var err error
+	config.Interval, err =
+		os.GetDurationFromEnvVar("EVENT_RETENTION_INTERVAL", time.Hour)
+	if err != nil {
+		return config, err
+	}
+	log.Println("EVENT_RETENTION_INTERVAL: ", config.Interval)
+	config.Policy.MaxAge = os.GetEnvVar("EVENT_RETENTION_MAX_AGE", "")
+	if config.Policy.MaxAge != "" {
+		if _, err = time.ParseDuration(config.Policy.MaxAge); err != nil {
+			return config, errors.Wrapf(
+				err,
+				"value %q of environment variable EVENT_RETENTION_MAX_AGE was not "+
+					"parsable as a duration",
+				config.Policy.MaxAge,
+			)
+		}
+	}
+	log.Println("EVENT_RETENTION_MAX_AGE: ", config.Policy.MaxAge)
+	config.Policy.MaxCount, err =
+		os.GetIntFromEnvVar("EVENT_RETENTION_MAX_COUNT", 0)
+	if err != nil {
+		return config, err
+	}
+	log.Println("EVENT_RETENTION_MAX_COUNT: ", config.Policy.MaxCount)
+	phaseMaxAges :=
+		os.GetStringSliceFromEnvVar("EVENT_RETENTION_PHASE_MAX_AGES", []string{})
+	if len(phaseMaxAges) > 0 {
+		config.Policy.PhaseMaxAges = map[api.WorkerPhase]string{}
+	}
+	for _, phaseMaxAge := range phaseMaxAges {
+		tokens := strings.SplitN(phaseMaxAge, "=", 2)
+		if len(tokens) != 2 {
+			return config, errors.Errorf(
+				"value %q in environment variable EVENT_RETENTION_PHASE_MAX_AGES is "+
+					"not of the form PHASE=DURATION",
+				phaseMaxAge,
+			)
+		}
+		phase := api.WorkerPhase(strings.TrimSpace(tokens[0]))
+		if !phase.IsTerminal() {
+			return config, errors.Errorf(
+				"worker phase %q in environment variable "+
+					"EVENT_RETENTION_PHASE_MAX_AGES is not a terminal phase",
+				phase,
+			)
+		}
+		maxAge := strings.TrimSpace(tokens[1])
+		if _, err = time.ParseDuration(maxAge); err != nil {
+			return config, errors.Wrapf(
+				err,
+				"max age %q for worker phase %q in environment variable "+
+					"EVENT_RETENTION_PHASE_MAX_AGES was not parsable as a duration",
+				maxAge,
+				phase,
+			)
+		}
+		config.Policy.PhaseMaxAges[phase] = maxAge
+	}
+	log.Println(
+		"EVENT_RETENTION_PHASE_MAX_AGES: ",
+		config.Policy.PhaseMaxAges,
+	)
+	return config, nil
+}
+
+// webhookDeliveryServiceConfig returns an api.WebhookDeliveryServiceConfig
+// based on configuration obtained from environment variables.
+func webhookDeliveryServiceConfig() (api.WebhookDeliveryServiceConfig, error) {
+	config := api.WebhookDeliveryServiceConfig{}
+	var err error
+	config.Interval, err =
+		os.GetDurationFromEnvVar("WEBHOOK_DELIVERY_INTERVAL", 5*time.Second)
+	if err != nil {
+		return config, err
+	}
+	log.Println("WEBHOOK_DELIVERY_INTERVAL: ", config.Interval)
+	config.Timeout, err =
+		os.GetDurationFromEnvVar("WEBHOOK_DELIVERY_TIMEOUT", 10*time.Second)
+	if err != nil {
+		return config, err
+	}
+	log.Println("WEBHOOK_DELIVERY_TIMEOUT: ", config.Timeout)
+	config.MaxAttempts, err =
+		os.GetIntFromEnvVar("WEBHOOK_DELIVERY_MAX_ATTEMPTS", 8)
+	if err != nil {
+		return config, err
+	}
+	log.Println("WEBHOOK_DELIVERY_MAX_ATTEMPTS: ", config.MaxAttempts)
+	config.InitialBackoff, err = os.GetDurationFromEnvVar(
+		"WEBHOOK_DELIVERY_INITIAL_BACKOFF",
+		10*time.Second,
+	)
+	if err != nil {
+		return config, err
+	}
+	log.Println("WEBHOOK_DELIVERY_INITIAL_BACKOFF: ", config.InitialBackoff)
+	config.MaxBackoff, err =
+		os.GetDurationFromEnvVar("WEBHOOK_DELIVERY_MAX_BACKOFF", time.Hour)
+	if err != nil {
+		return config, err
+	}
+	log.Println("WEBHOOK_DELIVERY_MAX_BACKOFF: ", config.MaxBackoff)
+	return config, nil
+}
+
+// jobRetryServiceConfig returns an api.JobRetryServiceConfig based on
+// configuration obtained from environment variables.
+func jobRetryServiceConfig() (api.JobRetryServiceConfig, error) {
+	config := api.JobRetryServiceConfig{}
+	var err error
+	config.Interval, err =
+		os.GetDurationFromEnvVar("JOB_RETRY_INTERVAL", 5*time.Second)
+	if err != nil {
+		return config, err
+	}
+	log.Println("JOB_RETRY_INTERVAL: ", config.Interval)
+	return config, nil
+}
+
+// newArtifactsStore returns an appropriate implementation of
+// api.ArtifactsStore based on configuration obtained from environment
+// variables.
+func newArtifactsStore(database *mongo.Database) (api.ArtifactsStore, error) {
+	backend :=
+		os.GetEnvVar("ARTIFACTS_STORE_BACKEND", artifactsStoreBackendMongoDB)
+	log.Println("ARTIFACTS_STORE_BACKEND: ", backend)
+	switch backend {
+	case artifactsStoreBackendMongoDB:
+		return mongodb.NewArtifactsStore(database)
+	case artifactsStoreBackendFilesystem:
+		rootDir, err := os.GetRequiredEnvVar("ARTIFACTS_STORE_FILESYSTEM_ROOT")
+		if err != nil {
+			return nil, err
+		}
+		log.Println("ARTIFACTS_STORE_FILESYSTEM_ROOT: ", rootDir)
+		return filesystem.NewArtifactsStore(rootDir), nil
+	default:
+		return nil, errors.Errorf(
+			"unrecognized ARTIFACTS_STORE_BACKEND %q",
+			backend,
+		)
+	}
+}
+
+// newCoolLogsStore returns an appropriate implementation of api.CoolLogsStore
+// based on configuration obtained from environment variables.
+func newCoolLogsStore(database *mongo.Database) (api.CoolLogsStore, error) {
+	backend :=
+		os.GetEnvVar("COOL_LOGS_STORE_BACKEND", coolLogsStoreBackendMongoDB)
+	log.Println("COOL_LOGS_STORE_BACKEND: ", backend)
+	switch backend {
+	case coolLogsStoreBackendMongoDB:
+		return mongodb.NewLogsStore(database), nil
+	case coolLogsStoreBackendS3:
+		config, err := coolLogsS3ClientConfig()
+		if err != nil {
+			return nil, err
+		}
+		client, err := s3.NewClient(config)
+		if err != nil {
+			return nil, err
+		}
+		return apiS3.NewLogsStore(client), nil
+	default:
+		return nil, errors.Errorf(
+			"unrecognized COOL_LOGS_STORE_BACKEND %q",
+			backend,
+		)
+	}
+}
+
+// newCoolLogsWriter returns an implementation of api.CoolLogsWriter, suitable
+// for migrating logs out of MongoDB, based on configuration obtained from
+// environment variables.
+func newCoolLogsWriter() (api.CoolLogsWriter, error) {
+	backend :=
+		os.GetEnvVar("COOL_LOGS_STORE_BACKEND", coolLogsStoreBackendMongoDB)
+	log.Println("COOL_LOGS_STORE_BACKEND: ", backend)
+	if backend != coolLogsStoreBackendS3 {
+		return nil, errors.Errorf(
+			"logs can only be migrated when COOL_LOGS_STORE_BACKEND is %q",
+			coolLogsStoreBackendS3,
+		)
+	}
+	config, err := coolLogsS3ClientConfig()
+	if err != nil {
+		return nil, err
+	}
+	client, err := s3.NewClient(config)
+	if err != nil {
+		return nil, err
+	}
+	return apiS3.NewLogsWriter(client), nil
+}
+
+// coolLogsS3ClientConfig returns an s3.ClientConfig for the bucket in which
+// cool logs are stored based on configuration obtained from environment
+// variables.
+func coolLogsS3ClientConfig() (s3.ClientConfig, error) {
+	config := s3.ClientConfig{}
+	var err error
+	config.Endpoint, err = os.GetRequiredEnvVar("COOL_LOGS_STORE_S3_ENDPOINT")
+	if err != nil {
+		return config, err
+	}
+	log.Println("COOL_LOGS_STORE_S3_ENDPOINT: ", config.Endpoint)
+	config.Region = os.GetEnvVar("COOL_LOGS_STORE_S3_REGION", "us-east-1")
+	log.Println("COOL_LOGS_STORE_S3_REGION: ", config.Region)
+	config.Bucket, err = os.GetRequiredEnvVar("COOL_LOGS_STORE_S3_BUCKET")
+	if err != nil {
+		return config, err
+	}
+	log.Println("COOL_LOGS_STORE_S3_BUCKET: ", config.Bucket)
+	config.AccessKeyID, err =
+		os.GetRequiredEnvVar("COOL_LOGS_STORE_S3_ACCESS_KEY_ID")
+	if err != nil {
+		return config, err
+	}
+	config.SecretAccessKey, err =
+		os.GetRequiredEnvVar("COOL_LOGS_STORE_S3_SECRET_ACCESS_KEY")
+	if err != nil {
+		return config, err
+	}
+	config.PathStyle, err =
+		os.GetBoolFromEnvVar("COOL_LOGS_STORE_S3_PATH_STYLE", false)
+	if err != nil {
+		return config, err
+	}
+	log.Println("COOL_LOGS_STORE_S3_PATH_STYLE: ", config.PathStyle)
+	return config, nil
+}
+
+// artifactsServiceConfig returns an api.ArtifactsServiceConfig based on
+// configuration obtained from environment variables.
+func artifactsServiceConfig() (api.ArtifactsServiceConfig, error) {
+	config := api.ArtifactsServiceConfig{}
+	maxSize, err := os.GetIntFromEnvVar("ARTIFACT_MAX_SIZE", 100*1024*1024)
+	if err != nil {
+		return config, err
+	}
+	config.MaxSize = int64(maxSize)
+	log.Println("ARTIFACT_MAX_SIZE: ", config.MaxSize)
+	return config, nil
+}
+
 // thirdPartyAuthHelper returns an appropriate instance of
 // api.ThirdPartyAuthHelper based on configuration obtained from environment
 // variables.
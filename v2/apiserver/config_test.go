@@ -8,6 +8,7 @@ import (
 	"net/http/httptest"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/brigadecore/brigade/v2/apiserver/internal/api"
 	"github.com/brigadecore/brigade/v2/apiserver/internal/api/kubernetes"
@@ -350,6 +351,597 @@ func TestSubstrateConfig(t *testing.T) {
 	}
 }
 
+func TestEventsServiceConfig(t *testing.T) {
+	testCases := []struct {
+		name       string
+		setup      func()
+		assertions func(api.EventsServiceConfig, error)
+	}{
+		{
+			name: "EVENT_IDEMPOTENCY_WINDOW not parsable as duration",
+			setup: func() {
+				t.Setenv("EVENT_IDEMPOTENCY_WINDOW", "for a while")
+			},
+			assertions: func(_ api.EventsServiceConfig, err error) {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), "was not parsable as a duration")
+				require.Contains(t, err.Error(), "EVENT_IDEMPOTENCY_WINDOW")
+			},
+		},
+		{
+			name: "success",
+			setup: func() {
+				t.Setenv("EVENT_IDEMPOTENCY_WINDOW", "1h")
+			},
+			assertions: func(config api.EventsServiceConfig, err error) {
+				require.NoError(t, err)
+				require.Equal(
+					t,
+					api.EventsServiceConfig{
+						IdempotencyWindow: time.Hour,
+					},
+					config,
+				)
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.setup()
+			config, err := eventsServiceConfig()
+			testCase.assertions(config, err)
+		})
+	}
+}
+
+func TestCronServiceConfig(t *testing.T) {
+	testCases := []struct {
+		name       string
+		setup      func()
+		assertions func(api.CronServiceConfig, error)
+	}{
+		{
+			name: "CRON_INTERVAL not parsable as duration",
+			setup: func() {
+				t.Setenv("CRON_INTERVAL", "every now and then")
+			},
+			assertions: func(_ api.CronServiceConfig, err error) {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), "was not parsable as a duration")
+				require.Contains(t, err.Error(), "CRON_INTERVAL")
+			},
+		},
+		{
+			name: "success",
+			setup: func() {
+				t.Setenv("CRON_INTERVAL", "1m")
+			},
+			assertions: func(config api.CronServiceConfig, err error) {
+				require.NoError(t, err)
+				require.Equal(
+					t,
+					api.CronServiceConfig{
+						Interval: time.Minute,
+					},
+					config,
+				)
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.setup()
+			config, err := cronServiceConfig()
+			testCase.assertions(config, err)
+		})
+	}
+}
+
+func TestRetentionServiceConfig(t *testing.T) {
+	testCases := []struct {
+		name       string
+		setup      func()
+		assertions func(api.RetentionServiceConfig, error)
+	}{
+		{
+			name: "EVENT_RETENTION_INTERVAL not parsable as duration",
+			setup: func() {
+				t.Setenv("EVENT_RETENTION_INTERVAL", "every now and then")
+			},
+			assertions: func(_ api.RetentionServiceConfig, err error) {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), "was not parsable as a duration")
+				require.Contains(t, err.Error(), "EVENT_RETENTION_INTERVAL")
+			},
+		},
+		{
+			name: "EVENT_RETENTION_MAX_AGE not parsable as duration",
+			setup: func() {
+				t.Setenv("EVENT_RETENTION_INTERVAL", "1m")
+				t.Setenv("EVENT_RETENTION_MAX_AGE", "forever")
+			},
+			assertions: func(_ api.RetentionServiceConfig, err error) {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), "was not parsable as a duration")
+				require.Contains(t, err.Error(), "EVENT_RETENTION_MAX_AGE")
+			},
+		},
+		{
+			name: "EVENT_RETENTION_MAX_COUNT not parsable as int",
+			setup: func() {
+				t.Setenv("EVENT_RETENTION_INTERVAL", "1m")
+				t.Setenv("EVENT_RETENTION_MAX_AGE", "720h")
+				t.Setenv("EVENT_RETENTION_MAX_COUNT", "lots")
+			},
+			assertions: func(_ api.RetentionServiceConfig, err error) {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), "was not parsable as an int")
+				require.Contains(t, err.Error(), "EVENT_RETENTION_MAX_COUNT")
+			},
+		},
+		{
+			name: "EVENT_RETENTION_PHASE_MAX_AGES malformed",
+			setup: func() {
+				t.Setenv("EVENT_RETENTION_INTERVAL", "1m")
+				t.Setenv("EVENT_RETENTION_MAX_AGE", "720h")
+				t.Setenv("EVENT_RETENTION_MAX_COUNT", "1000")
+				t.Setenv("EVENT_RETENTION_PHASE_MAX_AGES", "FAILED")
+			},
+			assertions: func(_ api.RetentionServiceConfig, err error) {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), "not of the form PHASE=DURATION")
+			},
+		},
+		{
+			name: "EVENT_RETENTION_PHASE_MAX_AGES specifies non-terminal phase",
+			setup: func() {
+				t.Setenv("EVENT_RETENTION_INTERVAL", "1m")
+				t.Setenv("EVENT_RETENTION_MAX_AGE", "720h")
+				t.Setenv("EVENT_RETENTION_MAX_COUNT", "1000")
+				t.Setenv("EVENT_RETENTION_PHASE_MAX_AGES", "RUNNING=1h")
+			},
+			assertions: func(_ api.RetentionServiceConfig, err error) {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), "is not a terminal phase")
+			},
+		},
+		{
+			name: "EVENT_RETENTION_PHASE_MAX_AGES duration not parsable",
+			setup: func() {
+				t.Setenv("EVENT_RETENTION_INTERVAL", "1m")
+				t.Setenv("EVENT_RETENTION_MAX_AGE", "720h")
+				t.Setenv("EVENT_RETENTION_MAX_COUNT", "1000")
+				t.Setenv("EVENT_RETENTION_PHASE_MAX_AGES", "FAILED=forever")
+			},
+			assertions: func(_ api.RetentionServiceConfig, err error) {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), "was not parsable as a duration")
+			},
+		},
+		{
+			name: "success",
+			setup: func() {
+				t.Setenv("EVENT_RETENTION_INTERVAL", "1m")
+				t.Setenv("EVENT_RETENTION_MAX_AGE", "720h")
+				t.Setenv("EVENT_RETENTION_MAX_COUNT", "1000")
+				t.Setenv(
+					"EVENT_RETENTION_PHASE_MAX_AGES",
+					"FAILED=2160h,TIMED_OUT=2160h",
+				)
+			},
+			assertions: func(config api.RetentionServiceConfig, err error) {
+				require.NoError(t, err)
+				require.Equal(
+					t,
+					api.RetentionServiceConfig{
+						Interval: time.Minute,
+						Policy: api.RetentionPolicy{
+							MaxAge:   "720h",
+							MaxCount: 1000,
+							PhaseMaxAges: map[api.WorkerPhase]string{
+								api.WorkerPhaseFailed:   "2160h",
+								api.WorkerPhaseTimedOut: "2160h",
+							},
+						},
+					},
+					config,
+				)
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.setup()
+			config, err := retentionServiceConfig()
+			testCase.assertions(config, err)
+		})
+	}
+}
+
+func TestWebhookDeliveryServiceConfig(t *testing.T) {
+	testCases := []struct {
+		name       string
+		setup      func()
+		assertions func(api.WebhookDeliveryServiceConfig, error)
+	}{
+		{
+			name: "WEBHOOK_DELIVERY_INTERVAL not parsable as duration",
+			setup: func() {
+				t.Setenv("WEBHOOK_DELIVERY_INTERVAL", "every now and then")
+			},
+			assertions: func(_ api.WebhookDeliveryServiceConfig, err error) {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), "was not parsable as a duration")
+				require.Contains(t, err.Error(), "WEBHOOK_DELIVERY_INTERVAL")
+			},
+		},
+		{
+			name: "WEBHOOK_DELIVERY_TIMEOUT not parsable as duration",
+			setup: func() {
+				t.Setenv("WEBHOOK_DELIVERY_INTERVAL", "1s")
+				t.Setenv("WEBHOOK_DELIVERY_TIMEOUT", "a while")
+			},
+			assertions: func(_ api.WebhookDeliveryServiceConfig, err error) {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), "was not parsable as a duration")
+				require.Contains(t, err.Error(), "WEBHOOK_DELIVERY_TIMEOUT")
+			},
+		},
+		{
+			name: "WEBHOOK_DELIVERY_MAX_ATTEMPTS not parsable as int",
+			setup: func() {
+				t.Setenv("WEBHOOK_DELIVERY_INTERVAL", "1s")
+				t.Setenv("WEBHOOK_DELIVERY_TIMEOUT", "5s")
+				t.Setenv("WEBHOOK_DELIVERY_MAX_ATTEMPTS", "lots")
+			},
+			assertions: func(_ api.WebhookDeliveryServiceConfig, err error) {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), "was not parsable as an int")
+				require.Contains(t, err.Error(), "WEBHOOK_DELIVERY_MAX_ATTEMPTS")
+			},
+		},
+		{
+			name: "WEBHOOK_DELIVERY_INITIAL_BACKOFF not parsable as duration",
+			setup: func() {
+				t.Setenv("WEBHOOK_DELIVERY_INTERVAL", "1s")
+				t.Setenv("WEBHOOK_DELIVERY_TIMEOUT", "5s")
+				t.Setenv("WEBHOOK_DELIVERY_MAX_ATTEMPTS", "3")
+				t.Setenv("WEBHOOK_DELIVERY_INITIAL_BACKOFF", "a bit")
+			},
+			assertions: func(_ api.WebhookDeliveryServiceConfig, err error) {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), "was not parsable as a duration")
+				require.Contains(t, err.Error(), "WEBHOOK_DELIVERY_INITIAL_BACKOFF")
+			},
+		},
+		{
+			name: "WEBHOOK_DELIVERY_MAX_BACKOFF not parsable as duration",
+			setup: func() {
+				t.Setenv("WEBHOOK_DELIVERY_INTERVAL", "1s")
+				t.Setenv("WEBHOOK_DELIVERY_TIMEOUT", "5s")
+				t.Setenv("WEBHOOK_DELIVERY_MAX_ATTEMPTS", "3")
+				t.Setenv("WEBHOOK_DELIVERY_INITIAL_BACKOFF", "1s")
+				t.Setenv("WEBHOOK_DELIVERY_MAX_BACKOFF", "a lot")
+			},
+			assertions: func(_ api.WebhookDeliveryServiceConfig, err error) {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), "was not parsable as a duration")
+				require.Contains(t, err.Error(), "WEBHOOK_DELIVERY_MAX_BACKOFF")
+			},
+		},
+		{
+			name: "success",
+			setup: func() {
+				t.Setenv("WEBHOOK_DELIVERY_INTERVAL", "1s")
+				t.Setenv("WEBHOOK_DELIVERY_TIMEOUT", "5s")
+				t.Setenv("WEBHOOK_DELIVERY_MAX_ATTEMPTS", "3")
+				t.Setenv("WEBHOOK_DELIVERY_INITIAL_BACKOFF", "1s")
+				t.Setenv("WEBHOOK_DELIVERY_MAX_BACKOFF", "1m")
+			},
+			assertions: func(config api.WebhookDeliveryServiceConfig, err error) {
+				require.NoError(t, err)
+				require.Equal(
+					t,
+					api.WebhookDeliveryServiceConfig{
+						Interval:       time.Second,
+						Timeout:        5 * time.Second,
+						MaxAttempts:    3,
+						InitialBackoff: time.Second,
+						MaxBackoff:     time.Minute,
+					},
+					config,
+				)
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.setup()
+			config, err := webhookDeliveryServiceConfig()
+			testCase.assertions(config, err)
+		})
+	}
+}
+
+func TestJobRetryServiceConfig(t *testing.T) {
+	testCases := []struct {
+		name       string
+		setup      func()
+		assertions func(api.JobRetryServiceConfig, error)
+	}{
+		{
+			name: "JOB_RETRY_INTERVAL not parsable as duration",
+			setup: func() {
+				t.Setenv("JOB_RETRY_INTERVAL", "every now and then")
+			},
+			assertions: func(_ api.JobRetryServiceConfig, err error) {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), "was not parsable as a duration")
+				require.Contains(t, err.Error(), "JOB_RETRY_INTERVAL")
+			},
+		},
+		{
+			name: "success",
+			setup: func() {
+				t.Setenv("JOB_RETRY_INTERVAL", "10s")
+			},
+			assertions: func(config api.JobRetryServiceConfig, err error) {
+				require.NoError(t, err)
+				require.Equal(
+					t,
+					api.JobRetryServiceConfig{
+						Interval: 10 * time.Second,
+					},
+					config,
+				)
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.setup()
+			config, err := jobRetryServiceConfig()
+			testCase.assertions(config, err)
+		})
+	}
+}
+
+func TestNewArtifactsStore(t *testing.T) {
+	testCases := []struct {
+		name       string
+		setup      func()
+		assertions func(api.ArtifactsStore, error)
+	}{
+		{
+			name: "ARTIFACTS_STORE_BACKEND has invalid value",
+			setup: func() {
+				t.Setenv("ARTIFACTS_STORE_BACKEND", "bogus")
+			},
+			assertions: func(_ api.ArtifactsStore, err error) {
+				require.Error(t, err)
+				require.Contains(
+					t,
+					err.Error(),
+					"unrecognized ARTIFACTS_STORE_BACKEND",
+				)
+			},
+		},
+		{
+			name: "ARTIFACTS_STORE_FILESYSTEM_ROOT required but not set",
+			setup: func() {
+				t.Setenv("ARTIFACTS_STORE_BACKEND", artifactsStoreBackendFilesystem)
+			},
+			assertions: func(_ api.ArtifactsStore, err error) {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), "value not found for")
+				require.Contains(t, err.Error(), "ARTIFACTS_STORE_FILESYSTEM_ROOT")
+			},
+		},
+		{
+			name: "success with filesystem backend",
+			setup: func() {
+				t.Setenv("ARTIFACTS_STORE_FILESYSTEM_ROOT", "/var/lib/brigade")
+			},
+			assertions: func(store api.ArtifactsStore, err error) {
+				require.NoError(t, err)
+				require.NotNil(t, store)
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.setup()
+			store, err := newArtifactsStore(nil)
+			testCase.assertions(store, err)
+		})
+	}
+}
+
+func TestNewCoolLogsStore(t *testing.T) {
+	testCases := []struct {
+		name       string
+		setup      func()
+		assertions func(api.CoolLogsStore, error)
+	}{
+		{
+			name: "COOL_LOGS_STORE_BACKEND has invalid value",
+			setup: func() {
+				t.Setenv("COOL_LOGS_STORE_BACKEND", "bogus")
+			},
+			assertions: func(_ api.CoolLogsStore, err error) {
+				require.Error(t, err)
+				require.Contains(
+					t,
+					err.Error(),
+					"unrecognized COOL_LOGS_STORE_BACKEND",
+				)
+			},
+		},
+		{
+			name: "COOL_LOGS_STORE_S3_ENDPOINT required but not set",
+			setup: func() {
+				t.Setenv("COOL_LOGS_STORE_BACKEND", coolLogsStoreBackendS3)
+			},
+			assertions: func(_ api.CoolLogsStore, err error) {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), "value not found for")
+				require.Contains(t, err.Error(), "COOL_LOGS_STORE_S3_ENDPOINT")
+			},
+		},
+		{
+			name: "COOL_LOGS_STORE_S3_BUCKET required but not set",
+			setup: func() {
+				t.Setenv("COOL_LOGS_STORE_S3_ENDPOINT", "http://minio:9000")
+			},
+			assertions: func(_ api.CoolLogsStore, err error) {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), "value not found for")
+				require.Contains(t, err.Error(), "COOL_LOGS_STORE_S3_BUCKET")
+			},
+		},
+		{
+			name: "COOL_LOGS_STORE_S3_ACCESS_KEY_ID required but not set",
+			setup: func() {
+				t.Setenv("COOL_LOGS_STORE_S3_BUCKET", "brigade-logs")
+			},
+			assertions: func(_ api.CoolLogsStore, err error) {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), "value not found for")
+				require.Contains(
+					t,
+					err.Error(),
+					"COOL_LOGS_STORE_S3_ACCESS_KEY_ID",
+				)
+			},
+		},
+		{
+			name: "COOL_LOGS_STORE_S3_SECRET_ACCESS_KEY required but not set",
+			setup: func() {
+				t.Setenv("COOL_LOGS_STORE_S3_ACCESS_KEY_ID", "minioadmin")
+			},
+			assertions: func(_ api.CoolLogsStore, err error) {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), "value not found for")
+				require.Contains(
+					t,
+					err.Error(),
+					"COOL_LOGS_STORE_S3_SECRET_ACCESS_KEY",
+				)
+			},
+		},
+		{
+			name: "COOL_LOGS_STORE_S3_PATH_STYLE not parsable as bool",
+			setup: func() {
+				t.Setenv("COOL_LOGS_STORE_S3_SECRET_ACCESS_KEY", "minioadmin")
+				t.Setenv("COOL_LOGS_STORE_S3_PATH_STYLE", "sometimes")
+			},
+			assertions: func(_ api.CoolLogsStore, err error) {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), "was not parsable as a bool")
+				require.Contains(t, err.Error(), "COOL_LOGS_STORE_S3_PATH_STYLE")
+			},
+		},
+		{
+			name: "success with s3 backend",
+			setup: func() {
+				t.Setenv("COOL_LOGS_STORE_S3_PATH_STYLE", "true")
+			},
+			assertions: func(store api.CoolLogsStore, err error) {
+				require.NoError(t, err)
+				require.NotNil(t, store)
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.setup()
+			store, err := newCoolLogsStore(nil)
+			testCase.assertions(store, err)
+		})
+	}
+}
+
+func TestNewCoolLogsWriter(t *testing.T) {
+	testCases := []struct {
+		name       string
+		setup      func()
+		assertions func(api.CoolLogsWriter, error)
+	}{
+		{
+			name:  "COOL_LOGS_STORE_BACKEND is not s3",
+			setup: func() {},
+			assertions: func(_ api.CoolLogsWriter, err error) {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), "can only be migrated")
+			},
+		},
+		{
+			name: "success",
+			setup: func() {
+				t.Setenv("COOL_LOGS_STORE_BACKEND", coolLogsStoreBackendS3)
+				t.Setenv("COOL_LOGS_STORE_S3_ENDPOINT", "http://minio:9000")
+				t.Setenv("COOL_LOGS_STORE_S3_BUCKET", "brigade-logs")
+				t.Setenv("COOL_LOGS_STORE_S3_ACCESS_KEY_ID", "minioadmin")
+				t.Setenv("COOL_LOGS_STORE_S3_SECRET_ACCESS_KEY", "minioadmin")
+			},
+			assertions: func(writer api.CoolLogsWriter, err error) {
+				require.NoError(t, err)
+				require.NotNil(t, writer)
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.setup()
+			writer, err := newCoolLogsWriter()
+			testCase.assertions(writer, err)
+		})
+	}
+}
+
+func TestArtifactsServiceConfig(t *testing.T) {
+	testCases := []struct {
+		name       string
+		setup      func()
+		assertions func(api.ArtifactsServiceConfig, error)
+	}{
+		{
+			name: "ARTIFACT_MAX_SIZE not parsable as int",
+			setup: func() {
+				t.Setenv("ARTIFACT_MAX_SIZE", "huge")
+			},
+			assertions: func(_ api.ArtifactsServiceConfig, err error) {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), "was not parsable as an int")
+				require.Contains(t, err.Error(), "ARTIFACT_MAX_SIZE")
+			},
+		},
+		{
+			name: "success",
+			setup: func() {
+				t.Setenv("ARTIFACT_MAX_SIZE", "1024")
+			},
+			assertions: func(config api.ArtifactsServiceConfig, err error) {
+				require.NoError(t, err)
+				require.Equal(
+					t,
+					api.ArtifactsServiceConfig{
+						MaxSize: 1024,
+					},
+					config,
+				)
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.setup()
+			config, err := artifactsServiceConfig()
+			testCase.assertions(config, err)
+		})
+	}
+}
+
 func TestThirdPartyAuthHelper(t *testing.T) {
 	// Set up test OIDC auth server
 	server := httptest.NewServer(
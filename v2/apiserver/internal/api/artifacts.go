@@ -0,0 +1,355 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"time"
+
+	"github.com/brigadecore/brigade/v2/apiserver/internal/meta"
+	"github.com/pkg/errors"
+)
+
+// ArtifactKind represents the canonical Artifact kind string
+const ArtifactKind = "Artifact"
+
+// artifactNameRegex matches valid Artifact names. Names are restricted to
+// characters that are safe to use as file names on any platform and may not
+// begin with a dot.
+var artifactNameRegex = regexp.MustCompile(
+	`^[A-Za-z0-9][A-Za-z0-9._-]{0,252}$`,
+)
+
+// errArtifactTooLarge is returned by reads from an artifactReader once it has
+// yielded more than the maximum number of bytes permitted for an Artifact.
+var errArtifactTooLarge = errors.New("artifact exceeds maximum size")
+
+// Artifact represents a named file that was produced by a Job and retained by
+// Brigade for as long as the Event that spawned the Job.
+type Artifact struct {
+	// Name is a unique (per Job) identifier for the Artifact.
+	Name string `json:"name" bson:"name"`
+	// Size is the size of the Artifact in bytes.
+	Size int64 `json:"size" bson:"size"`
+	// Created indicates the time at which the Artifact was uploaded.
+	Created *time.Time `json:"created,omitempty" bson:"created,omitempty"`
+}
+
+// MarshalJSON amends Artifact instances with type metadata so that clients do
+// not need to be concerned with the tedium of doing so.
+func (a Artifact) MarshalJSON() ([]byte, error) {
+	type Alias Artifact
+	return json.Marshal(
+		struct {
+			meta.TypeMeta `json:",inline"`
+			Alias         `json:",inline"`
+		}{
+			TypeMeta: meta.TypeMeta{
+				APIVersion: meta.APIVersion,
+				Kind:       ArtifactKind,
+			},
+			Alias: (Alias)(a),
+		},
+	)
+}
+
+// ArtifactsServiceConfig encapsulates configuration options for the
+// ArtifactsService.
+type ArtifactsServiceConfig struct {
+	// MaxSize specifies the largest permissible size of any single Artifact, in
+	// bytes.
+	MaxSize int64
+}
+
+// ArtifactsService is the specialized interface for managing Artifacts. It's
+// decoupled from underlying technology choices (e.g. data store, message bus,
+// etc.) to keep business logic reusable and consistent while the underlying
+// tech stack remains free to change.
+type ArtifactsService interface {
+	// Upload stores the content read from the provided io.Reader as an Artifact
+	// of the specified Event's specified Job, replacing any existing Artifact of
+	// the same name. If the specified Event or Job does not exist,
+	// implementations MUST return a *meta.ErrNotFound error. If the Artifact name
+	// is invalid or the content exceeds the maximum permissible size,
+	// implementations MUST return a *meta.ErrBadRequest error.
+	Upload(
+		ctx context.Context,
+		eventID string,
+		jobName string,
+		artifactName string,
+		content io.Reader,
+	) (Artifact, error)
+	// List returns an ArtifactList, with its Items (Artifacts) ordered
+	// alphabetically by name, containing all Artifacts of the specified Event's
+	// specified Job. If the specified Event or Job does not exist,
+	// implementations MUST return a *meta.ErrNotFound error.
+	List(
+		ctx context.Context,
+		eventID string,
+		jobName string,
+	) (meta.List[Artifact], error)
+	// Download returns the specified Artifact of the specified Event's specified
+	// Job along with an io.ReadCloser from which its content can be read. Callers
+	// are responsible for closing the io.ReadCloser. If the specified Event, Job,
+	// or Artifact does not exist, implementations MUST return a
+	// *meta.ErrNotFound error. If the Artifact name is invalid, implementations
+	// MUST return a *meta.ErrBadRequest error.
+	Download(
+		ctx context.Context,
+		eventID string,
+		jobName string,
+		artifactName string,
+	) (Artifact, io.ReadCloser, error)
+}
+
+type artifactsService struct {
+	authorize        AuthorizeFn
+	projectAuthorize ProjectAuthorizeFn
+	eventsStore      EventsStore
+	artifactsStore   ArtifactsStore
+	config           ArtifactsServiceConfig
+}
+
+// NewArtifactsService returns a specialized interface for managing Artifacts.
+func NewArtifactsService(
+	authorizeFn AuthorizeFn,
+	projectAuthorize ProjectAuthorizeFn,
+	eventsStore EventsStore,
+	artifactsStore ArtifactsStore,
+	config ArtifactsServiceConfig,
+) ArtifactsService {
+	return &artifactsService{
+		authorize:        authorizeFn,
+		projectAuthorize: projectAuthorize,
+		eventsStore:      eventsStore,
+		artifactsStore:   artifactsStore,
+		config:           config,
+	}
+}
+
+func (a *artifactsService) Upload(
+	ctx context.Context,
+	eventID string,
+	jobName string,
+	artifactName string,
+	content io.Reader,
+) (Artifact, error) {
+	if err := a.authorize(ctx, RoleWorker, eventID); err != nil {
+		return Artifact{}, err
+	}
+
+	if err := validateArtifactName(artifactName); err != nil {
+		return Artifact{}, err
+	}
+
+	event, err := a.getEventWithJob(ctx, eventID, jobName)
+	if err != nil {
+		return Artifact{}, err
+	}
+
+	artifact, err := a.artifactsStore.Put(
+		ctx,
+		event,
+		jobName,
+		artifactName,
+		&artifactReader{
+			reader:    content,
+			remaining: a.config.MaxSize,
+		},
+	)
+	if err != nil {
+		if errors.Cause(err) == errArtifactTooLarge {
+			return Artifact{}, &meta.ErrBadRequest{
+				Reason: fmt.Sprintf(
+					"Artifact exceeds the maximum permissible size of %d bytes.",
+					a.config.MaxSize,
+				),
+			}
+		}
+		return Artifact{}, errors.Wrapf(
+			err,
+			"error storing event %q job %q artifact %q",
+			eventID,
+			jobName,
+			artifactName,
+		)
+	}
+	return artifact, nil
+}
+
+func (a *artifactsService) List(
+	ctx context.Context,
+	eventID string,
+	jobName string,
+) (meta.List[Artifact], error) {
+	event, err := a.getEventWithJob(ctx, eventID, jobName)
+	if err != nil {
+		return meta.List[Artifact]{}, err
+	}
+	if err = a.authorizeRead(ctx, event); err != nil {
+		return meta.List[Artifact]{}, err
+	}
+	artifacts, err := a.artifactsStore.List(ctx, event, jobName)
+	if err != nil {
+		return artifacts, errors.Wrapf(
+			err,
+			"error retrieving event %q job %q artifacts from store",
+			eventID,
+			jobName,
+		)
+	}
+	return artifacts, nil
+}
+
+func (a *artifactsService) Download(
+	ctx context.Context,
+	eventID string,
+	jobName string,
+	artifactName string,
+) (Artifact, io.ReadCloser, error) {
+	// Names that could never have been uploaded are rejected up front so that
+	// the store is never asked for anything else it may hold, such as an
+	// in-progress upload
+	if err := validateArtifactName(artifactName); err != nil {
+		return Artifact{}, nil, err
+	}
+	event, err := a.getEventWithJob(ctx, eventID, jobName)
+	if err != nil {
+		return Artifact{}, nil, err
+	}
+	if err = a.authorizeRead(ctx, event); err != nil {
+		return Artifact{}, nil, err
+	}
+	artifact, content, err :=
+		a.artifactsStore.Get(ctx, event, jobName, artifactName)
+	if err != nil {
+		return Artifact{}, nil, errors.Wrapf(
+			err,
+			"error retrieving event %q job %q artifact %q from store",
+			eventID,
+			jobName,
+			artifactName,
+		)
+	}
+	return artifact, content, nil
+}
+
+// validateArtifactName returns a *meta.ErrBadRequest error if the provided
+// Artifact name is invalid.
+func validateArtifactName(artifactName string) error {
+	if !artifactNameRegex.MatchString(artifactName) {
+		return &meta.ErrBadRequest{
+			Reason: fmt.Sprintf(
+				"Artifact name %q is invalid. Names may contain only letters, "+
+					"digits, dots, dashes, and underscores; may not begin with a dot, "+
+					"dash, or underscore; and may not exceed 253 characters.",
+				artifactName,
+			),
+		}
+	}
+	return nil
+}
+
+// getEventWithJob retrieves the specified Event from the store and verifies
+// that it has a Job with the specified name. If it does not, a
+// *meta.ErrNotFound error is returned.
+func (a *artifactsService) getEventWithJob(
+	ctx context.Context,
+	eventID string,
+	jobName string,
+) (Event, error) {
+	event, err := a.eventsStore.Get(ctx, eventID)
+	if err != nil {
+		return event,
+			errors.Wrapf(err, "error retrieving event %q from store", eventID)
+	}
+	if _, ok := event.Worker.Job(jobName); !ok {
+		return event, &meta.ErrNotFound{
+			Type: JobKind,
+			ID:   jobName,
+		}
+	}
+	return event, nil
+}
+
+// authorizeRead verifies that the principal may read the provided Event's
+// Artifacts. Much like logs, Artifacts are apt to contain sensitive
+// information, so rather than settling for RoleReader(), this requires the
+// principal to be a user of the Event's Project or the Event's own Worker.
+func (a *artifactsService) authorizeRead(
+	ctx context.Context,
+	event Event,
+) error {
+	err := a.projectAuthorize(ctx, event.ProjectID, RoleProjectUser)
+	if err != nil {
+		err = a.authorize(ctx, RoleWorker, event.ID)
+	}
+	return err
+}
+
+// artifactReader wraps an io.Reader and yields an errArtifactTooLarge error
+// once more than a specified number of bytes have been read from it.
+type artifactReader struct {
+	reader    io.Reader
+	remaining int64
+}
+
+func (a *artifactReader) Read(p []byte) (int, error) {
+	if a.remaining < 0 {
+		return 0, errArtifactTooLarge
+	}
+	// Read at most one byte more than what remains so we can tell whether the
+	// limit has been exceeded.
+	if int64(len(p)) > a.remaining+1 {
+		p = p[:a.remaining+1]
+	}
+	n, err := a.reader.Read(p)
+	a.remaining -= int64(n)
+	if a.remaining < 0 {
+		return n, errArtifactTooLarge
+	}
+	return n, err
+}
+
+// ArtifactsStore is an interface for components that implement Artifact
+// persistence concerns.
+type ArtifactsStore interface {
+	// Put stores the content read from the provided io.Reader as an Artifact of
+	// the provided Event's specified Job, replacing any existing Artifact of the
+	// same name. If reading from the io.Reader fails, implementations MUST NOT
+	// retain partial content and MUST return an error from which the read error
+	// can be recovered using errors.Cause().
+	Put(
+		ctx context.Context,
+		event Event,
+		jobName string,
+		artifactName string,
+		content io.Reader,
+	) (Artifact, error)
+	// List returns an ArtifactList, with its Items (Artifacts) ordered
+	// alphabetically by name, containing all Artifacts of the provided Event's
+	// specified Job.
+	List(
+		ctx context.Context,
+		event Event,
+		jobName string,
+	) (meta.List[Artifact], error)
+	// Get returns the specified Artifact of the provided Event's specified Job
+	// along with an io.ReadCloser from which its content can be read. If the
+	// specified Artifact does not exist, implementations MUST return a
+	// *meta.ErrNotFound error.
+	Get(
+		ctx context.Context,
+		event Event,
+		jobName string,
+		artifactName string,
+	) (Artifact, io.ReadCloser, error)
+	// DeleteEventArtifacts deletes all Artifacts of all of the provided Event's
+	// Jobs.
+	DeleteEventArtifacts(ctx context.Context, event Event) error
+	// DeleteProjectArtifacts deletes all Artifacts of all Jobs of all Events
+	// belonging to the specified Project.
+	DeleteProjectArtifacts(ctx context.Context, projectID string) error
+}
@@ -0,0 +1,582 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/brigadecore/brigade/v2/apiserver/internal/meta"
+	metaTesting "github.com/brigadecore/brigade/v2/apiserver/internal/meta/testing" // nolint: lll
+	"github.com/stretchr/testify/require"
+)
+
+func TestArtifactMarshalJSON(t *testing.T) {
+	metaTesting.RequireAPIVersionAndType(t, Artifact{}, ArtifactKind)
+}
+
+func TestNewArtifactsService(t *testing.T) {
+	eventsStore := &mockEventsStore{}
+	artifactsStore := &mockArtifactsStore{}
+	config := ArtifactsServiceConfig{
+		MaxSize: 1024,
+	}
+	svc, ok := NewArtifactsService(
+		alwaysAuthorize,
+		alwaysProjectAuthorize,
+		eventsStore,
+		artifactsStore,
+		config,
+	).(*artifactsService)
+	require.True(t, ok)
+	require.NotNil(t, svc.authorize)
+	require.NotNil(t, svc.projectAuthorize)
+	require.Same(t, eventsStore, svc.eventsStore)
+	require.Same(t, artifactsStore, svc.artifactsStore)
+	require.Equal(t, config, svc.config)
+}
+
+func TestArtifactsServiceUpload(t *testing.T) {
+	const testJobName = "italian"
+	const testArtifactName = "report.xml"
+	testEvent := Event{
+		ObjectMeta: meta.ObjectMeta{
+			ID: "123456789",
+		},
+		Worker: Worker{
+			Jobs: []Job{
+				{
+					Name: testJobName,
+				},
+			},
+		},
+	}
+	testCases := []struct {
+		name         string
+		artifactName string
+		jobName      string
+		service      ArtifactsService
+		assertions   func(Artifact, error)
+	}{
+		{
+			name:         "unauthorized",
+			artifactName: testArtifactName,
+			jobName:      testJobName,
+			service: &artifactsService{
+				authorize: neverAuthorize,
+			},
+			assertions: func(_ Artifact, err error) {
+				require.Error(t, err)
+				require.IsType(t, &meta.ErrAuthorization{}, err)
+			},
+		},
+		{
+			name:         "invalid artifact name",
+			artifactName: "../../etc/passwd",
+			jobName:      testJobName,
+			service: &artifactsService{
+				authorize: alwaysAuthorize,
+			},
+			assertions: func(_ Artifact, err error) {
+				require.Error(t, err)
+				require.IsType(t, &meta.ErrBadRequest{}, err)
+				require.Contains(
+					t,
+					err.(*meta.ErrBadRequest).Reason,
+					"is invalid",
+				)
+			},
+		},
+		{
+			name:         "error getting event from store",
+			artifactName: testArtifactName,
+			jobName:      testJobName,
+			service: &artifactsService{
+				authorize: alwaysAuthorize,
+				eventsStore: &mockEventsStore{
+					GetFn: func(context.Context, string) (Event, error) {
+						return Event{}, errors.New("something went wrong")
+					},
+				},
+			},
+			assertions: func(_ Artifact, err error) {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), "something went wrong")
+				require.Contains(t, err.Error(), "error retrieving event")
+			},
+		},
+		{
+			name:         "job not found",
+			artifactName: testArtifactName,
+			jobName:      "french",
+			service: &artifactsService{
+				authorize: alwaysAuthorize,
+				eventsStore: &mockEventsStore{
+					GetFn: func(context.Context, string) (Event, error) {
+						return testEvent, nil
+					},
+				},
+			},
+			assertions: func(_ Artifact, err error) {
+				require.Error(t, err)
+				require.IsType(t, &meta.ErrNotFound{}, err)
+				require.Equal(t, JobKind, err.(*meta.ErrNotFound).Type)
+			},
+		},
+		{
+			name:         "artifact too large",
+			artifactName: testArtifactName,
+			jobName:      testJobName,
+			service: &artifactsService{
+				authorize: alwaysAuthorize,
+				eventsStore: &mockEventsStore{
+					GetFn: func(context.Context, string) (Event, error) {
+						return testEvent, nil
+					},
+				},
+				artifactsStore: &mockArtifactsStore{
+					PutFn: func(
+						_ context.Context,
+						_ Event,
+						_ string,
+						_ string,
+						content io.Reader,
+					) (Artifact, error) {
+						_, err := ioutil.ReadAll(content)
+						return Artifact{}, err
+					},
+				},
+				config: ArtifactsServiceConfig{
+					MaxSize: 4,
+				},
+			},
+			assertions: func(_ Artifact, err error) {
+				require.Error(t, err)
+				require.IsType(t, &meta.ErrBadRequest{}, err)
+				require.Contains(
+					t,
+					err.(*meta.ErrBadRequest).Reason,
+					"exceeds the maximum permissible size",
+				)
+			},
+		},
+		{
+			name:         "error storing artifact",
+			artifactName: testArtifactName,
+			jobName:      testJobName,
+			service: &artifactsService{
+				authorize: alwaysAuthorize,
+				eventsStore: &mockEventsStore{
+					GetFn: func(context.Context, string) (Event, error) {
+						return testEvent, nil
+					},
+				},
+				artifactsStore: &mockArtifactsStore{
+					PutFn: func(
+						context.Context,
+						Event,
+						string,
+						string,
+						io.Reader,
+					) (Artifact, error) {
+						return Artifact{}, errors.New("something went wrong")
+					},
+				},
+				config: ArtifactsServiceConfig{
+					MaxSize: 1024,
+				},
+			},
+			assertions: func(_ Artifact, err error) {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), "something went wrong")
+				require.Contains(t, err.Error(), "error storing event")
+			},
+		},
+		{
+			name:         "success",
+			artifactName: testArtifactName,
+			jobName:      testJobName,
+			service: &artifactsService{
+				authorize: alwaysAuthorize,
+				eventsStore: &mockEventsStore{
+					GetFn: func(context.Context, string) (Event, error) {
+						return testEvent, nil
+					},
+				},
+				artifactsStore: &mockArtifactsStore{
+					PutFn: func(
+						_ context.Context,
+						event Event,
+						jobName string,
+						artifactName string,
+						content io.Reader,
+					) (Artifact, error) {
+						require.Equal(t, testEvent.ID, event.ID)
+						require.Equal(t, testJobName, jobName)
+						require.Equal(t, testArtifactName, artifactName)
+						contentBytes, err := ioutil.ReadAll(content)
+						require.NoError(t, err)
+						return Artifact{
+							Name: artifactName,
+							Size: int64(len(contentBytes)),
+						}, nil
+					},
+				},
+				config: ArtifactsServiceConfig{
+					MaxSize: 1024,
+				},
+			},
+			assertions: func(artifact Artifact, err error) {
+				require.NoError(t, err)
+				require.Equal(t, testArtifactName, artifact.Name)
+				require.Equal(t, int64(len("<xml/>")), artifact.Size)
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.assertions(
+				testCase.service.Upload(
+					context.Background(),
+					testEvent.ID,
+					testCase.jobName,
+					testCase.artifactName,
+					strings.NewReader("<xml/>"),
+				),
+			)
+		})
+	}
+}
+
+func TestArtifactsServiceList(t *testing.T) {
+	const testJobName = "italian"
+	testEvent := Event{
+		ObjectMeta: meta.ObjectMeta{
+			ID: "123456789",
+		},
+		Worker: Worker{
+			Jobs: []Job{
+				{
+					Name: testJobName,
+				},
+			},
+		},
+	}
+	testCases := []struct {
+		name       string
+		service    ArtifactsService
+		assertions func(meta.List[Artifact], error)
+	}{
+		{
+			name: "error getting event from store",
+			service: &artifactsService{
+				eventsStore: &mockEventsStore{
+					GetFn: func(context.Context, string) (Event, error) {
+						return Event{}, errors.New("something went wrong")
+					},
+				},
+			},
+			assertions: func(_ meta.List[Artifact], err error) {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), "something went wrong")
+				require.Contains(t, err.Error(), "error retrieving event")
+			},
+		},
+		{
+			name: "unauthorized",
+			service: &artifactsService{
+				authorize:        neverAuthorize,
+				projectAuthorize: neverProjectAuthorize,
+				eventsStore: &mockEventsStore{
+					GetFn: func(context.Context, string) (Event, error) {
+						return testEvent, nil
+					},
+				},
+			},
+			assertions: func(_ meta.List[Artifact], err error) {
+				require.Error(t, err)
+				require.IsType(t, &meta.ErrAuthorization{}, err)
+			},
+		},
+		{
+			name: "error listing artifacts",
+			service: &artifactsService{
+				projectAuthorize: alwaysProjectAuthorize,
+				eventsStore: &mockEventsStore{
+					GetFn: func(context.Context, string) (Event, error) {
+						return testEvent, nil
+					},
+				},
+				artifactsStore: &mockArtifactsStore{
+					ListFn: func(
+						context.Context,
+						Event,
+						string,
+					) (meta.List[Artifact], error) {
+						return meta.List[Artifact]{}, errors.New("something went wrong")
+					},
+				},
+			},
+			assertions: func(_ meta.List[Artifact], err error) {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), "something went wrong")
+				require.Contains(t, err.Error(), "error retrieving event")
+			},
+		},
+		{
+			name: "success with worker principal",
+			service: &artifactsService{
+				authorize:        alwaysAuthorize,
+				projectAuthorize: neverProjectAuthorize,
+				eventsStore: &mockEventsStore{
+					GetFn: func(context.Context, string) (Event, error) {
+						return testEvent, nil
+					},
+				},
+				artifactsStore: &mockArtifactsStore{
+					ListFn: func(
+						_ context.Context,
+						_ Event,
+						jobName string,
+					) (meta.List[Artifact], error) {
+						require.Equal(t, testJobName, jobName)
+						return meta.List[Artifact]{
+							Items: []Artifact{
+								{
+									Name: "report.xml",
+								},
+							},
+						}, nil
+					},
+				},
+			},
+			assertions: func(artifacts meta.List[Artifact], err error) {
+				require.NoError(t, err)
+				require.Len(t, artifacts.Items, 1)
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.assertions(
+				testCase.service.List(context.Background(), testEvent.ID, testJobName),
+			)
+		})
+	}
+}
+
+func TestArtifactsServiceDownload(t *testing.T) {
+	const testJobName = "italian"
+	const testArtifactName = "report.xml"
+	testEvent := Event{
+		ObjectMeta: meta.ObjectMeta{
+			ID: "123456789",
+		},
+		Worker: Worker{
+			Jobs: []Job{
+				{
+					Name: testJobName,
+				},
+			},
+		},
+	}
+	testCases := []struct {
+		name         string
+		artifactName string
+		service      ArtifactsService
+		assertions   func(Artifact, io.ReadCloser, error)
+	}{
+		{
+			name:         "invalid artifact name",
+			artifactName: ".upload-report.xml-123456",
+			service:      &artifactsService{},
+			assertions: func(_ Artifact, _ io.ReadCloser, err error) {
+				require.Error(t, err)
+				require.IsType(t, &meta.ErrBadRequest{}, err)
+				require.Contains(
+					t,
+					err.(*meta.ErrBadRequest).Reason,
+					"is invalid",
+				)
+			},
+		},
+		{
+			name:         "unauthorized",
+			artifactName: testArtifactName,
+			service: &artifactsService{
+				authorize:        neverAuthorize,
+				projectAuthorize: neverProjectAuthorize,
+				eventsStore: &mockEventsStore{
+					GetFn: func(context.Context, string) (Event, error) {
+						return testEvent, nil
+					},
+				},
+			},
+			assertions: func(_ Artifact, _ io.ReadCloser, err error) {
+				require.Error(t, err)
+				require.IsType(t, &meta.ErrAuthorization{}, err)
+			},
+		},
+		{
+			name:         "artifact not found",
+			artifactName: testArtifactName,
+			service: &artifactsService{
+				projectAuthorize: alwaysProjectAuthorize,
+				eventsStore: &mockEventsStore{
+					GetFn: func(context.Context, string) (Event, error) {
+						return testEvent, nil
+					},
+				},
+				artifactsStore: &mockArtifactsStore{
+					GetFn: func(
+						context.Context,
+						Event,
+						string,
+						string,
+					) (Artifact, io.ReadCloser, error) {
+						return Artifact{}, nil, &meta.ErrNotFound{
+							Type: ArtifactKind,
+							ID:   testArtifactName,
+						}
+					},
+				},
+			},
+			assertions: func(_ Artifact, _ io.ReadCloser, err error) {
+				require.Error(t, err)
+				var notFoundErr *meta.ErrNotFound
+				require.ErrorAs(t, err, &notFoundErr)
+				require.Equal(t, ArtifactKind, notFoundErr.Type)
+			},
+		},
+		{
+			name:         "success",
+			artifactName: testArtifactName,
+			service: &artifactsService{
+				projectAuthorize: alwaysProjectAuthorize,
+				eventsStore: &mockEventsStore{
+					GetFn: func(context.Context, string) (Event, error) {
+						return testEvent, nil
+					},
+				},
+				artifactsStore: &mockArtifactsStore{
+					GetFn: func(
+						_ context.Context,
+						_ Event,
+						jobName string,
+						artifactName string,
+					) (Artifact, io.ReadCloser, error) {
+						require.Equal(t, testJobName, jobName)
+						require.Equal(t, testArtifactName, artifactName)
+						return Artifact{
+								Name: artifactName,
+								Size: 6,
+							},
+							ioutil.NopCloser(strings.NewReader("<xml/>")),
+							nil
+					},
+				},
+			},
+			assertions: func(artifact Artifact, content io.ReadCloser, err error) {
+				require.NoError(t, err)
+				require.Equal(t, testArtifactName, artifact.Name)
+				contentBytes, err := ioutil.ReadAll(content)
+				require.NoError(t, err)
+				require.Equal(t, "<xml/>", string(contentBytes))
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.assertions(
+				testCase.service.Download(
+					context.Background(),
+					testEvent.ID,
+					testJobName,
+					testCase.artifactName,
+				),
+			)
+		})
+	}
+}
+
+func TestArtifactReader(t *testing.T) {
+	reader := &artifactReader{
+		reader:    strings.NewReader("abcd"),
+		remaining: 4,
+	}
+	contentBytes, err := ioutil.ReadAll(reader)
+	require.NoError(t, err)
+	require.Equal(t, "abcd", string(contentBytes))
+	reader = &artifactReader{
+		reader:    strings.NewReader("abcde"),
+		remaining: 4,
+	}
+	_, err = ioutil.ReadAll(reader)
+	require.Equal(t, errArtifactTooLarge, err)
+}
+
+type mockArtifactsStore struct {
+	PutFn func(
+		ctx context.Context,
+		event Event,
+		jobName string,
+		artifactName string,
+		content io.Reader,
+	) (Artifact, error)
+	ListFn func(
+		ctx context.Context,
+		event Event,
+		jobName string,
+	) (meta.List[Artifact], error)
+	GetFn func(
+		ctx context.Context,
+		event Event,
+		jobName string,
+		artifactName string,
+	) (Artifact, io.ReadCloser, error)
+	DeleteEventArtifactsFn   func(ctx context.Context, event Event) error
+	DeleteProjectArtifactsFn func(ctx context.Context, projectID string) error
+}
+
+func (m *mockArtifactsStore) Put(
+	ctx context.Context,
+	event Event,
+	jobName string,
+	artifactName string,
+	content io.Reader,
+) (Artifact, error) {
+	return m.PutFn(ctx, event, jobName, artifactName, content)
+}
+
+func (m *mockArtifactsStore) List(
+	ctx context.Context,
+	event Event,
+	jobName string,
+) (meta.List[Artifact], error) {
+	return m.ListFn(ctx, event, jobName)
+}
+
+func (m *mockArtifactsStore) Get(
+	ctx context.Context,
+	event Event,
+	jobName string,
+	artifactName string,
+) (Artifact, io.ReadCloser, error) {
+	return m.GetFn(ctx, event, jobName, artifactName)
+}
+
+func (m *mockArtifactsStore) DeleteEventArtifacts(
+	ctx context.Context,
+	event Event,
+) error {
+	return m.DeleteEventArtifactsFn(ctx, event)
+}
+
+func (m *mockArtifactsStore) DeleteProjectArtifacts(
+	ctx context.Context,
+	projectID string,
+) error {
+	return m.DeleteProjectArtifactsFn(ctx, projectID)
+}
@@ -0,0 +1,135 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/brigadecore/brigade/v2/apiserver/internal/meta"
+	"github.com/pkg/errors"
+)
+
+// parseConcurrencyGroup parses the provided concurrency group template.
+func parseConcurrencyGroup(text string) (*template.Template, error) {
+	return template.New("concurrencyGroup").
+		Option("missingkey=zero").
+		Parse(text)
+}
+
+// validateConcurrencyGroup returns a *meta.ErrBadRequest if the provided
+// WorkerSpec's concurrency group template cannot be parsed or refers to Event
+// fields that don't exist.
+func validateConcurrencyGroup(workerSpec WorkerSpec) error {
+	if _, err :=
+		evaluateConcurrencyGroup(workerSpec.ConcurrencyGroup, Event{}); err != nil {
+		return &meta.ErrBadRequest{
+			Reason: "Invalid concurrency group.",
+			Details: []string{
+				fmt.Sprintf(
+					"concurrency group template %q is invalid: %s",
+					workerSpec.ConcurrencyGroup,
+					err,
+				),
+			},
+		}
+	}
+	return nil
+}
+
+// evaluateConcurrencyGroup evaluates the provided concurrency group template
+// against the provided Event. Git details are assumed to be present, but empty,
+// if the Event doesn't specify any, so templates like {{.Git.Ref}} evaluate to
+// an empty string instead of failing. An empty result means the Event doesn't
+// belong to any concurrency group.
+func evaluateConcurrencyGroup(text string, event Event) (string, error) {
+	if text == "" {
+		return "", nil
+	}
+	tmpl, err := parseConcurrencyGroup(text)
+	if err != nil {
+		return "", errors.Wrapf(
+			err,
+			"error parsing concurrency group template %q",
+			text,
+		)
+	}
+	if event.Git == nil {
+		event.Git = &GitDetails{}
+	}
+	buf := &bytes.Buffer{}
+	if err = tmpl.Execute(buf, event); err != nil {
+		return "", errors.Wrapf(
+			err,
+			"error evaluating concurrency group template %q",
+			text,
+		)
+	}
+	return strings.TrimSpace(buf.String()), nil
+}
+
+// supersede cancels (or aborts) all Events in the provided Event's concurrency
+// group that are older than it and whose Workers have not yet reached a
+// terminal phase. Each such Event is labeled with the ID of the Event that
+// superseded it.
+func (e *eventsService) supersede(
+	ctx context.Context,
+	project Project,
+	event Event,
+) error {
+	if event.ConcurrencyGroup == "" {
+		return nil
+	}
+	selector := EventsSelector{
+		ProjectID: project.ID,
+		WorkerPhases: []WorkerPhase{
+			WorkerPhasePending,
+			WorkerPhaseStarting,
+			WorkerPhaseRunning,
+		},
+		ConcurrencyGroup: event.ConcurrencyGroup,
+	}
+	opts := meta.ListOptions{Limit: 100}
+	for {
+		events, err := e.eventsStore.List(ctx, selector, opts)
+		if err != nil {
+			return errors.Wrapf(
+				err,
+				"error retrieving events in concurrency group %q from store",
+				event.ConcurrencyGroup,
+			)
+		}
+		for _, older := range events.Items {
+			if older.ID == event.ID ||
+				older.Created == nil ||
+				event.Created == nil ||
+				!older.Created.Before(*event.Created) {
+				continue
+			}
+			if err = e.cancel(ctx, project, older); err != nil {
+				// The Event may have reached a terminal phase in the meantime.
+				if _, ok := errors.Cause(err).(*meta.ErrConflict); ok {
+					continue
+				}
+				return err
+			}
+			labels := map[string]string{}
+			for k, v := range older.Labels {
+				labels[k] = v
+			}
+			labels[SupersededByLabelKey] = event.ID
+			if err = e.eventsStore.UpdateLabels(ctx, older.ID, labels); err != nil {
+				return errors.Wrapf(
+					err,
+					"error updating labels of event %q in store",
+					older.ID,
+				)
+			}
+		}
+		if events.Continue == "" {
+			return nil
+		}
+		opts.Continue = events.Continue
+	}
+}
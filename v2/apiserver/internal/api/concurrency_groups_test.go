@@ -0,0 +1,363 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/brigadecore/brigade/v2/apiserver/internal/meta"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateConcurrencyGroup(t *testing.T) {
+	testCases := []struct {
+		name             string
+		concurrencyGroup string
+		assertions       func(error)
+	}{
+		{
+			name: "no concurrency group",
+			assertions: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name:             "unparseable template",
+			concurrencyGroup: "{{.Git.Ref",
+			assertions: func(err error) {
+				require.Error(t, err)
+				require.IsType(t, &meta.ErrBadRequest{}, err)
+				require.Equal(
+					t,
+					"Invalid concurrency group.",
+					err.(*meta.ErrBadRequest).Reason,
+				)
+			},
+		},
+		{
+			name:             "template refers to non-existent field",
+			concurrencyGroup: "{{.Branch}}",
+			assertions: func(err error) {
+				require.Error(t, err)
+				require.IsType(t, &meta.ErrBadRequest{}, err)
+			},
+		},
+		{
+			name:             "valid template",
+			concurrencyGroup: "{{.Source}}:{{.Git.Ref}}",
+			assertions: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.assertions(
+				validateConcurrencyGroup(
+					WorkerSpec{
+						ConcurrencyGroup: testCase.concurrencyGroup,
+					},
+				),
+			)
+		})
+	}
+}
+
+func TestEvaluateConcurrencyGroup(t *testing.T) {
+	testCases := []struct {
+		name             string
+		concurrencyGroup string
+		event            Event
+		expected         string
+	}{
+		{
+			name:  "no concurrency group",
+			event: Event{Source: "foo"},
+		},
+		{
+			name:             "git details",
+			concurrencyGroup: "{{.Git.Ref}}",
+			event: Event{
+				Git: &GitDetails{
+					Ref: "refs/heads/main",
+				},
+			},
+			expected: "refs/heads/main",
+		},
+		{
+			name:             "no git details",
+			concurrencyGroup: "{{.Git.Ref}}",
+			event:            Event{},
+		},
+		{
+			name:             "missing label",
+			concurrencyGroup: `{{index .Labels "branch"}}`,
+			event:            Event{},
+		},
+		{
+			name:             "several fields",
+			concurrencyGroup: `{{.Type}}-{{index .Labels "branch"}}`,
+			event: Event{
+				Type: "push",
+				Labels: map[string]string{
+					"branch": "main",
+				},
+			},
+			expected: "push-main",
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			group, err :=
+				evaluateConcurrencyGroup(testCase.concurrencyGroup, testCase.event)
+			require.NoError(t, err)
+			require.Equal(t, testCase.expected, group)
+		})
+	}
+}
+
+func TestEventsServiceSupersede(t *testing.T) {
+	const testConcurrencyGroup = "refs/heads/main"
+	now := time.Now().UTC()
+	earlier := now.Add(-time.Minute)
+	later := now.Add(time.Minute)
+	testProject := Project{
+		ObjectMeta: meta.ObjectMeta{
+			ID: "blue-book",
+		},
+	}
+	testEvent := Event{
+		ObjectMeta: meta.ObjectMeta{
+			ID:      "tunguska",
+			Created: &now,
+		},
+		ConcurrencyGroup: testConcurrencyGroup,
+	}
+	testCases := []struct {
+		name       string
+		event      Event
+		service    *eventsService
+		assertions func(error)
+	}{
+		{
+			name: "event doesn't belong to a concurrency group",
+			event: Event{
+				ObjectMeta: meta.ObjectMeta{
+					ID: "tunguska",
+				},
+			},
+			service: &eventsService{},
+			assertions: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name:  "error listing events",
+			event: testEvent,
+			service: &eventsService{
+				eventsStore: &mockEventsStore{
+					ListFn: func(
+						context.Context,
+						EventsSelector,
+						meta.ListOptions,
+					) (meta.List[Event], error) {
+						return meta.List[Event]{}, errors.New("something went wrong")
+					},
+				},
+			},
+			assertions: func(err error) {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), "something went wrong")
+				require.Contains(t, err.Error(), "error retrieving events")
+			},
+		},
+		{
+			name:  "error updating labels",
+			event: testEvent,
+			service: &eventsService{
+				eventsStore: &mockEventsStore{
+					ListFn: func(
+						context.Context,
+						EventsSelector,
+						meta.ListOptions,
+					) (meta.List[Event], error) {
+						return meta.List[Event]{
+							Items: []Event{
+								{
+									ObjectMeta: meta.ObjectMeta{
+										ID:      "roswell",
+										Created: &earlier,
+									},
+								},
+							},
+						}, nil
+					},
+					CancelFn: func(context.Context, string) error {
+						return nil
+					},
+					GetFn: func(context.Context, string) (Event, error) {
+						return Event{}, nil
+					},
+					UpdateLabelsFn: func(
+						context.Context,
+						string,
+						map[string]string,
+					) error {
+						return errors.New("something went wrong")
+					},
+				},
+				substrate: &mockSubstrate{
+					DeleteWorkerAndJobsFn: func(context.Context, Project, Event) error {
+						return nil
+					},
+				},
+			},
+			assertions: func(err error) {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), "something went wrong")
+				require.Contains(t, err.Error(), "error updating labels")
+			},
+		},
+		{
+			name:  "error canceling event",
+			event: testEvent,
+			service: &eventsService{
+				eventsStore: &mockEventsStore{
+					ListFn: func(
+						context.Context,
+						EventsSelector,
+						meta.ListOptions,
+					) (meta.List[Event], error) {
+						return meta.List[Event]{
+							Items: []Event{
+								{
+									ObjectMeta: meta.ObjectMeta{
+										ID:      "roswell",
+										Created: &earlier,
+									},
+								},
+							},
+						}, nil
+					},
+					CancelFn: func(context.Context, string) error {
+						return errors.New("something went wrong")
+					},
+				},
+			},
+			assertions: func(err error) {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), "something went wrong")
+				require.Contains(t, err.Error(), "error canceling event")
+			},
+		},
+		{
+			name:  "success",
+			event: testEvent,
+			service: &eventsService{
+				eventsStore: &mockEventsStore{
+					ListFn: func(
+						_ context.Context,
+						selector EventsSelector,
+						opts meta.ListOptions,
+					) (meta.List[Event], error) {
+						require.Equal(t, testProject.ID, selector.ProjectID)
+						require.Equal(
+							t,
+							testConcurrencyGroup,
+							selector.ConcurrencyGroup,
+						)
+						require.Equal(
+							t,
+							[]WorkerPhase{
+								WorkerPhasePending,
+								WorkerPhaseStarting,
+								WorkerPhaseRunning,
+							},
+							selector.WorkerPhases,
+						)
+						if opts.Continue == "" {
+							return meta.List[Event]{
+								Items: []Event{
+									{ // Newer; should be left alone
+										ObjectMeta: meta.ObjectMeta{
+											ID:      "area-51",
+											Created: &later,
+										},
+									},
+									testEvent, // Itself; should be left alone
+								},
+								ListMeta: meta.ListMeta{
+									Continue: "more",
+								},
+							}, nil
+						}
+						return meta.List[Event]{
+							Items: []Event{
+								{
+									ObjectMeta: meta.ObjectMeta{
+										ID:      "roswell",
+										Created: &earlier,
+									},
+									Labels: map[string]string{
+										"foo": "bar",
+									},
+								},
+								{ // Reached a terminal phase in the meantime
+									ObjectMeta: meta.ObjectMeta{
+										ID:      "rendlesham",
+										Created: &earlier,
+									},
+								},
+							},
+						}, nil
+					},
+					UpdateLabelsFn: func(
+						_ context.Context,
+						id string,
+						labels map[string]string,
+					) error {
+						require.Equal(t, "roswell", id)
+						require.Equal(t, testEvent.ID, labels[SupersededByLabelKey])
+						require.Equal(t, "bar", labels["foo"])
+						return nil
+					},
+					CancelFn: func(_ context.Context, id string) error {
+						if id == "rendlesham" {
+							return &meta.ErrConflict{}
+						}
+						require.Equal(t, "roswell", id)
+						return nil
+					},
+					GetFn: func(context.Context, string) (Event, error) {
+						return Event{}, nil
+					},
+				},
+				substrate: &mockSubstrate{
+					DeleteWorkerAndJobsFn: func(
+						_ context.Context,
+						_ Project,
+						event Event,
+					) error {
+						require.Equal(t, "roswell", event.ID)
+						return nil
+					},
+				},
+			},
+			assertions: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.assertions(
+				testCase.service.supersede(
+					context.Background(),
+					testProject,
+					testCase.event,
+				),
+			)
+		})
+	}
+}
@@ -24,6 +24,10 @@ type ContainerSpec struct {
 	// Environment is a map of key/value pairs that specify environment variables
 	// to be set within the OCI container.
 	Environment map[string]string `json:"environment,omitempty" bson:"environment,omitempty"` // nolint: lll
+	// Resources optionally specifies the compute resources the OCI container
+	// requires and the most it may consume. Where these are unspecified,
+	// Project-level defaults apply.
+	Resources *ContainerResources `json:"resources,omitempty" bson:"resources,omitempty"` // nolint: lll
 }
 
 func (cs ContainerSpec) EqualTo(cs2 ContainerSpec) bool {
@@ -62,3 +66,17 @@ func (cs ContainerSpec) EqualTo(cs2 ContainerSpec) bool {
 
 	return reflect.DeepEqual(cs, cs2)
 }
+
+// ContainerStatus represents the status of a single OCI container belonging to
+// a Worker or Job.
+type ContainerStatus struct {
+	// Name is the name of the container.
+	Name string `json:"name" bson:"name"`
+	// ExitCode is the exit code of the container, if it has exited.
+	ExitCode *int32 `json:"exitCode,omitempty" bson:"exitCode,omitempty"`
+	// Reason is a brief, machine-readable explanation of the container's current
+	// state, e.g. "OOMKilled" or "ImagePullBackOff".
+	Reason string `json:"reason,omitempty" bson:"reason,omitempty"`
+	// Message is a human-readable elaboration on Reason.
+	Message string `json:"message,omitempty" bson:"message,omitempty"`
+}
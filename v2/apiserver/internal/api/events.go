@@ -25,6 +25,10 @@ const (
 	// on any retried event
 	RetryLabelKey = "brigade.sh/retryOf"
 
+	// SupersededByLabelKey is the label key used for tracing the event that
+	// superseded an event in the same concurrency group
+	SupersededByLabelKey = "brigade.sh/supersededBy"
+
 	defaultWorkspaceSize = "10Gi"
 )
 
@@ -90,6 +94,11 @@ type Event struct {
 	ShortTitle string `json:"shortTitle,omitempty" bson:"shortTitle,omitempty"`
 	// LongTitle is an optional, detailed title for the Event.
 	LongTitle string `json:"longTitle,omitempty" bson:"longTitle,omitempty"`
+	// Priority optionally specifies the relative urgency of the Event, from zero
+	// (the default) through nine. When Workers (and their Jobs) are waiting for
+	// capacity, those belonging to higher priority Events are scheduled first.
+	// The priority is capped by the MaxEventPriority of each subscribed Project.
+	Priority int `json:"priority,omitempty" bson:"priority,omitempty"`
 	// Git contains git-specific Event details. These can be used to override
 	// similar details defined at the Project level. This is useful for scenarios
 	// wherein an Event may need to convey an alternative source, branch, etc.
@@ -110,6 +119,19 @@ type Event struct {
 	Summary string `json:"summary,omitempty" bson:"summary,omitempty"`
 	// Worker contains details of the Worker assigned to handle the Event.
 	Worker Worker `json:"worker" bson:"worker"`
+	// IdempotencyKey is the idempotency key, if any, that was specified when the
+	// Event was created. This is set by the system and cannot be set directly by
+	// clients. See EventCreateOptions.
+	IdempotencyKey string `json:"idempotencyKey,omitempty" bson:"idempotencyKey,omitempty"` // nolint: lll
+	// ConcurrencyGroup is the concurrency group, if any, to which the Event
+	// belongs. This is set by the system, by evaluating the ConcurrencyGroup
+	// template of the Project's WorkerSpec, and cannot be set directly by
+	// clients.
+	ConcurrencyGroup string `json:"concurrencyGroup,omitempty" bson:"concurrencyGroup,omitempty"` // nolint: lll
+	// Pinned indicates when the Event was pinned. Pinned Events are exempt from
+	// deletion by retention policies. If this field's value is nil, the Event is
+	// not pinned.
+	Pinned *time.Time `json:"pinned,omitempty" bson:"pinned,omitempty"`
 }
 
 // MarshalJSON amends Event instances with type metadata.
@@ -161,6 +183,16 @@ type GitDetails struct {
 	Ref string `json:"ref,omitempty" bson:"ref,omitempty"`
 }
 
+// EventCreateOptions represents useful, optional settings for creating Events.
+type EventCreateOptions struct {
+	// IdempotencyKey is an optional, client-generated key that uniquely
+	// identifies a request to create Events from a given source. If a request
+	// bearing the same key for the same source was already accepted within the
+	// configured idempotency window, the Events created by that request are
+	// returned and no new Events are created.
+	IdempotencyKey string
+}
+
 // EventsSelector represents useful filter criteria when selecting multiple
 // Events for API group operations like list, cancel, or delete.
 type EventsSelector struct {
@@ -185,6 +217,20 @@ type EventsSelector struct {
 	// Labels specifies that only Events labeled with these key/value pairs should
 	// be selected.
 	Labels map[string]string
+	// ConcurrencyGroup specifies that only Events belonging to the indicated
+	// concurrency group should be selected.
+	ConcurrencyGroup string
+	// CreatedBefore specifies that only Events created before the indicated time
+	// should be selected.
+	CreatedBefore *time.Time
+	// CreatedBeforeTiebreakerID amends CreatedBefore so that Events created at
+	// exactly the indicated time are also selected if their IDs are greater than
+	// or equal to the indicated ID. Events are ordered newest first, with ties
+	// broken by ID, so together these select a given Event and every Event
+	// ordered after it.
+	CreatedBeforeTiebreakerID string
+	// ExcludePinned specifies that pinned Events should not be selected.
+	ExcludePinned bool
 }
 
 // CancelManyEventsResult represents a summary of a mass Event cancellation
@@ -235,13 +281,77 @@ func (d DeleteManyEventsResult) MarshalJSON() ([]byte, error) {
 	)
 }
 
+// EventMatchResult represents the outcome of evaluating which Projects would
+// receive a prospective Event.
+type EventMatchResult struct {
+	// Subscribers enumerates the IDs of all Projects that would receive the
+	// Event.
+	Subscribers []string `json:"subscribers"`
+	// NonSubscribers describes, for every Project that would NOT receive the
+	// Event, why none of its EventSubscriptions matched the Event.
+	NonSubscribers []ProjectMatchFailure `json:"nonSubscribers"`
+}
+
+// MarshalJSON amends EventMatchResult instances with type metadata.
+func (e EventMatchResult) MarshalJSON() ([]byte, error) {
+	type Alias EventMatchResult
+	return json.Marshal(
+		struct {
+			meta.TypeMeta `json:",inline"`
+			Alias         `json:",inline"`
+		}{
+			TypeMeta: meta.TypeMeta{
+				APIVersion: meta.APIVersion,
+				Kind:       "EventMatchResult",
+			},
+			Alias: (Alias)(e),
+		},
+	)
+}
+
+// ProjectMatchFailure describes why a Project would not receive an Event.
+type ProjectMatchFailure struct {
+	// ProjectID is the ID of the Project that would not receive the Event.
+	ProjectID string `json:"projectID"`
+	// Subscriptions describes, for each of the Project's EventSubscriptions, the
+	// criteria the Event failed to satisfy. If the Project has no
+	// EventSubscriptions, this is empty.
+	Subscriptions []SubscriptionMatchFailure `json:"subscriptions"`
+}
+
+// SubscriptionMatchFailure describes why an EventSubscription did not match an
+// Event.
+type SubscriptionMatchFailure struct {
+	// Index is the position of the EventSubscription among the Project's
+	// EventSubscriptions.
+	Index int `json:"index"`
+	// Source is the EventSubscription's Source. It is included to help identify
+	// the EventSubscription.
+	Source string `json:"source"`
+	// Mismatches describes each of the EventSubscription's criteria that the
+	// Event failed to satisfy.
+	Mismatches []SubscriptionCriterionMismatch `json:"mismatches"`
+}
+
+// EventsServiceConfig encapsulates configuration options for the
+// EventsService.
+type EventsServiceConfig struct {
+	// IdempotencyWindow specifies how long an idempotency key remains in effect
+	// after the Events it was used to create were created. A repeat request
+	// bearing the same key within this window does not create new Events.
+	IdempotencyWindow time.Duration
+}
+
 // EventsService is the specialized interface for managing Events. It's
 // decoupled from underlying technology choices (e.g. data store, message bus,
 // etc.) to keep business logic reusable and consistent while the underlying
 // tech stack remains free to change.
 type EventsService interface {
-	// Create creates a new Event.
-	Create(context.Context, Event) (
+	// Create creates a new Event. If the EventCreateOptions specify an
+	// idempotency key that was already used to create Events from the same
+	// source within the configured idempotency window, implementations MUST
+	// return the previously created Events instead of creating new ones.
+	Create(context.Context, Event, EventCreateOptions) (
 		meta.List[Event],
 		error,
 	)
@@ -275,6 +385,13 @@ type EventsService interface {
 	// reached a terminal state. If the specified Event's Worker has already
 	// reached a terminal state, implementations MUST return a *meta.ErrConflict.
 	Cancel(context.Context, string) error
+	// Pin exempts a single Event, specified by its identifier, from deletion by
+	// retention policies. If no such event is found, implementations MUST return
+	// a *meta.ErrNotFound error.
+	Pin(context.Context, string) error
+	// Unpin reverses the effect of Pin. If no such event is found,
+	// implementations MUST return a *meta.ErrNotFound error.
+	Unpin(context.Context, string) error
 	// CancelMany cancels multiple Events specified by the EventsSelector
 	// parameter. Implementations MUST only cancel events whose Workers have not
 	// already reached a terminal state.
@@ -297,6 +414,11 @@ type EventsService interface {
 	// are inherited and the job not re-scheduled, for example when a job has
 	// succeeded and does not make use of a shared workspace.
 	Retry(context.Context, string) (Event, error)
+	// Match determines which Projects would receive the provided Event and, for
+	// each Project that would not, which EventSubscription criteria the Event
+	// failed to satisfy. Implementations MUST NOT persist the Event or schedule
+	// any Workers.
+	Match(context.Context, Event) (EventMatchResult, error)
 }
 
 type eventsService struct {
@@ -305,7 +427,10 @@ type eventsService struct {
 	projectsStore       ProjectsStore
 	eventsStore         EventsStore
 	logsStore           CoolLogsStore
+	artifactsStore      ArtifactsStore
 	substrate           Substrate
+	notifier            Notifier
+	config              EventsServiceConfig
 	createSingleEventFn func(context.Context, Project, Event) (Event, error)
 }
 
@@ -316,7 +441,10 @@ func NewEventsService(
 	projectsStore ProjectsStore,
 	eventsStore EventsStore,
 	logsStore CoolLogsStore,
+	artifactsStore ArtifactsStore,
 	substrate Substrate,
+	notifier Notifier,
+	config EventsServiceConfig,
 ) EventsService {
 	e := &eventsService{
 		authorize:        authorizeFn,
@@ -324,7 +452,10 @@ func NewEventsService(
 		projectsStore:    projectsStore,
 		eventsStore:      eventsStore,
 		logsStore:        logsStore,
+		artifactsStore:   artifactsStore,
 		substrate:        substrate,
+		notifier:         notifier,
+		config:           config,
 	}
 	e.createSingleEventFn = e.createSingleEvent
 	return e
@@ -333,9 +464,84 @@ func NewEventsService(
 func (e *eventsService) Create(
 	ctx context.Context,
 	event Event,
+	opts EventCreateOptions,
 ) (meta.List[Event], error) {
 	events := meta.List[Event]{}
 
+	if err := e.authorizeCreate(ctx, event); err != nil {
+		return events, err
+	}
+
+	// Clients cannot set the idempotency key directly-- only by way of options
+	event.IdempotencyKey = opts.IdempotencyKey
+	// Events previously created with the same idempotency key, indexed by
+	// Project. A prior request bearing the key may have failed partway through,
+	// so these need not cover all subscribers. Whatever is missing is created
+	// below.
+	existing := map[string]Event{}
+	if event.IdempotencyKey != "" {
+		var err error
+		if events, existing, err =
+			e.getIdempotentEvents(ctx, event); err != nil {
+			return events, err
+		}
+	}
+
+	now := time.Now().UTC()
+	event.Created = &now
+
+	candidates, err := e.projectsStore.ListSubscribers(ctx, event)
+	if err != nil {
+		return events, errors.Wrap(
+			err,
+			"error retrieving subscribed projects from store",
+		)
+	}
+
+	// The store only narrows the field of candidates. Whether each candidate is
+	// actually subscribed is determined here.
+	subscribers := []Project{}
+	for _, project := range candidates.Items {
+		if project.IsSubscribed(event) {
+			subscribers = append(subscribers, project)
+		}
+	}
+
+	// Iterate over all subscribed projects and create a discrete event for each
+	// that doesn't already have one.
+	for _, project := range subscribers {
+		if _, ok := existing[project.ID]; ok {
+			continue
+		}
+		event.ProjectID = project.ID
+		evt, err := e.createSingleEventFn(ctx, project, event)
+		if err != nil {
+			// If we lost a race with a concurrent request bearing the same
+			// idempotency key, use the Event created by that request instead.
+			if _, ok := errors.Cause(err).(*meta.ErrConflict); ok &&
+				event.IdempotencyKey != "" {
+				_, raced, getErr := e.getIdempotentEvents(ctx, event)
+				if getErr != nil {
+					return events, getErr
+				}
+				if evt, ok = raced[project.ID]; ok {
+					events.Items = append(events.Items, evt)
+					continue
+				}
+			}
+			return events, err
+		}
+		events.Items = append(events.Items, evt)
+	}
+	return events, nil
+}
+
+// authorizeCreate returns an error if the principal associated with the
+// provided context is not permitted to create the provided Event.
+func (e *eventsService) authorizeCreate(
+	ctx context.Context,
+	event Event,
+) error {
 	if event.ProjectID == "" {
 		// This event doesn't reference a discrete project and is instead going to
 		// be matched to all subscribing projects, so the only access requirement is
@@ -347,7 +553,7 @@ func (e *eventsService) Create(
 			RoleEventCreator,
 			event.Source,
 		); err != nil {
-			return events, err
+			return err
 		}
 	} else {
 		// This event references a discrete project, so the access requirement is
@@ -366,34 +572,60 @@ func (e *eventsService) Create(
 				RoleEventCreator,
 				event.Source,
 			); err != nil {
-				return events, err
+				return err
 			}
 		}
 	}
 
-	now := time.Now().UTC()
-	event.Created = &now
+	return nil
+}
 
-	subscribers, err := e.projectsStore.ListSubscribers(ctx, event)
+// getIdempotentEvents retrieves Events previously created from the same source
+// as the provided Event and bearing the same idempotency key, both as a list
+// and indexed by Project. If such Events were found, but were created outside
+// the idempotency window, the key is released so that it may be re-used and no
+// Events are returned.
+func (e *eventsService) getIdempotentEvents(
+	ctx context.Context,
+	event Event,
+) (meta.List[Event], map[string]Event, error) {
+	byProject := map[string]Event{}
+	events, err := e.eventsStore.ListByIdempotencyKey(
+		ctx,
+		event.Source,
+		event.IdempotencyKey,
+	)
 	if err != nil {
-		return events, errors.Wrap(
+		return meta.List[Event]{}, byProject, errors.Wrapf(
 			err,
-			"error retrieving subscribed projects from store",
+			"error retrieving events with idempotency key %q from store",
+			event.IdempotencyKey,
 		)
 	}
-
-	// If we get to here, no project ID is specified, so we iterate over all
-	// subscribed projects in the list and create a discrete event for each.
-	events.Items = make([]Event, subscribers.Len())
-	for i, project := range subscribers.Items {
-		event.ProjectID = project.ID
-		evt, err := e.createSingleEventFn(ctx, project, event)
-		if err != nil {
-			return events, err
+	if events.Len() == 0 {
+		return events, byProject, nil
+	}
+	windowStart := time.Now().UTC().Add(-e.config.IdempotencyWindow)
+	for _, evt := range events.Items {
+		if evt.Created != nil && evt.Created.After(windowStart) {
+			for _, evt := range events.Items {
+				byProject[evt.ProjectID] = evt
+			}
+			return events, byProject, nil
 		}
-		events.Items[i] = evt
 	}
-	return events, nil
+	if err = e.eventsStore.ReleaseIdempotencyKey(
+		ctx,
+		event.Source,
+		event.IdempotencyKey,
+	); err != nil {
+		return meta.List[Event]{}, byProject, errors.Wrapf(
+			err,
+			"error releasing expired idempotency key %q",
+			event.IdempotencyKey,
+		)
+	}
+	return meta.List[Event]{}, byProject, nil
 }
 
 func (e *eventsService) createSingleEvent(
@@ -404,6 +636,14 @@ func (e *eventsService) createSingleEvent(
 
 	event.ID = uuid.NewV4().String()
 
+	// The Project decides how urgent its Events are allowed to be
+	if event.Priority > project.Spec.MaxEventPriority {
+		event.Priority = project.Spec.MaxEventPriority
+	}
+	if event.Priority < 0 {
+		event.Priority = 0
+	}
+
 	jobs := []Job{}
 	workerSpec := project.Spec.WorkerTemplate
 	// If the event is a retry of another, defer to the Worker.Spec on the event
@@ -443,6 +683,15 @@ func (e *eventsService) createSingleEvent(
 		workerSpec.ConfigFilesDirectory = ".brigade"
 	}
 
+	// Clients cannot set the concurrency group directly
+	var err error
+	if event.ConcurrencyGroup, err = evaluateConcurrencyGroup(
+		workerSpec.ConcurrencyGroup,
+		event,
+	); err != nil {
+		return event, err
+	}
+
 	event.Worker = Worker{
 		Jobs: jobs,
 		Spec: workerSpec,
@@ -470,6 +719,17 @@ func (e *eventsService) createSingleEvent(
 		)
 	}
 
+	// Cancel older Events in the same concurrency group. By now, the new Event
+	// has been persisted and scheduled, so failing to do so doesn't fail its
+	// creation. Any older Events that remain will simply run their course.
+	if err := e.supersede(ctx, project, event); err != nil {
+		log.Println(errors.Wrapf(
+			err,
+			"error superseding events in concurrency group %q",
+			event.ConcurrencyGroup,
+		))
+	}
+
 	return event, nil
 }
 
@@ -549,6 +809,7 @@ func (e *eventsService) Clone(
 	clone := event
 	clone.ObjectMeta = meta.ObjectMeta{}
 	clone.Worker = Worker{}
+	clone.IdempotencyKey = ""
 
 	// Add a label for tracing the original cloned event id
 	if clone.Labels == nil {
@@ -556,7 +817,7 @@ func (e *eventsService) Clone(
 	}
 	clone.Labels[CloneLabelKey] = id
 
-	events, err := e.Create(ctx, clone)
+	events, err := e.Create(ctx, clone, EventCreateOptions{})
 	if err != nil {
 		return Event{}, err
 	}
@@ -622,21 +883,129 @@ func (e *eventsService) Cancel(ctx context.Context, id string) error {
 		)
 	}
 
-	if err = e.eventsStore.Cancel(ctx, id); err != nil {
-		return errors.Wrapf(err, "error canceling event %q in store", id)
+	return e.cancel(ctx, project, event)
+}
+
+// cancel cancels (or aborts) the provided Event and cleans up its Worker and
+// Jobs on the substrate. No authorization is performed.
+func (e *eventsService) cancel(
+	ctx context.Context,
+	project Project,
+	event Event,
+) error {
+	if err := e.eventsStore.Cancel(ctx, event.ID); err != nil {
+		return errors.Wrapf(err, "error canceling event %q in store", event.ID)
+	}
+
+	// Retrieve the Event again to learn which phases its Worker and Jobs have
+	// entered
+	if canceled, err := e.eventsStore.Get(ctx, event.ID); err != nil {
+		log.Println(
+			errors.Wrapf(err, "error retrieving event %q from store", event.ID),
+		)
+	} else {
+		e.notifyCanceled(ctx, canceled)
 	}
 
-	if err = e.substrate.DeleteWorkerAndJobs(ctx, project, event); err != nil {
+	if err := e.substrate.DeleteWorkerAndJobs(ctx, project, event); err != nil {
 		return errors.Wrapf(
 			err,
 			"error deleting event %q worker and jobs from the substrate",
-			id,
+			event.ID,
 		)
 	}
 
 	return nil
 }
 
+// notifyCanceled informs interested parties that the Worker of the provided
+// Event, which has just been canceled (or aborted), and any Jobs that were
+// canceled (or aborted) along with it have entered new phases. A failure to do
+// so is logged, but isn't allowed to fail the cancellation.
+func (e *eventsService) notifyCanceled(ctx context.Context, event Event) {
+	phase := event.Worker.Status.Phase
+	if phase != WorkerPhaseCanceled && phase != WorkerPhaseAborted {
+		return
+	}
+	if err := e.notifier.NotifyWorkerPhase(ctx, event, phase); err != nil {
+		log.Println(
+			errors.Wrapf(
+				err,
+				"error sending notifications of event %q worker phase %q",
+				event.ID,
+				phase,
+			),
+		)
+	}
+	// A Worker that was canceled before it started has no Jobs to cancel
+	if phase != WorkerPhaseAborted {
+		return
+	}
+	for _, job := range event.Worker.Jobs {
+		// Canceling an Event doesn't record an end time for the Jobs it cancels
+		// (or aborts). This distinguishes them from Jobs that were aborted or
+		// timed out individually. Jobs canceled earlier because a dependency
+		// failed are distinguishable by their reason.
+		if job.Status == nil ||
+			(job.Status.Phase != JobPhaseCanceled &&
+				job.Status.Phase != JobPhaseAborted) ||
+			job.Status.Ended != nil ||
+			job.Status.Reason == ReasonDependencyFailed {
+			continue
+		}
+		if err := e.notifier.NotifyJobPhase(
+			ctx,
+			event,
+			job.Name,
+			job.Status.Phase,
+		); err != nil {
+			log.Println(
+				errors.Wrapf(
+					err,
+					"error sending notifications of event %q job %q phase %q",
+					event.ID,
+					job.Name,
+					job.Status.Phase,
+				),
+			)
+		}
+	}
+}
+
+func (e *eventsService) Pin(ctx context.Context, id string) error {
+	event, err := e.eventsStore.Get(ctx, id)
+	if err != nil {
+		return errors.Wrapf(err, "error retrieving event %q from store", id)
+	}
+
+	if err =
+		e.projectAuthorize(ctx, event.ProjectID, RoleProjectUser); err != nil {
+		return err
+	}
+
+	if err = e.eventsStore.Pin(ctx, id); err != nil {
+		return errors.Wrapf(err, "error pinning event %q in store", id)
+	}
+	return nil
+}
+
+func (e *eventsService) Unpin(ctx context.Context, id string) error {
+	event, err := e.eventsStore.Get(ctx, id)
+	if err != nil {
+		return errors.Wrapf(err, "error retrieving event %q from store", id)
+	}
+
+	if err =
+		e.projectAuthorize(ctx, event.ProjectID, RoleProjectUser); err != nil {
+		return err
+	}
+
+	if err = e.eventsStore.Unpin(ctx, id); err != nil {
+		return errors.Wrapf(err, "error unpinning event %q in store", id)
+	}
+	return nil
+}
+
 func (e *eventsService) CancelMany(
 	ctx context.Context,
 	selector EventsSelector,
@@ -687,6 +1056,8 @@ func (e *eventsService) CancelMany(
 	for i := 0; i < concurrency; i++ {
 		go func() {
 			for event := range eventCh {
+				// deliberately not using ctx
+				e.notifyCanceled(context.Background(), event)
 				if err := e.substrate.DeleteWorkerAndJobs(
 					context.Background(), // deliberately not using ctx
 					project,
@@ -737,7 +1108,11 @@ func (e *eventsService) Delete(ctx context.Context, id string) error {
 		)
 	}
 
-	return e.logsStore.DeleteEventLogs(ctx, id)
+	if err = e.logsStore.DeleteEventLogs(ctx, event); err != nil {
+		return errors.Wrapf(err, "error deleting logs for event %q", id)
+	}
+
+	return e.artifactsStore.DeleteEventArtifacts(ctx, event)
 }
 
 func (e *eventsService) DeleteMany(
@@ -793,6 +1168,8 @@ func (e *eventsService) DeleteMany(
 	for i := 0; i < concurrency; i++ {
 		go func() {
 			for event := range eventCh {
+				// deliberately not using ctx
+				e.notifyCanceled(context.Background(), event)
 				if err := e.substrate.DeleteWorkerAndJobs(
 					context.Background(), // deliberately not using ctx
 					project,
@@ -807,7 +1184,7 @@ func (e *eventsService) DeleteMany(
 
 				if err := e.logsStore.DeleteEventLogs(
 					context.Background(), // deliberately not using ctx
-					event.ID,
+					event,
 				); err != nil {
 					log.Println(errors.Wrapf(
 						err,
@@ -815,6 +1192,17 @@ func (e *eventsService) DeleteMany(
 						event.ID,
 					))
 				}
+
+				if err := e.artifactsStore.DeleteEventArtifacts(
+					context.Background(), // deliberately not using ctx
+					event,
+				); err != nil {
+					log.Println(errors.Wrapf(
+						err,
+						"error deleting artifacts for event %q",
+						event.ID,
+					))
+				}
 			}
 		}()
 	}
@@ -854,6 +1242,7 @@ func (e *eventsService) Retry(
 	// metadata
 	retry := event
 	retry.ObjectMeta = meta.ObjectMeta{}
+	retry.IdempotencyKey = ""
 
 	// Add a label for tracing the original event id
 	if retry.Labels == nil {
@@ -877,7 +1266,7 @@ func (e *eventsService) Retry(
 	}
 	retry.Worker.Jobs = jobs
 
-	events, err := e.Create(ctx, retry)
+	events, err := e.Create(ctx, retry, EventCreateOptions{})
 	if err != nil {
 		return Event{}, err
 	}
@@ -885,12 +1274,84 @@ func (e *eventsService) Retry(
 	return events.Items[0], nil
 }
 
+func (e *eventsService) Match(
+	ctx context.Context,
+	event Event,
+) (EventMatchResult, error) {
+	result := EventMatchResult{
+		Subscribers:    []string{},
+		NonSubscribers: []ProjectMatchFailure{},
+	}
+
+	// The result describes the subscriptions of every Project, not just those
+	// the Event would reach, so being permitted to create the Event isn't enough.
+	// The principal must also be permitted to read system-wide.
+	if err := e.authorize(ctx, RoleReader, ""); err != nil {
+		return result, err
+	}
+	if err := e.authorizeCreate(ctx, event); err != nil {
+		return result, err
+	}
+
+	var projects []Project
+	if event.ProjectID != "" {
+		project, err := e.projectsStore.Get(ctx, event.ProjectID)
+		if err != nil {
+			return result, errors.Wrapf(
+				err,
+				"error retrieving project %q from store",
+				event.ProjectID,
+			)
+		}
+		projects = []Project{project}
+	} else {
+		opts := meta.ListOptions{Limit: 100}
+		for {
+			page, err := e.projectsStore.List(ctx, opts)
+			if err != nil {
+				return result, errors.Wrap(err, "error retrieving projects from store")
+			}
+			projects = append(projects, page.Items...)
+			if page.Continue == "" {
+				break
+			}
+			opts.Continue = page.Continue
+		}
+	}
+
+	for _, project := range projects {
+		if project.IsSubscribed(event) {
+			result.Subscribers = append(result.Subscribers, project.ID)
+			continue
+		}
+		failure := ProjectMatchFailure{
+			ProjectID:     project.ID,
+			Subscriptions: []SubscriptionMatchFailure{},
+		}
+		for i, subscription := range project.Spec.EventSubscriptions {
+			failure.Subscriptions = append(
+				failure.Subscriptions,
+				SubscriptionMatchFailure{
+					Index:      i,
+					Source:     subscription.Source,
+					Mismatches: subscription.Mismatches(event),
+				},
+			)
+		}
+		result.NonSubscribers = append(result.NonSubscribers, failure)
+	}
+
+	return result, nil
+}
+
 // EventsStore is an interface for components that implement Event persistence
 // concerns.
 type EventsStore interface {
 	// Create persists a new Event in the underlying data store. If n Event having
 	// the same ID already exists, implementations MUST return a *meta.ErrConflict
-	// error.
+	// error. Implementations MUST also return a *meta.ErrConflict error if an
+	// Event for the same Project, from the same source, and bearing the same
+	// (non-empty) idempotency key already exists.
 	Create(context.Context, Event) error
 	// List retrieves an EventList from the underlying data store, with its Items
 	// (Events) ordered by age, newest first. Criteria for which Events should be
@@ -904,6 +1365,17 @@ type EventsStore interface {
 	// specified Event does not exist, implementations MUST return a
 	// *meta.ErrNotFound error.
 	Get(context.Context, string) (Event, error)
+	// ListByIdempotencyKey retrieves an EventList from the underlying data store
+	// containing all Events from the specified source that bear the specified
+	// idempotency key.
+	ListByIdempotencyKey(
+		ctx context.Context,
+		source string,
+		key string,
+	) (meta.List[Event], error)
+	// ReleaseIdempotencyKey removes the specified idempotency key from all Events
+	// from the specified source that bear it so that the key may be re-used.
+	ReleaseIdempotencyKey(ctx context.Context, source string, key string) error
 	// GetByHashedWorkerToken retrieves a single Event from the underlying data
 	// store by the provided hashed Worker token. If no such Event exists,
 	// implementations MUST return a *meta.ErrNotFound error.
@@ -914,6 +1386,10 @@ type EventsStore interface {
 	UpdateSourceState(context.Context, string, SourceState) error
 	// UpdateSummary updates the opaque, Worker-specific Event summary.
 	UpdateSummary(context.Context, string, EventSummary) error
+	// UpdateLabels replaces the labels of the specified Event. If the specified
+	// Event does not exist, implementations MUST return a *meta.ErrNotFound
+	// error.
+	UpdateLabels(context.Context, string, map[string]string) error
 	// Cancel updates the specified Event in the underlying data store to reflect
 	// that it has been canceled. Implementations MAY assume the Event's existence
 	// has been pre-confirmed by the caller. Implementations MUST only cancel
@@ -921,6 +1397,14 @@ type EventsStore interface {
 	// specified Event's Worker has already reached a terminal state,
 	// implementations MUST return a *meta.ErrConflict.
 	Cancel(context.Context, string) error
+	// Pin updates the specified Event in the underlying data store to reflect
+	// that it has been pinned. If the specified Event does not exist,
+	// implementations MUST return a *meta.ErrNotFound error.
+	Pin(context.Context, string) error
+	// Unpin updates the specified Event in the underlying data store to reflect
+	// that it is no longer pinned. If the specified Event does not exist,
+	// implementations MUST return a *meta.ErrNotFound error.
+	Unpin(context.Context, string) error
 	// CancelMany updates multiple Events specified by the EventsSelector
 	// parameter in the underlying data store to reflect that they have been
 	// canceled. Implementations MUST only cancel events whose Workers have not
@@ -938,4 +1422,7 @@ type EventsStore interface {
 	// DeleteByProjectID unconditionally deletes all Events associated with the
 	// specified project.
 	DeleteByProjectID(context.Context, string) error
+	// GetQueueDepth counts the specified Project's Workers and Jobs that are
+	// waiting to be scheduled, i.e. those in a PENDING phase.
+	GetQueueDepth(context.Context, string) (ProjectQueueDepth, error)
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/brigadecore/brigade/v2/apiserver/internal/meta"
 	metaTesting "github.com/brigadecore/brigade/v2/apiserver/internal/meta/testing" // nolint: lll
@@ -30,30 +31,52 @@ func TestDeleteManyEventsResultMarshalJSON(t *testing.T) {
 	)
 }
 
+func TestEventMatchResultMarshalJSON(t *testing.T) {
+	metaTesting.RequireAPIVersionAndType(
+		t,
+		&EventMatchResult{},
+		"EventMatchResult",
+	)
+}
+
 func TestNewEventsService(t *testing.T) {
 	projectsStore := &mockProjectsStore{}
 	eventsStore := &mockEventsStore{}
 	logsStore := &mockLogsStore{}
+	artifactsStore := &mockArtifactsStore{}
 	substrate := &mockSubstrate{}
+	notifier := &mockNotifier{}
 	svc, ok := NewEventsService(
 		alwaysAuthorize,
 		alwaysProjectAuthorize,
 		projectsStore,
 		eventsStore,
 		logsStore,
+		artifactsStore,
 		substrate,
+		notifier,
+		EventsServiceConfig{
+			IdempotencyWindow: time.Hour,
+		},
 	).(*eventsService)
 	require.True(t, ok)
 	require.NotNil(t, svc.authorize)
 	require.Same(t, projectsStore, svc.projectsStore)
 	require.Same(t, eventsStore, svc.eventsStore)
+	require.Same(t, artifactsStore, svc.artifactsStore)
 	require.Same(t, substrate, svc.substrate)
+	require.Same(t, notifier, svc.notifier)
+	require.Equal(t, time.Hour, svc.config.IdempotencyWindow)
 }
 
 func TestEventsServiceCreate(t *testing.T) {
+	const testIdempotencyKey = "abc123"
+	now := time.Now().UTC()
+	longAgo := now.Add(-48 * time.Hour)
 	testCases := []struct {
 		name       string
 		event      Event
+		opts       EventCreateOptions
 		service    EventsService
 		assertions func(meta.List[Event], error)
 	}{
@@ -114,17 +137,13 @@ func TestEventsServiceCreate(t *testing.T) {
 						}, nil
 					},
 					ListSubscribersFn: func(
-						context.Context,
-						Event,
+						_ context.Context,
+						event Event,
 					) (meta.List[Project], error) {
+						project := subscriberTo(event)
+						project.ID = "blue-book"
 						return meta.List[Project]{
-							Items: []Project{
-								{
-									ObjectMeta: meta.ObjectMeta{
-										ID: "blue-book",
-									},
-								},
-							},
+							Items: []Project{project},
 						}, nil
 					},
 				},
@@ -158,17 +177,13 @@ func TestEventsServiceCreate(t *testing.T) {
 						}, nil
 					},
 					ListSubscribersFn: func(
-						context.Context,
-						Event,
+						_ context.Context,
+						event Event,
 					) (meta.List[Project], error) {
+						project := subscriberTo(event)
+						project.ID = "blue-book"
 						return meta.List[Project]{
-							Items: []Project{
-								{
-									ObjectMeta: meta.ObjectMeta{
-										ID: "blue-book",
-									},
-								},
-							},
+							Items: []Project{project},
 						}, nil
 					},
 				},
@@ -232,11 +247,11 @@ func TestEventsServiceCreate(t *testing.T) {
 				authorize: alwaysAuthorize,
 				projectsStore: &mockProjectsStore{
 					ListSubscribersFn: func(
-						context.Context,
-						Event,
+						_ context.Context,
+						event Event,
 					) (meta.List[Project], error) {
 						return meta.List[Project]{
-							Items: []Project{{}, {}},
+							Items: []Project{subscriberTo(event), subscriberTo(event)},
 						}, nil
 					},
 				},
@@ -266,11 +281,135 @@ func TestEventsServiceCreate(t *testing.T) {
 				authorize: alwaysAuthorize,
 				projectsStore: &mockProjectsStore{
 					ListSubscribersFn: func(
+						_ context.Context,
+						event Event,
+					) (meta.List[Project], error) {
+						return meta.List[Project]{
+							Items: []Project{subscriberTo(event), subscriberTo(event)},
+						}, nil
+					},
+				},
+				createSingleEventFn: func(
+					context.Context,
+					Project,
+					Event,
+				) (Event, error) {
+					return Event{}, nil
+				},
+			},
+			assertions: func(events meta.List[Event], err error) {
+				require.NoError(t, err)
+				require.Len(t, events.Items, 2)
+			},
+		},
+		{
+			name: "create multiple events for subscribed projects; candidates " +
+				"that are not subscribed are skipped",
+			event: Event{
+				Source: "github-gateway",
+				Type:   "push",
+				Labels: map[string]string{
+					"branch": "main",
+				},
+			},
+			service: &eventsService{
+				authorize: alwaysAuthorize,
+				projectsStore: &mockProjectsStore{
+					ListSubscribersFn: func(
+						_ context.Context,
+						event Event,
+					) (meta.List[Project], error) {
+						notSubscribed := subscriberTo(event)
+						notSubscribed.ID = "not-subscribed"
+						notSubscribed.Spec.EventSubscriptions[0].MatchStrategy =
+							MatchStrategyGlob
+						notSubscribed.Spec.EventSubscriptions[0].Labels =
+							map[string]string{
+								"branch": "release/*",
+							}
+						return meta.List[Project]{
+							Items: []Project{subscriberTo(event), notSubscribed},
+						}, nil
+					},
+				},
+				createSingleEventFn: func(
+					_ context.Context,
+					project Project,
+					event Event,
+				) (Event, error) {
+					require.NotEqual(t, "not-subscribed", project.ID)
+					return event, nil
+				},
+			},
+			assertions: func(events meta.List[Event], err error) {
+				require.NoError(t, err)
+				require.Len(t, events.Items, 1)
+			},
+		},
+		{
+			name: "error retrieving events by idempotency key",
+			opts: EventCreateOptions{
+				IdempotencyKey: testIdempotencyKey,
+			},
+			service: &eventsService{
+				authorize: alwaysAuthorize,
+				eventsStore: &mockEventsStore{
+					ListByIdempotencyKeyFn: func(
 						context.Context,
-						Event,
+						string,
+						string,
+					) (meta.List[Event], error) {
+						return meta.List[Event]{}, errors.New("something went wrong")
+					},
+				},
+			},
+			assertions: func(_ meta.List[Event], err error) {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), "something went wrong")
+				require.Contains(
+					t,
+					err.Error(),
+					"error retrieving events with idempotency key",
+				)
+			},
+		},
+		{
+			name: "repeat request within idempotency window",
+			opts: EventCreateOptions{
+				IdempotencyKey: testIdempotencyKey,
+			},
+			service: &eventsService{
+				authorize: alwaysAuthorize,
+				config: EventsServiceConfig{
+					IdempotencyWindow: time.Hour,
+				},
+				eventsStore: &mockEventsStore{
+					ListByIdempotencyKeyFn: func(
+						_ context.Context,
+						_ string,
+						key string,
+					) (meta.List[Event], error) {
+						require.Equal(t, testIdempotencyKey, key)
+						return meta.List[Event]{
+							Items: []Event{
+								{
+									ObjectMeta: meta.ObjectMeta{
+										ID:      "123456789",
+										Created: &now,
+									},
+									IdempotencyKey: key,
+								},
+							},
+						}, nil
+					},
+				},
+				projectsStore: &mockProjectsStore{
+					ListSubscribersFn: func(
+						_ context.Context,
+						event Event,
 					) (meta.List[Project], error) {
 						return meta.List[Project]{
-							Items: []Project{{}, {}},
+							Items: []Project{subscriberTo(event)},
 						}, nil
 					},
 				},
@@ -279,19 +418,244 @@ func TestEventsServiceCreate(t *testing.T) {
 					Project,
 					Event,
 				) (Event, error) {
+					require.Fail(t, "no event should have been created")
 					return Event{}, nil
 				},
 			},
+			assertions: func(events meta.List[Event], err error) {
+				require.NoError(t, err)
+				require.Len(t, events.Items, 1)
+				require.Equal(t, "123456789", events.Items[0].ID)
+			},
+		},
+		{
+			name: "repeat of partially completed request within idempotency window",
+			opts: EventCreateOptions{
+				IdempotencyKey: testIdempotencyKey,
+			},
+			service: &eventsService{
+				authorize: alwaysAuthorize,
+				config: EventsServiceConfig{
+					IdempotencyWindow: time.Hour,
+				},
+				eventsStore: &mockEventsStore{
+					ListByIdempotencyKeyFn: func(
+						_ context.Context,
+						_ string,
+						key string,
+					) (meta.List[Event], error) {
+						return meta.List[Event]{
+							Items: []Event{
+								{
+									ObjectMeta: meta.ObjectMeta{
+										ID:      "123456789",
+										Created: &now,
+									},
+									ProjectID:      "bluebook",
+									IdempotencyKey: key,
+								},
+							},
+						}, nil
+					},
+				},
+				projectsStore: &mockProjectsStore{
+					ListSubscribersFn: func(
+						_ context.Context,
+						event Event,
+					) (meta.List[Project], error) {
+						bluebook := subscriberTo(event)
+						bluebook.ID = "bluebook"
+						italian := subscriberTo(event)
+						italian.ID = "italian"
+						return meta.List[Project]{
+							Items: []Project{bluebook, italian},
+						}, nil
+					},
+				},
+				createSingleEventFn: func(
+					_ context.Context,
+					project Project,
+					event Event,
+				) (Event, error) {
+					require.Equal(t, "italian", project.ID)
+					require.Equal(t, testIdempotencyKey, event.IdempotencyKey)
+					event.ID = "987654321"
+					return event, nil
+				},
+			},
 			assertions: func(events meta.List[Event], err error) {
 				require.NoError(t, err)
 				require.Len(t, events.Items, 2)
+				require.Equal(t, "123456789", events.Items[0].ID)
+				require.Equal(t, "987654321", events.Items[1].ID)
+			},
+		},
+		{
+			name: "error releasing expired idempotency key",
+			opts: EventCreateOptions{
+				IdempotencyKey: testIdempotencyKey,
+			},
+			service: &eventsService{
+				authorize: alwaysAuthorize,
+				config: EventsServiceConfig{
+					IdempotencyWindow: time.Hour,
+				},
+				eventsStore: &mockEventsStore{
+					ListByIdempotencyKeyFn: func(
+						context.Context,
+						string,
+						string,
+					) (meta.List[Event], error) {
+						return meta.List[Event]{
+							Items: []Event{
+								{
+									ObjectMeta: meta.ObjectMeta{
+										Created: &longAgo,
+									},
+								},
+							},
+						}, nil
+					},
+					ReleaseIdempotencyKeyFn: func(
+						context.Context,
+						string,
+						string,
+					) error {
+						return errors.New("something went wrong")
+					},
+				},
+			},
+			assertions: func(_ meta.List[Event], err error) {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), "something went wrong")
+				require.Contains(t, err.Error(), "error releasing expired")
+			},
+		},
+		{
+			name: "repeat request outside idempotency window",
+			opts: EventCreateOptions{
+				IdempotencyKey: testIdempotencyKey,
+			},
+			service: &eventsService{
+				authorize: alwaysAuthorize,
+				config: EventsServiceConfig{
+					IdempotencyWindow: time.Hour,
+				},
+				eventsStore: &mockEventsStore{
+					ListByIdempotencyKeyFn: func(
+						context.Context,
+						string,
+						string,
+					) (meta.List[Event], error) {
+						return meta.List[Event]{
+							Items: []Event{
+								{
+									ObjectMeta: meta.ObjectMeta{
+										Created: &longAgo,
+									},
+								},
+							},
+						}, nil
+					},
+					ReleaseIdempotencyKeyFn: func(
+						context.Context,
+						string,
+						string,
+					) error {
+						return nil
+					},
+				},
+				projectsStore: &mockProjectsStore{
+					ListSubscribersFn: func(
+						_ context.Context,
+						event Event,
+					) (meta.List[Project], error) {
+						return meta.List[Project]{
+							Items: []Project{subscriberTo(event)},
+						}, nil
+					},
+				},
+				createSingleEventFn: func(
+					_ context.Context,
+					_ Project,
+					event Event,
+				) (Event, error) {
+					require.Equal(t, testIdempotencyKey, event.IdempotencyKey)
+					return event, nil
+				},
+			},
+			assertions: func(events meta.List[Event], err error) {
+				require.NoError(t, err)
+				require.Len(t, events.Items, 1)
+			},
+		},
+		{
+			name: "concurrent request with same idempotency key",
+			opts: EventCreateOptions{
+				IdempotencyKey: testIdempotencyKey,
+			},
+			service: &eventsService{
+				authorize: alwaysAuthorize,
+				config: EventsServiceConfig{
+					IdempotencyWindow: time.Hour,
+				},
+				eventsStore: func() EventsStore {
+					var calls int
+					return &mockEventsStore{
+						ListByIdempotencyKeyFn: func(
+							context.Context,
+							string,
+							string,
+						) (meta.List[Event], error) {
+							calls++
+							if calls == 1 {
+								return meta.List[Event]{}, nil
+							}
+							return meta.List[Event]{
+								Items: []Event{
+									{
+										ObjectMeta: meta.ObjectMeta{
+											ID:      "123456789",
+											Created: &now,
+										},
+									},
+								},
+							}, nil
+						},
+					}
+				}(),
+				projectsStore: &mockProjectsStore{
+					ListSubscribersFn: func(
+						_ context.Context,
+						event Event,
+					) (meta.List[Project], error) {
+						return meta.List[Project]{
+							Items: []Project{subscriberTo(event)},
+						}, nil
+					},
+				},
+				createSingleEventFn: func(
+					context.Context,
+					Project,
+					Event,
+				) (Event, error) {
+					return Event{}, &meta.ErrConflict{}
+				},
+			},
+			assertions: func(events meta.List[Event], err error) {
+				require.NoError(t, err)
+				require.Len(t, events.Items, 1)
+				require.Equal(t, "123456789", events.Items[0].ID)
 			},
 		},
 	}
 	for _, testCase := range testCases {
 		t.Run(testCase.name, func(t *testing.T) {
-			events, err :=
-				testCase.service.Create(context.Background(), testCase.event)
+			events, err := testCase.service.Create(
+				context.Background(),
+				testCase.event,
+				testCase.opts,
+			)
 			testCase.assertions(events, err)
 		})
 	}
@@ -310,11 +674,14 @@ func TestEventsServiceCreateSingleEvent(t *testing.T) {
 		RetryLabelKey: "1234567",
 	}
 	testCases := []struct {
-		name        string
-		eventLabels map[string]string
-		worker      Worker
-		service     *eventsService
-		assertions  func(Event, error)
+		name             string
+		eventLabels      map[string]string
+		eventPriority    int
+		maxEventPriority int
+		concurrencyGroup string
+		worker           Worker
+		service          *eventsService
+		assertions       func(Event, error)
 	}{
 		{
 			name: "error creating event in store",
@@ -332,40 +699,151 @@ func TestEventsServiceCreateSingleEvent(t *testing.T) {
 			},
 		},
 		{
-			name: "error scheduling worker in substrate",
+			name: "error scheduling worker in substrate",
+			service: &eventsService{
+				eventsStore: &mockEventsStore{
+					CreateFn: func(context.Context, Event) error {
+						return nil
+					},
+				},
+				substrate: &mockSubstrate{
+					ScheduleWorkerFn: func(context.Context, Event) error {
+						return errors.New("substrate error")
+					},
+				},
+			},
+			assertions: func(_ Event, err error) {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), "error scheduling event")
+				require.Contains(t, err.Error(), "substrate error")
+			},
+		},
+		{
+			name:        "event retry - inherit worker spec and job",
+			eventLabels: testEventRetryLabel,
+			worker: Worker{
+				Spec: WorkerSpec{
+					DefaultConfigFiles: map[string]string{
+						"defaultConfig": "myConfig",
+					},
+				},
+				Jobs: []Job{
+					{
+						Name: "foo",
+					},
+				},
+			},
+			service: &eventsService{
+				eventsStore: &mockEventsStore{
+					CreateFn: func(context.Context, Event) error {
+						return nil
+					},
+				},
+				substrate: &mockSubstrate{
+					ScheduleWorkerFn: func(context.Context, Event) error {
+						return nil
+					},
+				},
+			},
+			assertions: func(event Event, err error) {
+				require.NoError(t, err)
+				require.Equal(t, 1, len(event.Worker.Jobs))
+				require.Equal(t, Job{Name: "foo"}, event.Worker.Jobs[0])
+				require.Equal(
+					t,
+					map[string]string{"defaultConfig": "myConfig"},
+					event.Worker.Spec.DefaultConfigFiles,
+				)
+			},
+		},
+		{
+			name: "success",
+			service: &eventsService{
+				eventsStore: &mockEventsStore{
+					CreateFn: func(context.Context, Event) error {
+						return nil
+					},
+				},
+				substrate: &mockSubstrate{
+					ScheduleWorkerFn: func(context.Context, Event) error {
+						return nil
+					},
+				},
+			},
+			assertions: func(event Event, err error) {
+				require.NoError(t, err)
+				// Make sure the Event looks like what we expect-- i.e. ID generated,
+				// default applied, etc.
+				require.NotEmpty(t, event.ID)
+				require.Equal(t, defaultWorkspaceSize, event.Worker.Spec.WorkspaceSize)
+				require.NotNil(t, event.Worker.Spec.Git)
+				require.Equal(t, testEvent.Git.CloneURL, event.Worker.Spec.Git.CloneURL)
+				require.Equal(t, testEvent.Git.Commit, event.Worker.Spec.Git.Commit)
+				require.Equal(t, testEvent.Git.Ref, event.Worker.Spec.Git.Ref)
+				require.Equal(t, LogLevelInfo, event.Worker.Spec.LogLevel)
+				require.Equal(t, WorkerPhasePending, event.Worker.Status.Phase)
+			},
+		},
+		{
+			name:             "error superseding events is not fatal",
+			concurrencyGroup: "{{.Git.Ref}}",
 			service: &eventsService{
 				eventsStore: &mockEventsStore{
 					CreateFn: func(context.Context, Event) error {
 						return nil
 					},
+					ListFn: func(
+						context.Context,
+						EventsSelector,
+						meta.ListOptions,
+					) (meta.List[Event], error) {
+						return meta.List[Event]{}, errors.New("store error")
+					},
 				},
 				substrate: &mockSubstrate{
 					ScheduleWorkerFn: func(context.Context, Event) error {
-						return errors.New("substrate error")
+						return nil
 					},
 				},
 			},
-			assertions: func(_ Event, err error) {
-				require.Error(t, err)
-				require.Contains(t, err.Error(), "error scheduling event")
-				require.Contains(t, err.Error(), "substrate error")
+			assertions: func(event Event, err error) {
+				// The new Event has already been persisted and scheduled
+				require.NoError(t, err)
+				require.NotEmpty(t, event.ID)
 			},
 		},
 		{
-			name:        "event retry - inherit worker spec and job",
-			eventLabels: testEventRetryLabel,
-			worker: Worker{
-				Spec: WorkerSpec{
-					DefaultConfigFiles: map[string]string{
-						"defaultConfig": "myConfig",
+			name:             "event joins concurrency group",
+			concurrencyGroup: "{{.Git.Ref}}",
+			service: &eventsService{
+				eventsStore: &mockEventsStore{
+					CreateFn: func(_ context.Context, event Event) error {
+						require.Equal(t, "dev", event.ConcurrencyGroup)
+						return nil
+					},
+					ListFn: func(
+						context.Context,
+						EventsSelector,
+						meta.ListOptions,
+					) (meta.List[Event], error) {
+						return meta.List[Event]{}, nil
 					},
 				},
-				Jobs: []Job{
-					{
-						Name: "foo",
+				substrate: &mockSubstrate{
+					ScheduleWorkerFn: func(context.Context, Event) error {
+						return nil
 					},
 				},
 			},
+			assertions: func(event Event, err error) {
+				require.NoError(t, err)
+				require.Equal(t, "dev", event.ConcurrencyGroup)
+			},
+		},
+		{
+			name:             "priority within project maximum",
+			eventPriority:    3,
+			maxEventPriority: 5,
 			service: &eventsService{
 				eventsStore: &mockEventsStore{
 					CreateFn: func(context.Context, Event) error {
@@ -380,17 +858,13 @@ func TestEventsServiceCreateSingleEvent(t *testing.T) {
 			},
 			assertions: func(event Event, err error) {
 				require.NoError(t, err)
-				require.Equal(t, 1, len(event.Worker.Jobs))
-				require.Equal(t, Job{Name: "foo"}, event.Worker.Jobs[0])
-				require.Equal(
-					t,
-					map[string]string{"defaultConfig": "myConfig"},
-					event.Worker.Spec.DefaultConfigFiles,
-				)
+				require.Equal(t, 3, event.Priority)
 			},
 		},
 		{
-			name: "success",
+			name:             "priority exceeds project maximum",
+			eventPriority:    9,
+			maxEventPriority: 5,
 			service: &eventsService{
 				eventsStore: &mockEventsStore{
 					CreateFn: func(context.Context, Event) error {
@@ -398,30 +872,26 @@ func TestEventsServiceCreateSingleEvent(t *testing.T) {
 					},
 				},
 				substrate: &mockSubstrate{
-					ScheduleWorkerFn: func(context.Context, Event) error {
+					ScheduleWorkerFn: func(_ context.Context, event Event) error {
+						require.Equal(t, 5, event.Priority)
 						return nil
 					},
 				},
 			},
 			assertions: func(event Event, err error) {
 				require.NoError(t, err)
-				// Make sure the Event looks like what we expect-- i.e. ID generated,
-				// default applied, etc.
-				require.NotEmpty(t, event.ID)
-				require.Equal(t, defaultWorkspaceSize, event.Worker.Spec.WorkspaceSize)
-				require.NotNil(t, event.Worker.Spec.Git)
-				require.Equal(t, testEvent.Git.CloneURL, event.Worker.Spec.Git.CloneURL)
-				require.Equal(t, testEvent.Git.Commit, event.Worker.Spec.Git.Commit)
-				require.Equal(t, testEvent.Git.Ref, event.Worker.Spec.Git.Ref)
-				require.Equal(t, LogLevelInfo, event.Worker.Spec.LogLevel)
-				require.Equal(t, WorkerPhasePending, event.Worker.Status.Phase)
+				require.Equal(t, 5, event.Priority)
 			},
 		},
 	}
 	for _, testCase := range testCases {
 		t.Run(testCase.name, func(t *testing.T) {
 			testEvent.Labels = testCase.eventLabels
+			testEvent.Priority = testCase.eventPriority
 			testEvent.Worker = testCase.worker
+			testProject.Spec.MaxEventPriority = testCase.maxEventPriority
+			testProject.Spec.WorkerTemplate.ConcurrencyGroup =
+				testCase.concurrencyGroup
 			event, err := testCase.service.createSingleEvent(
 				context.Background(),
 				testProject,
@@ -655,11 +1125,11 @@ func TestEventsServiceClone(t *testing.T) {
 				},
 				projectsStore: &mockProjectsStore{
 					ListSubscribersFn: func(
-						context.Context,
-						Event,
+						_ context.Context,
+						event Event,
 					) (meta.List[Project], error) {
 						return meta.List[Project]{
-							Items: []Project{{}, {}},
+							Items: []Project{subscriberTo(event), subscriberTo(event)},
 						}, nil
 					},
 				},
@@ -697,11 +1167,11 @@ func TestEventsServiceClone(t *testing.T) {
 				},
 				projectsStore: &mockProjectsStore{
 					ListSubscribersFn: func(
-						context.Context,
-						Event,
+						_ context.Context,
+						event Event,
 					) (meta.List[Project], error) {
 						return meta.List[Project]{
-							Items: []Project{{}, {}},
+							Items: []Project{subscriberTo(event), subscriberTo(event)},
 						}, nil
 					},
 				},
@@ -904,7 +1374,270 @@ func TestEventsServiceUpdateSummary(t *testing.T) {
 	}
 }
 
-func TestEventsServiceCancel(t *testing.T) {
+func TestEventsServiceCancel(t *testing.T) {
+	const testEventID = "123456789"
+	testCases := []struct {
+		name       string
+		service    EventsService
+		assertions func(error)
+	}{
+		{
+			name: "error retrieving event from store",
+			service: &eventsService{
+				eventsStore: &mockEventsStore{
+					GetFn: func(context.Context, string) (Event, error) {
+						return Event{}, errors.New("events store error")
+					},
+				},
+			},
+			assertions: func(err error) {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), "error retrieving event")
+				require.Contains(t, err.Error(), "events store error")
+			},
+		},
+		{
+			name: "unauthorized",
+			service: &eventsService{
+				projectAuthorize: neverProjectAuthorize,
+				eventsStore: &mockEventsStore{
+					GetFn: func(context.Context, string) (Event, error) {
+						return Event{}, nil
+					},
+				},
+			},
+			assertions: func(err error) {
+				require.Error(t, err)
+				require.IsType(t, &meta.ErrAuthorization{}, err)
+			},
+		},
+		{
+			name: "error retrieving project from store",
+			service: &eventsService{
+				projectAuthorize: alwaysProjectAuthorize,
+				eventsStore: &mockEventsStore{
+					GetFn: func(context.Context, string) (Event, error) {
+						return Event{}, nil
+					},
+				},
+				projectsStore: &mockProjectsStore{
+					GetFn: func(context.Context, string) (Project, error) {
+						return Project{}, errors.New("projects store error")
+					},
+				},
+			},
+			assertions: func(err error) {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), "error retrieving project")
+				require.Contains(t, err.Error(), "projects store error")
+			},
+		},
+		{
+			name: "error canceling event in store",
+			service: &eventsService{
+				projectAuthorize: alwaysProjectAuthorize,
+				eventsStore: &mockEventsStore{
+					GetFn: func(context.Context, string) (Event, error) {
+						return Event{}, nil
+					},
+					CancelFn: func(context.Context, string) error {
+						return errors.New("events store error")
+					},
+				},
+				projectsStore: &mockProjectsStore{
+					GetFn: func(context.Context, string) (Project, error) {
+						return Project{}, nil
+					},
+				},
+			},
+			assertions: func(err error) {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), "error canceling event")
+				require.Contains(t, err.Error(), "events store error")
+			},
+		},
+		{
+			name: "error deleting event from substrate",
+			service: &eventsService{
+				projectAuthorize: alwaysProjectAuthorize,
+				eventsStore: &mockEventsStore{
+					GetFn: func(context.Context, string) (Event, error) {
+						return Event{}, nil
+					},
+					CancelFn: func(context.Context, string) error {
+						return nil
+					},
+				},
+				projectsStore: &mockProjectsStore{
+					GetFn: func(context.Context, string) (Project, error) {
+						return Project{}, nil
+					},
+				},
+				substrate: &mockSubstrate{
+					DeleteWorkerAndJobsFn: func(context.Context, Project, Event) error {
+						return errors.New("substrate error")
+					},
+				},
+			},
+			assertions: func(err error) {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), "error deleting event")
+				require.Contains(t, err.Error(), "worker and jobs")
+				require.Contains(t, err.Error(), "from the substrate")
+				require.Contains(t, err.Error(), "substrate error")
+			},
+		},
+		{
+			name: "success",
+			service: &eventsService{
+				projectAuthorize: alwaysProjectAuthorize,
+				eventsStore: &mockEventsStore{
+					GetFn: func(context.Context, string) (Event, error) {
+						return Event{}, nil
+					},
+					CancelFn: func(context.Context, string) error {
+						return nil
+					},
+				},
+				projectsStore: &mockProjectsStore{
+					GetFn: func(context.Context, string) (Project, error) {
+						return Project{}, nil
+					},
+				},
+				substrate: &mockSubstrate{
+					DeleteWorkerAndJobsFn: func(context.Context, Project, Event) error {
+						return nil
+					},
+				},
+			},
+			assertions: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			err := testCase.service.Cancel(context.Background(), testEventID)
+			testCase.assertions(err)
+		})
+	}
+}
+
+func TestEventsServiceNotifyCanceled(t *testing.T) {
+	now := time.Now().UTC()
+	testCases := []struct {
+		name                 string
+		event                Event
+		expectedWorkerPhases []WorkerPhase
+		expectedJobPhases    map[string]JobPhase
+	}{
+		{
+			name: "worker not canceled",
+			event: Event{
+				Worker: Worker{
+					Status: WorkerStatus{
+						Phase: WorkerPhaseRunning,
+					},
+				},
+			},
+			expectedJobPhases: map[string]JobPhase{},
+		},
+		{
+			name: "worker canceled",
+			event: Event{
+				Worker: Worker{
+					Status: WorkerStatus{
+						Phase: WorkerPhaseCanceled,
+					},
+				},
+			},
+			expectedWorkerPhases: []WorkerPhase{WorkerPhaseCanceled},
+			expectedJobPhases:    map[string]JobPhase{},
+		},
+		{
+			name: "worker aborted",
+			event: Event{
+				Worker: Worker{
+					Jobs: []Job{
+						{
+							Name: "pending",
+							Status: &JobStatus{
+								Phase: JobPhaseCanceled,
+							},
+						},
+						{
+							Name: "running",
+							Status: &JobStatus{
+								Phase: JobPhaseAborted,
+							},
+						},
+						{
+							Name: "succeeded",
+							Status: &JobStatus{
+								Phase: JobPhaseSucceeded,
+								Ended: &now,
+							},
+						},
+						{
+							Name: "aborted-earlier",
+							Status: &JobStatus{
+								Phase: JobPhaseAborted,
+								Ended: &now,
+							},
+						},
+						{
+							Name: "dependency-failed",
+							Status: &JobStatus{
+								Phase:  JobPhaseCanceled,
+								Reason: ReasonDependencyFailed,
+							},
+						},
+					},
+					Status: WorkerStatus{
+						Phase: WorkerPhaseAborted,
+					},
+				},
+			},
+			expectedWorkerPhases: []WorkerPhase{WorkerPhaseAborted},
+			expectedJobPhases: map[string]JobPhase{
+				"pending": JobPhaseCanceled,
+				"running": JobPhaseAborted,
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			var workerPhases []WorkerPhase
+			jobPhases := map[string]JobPhase{}
+			service := &eventsService{
+				notifier: &mockNotifier{
+					NotifyWorkerPhaseFn: func(
+						_ context.Context,
+						_ Event,
+						phase WorkerPhase,
+					) error {
+						workerPhases = append(workerPhases, phase)
+						return nil
+					},
+					NotifyJobPhaseFn: func(
+						_ context.Context,
+						_ Event,
+						jobName string,
+						phase JobPhase,
+					) error {
+						jobPhases[jobName] = phase
+						return errors.New("notifier error") // Should only be logged
+					},
+				},
+			}
+			service.notifyCanceled(context.Background(), testCase.event)
+			require.Equal(t, testCase.expectedWorkerPhases, workerPhases)
+			require.Equal(t, testCase.expectedJobPhases, jobPhases)
+		})
+	}
+}
+
+func TestEventsServicePin(t *testing.T) {
 	const testEventID = "123456789"
 	testCases := []struct {
 		name       string
@@ -942,79 +1675,105 @@ func TestEventsServiceCancel(t *testing.T) {
 			},
 		},
 		{
-			name: "error retrieving project from store",
+			name: "error pinning event in store",
 			service: &eventsService{
 				projectAuthorize: alwaysProjectAuthorize,
 				eventsStore: &mockEventsStore{
 					GetFn: func(context.Context, string) (Event, error) {
 						return Event{}, nil
 					},
-				},
-				projectsStore: &mockProjectsStore{
-					GetFn: func(context.Context, string) (Project, error) {
-						return Project{}, errors.New("projects store error")
+					PinFn: func(context.Context, string) error {
+						return errors.New("events store error")
 					},
 				},
 			},
 			assertions: func(err error) {
 				require.Error(t, err)
-				require.Contains(t, err.Error(), "error retrieving project")
-				require.Contains(t, err.Error(), "projects store error")
+				require.Contains(t, err.Error(), "error pinning event")
+				require.Contains(t, err.Error(), "events store error")
 			},
 		},
 		{
-			name: "error canceling event in store",
+			name: "success",
 			service: &eventsService{
 				projectAuthorize: alwaysProjectAuthorize,
 				eventsStore: &mockEventsStore{
 					GetFn: func(context.Context, string) (Event, error) {
 						return Event{}, nil
 					},
-					CancelFn: func(context.Context, string) error {
-						return errors.New("events store error")
+					PinFn: func(_ context.Context, id string) error {
+						require.Equal(t, testEventID, id)
+						return nil
 					},
 				},
-				projectsStore: &mockProjectsStore{
-					GetFn: func(context.Context, string) (Project, error) {
-						return Project{}, nil
+			},
+			assertions: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			err := testCase.service.Pin(context.Background(), testEventID)
+			testCase.assertions(err)
+		})
+	}
+}
+
+func TestEventsServiceUnpin(t *testing.T) {
+	const testEventID = "123456789"
+	testCases := []struct {
+		name       string
+		service    EventsService
+		assertions func(error)
+	}{
+		{
+			name: "error retrieving event from store",
+			service: &eventsService{
+				eventsStore: &mockEventsStore{
+					GetFn: func(context.Context, string) (Event, error) {
+						return Event{}, errors.New("events store error")
 					},
 				},
 			},
 			assertions: func(err error) {
 				require.Error(t, err)
-				require.Contains(t, err.Error(), "error canceling event")
+				require.Contains(t, err.Error(), "error retrieving event")
 				require.Contains(t, err.Error(), "events store error")
 			},
 		},
 		{
-			name: "error deleting event from substrate",
+			name: "unauthorized",
 			service: &eventsService{
-				projectAuthorize: alwaysProjectAuthorize,
+				projectAuthorize: neverProjectAuthorize,
 				eventsStore: &mockEventsStore{
 					GetFn: func(context.Context, string) (Event, error) {
 						return Event{}, nil
 					},
-					CancelFn: func(context.Context, string) error {
-						return nil
-					},
 				},
-				projectsStore: &mockProjectsStore{
-					GetFn: func(context.Context, string) (Project, error) {
-						return Project{}, nil
+			},
+			assertions: func(err error) {
+				require.Error(t, err)
+				require.IsType(t, &meta.ErrAuthorization{}, err)
+			},
+		},
+		{
+			name: "error unpinning event in store",
+			service: &eventsService{
+				projectAuthorize: alwaysProjectAuthorize,
+				eventsStore: &mockEventsStore{
+					GetFn: func(context.Context, string) (Event, error) {
+						return Event{}, nil
 					},
-				},
-				substrate: &mockSubstrate{
-					DeleteWorkerAndJobsFn: func(context.Context, Project, Event) error {
-						return errors.New("substrate error")
+					UnpinFn: func(context.Context, string) error {
+						return errors.New("events store error")
 					},
 				},
 			},
 			assertions: func(err error) {
 				require.Error(t, err)
-				require.Contains(t, err.Error(), "error deleting event")
-				require.Contains(t, err.Error(), "worker and jobs")
-				require.Contains(t, err.Error(), "from the substrate")
-				require.Contains(t, err.Error(), "substrate error")
+				require.Contains(t, err.Error(), "error unpinning event")
+				require.Contains(t, err.Error(), "events store error")
 			},
 		},
 		{
@@ -1025,17 +1784,8 @@ func TestEventsServiceCancel(t *testing.T) {
 					GetFn: func(context.Context, string) (Event, error) {
 						return Event{}, nil
 					},
-					CancelFn: func(context.Context, string) error {
-						return nil
-					},
-				},
-				projectsStore: &mockProjectsStore{
-					GetFn: func(context.Context, string) (Project, error) {
-						return Project{}, nil
-					},
-				},
-				substrate: &mockSubstrate{
-					DeleteWorkerAndJobsFn: func(context.Context, Project, Event) error {
+					UnpinFn: func(_ context.Context, id string) error {
+						require.Equal(t, testEventID, id)
 						return nil
 					},
 				},
@@ -1047,7 +1797,7 @@ func TestEventsServiceCancel(t *testing.T) {
 	}
 	for _, testCase := range testCases {
 		t.Run(testCase.name, func(t *testing.T) {
-			err := testCase.service.Cancel(context.Background(), testEventID)
+			err := testCase.service.Unpin(context.Background(), testEventID)
 			testCase.assertions(err)
 		})
 	}
@@ -1325,7 +2075,7 @@ func TestEventsServiceDelete(t *testing.T) {
 					},
 				},
 				logsStore: &mockLogsStore{
-					DeleteEventLogsFn: func(context.Context, string) error {
+					DeleteEventLogsFn: func(context.Context, Event) error {
 						return errors.New("error deleting logs")
 					},
 				},
@@ -1340,6 +2090,44 @@ func TestEventsServiceDelete(t *testing.T) {
 				require.Contains(t, err.Error(), "error deleting logs")
 			},
 		},
+		{
+			name: "error deleting event artifacts",
+			service: &eventsService{
+				projectAuthorize: alwaysProjectAuthorize,
+				eventsStore: &mockEventsStore{
+					GetFn: func(context.Context, string) (Event, error) {
+						return Event{}, nil
+					},
+					DeleteFn: func(context.Context, string) error {
+						return nil
+					},
+				},
+				projectsStore: &mockProjectsStore{
+					GetFn: func(context.Context, string) (Project, error) {
+						return Project{}, nil
+					},
+				},
+				logsStore: &mockLogsStore{
+					DeleteEventLogsFn: func(context.Context, Event) error {
+						return nil
+					},
+				},
+				artifactsStore: &mockArtifactsStore{
+					DeleteEventArtifactsFn: func(context.Context, Event) error {
+						return errors.New("error deleting artifacts")
+					},
+				},
+				substrate: &mockSubstrate{
+					DeleteWorkerAndJobsFn: func(context.Context, Project, Event) error {
+						return nil
+					},
+				},
+			},
+			assertions: func(err error) {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), "error deleting artifacts")
+			},
+		},
 		{
 			name: "success",
 			service: &eventsService{
@@ -1358,7 +2146,12 @@ func TestEventsServiceDelete(t *testing.T) {
 					},
 				},
 				logsStore: &mockLogsStore{
-					DeleteEventLogsFn: func(context.Context, string) error {
+					DeleteEventLogsFn: func(context.Context, Event) error {
+						return nil
+					},
+				},
+				artifactsStore: &mockArtifactsStore{
+					DeleteEventArtifactsFn: func(context.Context, Event) error {
 						return nil
 					},
 				},
@@ -1608,11 +2401,11 @@ func TestEventsServiceRetry(t *testing.T) {
 				},
 				projectsStore: &mockProjectsStore{
 					ListSubscribersFn: func(
-						context.Context,
-						Event,
+						_ context.Context,
+						event Event,
 					) (meta.List[Project], error) {
 						return meta.List[Project]{
-							Items: []Project{{}, {}},
+							Items: []Project{subscriberTo(event), subscriberTo(event)},
 						}, nil
 					},
 				},
@@ -1654,11 +2447,11 @@ func TestEventsServiceRetry(t *testing.T) {
 				},
 				projectsStore: &mockProjectsStore{
 					ListSubscribersFn: func(
-						context.Context,
-						Event,
+						_ context.Context,
+						event Event,
 					) (meta.List[Project], error) {
 						return meta.List[Project]{
-							Items: []Project{{}, {}},
+							Items: []Project{subscriberTo(event), subscriberTo(event)},
 						}, nil
 					},
 				},
@@ -1704,11 +2497,11 @@ func TestEventsServiceRetry(t *testing.T) {
 				},
 				projectsStore: &mockProjectsStore{
 					ListSubscribersFn: func(
-						context.Context,
-						Event,
+						_ context.Context,
+						event Event,
 					) (meta.List[Project], error) {
 						return meta.List[Project]{
-							Items: []Project{{}, {}},
+							Items: []Project{subscriberTo(event), subscriberTo(event)},
 						}, nil
 					},
 				},
@@ -1758,11 +2551,11 @@ func TestEventsServiceRetry(t *testing.T) {
 				},
 				projectsStore: &mockProjectsStore{
 					ListSubscribersFn: func(
-						context.Context,
-						Event,
+						_ context.Context,
+						event Event,
 					) (meta.List[Project], error) {
 						return meta.List[Project]{
-							Items: []Project{{}, {}},
+							Items: []Project{subscriberTo(event), subscriberTo(event)},
 						}, nil
 					},
 				},
@@ -1804,11 +2597,11 @@ func TestEventsServiceRetry(t *testing.T) {
 				},
 				projectsStore: &mockProjectsStore{
 					ListSubscribersFn: func(
-						context.Context,
-						Event,
+						_ context.Context,
+						event Event,
 					) (meta.List[Project], error) {
 						return meta.List[Project]{
-							Items: []Project{{}, {}},
+							Items: []Project{subscriberTo(event), subscriberTo(event)},
 						}, nil
 					},
 				},
@@ -1848,6 +2641,183 @@ func TestEventsServiceRetry(t *testing.T) {
 	}
 }
 
+func TestEventsServiceMatch(t *testing.T) {
+	testEvent := Event{
+		Source: "github-gateway",
+		Type:   "push",
+		Labels: map[string]string{
+			"branch": "main",
+		},
+	}
+	testCases := []struct {
+		name       string
+		event      Event
+		service    EventsService
+		assertions func(EventMatchResult, error)
+	}{
+		{
+			name:  "unauthorized",
+			event: testEvent,
+			service: &eventsService{
+				authorize: neverAuthorize,
+			},
+			assertions: func(_ EventMatchResult, err error) {
+				require.Error(t, err)
+				require.IsType(t, &meta.ErrAuthorization{}, err)
+			},
+		},
+		{
+			name:  "authorized to create event but not to read",
+			event: testEvent,
+			service: &eventsService{
+				authorize: func(_ context.Context, role Role, _ string) error {
+					if role == RoleReader {
+						return &meta.ErrAuthorization{}
+					}
+					return nil
+				},
+			},
+			assertions: func(_ EventMatchResult, err error) {
+				require.Error(t, err)
+				require.IsType(t, &meta.ErrAuthorization{}, err)
+			},
+		},
+		{
+			name: "error retrieving specified project",
+			event: Event{
+				ProjectID: "blue-book",
+				Source:    testEvent.Source,
+				Type:      testEvent.Type,
+			},
+			service: &eventsService{
+				authorize:        alwaysAuthorize,
+				projectAuthorize: alwaysProjectAuthorize,
+				projectsStore: &mockProjectsStore{
+					GetFn: func(context.Context, string) (Project, error) {
+						return Project{}, errors.New("something went wrong")
+					},
+				},
+			},
+			assertions: func(_ EventMatchResult, err error) {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), "something went wrong")
+				require.Contains(t, err.Error(), "error retrieving project")
+			},
+		},
+		{
+			name:  "error listing projects",
+			event: testEvent,
+			service: &eventsService{
+				authorize: alwaysAuthorize,
+				projectsStore: &mockProjectsStore{
+					ListFn: func(
+						context.Context,
+						meta.ListOptions,
+					) (meta.List[Project], error) {
+						return meta.List[Project]{}, errors.New("something went wrong")
+					},
+				},
+			},
+			assertions: func(_ EventMatchResult, err error) {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), "something went wrong")
+				require.Contains(t, err.Error(), "error retrieving projects")
+			},
+		},
+		{
+			name:  "success",
+			event: testEvent,
+			service: &eventsService{
+				authorize: alwaysAuthorize,
+				projectsStore: &mockProjectsStore{
+					ListFn: func(
+						_ context.Context,
+						opts meta.ListOptions,
+					) (meta.List[Project], error) {
+						// Return results over two pages
+						if opts.Continue == "" {
+							subscriber := subscriberTo(testEvent)
+							subscriber.ID = "subscribed"
+							return meta.List[Project]{
+								Items: []Project{subscriber},
+								ListMeta: meta.ListMeta{
+									Continue: "subscribed",
+								},
+							}, nil
+						}
+						notSubscribed := subscriberTo(testEvent)
+						notSubscribed.ID = "not-subscribed"
+						notSubscribed.Spec.EventSubscriptions[0].Types =
+							[]string{"pull_request"}
+						notSubscribed.Spec.EventSubscriptions[0].Labels =
+							map[string]string{
+								"branch": "release",
+							}
+						return meta.List[Project]{
+							Items: []Project{
+								notSubscribed,
+								{
+									ObjectMeta: meta.ObjectMeta{
+										ID: "no-subscriptions",
+									},
+								},
+							},
+						}, nil
+					},
+				},
+				createSingleEventFn: func(
+					context.Context,
+					Project,
+					Event,
+				) (Event, error) {
+					require.Fail(t, "no event should have been created")
+					return Event{}, nil
+				},
+			},
+			assertions: func(result EventMatchResult, err error) {
+				require.NoError(t, err)
+				require.Equal(t, []string{"subscribed"}, result.Subscribers)
+				require.Len(t, result.NonSubscribers, 2)
+				require.Equal(t, "not-subscribed", result.NonSubscribers[0].ProjectID)
+				require.Len(t, result.NonSubscribers[0].Subscriptions, 1)
+				mismatches := result.NonSubscribers[0].Subscriptions[0].Mismatches
+				require.Len(t, mismatches, 2)
+				require.Equal(t, SubscriptionCriterionType, mismatches[0].Criterion)
+				require.Equal(t, SubscriptionCriterionLabel, mismatches[1].Criterion)
+				require.Equal(
+					t,
+					"no-subscriptions",
+					result.NonSubscribers[1].ProjectID,
+				)
+				require.Empty(t, result.NonSubscribers[1].Subscriptions)
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			result, err :=
+				testCase.service.Match(context.Background(), testCase.event)
+			testCase.assertions(result, err)
+		})
+	}
+}
+
+// subscriberTo returns a Project having a single EventSubscription that
+// matches the provided Event.
+func subscriberTo(event Event) Project {
+	return Project{
+		Spec: ProjectSpec{
+			EventSubscriptions: []EventSubscription{
+				{
+					Source:     event.Source,
+					Types:      []string{event.Type},
+					Qualifiers: event.Qualifiers,
+				},
+			},
+		},
+	}
+}
+
 type mockEventsStore struct {
 	CreateFn func(context.Context, Event) error
 	ListFn   func(
@@ -1855,10 +2825,19 @@ type mockEventsStore struct {
 		EventsSelector,
 		meta.ListOptions,
 	) (meta.List[Event], error)
-	GetFn                    func(context.Context, string) (Event, error)
+	GetFn                  func(context.Context, string) (Event, error)
+	ListByIdempotencyKeyFn func(
+		context.Context,
+		string,
+		string,
+	) (meta.List[Event], error)
+	ReleaseIdempotencyKeyFn  func(context.Context, string, string) error
 	GetByHashedWorkerTokenFn func(context.Context, string) (Event, error)
 	UpdateSourceStateFn      func(context.Context, string, SourceState) error
 	UpdateSummaryFn          func(context.Context, string, EventSummary) error
+	UpdateLabelsFn           func(context.Context, string, map[string]string) error
+	PinFn                    func(context.Context, string) error
+	UnpinFn                  func(context.Context, string) error
 	CancelFn                 func(context.Context, string) error
 	CancelManyFn             func(
 		context.Context,
@@ -1870,6 +2849,7 @@ type mockEventsStore struct {
 		EventsSelector,
 	) (<-chan Event, int64, error)
 	DeleteByProjectIDFn func(context.Context, string) error
+	GetQueueDepthFn     func(context.Context, string) (ProjectQueueDepth, error)
 }
 
 func (m *mockEventsStore) Create(ctx context.Context, event Event) error {
@@ -1888,6 +2868,22 @@ func (m *mockEventsStore) Get(ctx context.Context, id string) (Event, error) {
 	return m.GetFn(ctx, id)
 }
 
+func (m *mockEventsStore) ListByIdempotencyKey(
+	ctx context.Context,
+	source string,
+	key string,
+) (meta.List[Event], error) {
+	return m.ListByIdempotencyKeyFn(ctx, source, key)
+}
+
+func (m *mockEventsStore) ReleaseIdempotencyKey(
+	ctx context.Context,
+	source string,
+	key string,
+) error {
+	return m.ReleaseIdempotencyKeyFn(ctx, source, key)
+}
+
 func (m *mockEventsStore) GetByHashedWorkerToken(
 	ctx context.Context,
 	hashedToken string,
@@ -1911,10 +2907,26 @@ func (m *mockEventsStore) UpdateSummary(
 	return m.UpdateSummaryFn(ctx, id, summary)
 }
 
+func (m *mockEventsStore) UpdateLabels(
+	ctx context.Context,
+	id string,
+	labels map[string]string,
+) error {
+	return m.UpdateLabelsFn(ctx, id, labels)
+}
+
 func (m *mockEventsStore) Cancel(ctx context.Context, id string) error {
 	return m.CancelFn(ctx, id)
 }
 
+func (m *mockEventsStore) Pin(ctx context.Context, id string) error {
+	return m.PinFn(ctx, id)
+}
+
+func (m *mockEventsStore) Unpin(ctx context.Context, id string) error {
+	return m.UnpinFn(ctx, id)
+}
+
 func (m *mockEventsStore) CancelMany(
 	ctx context.Context,
 	selector EventsSelector,
@@ -1939,3 +2951,10 @@ func (m *mockEventsStore) DeleteByProjectID(
 ) error {
 	return m.DeleteByProjectIDFn(ctx, projectID)
 }
+
+func (m *mockEventsStore) GetQueueDepth(
+	ctx context.Context,
+	projectID string,
+) (ProjectQueueDepth, error) {
+	return m.GetQueueDepthFn(ctx, projectID)
+}
@@ -0,0 +1,227 @@
+package filesystem
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/brigadecore/brigade/v2/apiserver/internal/api"
+	"github.com/brigadecore/brigade/v2/apiserver/internal/meta"
+	"github.com/pkg/errors"
+)
+
+// artifactsStore is a local filesystem-based implementation of the
+// api.ArtifactsStore interface. Artifacts are stored beneath a root directory
+// using the layout <root>/<project ID>/<event ID>/<job name>/<artifact name>.
+type artifactsStore struct {
+	rootDir string
+}
+
+// NewArtifactsStore returns a local filesystem-based implementation of the
+// api.ArtifactsStore interface that stores Artifacts beneath the specified
+// root directory.
+func NewArtifactsStore(rootDir string) api.ArtifactsStore {
+	return &artifactsStore{
+		rootDir: rootDir,
+	}
+}
+
+func (a *artifactsStore) Put(
+	_ context.Context,
+	event api.Event,
+	jobName string,
+	artifactName string,
+	content io.Reader,
+) (api.Artifact, error) {
+	jobDir := a.jobDir(event, jobName)
+	if err := os.MkdirAll(jobDir, 0755); err != nil {
+		return api.Artifact{}, errors.Wrapf(
+			err,
+			"error creating directory for event %q job %q artifacts",
+			event.ID,
+			jobName,
+		)
+	}
+	// Content is written to a temporary file that is renamed only once the
+	// content has been read in full. This ensures an existing Artifact of the
+	// same name is never replaced by partial content.
+	tmpFile, err := os.CreateTemp(jobDir, ".upload-*")
+	if err != nil {
+		return api.Artifact{}, errors.Wrapf(
+			err,
+			"error creating temporary file for event %q job %q artifact %q",
+			event.ID,
+			jobName,
+			artifactName,
+		)
+	}
+	defer os.Remove(tmpFile.Name()) // Has no effect once the file is renamed
+	if _, err = io.Copy(tmpFile, content); err != nil {
+		tmpFile.Close()
+		return api.Artifact{}, errors.Wrapf(
+			err,
+			"error writing event %q job %q artifact %q",
+			event.ID,
+			jobName,
+			artifactName,
+		)
+	}
+	if err = tmpFile.Close(); err != nil {
+		return api.Artifact{}, errors.Wrapf(
+			err,
+			"error closing temporary file for event %q job %q artifact %q",
+			event.ID,
+			jobName,
+			artifactName,
+		)
+	}
+	artifactPath := filepath.Join(jobDir, artifactName)
+	if err = os.Rename(tmpFile.Name(), artifactPath); err != nil {
+		return api.Artifact{}, errors.Wrapf(
+			err,
+			"error moving event %q job %q artifact %q into place",
+			event.ID,
+			jobName,
+			artifactName,
+		)
+	}
+	fileInfo, err := os.Stat(artifactPath)
+	if err != nil {
+		return api.Artifact{}, errors.Wrapf(
+			err,
+			"error retrieving info for event %q job %q artifact %q",
+			event.ID,
+			jobName,
+			artifactName,
+		)
+	}
+	return artifact(fileInfo), nil
+}
+
+func (a *artifactsStore) List(
+	_ context.Context,
+	event api.Event,
+	jobName string,
+) (meta.List[api.Artifact], error) {
+	artifacts := meta.List[api.Artifact]{
+		Items: []api.Artifact{},
+	}
+	dirEntries, err := os.ReadDir(a.jobDir(event, jobName))
+	if os.IsNotExist(err) {
+		return artifacts, nil
+	}
+	if err != nil {
+		return artifacts, errors.Wrapf(
+			err,
+			"error reading directory for event %q job %q artifacts",
+			event.ID,
+			jobName,
+		)
+	}
+	for _, dirEntry := range dirEntries {
+		// Skip directories and any temporary files left over from uploads that
+		// are in progress.
+		if dirEntry.IsDir() || dirEntry.Name()[0] == '.' {
+			continue
+		}
+		fileInfo, err := dirEntry.Info()
+		if os.IsNotExist(err) {
+			continue // The file was deleted after the directory was read
+		}
+		if err != nil {
+			return artifacts, errors.Wrapf(
+				err,
+				"error retrieving info for event %q job %q artifact %q",
+				event.ID,
+				jobName,
+				dirEntry.Name(),
+			)
+		}
+		artifacts.Items = append(artifacts.Items, artifact(fileInfo))
+	}
+	sort.Slice(artifacts.Items, func(i, j int) bool {
+		return artifacts.Items[i].Name < artifacts.Items[j].Name
+	})
+	return artifacts, nil
+}
+
+func (a *artifactsStore) Get(
+	_ context.Context,
+	event api.Event,
+	jobName string,
+	artifactName string,
+) (api.Artifact, io.ReadCloser, error) {
+	file, err := os.Open(filepath.Join(a.jobDir(event, jobName), artifactName))
+	if os.IsNotExist(err) {
+		return api.Artifact{}, nil, &meta.ErrNotFound{
+			Type: api.ArtifactKind,
+			ID:   artifactName,
+		}
+	}
+	if err != nil {
+		return api.Artifact{}, nil, errors.Wrapf(
+			err,
+			"error opening event %q job %q artifact %q",
+			event.ID,
+			jobName,
+			artifactName,
+		)
+	}
+	fileInfo, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return api.Artifact{}, nil, errors.Wrapf(
+			err,
+			"error retrieving info for event %q job %q artifact %q",
+			event.ID,
+			jobName,
+			artifactName,
+		)
+	}
+	return artifact(fileInfo), file, nil
+}
+
+func (a *artifactsStore) DeleteEventArtifacts(
+	_ context.Context,
+	event api.Event,
+) error {
+	if err := os.RemoveAll(
+		filepath.Join(a.rootDir, event.ProjectID, event.ID),
+	); err != nil {
+		return errors.Wrapf(err, "error deleting artifacts for event %q", event.ID)
+	}
+	return nil
+}
+
+func (a *artifactsStore) DeleteProjectArtifacts(
+	_ context.Context,
+	projectID string,
+) error {
+	if err := os.RemoveAll(filepath.Join(a.rootDir, projectID)); err != nil {
+		return errors.Wrapf(
+			err,
+			"error deleting artifacts for project %q",
+			projectID,
+		)
+	}
+	return nil
+}
+
+// jobDir returns the path to the directory in which the Artifacts of the
+// provided Event's specified Job are stored.
+func (a *artifactsStore) jobDir(event api.Event, jobName string) string {
+	return filepath.Join(a.rootDir, event.ProjectID, event.ID, jobName)
+}
+
+// artifact returns an api.Artifact corresponding to the provided
+// os.FileInfo.
+func artifact(fileInfo os.FileInfo) api.Artifact {
+	modTime := fileInfo.ModTime().UTC()
+	return api.Artifact{
+		Name:    fileInfo.Name(),
+		Size:    fileInfo.Size(),
+		Created: &modTime,
+	}
+}
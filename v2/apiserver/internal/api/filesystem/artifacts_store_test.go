@@ -0,0 +1,203 @@
+package filesystem
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/brigadecore/brigade/v2/apiserver/internal/api"
+	"github.com/brigadecore/brigade/v2/apiserver/internal/meta"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+var testEvent = api.Event{
+	ObjectMeta: meta.ObjectMeta{
+		ID: "123456789",
+	},
+	ProjectID: "blue-book",
+}
+
+const testJobName = "italian"
+
+func TestNewArtifactsStore(t *testing.T) {
+	store, ok := NewArtifactsStore("/var/lib/brigade").(*artifactsStore)
+	require.True(t, ok)
+	require.Equal(t, "/var/lib/brigade", store.rootDir)
+}
+
+func TestArtifactsStorePut(t *testing.T) {
+	ctx := context.Background()
+	store := &artifactsStore{
+		rootDir: t.TempDir(),
+	}
+
+	artifact, err := store.Put(
+		ctx,
+		testEvent,
+		testJobName,
+		"report.xml",
+		strings.NewReader("foo"),
+	)
+	require.NoError(t, err)
+	require.Equal(t, "report.xml", artifact.Name)
+	require.Equal(t, int64(3), artifact.Size)
+	require.NotNil(t, artifact.Created)
+
+	// Overwrite the existing artifact
+	artifact, err = store.Put(
+		ctx,
+		testEvent,
+		testJobName,
+		"report.xml",
+		strings.NewReader("foobar"),
+	)
+	require.NoError(t, err)
+	require.Equal(t, int64(6), artifact.Size)
+
+	// A failed read must leave the existing artifact intact
+	_, err = store.Put(
+		ctx,
+		testEvent,
+		testJobName,
+		"report.xml",
+		&errReader{err: errors.New("something went wrong")},
+	)
+	require.Error(t, err)
+	require.Equal(t, "something went wrong", errors.Cause(err).Error())
+	content, err := os.ReadFile(
+		filepath.Join(
+			store.rootDir,
+			testEvent.ProjectID,
+			testEvent.ID,
+			testJobName,
+			"report.xml",
+		),
+	)
+	require.NoError(t, err)
+	require.Equal(t, "foobar", string(content))
+
+	// No temporary files should have been left behind
+	artifacts, err := store.List(ctx, testEvent, testJobName)
+	require.NoError(t, err)
+	require.Len(t, artifacts.Items, 1)
+	dirEntries, err := os.ReadDir(
+		filepath.Join(store.rootDir, testEvent.ProjectID, testEvent.ID, testJobName),
+	)
+	require.NoError(t, err)
+	require.Len(t, dirEntries, 1)
+}
+
+func TestArtifactsStoreList(t *testing.T) {
+	ctx := context.Background()
+	store := &artifactsStore{
+		rootDir: t.TempDir(),
+	}
+
+	// No artifacts yet
+	artifacts, err := store.List(ctx, testEvent, testJobName)
+	require.NoError(t, err)
+	require.Empty(t, artifacts.Items)
+
+	for _, name := range []string{"report.xml", "coverage.out"} {
+		_, err = store.Put(
+			ctx,
+			testEvent,
+			testJobName,
+			name,
+			strings.NewReader(name),
+		)
+		require.NoError(t, err)
+	}
+	artifacts, err = store.List(ctx, testEvent, testJobName)
+	require.NoError(t, err)
+	require.Len(t, artifacts.Items, 2)
+	require.Equal(t, "coverage.out", artifacts.Items[0].Name)
+	require.Equal(t, "report.xml", artifacts.Items[1].Name)
+}
+
+func TestArtifactsStoreGet(t *testing.T) {
+	ctx := context.Background()
+	store := &artifactsStore{
+		rootDir: t.TempDir(),
+	}
+
+	_, _, err := store.Get(ctx, testEvent, testJobName, "report.xml")
+	require.Error(t, err)
+	require.IsType(t, &meta.ErrNotFound{}, err)
+	require.Equal(t, api.ArtifactKind, err.(*meta.ErrNotFound).Type)
+
+	_, err = store.Put(
+		ctx,
+		testEvent,
+		testJobName,
+		"report.xml",
+		strings.NewReader("foo"),
+	)
+	require.NoError(t, err)
+	artifact, reader, err := store.Get(ctx, testEvent, testJobName, "report.xml")
+	require.NoError(t, err)
+	defer reader.Close()
+	require.Equal(t, "report.xml", artifact.Name)
+	require.Equal(t, int64(3), artifact.Size)
+	content, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	require.Equal(t, "foo", string(content))
+}
+
+func TestArtifactsStoreDeleteEventArtifacts(t *testing.T) {
+	ctx := context.Background()
+	store := &artifactsStore{
+		rootDir: t.TempDir(),
+	}
+	otherEvent := testEvent
+	otherEvent.ID = "abcdefghi"
+	for _, event := range []api.Event{testEvent, otherEvent} {
+		_, err := store.Put(
+			ctx,
+			event,
+			testJobName,
+			"report.xml",
+			strings.NewReader("foo"),
+		)
+		require.NoError(t, err)
+	}
+	err := store.DeleteEventArtifacts(ctx, testEvent)
+	require.NoError(t, err)
+	artifacts, err := store.List(ctx, testEvent, testJobName)
+	require.NoError(t, err)
+	require.Empty(t, artifacts.Items)
+	artifacts, err = store.List(ctx, otherEvent, testJobName)
+	require.NoError(t, err)
+	require.Len(t, artifacts.Items, 1)
+}
+
+func TestArtifactsStoreDeleteProjectArtifacts(t *testing.T) {
+	ctx := context.Background()
+	store := &artifactsStore{
+		rootDir: t.TempDir(),
+	}
+	_, err := store.Put(
+		ctx,
+		testEvent,
+		testJobName,
+		"report.xml",
+		strings.NewReader("foo"),
+	)
+	require.NoError(t, err)
+	err = store.DeleteProjectArtifacts(ctx, testEvent.ProjectID)
+	require.NoError(t, err)
+	_, err = os.Stat(filepath.Join(store.rootDir, testEvent.ProjectID))
+	require.True(t, os.IsNotExist(err))
+}
+
+type errReader struct {
+	err error
+}
+
+func (e *errReader) Read([]byte) (int, error) {
+	return 0, e.err
+}
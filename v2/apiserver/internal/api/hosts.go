@@ -0,0 +1,382 @@
+package api
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/brigadecore/brigade/v2/apiserver/internal/meta"
+)
+
+// TolerationOperator represents how a Toleration's value is compared to that
+// of a taint.
+type TolerationOperator string
+
+const (
+	// TolerationOperatorEqual represents a Toleration that matches taints having
+	// the same key and value.
+	TolerationOperatorEqual TolerationOperator = "Equal"
+	// TolerationOperatorExists represents a Toleration that matches taints
+	// having the same key, regardless of value.
+	TolerationOperatorExists TolerationOperator = "Exists"
+)
+
+// TaintEffect represents the effect a taint has on Workers and Jobs that do
+// not tolerate it.
+type TaintEffect string
+
+const (
+	// TaintEffectNoSchedule represents a taint that prevents Workers and Jobs
+	// that do not tolerate it from being hosted on a substrate node.
+	TaintEffectNoSchedule TaintEffect = "NoSchedule"
+	// TaintEffectPreferNoSchedule represents a taint that discourages Workers
+	// and Jobs that do not tolerate it from being hosted on a substrate node.
+	TaintEffectPreferNoSchedule TaintEffect = "PreferNoSchedule"
+	// TaintEffectNoExecute represents a taint that evicts Workers and Jobs that
+	// do not tolerate it from a substrate node.
+	TaintEffectNoExecute TaintEffect = "NoExecute"
+)
+
+// Toleration permits a Worker or Job to be hosted on substrate nodes having a
+// matching taint.
+type Toleration struct {
+	// Key is the taint key the Toleration applies to. An empty key with the
+	// Exists operator matches all taints.
+	Key string `json:"key,omitempty" bson:"key,omitempty"`
+	// Operator specifies how the Toleration's value is compared to that of a
+	// taint. If not specified, this defaults to Equal.
+	Operator TolerationOperator `json:"operator,omitempty" bson:"operator,omitempty"` // nolint: lll
+	// Value is the taint value the Toleration matches. It must be empty if the
+	// Operator is Exists.
+	Value string `json:"value,omitempty" bson:"value,omitempty"`
+	// Effect optionally specifies the taint effect the Toleration matches. If
+	// not specified, the Toleration matches all taint effects.
+	Effect TaintEffect `json:"effect,omitempty" bson:"effect,omitempty"`
+}
+
+// permits returns true if the Toleration, used as a policy, permits the
+// provided Toleration to be requested and false otherwise. This is the case if
+// every taint the provided Toleration matches is also matched by this one.
+func (t Toleration) permits(t2 Toleration) bool {
+	if t.Operator == TolerationOperatorExists && t.Key == "" {
+		return t.Effect == "" || t.Effect == t2.Effect
+	}
+	if t.Key != t2.Key {
+		return false
+	}
+	if t.Operator != TolerationOperatorExists &&
+		(t2.Operator == TolerationOperatorExists || t.Value != t2.Value) {
+		return false
+	}
+	return t.Effect == "" || t.Effect == t2.Effect
+}
+
+// NodeSelectorOperator represents how the value of a substrate node's label is
+// compared to the values in a NodeSelectorRequirement.
+type NodeSelectorOperator string
+
+const (
+	// NodeSelectorOperatorIn requires the label's value to be one of the
+	// requirement's values.
+	NodeSelectorOperatorIn NodeSelectorOperator = "In"
+	// NodeSelectorOperatorNotIn requires the label's value to be none of the
+	// requirement's values, or the label to be absent.
+	NodeSelectorOperatorNotIn NodeSelectorOperator = "NotIn"
+	// NodeSelectorOperatorExists requires the label to be present.
+	NodeSelectorOperatorExists NodeSelectorOperator = "Exists"
+	// NodeSelectorOperatorDoesNotExist requires the label to be absent.
+	NodeSelectorOperatorDoesNotExist NodeSelectorOperator = "DoesNotExist"
+	// NodeSelectorOperatorGt requires the label's value, interpreted as an
+	// integer, to be greater than the requirement's one value.
+	NodeSelectorOperatorGt NodeSelectorOperator = "Gt"
+	// NodeSelectorOperatorLt requires the label's value, interpreted as an
+	// integer, to be less than the requirement's one value.
+	NodeSelectorOperatorLt NodeSelectorOperator = "Lt"
+)
+
+// NodeSelectorRequirement represents a requirement placed on the value of a
+// substrate node's label.
+type NodeSelectorRequirement struct {
+	// Key is the label key the requirement applies to.
+	Key string `json:"key" bson:"key"`
+	// Operator specifies how the label's value is compared to Values.
+	Operator NodeSelectorOperator `json:"operator" bson:"operator"`
+	// Values must be non-empty if the Operator is In or NotIn, must be empty if
+	// the Operator is Exists or DoesNotExist, and must contain exactly one
+	// integer if the Operator is Gt or Lt.
+	Values []string `json:"values,omitempty" bson:"values,omitempty"`
+}
+
+// PreferredNodeSelectorRequirement represents a NodeSelectorRequirement that
+// substrate nodes should preferably, but need not, satisfy.
+type PreferredNodeSelectorRequirement struct {
+	// Weight indicates how strongly the requirement is preferred, relative to
+	// other preferred requirements. It must be between 1 and 100.
+	Weight int `json:"weight" bson:"weight"`
+	// NodeSelectorRequirement is the preferred requirement.
+	NodeSelectorRequirement `json:",inline" bson:",inline"`
+}
+
+// NodeAffinity represents requirements, beyond simple node selectors, that a
+// substrate node must or should satisfy to host a Worker or Job. Note that
+// anti-affinity is expressed using the NotIn and DoesNotExist operators.
+type NodeAffinity struct {
+	// Required enumerates requirements that a substrate node must satisfy, in
+	// their entirety, to host the Worker or Job.
+	Required []NodeSelectorRequirement `json:"required,omitempty" bson:"required,omitempty"` // nolint: lll
+	// Preferred enumerates requirements that substrate nodes should preferably
+	// satisfy to host the Worker or Job.
+	Preferred []PreferredNodeSelectorRequirement `json:"preferred,omitempty" bson:"preferred,omitempty"` // nolint: lll
+}
+
+// validateHostConstraints returns details of any problems with the provided
+// Tolerations and NodeAffinity.
+func validateHostConstraints(
+	tolerations []Toleration,
+	affinity *NodeAffinity,
+) []string {
+	details := validateTolerations(tolerations)
+	if affinity == nil {
+		return details
+	}
+	for i, requirement := range affinity.Required {
+		details = append(
+			details,
+			validateNodeSelectorRequirement(
+				fmt.Sprintf("required node affinity %d", i),
+				requirement,
+			)...,
+		)
+	}
+	for i, requirement := range affinity.Preferred {
+		field := fmt.Sprintf("preferred node affinity %d", i)
+		if requirement.Weight < 1 || requirement.Weight > 100 {
+			details = append(
+				details,
+				fmt.Sprintf("%s weight must be between 1 and 100", field),
+			)
+		}
+		details = append(
+			details,
+			validateNodeSelectorRequirement(
+				field,
+				requirement.NodeSelectorRequirement,
+			)...,
+		)
+	}
+	return details
+}
+
+// validateTolerations returns details of any problems with the provided
+// Tolerations.
+func validateTolerations(tolerations []Toleration) []string {
+	details := []string{}
+	for i, toleration := range tolerations {
+		switch toleration.Operator {
+		case TolerationOperatorExists:
+			if toleration.Value != "" {
+				details = append(
+					details,
+					fmt.Sprintf(
+						"toleration %d must not specify a value when its operator is %q",
+						i,
+						TolerationOperatorExists,
+					),
+				)
+			}
+		case "", TolerationOperatorEqual:
+			if toleration.Key == "" {
+				details = append(
+					details,
+					fmt.Sprintf(
+						"toleration %d must specify a key unless its operator is %q",
+						i,
+						TolerationOperatorExists,
+					),
+				)
+			}
+		default:
+			details = append(
+				details,
+				fmt.Sprintf(
+					"toleration %d operator %q is invalid",
+					i,
+					toleration.Operator,
+				),
+			)
+		}
+		switch toleration.Effect {
+		case "",
+			TaintEffectNoSchedule,
+			TaintEffectPreferNoSchedule,
+			TaintEffectNoExecute:
+		default:
+			details = append(
+				details,
+				fmt.Sprintf("toleration %d effect %q is invalid", i, toleration.Effect),
+			)
+		}
+	}
+	return details
+}
+
+// validateNodeSelectorRequirement returns details of any problems with the
+// provided NodeSelectorRequirement, prefixed with the provided field name.
+func validateNodeSelectorRequirement(
+	field string,
+	requirement NodeSelectorRequirement,
+) []string {
+	details := []string{}
+	if requirement.Key == "" {
+		details = append(details, fmt.Sprintf("%s must specify a key", field))
+	}
+	switch requirement.Operator {
+	case NodeSelectorOperatorIn, NodeSelectorOperatorNotIn:
+		if len(requirement.Values) == 0 {
+			details = append(
+				details,
+				fmt.Sprintf(
+					"%s must specify values when its operator is %q",
+					field,
+					requirement.Operator,
+				),
+			)
+		}
+	case NodeSelectorOperatorExists, NodeSelectorOperatorDoesNotExist:
+		if len(requirement.Values) > 0 {
+			details = append(
+				details,
+				fmt.Sprintf(
+					"%s must not specify values when its operator is %q",
+					field,
+					requirement.Operator,
+				),
+			)
+		}
+	case NodeSelectorOperatorGt, NodeSelectorOperatorLt:
+		if len(requirement.Values) != 1 {
+			details = append(
+				details,
+				fmt.Sprintf(
+					"%s must specify exactly one value when its operator is %q",
+					field,
+					requirement.Operator,
+				),
+			)
+		} else if _, err :=
+			strconv.ParseInt(requirement.Values[0], 10, 64); err != nil {
+			details = append(
+				details,
+				fmt.Sprintf(
+					"%s value %q is not an integer",
+					field,
+					requirement.Values[0],
+				),
+			)
+		}
+	default:
+		details = append(
+			details,
+			fmt.Sprintf("%s operator %q is invalid", field, requirement.Operator),
+		)
+	}
+	return details
+}
+
+// validateJobHost returns a *meta.ErrBadRequest error if the provided JobHost
+// specifies invalid scheduling constraints.
+func validateJobHost(host *JobHost) error {
+	if host == nil {
+		return nil
+	}
+	details := validateHostConstraints(host.Tolerations, host.NodeAffinity)
+	if len(details) > 0 {
+		return &meta.ErrBadRequest{
+			Reason:  "Invalid job host.",
+			Details: details,
+		}
+	}
+	return nil
+}
+
+// validateWorkerHostAndJobPolicies returns a *meta.ErrBadRequest error if the
+// provided WorkerSpec's WorkerHost specifies invalid scheduling constraints or
+// if its JobPolicies permit invalid Tolerations.
+func validateWorkerHostAndJobPolicies(workerSpec WorkerSpec) error {
+	details := []string{}
+	if workerSpec.Host != nil {
+		details = append(
+			details,
+			validateHostConstraints(
+				workerSpec.Host.Tolerations,
+				workerSpec.Host.NodeAffinity,
+			)...,
+		)
+	}
+	if workerSpec.JobPolicies != nil {
+		for _, detail := range validateTolerations(
+			workerSpec.JobPolicies.AllowedTolerations,
+		) {
+			details = append(details, fmt.Sprintf("allowed %s", detail))
+		}
+	}
+	if len(details) > 0 {
+		return &meta.ErrBadRequest{
+			Reason:  "Invalid worker host or job policies.",
+			Details: details,
+		}
+	}
+	return nil
+}
+
+// authorizeJobHost returns a *meta.ErrAuthorization error if the provided
+// JobPolicies do not permit the scheduling constraints specified by the
+// provided JobHost.
+func authorizeJobHost(host *JobHost, policies *JobPolicies) error {
+	if host == nil {
+		return nil
+	}
+	if policies == nil {
+		policies = &JobPolicies{}
+	}
+	for _, toleration := range host.Tolerations {
+		var permitted bool
+		for _, allowedToleration := range policies.AllowedTolerations {
+			if allowedToleration.permits(toleration) {
+				permitted = true
+				break
+			}
+		}
+		if !permitted {
+			return &meta.ErrAuthorization{
+				Reason: fmt.Sprintf(
+					"Worker configuration forbids jobs from tolerating taint %q.",
+					toleration.Key,
+				),
+			}
+		}
+	}
+	if host.NodeAffinity != nil && !policies.AllowNodeAffinity {
+		return &meta.ErrAuthorization{
+			Reason: "Worker configuration forbids jobs from specifying node " +
+				"affinity.",
+		}
+	}
+	if host.PriorityClassName != "" {
+		var permitted bool
+		for _, priorityClassName := range policies.AllowedPriorityClassNames {
+			if priorityClassName == host.PriorityClassName {
+				permitted = true
+				break
+			}
+		}
+		if !permitted {
+			return &meta.ErrAuthorization{
+				Reason: fmt.Sprintf(
+					"Worker configuration forbids jobs from using priority class %q.",
+					host.PriorityClassName,
+				),
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,465 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/brigadecore/brigade/v2/apiserver/internal/meta"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTolerationPermits(t *testing.T) {
+	testCases := []struct {
+		name       string
+		policy     Toleration
+		toleration Toleration
+		permitted  bool
+	}{
+		{
+			name: "policy tolerates everything",
+			policy: Toleration{
+				Operator: TolerationOperatorExists,
+			},
+			toleration: Toleration{
+				Key:      "gpu",
+				Operator: TolerationOperatorExists,
+			},
+			permitted: true,
+		},
+		{
+			name: "policy tolerates everything with a specific effect",
+			policy: Toleration{
+				Operator: TolerationOperatorExists,
+				Effect:   TaintEffectNoSchedule,
+			},
+			toleration: Toleration{
+				Key:      "gpu",
+				Operator: TolerationOperatorExists,
+			},
+			permitted: false,
+		},
+		{
+			name: "keys differ",
+			policy: Toleration{
+				Key:      "gpu",
+				Operator: TolerationOperatorExists,
+			},
+			toleration: Toleration{
+				Key:      "spot",
+				Operator: TolerationOperatorExists,
+			},
+			permitted: false,
+		},
+		{
+			name: "policy tolerates any value",
+			policy: Toleration{
+				Key:      "gpu",
+				Operator: TolerationOperatorExists,
+			},
+			toleration: Toleration{
+				Key:   "gpu",
+				Value: "nvidia",
+			},
+			permitted: true,
+		},
+		{
+			name: "policy tolerates a specific value",
+			policy: Toleration{
+				Key:   "gpu",
+				Value: "nvidia",
+			},
+			toleration: Toleration{
+				Key:      "gpu",
+				Operator: TolerationOperatorEqual,
+				Value:    "nvidia",
+			},
+			permitted: true,
+		},
+		{
+			name: "values differ",
+			policy: Toleration{
+				Key:   "gpu",
+				Value: "nvidia",
+			},
+			toleration: Toleration{
+				Key:   "gpu",
+				Value: "amd",
+			},
+			permitted: false,
+		},
+		{
+			name: "toleration is broader than policy",
+			policy: Toleration{
+				Key:   "gpu",
+				Value: "nvidia",
+			},
+			toleration: Toleration{
+				Key:      "gpu",
+				Operator: TolerationOperatorExists,
+			},
+			permitted: false,
+		},
+		{
+			name: "effects differ",
+			policy: Toleration{
+				Key:      "gpu",
+				Operator: TolerationOperatorExists,
+				Effect:   TaintEffectNoSchedule,
+			},
+			toleration: Toleration{
+				Key:      "gpu",
+				Operator: TolerationOperatorExists,
+				Effect:   TaintEffectNoExecute,
+			},
+			permitted: false,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			require.Equal(
+				t,
+				testCase.permitted,
+				testCase.policy.permits(testCase.toleration),
+			)
+		})
+	}
+}
+
+func TestValidateJobHost(t *testing.T) {
+	testCases := []struct {
+		name       string
+		host       *JobHost
+		assertions func(error)
+	}{
+		{
+			name: "nil host",
+			host: nil,
+			assertions: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "invalid host",
+			host: &JobHost{
+				Tolerations: []Toleration{
+					{
+						Operator: TolerationOperatorEqual,
+					},
+					{
+						Key:      "gpu",
+						Operator: TolerationOperatorExists,
+						Value:    "nvidia",
+						Effect:   "Sometimes",
+					},
+					{
+						Key:      "gpu",
+						Operator: "Maybe",
+					},
+				},
+				NodeAffinity: &NodeAffinity{
+					Required: []NodeSelectorRequirement{
+						{
+							Operator: NodeSelectorOperatorIn,
+						},
+						{
+							Key:      "spot",
+							Operator: NodeSelectorOperatorExists,
+							Values:   []string{"true"},
+						},
+						{
+							Key:      "cores",
+							Operator: NodeSelectorOperatorGt,
+							Values:   []string{"lots"},
+						},
+					},
+					Preferred: []PreferredNodeSelectorRequirement{
+						{
+							Weight: 101,
+							NodeSelectorRequirement: NodeSelectorRequirement{
+								Key:      "disk",
+								Operator: "Like",
+							},
+						},
+					},
+				},
+			},
+			assertions: func(err error) {
+				require.Error(t, err)
+				require.IsType(t, &meta.ErrBadRequest{}, err)
+				badReqErr := err.(*meta.ErrBadRequest)
+				require.Equal(t, "Invalid job host.", badReqErr.Reason)
+				require.Equal(
+					t,
+					[]string{
+						`toleration 0 must specify a key unless its operator is "Exists"`,
+						`toleration 1 must not specify a value when its operator is ` +
+							`"Exists"`,
+						`toleration 1 effect "Sometimes" is invalid`,
+						`toleration 2 operator "Maybe" is invalid`,
+						"required node affinity 0 must specify a key",
+						`required node affinity 0 must specify values when its ` +
+							`operator is "In"`,
+						`required node affinity 1 must not specify values when its ` +
+							`operator is "Exists"`,
+						`required node affinity 2 value "lots" is not an integer`,
+						"preferred node affinity 0 weight must be between 1 and 100",
+						`preferred node affinity 0 operator "Like" is invalid`,
+					},
+					badReqErr.Details,
+				)
+			},
+		},
+		{
+			name: "valid host",
+			host: &JobHost{
+				Tolerations: []Toleration{
+					{
+						Key:    "gpu",
+						Value:  "nvidia",
+						Effect: TaintEffectNoSchedule,
+					},
+				},
+				NodeAffinity: &NodeAffinity{
+					Required: []NodeSelectorRequirement{
+						{
+							Key:      "zone",
+							Operator: NodeSelectorOperatorNotIn,
+							Values:   []string{"us-east-1a"},
+						},
+					},
+					Preferred: []PreferredNodeSelectorRequirement{
+						{
+							Weight: 50,
+							NodeSelectorRequirement: NodeSelectorRequirement{
+								Key:      "cores",
+								Operator: NodeSelectorOperatorGt,
+								Values:   []string{"8"},
+							},
+						},
+					},
+				},
+			},
+			assertions: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.assertions(validateJobHost(testCase.host))
+		})
+	}
+}
+
+func TestValidateWorkerHostAndJobPolicies(t *testing.T) {
+	testCases := []struct {
+		name       string
+		workerSpec WorkerSpec
+		assertions func(error)
+	}{
+		{
+			name:       "no host or job policies",
+			workerSpec: WorkerSpec{},
+			assertions: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "invalid host and job policies",
+			workerSpec: WorkerSpec{
+				Host: &WorkerHost{
+					NodeAffinity: &NodeAffinity{
+						Required: []NodeSelectorRequirement{
+							{
+								Key:      "cores",
+								Operator: NodeSelectorOperatorLt,
+							},
+						},
+					},
+				},
+				JobPolicies: &JobPolicies{
+					AllowedTolerations: []Toleration{
+						{
+							Value: "nvidia",
+						},
+					},
+				},
+			},
+			assertions: func(err error) {
+				require.Error(t, err)
+				require.IsType(t, &meta.ErrBadRequest{}, err)
+				badReqErr := err.(*meta.ErrBadRequest)
+				require.Equal(
+					t,
+					"Invalid worker host or job policies.",
+					badReqErr.Reason,
+				)
+				require.Equal(
+					t,
+					[]string{
+						`required node affinity 0 must specify exactly one value when ` +
+							`its operator is "Lt"`,
+						`allowed toleration 0 must specify a key unless its operator ` +
+							`is "Exists"`,
+					},
+					badReqErr.Details,
+				)
+			},
+		},
+		{
+			name: "valid host and job policies",
+			workerSpec: WorkerSpec{
+				Host: &WorkerHost{
+					Tolerations: []Toleration{
+						{
+							Key:      "workers",
+							Operator: TolerationOperatorExists,
+						},
+					},
+				},
+				JobPolicies: &JobPolicies{
+					AllowedTolerations: []Toleration{
+						{
+							Operator: TolerationOperatorExists,
+						},
+					},
+				},
+			},
+			assertions: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.assertions(
+				validateWorkerHostAndJobPolicies(testCase.workerSpec),
+			)
+		})
+	}
+}
+
+func TestAuthorizeJobHost(t *testing.T) {
+	testHost := &JobHost{
+		Tolerations: []Toleration{
+			{
+				Key:    "gpu",
+				Value:  "nvidia",
+				Effect: TaintEffectNoSchedule,
+			},
+		},
+		NodeAffinity: &NodeAffinity{
+			Required: []NodeSelectorRequirement{
+				{
+					Key:      "zone",
+					Operator: NodeSelectorOperatorIn,
+					Values:   []string{"us-east-1a"},
+				},
+			},
+		},
+		PriorityClassName: "high",
+	}
+	testCases := []struct {
+		name       string
+		host       *JobHost
+		policies   *JobPolicies
+		assertions func(error)
+	}{
+		{
+			name: "nil host",
+			host: nil,
+			assertions: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "host with only a node selector",
+			host: &JobHost{
+				NodeSelector: map[string]string{
+					"disk": "ssd",
+				},
+			},
+			assertions: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "toleration not allowed",
+			host: testHost,
+			assertions: func(err error) {
+				require.Error(t, err)
+				require.IsType(t, &meta.ErrAuthorization{}, err)
+				require.Equal(
+					t,
+					`Worker configuration forbids jobs from tolerating taint "gpu".`,
+					err.(*meta.ErrAuthorization).Reason,
+				)
+			},
+		},
+		{
+			name: "node affinity not allowed",
+			host: testHost,
+			policies: &JobPolicies{
+				AllowedTolerations: []Toleration{
+					{
+						Key:      "gpu",
+						Operator: TolerationOperatorExists,
+					},
+				},
+			},
+			assertions: func(err error) {
+				require.Error(t, err)
+				require.IsType(t, &meta.ErrAuthorization{}, err)
+				require.Equal(
+					t,
+					"Worker configuration forbids jobs from specifying node affinity.",
+					err.(*meta.ErrAuthorization).Reason,
+				)
+			},
+		},
+		{
+			name: "priority class not allowed",
+			host: testHost,
+			policies: &JobPolicies{
+				AllowedTolerations: []Toleration{
+					{
+						Key:      "gpu",
+						Operator: TolerationOperatorExists,
+					},
+				},
+				AllowNodeAffinity:         true,
+				AllowedPriorityClassNames: []string{"low"},
+			},
+			assertions: func(err error) {
+				require.Error(t, err)
+				require.IsType(t, &meta.ErrAuthorization{}, err)
+				require.Equal(
+					t,
+					`Worker configuration forbids jobs from using priority class "high".`,
+					err.(*meta.ErrAuthorization).Reason,
+				)
+			},
+		},
+		{
+			name: "everything allowed",
+			host: testHost,
+			policies: &JobPolicies{
+				AllowedTolerations: []Toleration{
+					{
+						Key:      "gpu",
+						Operator: TolerationOperatorExists,
+					},
+				},
+				AllowNodeAffinity:         true,
+				AllowedPriorityClassNames: []string{"low", "high"},
+			},
+			assertions: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.assertions(authorizeJobHost(testCase.host, testCase.policies))
+		})
+	}
+}
@@ -0,0 +1,293 @@
+package api
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/brigadecore/brigade/v2/apiserver/internal/meta"
+	"github.com/pkg/errors"
+)
+
+const (
+	// defaultJobCacheTTL is how long the results of a cacheable Job remain
+	// eligible for reuse if its JobCachePolicy does not specify otherwise.
+	defaultJobCacheTTL = 7 * 24 * time.Hour
+	// maxJobCacheTTL is the longest that the results of a cacheable Job may
+	// remain eligible for reuse.
+	maxJobCacheTTL = 30 * 24 * time.Hour
+)
+
+// ReasonCached is the Reason recorded in the status of a Job that was never
+// run because the results of an earlier, equivalent Job were reused instead.
+const ReasonCached = "Cached"
+
+// JobCachePolicy opts a Job into cross-Event result caching. If an earlier
+// Event for the same Project already succeeded with an equivalent Job having
+// the same cache key, the Job is not run and the earlier Job's results are
+// reused instead.
+type JobCachePolicy struct {
+	// Key optionally specifies an arbitrary value that, along with the Job's
+	// name and specification, identifies the Job's results. If not specified,
+	// the git commit the Event's Worker is checked out at is used instead. If
+	// neither is available, the Job is not cached.
+	Key string `json:"key,omitempty" bson:"key,omitempty"`
+	// TTLDuration optionally specifies how long the Job's results remain
+	// eligible for reuse. If not specified, this defaults to seven days.
+	TTLDuration string `json:"ttlDuration,omitempty" bson:"ttlDuration,omitempty"` // nolint: lll
+}
+
+// ttl returns how long the results of a Job using this policy remain eligible
+// for reuse. It assumes the policy has already been validated.
+func (j *JobCachePolicy) ttl() time.Duration {
+	if j.TTLDuration == "" {
+		return defaultJobCacheTTL
+	}
+	ttl, _ := time.ParseDuration(j.TTLDuration)
+	return ttl
+}
+
+// validateJobCachePolicy returns a *meta.ErrBadRequest error if the provided
+// JobSpec specifies an invalid JobCachePolicy.
+func validateJobCachePolicy(spec JobSpec) error {
+	if spec.Cache == nil {
+		return nil
+	}
+	details := []string{}
+	if spec.Cache.TTLDuration != "" {
+		if ttl, err := time.ParseDuration(spec.Cache.TTLDuration); err != nil {
+			details = append(
+				details,
+				fmt.Sprintf("invalid ttl duration %q", spec.Cache.TTLDuration),
+			)
+		} else if ttl <= 0 || ttl > maxJobCacheTTL {
+			details = append(
+				details,
+				fmt.Sprintf(
+					"ttl duration %q is not greater than zero and less than or "+
+						"equal to %s",
+					spec.Cache.TTLDuration,
+					maxJobCacheTTL,
+				),
+			)
+		}
+	}
+	// Whatever a Job leaves in the shared workspace for subsequent Jobs would be
+	// missing if the Job's results were reused, so such Jobs cannot be cached.
+	if (Job{Spec: spec}).UsesWorkspace() {
+		details = append(
+			details,
+			"jobs that use the shared workspace cannot be cached",
+		)
+	}
+	if len(details) > 0 {
+		return &meta.ErrBadRequest{
+			Reason:  "Invalid cache policy.",
+			Details: details,
+		}
+	}
+	return nil
+}
+
+// jobCacheKey returns the key that identifies the results of the specified Job
+// of the provided Event. The key is derived from the Job's name, its
+// specification, and either the user-supplied key from the Job's
+// JobCachePolicy or the git commit the Event's Worker is checked out at. If the
+// Job is not cacheable or no key can be derived, it returns false.
+func jobCacheKey(event Event, jobName string, spec JobSpec) (string, bool) {
+	if spec.Cache == nil {
+		return "", false
+	}
+	key := spec.Cache.Key
+	if key == "" {
+		if event.Worker.Spec.Git == nil || event.Worker.Spec.Git.Commit == "" {
+			return "", false
+		}
+		key = fmt.Sprintf("commit:%s", event.Worker.Spec.Git.Commit)
+	}
+	// The cache policy itself is left out so that, for instance, changing the
+	// TTL doesn't invalidate everything already in the cache.
+	spec.Cache = nil
+	specBytes, err := json.Marshal(spec)
+	if err != nil { // This should never happen
+		return "", false
+	}
+	hash := sha256.New()
+	hash.Write([]byte(jobName))
+	hash.Write([]byte{0})
+	hash.Write(specBytes)
+	hash.Write([]byte{0})
+	hash.Write([]byte(key))
+	return fmt.Sprintf("%x", hash.Sum(nil)), true
+}
+
+// JobCacheEntry records the results of a successful, cacheable Job so they may
+// be reused by equivalent Jobs of subsequent Events for the same Project.
+type JobCacheEntry struct {
+	// ProjectID is the identifier of the Project the Job belongs to.
+	ProjectID string `json:"projectID" bson:"projectID"`
+	// Key is the key that identifies the Job's results.
+	Key string `json:"key" bson:"key"`
+	// EventID is the identifier of the Event whose Job produced the results.
+	EventID string `json:"eventID" bson:"eventID"`
+	// JobName is the name of the Job that produced the results.
+	JobName string `json:"jobName" bson:"jobName"`
+	// Started is the time at which the Job that produced the results started.
+	Started *time.Time `json:"started,omitempty" bson:"started,omitempty"`
+	// Ended is the time at which the Job that produced the results ended.
+	Ended *time.Time `json:"ended,omitempty" bson:"ended,omitempty"`
+	// Outputs are the outputs reported by the Job that produced the results.
+	Outputs JobOutputValues `json:"outputs,omitempty" bson:"outputs,omitempty"`
+	// Expires is the time after which the results are no longer eligible for
+	// reuse.
+	Expires time.Time `json:"expires" bson:"expires"`
+}
+
+// cachedJobStatus returns the status the provided Job of the provided Event
+// should assume if the results of an earlier, equivalent Job can be reused. If
+// no such results exist, it returns nil.
+func (j *jobsService) cachedJobStatus(
+	ctx context.Context,
+	event Event,
+	job Job,
+) (*JobStatus, error) {
+	if job.CacheKey == "" {
+		return nil, nil
+	}
+	entry, err := j.jobCacheStore.Get(ctx, event.ProjectID, job.CacheKey)
+	if err != nil {
+		if _, ok := errors.Cause(err).(*meta.ErrNotFound); ok {
+			return nil, nil
+		}
+		return nil, errors.Wrapf(
+			err,
+			"error retrieving project %q job cache entry from store",
+			event.ProjectID,
+		)
+	}
+	// The Event whose Job produced the results may since have been deleted, in
+	// which case its logs have been deleted too and the results can't be reused.
+	if _, err = j.eventsStore.Get(ctx, entry.EventID); err != nil {
+		if _, ok := errors.Cause(err).(*meta.ErrNotFound); ok {
+			return nil, nil
+		}
+		return nil, errors.Wrapf(
+			err,
+			"error retrieving event %q from store",
+			entry.EventID,
+		)
+	}
+	return &JobStatus{
+		Started:     entry.Started,
+		Ended:       entry.Ended,
+		Phase:       JobPhaseSucceeded,
+		Reason:      ReasonCached,
+		Message:     fmt.Sprintf("Results were reused from event %q.", entry.EventID),
+		LogsEventID: entry.EventID,
+		Outputs:     entry.Outputs,
+	}, nil
+}
+
+// cacheJobResults records the results of the provided Job of the provided
+// Event, which has just succeeded with the provided status, if the Job is
+// cacheable.
+func (j *jobsService) cacheJobResults(
+	ctx context.Context,
+	event Event,
+	job Job,
+	status JobStatus,
+) error {
+	// Results that were themselves reused or inherited are already cached
+	if job.CacheKey == "" || status.LogsEventID != "" {
+		return nil
+	}
+	return errors.Wrapf(
+		j.jobCacheStore.Put(
+			ctx,
+			JobCacheEntry{
+				ProjectID: event.ProjectID,
+				Key:       job.CacheKey,
+				EventID:   event.ID,
+				JobName:   job.Name,
+				Started:   status.Started,
+				Ended:     status.Ended,
+				Outputs:   status.Outputs,
+				Expires:   time.Now().UTC().Add(job.Spec.Cache.ttl()),
+			},
+		),
+		"error storing project %q job cache entry",
+		event.ProjectID,
+	)
+}
+
+// JobCacheService is the specialized interface for managing Projects' cached
+// Job results. It's decoupled from underlying technology choices (e.g. data
+// store, message bus, etc.) to keep business logic reusable and consistent
+// while the underlying tech stack remains free to change.
+type JobCacheService interface {
+	// Purge discards all of the specified Project's cached Job results. If the
+	// specified Project does not exist, implementations MUST return a
+	// *meta.ErrNotFound error.
+	Purge(ctx context.Context, projectID string) error
+}
+
+type jobCacheService struct {
+	projectAuthorize ProjectAuthorizeFn
+	projectsStore    ProjectsStore
+	jobCacheStore    JobCacheStore
+}
+
+// NewJobCacheService returns a specialized interface for managing Projects'
+// cached Job results.
+func NewJobCacheService(
+	projectAuthorize ProjectAuthorizeFn,
+	projectsStore ProjectsStore,
+	jobCacheStore JobCacheStore,
+) JobCacheService {
+	return &jobCacheService{
+		projectAuthorize: projectAuthorize,
+		projectsStore:    projectsStore,
+		jobCacheStore:    jobCacheStore,
+	}
+}
+
+func (j *jobCacheService) Purge(ctx context.Context, projectID string) error {
+	if err :=
+		j.projectAuthorize(ctx, projectID, RoleProjectDeveloper); err != nil {
+		return err
+	}
+
+	if _, err := j.projectsStore.Get(ctx, projectID); err != nil {
+		return errors.Wrapf(
+			err,
+			"error retrieving project %q from store",
+			projectID,
+		)
+	}
+
+	if err := j.jobCacheStore.DeleteByProjectID(ctx, projectID); err != nil {
+		return errors.Wrapf(
+			err,
+			"error purging job cache for project %q",
+			projectID,
+		)
+	}
+	return nil
+}
+
+// JobCacheStore is an interface for components that implement JobCacheEntry
+// persistence concerns.
+type JobCacheStore interface {
+	// Get retrieves the unexpired JobCacheEntry identified by the specified
+	// Project and key. If no such JobCacheEntry exists, implementations MUST
+	// return a *meta.ErrNotFound error.
+	Get(ctx context.Context, projectID string, key string) (JobCacheEntry, error)
+	// Put stores the provided JobCacheEntry, replacing any existing
+	// JobCacheEntry having the same Project and key.
+	Put(ctx context.Context, entry JobCacheEntry) error
+	// DeleteByProjectID deletes all JobCacheEntries belonging to the specified
+	// Project.
+	DeleteByProjectID(ctx context.Context, projectID string) error
+}
@@ -0,0 +1,620 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/brigadecore/brigade/v2/apiserver/internal/meta"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJobCachePolicyTTL(t *testing.T) {
+	require.Equal(t, defaultJobCacheTTL, (&JobCachePolicy{}).ttl())
+	require.Equal(
+		t,
+		10*time.Hour,
+		(&JobCachePolicy{TTLDuration: "10h"}).ttl(),
+	)
+}
+
+func TestValidateJobCachePolicy(t *testing.T) {
+	testCases := []struct {
+		name       string
+		spec       JobSpec
+		assertions func(error)
+	}{
+		{
+			name: "no cache policy",
+			assertions: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "invalid cache policy",
+			spec: JobSpec{
+				PrimaryContainer: JobContainerSpec{
+					WorkspaceMountPath: "/var/workspace",
+				},
+				Cache: &JobCachePolicy{
+					TTLDuration: "forever",
+				},
+			},
+			assertions: func(err error) {
+				require.Error(t, err)
+				require.IsType(t, &meta.ErrBadRequest{}, err)
+				badReqErr := err.(*meta.ErrBadRequest)
+				require.Equal(t, "Invalid cache policy.", badReqErr.Reason)
+				require.Equal(
+					t,
+					[]string{
+						`invalid ttl duration "forever"`,
+						"jobs that use the shared workspace cannot be cached",
+					},
+					badReqErr.Details,
+				)
+			},
+		},
+		{
+			name: "ttl too long",
+			spec: JobSpec{
+				Cache: &JobCachePolicy{
+					TTLDuration: "1000h",
+				},
+			},
+			assertions: func(err error) {
+				require.Error(t, err)
+				require.IsType(t, &meta.ErrBadRequest{}, err)
+				require.Len(t, err.(*meta.ErrBadRequest).Details, 1)
+			},
+		},
+		{
+			name: "valid cache policy",
+			spec: JobSpec{
+				Cache: &JobCachePolicy{
+					Key:         "foo",
+					TTLDuration: "24h",
+				},
+			},
+			assertions: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.assertions(validateJobCachePolicy(testCase.spec))
+		})
+	}
+}
+
+func TestJobCacheKey(t *testing.T) {
+	event := Event{
+		Worker: Worker{
+			Spec: WorkerSpec{
+				Git: &GitConfig{
+					Commit: "1234567",
+				},
+			},
+		},
+	}
+	spec := JobSpec{
+		PrimaryContainer: JobContainerSpec{
+			ContainerSpec: ContainerSpec{
+				Image: "debian:latest",
+			},
+		},
+		Cache: &JobCachePolicy{},
+	}
+
+	// Not cacheable
+	_, ok := jobCacheKey(event, "build", JobSpec{})
+	require.False(t, ok)
+
+	// No key and no commit
+	_, ok = jobCacheKey(Event{}, "build", spec)
+	require.False(t, ok)
+
+	key, ok := jobCacheKey(event, "build", spec)
+	require.True(t, ok)
+	require.NotEmpty(t, key)
+
+	// Changing the cache policy's TTL doesn't change the key
+	ttlSpec := spec
+	ttlSpec.Cache = &JobCachePolicy{TTLDuration: "1h"}
+	ttlKey, ok := jobCacheKey(event, "build", ttlSpec)
+	require.True(t, ok)
+	require.Equal(t, key, ttlKey)
+
+	// Changing the job's name changes the key
+	nameKey, ok := jobCacheKey(event, "test", spec)
+	require.True(t, ok)
+	require.NotEqual(t, key, nameKey)
+
+	// Changing the job's spec changes the key
+	imageSpec := spec
+	imageSpec.PrimaryContainer.Image = "debian:bullseye"
+	imageKey, ok := jobCacheKey(event, "build", imageSpec)
+	require.True(t, ok)
+	require.NotEqual(t, key, imageKey)
+
+	// Changing the value of an environment variable changes the key
+	envSpec := spec
+	envSpec.PrimaryContainer.Environment = map[string]string{"FOO": "bar"}
+	envKey, ok := jobCacheKey(event, "build", envSpec)
+	require.True(t, ok)
+	envSpec.PrimaryContainer.Environment = map[string]string{"FOO": "bat"}
+	otherEnvKey, ok := jobCacheKey(event, "build", envSpec)
+	require.True(t, ok)
+	require.NotEqual(t, envKey, otherEnvKey)
+
+	// A user-supplied key takes precedence over the commit
+	userKeySpec := spec
+	userKeySpec.Cache = &JobCachePolicy{Key: "foo"}
+	userKey, ok := jobCacheKey(Event{}, "build", userKeySpec)
+	require.True(t, ok)
+	require.NotEqual(t, key, userKey)
+	commitKey, ok := jobCacheKey(event, "build", userKeySpec)
+	require.True(t, ok)
+	require.Equal(t, userKey, commitKey)
+}
+
+func TestJobsServiceCachedJobStatus(t *testing.T) {
+	testEvent := Event{
+		ObjectMeta: meta.ObjectMeta{
+			ID: "123456789",
+		},
+		ProjectID: "italian",
+	}
+	testJob := Job{
+		Name: "build",
+		Spec: JobSpec{
+			Cache: &JobCachePolicy{
+				Key: "foo",
+			},
+		},
+		CacheKey: "abcdef",
+	}
+	testCases := []struct {
+		name       string
+		job        Job
+		service    *jobsService
+		assertions func(*JobStatus, error)
+	}{
+		{
+			name: "job is not cacheable",
+			job: Job{
+				Name: "build",
+			},
+			service: &jobsService{},
+			assertions: func(status *JobStatus, err error) {
+				require.NoError(t, err)
+				require.Nil(t, status)
+			},
+		},
+		{
+			name: "cache miss",
+			job:  testJob,
+			service: &jobsService{
+				jobCacheStore: &mockJobCacheStore{
+					GetFn: func(
+						context.Context,
+						string,
+						string,
+					) (JobCacheEntry, error) {
+						return JobCacheEntry{}, &meta.ErrNotFound{}
+					},
+				},
+			},
+			assertions: func(status *JobStatus, err error) {
+				require.NoError(t, err)
+				require.Nil(t, status)
+			},
+		},
+		{
+			name: "error retrieving cache entry",
+			job:  testJob,
+			service: &jobsService{
+				jobCacheStore: &mockJobCacheStore{
+					GetFn: func(
+						context.Context,
+						string,
+						string,
+					) (JobCacheEntry, error) {
+						return JobCacheEntry{}, errors.New("something went wrong")
+					},
+				},
+			},
+			assertions: func(_ *JobStatus, err error) {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), "something went wrong")
+				require.Contains(t, err.Error(), "error retrieving project")
+			},
+		},
+		{
+			name: "cached results' event was deleted",
+			job:  testJob,
+			service: &jobsService{
+				jobCacheStore: &mockJobCacheStore{
+					GetFn: func(
+						context.Context,
+						string,
+						string,
+					) (JobCacheEntry, error) {
+						return JobCacheEntry{EventID: "abcdefghi"}, nil
+					},
+				},
+				eventsStore: &mockEventsStore{
+					GetFn: func(context.Context, string) (Event, error) {
+						return Event{}, &meta.ErrNotFound{}
+					},
+				},
+			},
+			assertions: func(status *JobStatus, err error) {
+				require.NoError(t, err)
+				require.Nil(t, status)
+			},
+		},
+		{
+			name: "cache hit",
+			job:  testJob,
+			service: &jobsService{
+				jobCacheStore: &mockJobCacheStore{
+					GetFn: func(
+						_ context.Context,
+						projectID string,
+						key string,
+					) (JobCacheEntry, error) {
+						require.Equal(t, testEvent.ProjectID, projectID)
+						require.NotEmpty(t, key)
+						return JobCacheEntry{
+							EventID: "abcdefghi",
+							Outputs: map[string]string{"digest": "sha256:123"},
+						}, nil
+					},
+				},
+				eventsStore: &mockEventsStore{
+					GetFn: func(_ context.Context, id string) (Event, error) {
+						require.Equal(t, "abcdefghi", id)
+						return Event{}, nil
+					},
+				},
+			},
+			assertions: func(status *JobStatus, err error) {
+				require.NoError(t, err)
+				require.NotNil(t, status)
+				require.Equal(t, JobPhaseSucceeded, status.Phase)
+				require.Equal(t, ReasonCached, status.Reason)
+				require.Equal(t, "abcdefghi", status.LogsEventID)
+				require.Equal(
+					t,
+					JobOutputValues{"digest": "sha256:123"},
+					status.Outputs,
+				)
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.assertions(
+				testCase.service.cachedJobStatus(
+					context.Background(),
+					testEvent,
+					testCase.job,
+				),
+			)
+		})
+	}
+}
+
+func TestJobsServiceCacheJobResults(t *testing.T) {
+	testEvent := Event{
+		ObjectMeta: meta.ObjectMeta{
+			ID: "123456789",
+		},
+		ProjectID: "italian",
+	}
+	testJob := Job{
+		Name: "build",
+		Spec: JobSpec{
+			Cache: &JobCachePolicy{
+				Key:         "foo",
+				TTLDuration: "1h",
+			},
+		},
+		CacheKey: "abcdef",
+	}
+	testCases := []struct {
+		name       string
+		job        Job
+		status     JobStatus
+		service    *jobsService
+		assertions func(error)
+	}{
+		{
+			name: "job is not cacheable",
+			job: Job{
+				Name: "build",
+			},
+			service: &jobsService{},
+			assertions: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "results were themselves reused",
+			job:  testJob,
+			status: JobStatus{
+				Phase:       JobPhaseSucceeded,
+				LogsEventID: "abcdefghi",
+			},
+			service: &jobsService{},
+			assertions: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "error storing cache entry",
+			job:  testJob,
+			status: JobStatus{
+				Phase: JobPhaseSucceeded,
+			},
+			service: &jobsService{
+				jobCacheStore: &mockJobCacheStore{
+					PutFn: func(context.Context, JobCacheEntry) error {
+						return errors.New("something went wrong")
+					},
+				},
+			},
+			assertions: func(err error) {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), "something went wrong")
+				require.Contains(t, err.Error(), "error storing project")
+			},
+		},
+		{
+			name: "success",
+			job:  testJob,
+			status: JobStatus{
+				Phase:   JobPhaseSucceeded,
+				Outputs: map[string]string{"digest": "sha256:123"},
+			},
+			service: &jobsService{
+				jobCacheStore: &mockJobCacheStore{
+					PutFn: func(_ context.Context, entry JobCacheEntry) error {
+						require.Equal(t, testEvent.ProjectID, entry.ProjectID)
+						require.Equal(t, testJob.CacheKey, entry.Key)
+						require.Equal(t, testEvent.ID, entry.EventID)
+						require.Equal(t, "build", entry.JobName)
+						require.Equal(
+							t,
+							JobOutputValues{"digest": "sha256:123"},
+							entry.Outputs,
+						)
+						require.WithinDuration(
+							t,
+							time.Now().UTC().Add(time.Hour),
+							entry.Expires,
+							time.Minute,
+						)
+						return nil
+					},
+				},
+			},
+			assertions: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.assertions(
+				testCase.service.cacheJobResults(
+					context.Background(),
+					testEvent,
+					testCase.job,
+					testCase.status,
+				),
+			)
+		})
+	}
+}
+
+func TestJobsServiceCreateCached(t *testing.T) {
+	var scheduled, notified bool
+	service := &jobsService{
+		authorize: alwaysAuthorize,
+		eventsStore: &mockEventsStore{
+			GetFn: func(context.Context, string) (Event, error) {
+				return Event{
+					ObjectMeta: meta.ObjectMeta{
+						ID: "123456789",
+					},
+					ProjectID: "italian",
+				}, nil
+			},
+		},
+		projectsStore: &mockProjectsStore{
+			GetFn: func(context.Context, string) (Project, error) {
+				return Project{}, nil
+			},
+		},
+		jobCacheStore: &mockJobCacheStore{
+			GetFn: func(context.Context, string, string) (JobCacheEntry, error) {
+				return JobCacheEntry{EventID: "abcdefghi"}, nil
+			},
+		},
+		jobsStore: &mockJobsStore{
+			CreateFn: func(_ context.Context, _ string, job Job) error {
+				require.Equal(t, JobPhaseSucceeded, job.Status.Phase)
+				require.Equal(t, ReasonCached, job.Status.Reason)
+				require.Equal(t, "abcdefghi", job.Status.LogsEventID)
+				require.NotEmpty(t, job.CacheKey)
+				return nil
+			},
+		},
+		substrate: &mockSubstrate{
+			ScheduleJobFn: func(context.Context, Project, Event, string) error {
+				scheduled = true
+				return nil
+			},
+		},
+		notifier: &mockNotifier{
+			NotifyJobPhaseFn: func(
+				_ context.Context,
+				_ Event,
+				jobName string,
+				phase JobPhase,
+			) error {
+				require.Equal(t, "build", jobName)
+				require.Equal(t, JobPhaseSucceeded, phase)
+				notified = true
+				return nil
+			},
+		},
+	}
+	err := service.Create(
+		context.Background(),
+		"123456789",
+		Job{
+			Name: "build",
+			Spec: JobSpec{
+				Cache: &JobCachePolicy{
+					Key: "foo",
+				},
+			},
+		},
+	)
+	require.NoError(t, err)
+	require.False(t, scheduled)
+	require.True(t, notified)
+}
+
+func TestNewJobCacheService(t *testing.T) {
+	projectsStore := &mockProjectsStore{}
+	jobCacheStore := &mockJobCacheStore{}
+	svc, ok := NewJobCacheService(
+		alwaysProjectAuthorize,
+		projectsStore,
+		jobCacheStore,
+	).(*jobCacheService)
+	require.True(t, ok)
+	require.NotNil(t, svc.projectAuthorize)
+	require.Same(t, projectsStore, svc.projectsStore)
+	require.Same(t, jobCacheStore, svc.jobCacheStore)
+}
+
+func TestJobCacheServicePurge(t *testing.T) {
+	testCases := []struct {
+		name       string
+		service    JobCacheService
+		assertions func(error)
+	}{
+		{
+			name: "unauthorized",
+			service: &jobCacheService{
+				projectAuthorize: neverProjectAuthorize,
+			},
+			assertions: func(err error) {
+				require.Error(t, err)
+				require.IsType(t, &meta.ErrAuthorization{}, err)
+			},
+		},
+		{
+			name: "error retrieving project from store",
+			service: &jobCacheService{
+				projectAuthorize: alwaysProjectAuthorize,
+				projectsStore: &mockProjectsStore{
+					GetFn: func(context.Context, string) (Project, error) {
+						return Project{}, errors.New("something went wrong")
+					},
+				},
+			},
+			assertions: func(err error) {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), "something went wrong")
+				require.Contains(t, err.Error(), "error retrieving project")
+			},
+		},
+		{
+			name: "error purging job cache",
+			service: &jobCacheService{
+				projectAuthorize: alwaysProjectAuthorize,
+				projectsStore: &mockProjectsStore{
+					GetFn: func(context.Context, string) (Project, error) {
+						return Project{}, nil
+					},
+				},
+				jobCacheStore: &mockJobCacheStore{
+					DeleteByProjectIDFn: func(context.Context, string) error {
+						return errors.New("something went wrong")
+					},
+				},
+			},
+			assertions: func(err error) {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), "something went wrong")
+				require.Contains(t, err.Error(), "error purging job cache")
+			},
+		},
+		{
+			name: "success",
+			service: &jobCacheService{
+				projectAuthorize: alwaysProjectAuthorize,
+				projectsStore: &mockProjectsStore{
+					GetFn: func(context.Context, string) (Project, error) {
+						return Project{}, nil
+					},
+				},
+				jobCacheStore: &mockJobCacheStore{
+					DeleteByProjectIDFn: func(context.Context, string) error {
+						return nil
+					},
+				},
+			},
+			assertions: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.assertions(
+				testCase.service.Purge(context.Background(), "italian"),
+			)
+		})
+	}
+}
+
+type mockJobCacheStore struct {
+	GetFn func(
+		ctx context.Context,
+		projectID string,
+		key string,
+	) (JobCacheEntry, error)
+	PutFn               func(context.Context, JobCacheEntry) error
+	DeleteByProjectIDFn func(context.Context, string) error
+}
+
+func (m *mockJobCacheStore) Get(
+	ctx context.Context,
+	projectID string,
+	key string,
+) (JobCacheEntry, error) {
+	return m.GetFn(ctx, projectID, key)
+}
+
+func (m *mockJobCacheStore) Put(
+	ctx context.Context,
+	entry JobCacheEntry,
+) error {
+	return m.PutFn(ctx, entry)
+}
+
+func (m *mockJobCacheStore) DeleteByProjectID(
+	ctx context.Context,
+	projectID string,
+) error {
+	return m.DeleteByProjectIDFn(ctx, projectID)
+}
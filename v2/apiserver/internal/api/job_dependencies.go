@@ -0,0 +1,235 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/brigadecore/brigade/v2/apiserver/internal/meta"
+	"github.com/pkg/errors"
+)
+
+// ReasonDependencyFailed is the Reason recorded in the status of a Job that
+// was canceled because one of the Jobs it depends on did not succeed.
+const ReasonDependencyFailed = "DependencyFailed"
+
+// validateJobDependencies returns a *meta.ErrBadRequest error if the provided
+// Job's dependencies are not all existing Jobs of the provided Event or if
+// they would introduce a cycle into the Event's graph of Jobs.
+func validateJobDependencies(event Event, job Job) error {
+	details := []string{}
+	seen := map[string]struct{}{}
+	for _, dependency := range job.Spec.DependsOn {
+		if _, ok := seen[dependency]; ok {
+			details = append(
+				details,
+				fmt.Sprintf("job %q is listed more than once", dependency),
+			)
+			continue
+		}
+		seen[dependency] = struct{}{}
+		if dependency == job.Name {
+			details = append(details, "a job cannot depend on itself")
+			continue
+		}
+		if _, ok := event.Worker.Job(dependency); !ok {
+			details = append(
+				details,
+				fmt.Sprintf("event %q has no job named %q", event.ID, dependency),
+			)
+		}
+	}
+	if len(details) == 0 {
+		// Ordinarily, a Job can only depend on Jobs that already exist and no
+		// existing Job can depend on a new one, but Jobs inherited by a retried
+		// Event may be re-created with different dependencies, so the graph must
+		// still be checked for cycles.
+		if cycle := findJobDependencyCycle(event, job); cycle != nil {
+			details = append(
+				details,
+				fmt.Sprintf("dependencies form a cycle: %v", cycle),
+			)
+		}
+	}
+	if len(details) > 0 {
+		return &meta.ErrBadRequest{
+			Reason:  "Invalid job dependencies.",
+			Details: details,
+		}
+	}
+	return nil
+}
+
+// findJobDependencyCycle returns the names of the Jobs forming a cycle that
+// leads back to the provided Job, if the Job's dependencies, combined with
+// those of the provided Event's other Jobs, form such a cycle. Otherwise, it
+// returns nil.
+func findJobDependencyCycle(event Event, job Job) []string {
+	dependsOn := map[string][]string{}
+	for _, existingJob := range event.Worker.Jobs {
+		dependsOn[existingJob.Name] = existingJob.Spec.DependsOn
+	}
+	dependsOn[job.Name] = job.Spec.DependsOn
+	visited := map[string]struct{}{}
+	var visit func(path []string) []string
+	visit = func(path []string) []string {
+		for _, dependency := range dependsOn[path[len(path)-1]] {
+			if dependency == job.Name {
+				return append(path, dependency)
+			}
+			if _, ok := visited[dependency]; ok {
+				continue
+			}
+			visited[dependency] = struct{}{}
+			if cycle := visit(append(path, dependency)); cycle != nil {
+				return cycle
+			}
+		}
+		return nil
+	}
+	return visit([]string{job.Name})
+}
+
+// checkJobDependencies examines the phases of the specified Jobs of the
+// provided Event. It returns true if all of them have succeeded. If any of them
+// has concluded without succeeding, it returns false along with that Job's
+// name.
+func checkJobDependencies(event Event, dependsOn []string) (bool, string) {
+	satisfied := true
+	for _, dependency := range dependsOn {
+		job, ok := event.Worker.Job(dependency)
+		if !ok || job.Status == nil || !job.Status.Phase.IsTerminal() {
+			satisfied = false
+			continue
+		}
+		if job.Status.Phase != JobPhaseSucceeded {
+			return false, dependency
+		}
+	}
+	return satisfied, ""
+}
+
+// dependencyFailedStatus returns the status of a Job that will never run
+// because the specified Job, on which it depends, did not succeed.
+func dependencyFailedStatus(dependency string) JobStatus {
+	return JobStatus{
+		Phase:  JobPhaseCanceled,
+		Reason: ReasonDependencyFailed,
+		Message: fmt.Sprintf(
+			"The job was canceled because job %q, on which it depends, did not "+
+				"succeed.",
+			dependency,
+		),
+	}
+}
+
+// resolveJobDependents is invoked after the specified Job of the specified
+// Event has reached a terminal phase. Any PENDING Jobs of the same Event that
+// depend on it are resolved. See resolvePendingJob().
+// Failure to resolve any one dependent Job is logged, but does not prevent the
+// others from being resolved.
+func (j *jobsService) resolveJobDependents(
+	ctx context.Context,
+	eventID string,
+	jobName string,
+) {
+	event, err := j.eventsStore.Get(ctx, eventID)
+	if err != nil {
+		log.Println(
+			errors.Wrapf(err, "error retrieving event %q from store", eventID),
+		)
+		return
+	}
+	for _, job := range event.Worker.Jobs {
+		if dependsOn(job, jobName) {
+			j.resolvePendingJob(ctx, eventID, job.Name)
+		}
+	}
+}
+
+// resolvePendingJob examines the specified Job of the specified Event. If it
+// is PENDING, it is scheduled (or, if cached results can be reused, succeeds
+// outright) if all of its dependencies have succeeded or is canceled if any of
+// its dependencies has concluded without succeeding. Any error is logged.
+func (j *jobsService) resolvePendingJob(
+	ctx context.Context,
+	eventID string,
+	jobName string,
+) {
+	// Always work from a fresh copy of the Event. If two dependencies of a Job
+	// conclude at nearly the same time, or one concludes while the Job is being
+	// created, this ensures at least one of them sees the other's final status.
+	event, err := j.eventsStore.Get(ctx, eventID)
+	if err != nil {
+		log.Println(
+			errors.Wrapf(err, "error retrieving event %q from store", eventID),
+		)
+		return
+	}
+	job, ok := event.Worker.Job(jobName)
+	if !ok ||
+		job.Status == nil ||
+		job.Status.Phase != JobPhasePending ||
+		job.Status.currentAttempt() > 1 {
+		return
+	}
+	satisfied, failedDependency :=
+		checkJobDependencies(event, job.Spec.DependsOn)
+	var status *JobStatus
+	if failedDependency != "" {
+		canceledStatus := dependencyFailedStatus(failedDependency)
+		status = &canceledStatus
+	} else if !satisfied {
+		return
+	} else if status, err = j.cachedJobStatus(ctx, event, job); err != nil {
+		// Not being able to reuse cached results isn't allowed to prevent the
+		// job from running.
+		log.Println(err)
+	}
+	if status != nil {
+		// Concluding a Job in turn resolves the Jobs that depend on it.
+		if _, err = j.updateStatus(ctx, event, jobName, *status); err != nil {
+			log.Println(
+				errors.Wrapf(
+					err,
+					"error updating status of event %q job %q",
+					eventID,
+					jobName,
+				),
+			)
+		}
+		return
+	}
+	project, err := j.projectsStore.Get(ctx, event.ProjectID)
+	if err != nil {
+		log.Println(
+			errors.Wrapf(
+				err,
+				"error retrieving project %q from store",
+				event.ProjectID,
+			),
+		)
+		return
+	}
+	if err = j.substrate.ScheduleJob(ctx, project, event, jobName); err != nil {
+		log.Println(
+			errors.Wrapf(
+				err,
+				"error scheduling event %q job %q on the substrate",
+				eventID,
+				jobName,
+			),
+		)
+	}
+}
+
+// dependsOn returns true if the provided Job depends directly on the specified
+// Job and false otherwise.
+func dependsOn(job Job, jobName string) bool {
+	for _, dependency := range job.Spec.DependsOn {
+		if dependency == jobName {
+			return true
+		}
+	}
+	return false
+}
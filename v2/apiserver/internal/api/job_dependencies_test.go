@@ -0,0 +1,509 @@
+package api
+
+import (
+	"context"
+	"testing"
+
+	"github.com/brigadecore/brigade/v2/apiserver/internal/meta"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateJobDependencies(t *testing.T) {
+	testEvent := Event{
+		ObjectMeta: meta.ObjectMeta{
+			ID: "123456789",
+		},
+		Worker: Worker{
+			Jobs: []Job{
+				{
+					Name: "build",
+				},
+				{
+					Name: "test",
+					Spec: JobSpec{
+						DependsOn: []string{"build"},
+					},
+				},
+			},
+		},
+	}
+	testCases := []struct {
+		name       string
+		event      Event
+		job        Job
+		assertions func(error)
+	}{
+		{
+			name:  "no dependencies",
+			event: testEvent,
+			job: Job{
+				Name: "lint",
+			},
+			assertions: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name:  "invalid dependencies",
+			event: testEvent,
+			job: Job{
+				Name: "publish",
+				Spec: JobSpec{
+					DependsOn: []string{"test", "test", "publish", "deploy"},
+				},
+			},
+			assertions: func(err error) {
+				require.Error(t, err)
+				require.IsType(t, &meta.ErrBadRequest{}, err)
+				badReqErr := err.(*meta.ErrBadRequest)
+				require.Equal(t, "Invalid job dependencies.", badReqErr.Reason)
+				require.Equal(
+					t,
+					[]string{
+						`job "test" is listed more than once`,
+						"a job cannot depend on itself",
+						`event "123456789" has no job named "deploy"`,
+					},
+					badReqErr.Details,
+				)
+			},
+		},
+		{
+			name:  "cycle",
+			event: testEvent,
+			// This can only happen when a job inherited by a retried event is
+			// re-created with different dependencies.
+			job: Job{
+				Name: "build",
+				Spec: JobSpec{
+					DependsOn: []string{"test"},
+				},
+			},
+			assertions: func(err error) {
+				require.Error(t, err)
+				require.IsType(t, &meta.ErrBadRequest{}, err)
+				require.Equal(
+					t,
+					[]string{"dependencies form a cycle: [build test build]"},
+					err.(*meta.ErrBadRequest).Details,
+				)
+			},
+		},
+		{
+			name:  "valid dependencies",
+			event: testEvent,
+			job: Job{
+				Name: "publish",
+				Spec: JobSpec{
+					DependsOn: []string{"build", "test"},
+				},
+			},
+			assertions: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.assertions(
+				validateJobDependencies(testCase.event, testCase.job),
+			)
+		})
+	}
+}
+
+func TestCheckJobDependencies(t *testing.T) {
+	testEvent := Event{
+		Worker: Worker{
+			Jobs: []Job{
+				{
+					Name: "build",
+					Status: &JobStatus{
+						Phase: JobPhaseSucceeded,
+					},
+				},
+				{
+					Name: "test",
+					Status: &JobStatus{
+						Phase: JobPhaseRunning,
+					},
+				},
+				{
+					Name: "lint",
+					Status: &JobStatus{
+						Phase: JobPhaseFailed,
+					},
+				},
+			},
+		},
+	}
+	testCases := []struct {
+		name                     string
+		dependsOn                []string
+		expectedSatisfied        bool
+		expectedFailedDependency string
+	}{
+		{
+			name:              "no dependencies",
+			expectedSatisfied: true,
+		},
+		{
+			name:              "all dependencies succeeded",
+			dependsOn:         []string{"build"},
+			expectedSatisfied: true,
+		},
+		{
+			name:              "dependency still running",
+			dependsOn:         []string{"build", "test"},
+			expectedSatisfied: false,
+		},
+		{
+			name:                     "dependency failed",
+			dependsOn:                []string{"test", "lint"},
+			expectedSatisfied:        false,
+			expectedFailedDependency: "lint",
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			satisfied, failedDependency :=
+				checkJobDependencies(testEvent, testCase.dependsOn)
+			require.Equal(t, testCase.expectedSatisfied, satisfied)
+			require.Equal(t, testCase.expectedFailedDependency, failedDependency)
+		})
+	}
+}
+
+func TestJobsServiceCreateWithDependencies(t *testing.T) {
+	const testEventID = "123456789"
+	const testJobName = "publish"
+	testCases := []struct {
+		name       string
+		buildPhase JobPhase
+		// concludedPhase, if set, is the phase of the dependency by the time the
+		// new Job has been persisted
+		concludedPhase JobPhase
+		service        func(scheduled *bool) JobsService
+		assertions     func(scheduled bool, err error)
+	}{
+		{
+			name:       "dependency not yet concluded",
+			buildPhase: JobPhaseRunning,
+			service: func(scheduled *bool) JobsService {
+				return &jobsService{
+					jobsStore: &mockJobsStore{
+						CreateFn: func(_ context.Context, _ string, job Job) error {
+							require.Equal(t, JobPhasePending, job.Status.Phase)
+							return nil
+						},
+					},
+					substrate: &mockSubstrate{
+						StoreJobEnvironmentFn: func(
+							context.Context,
+							Project,
+							string,
+							string,
+							JobSpec,
+						) error {
+							return nil
+						},
+						ScheduleJobFn: func(context.Context, Project, Event, string) error {
+							*scheduled = true
+							return nil
+						},
+					},
+				}
+			},
+			assertions: func(scheduled bool, err error) {
+				require.NoError(t, err)
+				require.False(t, scheduled)
+			},
+		},
+		{
+			name:           "dependency concluded during creation",
+			buildPhase:     JobPhaseRunning,
+			concludedPhase: JobPhaseSucceeded,
+			service: func(scheduled *bool) JobsService {
+				return &jobsService{
+					jobsStore: &mockJobsStore{
+						CreateFn: func(_ context.Context, _ string, job Job) error {
+							require.Equal(t, JobPhasePending, job.Status.Phase)
+							return nil
+						},
+					},
+					substrate: &mockSubstrate{
+						StoreJobEnvironmentFn: func(
+							context.Context,
+							Project,
+							string,
+							string,
+							JobSpec,
+						) error {
+							return nil
+						},
+						ScheduleJobFn: func(context.Context, Project, Event, string) error {
+							*scheduled = true
+							return nil
+						},
+					},
+				}
+			},
+			assertions: func(scheduled bool, err error) {
+				require.NoError(t, err)
+				require.True(t, scheduled)
+			},
+		},
+		{
+			name:       "dependency failed",
+			buildPhase: JobPhaseFailed,
+			service: func(scheduled *bool) JobsService {
+				return &jobsService{
+					jobsStore: &mockJobsStore{
+						CreateFn: func(_ context.Context, _ string, job Job) error {
+							require.Equal(t, JobPhaseCanceled, job.Status.Phase)
+							require.Equal(t, ReasonDependencyFailed, job.Status.Reason)
+							require.Contains(t, job.Status.Message, `"build"`)
+							return nil
+						},
+					},
+					substrate: &mockSubstrate{
+						ScheduleJobFn: func(context.Context, Project, Event, string) error {
+							*scheduled = true
+							return nil
+						},
+					},
+				}
+			},
+			assertions: func(scheduled bool, err error) {
+				require.NoError(t, err)
+				require.False(t, scheduled)
+			},
+		},
+		{
+			name:       "dependency succeeded",
+			buildPhase: JobPhaseSucceeded,
+			service: func(scheduled *bool) JobsService {
+				return &jobsService{
+					jobsStore: &mockJobsStore{
+						CreateFn: func(_ context.Context, _ string, job Job) error {
+							require.Equal(t, JobPhasePending, job.Status.Phase)
+							return nil
+						},
+					},
+					substrate: &mockSubstrate{
+						StoreJobEnvironmentFn: func(
+							context.Context,
+							Project,
+							string,
+							string,
+							JobSpec,
+						) error {
+							return nil
+						},
+						ScheduleJobFn: func(context.Context, Project, Event, string) error {
+							*scheduled = true
+							return nil
+						},
+					},
+				}
+			},
+			assertions: func(scheduled bool, err error) {
+				require.NoError(t, err)
+				require.True(t, scheduled)
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			var scheduled bool
+			service := testCase.service(&scheduled).(*jobsService)
+			service.authorize = alwaysAuthorize
+			var retrievals int
+			service.eventsStore = &mockEventsStore{
+				GetFn: func(context.Context, string) (Event, error) {
+					event := Event{
+						ObjectMeta: meta.ObjectMeta{
+							ID: testEventID,
+						},
+						Worker: Worker{
+							Jobs: []Job{
+								{
+									Name: "build",
+									Status: &JobStatus{
+										Phase: testCase.buildPhase,
+									},
+								},
+							},
+						},
+					}
+					retrievals++
+					if testCase.concludedPhase != "" && retrievals > 1 {
+						event.Worker.Jobs[0].Status.Phase = testCase.concludedPhase
+						event.Worker.Jobs = append(
+							event.Worker.Jobs,
+							Job{
+								Name: testJobName,
+								Spec: JobSpec{
+									DependsOn: []string{"build"},
+								},
+								Status: &JobStatus{
+									Phase: JobPhasePending,
+								},
+							},
+						)
+					}
+					return event, nil
+				},
+			}
+			service.projectsStore = &mockProjectsStore{
+				GetFn: func(context.Context, string) (Project, error) {
+					return Project{}, nil
+				},
+			}
+			err := service.Create(
+				context.Background(),
+				testEventID,
+				Job{
+					Name: testJobName,
+					Spec: JobSpec{
+						DependsOn: []string{"build"},
+					},
+				},
+			)
+			testCase.assertions(scheduled, err)
+		})
+	}
+}
+
+func TestJobsServiceResolveJobDependents(t *testing.T) {
+	const testEventID = "123456789"
+	testCases := []struct {
+		name       string
+		buildPhase JobPhase
+		assertions func(scheduled []string, statuses map[string]JobStatus)
+	}{
+		{
+			name:       "dependency succeeded",
+			buildPhase: JobPhaseSucceeded,
+			assertions: func(scheduled []string, statuses map[string]JobStatus) {
+				// "publish" also depends on "test", which hasn't concluded yet
+				require.Equal(t, []string{"test"}, scheduled)
+				require.Empty(t, statuses)
+			},
+		},
+		{
+			name:       "dependency failed",
+			buildPhase: JobPhaseFailed,
+			assertions: func(scheduled []string, statuses map[string]JobStatus) {
+				require.Empty(t, scheduled)
+				// Cancellation should have cascaded to "publish"
+				require.Len(t, statuses, 2)
+				for _, jobName := range []string{"test", "publish"} {
+					require.Equal(t, JobPhaseCanceled, statuses[jobName].Phase)
+					require.Equal(
+						t,
+						ReasonDependencyFailed,
+						statuses[jobName].Reason,
+					)
+				}
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			event := Event{
+				ObjectMeta: meta.ObjectMeta{
+					ID: testEventID,
+				},
+				Worker: Worker{
+					Jobs: []Job{
+						{
+							Name: "build",
+							Status: &JobStatus{
+								Phase: testCase.buildPhase,
+							},
+						},
+						{
+							Name: "lint",
+							Status: &JobStatus{
+								Phase: JobPhasePending,
+							},
+						},
+						{
+							Name: "test",
+							Spec: JobSpec{
+								DependsOn: []string{"build"},
+							},
+							Status: &JobStatus{
+								Phase: JobPhasePending,
+							},
+						},
+						{
+							Name: "publish",
+							Spec: JobSpec{
+								DependsOn: []string{"build", "test"},
+							},
+							Status: &JobStatus{
+								Phase: JobPhasePending,
+							},
+						},
+					},
+				},
+			}
+			scheduled := []string{}
+			statuses := map[string]JobStatus{}
+			service := &jobsService{
+				projectsStore: &mockProjectsStore{
+					GetFn: func(context.Context, string) (Project, error) {
+						return Project{}, nil
+					},
+				},
+				eventsStore: &mockEventsStore{
+					GetFn: func(context.Context, string) (Event, error) {
+						// Reflect any status updates made so far
+						for i, job := range event.Worker.Jobs {
+							if status, ok := statuses[job.Name]; ok {
+								event.Worker.Jobs[i].Status = &status
+							}
+						}
+						return event, nil
+					},
+				},
+				jobsStore: &mockJobsStore{
+					UpdateStatusFn: func(
+						_ context.Context,
+						_ string,
+						jobName string,
+						status JobStatus,
+					) error {
+						statuses[jobName] = status
+						return nil
+					},
+				},
+				substrate: &mockSubstrate{
+					ScheduleJobFn: func(
+						_ context.Context,
+						_ Project,
+						_ Event,
+						jobName string,
+					) error {
+						scheduled = append(scheduled, jobName)
+						return nil
+					},
+				},
+				notifier: &mockNotifier{
+					NotifyJobPhaseFn: func(
+						context.Context,
+						Event,
+						string,
+						JobPhase,
+					) error {
+						return nil
+					},
+				},
+			}
+			service.resolveJobDependents(context.Background(), testEventID, "build")
+			testCase.assertions(scheduled, statuses)
+		})
+	}
+}
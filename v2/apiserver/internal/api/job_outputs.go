@@ -0,0 +1,66 @@
+package api
+
+import (
+	"sort"
+
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+)
+
+// JobOutputValues is a map of key/value pairs reported by a Job. Output keys
+// may contain dots, which MongoDB does not permit in field names, so in BSON
+// these are represented as an array of documents, each having a key and a
+// value field, ordered by key.
+type JobOutputValues map[string]string
+
+// jobOutputValue is the BSON representation of a single key/value pair in
+// JobOutputValues.
+type jobOutputValue struct {
+	Key   string `bson:"key"`
+	Value string `bson:"value"`
+}
+
+func (j JobOutputValues) MarshalBSONValue() (bsontype.Type, []byte, error) {
+	keys := make([]string, 0, len(j))
+	for k := range j {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	values := make([]jobOutputValue, len(keys))
+	for i, k := range keys {
+		values[i] = jobOutputValue{Key: k, Value: j[k]}
+	}
+	return bson.MarshalValue(values)
+}
+
+func (j *JobOutputValues) UnmarshalBSONValue(
+	bsonType bsontype.Type,
+	bsonBytes []byte,
+) error {
+	rawValue := bson.RawValue{Type: bsonType, Value: bsonBytes}
+	switch bsonType {
+	case bsontype.Null:
+		*j = nil
+	case bsontype.EmbeddedDocument:
+		// Outputs were stored as a document before keys were permitted to
+		// contain dots
+		doc := map[string]string{}
+		if err := rawValue.Unmarshal(&doc); err != nil {
+			return errors.Wrap(err, "error unmarshaling job outputs")
+		}
+		*j = doc
+	case bsontype.Array:
+		values := []jobOutputValue{}
+		if err := rawValue.Unmarshal(&values); err != nil {
+			return errors.Wrap(err, "error unmarshaling job outputs")
+		}
+		*j = make(JobOutputValues, len(values))
+		for _, value := range values {
+			(*j)[value.Key] = value.Value
+		}
+	default:
+		return errors.Errorf("cannot unmarshal BSON %s into job outputs", bsonType)
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestJobOutputValuesBSON(t *testing.T) {
+	type record struct {
+		Outputs JobOutputValues `bson:"outputs,omitempty"`
+	}
+
+	t.Run("round trip", func(t *testing.T) {
+		// These keys contain dots and are NOT in lexical order
+		outputs := JobOutputValues{
+			"image.digest": "sha256:123",
+			"app.version":  "v1.0.1",
+		}
+		bsonBytes, err := bson.Marshal(record{Outputs: outputs})
+		require.NoError(t, err)
+		// Unmarshal into a generic bson.D and verify that the outputs are stored
+		// as an array of key/value documents in lexical order by key
+		d := bson.D{}
+		require.NoError(t, bson.Unmarshal(bsonBytes, &d))
+		require.Equal(
+			t,
+			bson.D{
+				{
+					Key: "outputs",
+					Value: bson.A{
+						bson.D{
+							{Key: "key", Value: "app.version"},
+							{Key: "value", Value: "v1.0.1"},
+						},
+						bson.D{
+							{Key: "key", Value: "image.digest"},
+							{Key: "value", Value: "sha256:123"},
+						},
+					},
+				},
+			},
+			d,
+		)
+		decoded := record{}
+		require.NoError(t, bson.Unmarshal(bsonBytes, &decoded))
+		require.Equal(t, outputs, decoded.Outputs)
+	})
+
+	t.Run("stored as a document", func(t *testing.T) {
+		bsonBytes, err := bson.Marshal(
+			bson.M{
+				"outputs": bson.M{
+					"digest": "sha256:123",
+				},
+			},
+		)
+		require.NoError(t, err)
+		decoded := record{}
+		require.NoError(t, bson.Unmarshal(bsonBytes, &decoded))
+		require.Equal(t, JobOutputValues{"digest": "sha256:123"}, decoded.Outputs)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		bsonBytes, err := bson.Marshal(record{})
+		require.NoError(t, err)
+		decoded := record{}
+		require.NoError(t, bson.Unmarshal(bsonBytes, &decoded))
+		require.Nil(t, decoded.Outputs)
+	})
+}
@@ -0,0 +1,274 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/brigadecore/brigade/v2/apiserver/internal/meta"
+	"github.com/pkg/errors"
+)
+
+// maxJobRetryBackoff is the longest a Job will ever wait between attempts,
+// regardless of how many of its attempts have been unsuccessful.
+const maxJobRetryBackoff = 24 * time.Hour
+
+// JobRetryPolicy describes whether and how a Job should be automatically
+// re-attempted if it does not succeed.
+type JobRetryPolicy struct {
+	// MaxAttempts specifies how many times, in total, the Job may be attempted.
+	// It must be at least 1.
+	MaxAttempts int `json:"maxAttempts" bson:"maxAttempts"`
+	// BackoffDuration optionally specifies how long to wait after the first
+	// unsuccessful attempt before attempting the Job again. The wait doubles
+	// after every subsequent unsuccessful attempt. This duration string is a
+	// sequence of decimal numbers, each with optional fraction and a unit suffix,
+	// such as "30s" or "1m30s". Valid time units are "ns", "us" (or "µs"), "ms",
+	// "s", "m", "h". If unspecified, the Job is attempted again right away.
+	BackoffDuration string `json:"backoffDuration,omitempty" bson:"backoffDuration,omitempty"` // nolint: lll
+	// RetryablePhases optionally enumerates the terminal JobPhases in which an
+	// attempt may conclude and still be followed by another. Valid values are
+	// FAILED and TIMED_OUT. If unspecified, both are retryable.
+	RetryablePhases []JobPhase `json:"retryablePhases,omitempty" bson:"retryablePhases,omitempty"` // nolint: lll
+	// RetryableExitCodes optionally enumerates exit codes of the Job's primary
+	// container. If specified, a FAILED attempt is only followed by another if
+	// the primary container exited with one of these codes. Attempts that TIMED
+	// OUT are unaffected by this setting.
+	RetryableExitCodes []int32 `json:"retryableExitCodes,omitempty" bson:"retryableExitCodes,omitempty"` // nolint: lll
+}
+
+// permitsRetry returns a bool indicating whether, per the JobRetryPolicy, the
+// attempt described by the provided terminal JobStatus should be followed by
+// another.
+func (j *JobRetryPolicy) permitsRetry(status JobStatus) bool {
+	if j == nil || status.currentAttempt() >= j.MaxAttempts {
+		return false
+	}
+	retryablePhases := j.RetryablePhases
+	if len(retryablePhases) == 0 {
+		retryablePhases = []JobPhase{JobPhaseFailed, JobPhaseTimedOut}
+	}
+	var retryablePhase bool
+	for _, phase := range retryablePhases {
+		if phase == status.Phase {
+			retryablePhase = true
+			break
+		}
+	}
+	if !retryablePhase {
+		return false
+	}
+	if status.Phase != JobPhaseFailed || len(j.RetryableExitCodes) == 0 {
+		return true
+	}
+	if status.ExitCode == nil {
+		return false
+	}
+	for _, exitCode := range j.RetryableExitCodes {
+		if exitCode == *status.ExitCode {
+			return true
+		}
+	}
+	return false
+}
+
+// backoff returns how long to wait after the specified unsuccessful attempt
+// before attempting the Job again.
+func (j *JobRetryPolicy) backoff(attempt int) time.Duration {
+	// Validation guarantees this parses
+	backoff, _ := time.ParseDuration(j.BackoffDuration) // nolint: errcheck
+	for i := 1; i < attempt && backoff < maxJobRetryBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > maxJobRetryBackoff {
+		return maxJobRetryBackoff
+	}
+	return backoff
+}
+
+// validateJobRetryPolicy returns a *meta.ErrBadRequest if the provided
+// JobRetryPolicy is invalid.
+func validateJobRetryPolicy(policy *JobRetryPolicy) error {
+	if policy == nil {
+		return nil
+	}
+	details := []string{}
+	if policy.MaxAttempts < 1 {
+		details = append(
+			details,
+			fmt.Sprintf("max attempts %d is less than 1", policy.MaxAttempts),
+		)
+	}
+	if policy.BackoffDuration != "" {
+		if backoff, err := time.ParseDuration(policy.BackoffDuration); err != nil {
+			details = append(
+				details,
+				fmt.Sprintf(
+					"backoff duration %q is invalid: %s",
+					policy.BackoffDuration,
+					err,
+				),
+			)
+		} else if backoff < 0 {
+			details = append(
+				details,
+				fmt.Sprintf("backoff duration %q is negative", policy.BackoffDuration),
+			)
+		}
+	}
+	for _, phase := range policy.RetryablePhases {
+		if phase != JobPhaseFailed && phase != JobPhaseTimedOut {
+			details = append(
+				details,
+				fmt.Sprintf("job phase %q is not retryable", phase),
+			)
+		}
+	}
+	if len(details) > 0 {
+		return &meta.ErrBadRequest{
+			Reason:  "Invalid retry policy.",
+			Details: details,
+		}
+	}
+	return nil
+}
+
+// JobAttempt represents a single concluded attempt at a Job.
+type JobAttempt struct {
+	// Attempt is the number of the attempt. Attempts are numbered from 1.
+	Attempt int `json:"attempt" bson:"attempt"`
+	// Started indicates the time the attempt began execution.
+	Started *time.Time `json:"started,omitempty" bson:"started,omitempty"`
+	// Ended indicates the time the attempt concluded execution.
+	Ended *time.Time `json:"ended,omitempty" bson:"ended,omitempty"`
+	// Phase indicates the terminal phase in which the attempt concluded.
+	Phase JobPhase `json:"phase" bson:"phase"`
+	// ExitCode is the exit code of the Job's primary container, if it exited.
+	ExitCode *int32 `json:"exitCode,omitempty" bson:"exitCode,omitempty"`
+	// Reason is a brief, machine-readable explanation of why the attempt
+	// concluded in the phase it did.
+	Reason string `json:"reason,omitempty" bson:"reason,omitempty"`
+	// Message is a human-readable elaboration on Reason.
+	Message string `json:"message,omitempty" bson:"message,omitempty"`
+}
+
+// JobRetryServiceConfig encapsulates configuration options for the
+// JobRetryService.
+type JobRetryServiceConfig struct {
+	// Interval specifies how frequently the JobRetryService should check for
+	// Jobs that are due to be attempted again.
+	Interval time.Duration
+}
+
+// JobRetryService is the specialized interface for re-attempting Jobs whose
+// earlier attempts were unsuccessful. It's decoupled from underlying
+// technology choices (e.g. data store, message bus, etc.) to keep business
+// logic reusable and consistent while the underlying tech stack remains free
+// to change.
+type JobRetryService interface {
+	// Run periodically schedules all Jobs that are due to be attempted again. It
+	// blocks until the provided context is canceled.
+	Run(context.Context)
+}
+
+type jobRetryService struct {
+	projectsStore ProjectsStore
+	jobsStore     JobsStore
+	substrate     Substrate
+	config        JobRetryServiceConfig
+	// nowFn is overridable for testing purposes
+	nowFn func() time.Time
+}
+
+// NewJobRetryService returns a specialized interface for re-attempting Jobs
+// whose earlier attempts were unsuccessful.
+func NewJobRetryService(
+	projectsStore ProjectsStore,
+	jobsStore JobsStore,
+	substrate Substrate,
+	config JobRetryServiceConfig,
+) JobRetryService {
+	return &jobRetryService{
+		projectsStore: projectsStore,
+		jobsStore:     jobsStore,
+		substrate:     substrate,
+		config:        config,
+		nowFn: func() time.Time {
+			return time.Now().UTC()
+		},
+	}
+}
+
+func (j *jobRetryService) Run(ctx context.Context) {
+	ticker := time.NewTicker(j.config.Interval)
+	defer ticker.Stop()
+	for {
+		if err := j.retryAll(ctx); err != nil {
+			log.Println(err)
+		}
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// retryAll claims and schedules Jobs until none remain that are due to be
+// attempted again.
+func (j *jobRetryService) retryAll(ctx context.Context) error {
+	for {
+		event, jobName, err := j.jobsStore.ClaimRetry(ctx, j.nowFn())
+		if err != nil {
+			return errors.Wrap(err, "error claiming job retry")
+		}
+		if event == nil {
+			return nil
+		}
+		if err = j.retry(ctx, *event, jobName); err != nil {
+			log.Println(err)
+		}
+	}
+}
+
+// retry schedules the next attempt at the specified Job. If that fails, the
+// attempt is postponed until the next interval.
+func (j *jobRetryService) retry(
+	ctx context.Context,
+	event Event,
+	jobName string,
+) error {
+	project, err := j.projectsStore.Get(ctx, event.ProjectID)
+	if err == nil {
+		if err = j.substrate.ScheduleJob(ctx, project, event, jobName); err == nil {
+			return nil
+		}
+	}
+	err = errors.Wrapf(
+		err,
+		"error scheduling event %q job %q retry",
+		event.ID,
+		jobName,
+	)
+	job, _ := event.Worker.Job(jobName)
+	status := *job.Status
+	nextAttempt := j.nowFn().Add(j.config.Interval)
+	status.NextAttempt = &nextAttempt
+	if updateErr := j.jobsStore.UpdateStatus(
+		ctx,
+		event.ID,
+		jobName,
+		status,
+	); updateErr != nil {
+		log.Println(
+			errors.Wrapf(
+				updateErr,
+				"error postponing event %q job %q retry",
+				event.ID,
+				jobName,
+			),
+		)
+	}
+	return err
+}
@@ -0,0 +1,366 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/brigadecore/brigade/v2/apiserver/internal/meta"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJobRetryPolicyPermitsRetry(t *testing.T) {
+	exitCode := int32(42)
+	testCases := []struct {
+		name          string
+		policy        *JobRetryPolicy
+		status        JobStatus
+		expectedRetry bool
+	}{
+		{
+			name: "nil policy",
+			status: JobStatus{
+				Phase: JobPhaseFailed,
+			},
+			expectedRetry: false,
+		},
+		{
+			name: "attempts exhausted",
+			policy: &JobRetryPolicy{
+				MaxAttempts: 2,
+			},
+			status: JobStatus{
+				Phase:   JobPhaseFailed,
+				Attempt: 2,
+			},
+			expectedRetry: false,
+		},
+		{
+			name: "timed out with default retryable phases",
+			policy: &JobRetryPolicy{
+				MaxAttempts: 2,
+			},
+			status: JobStatus{
+				Phase: JobPhaseTimedOut,
+			},
+			expectedRetry: true,
+		},
+		{
+			name: "phase not retryable",
+			policy: &JobRetryPolicy{
+				MaxAttempts:     2,
+				RetryablePhases: []JobPhase{JobPhaseTimedOut},
+			},
+			status: JobStatus{
+				Phase: JobPhaseFailed,
+			},
+			expectedRetry: false,
+		},
+		{
+			name: "aborted",
+			policy: &JobRetryPolicy{
+				MaxAttempts: 2,
+			},
+			status: JobStatus{
+				Phase: JobPhaseAborted,
+			},
+			expectedRetry: false,
+		},
+		{
+			name: "failed without exit code",
+			policy: &JobRetryPolicy{
+				MaxAttempts:        2,
+				RetryableExitCodes: []int32{42},
+			},
+			status: JobStatus{
+				Phase: JobPhaseFailed,
+			},
+			expectedRetry: false,
+		},
+		{
+			name: "failed with retryable exit code",
+			policy: &JobRetryPolicy{
+				MaxAttempts:        2,
+				RetryableExitCodes: []int32{1, 42},
+			},
+			status: JobStatus{
+				Phase:    JobPhaseFailed,
+				ExitCode: &exitCode,
+			},
+			expectedRetry: true,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			require.Equal(
+				t,
+				testCase.expectedRetry,
+				testCase.policy.permitsRetry(testCase.status),
+			)
+		})
+	}
+}
+
+func TestJobRetryPolicyBackoff(t *testing.T) {
+	policy := &JobRetryPolicy{
+		BackoffDuration: "10h",
+	}
+	require.Equal(t, 10*time.Hour, policy.backoff(1))
+	require.Equal(t, 20*time.Hour, policy.backoff(2))
+	require.Equal(t, maxJobRetryBackoff, policy.backoff(3))
+	require.Equal(t, maxJobRetryBackoff, policy.backoff(100))
+	policy = &JobRetryPolicy{}
+	require.Equal(t, time.Duration(0), policy.backoff(3))
+}
+
+func TestValidateJobRetryPolicy(t *testing.T) {
+	testCases := []struct {
+		name       string
+		policy     *JobRetryPolicy
+		assertions func(error)
+	}{
+		{
+			name: "nil policy",
+			assertions: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "invalid policy",
+			policy: &JobRetryPolicy{
+				BackoffDuration: "forever",
+				RetryablePhases: []JobPhase{JobPhaseAborted},
+			},
+			assertions: func(err error) {
+				require.Error(t, err)
+				require.IsType(t, &meta.ErrBadRequest{}, err)
+				badReqErr := err.(*meta.ErrBadRequest)
+				require.Equal(t, "Invalid retry policy.", badReqErr.Reason)
+				require.Len(t, badReqErr.Details, 3)
+			},
+		},
+		{
+			name: "negative backoff",
+			policy: &JobRetryPolicy{
+				MaxAttempts:     3,
+				BackoffDuration: "-1m",
+			},
+			assertions: func(err error) {
+				require.Error(t, err)
+				require.IsType(t, &meta.ErrBadRequest{}, err)
+				require.Equal(
+					t,
+					[]string{`backoff duration "-1m" is negative`},
+					err.(*meta.ErrBadRequest).Details,
+				)
+			},
+		},
+		{
+			name: "valid policy",
+			policy: &JobRetryPolicy{
+				MaxAttempts:     3,
+				BackoffDuration: "30s",
+				RetryablePhases: []JobPhase{JobPhaseFailed, JobPhaseTimedOut},
+			},
+			assertions: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.assertions(validateJobRetryPolicy(testCase.policy))
+		})
+	}
+}
+
+func TestNewJobRetryService(t *testing.T) {
+	projectsStore := &mockProjectsStore{}
+	jobsStore := &mockJobsStore{}
+	substrate := &mockSubstrate{}
+	config := JobRetryServiceConfig{
+		Interval: time.Second,
+	}
+	svc, ok := NewJobRetryService(
+		projectsStore,
+		jobsStore,
+		substrate,
+		config,
+	).(*jobRetryService)
+	require.True(t, ok)
+	require.Same(t, projectsStore, svc.projectsStore)
+	require.Same(t, jobsStore, svc.jobsStore)
+	require.Same(t, substrate, svc.substrate)
+	require.Equal(t, config, svc.config)
+	require.NotNil(t, svc.nowFn)
+}
+
+func TestJobRetryServiceRetryAll(t *testing.T) {
+	testCases := []struct {
+		name       string
+		service    *jobRetryService
+		assertions func(error)
+	}{
+		{
+			name: "error claiming job retry",
+			service: &jobRetryService{
+				jobsStore: &mockJobsStore{
+					ClaimRetryFn: func(
+						context.Context,
+						time.Time,
+					) (*Event, string, error) {
+						return nil, "", errors.New("something went wrong")
+					},
+				},
+			},
+			assertions: func(err error) {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), "something went wrong")
+				require.Contains(t, err.Error(), "error claiming job retry")
+			},
+		},
+		{
+			name: "success",
+			service: func() *jobRetryService {
+				claimed := false
+				return &jobRetryService{
+					projectsStore: &mockProjectsStore{
+						GetFn: func(context.Context, string) (Project, error) {
+							return Project{}, nil
+						},
+					},
+					jobsStore: &mockJobsStore{
+						ClaimRetryFn: func(
+							context.Context,
+							time.Time,
+						) (*Event, string, error) {
+							if claimed {
+								return nil, "", nil
+							}
+							claimed = true
+							return &Event{
+								ObjectMeta: meta.ObjectMeta{
+									ID: "123456789",
+								},
+							}, "italian", nil
+						},
+					},
+					substrate: &mockSubstrate{
+						ScheduleJobFn: func(
+							_ context.Context,
+							_ Project,
+							event Event,
+							jobName string,
+						) error {
+							require.Equal(t, "123456789", event.ID)
+							require.Equal(t, "italian", jobName)
+							return nil
+						},
+					},
+				}
+			}(),
+			assertions: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.service.nowFn = time.Now
+			testCase.assertions(
+				testCase.service.retryAll(context.Background()),
+			)
+		})
+	}
+}
+
+func TestJobRetryServiceRetry(t *testing.T) {
+	const testJobName = "italian"
+	now := time.Now().UTC()
+	testEvent := Event{
+		ObjectMeta: meta.ObjectMeta{
+			ID: "123456789",
+		},
+		Worker: Worker{
+			Jobs: []Job{
+				{
+					Name: testJobName,
+					Status: &JobStatus{
+						Phase:   JobPhasePending,
+						Attempt: 2,
+					},
+				},
+			},
+		},
+	}
+	testCases := []struct {
+		name       string
+		service    *jobRetryService
+		assertions func(error)
+	}{
+		{
+			name: "error scheduling job",
+			service: &jobRetryService{
+				projectsStore: &mockProjectsStore{
+					GetFn: func(context.Context, string) (Project, error) {
+						return Project{}, nil
+					},
+				},
+				jobsStore: &mockJobsStore{
+					UpdateStatusFn: func(
+						_ context.Context,
+						_ string,
+						jobName string,
+						status JobStatus,
+					) error {
+						require.Equal(t, testJobName, jobName)
+						require.Equal(t, JobPhasePending, status.Phase)
+						require.Equal(t, 2, status.Attempt)
+						require.Equal(t, now.Add(time.Minute), *status.NextAttempt)
+						return nil
+					},
+				},
+				substrate: &mockSubstrate{
+					ScheduleJobFn: func(context.Context, Project, Event, string) error {
+						return errors.New("something went wrong")
+					},
+				},
+			},
+			assertions: func(err error) {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), "something went wrong")
+				require.Contains(t, err.Error(), "error scheduling event")
+			},
+		},
+		{
+			name: "success",
+			service: &jobRetryService{
+				projectsStore: &mockProjectsStore{
+					GetFn: func(context.Context, string) (Project, error) {
+						return Project{}, nil
+					},
+				},
+				substrate: &mockSubstrate{
+					ScheduleJobFn: func(context.Context, Project, Event, string) error {
+						return nil
+					},
+				},
+			},
+			assertions: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.service.config.Interval = time.Minute
+			testCase.service.nowFn = func() time.Time {
+				return now
+			}
+			testCase.assertions(
+				testCase.service.retry(context.Background(), testEvent, testJobName),
+			)
+		})
+	}
+}
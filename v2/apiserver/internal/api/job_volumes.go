@@ -0,0 +1,347 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"github.com/brigadecore/brigade/v2/apiserver/internal/meta"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// JobVolumeType represents a type of additional volume that a Job's OCI
+// containers may mount.
+type JobVolumeType string
+
+const (
+	// JobVolumeTypeSecrets represents a volume containing selected Project
+	// secrets as files.
+	JobVolumeTypeSecrets JobVolumeType = "secrets"
+	// JobVolumeTypeConfigFiles represents a volume containing files whose
+	// content is specified inline.
+	JobVolumeTypeConfigFiles JobVolumeType = "configFiles"
+	// JobVolumeTypeTmpfs represents memory-backed scratch space.
+	JobVolumeTypeTmpfs JobVolumeType = "tmpfs"
+	// JobVolumeTypeCache represents a named, Project-wide volume whose contents
+	// persist across Events.
+	JobVolumeTypeCache JobVolumeType = "cache"
+)
+
+var (
+	jobVolumeFileNameRegex  = regexp.MustCompile(`^[-._a-zA-Z0-9]+$`)
+	jobVolumeCacheNameRegex = regexp.MustCompile(`^[a-z][a-z\d-]*[a-z\d]$`)
+)
+
+// maxJobVolumeCacheNameLength is the maximum length of the name of a cache
+// volume. This leaves room for a prefix in the names of underlying substrate
+// resources.
+const maxJobVolumeCacheNameLength = 50
+
+// JobVolume represents an additional volume to be mounted into one of a Job's
+// OCI containers. Exactly one of Secrets, ConfigFiles, Tmpfs, or Cache must be
+// specified. Note the types of volumes that may be mounted are subject to
+// Project-level configuration.
+type JobVolume struct {
+	// MountPath specifies the path in the OCI container's file system where the
+	// volume should be mounted.
+	MountPath string `json:"mountPath" bson:"mountPath"`
+	// ReadOnly indicates whether the volume should be mounted read-only. Volumes
+	// containing secrets or config files are always mounted read-only.
+	ReadOnly bool `json:"readOnly,omitempty" bson:"readOnly,omitempty"`
+	// Secrets specifies a volume containing selected Project secrets as files.
+	Secrets *SecretsVolumeSource `json:"secrets,omitempty" bson:"secrets,omitempty"` // nolint: lll
+	// ConfigFiles specifies a volume containing files whose content is specified
+	// inline.
+	ConfigFiles *ConfigFilesVolumeSource `json:"configFiles,omitempty" bson:"configFiles,omitempty"` // nolint: lll
+	// Tmpfs specifies memory-backed scratch space.
+	Tmpfs *TmpfsVolumeSource `json:"tmpfs,omitempty" bson:"tmpfs,omitempty"`
+	// Cache specifies a named, Project-wide volume whose contents persist across
+	// Events.
+	Cache *CacheVolumeSource `json:"cache,omitempty" bson:"cache,omitempty"`
+}
+
+// Type returns the JobVolumeType of the JobVolume or an empty string if the
+// JobVolume does not specify exactly one volume source.
+func (j JobVolume) Type() JobVolumeType {
+	var volumeType JobVolumeType
+	var count int
+	if j.Secrets != nil {
+		volumeType = JobVolumeTypeSecrets
+		count++
+	}
+	if j.ConfigFiles != nil {
+		volumeType = JobVolumeTypeConfigFiles
+		count++
+	}
+	if j.Tmpfs != nil {
+		volumeType = JobVolumeTypeTmpfs
+		count++
+	}
+	if j.Cache != nil {
+		volumeType = JobVolumeTypeCache
+		count++
+	}
+	if count != 1 {
+		return ""
+	}
+	return volumeType
+}
+
+// SecretsVolumeSource represents a volume containing selected Project secrets
+// as files.
+type SecretsVolumeSource struct {
+	// Keys enumerates the keys of the Project secrets to include in the volume.
+	// Each secret is represented by a file named after its key.
+	Keys []string `json:"keys" bson:"keys"`
+}
+
+// ConfigFilesVolumeSource represents a volume containing files whose content
+// is specified inline.
+type ConfigFilesVolumeSource struct {
+	// Files is a map of file names to file contents. Since file contents may
+	// contain sensitive information, they are redacted before the Job is
+	// persisted, just like the values of environment variables.
+	Files map[string]string `json:"files" bson:"files"`
+}
+
+// TmpfsVolumeSource represents memory-backed scratch space.
+type TmpfsVolumeSource struct {
+	// SizeLimit optionally limits how much memory the volume may consume.
+	SizeLimit string `json:"sizeLimit,omitempty" bson:"sizeLimit,omitempty"`
+}
+
+// CacheVolumeSource represents a named, Project-wide volume whose contents
+// persist across Events. It is intended for things like package manager
+// caches. Any of the Project's Jobs that mount a cache volume having the same
+// name share the same underlying storage, so cache volumes must not be relied
+// upon to hold anything other than data that can be recreated if necessary.
+type CacheVolumeSource struct {
+	// Name identifies the cache volume.
+	Name string `json:"name" bson:"name"`
+	// Size optionally specifies the size of the cache volume. It is only
+	// honored when the cache volume is first used. If not specified, this
+	// defaults to 1G.
+	Size string `json:"size,omitempty" bson:"size,omitempty"`
+}
+
+// jobVolumes returns all JobVolumes requested by the provided Job's OCI
+// containers.
+func jobVolumes(spec JobSpec) []JobVolume {
+	volumes := spec.PrimaryContainer.Volumes
+	for _, sidecarContainer := range spec.SidecarContainers {
+		volumes = append(volumes, sidecarContainer.Volumes...)
+	}
+	return volumes
+}
+
+// validateJobVolumes returns a *meta.ErrBadRequest error if any of the provided
+// JobSpec's OCI containers requests an invalid JobVolume.
+func validateJobVolumes(spec JobSpec) error {
+	details := validateJobContainerVolumes(spec.PrimaryContainer)
+	for sidecarName, sidecarContainer := range spec.SidecarContainers {
+		for _, detail := range validateJobContainerVolumes(sidecarContainer) {
+			details = append(
+				details,
+				fmt.Sprintf("sidecar container %q: %s", sidecarName, detail),
+			)
+		}
+	}
+	if len(details) > 0 {
+		return &meta.ErrBadRequest{
+			Reason:  "Invalid job volumes.",
+			Details: details,
+		}
+	}
+	return nil
+}
+
+// validateJobContainerVolumes returns details of any problems with the
+// JobVolumes requested by the provided JobContainerSpec.
+// nolint: gocyclo
+func validateJobContainerVolumes(spec JobContainerSpec) []string {
+	details := []string{}
+	mountPaths := map[string]struct{}{}
+	for _, mountPath := range []string{
+		spec.WorkspaceMountPath,
+		spec.SourceMountPath,
+	} {
+		if mountPath != "" {
+			mountPaths[mountPath] = struct{}{}
+		}
+	}
+	for i, volume := range spec.Volumes {
+		if volume.MountPath == "" {
+			details = append(details, fmt.Sprintf("volume %d has no mount path", i))
+		} else if _, ok := mountPaths[volume.MountPath]; ok {
+			details = append(
+				details,
+				fmt.Sprintf(
+					"volume %d mount path %q is already in use",
+					i,
+					volume.MountPath,
+				),
+			)
+		}
+		mountPaths[volume.MountPath] = struct{}{}
+		switch volume.Type() {
+		case JobVolumeTypeSecrets:
+			if len(volume.Secrets.Keys) == 0 {
+				details = append(
+					details,
+					fmt.Sprintf("volume %d specifies no secrets", i),
+				)
+			}
+		case JobVolumeTypeConfigFiles:
+			if len(volume.ConfigFiles.Files) == 0 {
+				details = append(
+					details,
+					fmt.Sprintf("volume %d specifies no config files", i),
+				)
+			}
+			for fileName := range volume.ConfigFiles.Files {
+				if !jobVolumeFileNameRegex.MatchString(fileName) ||
+					fileName == "." ||
+					fileName == ".." {
+					details = append(
+						details,
+						fmt.Sprintf("volume %d file name %q is invalid", i, fileName),
+					)
+				}
+			}
+		case JobVolumeTypeTmpfs:
+			if volume.Tmpfs.SizeLimit != "" {
+				if _, err := resource.ParseQuantity(volume.Tmpfs.SizeLimit); err != nil {
+					details = append(
+						details,
+						fmt.Sprintf(
+							"volume %d size limit %q is invalid",
+							i,
+							volume.Tmpfs.SizeLimit,
+						),
+					)
+				}
+			}
+		case JobVolumeTypeCache:
+			if len(volume.Cache.Name) > maxJobVolumeCacheNameLength ||
+				!jobVolumeCacheNameRegex.MatchString(volume.Cache.Name) {
+				details = append(
+					details,
+					fmt.Sprintf(
+						"volume %d cache name %q is invalid",
+						i,
+						volume.Cache.Name,
+					),
+				)
+			}
+			if volume.Cache.Size != "" {
+				if _, err := resource.ParseQuantity(volume.Cache.Size); err != nil {
+					details = append(
+						details,
+						fmt.Sprintf("volume %d size %q is invalid", i, volume.Cache.Size),
+					)
+				}
+			}
+		default:
+			details = append(
+				details,
+				fmt.Sprintf(
+					"volume %d must specify exactly one of secrets, configFiles, "+
+						"tmpfs, or cache",
+					i,
+				),
+			)
+		}
+	}
+	return details
+}
+
+// authorizeJobVolumes returns a *meta.ErrAuthorization error if the provided
+// JobPolicies do not permit any of the JobVolumes requested by the provided
+// JobSpec's OCI containers.
+func authorizeJobVolumes(spec JobSpec, policies *JobPolicies) error {
+	allowed := map[JobVolumeType]struct{}{}
+	if policies != nil {
+		for _, volumeType := range policies.AllowedVolumeTypes {
+			allowed[volumeType] = struct{}{}
+		}
+	}
+	for _, volume := range jobVolumes(spec) {
+		if _, ok := allowed[volume.Type()]; !ok {
+			return &meta.ErrAuthorization{
+				Reason: fmt.Sprintf(
+					"Worker configuration forbids jobs from mounting %q volumes.",
+					volume.Type(),
+				),
+			}
+		}
+	}
+	return nil
+}
+
+// checkJobVolumeSecrets returns a *meta.ErrBadRequest error if any of the
+// Project secrets to be mounted into the provided JobSpec's OCI containers does
+// not exist.
+func (j *jobsService) checkJobVolumeSecrets(
+	ctx context.Context,
+	project Project,
+	spec JobSpec,
+) error {
+	details := []string{}
+	checked := map[string]struct{}{}
+	for _, volume := range jobVolumes(spec) {
+		if volume.Secrets == nil {
+			continue
+		}
+		for _, key := range volume.Secrets.Keys {
+			if _, ok := checked[key]; ok {
+				continue
+			}
+			checked[key] = struct{}{}
+			if _, err := j.secretsStore.Get(ctx, project, key); err != nil {
+				if _, ok := errors.Cause(err).(*meta.ErrNotFound); ok {
+					details = append(
+						details,
+						fmt.Sprintf("project %q has no secret %q", project.ID, key),
+					)
+					continue
+				}
+				return errors.Wrapf(
+					err,
+					"error retrieving project %q secret %q from store",
+					project.ID,
+					key,
+				)
+			}
+		}
+	}
+	if len(details) > 0 {
+		return &meta.ErrBadRequest{
+			Reason:  "Invalid job volumes.",
+			Details: details,
+		}
+	}
+	return nil
+}
+
+// redactJobVolumes returns a copy of the provided JobVolumes with the contents
+// of any config files redacted.
+func redactJobVolumes(volumes []JobVolume) []JobVolume {
+	if volumes == nil {
+		return nil
+	}
+	redacted := make([]JobVolume, len(volumes))
+	for i, volume := range volumes {
+		if volume.ConfigFiles != nil {
+			// This needs to be a NEW map, otherwise as we mess with it, we're
+			// messing with the original since maps are references.
+			files := make(map[string]string, len(volume.ConfigFiles.Files))
+			for fileName := range volume.ConfigFiles.Files {
+				files[fileName] = "*** REDACTED ***"
+			}
+			volume.ConfigFiles = &ConfigFilesVolumeSource{Files: files}
+		}
+		redacted[i] = volume
+	}
+	return redacted
+}
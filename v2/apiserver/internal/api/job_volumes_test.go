@@ -0,0 +1,472 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/brigadecore/brigade/v2/apiserver/internal/meta"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJobVolumeType(t *testing.T) {
+	testCases := []struct {
+		name         string
+		volume       JobVolume
+		expectedType JobVolumeType
+	}{
+		{
+			name:         "no source",
+			volume:       JobVolume{},
+			expectedType: "",
+		},
+		{
+			name: "more than one source",
+			volume: JobVolume{
+				Tmpfs: &TmpfsVolumeSource{},
+				Cache: &CacheVolumeSource{},
+			},
+			expectedType: "",
+		},
+		{
+			name: "secrets",
+			volume: JobVolume{
+				Secrets: &SecretsVolumeSource{},
+			},
+			expectedType: JobVolumeTypeSecrets,
+		},
+		{
+			name: "config files",
+			volume: JobVolume{
+				ConfigFiles: &ConfigFilesVolumeSource{},
+			},
+			expectedType: JobVolumeTypeConfigFiles,
+		},
+		{
+			name: "tmpfs",
+			volume: JobVolume{
+				Tmpfs: &TmpfsVolumeSource{},
+			},
+			expectedType: JobVolumeTypeTmpfs,
+		},
+		{
+			name: "cache",
+			volume: JobVolume{
+				Cache: &CacheVolumeSource{},
+			},
+			expectedType: JobVolumeTypeCache,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			require.Equal(t, testCase.expectedType, testCase.volume.Type())
+		})
+	}
+}
+
+func TestValidateJobVolumes(t *testing.T) {
+	testCases := []struct {
+		name       string
+		spec       JobSpec
+		assertions func(error)
+	}{
+		{
+			name: "no volumes",
+			spec: JobSpec{},
+			assertions: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "invalid volumes",
+			spec: JobSpec{
+				PrimaryContainer: JobContainerSpec{
+					WorkspaceMountPath: "/var/workspace",
+					Volumes: []JobVolume{
+						{
+							MountPath: "/var/workspace",
+							Secrets:   &SecretsVolumeSource{},
+						},
+						{
+							MountPath: "/etc/foo",
+							ConfigFiles: &ConfigFilesVolumeSource{
+								Files: map[string]string{
+									"..": "bar",
+								},
+							},
+						},
+						{
+							Tmpfs: &TmpfsVolumeSource{
+								SizeLimit: "lots",
+							},
+						},
+						{
+							MountPath: "/var/cache",
+							Cache: &CacheVolumeSource{
+								Name: "Go",
+								Size: "lots",
+							},
+						},
+					},
+				},
+				SidecarContainers: map[string]JobContainerSpec{
+					"helper": {
+						Volumes: []JobVolume{
+							{
+								MountPath: "/var/cache",
+							},
+						},
+					},
+				},
+			},
+			assertions: func(err error) {
+				require.Error(t, err)
+				require.IsType(t, &meta.ErrBadRequest{}, err)
+				badReqErr := err.(*meta.ErrBadRequest)
+				require.Equal(t, "Invalid job volumes.", badReqErr.Reason)
+				require.Equal(
+					t,
+					[]string{
+						`volume 0 mount path "/var/workspace" is already in use`,
+						"volume 0 specifies no secrets",
+						`volume 1 file name ".." is invalid`,
+						"volume 2 has no mount path",
+						`volume 2 size limit "lots" is invalid`,
+						`volume 3 cache name "Go" is invalid`,
+						`volume 3 size "lots" is invalid`,
+						`sidecar container "helper": volume 0 must specify exactly one ` +
+							"of secrets, configFiles, tmpfs, or cache",
+					},
+					badReqErr.Details,
+				)
+			},
+		},
+		{
+			name: "valid volumes",
+			spec: JobSpec{
+				PrimaryContainer: JobContainerSpec{
+					Volumes: []JobVolume{
+						{
+							MountPath: "/var/secrets",
+							Secrets: &SecretsVolumeSource{
+								Keys: []string{"token"},
+							},
+						},
+						{
+							MountPath: "/etc/foo",
+							ConfigFiles: &ConfigFilesVolumeSource{
+								Files: map[string]string{
+									"foo.yaml": "bar: bat",
+								},
+							},
+						},
+						{
+							MountPath: "/tmp",
+							Tmpfs: &TmpfsVolumeSource{
+								SizeLimit: "64Mi",
+							},
+						},
+						{
+							MountPath: "/var/cache",
+							Cache: &CacheVolumeSource{
+								Name: "go-modules",
+								Size: "5Gi",
+							},
+						},
+					},
+				},
+			},
+			assertions: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.assertions(validateJobVolumes(testCase.spec))
+		})
+	}
+}
+
+func TestAuthorizeJobVolumes(t *testing.T) {
+	testSpec := JobSpec{
+		PrimaryContainer: JobContainerSpec{
+			Volumes: []JobVolume{
+				{
+					MountPath: "/tmp",
+					Tmpfs:     &TmpfsVolumeSource{},
+				},
+			},
+		},
+		SidecarContainers: map[string]JobContainerSpec{
+			"helper": {
+				Volumes: []JobVolume{
+					{
+						MountPath: "/var/cache",
+						Cache: &CacheVolumeSource{
+							Name: "go-modules",
+						},
+					},
+				},
+			},
+		},
+	}
+	testCases := []struct {
+		name       string
+		spec       JobSpec
+		policies   *JobPolicies
+		assertions func(error)
+	}{
+		{
+			name: "no volumes",
+			spec: JobSpec{},
+			assertions: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "no policies",
+			spec: testSpec,
+			assertions: func(err error) {
+				require.Error(t, err)
+				require.IsType(t, &meta.ErrAuthorization{}, err)
+				require.Equal(
+					t,
+					`Worker configuration forbids jobs from mounting "tmpfs" volumes.`,
+					err.(*meta.ErrAuthorization).Reason,
+				)
+			},
+		},
+		{
+			name: "volume type not allowed",
+			spec: testSpec,
+			policies: &JobPolicies{
+				AllowedVolumeTypes: []JobVolumeType{JobVolumeTypeTmpfs},
+			},
+			assertions: func(err error) {
+				require.Error(t, err)
+				require.IsType(t, &meta.ErrAuthorization{}, err)
+				require.Equal(
+					t,
+					`Worker configuration forbids jobs from mounting "cache" volumes.`,
+					err.(*meta.ErrAuthorization).Reason,
+				)
+			},
+		},
+		{
+			name: "all volume types allowed",
+			spec: testSpec,
+			policies: &JobPolicies{
+				AllowedVolumeTypes: []JobVolumeType{
+					JobVolumeTypeTmpfs,
+					JobVolumeTypeCache,
+				},
+			},
+			assertions: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.assertions(
+				authorizeJobVolumes(testCase.spec, testCase.policies),
+			)
+		})
+	}
+}
+
+func TestJobsServiceCheckJobVolumeSecrets(t *testing.T) {
+	testProject := Project{
+		ObjectMeta: meta.ObjectMeta{
+			ID: "italian",
+		},
+	}
+	testSpec := JobSpec{
+		PrimaryContainer: JobContainerSpec{
+			Volumes: []JobVolume{
+				{
+					MountPath: "/var/secrets",
+					Secrets: &SecretsVolumeSource{
+						Keys: []string{"foo", "bar"},
+					},
+				},
+			},
+		},
+	}
+	testCases := []struct {
+		name         string
+		secretsStore SecretsStore
+		assertions   func(error)
+	}{
+		{
+			name: "error retrieving secret from store",
+			secretsStore: &mockSecretsStore{
+				GetFn: func(context.Context, Project, string) (Secret, error) {
+					return Secret{}, errors.New("something went wrong")
+				},
+			},
+			assertions: func(err error) {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), "something went wrong")
+				require.Contains(t, err.Error(), "error retrieving project")
+			},
+		},
+		{
+			name: "secret does not exist",
+			secretsStore: &mockSecretsStore{
+				GetFn: func(_ context.Context, _ Project, key string) (Secret, error) {
+					if key == "bar" {
+						return Secret{}, &meta.ErrNotFound{}
+					}
+					return Secret{Key: key}, nil
+				},
+			},
+			assertions: func(err error) {
+				require.Error(t, err)
+				require.IsType(t, &meta.ErrBadRequest{}, err)
+				require.Equal(
+					t,
+					[]string{`project "italian" has no secret "bar"`},
+					err.(*meta.ErrBadRequest).Details,
+				)
+			},
+		},
+		{
+			name: "success",
+			secretsStore: &mockSecretsStore{
+				GetFn: func(_ context.Context, _ Project, key string) (Secret, error) {
+					return Secret{Key: key}, nil
+				},
+			},
+			assertions: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			service := &jobsService{
+				secretsStore: testCase.secretsStore,
+			}
+			testCase.assertions(
+				service.checkJobVolumeSecrets(
+					context.Background(),
+					testProject,
+					testSpec,
+				),
+			)
+		})
+	}
+}
+
+func TestRedactJobVolumes(t *testing.T) {
+	require.Nil(t, redactJobVolumes(nil))
+	volumes := []JobVolume{
+		{
+			MountPath: "/etc/foo",
+			ConfigFiles: &ConfigFilesVolumeSource{
+				Files: map[string]string{
+					"foo.yaml": "bar: bat",
+				},
+			},
+		},
+		{
+			MountPath: "/tmp",
+			Tmpfs:     &TmpfsVolumeSource{},
+		},
+	}
+	redacted := redactJobVolumes(volumes)
+	require.Equal(
+		t,
+		map[string]string{"foo.yaml": "*** REDACTED ***"},
+		redacted[0].ConfigFiles.Files,
+	)
+	require.Equal(t, volumes[1], redacted[1])
+	// The original should be unaltered
+	require.Equal(t, "bar: bat", volumes[0].ConfigFiles.Files["foo.yaml"])
+}
+
+func TestJobsServiceCreateWithVolumes(t *testing.T) {
+	const testEventID = "123456789"
+	var storedSpec JobSpec
+	service := &jobsService{
+		authorize: alwaysAuthorize,
+		eventsStore: &mockEventsStore{
+			GetFn: func(context.Context, string) (Event, error) {
+				return Event{
+					ObjectMeta: meta.ObjectMeta{
+						ID: testEventID,
+					},
+					Worker: Worker{
+						Spec: WorkerSpec{
+							JobPolicies: &JobPolicies{
+								AllowedVolumeTypes: []JobVolumeType{
+									JobVolumeTypeConfigFiles,
+								},
+							},
+						},
+					},
+				}, nil
+			},
+		},
+		projectsStore: &mockProjectsStore{
+			GetFn: func(context.Context, string) (Project, error) {
+				return Project{}, nil
+			},
+		},
+		jobsStore: &mockJobsStore{
+			CreateFn: func(_ context.Context, _ string, job Job) error {
+				require.Equal(
+					t,
+					map[string]string{"foo.yaml": "*** REDACTED ***"},
+					job.Spec.PrimaryContainer.Volumes[0].ConfigFiles.Files,
+				)
+				return nil
+			},
+		},
+		substrate: &mockSubstrate{
+			StoreJobEnvironmentFn: func(
+				_ context.Context,
+				_ Project,
+				_ string,
+				_ string,
+				spec JobSpec,
+			) error {
+				storedSpec = spec
+				return nil
+			},
+			ScheduleJobFn: func(context.Context, Project, Event, string) error {
+				return nil
+			},
+		},
+	}
+	err := service.Create(
+		context.Background(),
+		testEventID,
+		Job{
+			Name: "italian",
+			Spec: JobSpec{
+				PrimaryContainer: JobContainerSpec{
+					Volumes: []JobVolume{
+						{
+							MountPath: "/etc/foo",
+							ConfigFiles: &ConfigFilesVolumeSource{
+								Files: map[string]string{
+									"foo.yaml": "bar: bat",
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	)
+	require.NoError(t, err)
+	// The substrate should have received the unredacted config files
+	require.Equal(
+		t,
+		map[string]string{"foo.yaml": "bar: bat"},
+		storedSpec.PrimaryContainer.Volumes[0].ConfigFiles.Files,
+	)
+}
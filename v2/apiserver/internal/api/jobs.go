@@ -14,6 +14,12 @@ import (
 // JobKind represents the canonical Job kind string
 const JobKind = "Job"
 
+// maxJobOutputsSize is the maximum permissible size, in bytes, of all of a
+// Job's output keys and values combined. Outputs are stored alongside the Job
+// itself and are meant for small values only. Anything larger ought to be
+// stored as an Artifact.
+const maxJobOutputsSize = 32 * 1024
+
 // OSFamily represents a type of operating system.
 type OSFamily string
 
@@ -93,6 +99,10 @@ type Job struct {
 	Spec JobSpec `json:"spec" bson:"spec"`
 	// Status contains details of the Job's current state.
 	Status *JobStatus `json:"status" bson:"status"`
+	// CacheKey identifies the results of a cacheable Job. Since it is derived
+	// from the Job's specification BEFORE the values of environment variables
+	// are redacted, it is never exposed via the API.
+	CacheKey string `json:"-" bson:"cacheKey,omitempty"`
 }
 
 // UsesWorkspace returns a boolean value indicating whether or not the job
@@ -140,6 +150,15 @@ type JobSpec struct {
 	// but it is information that may be valuable to gateways that report job
 	// success/failure upstream to original event sources.
 	Fallible bool `json:"fallible" bson:"fallible"`
+	// RetryPolicy optionally specifies whether and how the Job should be
+	// automatically re-attempted if it does not succeed.
+	RetryPolicy *JobRetryPolicy `json:"retryPolicy,omitempty" bson:"retryPolicy,omitempty"` // nolint: lll
+	// DependsOn optionally lists the names of other Jobs of the same Event that
+	// must succeed before this Job may be started. If any of them does not
+	// succeed, this Job is canceled without ever having been started.
+	DependsOn []string `json:"dependsOn,omitempty" bson:"dependsOn,omitempty"`
+	// Cache optionally opts the Job into cross-Event result caching.
+	Cache *JobCachePolicy `json:"cache,omitempty" bson:"cache,omitempty"`
 }
 
 func (js JobSpec) EqualTo(js2 JobSpec) bool {
@@ -196,6 +215,10 @@ type JobContainerSpec struct {
 	// for the container, but that may be disallowed by Project-level
 	// configuration.
 	Privileged bool `json:"privileged" bson:"privileged"`
+	// Volumes optionally specifies additional volumes to be mounted into the OCI
+	// container. Note the types of volumes that may be mounted are subject to
+	// Project-level configuration.
+	Volumes []JobVolume `json:"volumes,omitempty" bson:"volumes,omitempty"`
 	// UseHostDockerSocket indicates whether the OCI container should mount the
 	// host's Docker socket into its own file system. This is commonly used to
 	// effect "Docker-out-of-Docker" ("DooD") scenarios wherein one of a Job's OCI
@@ -236,6 +259,18 @@ type JobHost struct {
 	// host a Job. This provides an opaque mechanism for communicating Job needs
 	// such as specific hardware like an SSD or GPU.
 	NodeSelector map[string]string `json:"nodeSelector,omitempty" bson:"nodeSelector,omitempty"` // nolint: lll
+	// Tolerations permit the Job to be hosted on substrate nodes having matching
+	// taints. Note the Tolerations a Job may specify are subject to
+	// Project-level configuration.
+	Tolerations []Toleration `json:"tolerations,omitempty" bson:"tolerations,omitempty"` // nolint: lll
+	// NodeAffinity specifies requirements, beyond NodeSelector, that a substrate
+	// node must or should satisfy to host the Job. Note whether a Job may specify
+	// NodeAffinity is subject to Project-level configuration.
+	NodeAffinity *NodeAffinity `json:"nodeAffinity,omitempty" bson:"nodeAffinity,omitempty"` // nolint: lll
+	// PriorityClassName specifies the substrate's priority class for the Job.
+	// Note the priority classes a Job may use are subject to Project-level
+	// configuration.
+	PriorityClassName string `json:"priorityClassName,omitempty" bson:"priorityClassName,omitempty"` // nolint: lll
 }
 
 func (jh *JobHost) EqualTo(jh2 *JobHost) bool {
@@ -266,6 +301,44 @@ type JobStatus struct {
 	// This is useful for looking up logs for an inherited job associated with
 	// retry events.
 	LogsEventID string `json:"logsEventID,omitempty" bson:"logsEventID,omitempty"`
+	// Attempt indicates which attempt at the Job this status describes. Attempts
+	// are numbered from 1. A value of 0 is equivalent to 1.
+	Attempt int `json:"attempt,omitempty" bson:"attempt,omitempty"`
+	// ExitCode is the exit code of the Job's primary container, if it has exited.
+	ExitCode *int32 `json:"exitCode,omitempty" bson:"exitCode,omitempty"`
+	// Reason is a brief, machine-readable explanation of why the Job is in its
+	// current phase, e.g. "OOMKilled", "ImagePullBackOff", or "Unschedulable".
+	Reason string `json:"reason,omitempty" bson:"reason,omitempty"`
+	// Message is a human-readable elaboration on Reason.
+	Message string `json:"message,omitempty" bson:"message,omitempty"`
+	// Containers describes the status of each of the Job's containers,
+	// including sidecars.
+	Containers []ContainerStatus `json:"containers,omitempty" bson:"containers,omitempty"` // nolint: lll
+	// NextAttempt indicates when the Job, having been PENDING since its previous
+	// attempt did not succeed, will next be attempted.
+	NextAttempt *time.Time `json:"nextAttempt,omitempty" bson:"nextAttempt,omitempty"` // nolint: lll
+	// Attempts lists every concluded attempt at a Job having a RetryPolicy,
+	// oldest first.
+	Attempts []JobAttempt `json:"attempts,omitempty" bson:"attempts,omitempty"`
+	// Outputs is a map of key/value pairs reported by the Job itself. These
+	// allow a Job to hand values (e.g. a built image's digest) back to the
+	// Worker, to subsequent Jobs, and to other API consumers.
+	Outputs JobOutputValues `json:"outputs,omitempty" bson:"outputs,omitempty"`
+}
+
+// JobOutputs encapsulates key/value pairs reported by a Job.
+type JobOutputs struct {
+	// Outputs is a map of key/value pairs reported by a Job.
+	Outputs map[string]string `json:"outputs,omitempty"`
+}
+
+// currentAttempt returns the number of the attempt at the Job that the
+// JobStatus describes.
+func (j JobStatus) currentAttempt() int {
+	if j.Attempt < 1 {
+		return 1
+	}
+	return j.Attempt
 }
 
 // JobsService is the specialized interface for managing Jobs. It's
@@ -311,36 +384,68 @@ type JobsService interface {
 		jobName string,
 		status JobStatus,
 	) error
+	// UpdateOutputs, given an Event identifier and Job name, merges the provided
+	// key/value pairs into that Job's outputs, replacing the values of any
+	// existing keys. If the specified Event or specified Job thereof does not
+	// exist, implementations MUST return a *meta.ErrNotFound error. If the
+	// resulting outputs would exceed the maximum permissible size,
+	// implementations MUST return a *meta.ErrBadRequest error.
+	UpdateOutputs(
+		ctx context.Context,
+		eventID string,
+		jobName string,
+		outputs JobOutputs,
+	) error
 	// Cleanup removes Job-related resources from the substrate, presumably
 	// upon completion, without deleting the Job from the data store.
 	Cleanup(ctx context.Context, eventID, jobName string) error
 	// Timeout updates a Job's status to indicate it has timed out and proceeds
 	// to cleanup Job-related resources from the substrate.
 	Timeout(ctx context.Context, eventID, jobName string) error
+	// Abort, given an Event identifier and Job name, updates that Job's status to
+	// indicate it has been aborted and proceeds to delete Job-related resources
+	// from the substrate. The Event's Worker and any other Jobs are unaffected.
+	// If the specified Event or specified Job thereof does not exist,
+	// implementations MUST return a *meta.ErrNotFound error. If the Job has
+	// already reached a terminal phase, implementations MUST return a
+	// *meta.ErrConflict error.
+	Abort(ctx context.Context, eventID, jobName string) error
 }
 
 type jobsService struct {
-	authorize     AuthorizeFn
-	projectsStore ProjectsStore
-	eventsStore   EventsStore
-	jobsStore     JobsStore
-	substrate     Substrate
+	authorize        AuthorizeFn
+	projectAuthorize ProjectAuthorizeFn
+	projectsStore    ProjectsStore
+	eventsStore      EventsStore
+	jobsStore        JobsStore
+	jobCacheStore    JobCacheStore
+	secretsStore     SecretsStore
+	substrate        Substrate
+	notifier         Notifier
 }
 
 // NewJobsService returns a specialized interface for managing Jobs.
 func NewJobsService(
 	authorizeFn AuthorizeFn,
+	projectAuthorize ProjectAuthorizeFn,
 	projectsStore ProjectsStore,
 	eventsStore EventsStore,
 	jobsStore JobsStore,
+	jobCacheStore JobCacheStore,
+	secretsStore SecretsStore,
 	substrate Substrate,
+	notifier Notifier,
 ) JobsService {
 	return &jobsService{
-		authorize:     authorizeFn,
-		projectsStore: projectsStore,
-		eventsStore:   eventsStore,
-		jobsStore:     jobsStore,
-		substrate:     substrate,
+		authorize:        authorizeFn,
+		projectAuthorize: projectAuthorize,
+		projectsStore:    projectsStore,
+		eventsStore:      eventsStore,
+		jobsStore:        jobsStore,
+		jobCacheStore:    jobCacheStore,
+		secretsStore:     secretsStore,
+		substrate:        substrate,
+		notifier:         notifier,
 	}
 }
 
@@ -358,6 +463,33 @@ func (j *jobsService) Create(
 	if err != nil {
 		return errors.Wrapf(err, "error retrieving event %q from store", eventID)
 	}
+
+	project, err := j.projectsStore.Get(ctx, event.ProjectID)
+	if err != nil {
+		return errors.Wrapf(
+			err,
+			"error retrieving project %q from store",
+			event.ProjectID,
+		)
+	}
+
+	// Apply Project-level resource defaults to all of the job's containers
+	// before anything else, so that a retried job's spec can be compared to the
+	// original's on equal footing.
+	job.Spec.PrimaryContainer.Resources =
+		project.Spec.Resources.ApplyTo(job.Spec.PrimaryContainer.Resources)
+	if len(job.Spec.SidecarContainers) > 0 {
+		// This needs to be a NEW map, otherwise as we mess with it, we're messing
+		// with the original since maps are references.
+		sidecarContainers :=
+			make(map[string]JobContainerSpec, len(job.Spec.SidecarContainers))
+		for sidecarName, sidecar := range job.Spec.SidecarContainers {
+			sidecar.Resources = project.Spec.Resources.ApplyTo(sidecar.Resources)
+			sidecarContainers[sidecarName] = sidecar
+		}
+		job.Spec.SidecarContainers = sidecarContainers
+	}
+
 	if originalJob, ok := event.Worker.Job(job.Name); ok {
 		// If this is not a retry event, return ErrConflict.
 		if event.Labels == nil || event.Labels[RetryLabelKey] == "" {
@@ -424,6 +556,14 @@ func (j *jobsService) Create(
 				"containers.",
 		}
 	}
+	if err :=
+		authorizeJobVolumes(job.Spec, event.Worker.Spec.JobPolicies); err != nil {
+		return err
+	}
+	if err :=
+		authorizeJobHost(job.Spec.Host, event.Worker.Spec.JobPolicies); err != nil {
+		return err
+	}
 	// if useDockerSocket &&
 	// 	(event.Worker.Spec.JobPolicies == nil ||
 	// 		!event.Worker.Spec.JobPolicies.AllowDockerSocketMount) {
@@ -442,22 +582,55 @@ func (j *jobsService) Create(
 		}
 	}
 
+	// Fail quickly if any of the job's containers requests or is limited to
+	// more compute resources than the project permits.
+	if err := validateJobResources(job.Spec, project.Spec.Resources); err != nil {
+		return err
+	}
+
+	if err := validateJobRetryPolicy(job.Spec.RetryPolicy); err != nil {
+		return err
+	}
+
+	if err := validateJobDependencies(event, job); err != nil {
+		return err
+	}
+
+	if err := validateJobCachePolicy(job.Spec); err != nil {
+		return err
+	}
+
+	if err := validateJobVolumes(job.Spec); err != nil {
+		return err
+	}
+
+	if err := validateJobHost(job.Spec.Host); err != nil {
+		return err
+	}
+
+	if err := j.checkJobVolumeSecrets(ctx, project, job.Spec); err != nil {
+		return err
+	}
+
 	now := time.Now().UTC()
 	job.Created = &now
 
-	// Set the initial status
-	job.Status = &JobStatus{
-		Phase: JobPhasePending,
+	// Set the initial status. A Job that depends on a Job that has already
+	// concluded without succeeding will never run, so it is canceled outright.
+	dependenciesSatisfied, failedDependency :=
+		checkJobDependencies(event, job.Spec.DependsOn)
+	if failedDependency != "" {
+		status := dependencyFailedStatus(failedDependency)
+		job.Status = &status
+	} else {
+		job.Status = &JobStatus{
+			Phase: JobPhasePending,
+		}
 	}
 
-	project, err := j.projectsStore.Get(ctx, event.ProjectID)
-	if err != nil {
-		return errors.Wrapf(
-			err,
-			"error retrieving project %q from store",
-			event.ProjectID,
-		)
-	}
+	// The cache key must account for the values of the Job's environment
+	// variables, so it is derived before they are redacted.
+	job.CacheKey, _ = jobCacheKey(event, job.Name, job.Spec)
 
 	// Redact the values of the Job's environment variables in the job we persist
 	// because they are likely to contain secrets.
@@ -468,6 +641,9 @@ func (j *jobsService) Create(
 	for k := range job.Spec.PrimaryContainer.Environment {
 		jobCopy.Spec.PrimaryContainer.Environment[k] = "*** REDACTED ***"
 	}
+	// Config files are likely to contain secrets as well.
+	jobCopy.Spec.PrimaryContainer.Volumes =
+		redactJobVolumes(job.Spec.PrimaryContainer.Volumes)
 	// This needs to be a NEW map, otherwise as we mess with it, we're messing
 	// with the original since maps are references.
 	jobCopy.Spec.SidecarContainers = map[string]JobContainerSpec{}
@@ -478,9 +654,22 @@ func (j *jobsService) Create(
 		for k := range job.Spec.SidecarContainers[sidecarName].Environment {
 			sidecar.Environment[k] = "*** REDACTED ***"
 		}
+		sidecar.Volumes = redactJobVolumes(sidecar.Volumes)
 		jobCopy.Spec.SidecarContainers[sidecarName] = sidecar
 	}
 
+	// If the Job is ready to run, but the results of an earlier, equivalent Job
+	// can be reused, there is no need to run it at all.
+	var cachedStatus *JobStatus
+	if dependenciesSatisfied {
+		if cachedStatus, err = j.cachedJobStatus(ctx, event, jobCopy); err != nil {
+			return err
+		}
+		if cachedStatus != nil {
+			jobCopy.Status = cachedStatus
+		}
+	}
+
 	if err = j.jobsStore.Create(ctx, eventID, jobCopy); err != nil {
 		return errors.Wrapf(
 			err, "error saving event %q job %q in store",
@@ -489,6 +678,32 @@ func (j *jobsService) Create(
 		)
 	}
 
+	if failedDependency != "" {
+		return nil
+	}
+
+	if cachedStatus != nil {
+		if err = j.notifier.NotifyJobPhase(
+			ctx,
+			event,
+			job.Name,
+			cachedStatus.Phase,
+		); err != nil {
+			// A failure to notify interested parties isn't allowed to fail the
+			// creation of the job itself.
+			log.Println(
+				errors.Wrapf(
+					err,
+					"error sending notifications of event %q job %q phase %q",
+					event.ID,
+					job.Name,
+					cachedStatus.Phase,
+				),
+			)
+		}
+		return nil
+	}
+
 	// Securely store the Job's environment variables
 	if err = j.substrate.StoreJobEnvironment(
 		ctx,
@@ -505,6 +720,15 @@ func (j *jobsService) Create(
 		)
 	}
 
+	// A Job with outstanding dependencies is scheduled only once they have all
+	// succeeded. See resolveJobDependents(). If the last of them concluded after
+	// the Event was retrieved above, but before the Job was persisted, it would
+	// have been unable to resolve this Job, so this Job must now resolve itself.
+	if !dependenciesSatisfied {
+		j.resolvePendingJob(ctx, eventID, job.Name)
+		return nil
+	}
+
 	return errors.Wrapf(
 		j.substrate.ScheduleJob(ctx, project, event, job.Name),
 		"error scheduling event %q job %q on the substrate",
@@ -560,7 +784,9 @@ func (j *jobsService) Start(
 		eventID,
 		jobName,
 		JobStatus{
-			Phase: JobPhaseStarting,
+			Phase:    JobPhaseStarting,
+			Attempt:  job.Status.Attempt,
+			Attempts: job.Status.Attempts,
 		},
 	); err != nil {
 		return errors.Wrapf(
@@ -672,7 +898,32 @@ func (j *jobsService) UpdateStatus(
 		return errors.Wrapf(err, "error retrieving event %q from store", eventID)
 	}
 
-	return j.updateStatus(ctx, event, jobName, status)
+	_, err = j.updateStatus(ctx, event, jobName, status)
+	return err
+}
+
+func (j *jobsService) UpdateOutputs(
+	ctx context.Context,
+	eventID string,
+	jobName string,
+	outputs JobOutputs,
+) error {
+	if err := j.authorize(ctx, RoleWorker, eventID); err != nil {
+		return err
+	}
+
+	return errors.Wrapf(
+		j.jobsStore.UpdateOutputs(
+			ctx,
+			eventID,
+			jobName,
+			outputs.Outputs,
+			maxJobOutputsSize,
+		),
+		"error updating outputs of event %q job %q in store",
+		eventID,
+		jobName,
+	)
 }
 
 func (j *jobsService) Cleanup(
@@ -689,7 +940,15 @@ func (j *jobsService) Cleanup(
 		return errors.Wrapf(err, "error retrieving event %q from store", eventID)
 	}
 
-	return j.cleanup(ctx, event, jobName)
+	job, ok := event.Worker.Job(jobName)
+	if !ok {
+		return &meta.ErrNotFound{
+			Type: JobKind,
+			ID:   jobName,
+		}
+	}
+
+	return j.cleanup(ctx, event, job)
 }
 
 func (j *jobsService) Timeout(
@@ -719,7 +978,7 @@ func (j *jobsService) Timeout(
 	status.Phase = JobPhaseTimedOut
 	status.Ended = &now
 
-	if err := j.updateStatus(ctx, event, jobName, status); err != nil {
+	if status, err = j.updateStatus(ctx, event, jobName, status); err != nil {
 		return errors.Wrapf(
 			err,
 			"error updating status for event %q job %q",
@@ -727,29 +986,98 @@ func (j *jobsService) Timeout(
 			jobName,
 		)
 	}
+	// The job may be due to be attempted again
+	job.Status = &status
 
-	return j.cleanup(ctx, event, jobName)
+	return j.cleanup(ctx, event, job)
+}
+
+func (j *jobsService) Abort(
+	ctx context.Context,
+	eventID string,
+	jobName string,
+) error {
+	event, err := j.eventsStore.Get(ctx, eventID)
+	if err != nil {
+		return errors.Wrapf(err, "error retrieving event %q from store", eventID)
+	}
+
+	if err = j.projectAuthorize(
+		ctx,
+		event.ProjectID,
+		RoleProjectDeveloper,
+	); err != nil {
+		return err
+	}
+
+	job, ok := event.Worker.Job(jobName)
+	if !ok {
+		return &meta.ErrNotFound{
+			Type: JobKind,
+			ID:   jobName,
+		}
+	}
+
+	project, err := j.projectsStore.Get(ctx, event.ProjectID)
+	if err != nil {
+		return errors.Wrapf(
+			err,
+			"error retrieving project %q from store",
+			event.ProjectID,
+		)
+	}
+
+	// Update job status. If the job has already reached a terminal phase, this
+	// results in a conflict.
+	now := time.Now().UTC()
+	status := *job.Status
+	status.Phase = JobPhaseAborted
+	status.Ended = &now
+	status.NextAttempt = nil
+	if _, err = j.updateStatus(ctx, event, jobName, status); err != nil {
+		return errors.Wrapf(
+			err,
+			"error updating status for event %q job %q",
+			event.ID,
+			jobName,
+		)
+	}
+
+	return errors.Wrapf(
+		j.substrate.DeleteJob(ctx, project, event, jobName),
+		"error deleting event %q job %q from the substrate",
+		event.ID,
+		jobName,
+	)
 }
 
 // updateStatus is an internal helper func created so that multiple exported
-// functions can share this logic after they've retrieved specified events.
+// functions can share this logic after they've retrieved specified events. It
+// returns the status that was actually recorded, which differs from the
+// provided status if the job is to be attempted again.
 func (j *jobsService) updateStatus(
 	ctx context.Context,
 	event Event,
 	jobName string,
 	status JobStatus,
-) error {
+) (JobStatus, error) {
 	job, ok := event.Worker.Job(jobName)
 	if !ok {
-		return &meta.ErrNotFound{
+		return JobStatus{}, &meta.ErrNotFound{
 			Type: JobKind,
 			ID:   jobName,
 		}
 	}
 
+	// Updates pertaining to an earlier attempt at the job are of no consequence.
+	// These occur routinely as the pods of earlier attempts are cleaned up.
+	if status.currentAttempt() < job.Status.currentAttempt() {
+		return *job.Status, nil
+	}
+
 	// We have a conflict if the job's phase is already terminal
 	if job.Status.Phase.IsTerminal() {
-		return &meta.ErrConflict{
+		return JobStatus{}, &meta.ErrConflict{
 			Type: JobKind,
 			ID:   job.Name,
 			Reason: fmt.Sprintf(
@@ -760,34 +1088,98 @@ func (j *jobsService) updateStatus(
 		}
 	}
 
-	return errors.Wrapf(
-		j.jobsStore.UpdateStatus(
-			ctx,
-			event.ID,
-			jobName,
-			status,
-		),
-		"error updating status of event %q worker job %q in store",
+	// The history of the job's attempts is not reported by the substrate, so
+	// carry it forward. The job's outputs aren't reported by the substrate
+	// either, but the store leaves them untouched unless they're provided here,
+	// which only happens when a job's cached results are reused.
+	status.Attempt = job.Status.Attempt
+	status.Attempts = job.Status.Attempts
+	if status.Phase.IsTerminal() && job.Spec.RetryPolicy != nil {
+		status.Attempts = append(
+			status.Attempts,
+			JobAttempt{
+				Attempt:  status.currentAttempt(),
+				Started:  status.Started,
+				Ended:    status.Ended,
+				Phase:    status.Phase,
+				ExitCode: status.ExitCode,
+				Reason:   status.Reason,
+				Message:  status.Message,
+			},
+		)
+		if job.Spec.RetryPolicy.permitsRetry(status) {
+			nextAttempt := time.Now().UTC().Add(
+				job.Spec.RetryPolicy.backoff(status.currentAttempt()),
+			)
+			status = JobStatus{
+				Phase:       JobPhasePending,
+				LogsEventID: status.LogsEventID,
+				Attempt:     status.currentAttempt() + 1,
+				NextAttempt: &nextAttempt,
+				Attempts:    status.Attempts,
+			}
+		}
+	}
+
+	if err := j.jobsStore.UpdateStatus(
+		ctx,
 		event.ID,
 		jobName,
-	)
-}
+		status,
+	); err != nil {
+		return JobStatus{}, errors.Wrapf(
+			err,
+			"error updating status of event %q worker job %q in store",
+			event.ID,
+			jobName,
+		)
+	}
 
-// cleanup is an internal helper func created so that multiple exported
-// functions can share this logic after they've retrieved specified events.
-func (j *jobsService) cleanup(
-	ctx context.Context,
-	event Event,
-	jobName string,
-) error {
-	job, ok := event.Worker.Job(jobName)
-	if !ok {
-		return &meta.ErrNotFound{
-			Type: JobKind,
-			ID:   jobName,
+	if status.Outputs == nil {
+		status.Outputs = job.Status.Outputs
+	}
+
+	if status.Phase != job.Status.Phase {
+		if err := j.notifier.NotifyJobPhase(
+			ctx,
+			event,
+			jobName,
+			status.Phase,
+		); err != nil {
+			// A failure to notify interested parties isn't allowed to fail the
+			// status update itself.
+			log.Println(
+				errors.Wrapf(
+					err,
+					"error sending notifications of event %q job %q phase %q",
+					event.ID,
+					jobName,
+					status.Phase,
+				),
+			)
 		}
 	}
 
+	if status.Phase == JobPhaseSucceeded {
+		if err := j.cacheJobResults(ctx, event, job, status); err != nil {
+			// A failure to cache the job's results isn't allowed to fail the status
+			// update itself.
+			log.Println(err)
+		}
+	}
+
+	// If the job has concluded for good, Jobs waiting on it may now be scheduled
+	// or canceled.
+	if status.Phase.IsTerminal() {
+		j.resolveJobDependents(ctx, event.ID, jobName)
+	}
+
+	return status, nil
+}
+
+// cleanup is an internal helper func created so that multiple exported
+// functions can share this logic after they've retrieved specified events.
+func (j *jobsService) cleanup(ctx context.Context, event Event, job Job) error {
 	project, err := j.projectsStore.Get(ctx, event.ProjectID)
 	if err != nil {
 		return errors.Wrapf(
@@ -797,11 +1189,30 @@ func (j *jobsService) cleanup(
 		)
 	}
 
+	// If the job is due to be, or is being, attempted again, only resources
+	// belonging exclusively to its earlier attempts may be deleted.
+	if job.Status != nil &&
+		!job.Status.Phase.IsTerminal() &&
+		job.Status.currentAttempt() > 1 {
+		return errors.Wrapf(
+			j.substrate.DeleteEarlierJobAttempts(
+				ctx,
+				project,
+				event,
+				job.Name,
+				job.Status.currentAttempt(),
+			),
+			"error deleting event %q job %q earlier attempts from the substrate",
+			event.ID,
+			job.Name,
+		)
+	}
+
 	return errors.Wrapf(
 		j.substrate.DeleteJob(ctx, project, event, job.Name),
 		"error deleting event %q jobs %q from the substrate",
 		event.ID,
-		jobName,
+		job.Name,
 	)
 }
 
@@ -812,12 +1223,33 @@ type JobsStore interface {
 	// store.
 	Create(ctx context.Context, eventID string, job Job) error
 	// UpdateStatus updates the status of the specified Job in the underlying data
-	// store. If the specified job is not found, implementations MUST return a
-	// *meta.ErrNotFound error.
+	// store. The Job's outputs are left untouched unless the provided status
+	// includes some. If the specified job is not found, implementations MUST
+	// return a *meta.ErrNotFound error.
 	UpdateStatus(
 		ctx context.Context,
 		eventID string,
 		jobName string,
 		status JobStatus,
 	) error
+	// UpdateOutputs merges the provided key/value pairs into the outputs of the
+	// specified Job in the underlying data store, replacing the values of any
+	// existing keys. Implementations MUST ensure that no concurrent update to
+	// the Job's outputs is lost. If the merged outputs' keys and values would
+	// exceed maxSize bytes in total, implementations MUST leave the outputs
+	// unchanged and return a *meta.ErrBadRequest error. If the specified job is
+	// not found, implementations MUST return a *meta.ErrNotFound error.
+	UpdateOutputs(
+		ctx context.Context,
+		eventID string,
+		jobName string,
+		outputs map[string]string,
+		maxSize int,
+	) error
+	// ClaimRetry retrieves an Event having a PENDING Job that is due to be
+	// attempted again at the provided time and, in the same atomic operation,
+	// clears that Job's next attempt time so that no one else claims it. The
+	// Event is returned along with the name of the claimed Job. If no Job is due
+	// to be attempted again, implementations MUST return a nil Event.
+	ClaimRetry(ctx context.Context, now time.Time) (*Event, string, error)
 }
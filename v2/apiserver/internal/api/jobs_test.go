@@ -15,20 +15,31 @@ func TestNewjobsService(t *testing.T) {
 	projectsStore := &mockProjectsStore{}
 	eventsStore := &mockEventsStore{}
 	jobsStore := &mockJobsStore{}
+	jobCacheStore := &mockJobCacheStore{}
+	secretsStore := &mockSecretsStore{}
 	substrate := &mockSubstrate{}
+	notifier := &mockNotifier{}
 	svc, ok := NewJobsService(
 		alwaysAuthorize,
+		alwaysProjectAuthorize,
 		projectsStore,
 		eventsStore,
 		jobsStore,
+		jobCacheStore,
+		secretsStore,
 		substrate,
+		notifier,
 	).(*jobsService)
 	require.True(t, ok)
 	require.NotNil(t, svc.authorize)
+	require.NotNil(t, svc.projectAuthorize)
 	require.Same(t, projectsStore, svc.projectsStore)
 	require.Same(t, eventsStore, svc.eventsStore)
 	require.Same(t, jobsStore, svc.jobsStore)
+	require.Same(t, jobCacheStore, svc.jobCacheStore)
+	require.Same(t, secretsStore, svc.secretsStore)
 	require.Same(t, substrate, svc.substrate)
+	require.Same(t, notifier, svc.notifier)
 }
 
 func TestJobsServiceCreate(t *testing.T) {
@@ -86,6 +97,11 @@ func TestJobsServiceCreate(t *testing.T) {
 						}, nil
 					},
 				},
+				projectsStore: &mockProjectsStore{
+					GetFn: func(context.Context, string) (Project, error) {
+						return Project{}, nil
+					},
+				},
 			},
 			assertions: func(err error) {
 				require.Error(t, err)
@@ -105,6 +121,11 @@ func TestJobsServiceCreate(t *testing.T) {
 						}, nil
 					},
 				},
+				projectsStore: &mockProjectsStore{
+					GetFn: func(context.Context, string) (Project, error) {
+						return Project{}, nil
+					},
+				},
 			},
 			assertions: func(err error) {
 				require.Error(t, err)
@@ -165,6 +186,11 @@ func TestJobsServiceCreate(t *testing.T) {
 						}, nil
 					},
 				},
+				projectsStore: &mockProjectsStore{
+					GetFn: func(context.Context, string) (Project, error) {
+						return Project{}, nil
+					},
+				},
 			},
 			assertions: func(err error) {
 				require.Error(t, err)
@@ -178,6 +204,49 @@ func TestJobsServiceCreate(t *testing.T) {
 				)
 			},
 		},
+		{
+			name: "resources exceed project maximum",
+			service: &jobsService{
+				authorize: alwaysAuthorize,
+				eventsStore: &mockEventsStore{
+					GetFn: func(context.Context, string) (Event, error) {
+						return Event{
+							Worker: Worker{
+								Spec: WorkerSpec{
+									UseWorkspace: true,
+									JobPolicies: &JobPolicies{
+										AllowPrivileged: true,
+									},
+								},
+							},
+						}, nil
+					},
+				},
+				projectsStore: &mockProjectsStore{
+					GetFn: func(context.Context, string) (Project, error) {
+						return Project{
+							Spec: ProjectSpec{
+								Resources: &ResourcePolicy{
+									Defaults: ContainerResources{
+										Limits: ResourceQuantities{
+											CPU: "4",
+										},
+									},
+									Max: ResourceQuantities{
+										CPU: "2",
+									},
+								},
+							},
+						}, nil
+					},
+				},
+			},
+			assertions: func(err error) {
+				require.Error(t, err)
+				require.IsType(t, &meta.ErrBadRequest{}, err)
+				require.Len(t, err.(*meta.ErrBadRequest).Details, 2)
+			},
+		},
 		{
 			name: "error getting project from store",
 			service: &jobsService{
@@ -474,6 +543,11 @@ func TestJobsServiceCreateRetry(t *testing.T) {
 						}, nil
 					},
 				},
+				projectsStore: &mockProjectsStore{
+					GetFn: func(context.Context, string) (Project, error) {
+						return Project{}, nil
+					},
+				},
 			},
 			workspaceMountPath: "",
 			assertions: func(err error) {
@@ -502,6 +576,11 @@ func TestJobsServiceCreateRetry(t *testing.T) {
 						}, nil
 					},
 				},
+				projectsStore: &mockProjectsStore{
+					GetFn: func(context.Context, string) (Project, error) {
+						return Project{}, nil
+					},
+				},
 			},
 			workspaceMountPath: "",
 			assertions: func(err error) {
@@ -608,6 +687,11 @@ func TestJobsServiceCreateRetry(t *testing.T) {
 						}, nil
 					},
 				},
+				projectsStore: &mockProjectsStore{
+					GetFn: func(context.Context, string) (Project, error) {
+						return Project{}, nil
+					},
+				},
 				// No other methods mocked out; they should not be called
 			},
 			workspaceMountPath: "",
@@ -1222,6 +1306,9 @@ func TestJobsServiceUpdateStatus(t *testing.T) {
 										Name: testJobName,
 										Status: &JobStatus{
 											Phase: JobPhaseRunning,
+											Outputs: map[string]string{
+												"foo": "bar",
+											},
 										},
 									},
 								},
@@ -1231,11 +1318,305 @@ func TestJobsServiceUpdateStatus(t *testing.T) {
 				},
 				jobsStore: &mockJobsStore{
 					UpdateStatusFn: func(
+						_ context.Context,
+						_ string,
+						_ string,
+						status JobStatus,
+					) error {
+						// Outputs must be left for the store to preserve
+						require.Nil(t, status.Outputs)
+						return nil
+					},
+				},
+				notifier: &mockNotifier{
+					NotifyJobPhaseFn: func(
+						_ context.Context,
+						_ Event,
+						jobName string,
+						_ JobPhase,
+					) error {
+						require.Equal(t, testJobName, jobName)
+						// Errors are logged, but don't fail the status update
+						return errors.New("something went wrong")
+					},
+				},
+			},
+			assertions: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			err := testCase.service.UpdateStatus(
+				context.Background(),
+				testEventID,
+				testJobName,
+				JobStatus{},
+			)
+			testCase.assertions(err)
+		})
+	}
+}
+
+func TestJobsServiceUpdateStatusWithRetries(t *testing.T) {
+	const testEventID = "123456789"
+	const testJobName = "italian"
+	exitCode := int32(1)
+	testRetryPolicy := &JobRetryPolicy{
+		MaxAttempts:        3,
+		BackoffDuration:    "1m",
+		RetryableExitCodes: []int32{1},
+	}
+	testCases := []struct {
+		name       string
+		jobSpec    JobSpec
+		jobStatus  JobStatus
+		status     JobStatus
+		assertions func(status JobStatus, err error)
+	}{
+		{
+			name: "update pertains to an earlier attempt",
+			jobSpec: JobSpec{
+				RetryPolicy: testRetryPolicy,
+			},
+			jobStatus: JobStatus{
+				Phase:   JobPhaseRunning,
+				Attempt: 2,
+			},
+			status: JobStatus{
+				Phase: JobPhaseAborted,
+			},
+			assertions: func(status JobStatus, err error) {
+				require.NoError(t, err)
+				// Nothing should have been stored
+				require.Equal(t, JobStatus{}, status)
+			},
+		},
+		{
+			name: "attempt failed and job will be retried",
+			jobSpec: JobSpec{
+				RetryPolicy: testRetryPolicy,
+			},
+			jobStatus: JobStatus{
+				Phase: JobPhaseRunning,
+			},
+			status: JobStatus{
+				Phase:    JobPhaseFailed,
+				ExitCode: &exitCode,
+			},
+			assertions: func(status JobStatus, err error) {
+				require.NoError(t, err)
+				require.Equal(t, JobPhasePending, status.Phase)
+				require.Equal(t, 2, status.Attempt)
+				require.NotNil(t, status.NextAttempt)
+				require.WithinDuration(
+					t,
+					time.Now().Add(time.Minute),
+					*status.NextAttempt,
+					5*time.Second,
+				)
+				require.Equal(
+					t,
+					[]JobAttempt{
+						{
+							Attempt:  1,
+							Phase:    JobPhaseFailed,
+							ExitCode: &exitCode,
+						},
+					},
+					status.Attempts,
+				)
+			},
+		},
+		{
+			name: "attempt failed with non-retryable exit code",
+			jobSpec: JobSpec{
+				RetryPolicy: testRetryPolicy,
+			},
+			jobStatus: JobStatus{
+				Phase: JobPhaseRunning,
+			},
+			status: JobStatus{
+				Phase: JobPhaseFailed,
+			},
+			assertions: func(status JobStatus, err error) {
+				require.NoError(t, err)
+				require.Equal(t, JobPhaseFailed, status.Phase)
+				require.Len(t, status.Attempts, 1)
+			},
+		},
+		{
+			name: "attempts exhausted",
+			jobSpec: JobSpec{
+				RetryPolicy: testRetryPolicy,
+			},
+			jobStatus: JobStatus{
+				Phase:   JobPhaseRunning,
+				Attempt: 3,
+				Attempts: []JobAttempt{
+					{
+						Attempt: 1,
+						Phase:   JobPhaseTimedOut,
+					},
+					{
+						Attempt:  2,
+						Phase:    JobPhaseFailed,
+						ExitCode: &exitCode,
+					},
+				},
+			},
+			status: JobStatus{
+				Phase:    JobPhaseFailed,
+				Attempt:  3,
+				ExitCode: &exitCode,
+			},
+			assertions: func(status JobStatus, err error) {
+				require.NoError(t, err)
+				require.Equal(t, JobPhaseFailed, status.Phase)
+				require.Equal(t, 3, status.Attempt)
+				require.Len(t, status.Attempts, 3)
+				require.Equal(t, 3, status.Attempts[2].Attempt)
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			var storedStatus JobStatus
+			service := &jobsService{
+				authorize: alwaysAuthorize,
+				eventsStore: &mockEventsStore{
+					GetFn: func(context.Context, string) (Event, error) {
+						jobStatus := testCase.jobStatus
+						return Event{
+							Worker: Worker{
+								Jobs: []Job{
+									{
+										Name:   testJobName,
+										Spec:   testCase.jobSpec,
+										Status: &jobStatus,
+									},
+								},
+							},
+						}, nil
+					},
+				},
+				jobsStore: &mockJobsStore{
+					UpdateStatusFn: func(
+						_ context.Context,
+						_ string,
+						_ string,
+						status JobStatus,
+					) error {
+						storedStatus = status
+						return nil
+					},
+				},
+				notifier: &mockNotifier{
+					NotifyJobPhaseFn: func(
 						context.Context,
+						Event,
 						string,
+						JobPhase,
+					) error {
+						return nil
+					},
+				},
+			}
+			err := service.UpdateStatus(
+				context.Background(),
+				testEventID,
+				testJobName,
+				testCase.status,
+			)
+			testCase.assertions(storedStatus, err)
+		})
+	}
+}
+
+func TestJobsServiceUpdateOutputs(t *testing.T) {
+	const testEventID = "123456789"
+	const testJobName = "italian"
+	testOutputs := JobOutputs{
+		Outputs: map[string]string{
+			"version": "v1.0.1",
+		},
+	}
+	testCases := []struct {
+		name       string
+		service    JobsService
+		assertions func(error)
+	}{
+		{
+			name: "unauthorized",
+			service: &jobsService{
+				authorize: neverAuthorize,
+			},
+			assertions: func(err error) {
+				require.Error(t, err)
+				require.IsType(t, &meta.ErrAuthorization{}, err)
+			},
+		},
+		{
+			name: "outputs too large",
+			service: &jobsService{
+				authorize: alwaysAuthorize,
+				jobsStore: &mockJobsStore{
+					UpdateOutputsFn: func(
+						context.Context,
 						string,
-						JobStatus,
+						string,
+						map[string]string,
+						int,
 					) error {
+						return &meta.ErrBadRequest{}
+					},
+				},
+			},
+			assertions: func(err error) {
+				require.Error(t, err)
+				var badRequestErr *meta.ErrBadRequest
+				require.ErrorAs(t, err, &badRequestErr)
+			},
+		},
+		{
+			name: "error updating outputs in store",
+			service: &jobsService{
+				authorize: alwaysAuthorize,
+				jobsStore: &mockJobsStore{
+					UpdateOutputsFn: func(
+						context.Context,
+						string,
+						string,
+						map[string]string,
+						int,
+					) error {
+						return errors.New("something went wrong")
+					},
+				},
+			},
+			assertions: func(err error) {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), "something went wrong")
+				require.Contains(t, err.Error(), "error updating outputs")
+			},
+		},
+		{
+			name: "success",
+			service: &jobsService{
+				authorize: alwaysAuthorize,
+				jobsStore: &mockJobsStore{
+					UpdateOutputsFn: func(
+						_ context.Context,
+						eventID string,
+						jobName string,
+						outputs map[string]string,
+						maxSize int,
+					) error {
+						require.Equal(t, testEventID, eventID)
+						require.Equal(t, testJobName, jobName)
+						require.Equal(t, testOutputs.Outputs, outputs)
+						require.Equal(t, maxJobOutputsSize, maxSize)
 						return nil
 					},
 				},
@@ -1247,11 +1628,11 @@ func TestJobsServiceUpdateStatus(t *testing.T) {
 	}
 	for _, testCase := range testCases {
 		t.Run(testCase.name, func(t *testing.T) {
-			err := testCase.service.UpdateStatus(
+			err := testCase.service.UpdateOutputs(
 				context.Background(),
 				testEventID,
 				testJobName,
-				JobStatus{},
+				testOutputs,
 			)
 			testCase.assertions(err)
 		})
@@ -1407,6 +1788,54 @@ func TestJobsServiceCleanup(t *testing.T) {
 				require.NoError(t, err)
 			},
 		},
+		{
+			name: "success with job being attempted again",
+			service: &jobsService{
+				authorize: alwaysAuthorize,
+				eventsStore: &mockEventsStore{
+					GetFn: func(context.Context, string) (Event, error) {
+						return Event{
+							Worker: Worker{
+								Jobs: []Job{
+									{
+										Name: testJobName,
+										Status: &JobStatus{
+											Phase:   JobPhasePending,
+											Attempt: 2,
+										},
+									},
+								},
+							},
+						}, nil
+					},
+				},
+				projectsStore: &mockProjectsStore{
+					GetFn: func(context.Context, string) (Project, error) {
+						return Project{}, nil
+					},
+				},
+				substrate: &mockSubstrate{
+					DeleteJobFn: func(context.Context, Project, Event, string) error {
+						require.Fail(t, "DeleteJobFn should not have been called, but was")
+						return nil
+					},
+					DeleteEarlierJobAttemptsFn: func(
+						_ context.Context,
+						_ Project,
+						_ Event,
+						jobName string,
+						currentAttempt int,
+					) error {
+						require.Equal(t, testJobName, jobName)
+						require.Equal(t, 2, currentAttempt)
+						return nil
+					},
+				},
+			},
+			assertions: func(err error) {
+				require.NoError(t, err)
+			},
+		},
 	}
 	for _, testCase := range testCases {
 		t.Run(testCase.name, func(t *testing.T) {
@@ -1487,6 +1916,17 @@ func TestJobsServiceTimeout(t *testing.T) {
 						return testEvent, nil
 					},
 				},
+				notifier: &mockNotifier{
+					NotifyJobPhaseFn: func(
+						_ context.Context,
+						_ Event,
+						_ string,
+						phase JobPhase,
+					) error {
+						require.Equal(t, JobPhaseTimedOut, phase)
+						return nil
+					},
+				},
 				jobsStore: &mockJobsStore{
 					UpdateStatusFn: func(
 						context.Context,
@@ -1523,6 +1963,17 @@ func TestJobsServiceTimeout(t *testing.T) {
 						return testEvent, nil
 					},
 				},
+				notifier: &mockNotifier{
+					NotifyJobPhaseFn: func(
+						_ context.Context,
+						_ Event,
+						_ string,
+						phase JobPhase,
+					) error {
+						require.Equal(t, JobPhaseTimedOut, phase)
+						return nil
+					},
+				},
 				jobsStore: &mockJobsStore{
 					UpdateStatusFn: func(
 						_ context.Context,
@@ -1564,6 +2015,218 @@ func TestJobsServiceTimeout(t *testing.T) {
 	}
 }
 
+func TestJobsServiceAbort(t *testing.T) {
+	const testEventID = "123456789"
+	const testJobName = "italian"
+	var testStartedTime = time.Unix(1234, 56789)
+	var testEvent = Event{
+		ProjectID: "blue-book",
+		Worker: Worker{
+			Jobs: []Job{
+				{
+					Name: testJobName,
+					Status: &JobStatus{
+						Started: &testStartedTime,
+						Phase:   JobPhaseRunning,
+					},
+				},
+			},
+		},
+	}
+	testCases := []struct {
+		name       string
+		service    JobsService
+		assertions func(error)
+	}{
+		{
+			name: "error getting event from store",
+			service: &jobsService{
+				eventsStore: &mockEventsStore{
+					GetFn: func(context.Context, string) (Event, error) {
+						return Event{}, errors.New("something went wrong")
+					},
+				},
+			},
+			assertions: func(err error) {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), "something went wrong")
+				require.Contains(t, err.Error(), "error retrieving event")
+			},
+		},
+		{
+			name: "unauthorized",
+			service: &jobsService{
+				eventsStore: &mockEventsStore{
+					GetFn: func(context.Context, string) (Event, error) {
+						return testEvent, nil
+					},
+				},
+				projectAuthorize: neverProjectAuthorize,
+			},
+			assertions: func(err error) {
+				require.Error(t, err)
+				require.IsType(t, &meta.ErrAuthorization{}, err)
+			},
+		},
+		{
+			name: "job not found",
+			service: &jobsService{
+				eventsStore: &mockEventsStore{
+					GetFn: func(context.Context, string) (Event, error) {
+						return Event{}, nil
+					},
+				},
+				projectAuthorize: alwaysProjectAuthorize,
+			},
+			assertions: func(err error) {
+				require.Error(t, err)
+				require.IsType(t, &meta.ErrNotFound{}, err)
+			},
+		},
+		{
+			name: "job already in a terminal phase",
+			service: &jobsService{
+				eventsStore: &mockEventsStore{
+					GetFn: func(context.Context, string) (Event, error) {
+						return Event{
+							Worker: Worker{
+								Jobs: []Job{
+									{
+										Name: testJobName,
+										Status: &JobStatus{
+											Phase: JobPhaseSucceeded,
+										},
+									},
+								},
+							},
+						}, nil
+					},
+				},
+				projectAuthorize: alwaysProjectAuthorize,
+				projectsStore: &mockProjectsStore{
+					GetFn: func(context.Context, string) (Project, error) {
+						return Project{}, nil
+					},
+				},
+			},
+			assertions: func(err error) {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), "already reached a terminal phase")
+			},
+		},
+		{
+			name: "error deleting job from substrate",
+			service: &jobsService{
+				eventsStore: &mockEventsStore{
+					GetFn: func(context.Context, string) (Event, error) {
+						return testEvent, nil
+					},
+				},
+				projectAuthorize: alwaysProjectAuthorize,
+				projectsStore: &mockProjectsStore{
+					GetFn: func(context.Context, string) (Project, error) {
+						return Project{}, nil
+					},
+				},
+				jobsStore: &mockJobsStore{
+					UpdateStatusFn: func(
+						context.Context,
+						string,
+						string,
+						JobStatus,
+					) error {
+						return nil
+					},
+				},
+				notifier: &mockNotifier{
+					NotifyJobPhaseFn: func(
+						context.Context,
+						Event,
+						string,
+						JobPhase,
+					) error {
+						return nil
+					},
+				},
+				substrate: &mockSubstrate{
+					DeleteJobFn: func(context.Context, Project, Event, string) error {
+						return errors.New("something went wrong")
+					},
+				},
+			},
+			assertions: func(err error) {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), "something went wrong")
+				require.Contains(t, err.Error(), "error deleting event")
+			},
+		},
+		{
+			name: "success",
+			service: &jobsService{
+				eventsStore: &mockEventsStore{
+					GetFn: func(context.Context, string) (Event, error) {
+						return testEvent, nil
+					},
+				},
+				projectAuthorize: alwaysProjectAuthorize,
+				projectsStore: &mockProjectsStore{
+					GetFn: func(context.Context, string) (Project, error) {
+						return Project{}, nil
+					},
+				},
+				jobsStore: &mockJobsStore{
+					UpdateStatusFn: func(
+						_ context.Context,
+						_ string,
+						_ string,
+						status JobStatus,
+					) error {
+						require.Equal(t, JobPhaseAborted, status.Phase)
+						require.Equal(t, &testStartedTime, status.Started)
+						require.NotNil(t, status.Ended)
+						return nil
+					},
+				},
+				notifier: &mockNotifier{
+					NotifyJobPhaseFn: func(
+						_ context.Context,
+						_ Event,
+						_ string,
+						phase JobPhase,
+					) error {
+						require.Equal(t, JobPhaseAborted, phase)
+						return nil
+					},
+				},
+				substrate: &mockSubstrate{
+					DeleteJobFn: func(
+						_ context.Context,
+						_ Project,
+						_ Event,
+						jobName string,
+					) error {
+						require.Equal(t, testJobName, jobName)
+						return nil
+					},
+				},
+			},
+			assertions: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			err := testCase.service.Abort(
+				context.Background(),
+				testEventID,
+				testJobName,
+			)
+			testCase.assertions(err)
+		})
+	}
+}
+
 func TestJobSpecEqualTo(t *testing.T) {
 	// Note: leaving fields that are maps or slices as empty is crucial for
 	// testing the behavior of comparison between a 'fresh' JobSpec and one that
@@ -1660,6 +2323,14 @@ type mockJobsStore struct {
 		jobName string,
 		status JobStatus,
 	) error
+	UpdateOutputsFn func(
+		ctx context.Context,
+		eventID string,
+		jobName string,
+		outputs map[string]string,
+		maxSize int,
+	) error
+	ClaimRetryFn func(ctx context.Context, now time.Time) (*Event, string, error)
 }
 
 func (m *mockJobsStore) Create(
@@ -1678,3 +2349,20 @@ func (m *mockJobsStore) UpdateStatus(
 ) error {
 	return m.UpdateStatusFn(ctx, eventID, jobName, status)
 }
+
+func (m *mockJobsStore) UpdateOutputs(
+	ctx context.Context,
+	eventID string,
+	jobName string,
+	outputs map[string]string,
+	maxSize int,
+) error {
+	return m.UpdateOutputsFn(ctx, eventID, jobName, outputs, maxSize)
+}
+
+func (m *mockJobsStore) ClaimRetry(
+	ctx context.Context,
+	now time.Time,
+) (*Event, string, error) {
+	return m.ClaimRetryFn(ctx, now)
+}
@@ -15,6 +15,7 @@ import (
 	"github.com/pkg/errors"
 	v1 "k8s.io/api/core/v1"
 	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 )
 
@@ -50,11 +51,7 @@ func (l *logsStore) StreamLogs(
 
 	req := l.kubeClient.CoreV1().Pods(project.Kubernetes.Namespace).GetLogs(
 		podName,
-		&v1.PodLogOptions{
-			Container:  selector.Container,
-			Timestamps: true,
-			Follow:     opts.Follow,
-		},
+		podLogOptions(selector, opts),
 	)
 
 	// The LogsService only would have called us for a Worker or Job that has
@@ -134,6 +131,22 @@ func (l *logsStore) StreamLogs(
 			} else {
 				logEntry.Message = logLine
 			}
+			// Kubernetes only honors the start of the requested time range to the
+			// nearest second, so lines written just before it are filtered out here.
+			if opts.Since != nil &&
+				logEntry.Time != nil &&
+				logEntry.Time.Before(*opts.Since) {
+				continue
+			}
+			// Kubernetes has no notion of an end time for logs, so lines written
+			// after the end of the requested time range are filtered out here. Since
+			// lines arrive in the order they were written, none of the lines that
+			// follow can be in range either.
+			if opts.Until != nil &&
+				logEntry.Time != nil &&
+				logEntry.Time.After(*opts.Until) {
+				return
+			}
 			select {
 			case logEntryCh <- logEntry:
 			case <-ctx.Done():
@@ -146,9 +159,33 @@ func (l *logsStore) StreamLogs(
 	return logEntryCh, nil
 }
 
+// podLogOptions returns Kubernetes options for retrieving logs from the
+// container specified by the provided api.LogsSelector, in accordance with the
+// provided api.LogStreamOptions.
+func podLogOptions(
+	selector api.LogsSelector,
+	opts api.LogStreamOptions,
+) *v1.PodLogOptions {
+	podLogOpts := &v1.PodLogOptions{
+		Container:  selector.Container,
+		Timestamps: true,
+		Follow:     opts.Follow,
+	}
+	if opts.TailLines > 0 {
+		tailLines := opts.TailLines
+		podLogOpts.TailLines = &tailLines
+	}
+	if opts.Since != nil {
+		sinceTime := metav1.NewTime(*opts.Since)
+		podLogOpts.SinceTime = &sinceTime
+	}
+	return podLogOpts
+}
+
 func podNameFromSelector(eventID string, selector api.LogsSelector) string {
 	if selector.Job == "" { // We want worker logs
 		return myk8s.WorkerPodName(eventID)
 	}
-	return myk8s.JobPodName(eventID, selector.Job) // We want job logs
+	// We want job logs
+	return myk8s.JobAttemptPodName(eventID, selector.Job, selector.Attempt)
 }
@@ -2,10 +2,13 @@ package kubernetes
 
 import (
 	"testing"
+	"time"
 
 	"github.com/brigadecore/brigade/v2/apiserver/internal/api"
 	myk8s "github.com/brigadecore/brigade/v2/internal/kubernetes"
 	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes/fake"
 )
 
@@ -25,6 +28,59 @@ func TestLogStoreStreamLogs(t *testing.T) {
 	// require.Fail(t, "test me")
 }
 
+func TestPodLogOptions(t *testing.T) {
+	const testContainerName = "foo"
+	testSince := time.Date(2021, time.December, 1, 12, 0, 0, 0, time.UTC)
+	testTailLines := int64(100)
+	testCases := []struct {
+		name               string
+		opts               api.LogStreamOptions
+		expectedPodLogOpts *v1.PodLogOptions
+	}{
+		{
+			name: "no options specified",
+			opts: api.LogStreamOptions{},
+			expectedPodLogOpts: &v1.PodLogOptions{
+				Container:  testContainerName,
+				Timestamps: true,
+			},
+		},
+		{
+			name: "all options specified",
+			opts: api.LogStreamOptions{
+				Follow:    true,
+				TailLines: testTailLines,
+				Since:     &testSince,
+				// This is applied client-side instead
+				Until: &testSince,
+			},
+			expectedPodLogOpts: &v1.PodLogOptions{
+				Container:  testContainerName,
+				Timestamps: true,
+				Follow:     true,
+				TailLines:  &testTailLines,
+				SinceTime: &metav1.Time{
+					Time: testSince,
+				},
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			require.Equal(
+				t,
+				testCase.expectedPodLogOpts,
+				podLogOptions(
+					api.LogsSelector{
+						Container: testContainerName,
+					},
+					testCase.opts,
+				),
+			)
+		})
+	}
+}
+
 func TestPodNameFromSelector(t *testing.T) {
 	const testEventID = "123456789"
 	const testJobName = "italian"
@@ -45,6 +101,14 @@ func TestPodNameFromSelector(t *testing.T) {
 			},
 			expectedPodName: myk8s.JobPodName(testEventID, testJobName),
 		},
+		{
+			name: "job attempt specified",
+			selector: api.LogsSelector{
+				Job:     testJobName,
+				Attempt: 2,
+			},
+			expectedPodName: myk8s.JobAttemptPodName(testEventID, testJobName, 2),
+		},
 	}
 	for _, testCase := range testCases {
 		t.Run(testCase.name, func(t *testing.T) {
@@ -87,6 +87,34 @@ func (s *secretsStore) List(
 	return secrets, nil
 }
 
+func (s *secretsStore) Get(
+	ctx context.Context,
+	project api.Project,
+	key string,
+) (api.Secret, error) {
+	k8sSecret, err := s.kubeClient.CoreV1().Secrets(
+		project.Kubernetes.Namespace,
+	).Get(ctx, "project-secrets", metav1.GetOptions{})
+	if err != nil {
+		return api.Secret{}, errors.Wrapf(
+			err,
+			"error retrieving secret \"project-secrets\" in namespace %q",
+			project.Kubernetes.Namespace,
+		)
+	}
+	value, ok := k8sSecret.Data[key]
+	if !ok {
+		return api.Secret{}, &meta.ErrNotFound{
+			Type: "Secret",
+			ID:   key,
+		}
+	}
+	return api.Secret{
+		Key:   key,
+		Value: string(value),
+	}, nil
+}
+
 func (s *secretsStore) Set(
 	ctx context.Context,
 	project api.Project,
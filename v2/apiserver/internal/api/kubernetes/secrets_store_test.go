@@ -105,6 +105,100 @@ func TestSecretsStoreList(t *testing.T) {
 	}
 }
 
+func TestSecretsStoreGet(t *testing.T) {
+	const testNamespace = "foo"
+	testCases := []struct {
+		name       string
+		key        string
+		setup      func() *fake.Clientset
+		assertions func(api.Secret, error)
+	}{
+		{
+			name: "error getting kubernetes secret",
+			key:  "foo",
+			setup: func() *fake.Clientset {
+				// We'll force an error simply by having the secret not exist
+				return fake.NewSimpleClientset()
+			},
+			assertions: func(_ api.Secret, err error) {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), "error retrieving secret")
+			},
+		},
+
+		{
+			name: "key not found",
+			key:  "bat",
+			setup: func() *fake.Clientset {
+				kubeClient := fake.NewSimpleClientset()
+				_, err := kubeClient.CoreV1().Secrets(testNamespace).Create(
+					context.Background(),
+					&corev1.Secret{
+						ObjectMeta: metav1.ObjectMeta{
+							Name: "project-secrets",
+						},
+						Data: map[string][]byte{
+							"foo": []byte("bar"),
+						},
+					},
+					metav1.CreateOptions{},
+				)
+				require.NoError(t, err)
+				return kubeClient
+			},
+			assertions: func(_ api.Secret, err error) {
+				require.Error(t, err)
+				require.IsType(t, &meta.ErrNotFound{}, err)
+			},
+		},
+
+		{
+			name: "success",
+			key:  "foo",
+			setup: func() *fake.Clientset {
+				kubeClient := fake.NewSimpleClientset()
+				_, err := kubeClient.CoreV1().Secrets(testNamespace).Create(
+					context.Background(),
+					&corev1.Secret{
+						ObjectMeta: metav1.ObjectMeta{
+							Name: "project-secrets",
+						},
+						Data: map[string][]byte{
+							"foo": []byte("bar"),
+						},
+					},
+					metav1.CreateOptions{},
+				)
+				require.NoError(t, err)
+				return kubeClient
+			},
+			assertions: func(secret api.Secret, err error) {
+				require.NoError(t, err)
+				require.Equal(t, "foo", secret.Key)
+				require.Equal(t, "bar", secret.Value)
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			s := &secretsStore{
+				kubeClient: testCase.setup(),
+			}
+			secret, err := s.Get(
+				context.Background(),
+				api.Project{
+					Kubernetes: &api.KubernetesDetails{
+						Namespace: testNamespace,
+					},
+				},
+				testCase.key,
+			)
+			testCase.assertions(secret, err)
+		})
+	}
+}
+
 func TestSecretsStoreSet(t *testing.T) {
 	const testNamespace = "foo"
 	const testKey = "foo"
@@ -4,6 +4,8 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"sort"
+	"strconv"
 	"time"
 
 	"github.com/brigadecore/brigade/v2/apiserver/internal/api"
@@ -13,10 +15,12 @@ import (
 	uuid "github.com/satori/go.uuid"
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
 	"k8s.io/client-go/kubernetes"
 )
 
@@ -137,24 +141,26 @@ func NewSubstrate(
 
 func (s *substrate) CountRunningWorkers(
 	ctx context.Context,
+	selector api.RunningWorkersSelector,
 ) (api.SubstrateWorkerCount, error) {
 	count := api.SubstrateWorkerCount{}
 	var err error
 	count.Count, err = s.countRunningPods(
 		ctx,
-		myk8s.WorkerPodsSelector(s.config.BrigadeID),
+		myk8s.ProjectWorkerPodsSelector(s.config.BrigadeID, selector.ProjectID),
 	)
 	return count, err
 }
 
 func (s *substrate) CountRunningJobs(
 	ctx context.Context,
+	selector api.RunningJobsSelector,
 ) (api.SubstrateJobCount, error) {
 	count := api.SubstrateJobCount{}
 	var err error
 	count.Count, err = s.countRunningPods(
 		ctx,
-		myk8s.JobPodsSelector(s.config.BrigadeID),
+		myk8s.ProjectJobPodsSelector(s.config.BrigadeID, selector.ProjectID),
 	)
 	return count, err
 }
@@ -399,7 +405,8 @@ func (s *substrate) ScheduleWorker(ctx context.Context, event api.Event) error {
 		ctx,
 		event.ID,
 		&queue.MessageOptions{
-			Durable: true,
+			Durable:  true,
+			Priority: uint8(event.Priority),
 		},
 	); err != nil {
 		return errors.Wrapf(
@@ -587,7 +594,8 @@ func (s *substrate) ScheduleJob(
 		ctx,
 		fmt.Sprintf("%s:%s", event.ID, jobName),
 		&queue.MessageOptions{
-			Durable: true,
+			Durable:  true,
+			Priority: uint8(event.Priority),
 		},
 	); err != nil {
 		return errors.Wrapf(
@@ -674,6 +682,52 @@ func (s *substrate) DeleteJob(
 	return nil
 }
 
+func (s *substrate) DeleteEarlierJobAttempts(
+	ctx context.Context,
+	project api.Project,
+	event api.Event,
+	jobName string,
+	currentAttempt int,
+) error {
+	currentAttemptRequirement, err := labels.NewRequirement(
+		myk8s.LabelJobAttempt,
+		selection.NotEquals,
+		[]string{strconv.Itoa(currentAttempt)},
+	)
+	if err != nil {
+		return errors.Wrap(err, "error building label selector")
+	}
+	labelSelector := labels.SelectorFromSet(
+		map[string]string{
+			myk8s.LabelBrigadeID: s.config.BrigadeID,
+			myk8s.LabelEvent:     event.ID,
+			myk8s.LabelJob:       jobName,
+		},
+	).Add(*currentAttemptRequirement).String()
+
+	// Delete all pods related to earlier attempts at this Job. Secrets are shared
+	// by all attempts, so those are left alone.
+	if err := s.kubeClient.CoreV1().Pods(
+		project.Kubernetes.Namespace,
+	).DeleteCollection(
+		ctx,
+		metav1.DeleteOptions{},
+		metav1.ListOptions{
+			LabelSelector: labelSelector,
+		},
+	); err != nil {
+		return errors.Wrapf(
+			err,
+			"error deleting event %q job %q earlier attempt pods in namespace %q",
+			event.ID,
+			jobName,
+			project.Kubernetes.Namespace,
+		)
+	}
+
+	return nil
+}
+
 func (s *substrate) DeleteWorkerAndJobs(
 	ctx context.Context,
 	project api.Project,
@@ -850,6 +904,67 @@ func (s *substrate) createWorkspacePVC(
 	return nil
 }
 
+// createCacheVolumePVC creates the PVC backing the specified Project-wide
+// cache volume if it does not already exist.
+func (s *substrate) createCacheVolumePVC(
+	ctx context.Context,
+	project api.Project,
+	cache api.CacheVolumeSource,
+) error {
+	storageQuantityStr := cache.Size
+	if storageQuantityStr == "" {
+		storageQuantityStr = "1G"
+	}
+	storageQuantity, err := resource.ParseQuantity(storageQuantityStr)
+	if err != nil {
+		return errors.Wrapf(
+			err,
+			"error parsing storage quantity %q for cache volume %q",
+			storageQuantityStr,
+			cache.Name,
+		)
+	}
+
+	cachePVC := corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      myk8s.CacheVolumePVCName(cache.Name),
+			Namespace: project.Kubernetes.Namespace,
+			Labels: map[string]string{
+				myk8s.LabelBrigadeID: s.config.BrigadeID,
+				myk8s.LabelComponent: myk8s.LabelKeyCacheVolume,
+				myk8s.LabelProject:   project.ID,
+			},
+		},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			StorageClassName: &s.config.WorkspaceStorageClass,
+			AccessModes: []corev1.PersistentVolumeAccessMode{
+				corev1.ReadWriteMany,
+			},
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{
+					"storage": storageQuantity,
+				},
+			},
+		},
+	}
+
+	pvcClient :=
+		s.kubeClient.CoreV1().PersistentVolumeClaims(project.Kubernetes.Namespace)
+	if _, err := pvcClient.Create(
+		ctx,
+		&cachePVC,
+		metav1.CreateOptions{},
+	); err != nil && !k8sErrors.IsAlreadyExists(err) {
+		return errors.Wrapf(
+			err,
+			"error creating PVC for cache volume %q",
+			cache.Name,
+		)
+	}
+
+	return nil
+}
+
 func (s *substrate) createWorkerPod(
 	ctx context.Context,
 	project api.Project,
@@ -990,6 +1105,11 @@ func (s *substrate) createWorkerPod(
 					Args:            event.Worker.Spec.Container.Arguments,
 					Env:             env,
 					VolumeMounts:    volumeMounts,
+					Resources: getResourceRequirements(
+						project.Spec.Resources.ApplyTo(
+							event.Worker.Spec.Container.Resources,
+						),
+					),
 				},
 			},
 			Volumes: volumes,
@@ -1015,6 +1135,21 @@ func (s *substrate) createWorkerPod(
 		}
 	}
 
+	if host := event.Worker.Spec.Host; host != nil {
+		if len(host.NodeSelector) > 0 && workerPod.Spec.NodeSelector == nil {
+			workerPod.Spec.NodeSelector = map[string]string{}
+		}
+		for key, value := range host.NodeSelector {
+			workerPod.Spec.NodeSelector[key] = value
+		}
+		workerPod.Spec.Tolerations = append(
+			workerPod.Spec.Tolerations,
+			getTolerations(host.Tolerations)...,
+		)
+		workerPod.Spec.Affinity = getAffinity(host.NodeAffinity)
+		workerPod.Spec.PriorityClassName = host.PriorityClassName
+	}
+
 	podClient := s.kubeClient.CoreV1().Pods(project.Kubernetes.Namespace)
 	if _, err := podClient.Create(
 		ctx,
@@ -1064,6 +1199,23 @@ func (s *substrate) createJobSecret(
 		}
 	}
 
+	// Config files are stored alongside environment variables
+	addConfigFiles := func(containerName string, volumes []api.JobVolume) {
+		for i, volume := range volumes {
+			if volume.ConfigFiles == nil {
+				continue
+			}
+			for fileName, content := range volume.ConfigFiles.Files {
+				jobSecret.StringData[configFileKey(containerName, i, fileName)] =
+					content
+			}
+		}
+	}
+	addConfigFiles(jobName, jobSpec.PrimaryContainer.Volumes)
+	for sidecarName, sidecarSpec := range jobSpec.SidecarContainers {
+		addConfigFiles(sidecarName, sidecarSpec.Volumes)
+	}
+
 	secretsClient := s.kubeClient.CoreV1().Secrets(project.Kubernetes.Namespace)
 	if _, err := secretsClient.Create(
 		ctx,
@@ -1224,19 +1376,73 @@ func (s *substrate) createJobPod(
 		i++
 	}
 
+	// Add any additional volumes requested by the job's containers. Each
+	// container's volumes are named after the container's position in the pod,
+	// except for cache volumes, which are named after the cache so that
+	// containers mounting the same cache share a single volume.
+	volumeNames := map[string]struct{}{}
+	for _, volume := range volumes {
+		volumeNames[volume.Name] = struct{}{}
+	}
+	for i, container := range containers {
+		containerSpec := jobSpec.PrimaryContainer
+		if i > 0 {
+			containerSpec = jobSpec.SidecarContainers[container.Name]
+		}
+		jobVolumes, volumeMounts := getJobVolumes(
+			event.ID,
+			jobName,
+			container.Name,
+			fmt.Sprintf("volume-%d", i),
+			containerSpec.Volumes,
+		)
+		containers[i].VolumeMounts =
+			append(containers[i].VolumeMounts, volumeMounts...)
+		for _, volume := range jobVolumes {
+			if _, ok := volumeNames[volume.Name]; ok {
+				continue
+			}
+			volumeNames[volume.Name] = struct{}{}
+			volumes = append(volumes, volume)
+		}
+		for _, volume := range containerSpec.Volumes {
+			if volume.Cache == nil {
+				continue
+			}
+			if err := s.createCacheVolumePVC(ctx, project, *volume.Cache); err != nil {
+				return errors.Wrapf(
+					err,
+					"error creating cache volume %q for event %q job %q",
+					volume.Cache.Name,
+					event.ID,
+					jobName,
+				)
+			}
+		}
+	}
+
+	// Every attempt at the job gets its own pod
+	attempt := 1
+	if job, ok := event.Worker.Job(jobName); ok &&
+		job.Status != nil &&
+		job.Status.Attempt > 1 {
+		attempt = job.Status.Attempt
+	}
+
 	jobPod := corev1.Pod{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      myk8s.JobPodName(event.ID, jobName),
+			Name:      myk8s.JobAttemptPodName(event.ID, jobName, attempt),
 			Namespace: project.Kubernetes.Namespace,
 			Annotations: map[string]string{
 				myk8s.AnnotationTimeoutDuration: fmt.Sprint(jobSpec.TimeoutDuration),
 			},
 			Labels: map[string]string{
-				myk8s.LabelBrigadeID: s.config.BrigadeID,
-				myk8s.LabelComponent: myk8s.LabelKeyJob,
-				myk8s.LabelProject:   event.ProjectID,
-				myk8s.LabelEvent:     event.ID,
-				myk8s.LabelJob:       jobName,
+				myk8s.LabelBrigadeID:  s.config.BrigadeID,
+				myk8s.LabelComponent:  myk8s.LabelKeyJob,
+				myk8s.LabelProject:    event.ProjectID,
+				myk8s.LabelEvent:      event.ID,
+				myk8s.LabelJob:        jobName,
+				myk8s.LabelJobAttempt: strconv.Itoa(attempt),
 			},
 		},
 		Spec: corev1.PodSpec{
@@ -1281,6 +1487,18 @@ func (s *substrate) createJobPod(
 		jobPod.Spec.Tolerations = append(jobPod.Spec.Tolerations, toleration)
 	}
 
+	if jobSpec.Host != nil {
+		for key, value := range jobSpec.Host.NodeSelector {
+			jobPod.Spec.NodeSelector[key] = value
+		}
+		jobPod.Spec.Tolerations = append(
+			jobPod.Spec.Tolerations,
+			getTolerations(jobSpec.Host.Tolerations)...,
+		)
+		jobPod.Spec.Affinity = getAffinity(jobSpec.Host.NodeAffinity)
+		jobPod.Spec.PriorityClassName = jobSpec.Host.PriorityClassName
+	}
+
 	podClient := s.kubeClient.CoreV1().Pods(project.Kubernetes.Namespace)
 	if _, err := podClient.Create(
 		ctx,
@@ -1313,6 +1531,7 @@ func getContainerFromSpec(
 		Args:            spec.Arguments,
 		Env:             make([]corev1.EnvVar, len(spec.Environment)),
 		VolumeMounts:    []corev1.VolumeMount{},
+		Resources:       getResourceRequirements(spec.Resources),
 	}
 	i := 0
 	for key := range spec.Environment {
@@ -1363,3 +1582,211 @@ func getContainerFromSpec(
 	}
 	return container
 }
+
+// configFileKey returns the key under which the content of the specified
+// config file belonging to the specified container's specified volume is
+// stored in a Job's secret.
+func configFileKey(
+	containerName string,
+	volumeIndex int,
+	fileName string,
+) string {
+	return fmt.Sprintf("%s.volume-%d.%s", containerName, volumeIndex, fileName)
+}
+
+// getJobVolumes returns Kubernetes volumes and corresponding volume mounts for
+// the additional volumes requested by the specified container of a Job. Apart
+// from cache volumes, volumes are named using the provided prefix, which must
+// be unique to the container. Quantities that cannot be parsed are ignored.
+// These should have been validated before reaching the substrate.
+func getJobVolumes(
+	eventID string,
+	jobName string,
+	containerName string,
+	volumeNamePrefix string,
+	jobVolumes []api.JobVolume,
+) ([]corev1.Volume, []corev1.VolumeMount) {
+	volumes := []corev1.Volume{}
+	volumeMounts := []corev1.VolumeMount{}
+	for i, jobVolume := range jobVolumes {
+		volume := corev1.Volume{
+			Name: fmt.Sprintf("%s-%d", volumeNamePrefix, i),
+		}
+		readOnly := jobVolume.ReadOnly
+		switch {
+		case jobVolume.Secrets != nil:
+			items := make([]corev1.KeyToPath, len(jobVolume.Secrets.Keys))
+			for j, key := range jobVolume.Secrets.Keys {
+				items[j] = corev1.KeyToPath{
+					Key:  key,
+					Path: key,
+				}
+			}
+			volume.VolumeSource = corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: "project-secrets",
+					Items:      items,
+				},
+			}
+			readOnly = true
+		case jobVolume.ConfigFiles != nil:
+			fileNames := make([]string, 0, len(jobVolume.ConfigFiles.Files))
+			for fileName := range jobVolume.ConfigFiles.Files {
+				fileNames = append(fileNames, fileName)
+			}
+			sort.Strings(fileNames)
+			items := make([]corev1.KeyToPath, len(fileNames))
+			for j, fileName := range fileNames {
+				items[j] = corev1.KeyToPath{
+					Key:  configFileKey(containerName, i, fileName),
+					Path: fileName,
+				}
+			}
+			volume.VolumeSource = corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: myk8s.JobSecretName(eventID, jobName),
+					Items:      items,
+				},
+			}
+			readOnly = true
+		case jobVolume.Tmpfs != nil:
+			emptyDir := &corev1.EmptyDirVolumeSource{
+				Medium: corev1.StorageMediumMemory,
+			}
+			if sizeLimit, err :=
+				resource.ParseQuantity(jobVolume.Tmpfs.SizeLimit); err == nil {
+				emptyDir.SizeLimit = &sizeLimit
+			}
+			volume.VolumeSource = corev1.VolumeSource{
+				EmptyDir: emptyDir,
+			}
+		case jobVolume.Cache != nil:
+			volume.Name = myk8s.CacheVolumePVCName(jobVolume.Cache.Name)
+			volume.VolumeSource = corev1.VolumeSource{
+				PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+					ClaimName: myk8s.CacheVolumePVCName(jobVolume.Cache.Name),
+				},
+			}
+		default:
+			continue
+		}
+		volumes = append(volumes, volume)
+		volumeMounts = append(
+			volumeMounts,
+			corev1.VolumeMount{
+				Name:      volume.Name,
+				MountPath: jobVolume.MountPath,
+				ReadOnly:  readOnly,
+			},
+		)
+	}
+	return volumes, volumeMounts
+}
+
+// getResourceRequirements returns Kubernetes resource requirements
+// corresponding to the provided ContainerResources. Quantities that cannot be
+// parsed are ignored. These should have been validated before reaching the
+// substrate.
+// getTolerations converts the provided api.Tolerations to their Kubernetes
+// equivalents.
+func getTolerations(tolerations []api.Toleration) []corev1.Toleration {
+	if len(tolerations) == 0 {
+		return nil
+	}
+	k8sTolerations := make([]corev1.Toleration, len(tolerations))
+	for i, toleration := range tolerations {
+		k8sTolerations[i] = corev1.Toleration{
+			Key:      toleration.Key,
+			Operator: corev1.TolerationOperator(toleration.Operator),
+			Value:    toleration.Value,
+			Effect:   corev1.TaintEffect(toleration.Effect),
+		}
+	}
+	return k8sTolerations
+}
+
+// getAffinity converts the provided api.NodeAffinity to its Kubernetes
+// equivalent.
+func getAffinity(nodeAffinity *api.NodeAffinity) *corev1.Affinity {
+	if nodeAffinity == nil ||
+		(len(nodeAffinity.Required) == 0 && len(nodeAffinity.Preferred) == 0) {
+		return nil
+	}
+	k8sNodeAffinity := &corev1.NodeAffinity{}
+	if len(nodeAffinity.Required) > 0 {
+		// All required requirements are ANDed together in a single term
+		k8sNodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution =
+			&corev1.NodeSelector{
+				NodeSelectorTerms: []corev1.NodeSelectorTerm{
+					{
+						MatchExpressions: getNodeSelectorRequirements(
+							nodeAffinity.Required,
+						),
+					},
+				},
+			}
+	}
+	for _, preferred := range nodeAffinity.Preferred {
+		k8sNodeAffinity.PreferredDuringSchedulingIgnoredDuringExecution = append(
+			k8sNodeAffinity.PreferredDuringSchedulingIgnoredDuringExecution,
+			corev1.PreferredSchedulingTerm{
+				Weight: int32(preferred.Weight),
+				Preference: corev1.NodeSelectorTerm{
+					MatchExpressions: getNodeSelectorRequirements(
+						[]api.NodeSelectorRequirement{preferred.NodeSelectorRequirement},
+					),
+				},
+			},
+		)
+	}
+	return &corev1.Affinity{
+		NodeAffinity: k8sNodeAffinity,
+	}
+}
+
+// getNodeSelectorRequirements converts the provided
+// api.NodeSelectorRequirements to their Kubernetes equivalents.
+func getNodeSelectorRequirements(
+	requirements []api.NodeSelectorRequirement,
+) []corev1.NodeSelectorRequirement {
+	k8sRequirements := make(
+		[]corev1.NodeSelectorRequirement,
+		len(requirements),
+	)
+	for i, requirement := range requirements {
+		k8sRequirements[i] = corev1.NodeSelectorRequirement{
+			Key:      requirement.Key,
+			Operator: corev1.NodeSelectorOperator(requirement.Operator),
+			Values:   requirement.Values,
+		}
+	}
+	return k8sRequirements
+}
+
+func getResourceRequirements(
+	resources *api.ContainerResources,
+) corev1.ResourceRequirements {
+	requirements := corev1.ResourceRequirements{}
+	if resources == nil {
+		return requirements
+	}
+	requirements.Requests = getResourceList(resources.Requests)
+	requirements.Limits = getResourceList(resources.Limits)
+	return requirements
+}
+
+// getResourceList returns a Kubernetes resource list corresponding to the
+// provided ResourceQuantities, or nil if no quantities are specified.
+func getResourceList(quantities api.ResourceQuantities) corev1.ResourceList {
+	list := corev1.ResourceList{}
+	if cpu, err := resource.ParseQuantity(quantities.CPU); err == nil {
+		list[corev1.ResourceCPU] = cpu
+	}
+	if memory, err := resource.ParseQuantity(quantities.Memory); err == nil {
+		list[corev1.ResourceMemory] = memory
+	}
+	if len(list) == 0 {
+		return nil
+	}
+	return list
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/kubernetes/fake"
@@ -33,6 +34,7 @@ func TestNewSubstrate(t *testing.T) {
 
 func TestSubstrateCountRunningWorkers(t *testing.T) {
 	const testBrigadeID = "4077th"
+	const testProjectID = "blue-book"
 	const testNamespace = "foo"
 	kubeClient := fake.NewSimpleClientset()
 	podsClient := kubeClient.CoreV1().Pods(testNamespace)
@@ -63,6 +65,26 @@ func TestSubstrateCountRunningWorkers(t *testing.T) {
 				Labels: map[string]string{
 					myk8s.LabelBrigadeID: testBrigadeID,
 					myk8s.LabelComponent: myk8s.LabelKeyWorker,
+					myk8s.LabelProject:   testProjectID,
+				},
+			},
+			Status: corev1.PodStatus{
+				Phase: corev1.PodRunning,
+			},
+		},
+		metav1.CreateOptions{},
+	)
+	require.NoError(t, err)
+	// This pod has correct labels, but belongs to a different project
+	_, err = podsClient.Create(
+		context.Background(),
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "baz",
+				Labels: map[string]string{
+					myk8s.LabelBrigadeID: testBrigadeID,
+					myk8s.LabelComponent: myk8s.LabelKeyWorker,
+					myk8s.LabelProject:   "other-project",
 				},
 			},
 			Status: corev1.PodStatus{
@@ -78,13 +100,25 @@ func TestSubstrateCountRunningWorkers(t *testing.T) {
 		},
 		kubeClient: kubeClient,
 	}
-	count, err := s.CountRunningWorkers(context.Background())
+	count, err := s.CountRunningWorkers(
+		context.Background(),
+		api.RunningWorkersSelector{},
+	)
+	require.NoError(t, err)
+	require.Equal(t, 2, count.Count)
+	count, err = s.CountRunningWorkers(
+		context.Background(),
+		api.RunningWorkersSelector{
+			ProjectID: testProjectID,
+		},
+	)
 	require.NoError(t, err)
 	require.Equal(t, 1, count.Count)
 }
 
 func TestSubstrateCountRunningJobs(t *testing.T) {
 	const testBrigadeID = "4077th"
+	const testProjectID = "blue-book"
 	const testNamespace = "foo"
 	kubeClient := fake.NewSimpleClientset()
 	podsClient := kubeClient.CoreV1().Pods(testNamespace)
@@ -115,6 +149,26 @@ func TestSubstrateCountRunningJobs(t *testing.T) {
 				Labels: map[string]string{
 					myk8s.LabelBrigadeID: testBrigadeID,
 					myk8s.LabelComponent: myk8s.LabelKeyJob,
+					myk8s.LabelProject:   testProjectID,
+				},
+			},
+			Status: corev1.PodStatus{
+				Phase: corev1.PodRunning,
+			},
+		},
+		metav1.CreateOptions{},
+	)
+	require.NoError(t, err)
+	// This pod has correct labels, but belongs to a different project
+	_, err = podsClient.Create(
+		context.Background(),
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "baz",
+				Labels: map[string]string{
+					myk8s.LabelBrigadeID: testBrigadeID,
+					myk8s.LabelComponent: myk8s.LabelKeyJob,
+					myk8s.LabelProject:   "other-project",
 				},
 			},
 			Status: corev1.PodStatus{
@@ -130,7 +184,18 @@ func TestSubstrateCountRunningJobs(t *testing.T) {
 		},
 		kubeClient: kubeClient,
 	}
-	count, err := s.CountRunningJobs(context.Background())
+	count, err := s.CountRunningJobs(
+		context.Background(),
+		api.RunningJobsSelector{},
+	)
+	require.NoError(t, err)
+	require.Equal(t, 2, count.Count)
+	count, err = s.CountRunningJobs(
+		context.Background(),
+		api.RunningJobsSelector{
+			ProjectID: testProjectID,
+		},
+	)
 	require.NoError(t, err)
 	require.Equal(t, 1, count.Count)
 }
@@ -584,10 +649,12 @@ func TestSubstrateScheduleWorker(t *testing.T) {
 					NewWriterFn: func(queueName string) (queue.Writer, error) {
 						return &mockQueueWriter{
 							WriteFn: func(
-								context.Context,
-								string,
-								*queue.MessageOptions,
+								_ context.Context,
+								_ string,
+								opts *queue.MessageOptions,
 							) error {
+								require.True(t, opts.Durable)
+								require.Equal(t, uint8(7), opts.Priority)
 								return nil
 							},
 							CloseFn: func(context.Context) error {
@@ -611,6 +678,7 @@ func TestSubstrateScheduleWorker(t *testing.T) {
 					ObjectMeta: meta.ObjectMeta{
 						ID: testEventID,
 					},
+					Priority: 7,
 				},
 			)
 			testCase.assertions(err)
@@ -1070,6 +1138,40 @@ func TestSubstrateDeleteJob(t *testing.T) {
 	require.NoError(t, err)
 }
 
+// TODO: Find a better way to test this. Unfortunately, the DeleteCollection
+// function on a *fake.ClientSet doesn't ACTUALLY delete collections of
+// resources based on the labels provided.
+//
+// Refer to: https://github.com/kubernetes/client-go/issues/609
+//
+// This makes it basically impossible to assert what we'd LIKE to assert here--
+// that pods belonging to earlier attempts at the Job are deleted while the
+// current attempt's pod is left alone. We'll settle for invoking
+// DeleteEarlierJobAttempts(...) and asserting we get no error-- so we at least
+// get some test coverage for this function. We'll have to make sure this
+// behavior is well-covered by integration or e2e tests in the future.
+func TestSubstrateDeleteEarlierJobAttempts(t *testing.T) {
+	s := &substrate{
+		kubeClient: fake.NewSimpleClientset(),
+	}
+	err := s.DeleteEarlierJobAttempts(
+		context.Background(),
+		api.Project{
+			Kubernetes: &api.KubernetesDetails{
+				Namespace: "foo",
+			},
+		},
+		api.Event{
+			ObjectMeta: meta.ObjectMeta{
+				ID: "123456789",
+			},
+		},
+		"italian",
+		2,
+	)
+	require.NoError(t, err)
+}
+
 // TODO: Find a better way to test this. Unfortunately, the DeleteCollection
 // function on a *fake.ClientSet doesn't ACTUALLY delete collections of
 // resources based on the labels provided.
@@ -1206,8 +1308,115 @@ func TestSubstrateCreateWorkspacePVC(t *testing.T) {
 	}
 }
 
+func TestSubstrateCreateCacheVolumePVC(t *testing.T) {
+	testProject := api.Project{
+		Kubernetes: &api.KubernetesDetails{
+			Namespace: "foo",
+		},
+	}
+	testCases := []struct {
+		name       string
+		cache      api.CacheVolumeSource
+		setup      func() *substrate
+		assertions func(kubernetes.Interface, error)
+	}{
+		{
+			name: "unparsable storage quantity",
+			cache: api.CacheVolumeSource{
+				Name: "go-modules",
+				Size: "10ZillionBytes",
+			},
+			setup: func() *substrate {
+				return &substrate{}
+			},
+			assertions: func(_ kubernetes.Interface, err error) {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), "error parsing storage quantity")
+			},
+		},
+		{
+			name: "pvc already exists",
+			cache: api.CacheVolumeSource{
+				Name: "go-modules",
+			},
+			setup: func() *substrate {
+				kubeClient := fake.NewSimpleClientset()
+				_, err := kubeClient.CoreV1().PersistentVolumeClaims(
+					testProject.Kubernetes.Namespace,
+				).Create(
+					context.Background(),
+					&corev1.PersistentVolumeClaim{
+						ObjectMeta: metav1.ObjectMeta{
+							Name: myk8s.CacheVolumePVCName("go-modules"),
+						},
+					},
+					metav1.CreateOptions{},
+				)
+				require.NoError(t, err)
+				return &substrate{
+					kubeClient: kubeClient,
+				}
+			},
+			assertions: func(_ kubernetes.Interface, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "success",
+			cache: api.CacheVolumeSource{
+				Name: "go-modules",
+				Size: "5Gi",
+			},
+			setup: func() *substrate {
+				return &substrate{
+					kubeClient: fake.NewSimpleClientset(),
+				}
+			},
+			assertions: func(kubeClient kubernetes.Interface, err error) {
+				require.NoError(t, err)
+				pvc, err := kubeClient.CoreV1().PersistentVolumeClaims(
+					testProject.Kubernetes.Namespace,
+				).Get(
+					context.Background(),
+					myk8s.CacheVolumePVCName("go-modules"),
+					metav1.GetOptions{},
+				)
+				require.NoError(t, err)
+				require.Equal(
+					t,
+					resource.MustParse("5Gi"),
+					pvc.Spec.Resources.Requests["storage"],
+				)
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			substrate := testCase.setup()
+			err := substrate.createCacheVolumePVC(
+				context.Background(),
+				testProject,
+				testCase.cache,
+			)
+			testCase.assertions(substrate.kubeClient, err)
+		})
+	}
+}
+
 func TestSubstrateCreateWorkerPod(t *testing.T) {
 	testProject := api.Project{
+		Spec: api.ProjectSpec{
+			Resources: &api.ResourcePolicy{
+				Defaults: api.ContainerResources{
+					Requests: api.ResourceQuantities{
+						CPU: "250m",
+					},
+				},
+				Max: api.ResourceQuantities{
+					Memory: "1Gi",
+				},
+			},
+		},
 		Kubernetes: &api.KubernetesDetails{
 			Namespace: "foo",
 		},
@@ -1282,6 +1491,18 @@ func TestSubstrateCreateWorkerPod(t *testing.T) {
 				)
 				require.NoError(t, err)
 				require.NotNil(t, pod)
+				require.Equal(
+					t,
+					corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{
+							corev1.ResourceCPU: resource.MustParse("250m"),
+						},
+						Limits: corev1.ResourceList{
+							corev1.ResourceMemory: resource.MustParse("1Gi"),
+						},
+					},
+					pod.Spec.Containers[0].Resources,
+				)
 			},
 		},
 	}
@@ -1321,6 +1542,20 @@ func TestSubstrateCreateJobSecret(t *testing.T) {
 						"BAT": "baz",
 					},
 				},
+				Volumes: []api.JobVolume{
+					{
+						MountPath: "/tmp",
+						Tmpfs:     &api.TmpfsVolumeSource{},
+					},
+					{
+						MountPath: "/etc/foo",
+						ConfigFiles: &api.ConfigFilesVolumeSource{
+							Files: map[string]string{
+								"foo.yaml": "bar: bat",
+							},
+						},
+					},
+				},
 			},
 		},
 	}
@@ -1379,6 +1614,9 @@ func TestSubstrateCreateJobSecret(t *testing.T) {
 				val, ok = secret.StringData["helper.BAT"]
 				require.True(t, ok)
 				require.Equal(t, "baz", val)
+				val, ok = secret.StringData["helper.volume-1.foo.yaml"]
+				require.True(t, ok)
+				require.Equal(t, "bar: bat", val)
 			},
 		},
 	}
@@ -1509,6 +1747,7 @@ func TestSubstrateCreateJobPod(t *testing.T) {
 				)
 				require.NoError(t, err)
 				require.NotNil(t, pod)
+				require.Equal(t, "1", pod.Labels[myk8s.LabelJobAttempt])
 				// Volumes:
 				require.Len(t, pod.Spec.Volumes, 3)
 				require.Equal(t, "workspace", pod.Spec.Volumes[0].Name)
@@ -1571,6 +1810,90 @@ func TestSubstrateCreateJobPod(t *testing.T) {
 				// )
 			},
 		},
+		{
+			name: "success with additional volumes",
+			setup: func() *substrate {
+				return &substrate{
+					config:     testSubstrateConfig,
+					kubeClient: fake.NewSimpleClientset(),
+				}
+			},
+			jobSpec: func() api.JobSpec {
+				cacheVolume := api.JobVolume{
+					MountPath: "/var/cache",
+					Cache: &api.CacheVolumeSource{
+						Name: "go-modules",
+					},
+				}
+				return api.JobSpec{
+					PrimaryContainer: api.JobContainerSpec{
+						Volumes: []api.JobVolume{
+							{
+								MountPath: "/var/secrets",
+								Secrets: &api.SecretsVolumeSource{
+									Keys: []string{"token"},
+								},
+							},
+							cacheVolume,
+						},
+					},
+					SidecarContainers: map[string]api.JobContainerSpec{
+						"helper": {
+							Volumes: []api.JobVolume{cacheVolume},
+						},
+					},
+				}
+			},
+			assertions: func(kubeClient kubernetes.Interface, err error) {
+				require.NoError(t, err)
+				pod, err := kubeClient.CoreV1().Pods(
+					testProject.Kubernetes.Namespace,
+				).Get(
+					context.Background(),
+					myk8s.JobPodName(testEvent.ID, testJobName),
+					metav1.GetOptions{},
+				)
+				require.NoError(t, err)
+				// The cache volume is shared by both containers
+				require.Len(t, pod.Spec.Volumes, 2)
+				require.Equal(t, "volume-0-0", pod.Spec.Volumes[0].Name)
+				require.Equal(t, "cache-go-modules", pod.Spec.Volumes[1].Name)
+				require.Equal(
+					t,
+					[]corev1.VolumeMount{
+						{
+							Name:      "volume-0-0",
+							MountPath: "/var/secrets",
+							ReadOnly:  true,
+						},
+						{
+							Name:      "cache-go-modules",
+							MountPath: "/var/cache",
+						},
+					},
+					pod.Spec.Containers[0].VolumeMounts,
+				)
+				require.Equal(
+					t,
+					[]corev1.VolumeMount{
+						{
+							Name:      "cache-go-modules",
+							MountPath: "/var/cache",
+						},
+					},
+					pod.Spec.Containers[1].VolumeMounts,
+				)
+				// The cache volume's PVC should have been created
+				_, err = kubeClient.CoreV1().PersistentVolumeClaims(
+					testProject.Kubernetes.Namespace,
+				).Get(
+					context.Background(),
+					myk8s.CacheVolumePVCName("go-modules"),
+					metav1.GetOptions{},
+				)
+				require.NoError(t, err)
+			},
+		},
 		{
 			name: "success with windows",
 			setup: func() *substrate {
@@ -1865,6 +2188,226 @@ func TestSubstrateCreatePodWithNodeSelectorAndToleration(t *testing.T) {
 	}
 }
 
+func TestSubstrateCreatePodsWithHost(t *testing.T) {
+	testProject := api.Project{
+		Kubernetes: &api.KubernetesDetails{
+			Namespace: "foo",
+		},
+	}
+	testTolerations := []api.Toleration{
+		{
+			Key:      "gpu",
+			Operator: api.TolerationOperatorExists,
+			Effect:   api.TaintEffectNoSchedule,
+		},
+	}
+	testNodeAffinity := &api.NodeAffinity{
+		Required: []api.NodeSelectorRequirement{
+			{
+				Key:      "zone",
+				Operator: api.NodeSelectorOperatorNotIn,
+				Values:   []string{"us-east-1a"},
+			},
+		},
+	}
+	testEvent := api.Event{
+		ObjectMeta: meta.ObjectMeta{
+			ID: "123456789",
+		},
+		Worker: api.Worker{
+			Spec: api.WorkerSpec{
+				Host: &api.WorkerHost{
+					NodeSelector: map[string]string{
+						"pool": "workers",
+					},
+					Tolerations:       testTolerations,
+					NodeAffinity:      testNodeAffinity,
+					PriorityClassName: "high",
+				},
+			},
+		},
+	}
+	testJobName := "italian"
+	expectedTolerations := []corev1.Toleration{
+		{
+			Key:      "foo",
+			Operator: corev1.TolerationOpExists,
+		},
+		{
+			Key:      "gpu",
+			Operator: corev1.TolerationOpExists,
+			Effect:   corev1.TaintEffectNoSchedule,
+		},
+	}
+	expectedAffinity := &corev1.Affinity{
+		NodeAffinity: &corev1.NodeAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+				NodeSelectorTerms: []corev1.NodeSelectorTerm{
+					{
+						MatchExpressions: []corev1.NodeSelectorRequirement{
+							{
+								Key:      "zone",
+								Operator: corev1.NodeSelectorOpNotIn,
+								Values:   []string{"us-east-1a"},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	substrate := &substrate{
+		kubeClient: fake.NewSimpleClientset(),
+		config: SubstrateConfig{
+			NodeSelectorKey:   "foo",
+			NodeSelectorValue: "bar",
+			TolerationKey:     "foo",
+		},
+	}
+	err := substrate.createWorkerPod(
+		context.Background(),
+		testProject,
+		testEvent,
+	)
+	require.NoError(t, err)
+	err = substrate.createJobPod(
+		context.Background(),
+		testProject,
+		testEvent,
+		testJobName,
+		api.JobSpec{
+			Host: &api.JobHost{
+				NodeSelector: map[string]string{
+					"pool": "jobs",
+				},
+				Tolerations:       testTolerations,
+				NodeAffinity:      testNodeAffinity,
+				PriorityClassName: "low",
+			},
+		},
+	)
+	require.NoError(t, err)
+
+	workerPod, err := substrate.kubeClient.CoreV1().Pods(
+		testProject.Kubernetes.Namespace,
+	).Get(
+		context.Background(),
+		myk8s.WorkerPodName(testEvent.ID),
+		metav1.GetOptions{},
+	)
+	require.NoError(t, err)
+	require.Equal(
+		t,
+		map[string]string{
+			"foo":  "bar",
+			"pool": "workers",
+		},
+		workerPod.Spec.NodeSelector,
+	)
+	require.Equal(t, expectedTolerations, workerPod.Spec.Tolerations)
+	require.Equal(t, expectedAffinity, workerPod.Spec.Affinity)
+	require.Equal(t, "high", workerPod.Spec.PriorityClassName)
+
+	jobPod, err := substrate.kubeClient.CoreV1().Pods(
+		testProject.Kubernetes.Namespace,
+	).Get(
+		context.Background(),
+		myk8s.JobPodName(testEvent.ID, testJobName),
+		metav1.GetOptions{},
+	)
+	require.NoError(t, err)
+	require.Equal(
+		t,
+		map[string]string{
+			"foo":  "bar",
+			"pool": "jobs",
+		},
+		jobPod.Spec.NodeSelector,
+	)
+	require.Equal(t, expectedTolerations, jobPod.Spec.Tolerations)
+	require.Equal(t, expectedAffinity, jobPod.Spec.Affinity)
+	require.Equal(t, "low", jobPod.Spec.PriorityClassName)
+}
+
+func TestGetAffinity(t *testing.T) {
+	testCases := []struct {
+		name             string
+		nodeAffinity     *api.NodeAffinity
+		expectedAffinity *corev1.Affinity
+	}{
+		{
+			name:             "nil node affinity",
+			nodeAffinity:     nil,
+			expectedAffinity: nil,
+		},
+		{
+			name:             "empty node affinity",
+			nodeAffinity:     &api.NodeAffinity{},
+			expectedAffinity: nil,
+		},
+		{
+			name: "preferred node affinity",
+			nodeAffinity: &api.NodeAffinity{
+				Preferred: []api.PreferredNodeSelectorRequirement{
+					{
+						Weight: 10,
+						NodeSelectorRequirement: api.NodeSelectorRequirement{
+							Key:      "disk",
+							Operator: api.NodeSelectorOperatorIn,
+							Values:   []string{"ssd"},
+						},
+					},
+					{
+						Weight: 50,
+						NodeSelectorRequirement: api.NodeSelectorRequirement{
+							Key:      "spot",
+							Operator: api.NodeSelectorOperatorDoesNotExist,
+						},
+					},
+				},
+			},
+			expectedAffinity: &corev1.Affinity{
+				NodeAffinity: &corev1.NodeAffinity{
+					PreferredDuringSchedulingIgnoredDuringExecution: []corev1.PreferredSchedulingTerm{ // nolint: lll
+						{
+							Weight: 10,
+							Preference: corev1.NodeSelectorTerm{
+								MatchExpressions: []corev1.NodeSelectorRequirement{
+									{
+										Key:      "disk",
+										Operator: corev1.NodeSelectorOpIn,
+										Values:   []string{"ssd"},
+									},
+								},
+							},
+						},
+						{
+							Weight: 50,
+							Preference: corev1.NodeSelectorTerm{
+								MatchExpressions: []corev1.NodeSelectorRequirement{
+									{
+										Key:      "spot",
+										Operator: corev1.NodeSelectorOpDoesNotExist,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			require.Equal(
+				t,
+				testCase.expectedAffinity,
+				getAffinity(testCase.nodeAffinity),
+			)
+		})
+	}
+}
+
 func TestGenerateNewNamespace(t *testing.T) {
 	namespace := generateNewNamespace()
 	tokens := strings.SplitN(namespace, "-", 2)
@@ -1905,3 +2448,125 @@ func (m *mockQueueWriter) Write(
 func (m *mockQueueWriter) Close(ctx context.Context) error {
 	return m.CloseFn(ctx)
 }
+
+func TestGetResourceRequirements(t *testing.T) {
+	testCases := []struct {
+		name       string
+		resources  *api.ContainerResources
+		assertions func(corev1.ResourceRequirements)
+	}{
+		{
+			name: "nil resources",
+			assertions: func(requirements corev1.ResourceRequirements) {
+				require.Equal(t, corev1.ResourceRequirements{}, requirements)
+			},
+		},
+		{
+			name: "requests and limits",
+			resources: &api.ContainerResources{
+				Requests: api.ResourceQuantities{
+					CPU:    "500m",
+					Memory: "256Mi",
+				},
+				Limits: api.ResourceQuantities{
+					Memory: "1Gi",
+				},
+			},
+			assertions: func(requirements corev1.ResourceRequirements) {
+				require.Equal(
+					t,
+					corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{
+							corev1.ResourceCPU:    resource.MustParse("500m"),
+							corev1.ResourceMemory: resource.MustParse("256Mi"),
+						},
+						Limits: corev1.ResourceList{
+							corev1.ResourceMemory: resource.MustParse("1Gi"),
+						},
+					},
+					requirements,
+				)
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.assertions(getResourceRequirements(testCase.resources))
+		})
+	}
+}
+
+func TestGetJobVolumes(t *testing.T) {
+	volumes, volumeMounts := getJobVolumes(
+		"123456789",
+		"italian",
+		"helper",
+		"volume-1",
+		[]api.JobVolume{
+			{
+				MountPath: "/etc/foo",
+				ConfigFiles: &api.ConfigFilesVolumeSource{
+					Files: map[string]string{
+						"foo.yaml": "bar: bat",
+						"bar.yaml": "baz: qux",
+					},
+				},
+			},
+			{
+				MountPath: "/tmp",
+				Tmpfs: &api.TmpfsVolumeSource{
+					SizeLimit: "64Mi",
+				},
+			},
+		},
+	)
+	sizeLimit := resource.MustParse("64Mi")
+	require.Equal(
+		t,
+		[]corev1.Volume{
+			{
+				Name: "volume-1-0",
+				VolumeSource: corev1.VolumeSource{
+					Secret: &corev1.SecretVolumeSource{
+						SecretName: myk8s.JobSecretName("123456789", "italian"),
+						Items: []corev1.KeyToPath{
+							{
+								Key:  "helper.volume-0.bar.yaml",
+								Path: "bar.yaml",
+							},
+							{
+								Key:  "helper.volume-0.foo.yaml",
+								Path: "foo.yaml",
+							},
+						},
+					},
+				},
+			},
+			{
+				Name: "volume-1-1",
+				VolumeSource: corev1.VolumeSource{
+					EmptyDir: &corev1.EmptyDirVolumeSource{
+						Medium:    corev1.StorageMediumMemory,
+						SizeLimit: &sizeLimit,
+					},
+				},
+			},
+		},
+		volumes,
+	)
+	require.Equal(
+		t,
+		[]corev1.VolumeMount{
+			{
+				Name:      "volume-1-0",
+				MountPath: "/etc/foo",
+				ReadOnly:  true,
+			},
+			{
+				Name:      "volume-1-1",
+				MountPath: "/tmp",
+			},
+		},
+		volumeMounts,
+	)
+}
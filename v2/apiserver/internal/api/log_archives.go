@@ -0,0 +1,361 @@
+package api
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"time"
+
+	"github.com/brigadecore/brigade/v2/apiserver/internal/meta"
+	myk8s "github.com/brigadecore/brigade/v2/internal/kubernetes"
+	"github.com/pkg/errors"
+)
+
+// logArchiveManifestName is the name of the file, at the root of every archive
+// of an Event's logs, that describes the archive's contents.
+const logArchiveManifestName = "manifest.json"
+
+// LogArchiveSource represents the store from which a file of logs in an
+// archive of an Event's logs was retrieved.
+type LogArchiveSource string
+
+const (
+	// LogArchiveSourceCool represents logs retrieved from the cool logs store.
+	LogArchiveSourceCool LogArchiveSource = "COOL"
+	// LogArchiveSourceWarm represents logs retrieved from the warm logs store.
+	LogArchiveSourceWarm LogArchiveSource = "WARM"
+)
+
+// LogArchiveManifest describes the contents of an archive of an Event's logs.
+type LogArchiveManifest struct {
+	// EventID is the ID of the Event whose logs were archived.
+	EventID string `json:"eventID"`
+	// ProjectID is the ID of the Project the Event belongs to.
+	ProjectID string `json:"projectID"`
+	// Created indicates the time at which the archive was created.
+	Created time.Time `json:"created"`
+	// Files describes each file of logs in the archive.
+	Files []LogArchiveFile `json:"files"`
+}
+
+// MarshalJSON amends LogArchiveManifest instances with type metadata so that
+// clients do not need to be concerned with the tedium of doing so.
+func (l LogArchiveManifest) MarshalJSON() ([]byte, error) {
+	type Alias LogArchiveManifest
+	return json.Marshal(
+		struct {
+			meta.TypeMeta `json:",inline"`
+			Alias         `json:",inline"`
+		}{
+			TypeMeta: meta.TypeMeta{
+				APIVersion: meta.APIVersion,
+				Kind:       "LogArchiveManifest",
+			},
+			Alias: (Alias)(l),
+		},
+	)
+}
+
+// LogArchiveFile describes a single file of logs in an archive of an Event's
+// logs.
+type LogArchiveFile struct {
+	// Path is the path of the file within the archive.
+	Path string `json:"path"`
+	// Job is the name of the Job whose logs the file contains. If empty, the
+	// file contains logs from the Event's Worker.
+	Job string `json:"job,omitempty"`
+	// Attempt is the attempt at the Job whose logs the file contains.
+	Attempt int `json:"attempt,omitempty"`
+	// Container is the name of the container whose logs the file contains.
+	Container string `json:"container"`
+	// LogsEventID, if non-empty, is the ID of another Event from which the Job,
+	// and therefore its logs, were inherited.
+	LogsEventID string `json:"logsEventID,omitempty"`
+	// Source indicates the store from which the logs were retrieved. If empty,
+	// no logs could be found and the file is empty.
+	Source LogArchiveSource `json:"source,omitempty"`
+	// Lines is the number of lines of logs in the file.
+	Lines int `json:"lines"`
+}
+
+// logArchiveItem pairs a LogArchiveFile with what is needed to retrieve its
+// logs.
+type logArchiveItem struct {
+	file     LogArchiveFile
+	event    Event
+	selector LogsSelector
+	// missing indicates that the logs are known not to exist
+	missing bool
+}
+
+func (l *logsService) Archive(
+	ctx context.Context,
+	eventID string,
+) (io.ReadCloser, error) {
+	event, err := l.eventsStore.Get(ctx, eventID)
+	if err != nil {
+		return nil,
+			errors.Wrapf(err, "error retrieving event %q from store", eventID)
+	}
+
+	if err = l.authorizeLogs(ctx, event); err != nil {
+		return nil, err
+	}
+
+	project, err := l.projectsStore.Get(ctx, event.ProjectID)
+	if err != nil {
+		return nil,
+			errors.Wrapf(
+				err,
+				"error retrieving project %q from store",
+				event.ProjectID,
+			)
+	}
+
+	items, err := l.logArchiveItems(ctx, event)
+	if err != nil {
+		return nil, err
+	}
+
+	manifest := LogArchiveManifest{
+		EventID:   event.ID,
+		ProjectID: event.ProjectID,
+		Created:   time.Now().UTC(),
+	}
+	reader, writer := io.Pipe()
+	go func() {
+		writer.CloseWithError(
+			l.writeLogArchive(ctx, project, manifest, items, writer),
+		)
+	}()
+	return reader, nil
+}
+
+// logArchiveItems returns a logArchiveItem for every container of the provided
+// Event's Worker and of every attempt at every Job spawned by that Worker. Jobs
+// whose logs were inherited from another Event are resolved to that Event.
+func (l *logsService) logArchiveItems(
+	ctx context.Context,
+	event Event,
+) ([]logArchiveItem, error) {
+	items := []logArchiveItem{}
+	for _, selector := range logsSelectorsForEvent(event) {
+		if selector.Job == "" {
+			items = append(
+				items,
+				logArchiveItem{
+					file: LogArchiveFile{
+						Path: path.Join(
+							myk8s.LabelKeyWorker,
+							fmt.Sprintf("%s.log", selector.Container),
+						),
+						Container: selector.Container,
+					},
+					event:    event,
+					selector: selector,
+				},
+			)
+			continue
+		}
+		logsEvent := event
+		var missing bool
+		job, _ := event.Worker.Job(selector.Job)
+		if job.Status.LogsEventID != "" {
+			var err error
+			if logsEvent, err =
+				l.eventsStore.Get(ctx, job.Status.LogsEventID); err != nil {
+				if _, ok := errors.Cause(err).(*meta.ErrNotFound); !ok {
+					return nil, errors.Wrapf(
+						err,
+						"error retrieving logs for job %q",
+						job.Name,
+					)
+				}
+				// The inherited logs no longer exist
+				missing = true
+			}
+		}
+		// Include every attempt at the Job, not just the most recent one
+		for attempt := 1; attempt <= selector.Attempt; attempt++ {
+			attemptSelector := selector
+			attemptSelector.Attempt = attempt
+			items = append(
+				items,
+				logArchiveItem{
+					file: LogArchiveFile{
+						Path: path.Join(
+							"jobs",
+							job.Name,
+							fmt.Sprintf("attempt-%d", attempt),
+							fmt.Sprintf("%s.log", selector.Container),
+						),
+						Job:         job.Name,
+						Attempt:     attempt,
+						Container:   selector.Container,
+						LogsEventID: job.Status.LogsEventID,
+					},
+					event:    logsEvent,
+					selector: attemptSelector,
+					missing:  missing,
+				},
+			)
+		}
+	}
+	return items, nil
+}
+
+// writeLogArchive writes a gzipped tarball containing logs for each of the
+// provided logArchiveItems, followed by a manifest describing them, to the
+// provided io.Writer.
+func (l *logsService) writeLogArchive(
+	ctx context.Context,
+	project Project,
+	manifest LogArchiveManifest,
+	items []logArchiveItem,
+	w io.Writer,
+) error {
+	// Cancel any streams that are abandoned if something goes wrong
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	gzipWriter := gzip.NewWriter(w)
+	tarWriter := tar.NewWriter(gzipWriter)
+	writeFile := func(name string, size int64, content io.Reader) error {
+		if err := tarWriter.WriteHeader(
+			&tar.Header{
+				Name:    name,
+				Mode:    0644,
+				Size:    size,
+				ModTime: manifest.Created,
+			},
+		); err != nil {
+			return errors.Wrapf(err, "error writing header for %s", name)
+		}
+		_, err := io.Copy(tarWriter, content)
+		return errors.Wrapf(err, "error writing %s", name)
+	}
+	// Each file's size must be known before its content is written to the
+	// archive and a container's logs may be too large to hold in memory, so
+	// each file's content is first spooled to a temporary file.
+	writeItem := func(item *logArchiveItem) error {
+		spool, err := os.CreateTemp("", "brigade-log-archive-*")
+		if err != nil {
+			return errors.Wrapf(
+				err,
+				"error creating temporary file for %s",
+				item.file.Path,
+			)
+		}
+		defer os.Remove(spool.Name())
+		defer spool.Close()
+		if !item.missing {
+			bufferedSpool := bufio.NewWriter(spool)
+			if item.file.Source, item.file.Lines, err =
+				l.readArchiveLogs(ctx, project, *item, bufferedSpool); err != nil {
+				return err
+			}
+			if err = bufferedSpool.Flush(); err != nil {
+				return errors.Wrapf(err, "error spooling logs for %s", item.file.Path)
+			}
+		}
+		size, err := spool.Seek(0, io.SeekCurrent)
+		if err == nil {
+			_, err = spool.Seek(0, io.SeekStart)
+		}
+		if err != nil {
+			return errors.Wrapf(err, "error rewinding logs for %s", item.file.Path)
+		}
+		return writeFile(item.file.Path, size, spool)
+	}
+	manifest.Files = make([]LogArchiveFile, len(items))
+	for i := range items {
+		if err := writeItem(&items[i]); err != nil {
+			return err
+		}
+		manifest.Files[i] = items[i].file
+	}
+	manifestBytes, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "error marshaling log archive manifest")
+	}
+	if err = writeFile(
+		logArchiveManifestName,
+		int64(len(manifestBytes)),
+		bytes.NewReader(manifestBytes),
+	); err != nil {
+		return err
+	}
+	if err = tarWriter.Close(); err != nil {
+		return errors.Wrap(err, "error closing log archive")
+	}
+	return errors.Wrap(gzipWriter.Close(), "error closing log archive")
+}
+
+// readArchiveLogs writes the logs specified by the provided logArchiveItem to
+// the provided io.Writer, one line per log entry, each prefixed with the time
+// it was written if that is known. Logs are read from the cool logs store if
+// it has any and from the warm logs store otherwise. It returns the store the
+// logs were read from, or an empty string if no logs were found, along with
+// the number of lines written.
+func (l *logsService) readArchiveLogs(
+	ctx context.Context,
+	project Project,
+	item logArchiveItem,
+	w io.Writer,
+) (LogArchiveSource, int, error) {
+	stores := []struct {
+		source LogArchiveSource
+		store  LogsStore
+	}{
+		{
+			source: LogArchiveSourceCool,
+			store:  l.coolLogsStore,
+		},
+		{
+			source: LogArchiveSourceWarm,
+			store:  l.warmLogsStore,
+		},
+	}
+	for _, s := range stores {
+		logEntryCh, err := s.store.StreamLogs(
+			ctx,
+			project,
+			item.event,
+			item.selector,
+			LogStreamOptions{},
+		)
+		if err != nil {
+			if _, ok := errors.Cause(err).(*meta.ErrNotFound); ok {
+				continue
+			}
+			return "", 0,
+				errors.Wrapf(err, "error retrieving logs for %s", item.file.Path)
+		}
+		var lines int
+		for logEntry := range logEntryCh {
+			line := logEntry.Message
+			if logEntry.Time != nil {
+				line = fmt.Sprintf(
+					"%s %s",
+					logEntry.Time.UTC().Format(time.RFC3339Nano),
+					line,
+				)
+			}
+			if _, err = fmt.Fprintln(w, line); err != nil {
+				return "", 0,
+					errors.Wrapf(err, "error writing logs for %s", item.file.Path)
+			}
+			lines++
+		}
+		if lines > 0 {
+			return s.source, lines, nil
+		}
+	}
+	return "", 0, nil
+}
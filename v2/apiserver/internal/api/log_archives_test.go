@@ -0,0 +1,256 @@
+package api
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/brigadecore/brigade/v2/apiserver/internal/meta"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogsServiceArchive(t *testing.T) {
+	const testEventID = "123456789"
+	const testLogsEventID = "987654321"
+	testTime := time.Date(2021, time.December, 1, 12, 0, 0, 0, time.UTC)
+	testEvent := Event{
+		ObjectMeta: meta.ObjectMeta{
+			ID: testEventID,
+		},
+		ProjectID: "bluebook",
+		Worker: Worker{
+			Status: WorkerStatus{
+				Phase: WorkerPhaseSucceeded,
+			},
+			Jobs: []Job{
+				{
+					Name: "italian",
+					Status: &JobStatus{
+						Phase:   JobPhaseSucceeded,
+						Attempt: 2,
+					},
+				},
+				{
+					Name: "french",
+					Status: &JobStatus{
+						Phase:       JobPhaseSucceeded,
+						LogsEventID: testLogsEventID,
+					},
+				},
+				{
+					Name: "spanish",
+					Status: &JobStatus{
+						Phase:       JobPhaseSucceeded,
+						LogsEventID: "nonexistent",
+					},
+				},
+			},
+		},
+	}
+	testCases := []struct {
+		name       string
+		service    LogsService
+		assertions func(io.ReadCloser, error)
+	}{
+		{
+			name: "error retrieving event from store",
+			service: &logsService{
+				eventsStore: &mockEventsStore{
+					GetFn: func(context.Context, string) (Event, error) {
+						return Event{}, errors.New("something went wrong")
+					},
+				},
+			},
+			assertions: func(_ io.ReadCloser, err error) {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), "something went wrong")
+				require.Contains(t, err.Error(), "error retrieving event")
+			},
+		},
+		{
+			name: "unauthorized",
+			service: &logsService{
+				authorize:        neverAuthorize,
+				projectAuthorize: neverProjectAuthorize,
+				eventsStore: &mockEventsStore{
+					GetFn: func(context.Context, string) (Event, error) {
+						return testEvent, nil
+					},
+				},
+			},
+			assertions: func(_ io.ReadCloser, err error) {
+				require.Error(t, err)
+				require.IsType(t, &meta.ErrAuthorization{}, err)
+			},
+		},
+		{
+			name: "success",
+			service: &logsService{
+				projectAuthorize: alwaysProjectAuthorize,
+				projectsStore: &mockProjectsStore{
+					GetFn: func(context.Context, string) (Project, error) {
+						return Project{}, nil
+					},
+				},
+				eventsStore: &mockEventsStore{
+					GetFn: func(_ context.Context, id string) (Event, error) {
+						switch id {
+						case testEventID:
+							return testEvent, nil
+						case testLogsEventID:
+							return Event{
+								ObjectMeta: meta.ObjectMeta{
+									ID: testLogsEventID,
+								},
+							}, nil
+						}
+						return Event{}, &meta.ErrNotFound{}
+					},
+				},
+				coolLogsStore: &mockLogsStore{
+					StreamLogsFn: func(
+						_ context.Context,
+						_ Project,
+						event Event,
+						selector LogsSelector,
+						_ LogStreamOptions,
+					) (<-chan LogEntry, error) {
+						logCh := make(chan LogEntry, 1)
+						// Only the italian job's logs have been aggregated
+						if selector.Job == "italian" {
+							logCh <- LogEntry{
+								Time: &testTime,
+								Message: fmt.Sprintf(
+									"attempt %d from %s",
+									selector.Attempt,
+									event.ID,
+								),
+							}
+						}
+						close(logCh)
+						return logCh, nil
+					},
+				},
+				warmLogsStore: &mockLogsStore{
+					StreamLogsFn: func(
+						_ context.Context,
+						_ Project,
+						event Event,
+						selector LogsSelector,
+						_ LogStreamOptions,
+					) (<-chan LogEntry, error) {
+						if selector.Job == "italian" {
+							return nil, &meta.ErrNotFound{}
+						}
+						logCh := make(chan LogEntry, 1)
+						logCh <- LogEntry{
+							Message: fmt.Sprintf(
+								"%s from %s",
+								selector.Container,
+								event.ID,
+							),
+						}
+						close(logCh)
+						return logCh, nil
+					},
+				},
+			},
+			assertions: func(reader io.ReadCloser, err error) {
+				require.NoError(t, err)
+				defer reader.Close()
+				gzipReader, err := gzip.NewReader(reader)
+				require.NoError(t, err)
+				tarReader := tar.NewReader(gzipReader)
+				files := map[string]string{}
+				for {
+					header, err := tarReader.Next()
+					if err == io.EOF {
+						break
+					}
+					require.NoError(t, err)
+					contentBytes, err := io.ReadAll(tarReader)
+					require.NoError(t, err)
+					files[header.Name] = string(contentBytes)
+				}
+				manifestJSON := files[logArchiveManifestName]
+				delete(files, logArchiveManifestName)
+				require.Equal(
+					t,
+					map[string]string{
+						"worker/worker.log": "worker from 123456789\n",
+						"jobs/italian/attempt-1/italian.log": "2021-12-01T12:00:00Z " +
+							"attempt 1 from 123456789\n",
+						"jobs/italian/attempt-2/italian.log": "2021-12-01T12:00:00Z " +
+							"attempt 2 from 123456789\n",
+						"jobs/french/attempt-1/french.log":   "french from 987654321\n",
+						"jobs/spanish/attempt-1/spanish.log": "",
+					},
+					files,
+				)
+				manifest := struct {
+					Kind  string           `json:"kind"`
+					Files []LogArchiveFile `json:"files"`
+				}{}
+				require.NoError(t, json.Unmarshal([]byte(manifestJSON), &manifest))
+				require.Equal(t, "LogArchiveManifest", manifest.Kind)
+				require.Equal(
+					t,
+					[]LogArchiveFile{
+						{
+							Path:      "worker/worker.log",
+							Container: "worker",
+							Source:    LogArchiveSourceWarm,
+							Lines:     1,
+						},
+						{
+							Path:      "jobs/italian/attempt-1/italian.log",
+							Job:       "italian",
+							Attempt:   1,
+							Container: "italian",
+							Source:    LogArchiveSourceCool,
+							Lines:     1,
+						},
+						{
+							Path:      "jobs/italian/attempt-2/italian.log",
+							Job:       "italian",
+							Attempt:   2,
+							Container: "italian",
+							Source:    LogArchiveSourceCool,
+							Lines:     1,
+						},
+						{
+							Path:        "jobs/french/attempt-1/french.log",
+							Job:         "french",
+							Attempt:     1,
+							Container:   "french",
+							LogsEventID: testLogsEventID,
+							Source:      LogArchiveSourceWarm,
+							Lines:       1,
+						},
+						{
+							Path:        "jobs/spanish/attempt-1/spanish.log",
+							Job:         "spanish",
+							Attempt:     1,
+							Container:   "spanish",
+							LogsEventID: "nonexistent",
+						},
+					},
+					manifest.Files,
+				)
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.assertions(
+				testCase.service.Archive(context.Background(), testEventID),
+			)
+		})
+	}
+}
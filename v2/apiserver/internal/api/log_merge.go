@@ -0,0 +1,300 @@
+package api
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/brigadecore/brigade/v2/apiserver/internal/meta"
+	"github.com/pkg/errors"
+)
+
+const (
+	// logMergeWindow is how long a line of logs from one container may be held
+	// back while waiting to learn whether other containers have written lines
+	// earlier.
+	logMergeWindow = time.Second
+	// logSourcesPollInterval is how often a followed stream of logs from all of
+	// an Event's containers checks for containers that have started since the
+	// stream began.
+	logSourcesPollInterval = 5 * time.Second
+)
+
+// logSourceMessage is a message from one of several sources of logs whose
+// lines are to be merged.
+type logSourceMessage struct {
+	// source identifies the source of logs the message pertains to.
+	source int
+	// opened indicates that the source has been opened and that lines of logs
+	// from it should be awaited.
+	opened bool
+	// closed indicates that the source has been closed and no further lines of
+	// logs from it should be awaited.
+	closed bool
+	// logEntry is a line of logs from the source. It is only meaningful if
+	// neither opened nor closed is true.
+	logEntry LogEntry
+}
+
+// pendingLogEntry is a line of logs that has been received from a source but
+// not yet sent to the merged stream.
+type pendingLogEntry struct {
+	logEntry LogEntry
+	received time.Time
+}
+
+// time returns the time the line of logs was written or, if that is unknown,
+// the time it was received.
+func (p pendingLogEntry) time() time.Time {
+	if p.logEntry.Time != nil {
+		return *p.logEntry.Time
+	}
+	return p.received
+}
+
+// streamAll returns a channel over which logs from the provided Event's Worker
+// and from every container of every Job spawned by that Worker are streamed,
+// interleaved by the time each line was written.
+func (l *logsService) streamAll(
+	ctx context.Context,
+	event Event,
+	opts LogStreamOptions,
+) (<-chan LogEntry, error) {
+	project, err := l.projectsStore.Get(ctx, event.ProjectID)
+	if err != nil {
+		return nil,
+			errors.Wrapf(
+				err,
+				"error retrieving project %q from store",
+				event.ProjectID,
+			)
+	}
+	msgCh := make(chan logSourceMessage)
+	go l.streamAllSources(ctx, project, event, opts, msgCh)
+	return mergeLogEntries(ctx, msgCh, logMergeWindow), nil
+}
+
+// streamAllSources streams logs from every container of the provided Event's
+// Worker and Jobs over the provided channel. If the stream is being followed,
+// the Event is polled for containers that start later until its Worker reaches
+// a terminal phase. The channel is closed once logs from every container have
+// been streamed.
+func (l *logsService) streamAllSources(
+	ctx context.Context,
+	project Project,
+	event Event,
+	opts LogStreamOptions,
+	msgCh chan<- logSourceMessage,
+) {
+	defer close(msgCh)
+	wg := sync.WaitGroup{}
+	defer wg.Wait()
+	sources := map[LogsSelector]int{}
+	startSources := func(event Event) bool {
+		for _, selector := range logsSelectorsForEvent(event) {
+			if _, ok := sources[selector]; ok {
+				continue
+			}
+			source := len(sources)
+			sources[selector] = source
+			select {
+			case msgCh <- logSourceMessage{source: source, opened: true}:
+			case <-ctx.Done():
+				return false
+			}
+			wg.Add(1)
+			go func(selector LogsSelector) {
+				defer wg.Done()
+				l.streamSource(ctx, project, event, source, selector, opts, msgCh)
+			}(selector)
+		}
+		return true
+	}
+	if !startSources(event) || !opts.Follow {
+		return
+	}
+	ticker := time.NewTicker(logSourcesPollInterval)
+	defer ticker.Stop()
+	for !event.Worker.Status.Phase.IsTerminal() {
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+		latestEvent, err := l.eventsStore.Get(ctx, event.ID)
+		if err != nil {
+			log.Println(
+				errors.Wrapf(err, "error retrieving event %q from store", event.ID),
+			)
+			continue
+		}
+		event = latestEvent
+		if !startSources(event) {
+			return
+		}
+	}
+}
+
+// streamSource streams logs from the container specified by the provided
+// LogsSelector over the provided channel, tagging each line with the names of
+// the Job and container it belongs to.
+func (l *logsService) streamSource(
+	ctx context.Context,
+	project Project,
+	event Event,
+	source int,
+	selector LogsSelector,
+	opts LogStreamOptions,
+	msgCh chan<- logSourceMessage,
+) {
+	defer func() {
+		select {
+		case msgCh <- logSourceMessage{source: source, closed: true}:
+		case <-ctx.Done():
+		}
+	}()
+	// A Job's logs may have been inherited from a Job belonging to another Event
+	if job, ok := event.Worker.Job(selector.Job); ok &&
+		job.Status != nil &&
+		job.Status.LogsEventID != "" {
+		var err error
+		if event, err = l.eventsStore.Get(ctx, job.Status.LogsEventID); err != nil {
+			log.Println(
+				errors.Wrapf(
+					err,
+					"error retrieving logs for job %q",
+					selector.Job,
+				),
+			)
+			return
+		}
+	}
+	logCh, err := l.streamContainerLogs(ctx, project, event, selector, opts)
+	if err != nil {
+		// A container whose logs can't be found simply contributes no lines
+		if _, ok := errors.Cause(err).(*meta.ErrNotFound); !ok {
+			log.Println(
+				errors.Wrapf(
+					err,
+					"error streaming logs from container %q of event %q",
+					selector.Container,
+					event.ID,
+				),
+			)
+		}
+		return
+	}
+	for logEntry := range logCh {
+		logEntry.Job = selector.Job
+		logEntry.Container = selector.Container
+		select {
+		case msgCh <- logSourceMessage{source: source, logEntry: logEntry}:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// mergeLogEntries returns a channel over which lines of logs received from any
+// number of sources are sent, ordered by the time they were written. Because
+// sources send lines only as they become available, a line is held back until
+// every open source has sent a line or until the provided window has elapsed,
+// whichever comes first. The returned channel is closed once the provided
+// channel has been closed and every line has been sent.
+func mergeLogEntries(
+	ctx context.Context,
+	msgCh <-chan logSourceMessage,
+	window time.Duration,
+) <-chan LogEntry {
+	logCh := make(chan LogEntry)
+	go func() {
+		defer close(logCh)
+		openSources := map[int]struct{}{}
+		queues := map[int][]pendingLogEntry{}
+		ticker := time.NewTicker(window / 4)
+		defer ticker.Stop()
+		for {
+			select {
+			case msg, ok := <-msgCh:
+				if !ok {
+					// No further lines will be received from any source
+					msgCh = nil
+					openSources = map[int]struct{}{}
+					break
+				}
+				switch {
+				case msg.opened:
+					openSources[msg.source] = struct{}{}
+				case msg.closed:
+					delete(openSources, msg.source)
+				default:
+					queues[msg.source] = append(
+						queues[msg.source],
+						pendingLogEntry{
+							logEntry: msg.logEntry,
+							received: time.Now(),
+						},
+					)
+				}
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+			for {
+				source, ok := nextLogSource(openSources, queues, window)
+				if !ok {
+					break
+				}
+				select {
+				case logCh <- queues[source][0].logEntry:
+				case <-ctx.Done():
+					return
+				}
+				if queues[source] = queues[source][1:]; len(queues[source]) == 0 {
+					delete(queues, source)
+				}
+			}
+			if msgCh == nil {
+				return
+			}
+		}
+	}()
+	return logCh
+}
+
+// nextLogSource returns the source whose earliest pending line of logs should
+// be sent next, and true, if any line is ready to be sent. Otherwise, it
+// returns false.
+func nextLogSource(
+	openSources map[int]struct{},
+	queues map[int][]pendingLogEntry,
+	window time.Duration,
+) (int, bool) {
+	next := -1
+	for source, queue := range queues {
+		if next == -1 {
+			next = source
+			continue
+		}
+		t := queue[0].time()
+		nextT := queues[next][0].time()
+		if t.Before(nextT) || (t.Equal(nextT) && source < next) {
+			next = source
+		}
+	}
+	if next == -1 {
+		return 0, false
+	}
+	// A line that has been held back long enough is sent regardless of whether
+	// other sources may yet send earlier lines
+	if time.Since(queues[next][0].received) >= window {
+		return next, true
+	}
+	for source := range openSources {
+		if len(queues[source]) == 0 {
+			return 0, false
+		}
+	}
+	return next, true
+}
@@ -0,0 +1,171 @@
+package api
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergeLogEntries(t *testing.T) {
+	t0 := time.Date(2021, time.December, 1, 12, 0, 0, 0, time.UTC)
+	t1 := t0.Add(time.Second)
+	t2 := t0.Add(2 * time.Second)
+	t3 := t0.Add(3 * time.Second)
+
+	t.Run("lines are interleaved by time", func(t *testing.T) {
+		msgCh := make(chan logSourceMessage)
+		logCh := mergeLogEntries(context.Background(), msgCh, time.Hour)
+		go func() {
+			defer close(msgCh)
+			msgCh <- logSourceMessage{source: 0, opened: true}
+			msgCh <- logSourceMessage{source: 1, opened: true}
+			msgCh <- logSourceMessage{
+				source:   0,
+				logEntry: LogEntry{Time: &t1, Message: "b"},
+			}
+			msgCh <- logSourceMessage{
+				source:   0,
+				logEntry: LogEntry{Time: &t3, Message: "d"},
+			}
+			msgCh <- logSourceMessage{
+				source:   1,
+				logEntry: LogEntry{Time: &t0, Message: "a"},
+			}
+			msgCh <- logSourceMessage{
+				source:   1,
+				logEntry: LogEntry{Time: &t2, Message: "c"},
+			}
+			msgCh <- logSourceMessage{source: 1, closed: true}
+			msgCh <- logSourceMessage{source: 0, closed: true}
+		}()
+		messages := []string{}
+		for logEntry := range logCh {
+			messages = append(messages, logEntry.Message)
+		}
+		require.Equal(t, []string{"a", "b", "c", "d"}, messages)
+	})
+
+	t.Run("lines are not held back indefinitely", func(t *testing.T) {
+		msgCh := make(chan logSourceMessage)
+		logCh := mergeLogEntries(
+			context.Background(),
+			msgCh,
+			100*time.Millisecond,
+		)
+		defer close(msgCh)
+		msgCh <- logSourceMessage{source: 0, opened: true}
+		msgCh <- logSourceMessage{source: 1, opened: true}
+		msgCh <- logSourceMessage{
+			source:   0,
+			logEntry: LogEntry{Time: &t0, Message: "a"},
+		}
+		// Source 1 never sends anything, but the line from source 0 should be
+		// sent once the window has elapsed
+		select {
+		case logEntry := <-logCh:
+			require.Equal(t, "a", logEntry.Message)
+		case <-time.After(5 * time.Second):
+			require.Fail(t, "timed out waiting for merged line of logs")
+		}
+	})
+}
+
+func TestLogsServiceStreamAll(t *testing.T) {
+	const testEventID = "123456789"
+	t0 := time.Date(2021, time.December, 1, 12, 0, 0, 0, time.UTC)
+	t1 := t0.Add(time.Second)
+	t2 := t0.Add(2 * time.Second)
+	service := &logsService{
+		projectAuthorize: alwaysProjectAuthorize,
+		projectsStore: &mockProjectsStore{
+			GetFn: func(context.Context, string) (Project, error) {
+				return Project{}, nil
+			},
+		},
+		eventsStore: &mockEventsStore{
+			GetFn: func(context.Context, string) (Event, error) {
+				return Event{
+					Worker: Worker{
+						Status: WorkerStatus{
+							Phase: WorkerPhaseRunning,
+						},
+						Jobs: []Job{
+							{
+								Name: "italian",
+								Spec: JobSpec{
+									SidecarContainers: map[string]JobContainerSpec{
+										"helper": {},
+									},
+								},
+								Status: &JobStatus{
+									Phase: JobPhaseRunning,
+								},
+							},
+							{
+								Name: "french",
+								Status: &JobStatus{
+									Phase: JobPhasePending,
+								},
+							},
+						},
+					},
+				}, nil
+			},
+		},
+		warmLogsStore: &mockLogsStore{
+			StreamLogsFn: func(
+				_ context.Context,
+				_ Project,
+				_ Event,
+				selector LogsSelector,
+				_ LogStreamOptions,
+			) (<-chan LogEntry, error) {
+				logEntries := map[string]LogEntry{
+					"worker":  {Time: &t0, Message: "worker"},
+					"italian": {Time: &t2, Message: "italian"},
+					"helper":  {Time: &t1, Message: "helper"},
+				}
+				logCh := make(chan LogEntry, 1)
+				logCh <- logEntries[selector.Container]
+				close(logCh)
+				return logCh, nil
+			},
+		},
+	}
+	logCh, err := service.Stream(
+		context.Background(),
+		testEventID,
+		LogsSelector{All: true},
+		LogStreamOptions{},
+	)
+	require.NoError(t, err)
+	logEntries := []LogEntry{}
+	for logEntry := range logCh {
+		logEntries = append(logEntries, logEntry)
+	}
+	require.Equal(
+		t,
+		[]LogEntry{
+			{
+				Time:      &t0,
+				Message:   "worker",
+				Container: "worker",
+			},
+			{
+				Time:      &t1,
+				Message:   "helper",
+				Job:       "italian",
+				Container: "helper",
+			},
+			{
+				Time:      &t2,
+				Message:   "italian",
+				Job:       "italian",
+				Container: "italian",
+			},
+		},
+		logEntries,
+	)
+}
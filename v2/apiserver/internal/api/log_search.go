@@ -0,0 +1,471 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"regexp/syntax"
+
+	"github.com/brigadecore/brigade/v2/apiserver/internal/meta"
+	myk8s "github.com/brigadecore/brigade/v2/internal/kubernetes"
+	"github.com/pkg/errors"
+)
+
+const (
+	// defaultLogSearchLimit is the maximum number of matching lines of logs
+	// returned by a search when no limit is specified.
+	defaultLogSearchLimit = 100
+	// maxLogSearchLimit is the most matching lines of logs a single search may
+	// return.
+	maxLogSearchLimit = 1000
+	// maxLogSearchContextLines is the most lines of logs a search may return
+	// before and after each matching line.
+	maxLogSearchContextLines = 10
+	// defaultLogSearchEvents is the number of a Project's most recent Events
+	// whose logs are searched when no number is specified.
+	defaultLogSearchEvents = 10
+	// maxLogSearchEvents is the most of a Project's recent Events whose logs a
+	// single search may cover.
+	maxLogSearchEvents = 100
+)
+
+// LogSearchCriteria represents criteria for selecting lines of logs in a
+// search.
+type LogSearchCriteria struct {
+	// Pattern is a substring or, if Regex is true, a regular expression that
+	// selected lines of logs must contain or match, respectively.
+	Pattern string
+	// Regex indicates whether Pattern is a regular expression.
+	Regex bool
+	// IgnoreCase indicates whether Pattern should be matched without regard to
+	// case.
+	IgnoreCase bool
+}
+
+// LogSearchOptions represents useful options for searching logs.
+type LogSearchOptions struct {
+	// ContextLines specifies how many lines of logs to return before and after
+	// each matching line.
+	ContextLines int
+	// Limit specifies the most matching lines of logs to return. If not
+	// specified, this defaults to 100.
+	Limit int
+	// Events specifies how many of a Project's most recent Events to search when
+	// searching a Project's logs. If not specified, this defaults to 10.
+	Events int
+}
+
+// LogSearchMatch represents a single line of logs matched by a search, along
+// with the surrounding lines and details of where it was found.
+type LogSearchMatch struct {
+	// EventID is the ID of the Event whose logs the line belongs to.
+	EventID string `json:"eventID"`
+	// Job is the name of the Job whose logs the line belongs to. If empty, the
+	// line belongs to the Event's Worker's logs.
+	Job string `json:"job,omitempty"`
+	// Attempt is the attempt at the Job whose logs the line belongs to.
+	Attempt int `json:"attempt,omitempty"`
+	// Container is the name of the container whose logs the line belongs to.
+	Container string `json:"container"`
+	// LogEntry is the matching line.
+	LogEntry LogEntry `json:"logEntry"`
+	// Before enumerates lines of logs written by the same container immediately
+	// before the matching line.
+	Before []LogEntry `json:"before,omitempty"`
+	// After enumerates lines of logs written by the same container immediately
+	// after the matching line.
+	After []LogEntry `json:"after,omitempty"`
+}
+
+// logSearchMatcher returns a regular expression that matches lines of logs
+// selected by the provided LogSearchCriteria. Its syntax is common to Go and
+// to the log stores that support searching. If the criteria are invalid, a
+// *meta.ErrBadRequest error is returned.
+func logSearchMatcher(criteria LogSearchCriteria) (*regexp.Regexp, error) {
+	if criteria.Pattern == "" {
+		return nil, &meta.ErrBadRequest{
+			Reason: "A search pattern must be specified.",
+		}
+	}
+	expression := criteria.Pattern
+	if !criteria.Regex {
+		expression = regexp.QuoteMeta(expression)
+	}
+	if criteria.IgnoreCase {
+		expression = fmt.Sprintf("(?i)%s", expression)
+	}
+	matcher, err := regexp.Compile(expression)
+	if err != nil {
+		return nil, &meta.ErrBadRequest{
+			Reason:  "Invalid search pattern.",
+			Details: []string{err.Error()},
+		}
+	}
+	// Go's regular expressions are immune to catastrophic backtracking, but
+	// those of the log stores that search natively may not be. Patterns that
+	// repeat something that is itself repeated, like "(a+)+", are the usual
+	// culprits, so those are refused.
+	parsed, err := syntax.Parse(expression, syntax.Perl)
+	if err != nil {
+		return nil, &meta.ErrBadRequest{
+			Reason:  "Invalid search pattern.",
+			Details: []string{err.Error()},
+		}
+	}
+	if hasNestedRepetition(parsed, false) {
+		return nil, &meta.ErrBadRequest{
+			Reason: "Invalid search pattern.",
+			Details: []string{
+				"repetition of an expression that is itself repeated is not " +
+					"supported",
+			},
+		}
+	}
+	return matcher, nil
+}
+
+// hasNestedRepetition returns true if any repeated part of the provided parsed
+// regular expression contains a repeated part of its own. The repeated
+// argument indicates whether the provided expression is itself within a
+// repeated part.
+func hasNestedRepetition(re *syntax.Regexp, repeated bool) bool {
+	switch re.Op {
+	case syntax.OpStar, syntax.OpPlus:
+		if repeated {
+			return true
+		}
+		repeated = true
+	case syntax.OpRepeat:
+		if re.Max != 1 {
+			if repeated {
+				return true
+			}
+			repeated = true
+		}
+	}
+	for _, sub := range re.Sub {
+		if hasNestedRepetition(sub, repeated) {
+			return true
+		}
+	}
+	return false
+}
+
+// validateLogSearchOptions applies defaults to the provided LogSearchOptions
+// and returns a *meta.ErrBadRequest error if any of them are out of range.
+func validateLogSearchOptions(opts *LogSearchOptions) error {
+	if opts.Limit == 0 {
+		opts.Limit = defaultLogSearchLimit
+	}
+	if opts.Events == 0 {
+		opts.Events = defaultLogSearchEvents
+	}
+	details := []string{}
+	if opts.ContextLines < 0 || opts.ContextLines > maxLogSearchContextLines {
+		details = append(
+			details,
+			fmt.Sprintf(
+				"context lines must be between 0 and %d",
+				maxLogSearchContextLines,
+			),
+		)
+	}
+	if opts.Limit < 1 || opts.Limit > maxLogSearchLimit {
+		details = append(
+			details,
+			fmt.Sprintf("limit must be between 1 and %d", maxLogSearchLimit),
+		)
+	}
+	if opts.Events < 1 || opts.Events > maxLogSearchEvents {
+		details = append(
+			details,
+			fmt.Sprintf("events must be between 1 and %d", maxLogSearchEvents),
+		)
+	}
+	if len(details) > 0 {
+		return &meta.ErrBadRequest{
+			Reason:  "Invalid log search options.",
+			Details: details,
+		}
+	}
+	return nil
+}
+
+func (l *logsService) Search(
+	ctx context.Context,
+	eventID string,
+	criteria LogSearchCriteria,
+	opts LogSearchOptions,
+) (meta.List[LogSearchMatch], error) {
+	matches := meta.List[LogSearchMatch]{}
+	matcher, err := logSearchMatcher(criteria)
+	if err != nil {
+		return matches, err
+	}
+	if err = validateLogSearchOptions(&opts); err != nil {
+		return matches, err
+	}
+
+	event, err := l.eventsStore.Get(ctx, eventID)
+	if err != nil {
+		return matches,
+			errors.Wrapf(err, "error retrieving event %q from store", eventID)
+	}
+	if err = l.authorizeLogs(ctx, event); err != nil {
+		return matches, err
+	}
+
+	project, err := l.projectsStore.Get(ctx, event.ProjectID)
+	if err != nil {
+		return matches, errors.Wrapf(
+			err,
+			"error retrieving project %q from store",
+			event.ProjectID,
+		)
+	}
+
+	matches.Items, err = l.searchEventLogs(ctx, project, event, matcher, opts)
+	return matches, err
+}
+
+func (l *logsService) SearchProject(
+	ctx context.Context,
+	projectID string,
+	criteria LogSearchCriteria,
+	opts LogSearchOptions,
+) (meta.List[LogSearchMatch], error) {
+	matches := meta.List[LogSearchMatch]{}
+	matcher, err := logSearchMatcher(criteria)
+	if err != nil {
+		return matches, err
+	}
+	if err = validateLogSearchOptions(&opts); err != nil {
+		return matches, err
+	}
+
+	// As with streaming, we require the principal to be a project user in order
+	// to search logs.
+	if err = l.projectAuthorize(ctx, projectID, RoleProjectUser); err != nil {
+		return matches, err
+	}
+
+	project, err := l.projectsStore.Get(ctx, projectID)
+	if err != nil {
+		return matches,
+			errors.Wrapf(err, "error retrieving project %q from store", projectID)
+	}
+
+	events, err := l.eventsStore.List(
+		ctx,
+		EventsSelector{
+			ProjectID:    projectID,
+			WorkerPhases: WorkerPhasesAll(),
+		},
+		meta.ListOptions{
+			Limit: int64(opts.Events),
+		},
+	)
+	if err != nil {
+		return matches, errors.Wrapf(
+			err,
+			"error retrieving project %q events from store",
+			projectID,
+		)
+	}
+
+	matches.Items = []LogSearchMatch{}
+	for _, event := range events.Items {
+		eventOpts := opts
+		eventOpts.Limit = opts.Limit - len(matches.Items)
+		eventMatches, err :=
+			l.searchEventLogs(ctx, project, event, matcher, eventOpts)
+		if err != nil {
+			return matches, err
+		}
+		matches.Items = append(matches.Items, eventMatches...)
+		if len(matches.Items) >= opts.Limit {
+			break
+		}
+	}
+	return matches, nil
+}
+
+// searchEventLogs searches the provided Event's logs using the cool logs store
+// and falls back to searching logs still available from the warm logs store
+// for any container in which the former finds no matches. This accounts for
+// logs that have not yet been aggregated in the cool logs store.
+func (l *logsService) searchEventLogs(
+	ctx context.Context,
+	project Project,
+	event Event,
+	matcher *regexp.Regexp,
+	opts LogSearchOptions,
+) ([]LogSearchMatch, error) {
+	matches, err := l.coolLogsStore.SearchLogs(ctx, event, matcher.String(), opts)
+	if err != nil {
+		return nil, errors.Wrapf(
+			err,
+			"error searching event %q logs in cool logs store",
+			event.ID,
+		)
+	}
+	if len(matches) >= opts.Limit {
+		return matches, nil
+	}
+	// Containers in which the cool logs store found matches evidently have had
+	// their logs aggregated already
+	searched := map[LogsSelector]struct{}{}
+	for _, match := range matches {
+		searched[LogsSelector{
+			Job:       match.Job,
+			Container: match.Container,
+			Attempt:   match.Attempt,
+		}] = struct{}{}
+	}
+	for _, selector := range logsSelectorsForEvent(event) {
+		if _, ok := searched[selector]; ok {
+			continue
+		}
+		// Cancel the stream as soon as we're done with it
+		streamCtx, cancel := context.WithCancel(ctx)
+		logEntryCh, err := l.warmLogsStore.StreamLogs(
+			streamCtx,
+			project,
+			event,
+			selector,
+			LogStreamOptions{},
+		)
+		if err != nil {
+			cancel()
+			// The underlying pod no longer exists
+			if _, ok := errors.Cause(err).(*meta.ErrNotFound); ok {
+				continue
+			}
+			return nil, errors.Wrapf(
+				err,
+				"error searching event %q logs in warm logs store",
+				event.ID,
+			)
+		}
+		matches = append(
+			matches,
+			SearchLogEntries(
+				logEntryCh,
+				matcher,
+				opts.ContextLines,
+				opts.Limit-len(matches),
+				LogSearchMatch{
+					EventID:   event.ID,
+					Job:       selector.Job,
+					Attempt:   selector.Attempt,
+					Container: selector.Container,
+				},
+			)...,
+		)
+		cancel()
+		if len(matches) >= opts.Limit {
+			break
+		}
+	}
+	return matches, nil
+}
+
+// logsSelectorsForEvent returns LogsSelectors for every container belonging
+// to the provided Event's Worker and to the current attempts at each of its
+// Jobs that have moved past the PENDING and STARTING phases.
+func logsSelectorsForEvent(event Event) []LogsSelector {
+	selectors := []LogsSelector{}
+	if event.Worker.Status.Phase == WorkerPhasePending ||
+		event.Worker.Status.Phase == WorkerPhaseStarting {
+		return selectors
+	}
+	if event.Worker.Spec.Git != nil {
+		selectors = append(selectors, LogsSelector{Container: "vcs"})
+	}
+	selectors = append(
+		selectors,
+		LogsSelector{Container: myk8s.LabelKeyWorker},
+	)
+	for _, job := range event.Worker.Jobs {
+		if job.Status == nil ||
+			job.Status.Phase == JobPhasePending ||
+			job.Status.Phase == JobPhaseStarting {
+			continue
+		}
+		attempt := job.Status.currentAttempt()
+		usesSource := job.Spec.PrimaryContainer.SourceMountPath != ""
+		for _, sidecarContainer := range job.Spec.SidecarContainers {
+			if sidecarContainer.SourceMountPath != "" {
+				usesSource = true
+			}
+		}
+		if usesSource {
+			selectors = append(
+				selectors,
+				LogsSelector{Job: job.Name, Container: "vcs", Attempt: attempt},
+			)
+		}
+		selectors = append(
+			selectors,
+			LogsSelector{Job: job.Name, Container: job.Name, Attempt: attempt},
+		)
+		for containerName := range job.Spec.SidecarContainers {
+			selectors = append(
+				selectors,
+				LogsSelector{Job: job.Name, Container: containerName, Attempt: attempt},
+			)
+		}
+	}
+	return selectors
+}
+
+// SearchLogEntries returns up to limit LogSearchMatches for lines of logs
+// received over the provided channel that match the provided regular
+// expression. Each match is based on the provided LogSearchMatch and includes
+// up to contextLines lines before and after the matching line. Callers should
+// cancel the stream once this function returns, since it returns without
+// draining the channel once the limit is reached. This is exported for the
+// benefit of cool logs stores that cannot search logs natively.
+func SearchLogEntries(
+	logEntryCh <-chan LogEntry,
+	matcher *regexp.Regexp,
+	contextLines int,
+	limit int,
+	base LogSearchMatch,
+) []LogSearchMatch {
+	matches := []LogSearchMatch{}
+	// Indices of matches still awaiting lines after the matching line
+	pending := []int{}
+	before := []LogEntry{}
+	for logEntry := range logEntryCh {
+		stillPending := pending[:0]
+		for _, i := range pending {
+			matches[i].After = append(matches[i].After, logEntry)
+			if len(matches[i].After) < contextLines {
+				stillPending = append(stillPending, i)
+			}
+		}
+		pending = stillPending
+		if len(matches) < limit && matcher.MatchString(logEntry.Message) {
+			match := base
+			match.LogEntry = logEntry
+			if len(before) > 0 {
+				match.Before = append([]LogEntry{}, before...)
+			}
+			matches = append(matches, match)
+			if contextLines > 0 {
+				pending = append(pending, len(matches)-1)
+			}
+		}
+		if len(matches) >= limit && len(pending) == 0 {
+			break
+		}
+		if contextLines > 0 {
+			before = append(before, logEntry)
+			if len(before) > contextLines {
+				before = before[1:]
+			}
+		}
+	}
+	return matches
+}
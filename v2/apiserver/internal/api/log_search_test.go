@@ -0,0 +1,727 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/brigadecore/brigade/v2/apiserver/internal/meta"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogSearchMatcher(t *testing.T) {
+	testCases := []struct {
+		name       string
+		criteria   LogSearchCriteria
+		assertions func(*regexp.Regexp, error)
+	}{
+		{
+			name:     "no pattern",
+			criteria: LogSearchCriteria{},
+			assertions: func(_ *regexp.Regexp, err error) {
+				require.Error(t, err)
+				require.IsType(t, &meta.ErrBadRequest{}, err)
+			},
+		},
+		{
+			name: "substring",
+			criteria: LogSearchCriteria{
+				Pattern: "expected 1.0 (got",
+			},
+			assertions: func(matcher *regexp.Regexp, err error) {
+				require.NoError(t, err)
+				require.True(t, matcher.MatchString("FAIL: expected 1.0 (got 2.0)"))
+				require.False(t, matcher.MatchString("FAIL: expected 100 (got 2.0)"))
+			},
+		},
+		{
+			name: "substring ignoring case",
+			criteria: LogSearchCriteria{
+				Pattern:    "fail",
+				IgnoreCase: true,
+			},
+			assertions: func(matcher *regexp.Regexp, err error) {
+				require.NoError(t, err)
+				require.True(t, matcher.MatchString("--- FAIL: TestFoo"))
+			},
+		},
+		{
+			name: "invalid regex",
+			criteria: LogSearchCriteria{
+				Pattern: "(FAIL",
+				Regex:   true,
+			},
+			assertions: func(_ *regexp.Regexp, err error) {
+				require.Error(t, err)
+				require.IsType(t, &meta.ErrBadRequest{}, err)
+				require.Equal(
+					t,
+					"Invalid search pattern.",
+					err.(*meta.ErrBadRequest).Reason,
+				)
+			},
+		},
+		{
+			name: "regex",
+			criteria: LogSearchCriteria{
+				Pattern: `^--- FAIL: Test\w+`,
+				Regex:   true,
+			},
+			assertions: func(matcher *regexp.Regexp, err error) {
+				require.NoError(t, err)
+				require.True(t, matcher.MatchString("--- FAIL: TestFoo (0.00s)"))
+				require.False(t, matcher.MatchString("--- PASS: TestFoo (0.00s)"))
+			},
+		},
+		{
+			name: "regex with nested repetition",
+			criteria: LogSearchCriteria{
+				Pattern: `^(\w+\s?)*$`,
+				Regex:   true,
+			},
+			assertions: func(_ *regexp.Regexp, err error) {
+				require.Error(t, err)
+				require.IsType(t, &meta.ErrBadRequest{}, err)
+				require.Contains(
+					t,
+					err.(*meta.ErrBadRequest).Details[0],
+					"itself repeated",
+				)
+			},
+		},
+		{
+			name: "regex with sequential repetition",
+			criteria: LogSearchCriteria{
+				Pattern: `FAIL: \w+ \(\d+(\.\d{1,2})?s\)`,
+				Regex:   true,
+			},
+			assertions: func(matcher *regexp.Regexp, err error) {
+				require.NoError(t, err)
+				require.True(t, matcher.MatchString("FAIL: TestFoo (0.05s)"))
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.assertions(logSearchMatcher(testCase.criteria))
+		})
+	}
+}
+
+func TestValidateLogSearchOptions(t *testing.T) {
+	opts := LogSearchOptions{}
+	require.NoError(t, validateLogSearchOptions(&opts))
+	require.Equal(
+		t,
+		LogSearchOptions{
+			Limit:  defaultLogSearchLimit,
+			Events: defaultLogSearchEvents,
+		},
+		opts,
+	)
+	opts = LogSearchOptions{
+		ContextLines: -1,
+		Limit:        maxLogSearchLimit + 1,
+		Events:       -1,
+	}
+	err := validateLogSearchOptions(&opts)
+	require.Error(t, err)
+	require.IsType(t, &meta.ErrBadRequest{}, err)
+	require.Len(t, err.(*meta.ErrBadRequest).Details, 3)
+}
+
+func TestSearchLogEntries(t *testing.T) {
+	testLogEntries := []LogEntry{
+		{Message: "one"},
+		{Message: "two"},
+		{Message: "FAIL three"},
+		{Message: "four"},
+		{Message: "FAIL five"},
+		{Message: "six"},
+		{Message: "seven"},
+	}
+	testBase := LogSearchMatch{
+		EventID:   "123456789",
+		Job:       "italian",
+		Attempt:   1,
+		Container: "italian",
+	}
+	testCases := []struct {
+		name            string
+		contextLines    int
+		limit           int
+		expectedMatches []LogSearchMatch
+	}{
+		{
+			name:         "no context",
+			contextLines: 0,
+			limit:        10,
+			expectedMatches: []LogSearchMatch{
+				{
+					EventID:   "123456789",
+					Job:       "italian",
+					Attempt:   1,
+					Container: "italian",
+					LogEntry:  LogEntry{Message: "FAIL three"},
+				},
+				{
+					EventID:   "123456789",
+					Job:       "italian",
+					Attempt:   1,
+					Container: "italian",
+					LogEntry:  LogEntry{Message: "FAIL five"},
+				},
+			},
+		},
+		{
+			name:         "with context",
+			contextLines: 2,
+			limit:        10,
+			expectedMatches: []LogSearchMatch{
+				{
+					EventID:   "123456789",
+					Job:       "italian",
+					Attempt:   1,
+					Container: "italian",
+					LogEntry:  LogEntry{Message: "FAIL three"},
+					Before: []LogEntry{
+						{Message: "one"},
+						{Message: "two"},
+					},
+					After: []LogEntry{
+						{Message: "four"},
+						{Message: "FAIL five"},
+					},
+				},
+				{
+					EventID:   "123456789",
+					Job:       "italian",
+					Attempt:   1,
+					Container: "italian",
+					LogEntry:  LogEntry{Message: "FAIL five"},
+					Before: []LogEntry{
+						{Message: "FAIL three"},
+						{Message: "four"},
+					},
+					After: []LogEntry{
+						{Message: "six"},
+						{Message: "seven"},
+					},
+				},
+			},
+		},
+		{
+			name:         "limited",
+			contextLines: 1,
+			limit:        1,
+			expectedMatches: []LogSearchMatch{
+				{
+					EventID:   "123456789",
+					Job:       "italian",
+					Attempt:   1,
+					Container: "italian",
+					LogEntry:  LogEntry{Message: "FAIL three"},
+					Before: []LogEntry{
+						{Message: "two"},
+					},
+					After: []LogEntry{
+						{Message: "four"},
+					},
+				},
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			logEntryCh := make(chan LogEntry, len(testLogEntries))
+			for _, logEntry := range testLogEntries {
+				logEntryCh <- logEntry
+			}
+			close(logEntryCh)
+			require.Equal(
+				t,
+				testCase.expectedMatches,
+				SearchLogEntries(
+					logEntryCh,
+					regexp.MustCompile("FAIL"),
+					testCase.contextLines,
+					testCase.limit,
+					testBase,
+				),
+			)
+		})
+	}
+}
+
+func TestLogsSelectorsForEvent(t *testing.T) {
+	testCases := []struct {
+		name              string
+		event             Event
+		expectedSelectors []LogsSelector
+	}{
+		{
+			name: "worker pending",
+			event: Event{
+				Worker: Worker{
+					Status: WorkerStatus{
+						Phase: WorkerPhasePending,
+					},
+				},
+			},
+			expectedSelectors: []LogsSelector{},
+		},
+		{
+			name: "worker and jobs",
+			event: Event{
+				Worker: Worker{
+					Spec: WorkerSpec{
+						Git: &GitConfig{},
+					},
+					Status: WorkerStatus{
+						Phase: WorkerPhaseRunning,
+					},
+					Jobs: []Job{
+						{
+							Name: "italian",
+							Spec: JobSpec{
+								PrimaryContainer: JobContainerSpec{
+									SourceMountPath: "/src",
+								},
+								SidecarContainers: map[string]JobContainerSpec{
+									"helper": {},
+								},
+							},
+							Status: &JobStatus{
+								Phase:   JobPhaseFailed,
+								Attempt: 2,
+							},
+						},
+						{
+							Name: "french",
+							Status: &JobStatus{
+								Phase: JobPhasePending,
+							},
+						},
+					},
+				},
+			},
+			expectedSelectors: []LogsSelector{
+				{Container: "vcs"},
+				{Container: "worker"},
+				{Job: "italian", Container: "vcs", Attempt: 2},
+				{Job: "italian", Container: "italian", Attempt: 2},
+				{Job: "italian", Container: "helper", Attempt: 2},
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			require.Equal(
+				t,
+				testCase.expectedSelectors,
+				logsSelectorsForEvent(testCase.event),
+			)
+		})
+	}
+}
+
+func TestLogsServiceSearch(t *testing.T) {
+	const testEventID = "123456789"
+	testCriteria := LogSearchCriteria{
+		Pattern: "FAIL",
+	}
+	testEvent := Event{
+		ObjectMeta: meta.ObjectMeta{
+			ID: testEventID,
+		},
+		ProjectID: "italian",
+		Worker: Worker{
+			Status: WorkerStatus{
+				Phase: WorkerPhaseRunning,
+			},
+		},
+	}
+	testCases := []struct {
+		name       string
+		criteria   LogSearchCriteria
+		service    LogsService
+		assertions func(meta.List[LogSearchMatch], error)
+	}{
+		{
+			name:     "invalid criteria",
+			criteria: LogSearchCriteria{},
+			service:  &logsService{},
+			assertions: func(_ meta.List[LogSearchMatch], err error) {
+				require.Error(t, err)
+				require.IsType(t, &meta.ErrBadRequest{}, err)
+			},
+		},
+		{
+			name:     "error retrieving event from store",
+			criteria: testCriteria,
+			service: &logsService{
+				eventsStore: &mockEventsStore{
+					GetFn: func(context.Context, string) (Event, error) {
+						return Event{}, errors.New("something went wrong")
+					},
+				},
+			},
+			assertions: func(_ meta.List[LogSearchMatch], err error) {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), "something went wrong")
+				require.Contains(t, err.Error(), "error retrieving event")
+			},
+		},
+		{
+			name:     "unauthorized",
+			criteria: testCriteria,
+			service: &logsService{
+				authorize:        neverAuthorize,
+				projectAuthorize: neverProjectAuthorize,
+				eventsStore: &mockEventsStore{
+					GetFn: func(context.Context, string) (Event, error) {
+						return testEvent, nil
+					},
+				},
+			},
+			assertions: func(_ meta.List[LogSearchMatch], err error) {
+				require.Error(t, err)
+				require.IsType(t, &meta.ErrAuthorization{}, err)
+			},
+		},
+		{
+			name:     "error searching cool logs store",
+			criteria: testCriteria,
+			service: &logsService{
+				projectAuthorize: alwaysProjectAuthorize,
+				eventsStore: &mockEventsStore{
+					GetFn: func(context.Context, string) (Event, error) {
+						return testEvent, nil
+					},
+				},
+				projectsStore: &mockProjectsStore{
+					GetFn: func(context.Context, string) (Project, error) {
+						return Project{}, nil
+					},
+				},
+				coolLogsStore: &mockLogsStore{
+					SearchLogsFn: func(
+						context.Context,
+						Event,
+						string,
+						LogSearchOptions,
+					) ([]LogSearchMatch, error) {
+						return nil, errors.New("something went wrong")
+					},
+				},
+			},
+			assertions: func(_ meta.List[LogSearchMatch], err error) {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), "something went wrong")
+				require.Contains(t, err.Error(), "error searching event")
+			},
+		},
+		{
+			name:     "cool logs store finds matches",
+			criteria: testCriteria,
+			service: &logsService{
+				projectAuthorize: alwaysProjectAuthorize,
+				eventsStore: &mockEventsStore{
+					GetFn: func(context.Context, string) (Event, error) {
+						return testEvent, nil
+					},
+				},
+				projectsStore: &mockProjectsStore{
+					GetFn: func(context.Context, string) (Project, error) {
+						return Project{}, nil
+					},
+				},
+				coolLogsStore: &mockLogsStore{
+					SearchLogsFn: func(
+						_ context.Context,
+						_ Event,
+						expression string,
+						opts LogSearchOptions,
+					) ([]LogSearchMatch, error) {
+						require.Equal(t, "FAIL", expression)
+						require.Equal(t, defaultLogSearchLimit, opts.Limit)
+						return []LogSearchMatch{
+							{
+								EventID:   testEventID,
+								Container: "worker",
+								LogEntry:  LogEntry{Message: "FAIL"},
+							},
+						}, nil
+					},
+				},
+			},
+			assertions: func(matches meta.List[LogSearchMatch], err error) {
+				require.NoError(t, err)
+				require.Len(t, matches.Items, 1)
+			},
+		},
+		{
+			name:     "falls back to warm logs store per container",
+			criteria: testCriteria,
+			service: &logsService{
+				projectAuthorize: alwaysProjectAuthorize,
+				eventsStore: &mockEventsStore{
+					GetFn: func(context.Context, string) (Event, error) {
+						event := testEvent
+						event.Worker.Jobs = []Job{
+							{
+								Name:   "italian",
+								Status: &JobStatus{Phase: JobPhaseRunning},
+							},
+						}
+						return event, nil
+					},
+				},
+				projectsStore: &mockProjectsStore{
+					GetFn: func(context.Context, string) (Project, error) {
+						return Project{}, nil
+					},
+				},
+				coolLogsStore: &mockLogsStore{
+					SearchLogsFn: func(
+						context.Context,
+						Event,
+						string,
+						LogSearchOptions,
+					) ([]LogSearchMatch, error) {
+						return []LogSearchMatch{
+							{
+								EventID:   testEventID,
+								Container: "worker",
+								LogEntry:  LogEntry{Message: "FAIL (cool)"},
+							},
+						}, nil
+					},
+				},
+				warmLogsStore: &mockLogsStore{
+					StreamLogsFn: func(
+						_ context.Context,
+						_ Project,
+						_ Event,
+						selector LogsSelector,
+						_ LogStreamOptions,
+					) (<-chan LogEntry, error) {
+						// The Worker's logs were already found in the cool logs store
+						require.Equal(t, "italian", selector.Job)
+						logEntryCh := make(chan LogEntry, 1)
+						logEntryCh <- LogEntry{Message: "FAIL (warm)"}
+						close(logEntryCh)
+						return logEntryCh, nil
+					},
+				},
+			},
+			assertions: func(matches meta.List[LogSearchMatch], err error) {
+				require.NoError(t, err)
+				require.Equal(
+					t,
+					[]LogSearchMatch{
+						{
+							EventID:   testEventID,
+							Container: "worker",
+							LogEntry:  LogEntry{Message: "FAIL (cool)"},
+						},
+						{
+							EventID:   testEventID,
+							Job:       "italian",
+							Attempt:   1,
+							Container: "italian",
+							LogEntry:  LogEntry{Message: "FAIL (warm)"},
+						},
+					},
+					matches.Items,
+				)
+			},
+		},
+		{
+			name:     "falls back to warm logs store",
+			criteria: testCriteria,
+			service: &logsService{
+				projectAuthorize: alwaysProjectAuthorize,
+				eventsStore: &mockEventsStore{
+					GetFn: func(context.Context, string) (Event, error) {
+						return testEvent, nil
+					},
+				},
+				projectsStore: &mockProjectsStore{
+					GetFn: func(context.Context, string) (Project, error) {
+						return Project{}, nil
+					},
+				},
+				coolLogsStore: &mockLogsStore{
+					SearchLogsFn: func(
+						context.Context,
+						Event,
+						string,
+						LogSearchOptions,
+					) ([]LogSearchMatch, error) {
+						return nil, nil
+					},
+				},
+				warmLogsStore: &mockLogsStore{
+					StreamLogsFn: func(
+						context.Context,
+						Project,
+						Event,
+						LogsSelector,
+						LogStreamOptions,
+					) (<-chan LogEntry, error) {
+						logEntryCh := make(chan LogEntry, 2)
+						logEntryCh <- LogEntry{Message: "PASS"}
+						logEntryCh <- LogEntry{Message: "FAIL"}
+						close(logEntryCh)
+						return logEntryCh, nil
+					},
+				},
+			},
+			assertions: func(matches meta.List[LogSearchMatch], err error) {
+				require.NoError(t, err)
+				require.Equal(
+					t,
+					[]LogSearchMatch{
+						{
+							EventID:   testEventID,
+							Container: "worker",
+							LogEntry:  LogEntry{Message: "FAIL"},
+						},
+					},
+					matches.Items,
+				)
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.assertions(
+				testCase.service.Search(
+					context.Background(),
+					testEventID,
+					testCase.criteria,
+					LogSearchOptions{},
+				),
+			)
+		})
+	}
+}
+
+func TestLogsServiceSearchProject(t *testing.T) {
+	const testProjectID = "italian"
+	testCriteria := LogSearchCriteria{
+		Pattern: "FAIL",
+	}
+	testCases := []struct {
+		name       string
+		service    LogsService
+		assertions func(meta.List[LogSearchMatch], error)
+	}{
+		{
+			name: "unauthorized",
+			service: &logsService{
+				projectAuthorize: neverProjectAuthorize,
+			},
+			assertions: func(_ meta.List[LogSearchMatch], err error) {
+				require.Error(t, err)
+				require.IsType(t, &meta.ErrAuthorization{}, err)
+			},
+		},
+		{
+			name: "error retrieving events from store",
+			service: &logsService{
+				projectAuthorize: alwaysProjectAuthorize,
+				projectsStore: &mockProjectsStore{
+					GetFn: func(context.Context, string) (Project, error) {
+						return Project{}, nil
+					},
+				},
+				eventsStore: &mockEventsStore{
+					ListFn: func(
+						context.Context,
+						EventsSelector,
+						meta.ListOptions,
+					) (meta.List[Event], error) {
+						return meta.List[Event]{}, errors.New("something went wrong")
+					},
+				},
+			},
+			assertions: func(_ meta.List[LogSearchMatch], err error) {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), "something went wrong")
+				require.Contains(t, err.Error(), "error retrieving project")
+			},
+		},
+		{
+			name: "success",
+			service: &logsService{
+				projectAuthorize: alwaysProjectAuthorize,
+				projectsStore: &mockProjectsStore{
+					GetFn: func(context.Context, string) (Project, error) {
+						return Project{}, nil
+					},
+				},
+				eventsStore: &mockEventsStore{
+					ListFn: func(
+						_ context.Context,
+						selector EventsSelector,
+						opts meta.ListOptions,
+					) (meta.List[Event], error) {
+						require.Equal(t, testProjectID, selector.ProjectID)
+						require.Equal(t, int64(defaultLogSearchEvents), opts.Limit)
+						return meta.List[Event]{
+							Items: []Event{
+								{
+									ObjectMeta: meta.ObjectMeta{
+										ID: "abc",
+									},
+								},
+								{
+									ObjectMeta: meta.ObjectMeta{
+										ID: "def",
+									},
+								},
+							},
+						}, nil
+					},
+				},
+				coolLogsStore: &mockLogsStore{
+					SearchLogsFn: func(
+						_ context.Context,
+						event Event,
+						_ string,
+						_ LogSearchOptions,
+					) ([]LogSearchMatch, error) {
+						return []LogSearchMatch{
+							{
+								EventID:   event.ID,
+								Container: "worker",
+								LogEntry:  LogEntry{Message: "FAIL"},
+							},
+						}, nil
+					},
+				},
+			},
+			assertions: func(matches meta.List[LogSearchMatch], err error) {
+				require.NoError(t, err)
+				require.Len(t, matches.Items, 2)
+				require.Equal(t, "abc", matches.Items[0].EventID)
+				require.Equal(t, "def", matches.Items[1].EventID)
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.assertions(
+				testCase.service.SearchProject(
+					context.Background(),
+					testProjectID,
+					testCriteria,
+					LogSearchOptions{},
+				),
+			)
+		})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"time"
 
 	"github.com/brigadecore/brigade-foundations/retries"
@@ -24,6 +25,16 @@ type LogsSelector struct {
 	// presume logs are desired from a container having the same name as the
 	// selected Worker or Job.
 	Container string
+	// Attempt specifies, by number, an attempt at the Job specified by Job.
+	// Attempts are numbered from 1. If not specified, log streaming operations
+	// presume logs are desired from the Job's current or most recent attempt.
+	Attempt int
+	// All indicates that logs should be streamed from the Worker and from every
+	// container of every Job spawned by that Worker, interleaved by the time
+	// each line was written. Jobs spawned after streaming begins are picked up
+	// automatically if the stream is being followed. All may not be combined
+	// with Job, Container, or Attempt.
+	All bool
 }
 
 // LogStreamOptions represents useful options for streaming logs from some
@@ -34,6 +45,17 @@ type LogStreamOptions struct {
 	// until closed by the client (true), continuing to send new lines as they
 	// become available.
 	Follow bool `json:"follow"`
+	// TailLines, if greater than zero, limits the stream to beginning with the
+	// specified number of most recent lines of logs, instead of replaying all
+	// available lines.
+	TailLines int64 `json:"tailLines,omitempty"`
+	// Since, if specified, excludes lines of logs written before the specified
+	// time.
+	Since *time.Time `json:"since,omitempty"`
+	// Until, if specified, excludes lines of logs written after the specified
+	// time. When following, the stream concludes once a line written after the
+	// specified time is encountered.
+	Until *time.Time `json:"until,omitempty"`
 }
 
 // LogEntry represents one line of output from an OCI container.
@@ -42,6 +64,13 @@ type LogEntry struct {
 	Time *time.Time `json:"time,omitempty" bson:"time,omitempty"`
 	// Message is a single line of log output from an OCI container.
 	Message string `json:"message,omitempty" bson:"log,omitempty"`
+	// Job is the name of the Job whose logs the line belongs to. It is only
+	// populated when streaming logs from all of an Event's containers and is
+	// left empty for lines belonging to the Event's Worker.
+	Job string `json:"job,omitempty" bson:"-"`
+	// Container is the name of the container whose logs the line belongs to. It
+	// is only populated when streaming logs from all of an Event's containers.
+	Container string `json:"container,omitempty" bson:"-"`
 }
 
 // MarshalJSON amends LogEntry instances with type metadata so that clients do
@@ -78,6 +107,33 @@ type LogsService interface {
 		selector LogsSelector,
 		opts LogStreamOptions,
 	) (<-chan LogEntry, error)
+	// Search returns a list of lines of logs, belonging to an Event's Worker or
+	// any Job spawned by that Worker, that match the provided
+	// LogSearchCriteria. If the specified Event does not exist, implementations
+	// MUST return a *meta.ErrNotFound error.
+	Search(
+		ctx context.Context,
+		eventID string,
+		criteria LogSearchCriteria,
+		opts LogSearchOptions,
+	) (meta.List[LogSearchMatch], error)
+	// Archive returns an io.ReadCloser from which a gzipped tarball can be read.
+	// The tarball contains one file of logs for every container of an Event's
+	// Worker and of every attempt at every Job spawned by that Worker, along with
+	// a manifest describing them. Callers are responsible for closing the
+	// io.ReadCloser. If the specified Event does not exist, implementations MUST
+	// return a *meta.ErrNotFound error.
+	Archive(ctx context.Context, eventID string) (io.ReadCloser, error)
+	// SearchProject returns a list of lines of logs, belonging to any of a
+	// Project's most recent Events, that match the provided LogSearchCriteria.
+	// If the specified Project does not exist, implementations MUST return a
+	// *meta.ErrNotFound error.
+	SearchProject(
+		ctx context.Context,
+		projectID string,
+		criteria LogSearchCriteria,
+		opts LogSearchOptions,
+	) (meta.List[LogSearchMatch], error)
 }
 
 type logsService struct {
@@ -86,7 +142,7 @@ type logsService struct {
 	projectsStore    ProjectsStore
 	eventsStore      EventsStore
 	warmLogsStore    LogsStore
-	coolLogsStore    LogsStore
+	coolLogsStore    CoolLogsStore
 }
 
 // NewLogsService returns a specialized interface for accessing logs.
@@ -96,7 +152,7 @@ func NewLogsService(
 	projectsStore ProjectsStore,
 	eventsStore EventsStore,
 	warmLogsStore LogsStore,
-	coolLogsStore LogsStore,
+	coolLogsStore CoolLogsStore,
 ) LogsService {
 	return &logsService{
 		authorize:        authorize,
@@ -115,6 +171,37 @@ func (l *logsService) Stream(
 	selector LogsSelector,
 	opts LogStreamOptions,
 ) (<-chan LogEntry, error) {
+	if opts.TailLines < 0 {
+		return nil, &meta.ErrBadRequest{
+			Reason: "Number of tail lines must not be negative.",
+		}
+	}
+	if opts.Since != nil && opts.Until != nil && opts.Until.Before(*opts.Since) {
+		return nil, &meta.ErrBadRequest{
+			Reason: "End of time range must not be before its start.",
+		}
+	}
+
+	if selector.All {
+		if selector.Job != "" ||
+			selector.Container != "" ||
+			selector.Attempt != 0 {
+			return nil, &meta.ErrBadRequest{
+				Reason: "Logs from all containers may not be selected along with a " +
+					"specific job, container, or attempt.",
+			}
+		}
+		event, err := l.eventsStore.Get(ctx, eventID)
+		if err != nil {
+			return nil,
+				errors.Wrapf(err, "error retrieving event %q from store", eventID)
+		}
+		if err = l.authorizeLogs(ctx, event); err != nil {
+			return nil, err
+		}
+		return l.streamAll(ctx, event, opts)
+	}
+
 	// Set defaults on the selector
 	if selector.Job == "" { // If a job isn't specified, then we want worker logs
 		if selector.Container == "" {
@@ -135,24 +222,7 @@ func (l *logsService) Stream(
 			errors.Wrapf(err, "error retrieving event %q from store", eventID)
 	}
 
-	// Throughout the service layer, we typically only require RoleReader() to
-	// authorize read-only operations of any kind. In the case of logs, however,
-	// there's just too much possibility of secrets bleeding into the logs, not
-	// due to any fault of Brigade's but because of some end-user misstep. So, out
-	// of an abundance of caution, we raise the bar a little on this one read-only
-	// operation and require the principal to be a project user in order to stream
-	// logs.
-	err = l.projectAuthorize(ctx, event.ProjectID, RoleProjectUser)
-	if err != nil {
-		// We also permit access by the event's worker
-		err = l.authorize(ctx, RoleWorker, event.ID)
-	}
-	if err != nil {
-		// We also permit access by the creator of the event. This enables smarter
-		// gateways to send logs "upstream" if appropriate.
-		err = l.authorize(ctx, RoleEventCreator, event.Source)
-	}
-	if err != nil {
+	if err = l.authorizeLogs(ctx, event); err != nil {
 		return nil, err
 	}
 
@@ -213,6 +283,16 @@ func (l *logsService) Stream(
 				ID:   selector.Container,
 			}
 		}
+		// And make sure the attempt exists.
+		if selector.Attempt == 0 {
+			selector.Attempt = job.Status.currentAttempt()
+		} else if selector.Attempt < 0 ||
+			selector.Attempt > job.Status.currentAttempt() {
+			return nil, &meta.ErrNotFound{
+				Type: "JobAttempt",
+				ID:   fmt.Sprintf("%d", selector.Attempt),
+			}
+		}
 
 		// Check to see if we need to look up logs via a specific event ID,
 		// as job may be cached and carried over on a retry event
@@ -271,16 +351,31 @@ func (l *logsService) Stream(
 					event.Worker.Status.Phase == WorkerPhaseStarting, nil
 			}
 			// Else Job...
-			// If the Job's phase is PENDING or STARTING, then retry.
-			// Otherwise, exit the retry loop.
+			// If the selected attempt is the Job's current attempt and the Job's
+			// phase is PENDING or STARTING, then retry. Otherwise, exit the retry
+			// loop.
 			job, _ := event.Worker.Job(selector.Job)
-			return job.Status.Phase == JobPhasePending ||
-				job.Status.Phase == JobPhaseStarting, nil
+			return job.Status.currentAttempt() == selector.Attempt &&
+				(job.Status.Phase == JobPhasePending ||
+					job.Status.Phase == JobPhaseStarting), nil
 		},
 	); err != nil {
 		return nil, err
 	}
 
+	return l.streamContainerLogs(ctx, project, event, selector, opts)
+}
+
+// streamContainerLogs returns a channel over which logs from the container
+// specified by the provided LogsSelector are streamed. Logs are streamed from
+// the warm logs store if possible and from the cool logs store otherwise.
+func (l *logsService) streamContainerLogs(
+	ctx context.Context,
+	project Project,
+	event Event,
+	selector LogsSelector,
+	opts LogStreamOptions,
+) (<-chan LogEntry, error) {
 	logCh, err := l.warmLogsStore.StreamLogs(ctx, project, event, selector, opts)
 	if err != nil {
 		// If the issue is simply that the warmLogsStore couldn't find the logs
@@ -294,6 +389,29 @@ func (l *logsService) Stream(
 	return logCh, err
 }
 
+// authorizeLogs returns an error if the principal associated with the provided
+// context is not permitted to access the provided Event's logs.
+func (l *logsService) authorizeLogs(ctx context.Context, event Event) error {
+	// Throughout the service layer, we typically only require RoleReader() to
+	// authorize read-only operations of any kind. In the case of logs, however,
+	// there's just too much possibility of secrets bleeding into the logs, not
+	// due to any fault of Brigade's but because of some end-user misstep. So, out
+	// of an abundance of caution, we raise the bar a little on this read-only
+	// operation and require the principal to be a project user in order to
+	// access logs.
+	err := l.projectAuthorize(ctx, event.ProjectID, RoleProjectUser)
+	if err != nil {
+		// We also permit access by the event's worker
+		err = l.authorize(ctx, RoleWorker, event.ID)
+	}
+	if err != nil {
+		// We also permit access by the creator of the event. This enables smarter
+		// gateways to send logs "upstream" if appropriate.
+		err = l.authorize(ctx, RoleEventCreator, event.Source)
+	}
+	return err
+}
+
 // LogsStore is an interface for components that implement Log persistence
 // concerns.
 type LogsStore interface {
@@ -319,9 +437,39 @@ type LogsStore interface {
 type CoolLogsStore interface {
 	LogsStore
 
+	// SearchLogs returns up to opts.Limit lines of logs, belonging to the
+	// provided Event's Worker or any Job spawned by that Worker, that match the
+	// provided regular expression, along with up to opts.ContextLines lines
+	// before and after each of them.
+	SearchLogs(
+		ctx context.Context,
+		event Event,
+		expression string,
+		opts LogSearchOptions,
+	) ([]LogSearchMatch, error)
+
 	// DeleteEventLogs deletes all logs associated with the provided event.
-	DeleteEventLogs(ctx context.Context, id string) error
+	DeleteEventLogs(ctx context.Context, event Event) error
 
 	// DeleteProjectLogs deletes all logs associated with the provided project.
 	DeleteProjectLogs(ctx context.Context, id string) error
 }
+
+// CoolLogsWriter is an interface for components that write logs directly to a
+// cool logs store. Ordinarily, logs are written to a cool logs store by a log
+// aggregator, so this is only needed for moving logs from one cool logs store
+// to another.
+type CoolLogsWriter interface {
+	// WriteLogs writes the provided lines of logs, which belong to the container
+	// specified by the provided LogsSelector, as a single batch identified by the
+	// provided batch ID. Writing a batch with the same ID as a previously written
+	// batch for the same container replaces that batch.
+	WriteLogs(
+		ctx context.Context,
+		projectID string,
+		eventID string,
+		selector LogsSelector,
+		batchID string,
+		logEntries []LogEntry,
+	) error
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/brigadecore/brigade/v2/apiserver/internal/meta"
 	metaTesting "github.com/brigadecore/brigade/v2/apiserver/internal/meta/testing" // nolint: lll
@@ -37,12 +38,55 @@ func TestLogsService(t *testing.T) {
 
 func TestLogsServiceStream(t *testing.T) {
 	const testEventID = "123456789"
+	testSince := time.Date(2021, time.December, 1, 12, 0, 0, 0, time.UTC)
+	testUntil := testSince.Add(-time.Hour)
 	testCases := []struct {
 		name       string
 		service    LogsService
 		selector   LogsSelector
+		opts       LogStreamOptions
 		assertions func(<-chan LogEntry, error)
 	}{
+		{
+			name: "negative tail lines",
+			opts: LogStreamOptions{
+				TailLines: -1,
+			},
+			service: &logsService{},
+			assertions: func(_ <-chan LogEntry, err error) {
+				require.Error(t, err)
+				require.IsType(t, &meta.ErrBadRequest{}, err)
+			},
+		},
+		{
+			name: "invalid time range",
+			opts: LogStreamOptions{
+				Since: &testSince,
+				Until: &testUntil,
+			},
+			service: &logsService{},
+			assertions: func(_ <-chan LogEntry, err error) {
+				require.Error(t, err)
+				require.IsType(t, &meta.ErrBadRequest{}, err)
+				require.Equal(
+					t,
+					"End of time range must not be before its start.",
+					err.(*meta.ErrBadRequest).Reason,
+				)
+			},
+		},
+		{
+			name: "all containers selected along with a job",
+			selector: LogsSelector{
+				All: true,
+				Job: "italian",
+			},
+			service: &logsService{},
+			assertions: func(_ <-chan LogEntry, err error) {
+				require.Error(t, err)
+				require.IsType(t, &meta.ErrBadRequest{}, err)
+			},
+		},
 		{
 			name:     "error retrieving event from store",
 			selector: LogsSelector{},
@@ -147,6 +191,40 @@ func TestLogsServiceStream(t *testing.T) {
 				require.Equal(t, "bar", enf.ID)
 			},
 		},
+		{
+			name: "invalid job attempt",
+			selector: LogsSelector{
+				Job:     "foo",
+				Attempt: 3,
+			},
+			service: &logsService{
+				projectAuthorize: alwaysProjectAuthorize,
+				eventsStore: &mockEventsStore{
+					GetFn: func(context.Context, string) (Event, error) {
+						return Event{
+							Worker: Worker{
+								Jobs: []Job{
+									{
+										Name: "foo",
+										Status: &JobStatus{
+											Phase:   JobPhaseFailed,
+											Attempt: 2,
+										},
+									},
+								},
+							},
+						}, nil
+					},
+				},
+			},
+			assertions: func(_ <-chan LogEntry, err error) {
+				require.Error(t, err)
+				enf, ok := err.(*meta.ErrNotFound)
+				require.True(t, ok)
+				require.Equal(t, "JobAttempt", enf.Type)
+				require.Equal(t, "3", enf.ID)
+			},
+		},
 		{
 			name:     "error retrieving project from store",
 			selector: LogsSelector{},
@@ -419,7 +497,7 @@ func TestLogsServiceStream(t *testing.T) {
 				context.Background(),
 				testEventID,
 				testCase.selector,
-				LogStreamOptions{},
+				testCase.opts,
 			)
 			testCase.assertions(logCh, err)
 		})
@@ -435,9 +513,16 @@ type mockLogsStore struct {
 		opts LogStreamOptions,
 	) (<-chan LogEntry, error)
 
+	SearchLogsFn func(
+		ctx context.Context,
+		event Event,
+		expression string,
+		opts LogSearchOptions,
+	) ([]LogSearchMatch, error)
+
 	DeleteEventLogsFn func(
 		ctx context.Context,
-		id string,
+		event Event,
 	) error
 
 	DeleteProjectLogsFn func(
@@ -456,11 +541,20 @@ func (m *mockLogsStore) StreamLogs(
 	return m.StreamLogsFn(ctx, project, event, selector, opts)
 }
 
+func (m *mockLogsStore) SearchLogs(
+	ctx context.Context,
+	event Event,
+	expression string,
+	opts LogSearchOptions,
+) ([]LogSearchMatch, error) {
+	return m.SearchLogsFn(ctx, event, expression, opts)
+}
+
 func (m *mockLogsStore) DeleteEventLogs(
 	ctx context.Context,
-	id string,
+	event Event,
 ) error {
-	return m.DeleteEventLogsFn(ctx, id)
+	return m.DeleteEventLogsFn(ctx, event)
 }
 
 func (m *mockLogsStore) DeleteProjectLogs(
@@ -0,0 +1,362 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/brigadecore/brigade/v2/apiserver/internal/api"
+	"github.com/brigadecore/brigade/v2/apiserver/internal/lib/mongodb"
+	"github.com/brigadecore/brigade/v2/apiserver/internal/meta"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/gridfs"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// artifactsBucketName is the name of the GridFS bucket in which Artifacts are
+// stored.
+const artifactsBucketName = "artifacts"
+
+// gridFSBucket is an interface for the subset of *gridfs.Bucket functions that
+// we actually use. Using this interface, instead of using the *gridfs.Bucket
+// type directly, allows for the possibility of utilizing a mock implementation
+// for testing purposes.
+type gridFSBucket interface {
+	// UploadFromStream creates a file with a new ID and uploads the content read
+	// from the provided io.Reader to it.
+	UploadFromStream(
+		filename string,
+		source io.Reader,
+		opts ...*options.UploadOptions,
+	) (primitive.ObjectID, error)
+	// OpenDownloadStream creates a stream from which the content of the file
+	// with the provided ID can be read.
+	OpenDownloadStream(fileID interface{}) (*gridfs.DownloadStream, error)
+	// Delete deletes all chunks and metadata associated with the file with the
+	// provided ID.
+	Delete(fileID interface{}) error
+}
+
+// artifactMetadata is the metadata stored alongside every file in the
+// artifacts bucket.
+type artifactMetadata struct {
+	ProjectID string `bson:"projectID"`
+	EventID   string `bson:"eventID"`
+	Job       string `bson:"job"`
+	Name      string `bson:"name"`
+}
+
+// artifactFile represents a document in the artifacts bucket's files
+// collection.
+type artifactFile struct {
+	ID         primitive.ObjectID `bson:"_id"`
+	Length     int64              `bson:"length"`
+	UploadDate time.Time          `bson:"uploadDate"`
+	Metadata   artifactMetadata   `bson:"metadata"`
+}
+
+// artifact returns an api.Artifact corresponding to the artifactFile.
+func (a artifactFile) artifact() api.Artifact {
+	uploadDate := a.UploadDate.UTC()
+	return api.Artifact{
+		Name:    a.Metadata.Name,
+		Size:    a.Length,
+		Created: &uploadDate,
+	}
+}
+
+// artifactsStore is a MongoDB-based implementation of the api.ArtifactsStore
+// interface that utilizes GridFS for storing Artifact content.
+type artifactsStore struct {
+	bucket          gridFSBucket
+	filesCollection mongodb.Collection
+}
+
+// NewArtifactsStore returns a MongoDB-based implementation of the
+// api.ArtifactsStore interface that utilizes GridFS for storing Artifact
+// content.
+func NewArtifactsStore(database *mongo.Database) (api.ArtifactsStore, error) {
+	bucket, err := gridfs.NewBucket(
+		database,
+		options.GridFSBucket().SetName(artifactsBucketName),
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "error initializing artifacts bucket")
+	}
+	ctx, cancel :=
+		context.WithTimeout(context.Background(), createIndexTimeout)
+	defer cancel()
+	filesCollection := bucket.GetFilesCollection()
+	if _, err := filesCollection.Indexes().CreateMany(
+		ctx,
+		[]mongo.IndexModel{
+			// This index supports finding a job's artifacts.
+			{
+				Keys: bson.D{
+					{Key: "metadata.eventID", Value: 1},
+					{Key: "metadata.job", Value: 1},
+					{Key: "metadata.name", Value: 1},
+				},
+			},
+			// This index supports deleting a project's artifacts.
+			{
+				Keys: bson.M{
+					"metadata.projectID": 1,
+				},
+			},
+		},
+	); err != nil {
+		return nil, errors.Wrap(
+			err,
+			"error adding indexes to artifacts files collection",
+		)
+	}
+	return &artifactsStore{
+		bucket:          bucket,
+		filesCollection: filesCollection,
+	}, nil
+}
+
+func (a *artifactsStore) Put(
+	ctx context.Context,
+	event api.Event,
+	jobName string,
+	artifactName string,
+	content io.Reader,
+) (api.Artifact, error) {
+	// If reading from the source fails, the upload is aborted and any chunks
+	// already written are deleted.
+	id, err := a.bucket.UploadFromStream(
+		fmt.Sprintf("%s/%s/%s", event.ID, jobName, artifactName),
+		content,
+		options.GridFSUpload().SetMetadata(
+			artifactMetadata{
+				ProjectID: event.ProjectID,
+				EventID:   event.ID,
+				Job:       jobName,
+				Name:      artifactName,
+			},
+		),
+	)
+	if err != nil {
+		return api.Artifact{}, errors.Wrapf(
+			err,
+			"error uploading event %q job %q artifact %q",
+			event.ID,
+			jobName,
+			artifactName,
+		)
+	}
+
+	// Find all files for this artifact so we can delete all but the one we just
+	// uploaded.
+	cur, err := a.filesCollection.Find(
+		ctx,
+		bson.M{
+			"metadata.eventID": event.ID,
+			"metadata.job":     jobName,
+			"metadata.name":    artifactName,
+		},
+	)
+	if err != nil {
+		return api.Artifact{}, errors.Wrapf(
+			err,
+			"error finding event %q job %q artifact %q files",
+			event.ID,
+			jobName,
+			artifactName,
+		)
+	}
+	files := []artifactFile{}
+	if err = cur.All(ctx, &files); err != nil {
+		return api.Artifact{}, errors.Wrapf(
+			err,
+			"error decoding event %q job %q artifact %q files",
+			event.ID,
+			jobName,
+			artifactName,
+		)
+	}
+	var artifact *api.Artifact
+	for _, file := range files {
+		if file.ID == id {
+			uploaded := file.artifact()
+			artifact = &uploaded
+			continue
+		}
+		if err = a.bucket.Delete(file.ID); err != nil &&
+			err != gridfs.ErrFileNotFound {
+			return api.Artifact{}, errors.Wrapf(
+				err,
+				"error deleting superseded event %q job %q artifact %q file",
+				event.ID,
+				jobName,
+				artifactName,
+			)
+		}
+	}
+	if artifact == nil {
+		return api.Artifact{}, errors.Errorf(
+			"uploaded event %q job %q artifact %q file was not found",
+			event.ID,
+			jobName,
+			artifactName,
+		)
+	}
+	return *artifact, nil
+}
+
+func (a *artifactsStore) List(
+	ctx context.Context,
+	event api.Event,
+	jobName string,
+) (meta.List[api.Artifact], error) {
+	artifacts := meta.List[api.Artifact]{}
+	cur, err := a.filesCollection.Find(
+		ctx,
+		bson.M{
+			"metadata.eventID": event.ID,
+			"metadata.job":     jobName,
+		},
+		options.Find().SetSort(
+			bson.D{
+				{Key: "metadata.name", Value: 1},
+				{Key: "uploadDate", Value: -1},
+			},
+		),
+	)
+	if err != nil {
+		return artifacts, errors.Wrapf(
+			err,
+			"error finding event %q job %q artifacts",
+			event.ID,
+			jobName,
+		)
+	}
+	files := []artifactFile{}
+	if err = cur.All(ctx, &files); err != nil {
+		return artifacts, errors.Wrapf(
+			err,
+			"error decoding event %q job %q artifacts",
+			event.ID,
+			jobName,
+		)
+	}
+	artifacts.Items = make([]api.Artifact, 0, len(files))
+	for _, file := range files {
+		// Files are sorted newest first within each name, so if a superseded file
+		// has lingered, it is skipped here.
+		if len(artifacts.Items) > 0 &&
+			artifacts.Items[len(artifacts.Items)-1].Name == file.Metadata.Name {
+			continue
+		}
+		artifacts.Items = append(artifacts.Items, file.artifact())
+	}
+	return artifacts, nil
+}
+
+func (a *artifactsStore) Get(
+	ctx context.Context,
+	event api.Event,
+	jobName string,
+	artifactName string,
+) (api.Artifact, io.ReadCloser, error) {
+	res := a.filesCollection.FindOne(
+		ctx,
+		bson.M{
+			"metadata.eventID": event.ID,
+			"metadata.job":     jobName,
+			"metadata.name":    artifactName,
+		},
+		options.FindOne().SetSort(bson.M{"uploadDate": -1}),
+	)
+	file := artifactFile{}
+	err := res.Decode(&file)
+	if err == mongo.ErrNoDocuments {
+		return api.Artifact{}, nil, &meta.ErrNotFound{
+			Type: api.ArtifactKind,
+			ID:   artifactName,
+		}
+	}
+	if err != nil {
+		return api.Artifact{}, nil, errors.Wrapf(
+			err,
+			"error finding/decoding event %q job %q artifact %q",
+			event.ID,
+			jobName,
+			artifactName,
+		)
+	}
+	content, err := a.bucket.OpenDownloadStream(file.ID)
+	if err != nil {
+		return api.Artifact{}, nil, errors.Wrapf(
+			err,
+			"error opening event %q job %q artifact %q download stream",
+			event.ID,
+			jobName,
+			artifactName,
+		)
+	}
+	return file.artifact(), content, nil
+}
+
+func (a *artifactsStore) DeleteEventArtifacts(
+	ctx context.Context,
+	event api.Event,
+) error {
+	if err := a.deleteFiles(
+		ctx,
+		bson.M{
+			"metadata.eventID": event.ID,
+		},
+	); err != nil {
+		return errors.Wrapf(err, "error deleting artifacts for event %q", event.ID)
+	}
+	return nil
+}
+
+func (a *artifactsStore) DeleteProjectArtifacts(
+	ctx context.Context,
+	projectID string,
+) error {
+	if err := a.deleteFiles(
+		ctx,
+		bson.M{
+			"metadata.projectID": projectID,
+		},
+	); err != nil {
+		return errors.Wrapf(
+			err,
+			"error deleting artifacts for project %q",
+			projectID,
+		)
+	}
+	return nil
+}
+
+// deleteFiles deletes all files in the artifacts bucket matching the provided
+// criteria.
+func (a *artifactsStore) deleteFiles(
+	ctx context.Context,
+	criteria bson.M,
+) error {
+	cur, err := a.filesCollection.Find(ctx, criteria)
+	if err != nil {
+		return errors.Wrap(err, "error finding artifact files")
+	}
+	files := []artifactFile{}
+	if err = cur.All(ctx, &files); err != nil {
+		return errors.Wrap(err, "error decoding artifact files")
+	}
+	for _, file := range files {
+		if err = a.bucket.Delete(file.ID); err != nil &&
+			err != gridfs.ErrFileNotFound {
+			return errors.Wrapf(err, "error deleting artifact file %q", file.ID)
+		}
+	}
+	return nil
+}
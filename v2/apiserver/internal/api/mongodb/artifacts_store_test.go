@@ -0,0 +1,508 @@
+package mongodb
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/brigadecore/brigade/v2/apiserver/internal/api"
+	mongoTesting "github.com/brigadecore/brigade/v2/apiserver/internal/lib/mongodb/testing" // nolint: lll
+	"github.com/brigadecore/brigade/v2/apiserver/internal/meta"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/gridfs"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func TestArtifactsStorePut(t *testing.T) {
+	const testJobName = "italian"
+	const testArtifactName = "report.xml"
+	testEvent := api.Event{
+		ObjectMeta: meta.ObjectMeta{
+			ID: "123456789",
+		},
+		ProjectID: "blue-book",
+	}
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	newID := primitive.NewObjectID()
+	oldID := primitive.NewObjectID()
+	testCases := []struct {
+		name       string
+		store      *artifactsStore
+		assertions func(api.Artifact, error)
+	}{
+		{
+			name: "error uploading file",
+			store: &artifactsStore{
+				bucket: &mockGridFSBucket{
+					UploadFromStreamFn: func(
+						string,
+						io.Reader,
+						...*options.UploadOptions,
+					) (primitive.ObjectID, error) {
+						return primitive.NilObjectID, errors.New("something went wrong")
+					},
+				},
+			},
+			assertions: func(_ api.Artifact, err error) {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), "something went wrong")
+				require.Contains(t, err.Error(), "error uploading event")
+			},
+		},
+		{
+			name: "error deleting superseded file",
+			store: &artifactsStore{
+				bucket: &mockGridFSBucket{
+					UploadFromStreamFn: func(
+						string,
+						io.Reader,
+						...*options.UploadOptions,
+					) (primitive.ObjectID, error) {
+						return newID, nil
+					},
+					DeleteFn: func(interface{}) error {
+						return errors.New("something went wrong")
+					},
+				},
+				filesCollection: &mongoTesting.MockCollection{
+					FindFn: func(
+						context.Context,
+						interface{},
+						...*options.FindOptions,
+					) (*mongo.Cursor, error) {
+						return mongoTesting.MockCursor(
+							artifactFile{ID: oldID},
+							artifactFile{ID: newID},
+						)
+					},
+				},
+			},
+			assertions: func(_ api.Artifact, err error) {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), "something went wrong")
+				require.Contains(t, err.Error(), "error deleting superseded event")
+			},
+		},
+		{
+			name: "success",
+			store: &artifactsStore{
+				bucket: &mockGridFSBucket{
+					UploadFromStreamFn: func(
+						filename string,
+						_ io.Reader,
+						opts ...*options.UploadOptions,
+					) (primitive.ObjectID, error) {
+						require.Equal(
+							t,
+							"123456789/italian/report.xml",
+							filename,
+						)
+						require.Len(t, opts, 1)
+						require.Equal(
+							t,
+							artifactMetadata{
+								ProjectID: testEvent.ProjectID,
+								EventID:   testEvent.ID,
+								Job:       testJobName,
+								Name:      testArtifactName,
+							},
+							opts[0].Metadata,
+						)
+						return newID, nil
+					},
+					DeleteFn: func(fileID interface{}) error {
+						require.Equal(t, oldID, fileID)
+						return gridfs.ErrFileNotFound
+					},
+				},
+				filesCollection: &mongoTesting.MockCollection{
+					FindFn: func(
+						_ context.Context,
+						filter interface{},
+						_ ...*options.FindOptions,
+					) (*mongo.Cursor, error) {
+						require.Equal(
+							t,
+							bson.M{
+								"metadata.eventID": testEvent.ID,
+								"metadata.job":     testJobName,
+								"metadata.name":    testArtifactName,
+							},
+							filter,
+						)
+						return mongoTesting.MockCursor(
+							artifactFile{ID: oldID},
+							artifactFile{
+								ID:         newID,
+								Length:     6,
+								UploadDate: now,
+								Metadata: artifactMetadata{
+									Name: testArtifactName,
+								},
+							},
+						)
+					},
+				},
+			},
+			assertions: func(artifact api.Artifact, err error) {
+				require.NoError(t, err)
+				require.Equal(t, testArtifactName, artifact.Name)
+				require.Equal(t, int64(6), artifact.Size)
+				require.Equal(t, now, *artifact.Created)
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.assertions(
+				testCase.store.Put(
+					context.Background(),
+					testEvent,
+					testJobName,
+					testArtifactName,
+					nil,
+				),
+			)
+		})
+	}
+}
+
+func TestArtifactsStoreList(t *testing.T) {
+	const testJobName = "italian"
+	testEvent := api.Event{
+		ObjectMeta: meta.ObjectMeta{
+			ID: "123456789",
+		},
+	}
+	testCases := []struct {
+		name       string
+		collection *mongoTesting.MockCollection
+		assertions func(meta.List[api.Artifact], error)
+	}{
+		{
+			name: "error finding artifacts",
+			collection: &mongoTesting.MockCollection{
+				FindFn: func(
+					context.Context,
+					interface{},
+					...*options.FindOptions,
+				) (*mongo.Cursor, error) {
+					return nil, errors.New("something went wrong")
+				},
+			},
+			assertions: func(_ meta.List[api.Artifact], err error) {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), "something went wrong")
+				require.Contains(t, err.Error(), "error finding event")
+			},
+		},
+		{
+			name: "superseded files skipped",
+			collection: &mongoTesting.MockCollection{
+				FindFn: func(
+					_ context.Context,
+					filter interface{},
+					_ ...*options.FindOptions,
+				) (*mongo.Cursor, error) {
+					require.Equal(
+						t,
+						bson.M{
+							"metadata.eventID": testEvent.ID,
+							"metadata.job":     testJobName,
+						},
+						filter,
+					)
+					return mongoTesting.MockCursor(
+						artifactFile{
+							Length:   2,
+							Metadata: artifactMetadata{Name: "coverage.out"},
+						},
+						artifactFile{
+							Length:   1,
+							Metadata: artifactMetadata{Name: "coverage.out"},
+						},
+						artifactFile{
+							Length:   3,
+							Metadata: artifactMetadata{Name: "report.xml"},
+						},
+					)
+				},
+			},
+			assertions: func(artifacts meta.List[api.Artifact], err error) {
+				require.NoError(t, err)
+				require.Len(t, artifacts.Items, 2)
+				require.Equal(t, "coverage.out", artifacts.Items[0].Name)
+				require.Equal(t, int64(2), artifacts.Items[0].Size)
+				require.Equal(t, "report.xml", artifacts.Items[1].Name)
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			store := &artifactsStore{
+				filesCollection: testCase.collection,
+			}
+			testCase.assertions(
+				store.List(context.Background(), testEvent, testJobName),
+			)
+		})
+	}
+}
+
+func TestArtifactsStoreGet(t *testing.T) {
+	const testArtifactName = "report.xml"
+	testCases := []struct {
+		name       string
+		store      *artifactsStore
+		assertions func(api.Artifact, io.ReadCloser, error)
+	}{
+		{
+			name: "artifact not found",
+			store: &artifactsStore{
+				filesCollection: &mongoTesting.MockCollection{
+					FindOneFn: func(
+						context.Context,
+						interface{},
+						...*options.FindOneOptions,
+					) *mongo.SingleResult {
+						res, err := mongoTesting.MockSingleResult(mongo.ErrNoDocuments)
+						require.NoError(t, err)
+						return res
+					},
+				},
+			},
+			assertions: func(_ api.Artifact, _ io.ReadCloser, err error) {
+				require.Error(t, err)
+				require.IsType(t, &meta.ErrNotFound{}, err)
+				require.Equal(t, api.ArtifactKind, err.(*meta.ErrNotFound).Type)
+			},
+		},
+		{
+			name: "error opening download stream",
+			store: &artifactsStore{
+				bucket: &mockGridFSBucket{
+					OpenDownloadStreamFn: func(
+						interface{},
+					) (*gridfs.DownloadStream, error) {
+						return nil, errors.New("something went wrong")
+					},
+				},
+				filesCollection: &mongoTesting.MockCollection{
+					FindOneFn: func(
+						context.Context,
+						interface{},
+						...*options.FindOneOptions,
+					) *mongo.SingleResult {
+						res, err := mongoTesting.MockSingleResult(
+							artifactFile{
+								Metadata: artifactMetadata{Name: testArtifactName},
+							},
+						)
+						require.NoError(t, err)
+						return res
+					},
+				},
+			},
+			assertions: func(_ api.Artifact, _ io.ReadCloser, err error) {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), "something went wrong")
+				require.Contains(t, err.Error(), "download stream")
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.assertions(
+				testCase.store.Get(
+					context.Background(),
+					api.Event{},
+					"italian",
+					testArtifactName,
+				),
+			)
+		})
+	}
+}
+
+func TestArtifactsStoreDeleteEventArtifacts(t *testing.T) {
+	testEvent := api.Event{
+		ObjectMeta: meta.ObjectMeta{
+			ID: "123456789",
+		},
+	}
+	testID := primitive.NewObjectID()
+	testCases := []struct {
+		name       string
+		store      *artifactsStore
+		assertions func(error)
+	}{
+		{
+			name: "error finding artifact files",
+			store: &artifactsStore{
+				filesCollection: &mongoTesting.MockCollection{
+					FindFn: func(
+						context.Context,
+						interface{},
+						...*options.FindOptions,
+					) (*mongo.Cursor, error) {
+						return nil, errors.New("something went wrong")
+					},
+				},
+			},
+			assertions: func(err error) {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), "something went wrong")
+				require.Contains(t, err.Error(), "error deleting artifacts for event")
+			},
+		},
+		{
+			name: "success",
+			store: &artifactsStore{
+				bucket: &mockGridFSBucket{
+					DeleteFn: func(fileID interface{}) error {
+						require.Equal(t, testID, fileID)
+						return nil
+					},
+				},
+				filesCollection: &mongoTesting.MockCollection{
+					FindFn: func(
+						_ context.Context,
+						filter interface{},
+						_ ...*options.FindOptions,
+					) (*mongo.Cursor, error) {
+						require.Equal(
+							t,
+							bson.M{
+								"metadata.eventID": testEvent.ID,
+							},
+							filter,
+						)
+						return mongoTesting.MockCursor(artifactFile{ID: testID})
+					},
+				},
+			},
+			assertions: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.assertions(
+				testCase.store.DeleteEventArtifacts(context.Background(), testEvent),
+			)
+		})
+	}
+}
+
+func TestArtifactsStoreDeleteProjectArtifacts(t *testing.T) {
+	const testProjectID = "blue-book"
+	testID := primitive.NewObjectID()
+	testCases := []struct {
+		name       string
+		store      *artifactsStore
+		assertions func(error)
+	}{
+		{
+			name: "error deleting artifact file",
+			store: &artifactsStore{
+				bucket: &mockGridFSBucket{
+					DeleteFn: func(interface{}) error {
+						return errors.New("something went wrong")
+					},
+				},
+				filesCollection: &mongoTesting.MockCollection{
+					FindFn: func(
+						context.Context,
+						interface{},
+						...*options.FindOptions,
+					) (*mongo.Cursor, error) {
+						return mongoTesting.MockCursor(artifactFile{ID: testID})
+					},
+				},
+			},
+			assertions: func(err error) {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), "something went wrong")
+				require.Contains(
+					t,
+					err.Error(),
+					"error deleting artifacts for project",
+				)
+			},
+		},
+		{
+			name: "success",
+			store: &artifactsStore{
+				bucket: &mockGridFSBucket{
+					DeleteFn: func(fileID interface{}) error {
+						require.Equal(t, testID, fileID)
+						return nil
+					},
+				},
+				filesCollection: &mongoTesting.MockCollection{
+					FindFn: func(
+						_ context.Context,
+						filter interface{},
+						_ ...*options.FindOptions,
+					) (*mongo.Cursor, error) {
+						require.Equal(
+							t,
+							bson.M{
+								"metadata.projectID": testProjectID,
+							},
+							filter,
+						)
+						return mongoTesting.MockCursor(artifactFile{ID: testID})
+					},
+				},
+			},
+			assertions: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.assertions(
+				testCase.store.DeleteProjectArtifacts(
+					context.Background(),
+					testProjectID,
+				),
+			)
+		})
+	}
+}
+
+type mockGridFSBucket struct {
+	UploadFromStreamFn func(
+		filename string,
+		source io.Reader,
+		opts ...*options.UploadOptions,
+	) (primitive.ObjectID, error)
+	OpenDownloadStreamFn func(fileID interface{}) (*gridfs.DownloadStream, error)
+	DeleteFn             func(fileID interface{}) error
+}
+
+func (m *mockGridFSBucket) UploadFromStream(
+	filename string,
+	source io.Reader,
+	opts ...*options.UploadOptions,
+) (primitive.ObjectID, error) {
+	return m.UploadFromStreamFn(filename, source, opts...)
+}
+
+func (m *mockGridFSBucket) OpenDownloadStream(
+	fileID interface{},
+) (*gridfs.DownloadStream, error) {
+	return m.OpenDownloadStreamFn(fileID)
+}
+
+func (m *mockGridFSBucket) Delete(fileID interface{}) error {
+	return m.DeleteFn(fileID)
+}
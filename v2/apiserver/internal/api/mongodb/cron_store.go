@@ -0,0 +1,145 @@
+package mongodb
+
+import (
+	"context"
+	"time"
+
+	"github.com/brigadecore/brigade/v2/apiserver/internal/api"
+	"github.com/brigadecore/brigade/v2/apiserver/internal/lib/mongodb"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// cronRecord is the representation of an EventSchedule's last fired time in
+// the underlying collection.
+type cronRecord struct {
+	ProjectID string    `bson:"projectID"`
+	Schedule  string    `bson:"schedule"`
+	LastFired time.Time `bson:"lastFired"`
+}
+
+// cronStore is a MongoDB-based implementation of the api.CronStore interface.
+type cronStore struct {
+	collection mongodb.Collection
+}
+
+// NewCronStore returns a MongoDB-based implementation of the api.CronStore
+// interface.
+func NewCronStore(database *mongo.Database) (api.CronStore, error) {
+	ctx, cancel :=
+		context.WithTimeout(context.Background(), createIndexTimeout)
+	defer cancel()
+	unique := true
+	collection := database.Collection("schedules")
+	if _, err := collection.Indexes().CreateMany(
+		ctx,
+		[]mongo.IndexModel{
+			{
+				Keys: bson.D{
+					{Key: "projectID", Value: 1},
+					{Key: "schedule", Value: 1},
+				},
+				Options: &options.IndexOptions{
+					Unique: &unique,
+				},
+			},
+		},
+	); err != nil {
+		return nil, errors.Wrap(
+			err,
+			"error adding indexes to schedules collection",
+		)
+	}
+	return &cronStore{
+		collection: collection,
+	}, nil
+}
+
+func (c *cronStore) GetLastFired(
+	ctx context.Context,
+	projectID string,
+	schedule string,
+) (*time.Time, error) {
+	record := cronRecord{}
+	res := c.collection.FindOne(
+		ctx,
+		bson.M{
+			"projectID": projectID,
+			"schedule":  schedule,
+		},
+	)
+	err := res.Decode(&record)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(
+			err,
+			"error finding/decoding project %q schedule %q record",
+			projectID,
+			schedule,
+		)
+	}
+	lastFired := record.LastFired.UTC()
+	return &lastFired, nil
+}
+
+func (c *cronStore) Claim(
+	ctx context.Context,
+	projectID string,
+	schedule string,
+	fireTime time.Time,
+) (bool, error) {
+	// If a record exists with an EARLIER last fired time, it is updated. If no
+	// record exists, one is inserted. If a record exists with an equal or later
+	// last fired time, the upsert collides with the existing record on the
+	// unique index and the fire time has already been claimed by someone else.
+	upsert := true
+	res, err := c.collection.UpdateOne(
+		ctx,
+		bson.M{
+			"projectID": projectID,
+			"schedule":  schedule,
+			"lastFired": bson.M{"$lt": fireTime},
+		},
+		bson.M{
+			"$set": bson.M{
+				"lastFired": fireTime,
+			},
+		},
+		&options.UpdateOptions{
+			Upsert: &upsert,
+		},
+	)
+	if err != nil {
+		if mongodb.IsDuplicateKeyError(err) {
+			return false, nil
+		}
+		return false, errors.Wrapf(
+			err,
+			"error updating project %q schedule %q record",
+			projectID,
+			schedule,
+		)
+	}
+	return res.MatchedCount > 0 || res.UpsertedCount > 0, nil
+}
+
+func (c *cronStore) DeleteByProjectID(
+	ctx context.Context,
+	projectID string,
+) error {
+	_, err := c.collection.DeleteMany(
+		ctx,
+		bson.M{
+			"projectID": projectID,
+		},
+	)
+	return errors.Wrapf(
+		err,
+		"error deleting schedule records for project %q",
+		projectID,
+	)
+}
@@ -0,0 +1,251 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/brigadecore/brigade/v2/apiserver/internal/lib/mongodb"
+	mongoTesting "github.com/brigadecore/brigade/v2/apiserver/internal/lib/mongodb/testing" // nolint: lll
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func TestCronStoreGetLastFired(t *testing.T) {
+	const testProjectID = "italian"
+	const testSchedule = "nightly"
+	testLastFired := time.Date(2021, time.May, 1, 0, 0, 0, 0, time.UTC)
+	testCases := []struct {
+		name       string
+		collection mongodb.Collection
+		assertions func(lastFired *time.Time, err error)
+	}{
+		{
+			name: "record not found",
+			collection: &mongoTesting.MockCollection{
+				FindOneFn: func(
+					ctx context.Context,
+					filter interface{},
+					opts ...*options.FindOneOptions,
+				) *mongo.SingleResult {
+					res, err := mongoTesting.MockSingleResult(mongo.ErrNoDocuments)
+					require.NoError(t, err)
+					return res
+				},
+			},
+			assertions: func(lastFired *time.Time, err error) {
+				require.NoError(t, err)
+				require.Nil(t, lastFired)
+			},
+		},
+		{
+			name: "unanticipated error",
+			collection: &mongoTesting.MockCollection{
+				FindOneFn: func(
+					ctx context.Context,
+					filter interface{},
+					opts ...*options.FindOneOptions,
+				) *mongo.SingleResult {
+					res, err := mongoTesting.MockSingleResult(
+						errors.New("something went wrong"),
+					)
+					require.NoError(t, err)
+					return res
+				},
+			},
+			assertions: func(lastFired *time.Time, err error) {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), "something went wrong")
+				require.Contains(t, err.Error(), "error finding/decoding project")
+			},
+		},
+		{
+			name: "record found",
+			collection: &mongoTesting.MockCollection{
+				FindOneFn: func(
+					ctx context.Context,
+					filter interface{},
+					opts ...*options.FindOneOptions,
+				) *mongo.SingleResult {
+					res, err := mongoTesting.MockSingleResult(
+						cronRecord{
+							ProjectID: testProjectID,
+							Schedule:  testSchedule,
+							LastFired: testLastFired,
+						},
+					)
+					require.NoError(t, err)
+					return res
+				},
+			},
+			assertions: func(lastFired *time.Time, err error) {
+				require.NoError(t, err)
+				require.NotNil(t, lastFired)
+				require.True(t, testLastFired.Equal(*lastFired))
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			store := &cronStore{
+				collection: testCase.collection,
+			}
+			lastFired, err := store.GetLastFired(
+				context.Background(),
+				testProjectID,
+				testSchedule,
+			)
+			testCase.assertions(lastFired, err)
+		})
+	}
+}
+
+func TestCronStoreClaim(t *testing.T) {
+	testCases := []struct {
+		name       string
+		collection mongodb.Collection
+		assertions func(claimed bool, err error)
+	}{
+		{
+			name: "unanticipated error",
+			collection: &mongoTesting.MockCollection{
+				UpdateOneFn: func(
+					context.Context,
+					interface{},
+					interface{},
+					...*options.UpdateOptions,
+				) (*mongo.UpdateResult, error) {
+					return nil, errors.New("something went wrong")
+				},
+			},
+			assertions: func(claimed bool, err error) {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), "something went wrong")
+				require.Contains(t, err.Error(), "error updating project")
+				require.False(t, claimed)
+			},
+		},
+		{
+			name: "already claimed",
+			collection: &mongoTesting.MockCollection{
+				UpdateOneFn: func(
+					context.Context,
+					interface{},
+					interface{},
+					...*options.UpdateOptions,
+				) (*mongo.UpdateResult, error) {
+					return nil, mongoTesting.MockWriteException
+				},
+			},
+			assertions: func(claimed bool, err error) {
+				require.NoError(t, err)
+				require.False(t, claimed)
+			},
+		},
+		{
+			name: "claimed by update",
+			collection: &mongoTesting.MockCollection{
+				UpdateOneFn: func(
+					context.Context,
+					interface{},
+					interface{},
+					...*options.UpdateOptions,
+				) (*mongo.UpdateResult, error) {
+					return &mongo.UpdateResult{
+						MatchedCount: 1,
+					}, nil
+				},
+			},
+			assertions: func(claimed bool, err error) {
+				require.NoError(t, err)
+				require.True(t, claimed)
+			},
+		},
+		{
+			name: "claimed by insert",
+			collection: &mongoTesting.MockCollection{
+				UpdateOneFn: func(
+					context.Context,
+					interface{},
+					interface{},
+					...*options.UpdateOptions,
+				) (*mongo.UpdateResult, error) {
+					return &mongo.UpdateResult{
+						UpsertedCount: 1,
+					}, nil
+				},
+			},
+			assertions: func(claimed bool, err error) {
+				require.NoError(t, err)
+				require.True(t, claimed)
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			store := &cronStore{
+				collection: testCase.collection,
+			}
+			claimed, err := store.Claim(
+				context.Background(),
+				"italian",
+				"nightly",
+				time.Now().UTC(),
+			)
+			testCase.assertions(claimed, err)
+		})
+	}
+}
+
+func TestCronStoreDeleteByProjectID(t *testing.T) {
+	testCases := []struct {
+		name       string
+		collection mongodb.Collection
+		assertions func(err error)
+	}{
+		{
+			name: "unanticipated error",
+			collection: &mongoTesting.MockCollection{
+				DeleteManyFn: func(
+					context.Context,
+					interface{},
+					...*options.DeleteOptions,
+				) (*mongo.DeleteResult, error) {
+					return nil, errors.New("something went wrong")
+				},
+			},
+			assertions: func(err error) {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), "something went wrong")
+				require.Contains(t, err.Error(), "error deleting schedule records")
+			},
+		},
+		{
+			name: "success",
+			collection: &mongoTesting.MockCollection{
+				DeleteManyFn: func(
+					context.Context,
+					interface{},
+					...*options.DeleteOptions,
+				) (*mongo.DeleteResult, error) {
+					return &mongo.DeleteResult{}, nil
+				},
+			},
+			assertions: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			store := &cronStore{
+				collection: testCase.collection,
+			}
+			testCase.assertions(
+				store.DeleteByProjectID(context.Background(), "italian"),
+			)
+		})
+	}
+}
@@ -42,6 +42,35 @@ func NewEventsStore(database *mongo.Database) (api.EventsStore, error) {
 					Unique: &unique,
 				},
 			},
+			// This index guarantees that a given idempotency key cannot be used more
+			// than once to create an event for a given project from a given source.
+			// Only events that actually bear an idempotency key are indexed.
+			{
+				Keys: bson.D{
+					{Key: "source", Value: 1},
+					{Key: "idempotencyKey", Value: 1},
+					{Key: "projectID", Value: 1},
+				},
+				Options: &options.IndexOptions{
+					Unique: &unique,
+					PartialFilterExpression: bson.M{
+						"idempotencyKey": bson.M{"$exists": true},
+					},
+				},
+			},
+			// This index supports finding the events in a given concurrency group.
+			// Only events that actually belong to a concurrency group are indexed.
+			{
+				Keys: bson.D{
+					{Key: "projectID", Value: 1},
+					{Key: "concurrencyGroup", Value: 1},
+				},
+				Options: &options.IndexOptions{
+					PartialFilterExpression: bson.M{
+						"concurrencyGroup": bson.M{"$exists": true},
+					},
+				},
+			},
 		},
 	); err != nil {
 		return nil, errors.Wrap(err, "error adding indexes to events collection")
@@ -58,6 +87,18 @@ func (e *eventsStore) Create(ctx context.Context, event api.Event) error {
 		event.Worker.Jobs = []api.Job{}
 	}
 	if _, err := e.collection.InsertOne(ctx, event); err != nil {
+		if mongodb.IsDuplicateKeyError(err) {
+			return &meta.ErrConflict{
+				Type: api.EventKind,
+				ID:   event.ID,
+				Reason: fmt.Sprintf(
+					"An event with the ID %q or with the idempotency key %q already "+
+						"exists.",
+					event.ID,
+					event.IdempotencyKey,
+				),
+			}
+		}
 		return errors.Wrapf(err, "error inserting new event %q", event.ID)
 	}
 	return nil
@@ -93,6 +134,13 @@ func (e *eventsStore) List(
 	if selector.Type != "" {
 		criteria["type"] = selector.Type
 	}
+	if selector.ConcurrencyGroup != "" {
+		criteria["concurrencyGroup"] = selector.ConcurrencyGroup
+	}
+	applyCreatedBeforeCriteria(criteria, selector)
+	if selector.ExcludePinned {
+		criteria["pinned"] = nil // Matches null or missing
+	}
 	if len(selector.WorkerPhases) > 0 {
 		criteria["worker.status.phase"] = bson.M{
 			"$in": selector.WorkerPhases,
@@ -181,6 +229,69 @@ func (e *eventsStore) Get(
 	return event, nil
 }
 
+func (e *eventsStore) ListByIdempotencyKey(
+	ctx context.Context,
+	source string,
+	key string,
+) (meta.List[api.Event], error) {
+	events := meta.List[api.Event]{}
+	findOptions := options.Find()
+	findOptions.SetSort(
+		// bson.D preserves order so we use this wherever we sort so that if
+		// additional sort criteria are added in the future, they will be applied
+		// in the specified order.
+		bson.D{
+			{Key: "projectID", Value: 1},
+		},
+	)
+	cur, err := e.collection.Find(
+		ctx,
+		bson.M{
+			"source":         source,
+			"idempotencyKey": key,
+		},
+		findOptions,
+	)
+	if err != nil {
+		return events, errors.Wrapf(
+			err,
+			"error finding events with idempotency key %q",
+			key,
+		)
+	}
+	if err := cur.All(ctx, &events.Items); err != nil {
+		return events, errors.Wrap(err, "error decoding events")
+	}
+	return events, nil
+}
+
+func (e *eventsStore) ReleaseIdempotencyKey(
+	ctx context.Context,
+	source string,
+	key string,
+) error {
+	if _, err := e.collection.UpdateMany(
+		ctx,
+		bson.M{
+			"source":         source,
+			"idempotencyKey": key,
+		},
+		bson.M{
+			"$unset": bson.M{
+				"idempotencyKey": 1,
+			},
+		},
+	); err != nil {
+		return errors.Wrapf(
+			err,
+			"error releasing idempotency key %q for events from source %q",
+			key,
+			source,
+		)
+	}
+	return nil
+}
+
 func (e *eventsStore) GetByHashedWorkerToken(
 	ctx context.Context,
 	hashedWorkerToken string,
@@ -277,6 +388,43 @@ func (e *eventsStore) UpdateSummary(
 	return nil
 }
 
+func (e *eventsStore) UpdateLabels(
+	ctx context.Context,
+	id string,
+	labels map[string]string,
+) error {
+	// Labels are replaced wholesale because label keys commonly contain dots,
+	// which MongoDB would interpret as paths into nested documents.
+	res, err := e.collection.UpdateOne(
+		ctx,
+		bson.M{
+			"id": id,
+			"deleted": bson.M{
+				"$exists": false, // Don't grab logically deleted events
+			},
+		},
+		bson.M{
+			"$set": bson.M{
+				"labels": labels,
+			},
+		},
+	)
+	if err != nil {
+		return errors.Wrapf(
+			err,
+			"error updating labels of event %q",
+			id,
+		)
+	}
+	if res.MatchedCount == 0 {
+		return &meta.ErrNotFound{
+			Type: "Event",
+			ID:   id,
+		}
+	}
+	return nil
+}
+
 func (e *eventsStore) Cancel(ctx context.Context, id string) error {
 	cancellationTime := time.Now().UTC()
 
@@ -357,7 +505,60 @@ func (e *eventsStore) Cancel(ctx context.Context, id string) error {
 	return nil
 }
 
-// nolint: gocyclo
+func (e *eventsStore) Pin(ctx context.Context, id string) error {
+	res, err := e.collection.UpdateOne(
+		ctx,
+		bson.M{
+			"id": id,
+			"deleted": bson.M{
+				"$exists": false, // Don't grab logically deleted events
+			},
+		},
+		bson.M{
+			"$set": bson.M{
+				"pinned": time.Now().UTC(),
+			},
+		},
+	)
+	if err != nil {
+		return errors.Wrapf(err, "error pinning event %q", id)
+	}
+	if res.MatchedCount == 0 {
+		return &meta.ErrNotFound{
+			Type: api.EventKind,
+			ID:   id,
+		}
+	}
+	return nil
+}
+
+func (e *eventsStore) Unpin(ctx context.Context, id string) error {
+	res, err := e.collection.UpdateOne(
+		ctx,
+		bson.M{
+			"id": id,
+			"deleted": bson.M{
+				"$exists": false, // Don't grab logically deleted events
+			},
+		},
+		bson.M{
+			"$unset": bson.M{
+				"pinned": "",
+			},
+		},
+	)
+	if err != nil {
+		return errors.Wrapf(err, "error unpinning event %q", id)
+	}
+	if res.MatchedCount == 0 {
+		return &meta.ErrNotFound{
+			Type: api.EventKind,
+			ID:   id,
+		}
+	}
+	return nil
+}
+
 func (e *eventsStore) CancelMany(
 	ctx context.Context,
 	selector api.EventsSelector,
@@ -560,6 +761,10 @@ func (e *eventsStore) DeleteMany(
 			"$in": selector.WorkerPhases,
 		}
 	}
+	applyCreatedBeforeCriteria(criteria, selector)
+	if selector.ExcludePinned {
+		criteria["pinned"] = nil // Matches null or missing
+	}
 	result, err := e.collection.UpdateMany(
 		ctx,
 		criteria,
@@ -627,3 +832,83 @@ func (e *eventsStore) DeleteByProjectID(
 	)
 	return errors.Wrapf(err, "error deleting events for project %q", projectID)
 }
+
+func (e *eventsStore) GetQueueDepth(
+	ctx context.Context,
+	projectID string,
+) (api.ProjectQueueDepth, error) {
+	depth := api.ProjectQueueDepth{
+		ProjectID: projectID,
+	}
+	var err error
+	if depth.PendingWorkers, err = e.collection.CountDocuments(
+		ctx,
+		bson.M{
+			"projectID":           projectID,
+			"worker.status.phase": api.WorkerPhasePending,
+		},
+	); err != nil {
+		return depth, errors.Wrapf(
+			err,
+			"error counting pending workers for project %q",
+			projectID,
+		)
+	}
+	// Pending Jobs are elements of an array in each Event document, so we
+	// retrieve only the Jobs of Events that have any pending Jobs and count those
+	// ourselves.
+	findOptions := options.Find()
+	findOptions.SetProjection(bson.M{"worker.jobs": 1})
+	cur, err := e.collection.Find(
+		ctx,
+		bson.M{
+			"projectID":                projectID,
+			"worker.jobs.status.phase": api.JobPhasePending,
+		},
+		findOptions,
+	)
+	if err != nil {
+		return depth, errors.Wrapf(
+			err,
+			"error finding events with pending jobs for project %q",
+			projectID,
+		)
+	}
+	events := []api.Event{}
+	if err = cur.All(ctx, &events); err != nil {
+		return depth, errors.Wrap(err, "error decoding events")
+	}
+	for _, event := range events {
+		for _, job := range event.Worker.Jobs {
+			if job.Status != nil && job.Status.Phase == api.JobPhasePending {
+				depth.PendingJobs++
+			}
+		}
+	}
+	return depth, nil
+}
+
+// applyCreatedBeforeCriteria amends the provided criteria to select only
+// Events created before the time indicated by the provided EventsSelector, if
+// any, taking its tiebreaker ID into account.
+func applyCreatedBeforeCriteria(criteria bson.M, selector api.EventsSelector) {
+	if selector.CreatedBefore == nil {
+		return
+	}
+	if selector.CreatedBeforeTiebreakerID == "" {
+		criteria["created"] = bson.M{"$lt": *selector.CreatedBefore}
+		return
+	}
+	// $and is used so as not to collide with any other $or criteria
+	criteria["$and"] = []bson.M{
+		{
+			"$or": []bson.M{
+				{"created": bson.M{"$lt": *selector.CreatedBefore}},
+				{
+					"created": *selector.CreatedBefore,
+					"id":      bson.M{"$gte": selector.CreatedBeforeTiebreakerID},
+				},
+			},
+		},
+	}
+}
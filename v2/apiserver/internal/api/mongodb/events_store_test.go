@@ -13,6 +13,7 @@ import (
 	mongoTesting "github.com/brigadecore/brigade/v2/apiserver/internal/lib/mongodb/testing" // nolint: lll
 	"github.com/brigadecore/brigade/v2/apiserver/internal/meta"
 	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
@@ -47,6 +48,25 @@ func TestEventsStoreCreate(t *testing.T) {
 			},
 		},
 
+		{
+			name: "event already exists",
+			collection: &mongoTesting.MockCollection{
+				InsertOneFn: func(
+					ctx context.Context,
+					document interface{},
+					opts ...*options.InsertOneOptions,
+				) (*mongo.InsertOneResult, error) {
+					return nil, mongoTesting.MockWriteException
+				},
+			},
+			assertions: func(err error) {
+				require.Error(t, err)
+				require.IsType(t, &meta.ErrConflict{}, err)
+				require.Equal(t, api.EventKind, err.(*meta.ErrConflict).Type)
+				require.Equal(t, testEvent.ID, err.(*meta.ErrConflict).ID)
+			},
+		},
+
 		{
 			name: "successful creation",
 			collection: &mongoTesting.MockCollection{
@@ -303,6 +323,142 @@ func TestEventsStoreGet(t *testing.T) {
 	}
 }
 
+func TestEventsStoreListByIdempotencyKey(t *testing.T) {
+	const testSource = "github.com/krancour/fake-gateway"
+	const testKey = "abc123"
+	testCases := []struct {
+		name       string
+		collection mongodb.Collection
+		assertions func(events meta.List[api.Event], err error)
+	}{
+		{
+			name: "error finding events",
+			collection: &mongoTesting.MockCollection{
+				FindFn: func(
+					ctx context.Context,
+					filter interface{},
+					opts ...*options.FindOptions,
+				) (*mongo.Cursor, error) {
+					return nil, errors.New("something went wrong")
+				},
+			},
+			assertions: func(_ meta.List[api.Event], err error) {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), "something went wrong")
+				require.Contains(
+					t,
+					err.Error(),
+					"error finding events with idempotency key",
+				)
+			},
+		},
+		{
+			name: "found events",
+			collection: &mongoTesting.MockCollection{
+				FindFn: func(
+					ctx context.Context,
+					filter interface{},
+					opts ...*options.FindOptions,
+				) (*mongo.Cursor, error) {
+					require.Equal(
+						t,
+						bson.M{
+							"source":         testSource,
+							"idempotencyKey": testKey,
+						},
+						filter,
+					)
+					cursor, err := mongoTesting.MockCursor(
+						api.Event{
+							ObjectMeta: meta.ObjectMeta{
+								ID: "foo",
+							},
+							IdempotencyKey: testKey,
+						},
+					)
+					require.NoError(t, err)
+					return cursor, nil
+				},
+			},
+			assertions: func(events meta.List[api.Event], err error) {
+				require.NoError(t, err)
+				require.Len(t, events.Items, 1)
+				require.Equal(t, testKey, events.Items[0].IdempotencyKey)
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			store := &eventsStore{
+				collection: testCase.collection,
+			}
+			events, err := store.ListByIdempotencyKey(
+				context.Background(),
+				testSource,
+				testKey,
+			)
+			testCase.assertions(events, err)
+		})
+	}
+}
+
+func TestEventsStoreReleaseIdempotencyKey(t *testing.T) {
+	testCases := []struct {
+		name       string
+		collection mongodb.Collection
+		assertions func(err error)
+	}{
+		{
+			name: "unanticipated error",
+			collection: &mongoTesting.MockCollection{
+				UpdateManyFn: func(
+					context.Context,
+					interface{},
+					interface{},
+					...*options.UpdateOptions,
+				) (*mongo.UpdateResult, error) {
+					return nil, errors.New("something went wrong")
+				},
+			},
+			assertions: func(err error) {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), "something went wrong")
+				require.Contains(t, err.Error(), "error releasing idempotency key")
+			},
+		},
+		{
+			name: "success",
+			collection: &mongoTesting.MockCollection{
+				UpdateManyFn: func(
+					context.Context,
+					interface{},
+					interface{},
+					...*options.UpdateOptions,
+				) (*mongo.UpdateResult, error) {
+					return &mongo.UpdateResult{}, nil
+				},
+			},
+			assertions: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			store := &eventsStore{
+				collection: testCase.collection,
+			}
+			testCase.assertions(
+				store.ReleaseIdempotencyKey(
+					context.Background(),
+					"github.com/krancour/fake-gateway",
+					"abc123",
+				),
+			)
+		})
+	}
+}
+
 func TestEventsStoreGetByHashedToken(t *testing.T) {
 	const testEventID = "123456789"
 	testCases := []struct {
@@ -553,6 +709,258 @@ func TestEventsStoreUpdateSummary(t *testing.T) {
 	}
 }
 
+func TestEventsStoreUpdateLabels(t *testing.T) {
+	const testEvent = "123456789"
+	testCases := []struct {
+		name       string
+		collection mongodb.Collection
+		assertions func(err error)
+	}{
+		{
+			name: "unanticipated error",
+			collection: &mongoTesting.MockCollection{
+				UpdateOneFn: func(
+					context.Context,
+					interface{},
+					interface{},
+					...*options.UpdateOptions,
+				) (*mongo.UpdateResult, error) {
+					return nil, errors.New("something went wrong")
+				},
+			},
+			assertions: func(err error) {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), "something went wrong")
+				require.Contains(t, err.Error(), "error updating labels of event")
+			},
+		},
+
+		{
+			name: "event not found",
+			collection: &mongoTesting.MockCollection{
+				UpdateOneFn: func(
+					context.Context,
+					interface{},
+					interface{},
+					...*options.UpdateOptions,
+				) (*mongo.UpdateResult, error) {
+					return &mongo.UpdateResult{
+						MatchedCount: 0,
+					}, nil
+				},
+			},
+			assertions: func(err error) {
+				require.Error(t, err)
+				require.IsType(t, &meta.ErrNotFound{}, err)
+			},
+		},
+
+		{
+			name: "success",
+			collection: &mongoTesting.MockCollection{
+				UpdateOneFn: func(
+					_ context.Context,
+					_ interface{},
+					update interface{},
+					_ ...*options.UpdateOptions,
+				) (*mongo.UpdateResult, error) {
+					require.Equal(
+						t,
+						bson.M{
+							"$set": bson.M{
+								"labels": map[string]string{
+									api.SupersededByLabelKey: "987654321",
+								},
+							},
+						},
+						update,
+					)
+					return &mongo.UpdateResult{
+						MatchedCount: 1,
+					}, nil
+				},
+			},
+			assertions: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			store := &eventsStore{
+				collection: testCase.collection,
+			}
+			err :=
+				store.UpdateLabels(
+					context.Background(),
+					testEvent,
+					map[string]string{
+						api.SupersededByLabelKey: "987654321",
+					},
+				)
+			testCase.assertions(err)
+		})
+	}
+}
+
+func TestEventsStorePin(t *testing.T) {
+	const testEvent = "123456789"
+	testCases := []struct {
+		name       string
+		collection mongodb.Collection
+		assertions func(err error)
+	}{
+		{
+			name: "unanticipated error",
+			collection: &mongoTesting.MockCollection{
+				UpdateOneFn: func(
+					context.Context,
+					interface{},
+					interface{},
+					...*options.UpdateOptions,
+				) (*mongo.UpdateResult, error) {
+					return nil, errors.New("something went wrong")
+				},
+			},
+			assertions: func(err error) {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), "something went wrong")
+				require.Contains(t, err.Error(), "error pinning event")
+			},
+		},
+
+		{
+			name: "event not found",
+			collection: &mongoTesting.MockCollection{
+				UpdateOneFn: func(
+					context.Context,
+					interface{},
+					interface{},
+					...*options.UpdateOptions,
+				) (*mongo.UpdateResult, error) {
+					return &mongo.UpdateResult{
+						MatchedCount: 0,
+					}, nil
+				},
+			},
+			assertions: func(err error) {
+				require.Error(t, err)
+				require.IsType(t, &meta.ErrNotFound{}, err)
+			},
+		},
+
+		{
+			name: "success",
+			collection: &mongoTesting.MockCollection{
+				UpdateOneFn: func(
+					_ context.Context,
+					filter interface{},
+					update interface{},
+					_ ...*options.UpdateOptions,
+				) (*mongo.UpdateResult, error) {
+					require.Equal(t, testEvent, filter.(bson.M)["id"])
+					_, ok := update.(bson.M)["$set"]
+					require.True(t, ok)
+					return &mongo.UpdateResult{
+						MatchedCount: 1,
+					}, nil
+				},
+			},
+			assertions: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			store := &eventsStore{
+				collection: testCase.collection,
+			}
+			err := store.Pin(context.Background(), testEvent)
+			testCase.assertions(err)
+		})
+	}
+}
+
+func TestEventsStoreUnpin(t *testing.T) {
+	const testEvent = "123456789"
+	testCases := []struct {
+		name       string
+		collection mongodb.Collection
+		assertions func(err error)
+	}{
+		{
+			name: "unanticipated error",
+			collection: &mongoTesting.MockCollection{
+				UpdateOneFn: func(
+					context.Context,
+					interface{},
+					interface{},
+					...*options.UpdateOptions,
+				) (*mongo.UpdateResult, error) {
+					return nil, errors.New("something went wrong")
+				},
+			},
+			assertions: func(err error) {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), "something went wrong")
+				require.Contains(t, err.Error(), "error unpinning event")
+			},
+		},
+
+		{
+			name: "event not found",
+			collection: &mongoTesting.MockCollection{
+				UpdateOneFn: func(
+					context.Context,
+					interface{},
+					interface{},
+					...*options.UpdateOptions,
+				) (*mongo.UpdateResult, error) {
+					return &mongo.UpdateResult{
+						MatchedCount: 0,
+					}, nil
+				},
+			},
+			assertions: func(err error) {
+				require.Error(t, err)
+				require.IsType(t, &meta.ErrNotFound{}, err)
+			},
+		},
+
+		{
+			name: "success",
+			collection: &mongoTesting.MockCollection{
+				UpdateOneFn: func(
+					_ context.Context,
+					filter interface{},
+					update interface{},
+					_ ...*options.UpdateOptions,
+				) (*mongo.UpdateResult, error) {
+					require.Equal(t, testEvent, filter.(bson.M)["id"])
+					_, ok := update.(bson.M)["$unset"]
+					require.True(t, ok)
+					return &mongo.UpdateResult{
+						MatchedCount: 1,
+					}, nil
+				},
+			},
+			assertions: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			store := &eventsStore{
+				collection: testCase.collection,
+			}
+			err := store.Unpin(context.Background(), testEvent)
+			testCase.assertions(err)
+		})
+	}
+}
+
 func TestEventsStoreCancel(t *testing.T) {
 	const testEventID = "abcedfg"
 	testCases := []struct {
@@ -1107,3 +1515,176 @@ func TestEventsStoreDeleteByProjectID(t *testing.T) {
 		})
 	}
 }
+
+func TestEventsStoreGetQueueDepth(t *testing.T) {
+	const testProjectID = "blue-book"
+	testCases := []struct {
+		name       string
+		collection mongodb.Collection
+		assertions func(api.ProjectQueueDepth, error)
+	}{
+		{
+			name: "error counting pending workers",
+			collection: &mongoTesting.MockCollection{
+				CountDocumentsFn: func(
+					context.Context,
+					interface{},
+					...*options.CountOptions,
+				) (int64, error) {
+					return 0, errors.New("something went wrong")
+				},
+			},
+			assertions: func(_ api.ProjectQueueDepth, err error) {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), "something went wrong")
+				require.Contains(t, err.Error(), "error counting pending workers")
+			},
+		},
+		{
+			name: "error finding events with pending jobs",
+			collection: &mongoTesting.MockCollection{
+				CountDocumentsFn: func(
+					context.Context,
+					interface{},
+					...*options.CountOptions,
+				) (int64, error) {
+					return 1, nil
+				},
+				FindFn: func(
+					context.Context,
+					interface{},
+					...*options.FindOptions,
+				) (*mongo.Cursor, error) {
+					return nil, errors.New("something went wrong")
+				},
+			},
+			assertions: func(_ api.ProjectQueueDepth, err error) {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), "something went wrong")
+				require.Contains(
+					t,
+					err.Error(),
+					"error finding events with pending jobs",
+				)
+			},
+		},
+		{
+			name: "success",
+			collection: &mongoTesting.MockCollection{
+				CountDocumentsFn: func(
+					context.Context,
+					interface{},
+					...*options.CountOptions,
+				) (int64, error) {
+					return 1, nil
+				},
+				FindFn: func(
+					context.Context,
+					interface{},
+					...*options.FindOptions,
+				) (*mongo.Cursor, error) {
+					cursor, err := mongoTesting.MockCursor(
+						api.Event{
+							Worker: api.Worker{
+								Jobs: []api.Job{
+									{
+										Status: &api.JobStatus{
+											Phase: api.JobPhasePending,
+										},
+									},
+									{
+										Status: &api.JobStatus{
+											Phase: api.JobPhaseRunning,
+										},
+									},
+								},
+							},
+						},
+						api.Event{
+							Worker: api.Worker{
+								Jobs: []api.Job{
+									{
+										Status: &api.JobStatus{
+											Phase: api.JobPhasePending,
+										},
+									},
+								},
+							},
+						},
+					)
+					require.NoError(t, err)
+					return cursor, nil
+				},
+			},
+			assertions: func(depth api.ProjectQueueDepth, err error) {
+				require.NoError(t, err)
+				require.Equal(
+					t,
+					api.ProjectQueueDepth{
+						ProjectID:      testProjectID,
+						PendingWorkers: 1,
+						PendingJobs:    2,
+					},
+					depth,
+				)
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			store := &eventsStore{
+				collection: testCase.collection,
+			}
+			depth, err := store.GetQueueDepth(context.Background(), testProjectID)
+			testCase.assertions(depth, err)
+		})
+	}
+}
+
+func TestApplyCreatedBeforeCriteria(t *testing.T) {
+	cutoff := time.Date(2021, time.December, 1, 12, 0, 0, 0, time.UTC)
+	testCases := []struct {
+		name             string
+		selector         api.EventsSelector
+		expectedCriteria bson.M
+	}{
+		{
+			name:             "no cutoff",
+			selector:         api.EventsSelector{},
+			expectedCriteria: bson.M{},
+		},
+		{
+			name: "cutoff without tiebreaker",
+			selector: api.EventsSelector{
+				CreatedBefore: &cutoff,
+			},
+			expectedCriteria: bson.M{
+				"created": bson.M{"$lt": cutoff},
+			},
+		},
+		{
+			name: "cutoff with tiebreaker",
+			selector: api.EventsSelector{
+				CreatedBefore:             &cutoff,
+				CreatedBeforeTiebreakerID: "bravo",
+			},
+			expectedCriteria: bson.M{
+				"$and": []bson.M{
+					{
+						"$or": []bson.M{
+							{"created": bson.M{"$lt": cutoff}},
+							{"created": cutoff, "id": bson.M{"$gte": "bravo"}},
+						},
+					},
+				},
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			criteria := bson.M{}
+			applyCreatedBeforeCriteria(criteria, testCase.selector)
+			require.Equal(t, testCase.expectedCriteria, criteria)
+		})
+	}
+}
@@ -0,0 +1,147 @@
+package mongodb
+
+import (
+	"context"
+	"time"
+
+	"github.com/brigadecore/brigade/v2/apiserver/internal/api"
+	"github.com/brigadecore/brigade/v2/apiserver/internal/lib/mongodb"
+	"github.com/brigadecore/brigade/v2/apiserver/internal/meta"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// jobCacheStore is a MongoDB-based implementation of the api.JobCacheStore
+// interface.
+type jobCacheStore struct {
+	collection mongodb.Collection
+}
+
+// NewJobCacheStore returns a MongoDB-based implementation of the
+// api.JobCacheStore interface.
+func NewJobCacheStore(database *mongo.Database) (api.JobCacheStore, error) {
+	ctx, cancel :=
+		context.WithTimeout(context.Background(), createIndexTimeout)
+	defer cancel()
+	unique := true
+	expireAfterSeconds := int32(0)
+	collection := database.Collection("job-cache")
+	if _, err := collection.Indexes().CreateMany(
+		ctx,
+		[]mongo.IndexModel{
+			{
+				Keys: bson.D{
+					{Key: "projectID", Value: 1},
+					{Key: "key", Value: 1},
+				},
+				Options: &options.IndexOptions{
+					Unique: &unique,
+				},
+			},
+			// This index automatically deletes expired entries.
+			{
+				Keys: bson.M{
+					"expires": 1,
+				},
+				Options: &options.IndexOptions{
+					ExpireAfterSeconds: &expireAfterSeconds,
+				},
+			},
+		},
+	); err != nil {
+		return nil, errors.Wrap(
+			err,
+			"error adding indexes to job cache collection",
+		)
+	}
+	return &jobCacheStore{
+		collection: collection,
+	}, nil
+}
+
+func (j *jobCacheStore) Get(
+	ctx context.Context,
+	projectID string,
+	key string,
+) (api.JobCacheEntry, error) {
+	entry := api.JobCacheEntry{}
+	res := j.collection.FindOne(
+		ctx,
+		bson.M{
+			"projectID": projectID,
+			"key":       key,
+			// Expired entries are only deleted periodically, so exclude any that
+			// haven't been deleted yet.
+			"expires": bson.M{"$gt": time.Now().UTC()},
+		},
+	)
+	err := res.Decode(&entry)
+	if err == mongo.ErrNoDocuments {
+		return entry, &meta.ErrNotFound{
+			Type: "JobCacheEntry",
+			ID:   key,
+		}
+	}
+	if err != nil {
+		return entry, errors.Wrapf(
+			res.Err(),
+			"error finding/decoding project %q job cache entry %q",
+			projectID,
+			key,
+		)
+	}
+	return entry, nil
+}
+
+func (j *jobCacheStore) Put(
+	ctx context.Context,
+	entry api.JobCacheEntry,
+) error {
+	upsert := true
+	if _, err := j.collection.UpdateOne(
+		ctx,
+		bson.M{
+			"projectID": entry.ProjectID,
+			"key":       entry.Key,
+		},
+		bson.M{
+			"$set": entry,
+		},
+		&options.UpdateOptions{
+			Upsert: &upsert,
+		},
+	); err != nil {
+		// If another equivalent Job's results were cached at the same time, the
+		// upsert may have collided with them on the unique index. Either Job's
+		// results are as good as the other's.
+		if mongodb.IsDuplicateKeyError(err) {
+			return nil
+		}
+		return errors.Wrapf(
+			err,
+			"error upserting project %q job cache entry %q",
+			entry.ProjectID,
+			entry.Key,
+		)
+	}
+	return nil
+}
+
+func (j *jobCacheStore) DeleteByProjectID(
+	ctx context.Context,
+	projectID string,
+) error {
+	if _, err := j.collection.DeleteMany(
+		ctx,
+		bson.M{"projectID": projectID},
+	); err != nil {
+		return errors.Wrapf(
+			err,
+			"error deleting job cache entries for project %q",
+			projectID,
+		)
+	}
+	return nil
+}
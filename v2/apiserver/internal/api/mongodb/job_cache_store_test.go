@@ -0,0 +1,231 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/brigadecore/brigade/v2/apiserver/internal/api"
+	"github.com/brigadecore/brigade/v2/apiserver/internal/lib/mongodb"
+	mongoTesting "github.com/brigadecore/brigade/v2/apiserver/internal/lib/mongodb/testing" // nolint: lll
+	"github.com/brigadecore/brigade/v2/apiserver/internal/meta"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func TestJobCacheStoreGet(t *testing.T) {
+	const testProjectID = "italian"
+	const testKey = "abcdef"
+	testCases := []struct {
+		name       string
+		collection mongodb.Collection
+		assertions func(entry api.JobCacheEntry, err error)
+	}{
+		{
+			name: "entry not found",
+			collection: &mongoTesting.MockCollection{
+				FindOneFn: func(
+					ctx context.Context,
+					filter interface{},
+					opts ...*options.FindOneOptions,
+				) *mongo.SingleResult {
+					res, err := mongoTesting.MockSingleResult(mongo.ErrNoDocuments)
+					require.NoError(t, err)
+					return res
+				},
+			},
+			assertions: func(_ api.JobCacheEntry, err error) {
+				require.Error(t, err)
+				require.IsType(t, &meta.ErrNotFound{}, err)
+			},
+		},
+		{
+			name: "unanticipated error",
+			collection: &mongoTesting.MockCollection{
+				FindOneFn: func(
+					ctx context.Context,
+					filter interface{},
+					opts ...*options.FindOneOptions,
+				) *mongo.SingleResult {
+					res, err := mongoTesting.MockSingleResult(
+						errors.New("something went wrong"),
+					)
+					require.NoError(t, err)
+					return res
+				},
+			},
+			assertions: func(_ api.JobCacheEntry, err error) {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), "something went wrong")
+				require.Contains(t, err.Error(), "error finding/decoding project")
+			},
+		},
+		{
+			name: "entry found",
+			collection: &mongoTesting.MockCollection{
+				FindOneFn: func(
+					ctx context.Context,
+					filter interface{},
+					opts ...*options.FindOneOptions,
+				) *mongo.SingleResult {
+					res, err := mongoTesting.MockSingleResult(
+						api.JobCacheEntry{
+							ProjectID: testProjectID,
+							Key:       testKey,
+							EventID:   "123456789",
+							JobName:   "build",
+						},
+					)
+					require.NoError(t, err)
+					return res
+				},
+			},
+			assertions: func(entry api.JobCacheEntry, err error) {
+				require.NoError(t, err)
+				require.Equal(t, "123456789", entry.EventID)
+				require.Equal(t, "build", entry.JobName)
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			store := &jobCacheStore{
+				collection: testCase.collection,
+			}
+			testCase.assertions(
+				store.Get(context.Background(), testProjectID, testKey),
+			)
+		})
+	}
+}
+
+func TestJobCacheStorePut(t *testing.T) {
+	testCases := []struct {
+		name       string
+		collection mongodb.Collection
+		assertions func(err error)
+	}{
+		{
+			name: "unanticipated error",
+			collection: &mongoTesting.MockCollection{
+				UpdateOneFn: func(
+					context.Context,
+					interface{},
+					interface{},
+					...*options.UpdateOptions,
+				) (*mongo.UpdateResult, error) {
+					return nil, errors.New("something went wrong")
+				},
+			},
+			assertions: func(err error) {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), "something went wrong")
+				require.Contains(t, err.Error(), "error upserting project")
+			},
+		},
+		{
+			name: "collision with concurrent upsert",
+			collection: &mongoTesting.MockCollection{
+				UpdateOneFn: func(
+					context.Context,
+					interface{},
+					interface{},
+					...*options.UpdateOptions,
+				) (*mongo.UpdateResult, error) {
+					return nil, mongoTesting.MockWriteException
+				},
+			},
+			assertions: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "success",
+			collection: &mongoTesting.MockCollection{
+				UpdateOneFn: func(
+					context.Context,
+					interface{},
+					interface{},
+					...*options.UpdateOptions,
+				) (*mongo.UpdateResult, error) {
+					return &mongo.UpdateResult{UpsertedCount: 1}, nil
+				},
+			},
+			assertions: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			store := &jobCacheStore{
+				collection: testCase.collection,
+			}
+			testCase.assertions(
+				store.Put(
+					context.Background(),
+					api.JobCacheEntry{
+						ProjectID: "italian",
+						Key:       "abcdef",
+						EventID:   "123456789",
+						JobName:   "build",
+						Expires:   time.Now().UTC().Add(time.Hour),
+					},
+				),
+			)
+		})
+	}
+}
+
+func TestJobCacheStoreDeleteByProjectID(t *testing.T) {
+	testCases := []struct {
+		name       string
+		collection mongodb.Collection
+		assertions func(err error)
+	}{
+		{
+			name: "unanticipated error",
+			collection: &mongoTesting.MockCollection{
+				DeleteManyFn: func(
+					context.Context,
+					interface{},
+					...*options.DeleteOptions,
+				) (*mongo.DeleteResult, error) {
+					return nil, errors.New("something went wrong")
+				},
+			},
+			assertions: func(err error) {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), "something went wrong")
+				require.Contains(t, err.Error(), "error deleting job cache entries")
+			},
+		},
+		{
+			name: "success",
+			collection: &mongoTesting.MockCollection{
+				DeleteManyFn: func(
+					context.Context,
+					interface{},
+					...*options.DeleteOptions,
+				) (*mongo.DeleteResult, error) {
+					return &mongo.DeleteResult{}, nil
+				},
+			},
+			assertions: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			store := &jobCacheStore{
+				collection: testCase.collection,
+			}
+			testCase.assertions(
+				store.DeleteByProjectID(context.Background(), "italian"),
+			)
+		})
+	}
+}
@@ -3,6 +3,9 @@ package mongodb
 import (
 	"context"
 	"fmt"
+	"reflect"
+	"strings"
+	"time"
 
 	"github.com/brigadecore/brigade/v2/apiserver/internal/api"
 	"github.com/brigadecore/brigade/v2/apiserver/internal/lib/mongodb"
@@ -10,8 +13,30 @@ import (
 	"github.com/pkg/errors"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
+// jobStatusFields are the names of the fields of a stored api.JobStatus, except
+// for its outputs, which a Job may report at any time. Updating these fields
+// individually, instead of replacing the entire status, ensures a status
+// update never overwrites outputs reported in the meantime.
+var jobStatusFields = func() []string {
+	fields := []string{}
+	statusType := reflect.TypeOf(api.JobStatus{})
+	for i := 0; i < statusType.NumField(); i++ {
+		name := strings.Split(statusType.Field(i).Tag.Get("bson"), ",")[0]
+		if name != "" && name != "outputs" {
+			fields = append(fields, name)
+		}
+	}
+	return fields
+}()
+
+// maxOutputsUpdateAttempts is the maximum number of times UpdateOutputs will
+// attempt to merge new outputs into a Job's existing ones when those are being
+// modified concurrently.
+const maxOutputsUpdateAttempts = 10
+
 // jobsStore is a MongoDB-based implementation of the api.JobsStore interface.
 type jobsStore struct {
 	collection mongodb.Collection
@@ -62,17 +87,52 @@ func (j *jobsStore) UpdateStatus(
 	jobName string,
 	status api.JobStatus,
 ) error {
+	statusBytes, err := bson.Marshal(status)
+	if err != nil {
+		return errors.Wrapf(
+			err,
+			"error marshaling status of event %q job %q",
+			eventID,
+			jobName,
+		)
+	}
+	statusDoc := bson.M{}
+	if err = bson.Unmarshal(statusBytes, &statusDoc); err != nil {
+		return errors.Wrapf(
+			err,
+			"error unmarshaling status of event %q job %q",
+			eventID,
+			jobName,
+		)
+	}
+	setFields := bson.M{}
+	unsetFields := bson.M{}
+	for _, field := range jobStatusFields {
+		path := fmt.Sprintf("worker.jobs.$.status.%s", field)
+		if value, ok := statusDoc[field]; ok {
+			setFields[path] = value
+		} else {
+			unsetFields[path] = 1
+		}
+	}
+	// Outputs only accompany a status when a Job's cached results are reused, in
+	// which case the Job never ran and cannot have reported outputs of its own.
+	if status.Outputs != nil {
+		setFields["worker.jobs.$.status.outputs"] = status.Outputs
+	}
+	update := bson.M{
+		"$set": setFields,
+	}
+	if len(unsetFields) > 0 {
+		update["$unset"] = unsetFields
+	}
 	res, err := j.collection.UpdateOne(
 		ctx,
 		bson.M{
 			"id":               eventID,
 			"worker.jobs.name": jobName,
 		},
-		bson.M{
-			"$set": bson.M{
-				"worker.jobs.$.status": status,
-			},
-		},
+		update,
 	)
 	if err != nil {
 		return errors.Wrapf(
@@ -90,3 +150,184 @@ func (j *jobsStore) UpdateStatus(
 	}
 	return nil
 }
+
+func (j *jobsStore) UpdateOutputs(
+	ctx context.Context,
+	eventID string,
+	jobName string,
+	outputs map[string]string,
+	maxSize int,
+) error {
+	// Outputs are stored as an array, so they cannot be set individually.
+	// Instead, the merged outputs replace the existing ones only if those are
+	// still exactly as they were found. If they were modified in the meantime,
+	// the merge is attempted again.
+	for i := 0; i < maxOutputsUpdateAttempts; i++ {
+		current, currentOutputs, err := j.getOutputs(ctx, eventID, jobName)
+		if err != nil {
+			return err
+		}
+		merged := api.JobOutputValues{}
+		for k, v := range currentOutputs {
+			merged[k] = v
+		}
+		for k, v := range outputs {
+			merged[k] = v
+		}
+		var size int
+		for k, v := range merged {
+			size += len(k) + len(v)
+		}
+		if size > maxSize {
+			return &meta.ErrBadRequest{
+				Reason: fmt.Sprintf(
+					"Job outputs may not exceed %d bytes in total.",
+					maxSize,
+				),
+			}
+		}
+		res, err := j.collection.UpdateOne(
+			ctx,
+			bson.M{
+				"id": eventID,
+				"worker.jobs": bson.M{
+					"$elemMatch": bson.M{
+						"name": jobName,
+						// A nil value matches outputs that don't exist yet
+						"status.outputs": current,
+					},
+				},
+			},
+			bson.M{
+				"$set": bson.M{
+					"worker.jobs.$.status.outputs": merged,
+				},
+			},
+		)
+		if err != nil {
+			return errors.Wrapf(
+				err,
+				"error updating outputs of event %q job %q",
+				eventID,
+				jobName,
+			)
+		}
+		if res.MatchedCount == 1 {
+			return nil
+		}
+	}
+	return &meta.ErrConflict{
+		Type: api.JobKind,
+		ID:   fmt.Sprintf("%s:%s", eventID, jobName),
+		Reason: fmt.Sprintf(
+			"The outputs of event %q job %q are being modified concurrently.",
+			eventID,
+			jobName,
+		),
+	}
+}
+
+// getOutputs returns the outputs of the specified Job, both exactly as they are
+// stored and as api.JobOutputValues, or nil if the Job has no outputs. If the
+// specified Job does not exist, a *meta.ErrNotFound error is returned.
+func (j *jobsStore) getOutputs(
+	ctx context.Context,
+	eventID string,
+	jobName string,
+) (interface{}, api.JobOutputValues, error) {
+	record := struct {
+		Worker struct {
+			Jobs []struct {
+				Status struct {
+					Outputs interface{} `bson:"outputs"`
+				} `bson:"status"`
+			} `bson:"jobs"`
+		} `bson:"worker"`
+	}{}
+	err := j.collection.FindOne(
+		ctx,
+		bson.M{
+			"id":               eventID,
+			"worker.jobs.name": jobName,
+		},
+		options.FindOne().SetProjection(bson.M{"worker.jobs.$": 1}),
+	).Decode(&record)
+	if err == mongo.ErrNoDocuments ||
+		(err == nil && len(record.Worker.Jobs) == 0) {
+		return nil, nil, &meta.ErrNotFound{
+			Type: api.JobKind,
+			ID:   fmt.Sprintf("%s:%s", eventID, jobName),
+		}
+	}
+	if err != nil {
+		return nil, nil, errors.Wrapf(
+			err,
+			"error finding/decoding outputs of event %q job %q",
+			eventID,
+			jobName,
+		)
+	}
+	stored := record.Worker.Jobs[0].Status.Outputs
+	if stored == nil {
+		return nil, nil, nil
+	}
+	outputs := api.JobOutputValues{}
+	bsonType, bsonBytes, err := bson.MarshalValue(stored)
+	if err == nil {
+		err = outputs.UnmarshalBSONValue(bsonType, bsonBytes)
+	}
+	if err != nil {
+		return nil, nil, errors.Wrapf(
+			err,
+			"error decoding outputs of event %q job %q",
+			eventID,
+			jobName,
+		)
+	}
+	return stored, outputs, nil
+}
+
+func (j *jobsStore) ClaimRetry(
+	ctx context.Context,
+	now time.Time,
+) (*api.Event, string, error) {
+	res := j.collection.FindOneAndUpdate(
+		ctx,
+		bson.M{
+			"worker.jobs": bson.M{
+				"$elemMatch": bson.M{
+					"status.phase": api.JobPhasePending,
+					"status.nextAttempt": bson.M{
+						"$lte": now,
+					},
+				},
+			},
+		},
+		bson.M{
+			"$unset": bson.M{
+				"worker.jobs.$.status.nextAttempt": "",
+			},
+		},
+	)
+	event := api.Event{}
+	err := res.Decode(&event)
+	if err == mongo.ErrNoDocuments {
+		return nil, "", nil
+	}
+	if err != nil {
+		return nil, "", errors.Wrap(err, "error finding/decoding event")
+	}
+	// The event, as it was before the update, tells us which job was claimed
+	for _, job := range event.Worker.Jobs {
+		if job.Status != nil &&
+			job.Status.Phase == api.JobPhasePending &&
+			job.Status.NextAttempt != nil &&
+			!job.Status.NextAttempt.After(now) {
+			return &event, job.Name, nil
+		}
+	}
+	return nil, "", errors.Errorf(
+		"event %q has no job that is due to be attempted again",
+		event.ID,
+	)
+}
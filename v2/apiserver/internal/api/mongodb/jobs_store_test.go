@@ -3,6 +3,7 @@ package mongodb
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/brigadecore/brigade/v2/apiserver/internal/api"
 	"github.com/brigadecore/brigade/v2/apiserver/internal/lib/mongodb"
@@ -10,6 +11,7 @@ import (
 	"github.com/brigadecore/brigade/v2/apiserver/internal/meta"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
@@ -146,12 +148,291 @@ func TestJobsStoreUpdateStatus(t *testing.T) {
 		{
 			name: "success",
 			collection: &mongoTesting.MockCollection{
+				UpdateOneFn: func(
+					_ context.Context,
+					_ interface{},
+					update interface{},
+					_ ...*options.UpdateOptions,
+				) (*mongo.UpdateResult, error) {
+					setFields := update.(bson.M)["$set"].(bson.M)
+					unsetFields := update.(bson.M)["$unset"].(bson.M)
+					require.Equal(
+						t,
+						string(api.JobPhaseRunning),
+						setFields["worker.jobs.$.status.phase"],
+					)
+					require.Contains(t, unsetFields, "worker.jobs.$.status.ended")
+					// Outputs must be neither set nor unset
+					require.NotContains(t, setFields, "worker.jobs.$.status.outputs")
+					require.NotContains(
+						t,
+						unsetFields,
+						"worker.jobs.$.status.outputs",
+					)
+					return &mongo.UpdateResult{
+						MatchedCount: 1,
+					}, nil
+				},
+			},
+			assertions: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			store := &jobsStore{
+				collection: testCase.collection,
+			}
+			err := store.UpdateStatus(
+				context.Background(),
+				testEvent,
+				testJobName,
+				api.JobStatus{
+					Phase: api.JobPhaseRunning,
+				},
+			)
+			testCase.assertions(err)
+		})
+	}
+}
+
+func TestJobsStoreUpdateOutputs(t *testing.T) {
+	const testEvent = "123456789"
+	const testJobName = "italian"
+	const testMaxSize = 32
+	testOutputs := map[string]string{
+		"version": "v1.0.1",
+	}
+	// mockOutputsResult returns a *mongo.SingleResult containing a projection of
+	// an Event with a single Job having the provided outputs.
+	mockOutputsResult := func(outputs interface{}) *mongo.SingleResult {
+		status := bson.M{}
+		if outputs != nil {
+			status["outputs"] = outputs
+		}
+		res, err := mongoTesting.MockSingleResult(
+			bson.M{
+				"worker": bson.M{
+					"jobs": []bson.M{
+						{
+							"status": status,
+						},
+					},
+				},
+			},
+		)
+		require.NoError(t, err)
+		return res
+	}
+	testCases := []struct {
+		name       string
+		collection mongodb.Collection
+		assertions func(err error)
+	}{
+		{
+			name: "job not found",
+			collection: &mongoTesting.MockCollection{
+				FindOneFn: func(
+					context.Context,
+					interface{},
+					...*options.FindOneOptions,
+				) *mongo.SingleResult {
+					res, err := mongoTesting.MockSingleResult(mongo.ErrNoDocuments)
+					require.NoError(t, err)
+					return res
+				},
+			},
+			assertions: func(err error) {
+				require.Error(t, err)
+				require.IsType(t, &meta.ErrNotFound{}, err)
+			},
+		},
+		{
+			name: "error finding outputs",
+			collection: &mongoTesting.MockCollection{
+				FindOneFn: func(
+					context.Context,
+					interface{},
+					...*options.FindOneOptions,
+				) *mongo.SingleResult {
+					res, err :=
+						mongoTesting.MockSingleResult(errors.New("something went wrong"))
+					require.NoError(t, err)
+					return res
+				},
+			},
+			assertions: func(err error) {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), "something went wrong")
+				require.Contains(t, err.Error(), "error finding/decoding outputs")
+			},
+		},
+		{
+			name: "outputs too large",
+			collection: &mongoTesting.MockCollection{
+				FindOneFn: func(
+					context.Context,
+					interface{},
+					...*options.FindOneOptions,
+				) *mongo.SingleResult {
+					return mockOutputsResult(
+						api.JobOutputValues{
+							"digest": "sha256:abcdef0123456789",
+						},
+					)
+				},
+			},
+			assertions: func(err error) {
+				require.Error(t, err)
+				require.IsType(t, &meta.ErrBadRequest{}, err)
+			},
+		},
+		{
+			name: "unanticipated error",
+			collection: &mongoTesting.MockCollection{
+				FindOneFn: func(
+					context.Context,
+					interface{},
+					...*options.FindOneOptions,
+				) *mongo.SingleResult {
+					return mockOutputsResult(nil)
+				},
+				UpdateOneFn: func(
+					context.Context,
+					interface{},
+					interface{},
+					...*options.UpdateOptions,
+				) (*mongo.UpdateResult, error) {
+					return nil, errors.New("something went wrong")
+				},
+			},
+			assertions: func(err error) {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), "something went wrong")
+				require.Contains(t, err.Error(), "error updating outputs of event")
+			},
+		},
+		{
+			name: "persistent concurrent modification",
+			collection: &mongoTesting.MockCollection{
+				FindOneFn: func(
+					context.Context,
+					interface{},
+					...*options.FindOneOptions,
+				) *mongo.SingleResult {
+					return mockOutputsResult(nil)
+				},
 				UpdateOneFn: func(
 					context.Context,
 					interface{},
 					interface{},
 					...*options.UpdateOptions,
 				) (*mongo.UpdateResult, error) {
+					return &mongo.UpdateResult{
+						MatchedCount: 0,
+					}, nil
+				},
+			},
+			assertions: func(err error) {
+				require.Error(t, err)
+				require.IsType(t, &meta.ErrConflict{}, err)
+			},
+		},
+		{
+			name: "success after concurrent modification",
+			collection: func() mongodb.Collection {
+				current := api.JobOutputValues{"digest": "sha256:abc"}
+				var updates int
+				return &mongoTesting.MockCollection{
+					FindOneFn: func(
+						context.Context,
+						interface{},
+						...*options.FindOneOptions,
+					) *mongo.SingleResult {
+						return mockOutputsResult(current)
+					},
+					UpdateOneFn: func(
+						_ context.Context,
+						filter interface{},
+						update interface{},
+						_ ...*options.UpdateOptions,
+					) (*mongo.UpdateResult, error) {
+						updates++
+						criteria := filter.(bson.M)["worker.jobs"].(bson.M)["$elemMatch"]
+						require.Equal(t, testJobName, criteria.(bson.M)["name"])
+						require.NotNil(t, criteria.(bson.M)["status.outputs"])
+						if updates == 1 {
+							// Another key was reported in the meantime
+							current = api.JobOutputValues{"digest": "sha256:abc", "x": "y"}
+							return &mongo.UpdateResult{
+								MatchedCount: 0,
+							}, nil
+						}
+						require.Equal(
+							t,
+							bson.M{
+								"$set": bson.M{
+									"worker.jobs.$.status.outputs": api.JobOutputValues{
+										"digest":  "sha256:abc",
+										"x":       "y",
+										"version": "v1.0.1",
+									},
+								},
+							},
+							update,
+						)
+						return &mongo.UpdateResult{
+							MatchedCount: 1,
+						}, nil
+					},
+				}
+			}(),
+			assertions: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "existing outputs with dotted keys",
+			collection: &mongoTesting.MockCollection{
+				FindOneFn: func(
+					context.Context,
+					interface{},
+					...*options.FindOneOptions,
+				) *mongo.SingleResult {
+					return mockOutputsResult(api.JobOutputValues{"a.b": "c"})
+				},
+				UpdateOneFn: func(
+					_ context.Context,
+					filter interface{},
+					update interface{},
+					_ ...*options.UpdateOptions,
+				) (*mongo.UpdateResult, error) {
+					// Outputs must be matched and set as an array of key/value
+					// documents, since MongoDB doesn't permit dots in field names
+					criteria := filter.(bson.M)["worker.jobs"].(bson.M)["$elemMatch"]
+					require.Equal(
+						t,
+						bson.A{bson.D{{Key: "key", Value: "a.b"}, {Key: "value", Value: "c"}}}, // nolint: lll
+						criteria.(bson.M)["status.outputs"],
+					)
+					outputs := update.(bson.M)["$set"].(bson.M)["worker.jobs.$.status.outputs"] // nolint: lll
+					outputsType, outputsBytes, err := bson.MarshalValue(outputs)
+					require.NoError(t, err)
+					stored := []bson.D{}
+					require.NoError(
+						t,
+						bson.RawValue{Type: outputsType, Value: outputsBytes}.
+							Unmarshal(&stored),
+					)
+					require.Equal(
+						t,
+						[]bson.D{
+							{{Key: "key", Value: "a.b"}, {Key: "value", Value: "c"}},
+							{{Key: "key", Value: "version"}, {Key: "value", Value: "v1.0.1"}},
+						},
+						stored,
+					)
 					return &mongo.UpdateResult{
 						MatchedCount: 1,
 					}, nil
@@ -167,13 +448,125 @@ func TestJobsStoreUpdateStatus(t *testing.T) {
 			store := &jobsStore{
 				collection: testCase.collection,
 			}
-			err := store.UpdateStatus(
+			err := store.UpdateOutputs(
 				context.Background(),
 				testEvent,
 				testJobName,
-				api.JobStatus{},
+				testOutputs,
+				testMaxSize,
 			)
 			testCase.assertions(err)
 		})
 	}
 }
+
+func TestJobsStoreClaimRetry(t *testing.T) {
+	now := time.Now().UTC()
+	due := now.Add(-time.Minute)
+	notDue := now.Add(time.Minute)
+	testCases := []struct {
+		name       string
+		collection mongodb.Collection
+		assertions func(*api.Event, string, error)
+	}{
+		{
+			name: "no job is due",
+			collection: &mongoTesting.MockCollection{
+				FindOneAndUpdateFn: func(
+					context.Context,
+					interface{},
+					interface{},
+					...*options.FindOneAndUpdateOptions,
+				) *mongo.SingleResult {
+					res, err := mongoTesting.MockSingleResult(mongo.ErrNoDocuments)
+					require.NoError(t, err)
+					return res
+				},
+			},
+			assertions: func(event *api.Event, _ string, err error) {
+				require.NoError(t, err)
+				require.Nil(t, event)
+			},
+		},
+		{
+			name: "unanticipated error",
+			collection: &mongoTesting.MockCollection{
+				FindOneAndUpdateFn: func(
+					context.Context,
+					interface{},
+					interface{},
+					...*options.FindOneAndUpdateOptions,
+				) *mongo.SingleResult {
+					res, err := mongoTesting.MockSingleResult(
+						errors.New("something went wrong"),
+					)
+					require.NoError(t, err)
+					return res
+				},
+			},
+			assertions: func(_ *api.Event, _ string, err error) {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), "something went wrong")
+				require.Contains(t, err.Error(), "error finding/decoding")
+			},
+		},
+		{
+			name: "job claimed",
+			collection: &mongoTesting.MockCollection{
+				FindOneAndUpdateFn: func(
+					_ context.Context,
+					_ interface{},
+					update interface{},
+					_ ...*options.FindOneAndUpdateOptions,
+				) *mongo.SingleResult {
+					require.Equal(
+						t,
+						bson.M{"worker.jobs.$.status.nextAttempt": ""},
+						update.(bson.M)["$unset"],
+					)
+					res, err := mongoTesting.MockSingleResult(
+						api.Event{
+							ObjectMeta: meta.ObjectMeta{
+								ID: "123456789",
+							},
+							Worker: api.Worker{
+								Jobs: []api.Job{
+									{
+										Name: "foo",
+										Status: &api.JobStatus{
+											Phase:       api.JobPhasePending,
+											NextAttempt: &notDue,
+										},
+									},
+									{
+										Name: "bar",
+										Status: &api.JobStatus{
+											Phase:       api.JobPhasePending,
+											NextAttempt: &due,
+										},
+									},
+								},
+							},
+						},
+					)
+					require.NoError(t, err)
+					return res
+				},
+			},
+			assertions: func(event *api.Event, jobName string, err error) {
+				require.NoError(t, err)
+				require.NotNil(t, event)
+				require.Equal(t, "123456789", event.ID)
+				require.Equal(t, "bar", jobName)
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			store := &jobsStore{
+				collection: testCase.collection,
+			}
+			testCase.assertions(store.ClaimRetry(context.Background(), now))
+		})
+	}
+}
@@ -0,0 +1,182 @@
+package mongodb
+
+import (
+	"context"
+	"log"
+	"strconv"
+
+	"github.com/brigadecore/brigade/v2/apiserver/internal/api"
+	"github.com/brigadecore/brigade/v2/apiserver/internal/lib/mongodb"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// logMigrationBatchSize is the most lines of logs that are read from MongoDB
+// and written elsewhere at once when migrating logs.
+const logMigrationBatchSize = 10000
+
+// MigrateLogs moves all logs stored in MongoDB to another cool logs store using
+// the provided api.CoolLogsWriter and returns the number of lines moved. Logs
+// are moved one container at a time, in batches of bounded size, and are
+// deleted from MongoDB only after they have been written elsewhere, so a
+// migration that is interrupted can safely be run again.
+func MigrateLogs(
+	ctx context.Context,
+	database *mongo.Database,
+	writer api.CoolLogsWriter,
+) (int, error) {
+	return migrateLogs(
+		ctx,
+		database.Collection("logs"),
+		database.Collection("events"),
+		writer,
+		logMigrationBatchSize,
+	)
+}
+
+func migrateLogs(
+	ctx context.Context,
+	logsCollection mongodb.Collection,
+	eventsCollection mongodb.Collection,
+	writer api.CoolLogsWriter,
+	batchSize int64,
+) (int, error) {
+	var migrated int
+	for {
+		// The oldest remaining line of logs identifies the next container whose
+		// logs should be moved and begins the next batch of them
+		first := logRecord{}
+		err := logsCollection.FindOne(
+			ctx,
+			bson.M{},
+			&options.FindOneOptions{
+				Sort: bson.D{{Key: "_id", Value: 1}},
+			},
+		).Decode(&first)
+		if err == mongo.ErrNoDocuments {
+			return migrated, nil
+		}
+		if err != nil {
+			return migrated, errors.Wrap(err, "error finding logs to migrate")
+		}
+
+		criteria := containerCriteria(first)
+		findOptions := &options.FindOptions{}
+		findOptions.SetSort(bson.D{{Key: "_id", Value: 1}})
+		findOptions.SetLimit(batchSize)
+		cur, err := logsCollection.Find(ctx, criteria, findOptions)
+		if err != nil {
+			return migrated, errors.Wrapf(
+				err,
+				"error finding logs for container %q of event %q",
+				first.Container,
+				first.Event,
+			)
+		}
+		records := []logRecord{}
+		if err = cur.All(ctx, &records); err != nil {
+			return migrated, errors.Wrapf(
+				err,
+				"error decoding logs for container %q of event %q",
+				first.Container,
+				first.Event,
+			)
+		}
+		if len(records) == 0 {
+			continue // The logs were deleted out from under us
+		}
+
+		projectID := first.Project
+		if projectID == "" {
+			// Logs from older versions of the log aggregator aren't labeled with a
+			// project
+			if projectID, err =
+				eventProjectID(ctx, eventsCollection, first.Event); err != nil {
+				return migrated, err
+			}
+		}
+		if projectID == "" {
+			// Without the Event, these logs couldn't be retrieved anyway
+			log.Printf(
+				"event %q no longer exists; discarding %d lines of its logs",
+				first.Event,
+				len(records),
+			)
+		} else {
+			logEntries := make([]api.LogEntry, len(records))
+			for i, record := range records {
+				logEntries[i] = record.logEntry()
+			}
+			if err = writer.WriteLogs(
+				ctx,
+				projectID,
+				first.Event,
+				selectorFromRecord(first),
+				// Named for the batch's first line so that running the migration
+				// again after an interruption overwrites rather than duplicates logs
+				first.ID.Hex(),
+				logEntries,
+			); err != nil {
+				return migrated, errors.Wrapf(
+					err,
+					"error migrating logs for container %q of event %q",
+					first.Container,
+					first.Event,
+				)
+			}
+			migrated += len(records)
+		}
+
+		// Lines beyond this batch, including any written since we found these,
+		// are left for the next iteration
+		criteria["_id"] = bson.M{"$lte": records[len(records)-1].ID}
+		if _, err = logsCollection.DeleteMany(ctx, criteria); err != nil {
+			return migrated, errors.Wrapf(
+				err,
+				"error deleting migrated logs for container %q of event %q",
+				first.Container,
+				first.Event,
+			)
+		}
+	}
+}
+
+// eventProjectID returns the ID of the Project the specified Event belongs to
+// or an empty string if the Event does not exist.
+func eventProjectID(
+	ctx context.Context,
+	eventsCollection mongodb.Collection,
+	eventID string,
+) (string, error) {
+	event := struct {
+		ProjectID string `bson:"projectID"`
+	}{}
+	err := eventsCollection.FindOne(ctx, bson.M{"id": eventID}).Decode(&event)
+	if err == mongo.ErrNoDocuments {
+		return "", nil
+	}
+	if err != nil {
+		return "", errors.Wrapf(err, "error finding event %q", eventID)
+	}
+	return event.ProjectID, nil
+}
+
+// selectorFromRecord returns an api.LogsSelector for the container that wrote
+// the provided logRecord.
+func selectorFromRecord(record logRecord) api.LogsSelector {
+	selector := api.LogsSelector{
+		Container: record.Container,
+	}
+	if record.Component == "job" {
+		selector.Job = record.Job
+		// Logs from before jobs could be attempted more than once aren't labeled
+		// with an attempt at all.
+		selector.Attempt = 1
+		if attempt, err := strconv.Atoi(record.Attempt); err == nil {
+			selector.Attempt = attempt
+		}
+	}
+	return selector
+}
@@ -0,0 +1,370 @@
+package mongodb
+
+import (
+	"context"
+	"testing"
+
+	"github.com/brigadecore/brigade/v2/apiserver/internal/api"
+	mongoTesting "github.com/brigadecore/brigade/v2/apiserver/internal/lib/mongodb/testing" // nolint: lll
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// mockLogsCollection returns a mock collection that finds and deletes logs
+// from the provided slice of logRecords, which are presumed to be in order by
+// ID. Criteria are only partially honored-- logs are selected by event and
+// container alone. Limits on the number of logs found are honored.
+func mockLogsCollection(
+	t *testing.T,
+	records *[]logRecord,
+) *mongoTesting.MockCollection {
+	selected := func(record logRecord, filter interface{}) bool {
+		criteria := filter.(bson.M)
+		if maxID, ok := criteria["_id"]; ok &&
+			record.ID.Hex() > maxID.(bson.M)["$lte"].(primitive.ObjectID).Hex() {
+			return false
+		}
+		return record.Event == criteria["event"] &&
+			record.Container == criteria["container"]
+	}
+	return &mongoTesting.MockCollection{
+		FindOneFn: func(
+			context.Context,
+			interface{},
+			...*options.FindOneOptions,
+		) *mongo.SingleResult {
+			var obj interface{} = mongo.ErrNoDocuments
+			if len(*records) > 0 {
+				obj = (*records)[0]
+			}
+			res, err := mongoTesting.MockSingleResult(obj)
+			require.NoError(t, err)
+			return res
+		},
+		FindFn: func(
+			_ context.Context,
+			filter interface{},
+			opts ...*options.FindOptions,
+		) (*mongo.Cursor, error) {
+			found := []interface{}{}
+			for _, record := range *records {
+				if len(opts) > 0 && opts[0].Limit != nil &&
+					int64(len(found)) == *opts[0].Limit {
+					break
+				}
+				if selected(record, filter) {
+					found = append(found, record)
+				}
+			}
+			cursor, err := mongoTesting.MockCursor(found...)
+			require.NoError(t, err)
+			return cursor, nil
+		},
+		DeleteManyFn: func(
+			_ context.Context,
+			filter interface{},
+			_ ...*options.DeleteOptions,
+		) (*mongo.DeleteResult, error) {
+			remaining := []logRecord{}
+			for _, record := range *records {
+				if !selected(record, filter) {
+					remaining = append(remaining, record)
+				}
+			}
+			deleted := int64(len(*records) - len(remaining))
+			*records = remaining
+			return &mongo.DeleteResult{DeletedCount: deleted}, nil
+		},
+	}
+}
+
+type mockCoolLogsWriter struct {
+	WriteLogsFn func(
+		ctx context.Context,
+		projectID string,
+		eventID string,
+		selector api.LogsSelector,
+		batchID string,
+		logEntries []api.LogEntry,
+	) error
+}
+
+func (m *mockCoolLogsWriter) WriteLogs(
+	ctx context.Context,
+	projectID string,
+	eventID string,
+	selector api.LogsSelector,
+	batchID string,
+	logEntries []api.LogEntry,
+) error {
+	return m.WriteLogsFn(ctx, projectID, eventID, selector, batchID, logEntries)
+}
+
+type writtenLogs struct {
+	projectID string
+	eventID   string
+	selector  api.LogsSelector
+	batchID   string
+	messages  []string
+}
+
+func TestMigrateLogs(t *testing.T) {
+	ids := make([]primitive.ObjectID, 4)
+	for i := range ids {
+		ids[i] = primitive.NewObjectID()
+	}
+	eventsCollection := &mongoTesting.MockCollection{
+		FindOneFn: func(
+			_ context.Context,
+			filter interface{},
+			_ ...*options.FindOneOptions,
+		) *mongo.SingleResult {
+			var obj interface{} = mongo.ErrNoDocuments
+			if filter.(bson.M)["id"] == "123456789" {
+				obj = bson.M{"id": "123456789", "projectID": "bluebook"}
+			}
+			res, err := mongoTesting.MockSingleResult(obj)
+			require.NoError(t, err)
+			return res
+		},
+	}
+
+	t.Run("success", func(t *testing.T) {
+		records := []logRecord{
+			{
+				ID:        ids[0],
+				Event:     "123456789",
+				Project:   "bluebook",
+				Component: "worker",
+				Container: "worker",
+				Message:   "foo",
+			},
+			{
+				// Older logs aren't labeled with a project or attempt
+				ID:        ids[1],
+				Event:     "123456789",
+				Component: "job",
+				Job:       "italian",
+				Container: "italian",
+				Message:   "bar",
+			},
+			{
+				ID:        ids[2],
+				Event:     "123456789",
+				Project:   "bluebook",
+				Component: "worker",
+				Container: "worker",
+				Message:   "bat",
+			},
+			{
+				// This event no longer exists
+				ID:        ids[3],
+				Event:     "987654321",
+				Component: "worker",
+				Container: "worker",
+				Message:   "baz",
+			},
+		}
+		written := []writtenLogs{}
+		migrated, err := migrateLogs(
+			context.Background(),
+			mockLogsCollection(t, &records),
+			eventsCollection,
+			&mockCoolLogsWriter{
+				WriteLogsFn: func(
+					_ context.Context,
+					projectID string,
+					eventID string,
+					selector api.LogsSelector,
+					batchID string,
+					logEntries []api.LogEntry,
+				) error {
+					messages := []string{}
+					for _, logEntry := range logEntries {
+						messages = append(messages, logEntry.Message)
+					}
+					written = append(
+						written,
+						writtenLogs{
+							projectID: projectID,
+							eventID:   eventID,
+							selector:  selector,
+							batchID:   batchID,
+							messages:  messages,
+						},
+					)
+					return nil
+				},
+			},
+			10,
+		)
+		require.NoError(t, err)
+		require.Equal(t, 3, migrated)
+		require.Empty(t, records)
+		require.Equal(
+			t,
+			[]writtenLogs{
+				{
+					projectID: "bluebook",
+					eventID:   "123456789",
+					selector:  api.LogsSelector{Container: "worker"},
+					batchID:   ids[0].Hex(),
+					messages:  []string{"foo", "bat"},
+				},
+				{
+					projectID: "bluebook",
+					eventID:   "123456789",
+					selector: api.LogsSelector{
+						Job:       "italian",
+						Container: "italian",
+						Attempt:   1,
+					},
+					batchID:  ids[1].Hex(),
+					messages: []string{"bar"},
+				},
+			},
+			written,
+		)
+	})
+
+	t.Run("in batches", func(t *testing.T) {
+		records := []logRecord{
+			{
+				ID:        ids[0],
+				Event:     "123456789",
+				Project:   "bluebook",
+				Component: "worker",
+				Container: "worker",
+				Message:   "foo",
+			},
+			{
+				ID:        ids[1],
+				Event:     "123456789",
+				Project:   "bluebook",
+				Component: "worker",
+				Container: "worker",
+				Message:   "bar",
+			},
+		}
+		batchIDs := []string{}
+		migrated, err := migrateLogs(
+			context.Background(),
+			mockLogsCollection(t, &records),
+			eventsCollection,
+			&mockCoolLogsWriter{
+				WriteLogsFn: func(
+					_ context.Context,
+					_ string,
+					_ string,
+					_ api.LogsSelector,
+					batchID string,
+					logEntries []api.LogEntry,
+				) error {
+					require.Len(t, logEntries, 1)
+					batchIDs = append(batchIDs, batchID)
+					return nil
+				},
+			},
+			1,
+		)
+		require.NoError(t, err)
+		require.Equal(t, 2, migrated)
+		require.Empty(t, records)
+		require.Equal(t, []string{ids[0].Hex(), ids[1].Hex()}, batchIDs)
+	})
+
+	t.Run("error writing logs", func(t *testing.T) {
+		records := []logRecord{
+			{
+				ID:        ids[0],
+				Event:     "123456789",
+				Project:   "bluebook",
+				Component: "worker",
+				Container: "worker",
+				Message:   "foo",
+			},
+		}
+		_, err := migrateLogs(
+			context.Background(),
+			mockLogsCollection(t, &records),
+			eventsCollection,
+			&mockCoolLogsWriter{
+				WriteLogsFn: func(
+					context.Context,
+					string,
+					string,
+					api.LogsSelector,
+					string,
+					[]api.LogEntry,
+				) error {
+					return errors.New("something went wrong")
+				},
+			},
+			10,
+		)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "something went wrong")
+		require.Contains(t, err.Error(), "error migrating logs")
+		// Nothing should have been deleted
+		require.Len(t, records, 1)
+	})
+}
+
+func TestSelectorFromRecord(t *testing.T) {
+	testCases := []struct {
+		name             string
+		record           logRecord
+		expectedSelector api.LogsSelector
+	}{
+		{
+			name: "worker",
+			record: logRecord{
+				Component: "worker",
+				Container: "vcs",
+			},
+			expectedSelector: api.LogsSelector{
+				Container: "vcs",
+			},
+		},
+		{
+			name: "job without attempt",
+			record: logRecord{
+				Component: "job",
+				Job:       "italian",
+				Container: "italian",
+			},
+			expectedSelector: api.LogsSelector{
+				Job:       "italian",
+				Container: "italian",
+				Attempt:   1,
+			},
+		},
+		{
+			name: "job with attempt",
+			record: logRecord{
+				Component: "job",
+				Job:       "italian",
+				Attempt:   "3",
+				Container: "helper",
+			},
+			expectedSelector: api.LogsSelector{
+				Job:       "italian",
+				Container: "helper",
+				Attempt:   3,
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			require.Equal(
+				t,
+				testCase.expectedSelector,
+				selectorFromRecord(testCase.record),
+			)
+		})
+	}
+}
@@ -3,15 +3,24 @@ package mongodb
 import (
 	"context"
 	"log"
+	"strconv"
+	"time"
 
 	"github.com/brigadecore/brigade/v2/apiserver/internal/api"
 	"github.com/brigadecore/brigade/v2/apiserver/internal/lib/mongodb"
 	"github.com/pkg/errors"
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
+// maxLogSearchTime bounds how long MongoDB may spend on any one query made in
+// the course of a log search. Search patterns are user-supplied and MongoDB's
+// regular expression engine backtracks, so a pathological pattern could
+// otherwise tie up the database indefinitely.
+const maxLogSearchTime = 30 * time.Second
+
 // logsStore is a MongoDB-based implementation of the api.LogsStore interface.
 type logsStore struct {
 	collection mongodb.Collection
@@ -36,16 +45,45 @@ func (l *logsStore) StreamLogs(
 	opts api.LogStreamOptions,
 ) (<-chan api.LogEntry, error) {
 	criteria := criteriaFromSelector(event.ID, selector)
+	if timeCriteria := criteriaFromTimeRange(opts); timeCriteria != nil {
+		criteria["time"] = timeCriteria
+	}
+	findOptions := &options.FindOptions{}
+	if opts.TailLines > 0 {
+		// Find the most recent lines by traversing the collection in reverse. They
+		// are put back in order before they are sent.
+		findOptions.SetSort(bson.D{{Key: "$natural", Value: -1}})
+		findOptions.SetLimit(opts.TailLines)
+	}
 
 	logEntryCh := make(chan api.LogEntry)
 	go func() {
 		defer close(logEntryCh)
 
-		cur, err := l.collection.Find(
-			ctx,
-			criteria,
-			&options.FindOptions{},
-		)
+		cur, err := l.collection.Find(ctx, criteria, findOptions)
+		if err != nil {
+			log.Println(errors.Wrapf(err, "error finding log entries"))
+			return
+		}
+		defer cur.Close(ctx)
+
+		if opts.TailLines > 0 {
+			logEntries := []api.LogEntry{}
+			if err = cur.All(ctx, &logEntries); err != nil {
+				log.Println(
+					errors.Wrapf(err, "error decoding log entries from collection"),
+				)
+				return
+			}
+			for i := len(logEntries) - 1; i >= 0; i-- {
+				select {
+				case logEntryCh <- logEntries[i]:
+				case <-ctx.Done():
+					return
+				}
+			}
+			return
+		}
 
 		for cur.Next(ctx) {
 			logEntry := api.LogEntry{}
@@ -80,24 +118,220 @@ func criteriaFromSelector(
 	} else { // We want job logs
 		criteria["component"] = "job"
 		criteria["job"] = selector.Job
+		if selector.Attempt > 1 {
+			criteria["attempt"] = strconv.Itoa(selector.Attempt)
+		} else {
+			// Logs from before jobs could be attempted more than once aren't
+			// labeled with an attempt at all.
+			criteria["attempt"] = bson.M{
+				"$in": bson.A{"1", "", nil},
+			}
+		}
 	}
 	criteria["container"] = selector.Container
 	return criteria
 }
 
+// criteriaFromTimeRange returns criteria for selecting log entries written
+// within the time range specified by the provided api.LogStreamOptions or nil
+// if no time range is specified.
+func criteriaFromTimeRange(opts api.LogStreamOptions) bson.M {
+	if opts.Since == nil && opts.Until == nil {
+		return nil
+	}
+	criteria := bson.M{}
+	if opts.Since != nil {
+		criteria["$gte"] = *opts.Since
+	}
+	if opts.Until != nil {
+		criteria["$lte"] = *opts.Until
+	}
+	return criteria
+}
+
+// logRecord represents a line of logs as stored by the log aggregator, along
+// with details of where it came from.
+type logRecord struct {
+	ID        primitive.ObjectID `bson:"_id"`
+	Event     string             `bson:"event"`
+	Project   string             `bson:"project,omitempty"`
+	Component string             `bson:"component"`
+	Job       string             `bson:"job,omitempty"`
+	Attempt   string             `bson:"attempt,omitempty"`
+	Container string             `bson:"container"`
+	Time      *time.Time         `bson:"time,omitempty"`
+	Message   string             `bson:"log,omitempty"`
+}
+
+func (l *logRecord) logEntry() api.LogEntry {
+	return api.LogEntry{
+		Time:    l.Time,
+		Message: l.Message,
+	}
+}
+
+func (l *logsStore) SearchLogs(
+	ctx context.Context,
+	event api.Event,
+	expression string,
+	opts api.LogSearchOptions,
+) ([]api.LogSearchMatch, error) {
+	findOptions := &options.FindOptions{}
+	findOptions.SetSort(bson.D{{Key: "_id", Value: 1}})
+	findOptions.SetLimit(int64(opts.Limit))
+	findOptions.SetMaxTime(maxLogSearchTime)
+	cur, err := l.collection.Find(
+		ctx,
+		bson.M{
+			"event": event.ID,
+			"log":   bson.M{"$regex": expression},
+		},
+		findOptions,
+	)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error searching logs for event %q", event.ID)
+	}
+	records := []logRecord{}
+	if err = cur.All(ctx, &records); err != nil {
+		return nil, errors.Wrapf(
+			err,
+			"error decoding logs for event %q",
+			event.ID,
+		)
+	}
+	matches := make([]api.LogSearchMatch, len(records))
+	for i, record := range records {
+		matches[i] = api.LogSearchMatch{
+			EventID:   record.Event,
+			Container: record.Container,
+			LogEntry:  record.logEntry(),
+		}
+		if record.Component == "job" {
+			matches[i].Job = record.Job
+			matches[i].Attempt = 1
+			if attempt, err := strconv.Atoi(record.Attempt); err == nil {
+				matches[i].Attempt = attempt
+			}
+		}
+		if opts.ContextLines == 0 {
+			continue
+		}
+		if matches[i].Before, err = l.findContext(
+			ctx,
+			record,
+			true,
+			opts.ContextLines,
+		); err != nil {
+			return nil, err
+		}
+		if matches[i].After, err = l.findContext(
+			ctx,
+			record,
+			false,
+			opts.ContextLines,
+		); err != nil {
+			return nil, err
+		}
+	}
+	return matches, nil
+}
+
+// findContext returns up to the specified number of lines of logs written by
+// the same container immediately before or after the provided logRecord.
+func (l *logsStore) findContext(
+	ctx context.Context,
+	record logRecord,
+	before bool,
+	lines int,
+) ([]api.LogEntry, error) {
+	findOptions := &options.FindOptions{}
+	sortOrder := 1
+	if before {
+		sortOrder = -1
+	}
+	findOptions.SetSort(bson.D{{Key: "_id", Value: sortOrder}})
+	findOptions.SetLimit(int64(lines))
+	findOptions.SetMaxTime(maxLogSearchTime)
+	cur, err := l.collection.Find(
+		ctx,
+		contextCriteria(record, before),
+		findOptions,
+	)
+	if err != nil {
+		return nil, errors.Wrapf(
+			err,
+			"error finding context for log entry %q",
+			record.ID.Hex(),
+		)
+	}
+	records := []logRecord{}
+	if err = cur.All(ctx, &records); err != nil {
+		return nil, errors.Wrapf(
+			err,
+			"error decoding context for log entry %q",
+			record.ID.Hex(),
+		)
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+	logEntries := make([]api.LogEntry, len(records))
+	for i, contextRecord := range records {
+		if before { // These were found in reverse order
+			logEntries[len(records)-1-i] = contextRecord.logEntry()
+		} else {
+			logEntries[i] = contextRecord.logEntry()
+		}
+	}
+	return logEntries, nil
+}
+
+// contextCriteria returns criteria for selecting lines of logs written by the
+// same container before or after the provided logRecord.
+func contextCriteria(record logRecord, before bool) bson.M {
+	criteria := containerCriteria(record)
+	if before {
+		criteria["_id"] = bson.M{"$lt": record.ID}
+	} else {
+		criteria["_id"] = bson.M{"$gt": record.ID}
+	}
+	return criteria
+}
+
+// containerCriteria returns criteria for selecting lines of logs written by the
+// same container as the provided logRecord.
+func containerCriteria(record logRecord) bson.M {
+	criteria := bson.M{
+		"event":     record.Event,
+		"component": record.Component,
+		"container": record.Container,
+	}
+	if record.Component == "job" {
+		criteria["job"] = record.Job
+		if record.Attempt == "" {
+			// Logs from before jobs could be attempted more than once aren't
+			// labeled with an attempt at all.
+			criteria["attempt"] = bson.M{"$in": bson.A{"", nil}}
+		} else {
+			criteria["attempt"] = record.Attempt
+		}
+	}
+	return criteria
+}
+
 // DeleteEventLogs deletes all logs associated with the provided event from the
 // underlying mongo store.
 func (l *logsStore) DeleteEventLogs(
 	ctx context.Context,
-	id string,
+	event api.Event,
 ) error {
 	if _, err := l.collection.DeleteMany(
 		ctx,
 		bson.M{
-			"event": id,
+			"event": event.ID,
 		},
 	); err != nil {
-		return errors.Wrapf(err, "error deleting logs for event %q", id)
+		return errors.Wrapf(err, "error deleting logs for event %q", event.ID)
 	}
 	return nil
 }
@@ -2,10 +2,12 @@ package mongodb
 
 import (
 	"testing"
+	"time"
 
 	"github.com/brigadecore/brigade/v2/apiserver/internal/api"
 	"github.com/stretchr/testify/require"
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 // TODO: This is very difficult to test in isolation. The implementation of the
@@ -55,6 +57,26 @@ func TestCriteriaFromSelector(t *testing.T) {
 				"event":     testEventID,
 				"component": "job",
 				"job":       testJobName,
+				"attempt": bson.M{
+					"$in": bson.A{"1", "", nil},
+				},
+				"container": testContainerName,
+			},
+		},
+		{
+			name: "job attempt specified",
+			selector: api.LogsSelector{
+				Job: testJobName,
+				// The service layer will ALWAYS have set this field if it wasn't set
+				// already.
+				Container: testContainerName,
+				Attempt:   2,
+			},
+			expectedCriteria: bson.M{
+				"event":     testEventID,
+				"component": "job",
+				"job":       testJobName,
+				"attempt":   "2",
 				"container": testContainerName,
 			},
 		},
@@ -66,3 +88,125 @@ func TestCriteriaFromSelector(t *testing.T) {
 		})
 	}
 }
+
+func TestCriteriaFromTimeRange(t *testing.T) {
+	testSince := time.Date(2021, time.December, 1, 12, 0, 0, 0, time.UTC)
+	testUntil := testSince.Add(time.Hour)
+	testCases := []struct {
+		name             string
+		opts             api.LogStreamOptions
+		expectedCriteria bson.M
+	}{
+		{
+			name:             "no time range specified",
+			opts:             api.LogStreamOptions{},
+			expectedCriteria: nil,
+		},
+		{
+			name: "only since specified",
+			opts: api.LogStreamOptions{
+				Since: &testSince,
+			},
+			expectedCriteria: bson.M{
+				"$gte": testSince,
+			},
+		},
+		{
+			name: "since and until specified",
+			opts: api.LogStreamOptions{
+				Since: &testSince,
+				Until: &testUntil,
+			},
+			expectedCriteria: bson.M{
+				"$gte": testSince,
+				"$lte": testUntil,
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			require.Equal(
+				t,
+				testCase.expectedCriteria,
+				criteriaFromTimeRange(testCase.opts),
+			)
+		})
+	}
+}
+
+func TestContextCriteria(t *testing.T) {
+	testID := primitive.NewObjectID()
+	testCases := []struct {
+		name             string
+		record           logRecord
+		before           bool
+		expectedCriteria bson.M
+	}{
+		{
+			name: "worker logs before",
+			record: logRecord{
+				ID:        testID,
+				Event:     "123456789",
+				Component: "worker",
+				Container: "worker",
+			},
+			before: true,
+			expectedCriteria: bson.M{
+				"event":     "123456789",
+				"component": "worker",
+				"container": "worker",
+				"_id":       bson.M{"$lt": testID},
+			},
+		},
+		{
+			name: "job logs without attempt after",
+			record: logRecord{
+				ID:        testID,
+				Event:     "123456789",
+				Component: "job",
+				Job:       "italian",
+				Container: "italian",
+			},
+			before: false,
+			expectedCriteria: bson.M{
+				"event":     "123456789",
+				"component": "job",
+				"job":       "italian",
+				"attempt": bson.M{
+					"$in": bson.A{"", nil},
+				},
+				"container": "italian",
+				"_id":       bson.M{"$gt": testID},
+			},
+		},
+		{
+			name: "job logs with attempt after",
+			record: logRecord{
+				ID:        testID,
+				Event:     "123456789",
+				Component: "job",
+				Job:       "italian",
+				Attempt:   "2",
+				Container: "italian",
+			},
+			before: false,
+			expectedCriteria: bson.M{
+				"event":     "123456789",
+				"component": "job",
+				"job":       "italian",
+				"attempt":   "2",
+				"container": "italian",
+				"_id":       bson.M{"$gt": testID},
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			require.Equal(
+				t,
+				testCase.expectedCriteria,
+				contextCriteria(testCase.record, testCase.before),
+			)
+		})
+	}
+}
@@ -2,7 +2,6 @@ package mongodb
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 
 	"github.com/brigadecore/brigade/v2/apiserver/internal/api"
@@ -121,81 +120,11 @@ func (p *projectsStore) ListSubscribers(
 	event api.Event,
 ) (meta.List[api.Project], error) {
 	projects := meta.List[api.Project]{}
-	// Finding all Projects that are subscribed to a given Event is rather tricky.
-	// Matching on the basis of source, type, and qualifiers is easy enough, but
-	// LABELS account for the difficulty. If we were selecting Events, it's easy
-	// enough to say "select all events that have (at least) these labels," but
-	// when selecting PROJECTS, we're effectively working in reverse. We want to
-	// select all Projects having an EventSubscription that matches the Event's
-	// source, type, and qualifiers, AND doesn't have any labels that PRECLUDE a
-	// match. The only practical way of expressing those criteria in a MongoDB
-	// query is to provide a JavaScript function as a "where" clause. That
-	// function contains the logic for determining whether a SINGLE document
-	// (Project) is a match for the Event. For a large number of Projects, such a
-	// query is wildly inefficient, since the function needs to be applied to
-	// every single document (Project). To compensate, we build PRELIMINARY match
-	// criteria based on matching source, type, and qualifiers. MongoDB sensibly
-	// applies these criteria FIRST, then iterates over the preliminary results
-	// only, applying the provided "where" function to each in turn.
-	preliminaryMatchCriteria := bson.M{
-		"source": event.Source,
-		"types": bson.M{
-			"$in": []string{event.Type, "*"},
-		},
-	}
-	if len(event.Qualifiers) > 0 {
-		preliminaryMatchCriteria["qualifiers"] = event.Qualifiers
-	}
-	eventJSON, err := json.Marshal(
-		struct {
-			Source     string            `json:"source"`
-			Type       string            `json:"type"`
-			Qualifiers api.Qualifiers    `json:"qualifiers"`
-			Labels     map[string]string `json:"labels"`
-		}{
-			Source:     event.Source,
-			Type:       event.Type,
-			Qualifiers: event.Qualifiers,
-			Labels:     event.Labels,
-		},
-	)
-	// nolint: lll
-	where := fmt.Sprintf(`
-function() {
-	const project = this;
-	if (!project.spec.eventSubscriptions) {
-		return false;
-	}
-	const event = %s;
-	event.qualifiers = event.qualifiers || {};
-	event.labels = event.labels || {};
-	loop:
-	for (const subscription of project.spec.eventSubscriptions) {
-		subscription.qualifiers = subscription.qualifiers || {};
-		subscription.labels = subscription.labels || {};
-		if (subscription.source != event.source) continue;
-		if (!subscription.types.includes(event.type) && !subscription.types.includes("*")) continue; 
-		for (const key in event.qualifiers) {
-			if (subscription.qualifiers[key] != event.qualifiers[key]) continue loop;
-		}
-		for (const key in subscription.qualifiers) {
-			if (subscription.qualifiers[key] != event.qualifiers[key]) continue loop;
-		}
-		const eventLabelKeys = Object.keys(event.labels);
-		for (const labelKey in subscription.labels) {
-			if (!eventLabelKeys.includes(labelKey)) {
-				continue loop;
-			}
-			if (subscription.labels[labelKey] != event.labels[labelKey]) {
-				continue loop;
-			}
-		}
-		return true;
-	}
-	return false;
-}`,
-		eventJSON,
-	)
+	// Only source and type are matched here. Qualifiers and labels may be
+	// matched using glob or regex patterns and subscriptions may also include
+	// filter expressions, none of which are practical to evaluate in a MongoDB
+	// query. The caller is responsible for winnowing these candidates down to
+	// those Projects that are actually subscribed to the Event.
 	findOptions := options.Find()
 	findOptions.SetSort(
 		// bson.D preserves order so we use this wherever we sort so that if
@@ -207,9 +136,13 @@ function() {
 	)
 	query := bson.M{
 		"spec.eventSubscriptions": bson.M{
-			"$elemMatch": preliminaryMatchCriteria,
+			"$elemMatch": bson.M{
+				"source": event.Source,
+				"types": bson.M{
+					"$in": []string{event.Type, "*"},
+				},
+			},
 		},
-		"$where": where,
 	}
 	if event.ProjectID != "" {
 		query["id"] = event.ProjectID
@@ -224,6 +157,35 @@ function() {
 	return projects, nil
 }
 
+func (p *projectsStore) ListScheduled(
+	ctx context.Context,
+) (meta.List[api.Project], error) {
+	projects := meta.List[api.Project]{}
+	findOptions := options.Find()
+	findOptions.SetSort(
+		// bson.D preserves order so we use this wherever we sort so that if
+		// additional sort criteria are added in the future, they will be applied
+		// in the specified order.
+		bson.D{
+			{Key: "id", Value: 1},
+		},
+	)
+	cur, err := p.collection.Find(
+		ctx,
+		bson.M{
+			"spec.schedules.0": bson.M{"$exists": true},
+		},
+		findOptions,
+	)
+	if err != nil {
+		return projects, errors.Wrap(err, "error finding projects")
+	}
+	if err := cur.All(ctx, &projects.Items); err != nil {
+		return projects, errors.Wrap(err, "error decoding projects")
+	}
+	return projects, nil
+}
+
 func (p *projectsStore) Get(
 	ctx context.Context,
 	id string,
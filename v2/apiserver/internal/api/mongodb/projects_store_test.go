@@ -289,6 +289,75 @@ func TestProjectsStoreListSubscribers(t *testing.T) {
 	}
 }
 
+func TestProjectsStoreListScheduled(t *testing.T) {
+	testProject := api.Project{
+		ObjectMeta: meta.ObjectMeta{
+			ID: "project1",
+		},
+		Spec: api.ProjectSpec{
+			Schedules: []api.EventSchedule{
+				{
+					Name: "nightly",
+					Cron: "@daily",
+					Type: "build",
+				},
+			},
+		},
+	}
+	testCases := []struct {
+		name       string
+		collection mongodb.Collection
+		assertions func(projects meta.List[api.Project], err error)
+	}{
+		{
+			name: "error finding projects",
+			collection: &mongoTesting.MockCollection{
+				FindFn: func(
+					ctx context.Context,
+					filter interface{},
+					opts ...*options.FindOptions,
+				) (*mongo.Cursor, error) {
+					return nil, errors.New("something went wrong")
+				},
+			},
+			assertions: func(projects meta.List[api.Project], err error) {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), "something went wrong")
+				require.Contains(t, err.Error(), "error finding projects")
+			},
+		},
+
+		{
+			name: "found projects",
+			collection: &mongoTesting.MockCollection{
+				FindFn: func(
+					ctx context.Context,
+					filter interface{},
+					opts ...*options.FindOptions,
+				) (*mongo.Cursor, error) {
+					cursor, err := mongoTesting.MockCursor(testProject)
+					require.NoError(t, err)
+					return cursor, nil
+				},
+			},
+			assertions: func(projects meta.List[api.Project], err error) {
+				require.NoError(t, err)
+				require.Len(t, projects.Items, 1)
+				require.Equal(t, testProject.Spec, projects.Items[0].Spec)
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			store := &projectsStore{
+				collection: testCase.collection,
+			}
+			projects, err := store.ListScheduled(context.Background())
+			testCase.assertions(projects, err)
+		})
+	}
+}
+
 func TestProjectsStoreGet(t *testing.T) {
 	const testProjectID = "blue-book"
 	testCases := []struct {
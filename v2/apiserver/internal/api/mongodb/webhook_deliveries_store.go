@@ -0,0 +1,237 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/brigadecore/brigade/v2/apiserver/internal/api"
+	"github.com/brigadecore/brigade/v2/apiserver/internal/lib/mongodb"
+	"github.com/brigadecore/brigade/v2/apiserver/internal/meta"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// webhookDeliveryRetention is how long WebhookDeliveries are retained after
+// their creation. They exist only to aid in debugging, so there is no reason to
+// keep them for very long.
+const webhookDeliveryRetention = 7 * 24 * time.Hour
+
+// webhookDeliveriesStore is a MongoDB-based implementation of the
+// api.WebhookDeliveriesStore interface.
+type webhookDeliveriesStore struct {
+	collection mongodb.Collection
+}
+
+// NewWebhookDeliveriesStore returns a MongoDB-based implementation of the
+// api.WebhookDeliveriesStore interface.
+func NewWebhookDeliveriesStore(
+	database *mongo.Database,
+) (api.WebhookDeliveriesStore, error) {
+	ctx, cancel :=
+		context.WithTimeout(context.Background(), createIndexTimeout)
+	defer cancel()
+	unique := true
+	expireAfterSeconds := int32(webhookDeliveryRetention.Seconds())
+	collection := database.Collection("webhook-deliveries")
+	if _, err := collection.Indexes().CreateMany(
+		ctx,
+		[]mongo.IndexModel{
+			{
+				Keys: bson.M{
+					"id": 1,
+				},
+				Options: &options.IndexOptions{
+					Unique: &unique,
+				},
+			},
+			// This index supports listing a project's webhook deliveries.
+			{
+				Keys: bson.D{
+					{Key: "projectID", Value: 1},
+					{Key: "created", Value: -1},
+				},
+			},
+			// This index supports finding webhook deliveries that are due.
+			{
+				Keys: bson.D{
+					{Key: "status.phase", Value: 1},
+					{Key: "status.nextAttempt", Value: 1},
+				},
+			},
+			// This index automatically deletes old webhook deliveries.
+			{
+				Keys: bson.M{
+					"created": 1,
+				},
+				Options: &options.IndexOptions{
+					ExpireAfterSeconds: &expireAfterSeconds,
+				},
+			},
+		},
+	); err != nil {
+		return nil, errors.Wrap(
+			err,
+			"error adding indexes to webhook deliveries collection",
+		)
+	}
+	return &webhookDeliveriesStore{
+		collection: collection,
+	}, nil
+}
+
+func (w *webhookDeliveriesStore) Create(
+	ctx context.Context,
+	delivery api.WebhookDelivery,
+) error {
+	if _, err := w.collection.InsertOne(ctx, delivery); err != nil {
+		return errors.Wrapf(
+			err,
+			"error inserting new webhook delivery %q",
+			delivery.ID,
+		)
+	}
+	return nil
+}
+
+func (w *webhookDeliveriesStore) List(
+	ctx context.Context,
+	selector api.WebhookDeliveriesSelector,
+	opts meta.ListOptions,
+) (meta.List[api.WebhookDelivery], error) {
+	deliveries := meta.List[api.WebhookDelivery]{}
+
+	criteria := bson.M{
+		"projectID": selector.ProjectID,
+	}
+	if selector.EventID != "" {
+		criteria["notification.eventID"] = selector.EventID
+	}
+	if selector.Webhook != "" {
+		criteria["webhook"] = selector.Webhook
+	}
+	if opts.Continue != "" {
+		tokens := strings.Split(opts.Continue, ":")
+		if len(tokens) != 2 {
+			return deliveries, errors.New("error parsing continue time")
+		}
+		continueTimeNano, err := strconv.ParseInt(tokens[0], 10, 64)
+		if err != nil {
+			return deliveries, errors.Wrap(err, "error parsing continue time")
+		}
+		continueTime := time.Unix(0, continueTimeNano).UTC()
+		continueID := tokens[1]
+		criteria["$or"] = []bson.M{
+			{"created": continueTime, "id": bson.M{"$gt": continueID}},
+			{"created": bson.M{"$lt": continueTime}},
+		}
+	}
+
+	findOptions := options.Find()
+	findOptions.SetSort(
+		// bson.D preserves order, and we want to sort by created date/time FIRST
+		// and id SECOND
+		bson.D{
+			{Key: "created", Value: -1},
+			{Key: "id", Value: 1},
+		},
+	)
+	findOptions.SetLimit(opts.Limit)
+	cur, err := w.collection.Find(ctx, criteria, findOptions)
+	if err != nil {
+		return deliveries, errors.Wrap(err, "error finding webhook deliveries")
+	}
+	if err := cur.All(ctx, &deliveries.Items); err != nil {
+		return deliveries, errors.Wrap(err, "error decoding webhook deliveries")
+	}
+
+	if deliveries.Len() == opts.Limit {
+		continueTime := deliveries.Items[opts.Limit-1].Created
+		continueID := deliveries.Items[opts.Limit-1].ID
+		criteria["$or"] = []bson.M{
+			{"created": continueTime, "id": bson.M{"$gt": continueID}},
+			{"created": bson.M{"$lt": continueTime}},
+		}
+		remaining, err := w.collection.CountDocuments(ctx, criteria)
+		if err != nil {
+			return deliveries,
+				errors.Wrap(err, "error counting remaining webhook deliveries")
+		}
+		if remaining > 0 {
+			deliveries.Continue =
+				fmt.Sprintf("%d:%s", continueTime.UnixNano(), continueID)
+			deliveries.RemainingItemCount = remaining
+		}
+	}
+
+	return deliveries, nil
+}
+
+func (w *webhookDeliveriesStore) Claim(
+	ctx context.Context,
+	now time.Time,
+	leaseExpiry time.Time,
+) (*api.WebhookDelivery, error) {
+	res := w.collection.FindOneAndUpdate(
+		ctx,
+		bson.M{
+			"status.phase": api.WebhookDeliveryPhasePending,
+			"status.nextAttempt": bson.M{
+				"$lte": now,
+			},
+		},
+		bson.M{
+			"$set": bson.M{
+				"status.nextAttempt": leaseExpiry,
+			},
+		},
+		options.FindOneAndUpdate().SetSort(
+			bson.M{
+				"status.nextAttempt": 1,
+			},
+		),
+	)
+	delivery := api.WebhookDelivery{}
+	err := res.Decode(&delivery)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "error finding/decoding webhook delivery")
+	}
+	return &delivery, nil
+}
+
+func (w *webhookDeliveriesStore) UpdateStatus(
+	ctx context.Context,
+	id string,
+	status api.WebhookDeliveryStatus,
+) error {
+	res, err := w.collection.UpdateOne(
+		ctx,
+		bson.M{"id": id},
+		bson.M{
+			"$set": bson.M{
+				"status": status,
+			},
+		},
+	)
+	if err != nil {
+		return errors.Wrapf(
+			err,
+			"error updating status of webhook delivery %q",
+			id,
+		)
+	}
+	if res.MatchedCount == 0 {
+		return &meta.ErrNotFound{
+			Type: api.WebhookDeliveryKind,
+			ID:   id,
+		}
+	}
+	return nil
+}
@@ -0,0 +1,370 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/brigadecore/brigade/v2/apiserver/internal/api"
+	"github.com/brigadecore/brigade/v2/apiserver/internal/lib/mongodb"
+	mongoTesting "github.com/brigadecore/brigade/v2/apiserver/internal/lib/mongodb/testing" // nolint: lll
+	"github.com/brigadecore/brigade/v2/apiserver/internal/meta"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func TestWebhookDeliveriesStoreCreate(t *testing.T) {
+	testDelivery := api.WebhookDelivery{
+		ObjectMeta: meta.ObjectMeta{
+			ID: "123456789",
+		},
+	}
+	testCases := []struct {
+		name       string
+		collection mongodb.Collection
+		assertions func(error)
+	}{
+		{
+			name: "unanticipated error",
+			collection: &mongoTesting.MockCollection{
+				InsertOneFn: func(
+					context.Context,
+					interface{},
+					...*options.InsertOneOptions,
+				) (*mongo.InsertOneResult, error) {
+					return nil, errors.New("something went wrong")
+				},
+			},
+			assertions: func(err error) {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), "something went wrong")
+				require.Contains(t, err.Error(), "error inserting new webhook delivery")
+			},
+		},
+
+		{
+			name: "successful creation",
+			collection: &mongoTesting.MockCollection{
+				InsertOneFn: func(
+					context.Context,
+					interface{},
+					...*options.InsertOneOptions,
+				) (*mongo.InsertOneResult, error) {
+					return nil, nil
+				},
+			},
+			assertions: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			store := &webhookDeliveriesStore{
+				collection: testCase.collection,
+			}
+			testCase.assertions(store.Create(context.Background(), testDelivery))
+		})
+	}
+}
+
+func TestWebhookDeliveriesStoreList(t *testing.T) {
+	const testProjectID = "blue-book"
+	now := time.Now().UTC()
+	testDelivery := api.WebhookDelivery{
+		ObjectMeta: meta.ObjectMeta{
+			ID:      "foo",
+			Created: &now,
+		},
+	}
+	testCases := []struct {
+		name        string
+		listOptions meta.ListOptions
+		collection  mongodb.Collection
+		assertions  func(meta.List[api.WebhookDelivery], error)
+	}{
+		{
+			name: "unparsable continue value",
+			listOptions: meta.ListOptions{
+				Continue: "invalid time",
+			},
+			assertions: func(_ meta.List[api.WebhookDelivery], err error) {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), "error parsing continue time")
+			},
+		},
+
+		{
+			name: "error finding webhook deliveries",
+			collection: &mongoTesting.MockCollection{
+				FindFn: func(
+					context.Context,
+					interface{},
+					...*options.FindOptions,
+				) (*mongo.Cursor, error) {
+					return nil, errors.New("something went wrong")
+				},
+			},
+			assertions: func(_ meta.List[api.WebhookDelivery], err error) {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), "something went wrong")
+				require.Contains(t, err.Error(), "error finding webhook deliveries")
+			},
+		},
+
+		{
+			name: "webhook deliveries found; more pages of results exist",
+			listOptions: meta.ListOptions{
+				Limit: 1,
+			},
+			collection: &mongoTesting.MockCollection{
+				FindFn: func(
+					_ context.Context,
+					filter interface{},
+					_ ...*options.FindOptions,
+				) (*mongo.Cursor, error) {
+					criteria := filter.(bson.M)
+					require.Equal(t, testProjectID, criteria["projectID"])
+					require.Equal(t, "tunguska", criteria["notification.eventID"])
+					require.Equal(t, "slack", criteria["webhook"])
+					cursor, err := mongoTesting.MockCursor(testDelivery)
+					require.NoError(t, err)
+					return cursor, nil
+				},
+				CountDocumentsFn: func(
+					context.Context,
+					interface{},
+					...*options.CountOptions,
+				) (int64, error) {
+					return 5, nil
+				},
+			},
+			assertions: func(
+				deliveries meta.List[api.WebhookDelivery],
+				err error,
+			) {
+				require.NoError(t, err)
+				require.Len(t, deliveries.Items, 1)
+				require.Equal(t, testDelivery.ID, deliveries.Items[0].ID)
+				require.Equal(
+					t,
+					fmt.Sprintf(
+						"%d:%s",
+						deliveries.Items[0].Created.UnixNano(),
+						testDelivery.ID,
+					),
+					deliveries.Continue,
+				)
+				require.Equal(t, int64(5), deliveries.RemainingItemCount)
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			store := &webhookDeliveriesStore{
+				collection: testCase.collection,
+			}
+			deliveries, err := store.List(
+				context.Background(),
+				api.WebhookDeliveriesSelector{
+					ProjectID: testProjectID,
+					EventID:   "tunguska",
+					Webhook:   "slack",
+				},
+				testCase.listOptions,
+			)
+			testCase.assertions(deliveries, err)
+		})
+	}
+}
+
+func TestWebhookDeliveriesStoreClaim(t *testing.T) {
+	now := time.Now().UTC()
+	leaseExpiry := now.Add(time.Minute)
+	testCases := []struct {
+		name       string
+		collection mongodb.Collection
+		assertions func(*api.WebhookDelivery, error)
+	}{
+		{
+			name: "no webhook delivery is due",
+			collection: &mongoTesting.MockCollection{
+				FindOneAndUpdateFn: func(
+					context.Context,
+					interface{},
+					interface{},
+					...*options.FindOneAndUpdateOptions,
+				) *mongo.SingleResult {
+					res, err := mongoTesting.MockSingleResult(mongo.ErrNoDocuments)
+					require.NoError(t, err)
+					return res
+				},
+			},
+			assertions: func(delivery *api.WebhookDelivery, err error) {
+				require.NoError(t, err)
+				require.Nil(t, delivery)
+			},
+		},
+
+		{
+			name: "unanticipated error",
+			collection: &mongoTesting.MockCollection{
+				FindOneAndUpdateFn: func(
+					context.Context,
+					interface{},
+					interface{},
+					...*options.FindOneAndUpdateOptions,
+				) *mongo.SingleResult {
+					res, err := mongoTesting.MockSingleResult(
+						errors.New("something went wrong"),
+					)
+					require.NoError(t, err)
+					return res
+				},
+			},
+			assertions: func(_ *api.WebhookDelivery, err error) {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), "something went wrong")
+				require.Contains(t, err.Error(), "error finding/decoding")
+			},
+		},
+
+		{
+			name: "webhook delivery claimed",
+			collection: &mongoTesting.MockCollection{
+				FindOneAndUpdateFn: func(
+					_ context.Context,
+					filter interface{},
+					update interface{},
+					_ ...*options.FindOneAndUpdateOptions,
+				) *mongo.SingleResult {
+					require.Equal(
+						t,
+						api.WebhookDeliveryPhasePending,
+						filter.(bson.M)["status.phase"],
+					)
+					require.Equal(
+						t,
+						bson.M{"status.nextAttempt": leaseExpiry},
+						update.(bson.M)["$set"],
+					)
+					res, err := mongoTesting.MockSingleResult(
+						api.WebhookDelivery{
+							ObjectMeta: meta.ObjectMeta{
+								ID: "123456789",
+							},
+						},
+					)
+					require.NoError(t, err)
+					return res
+				},
+			},
+			assertions: func(delivery *api.WebhookDelivery, err error) {
+				require.NoError(t, err)
+				require.NotNil(t, delivery)
+				require.Equal(t, "123456789", delivery.ID)
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			store := &webhookDeliveriesStore{
+				collection: testCase.collection,
+			}
+			testCase.assertions(
+				store.Claim(context.Background(), now, leaseExpiry),
+			)
+		})
+	}
+}
+
+func TestWebhookDeliveriesStoreUpdateStatus(t *testing.T) {
+	const testDeliveryID = "123456789"
+	testCases := []struct {
+		name       string
+		collection mongodb.Collection
+		assertions func(error)
+	}{
+		{
+			name: "unanticipated error",
+			collection: &mongoTesting.MockCollection{
+				UpdateOneFn: func(
+					context.Context,
+					interface{},
+					interface{},
+					...*options.UpdateOptions,
+				) (*mongo.UpdateResult, error) {
+					return nil, errors.New("something went wrong")
+				},
+			},
+			assertions: func(err error) {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), "something went wrong")
+				require.Contains(
+					t,
+					err.Error(),
+					"error updating status of webhook delivery",
+				)
+			},
+		},
+
+		{
+			name: "webhook delivery not found",
+			collection: &mongoTesting.MockCollection{
+				UpdateOneFn: func(
+					context.Context,
+					interface{},
+					interface{},
+					...*options.UpdateOptions,
+				) (*mongo.UpdateResult, error) {
+					return &mongo.UpdateResult{
+						MatchedCount: 0,
+					}, nil
+				},
+			},
+			assertions: func(err error) {
+				require.Error(t, err)
+				require.IsType(t, &meta.ErrNotFound{}, err)
+			},
+		},
+
+		{
+			name: "success",
+			collection: &mongoTesting.MockCollection{
+				UpdateOneFn: func(
+					context.Context,
+					interface{},
+					interface{},
+					...*options.UpdateOptions,
+				) (*mongo.UpdateResult, error) {
+					return &mongo.UpdateResult{
+						MatchedCount: 1,
+					}, nil
+				},
+			},
+			assertions: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			store := &webhookDeliveriesStore{
+				collection: testCase.collection,
+			}
+			testCase.assertions(
+				store.UpdateStatus(
+					context.Background(),
+					testDeliveryID,
+					api.WebhookDeliveryStatus{
+						Phase: api.WebhookDeliveryPhaseSucceeded,
+					},
+				),
+			)
+		})
+	}
+}
@@ -83,3 +83,19 @@ func GetWorkerPrincipal(eventID string) *WorkerPrincipal {
 		eventID: eventID,
 	}
 }
+
+// CronPrincipal is an implementation of the Principal interface that represents
+// the component that emits Events on behalf of Projects' EventSchedules, which
+// is a special class of user because, although it cannot do much, it has the
+// UNIQUE ability to create Events from the "brigade.sh/cron" source.
+type CronPrincipal struct{}
+
+func (c *CronPrincipal) RoleAssignments() []RoleAssignment {
+	return []RoleAssignment{
+		{Role: RoleReader},
+		{
+			Role:  RoleEventCreator,
+			Scope: CronEventSource,
+		},
+	}
+}
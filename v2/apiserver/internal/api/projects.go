@@ -73,6 +73,40 @@ type ProjectSpec struct {
 	EventSubscriptions []EventSubscription `json:"eventSubscriptions,omitempty" bson:"eventSubscriptions,omitempty"` // nolint: lll
 	// WorkerTemplate is a prototypical WorkerSpec.
 	WorkerTemplate WorkerSpec `json:"workerTemplate" bson:"workerTemplate"`
+	// Schedules defines Events that should be emitted on behalf of the Project
+	// on a recurring basis. Note that, like any other Event, scheduled Events
+	// only trigger the execution of a new Worker if the Project also subscribes
+	// to them.
+	Schedules []EventSchedule `json:"schedules,omitempty" bson:"schedules,omitempty"` // nolint: lll
+	// MaxConcurrentWorkers optionally limits how many of the Project's Workers
+	// may execute concurrently. A value of zero indicates no Project-level limit.
+	// Regardless of this value, the system-wide limit always applies.
+	MaxConcurrentWorkers int `json:"maxConcurrentWorkers,omitempty" bson:"maxConcurrentWorkers,omitempty"` // nolint: lll
+	// MaxConcurrentJobs optionally limits how many of the Project's Jobs may
+	// execute concurrently. A value of zero indicates no Project-level limit.
+	// Regardless of this value, the system-wide limit always applies.
+	MaxConcurrentJobs int `json:"maxConcurrentJobs,omitempty" bson:"maxConcurrentJobs,omitempty"` // nolint: lll
+	// SchedulingWeight optionally specifies the Project's share of the system's
+	// Worker and Job capacity, relative to other Projects, when Projects are
+	// competing for that capacity. A Project with a weight of two, for instance,
+	// is granted capacity twice as often as a Project with a weight of one. A
+	// value of zero is treated as a weight of one.
+	SchedulingWeight int `json:"schedulingWeight,omitempty" bson:"schedulingWeight,omitempty"` // nolint: lll
+	// MaxEventPriority optionally specifies the highest Event priority the
+	// Project honors. The priority of any Event the Project receives is lowered
+	// to this value if it exceeds it. The default of zero means Events cannot
+	// jump the queue.
+	MaxEventPriority int `json:"maxEventPriority,omitempty" bson:"maxEventPriority,omitempty"` // nolint: lll
+	// Retention optionally specifies which of the Project's Events should be
+	// automatically deleted. Any setting specified here takes precedence over
+	// the corresponding system-level setting.
+	Retention *RetentionPolicy `json:"retention,omitempty" bson:"retention,omitempty"` // nolint: lll
+	// Webhooks optionally specifies HTTP endpoints that should be notified
+	// whenever the Project's Workers or Jobs enter specific phases.
+	Webhooks []Webhook `json:"webhooks,omitempty" bson:"webhooks,omitempty"`
+	// Resources optionally specifies defaults and maximums for the compute
+	// resources requested by and available to the Project's Workers and Jobs.
+	Resources *ResourcePolicy `json:"resources,omitempty" bson:"resources,omitempty"` // nolint: lll
 }
 
 // EventSubscription defines a set of Events of interest. ProjectSpecs utilize
@@ -116,6 +150,17 @@ type EventSubscription struct {
 	// match" subscription semantics differ from the Qualifiers field's "MUST
 	// match" subscription semantics.
 	Labels map[string]string `json:"labels,omitempty" bson:"labels,omitempty"`
+	// MatchStrategy optionally specifies how the values of the Qualifiers and
+	// Labels fields are matched against the values of an Event's Qualifiers and
+	// Labels. If unspecified, values must match exactly. Note that, regardless
+	// of strategy, Qualifier KEYS must always match exactly.
+	MatchStrategy MatchStrategy `json:"matchStrategy,omitempty" bson:"matchStrategy,omitempty"` // nolint: lll
+	// Expression optionally specifies a boolean filter expression that an Event
+	// MUST also satisfy for a Project to be considered subscribed. Expressions
+	// may reference the Event's source, type, labels.<key>, qualifiers.<key>,
+	// and payload.<path> (where the payload is treated as JSON). e.g.
+	// labels.env != "prod" && payload.ref =~ "^refs/heads/release/"
+	Expression string `json:"expression,omitempty" bson:"expression,omitempty"`
 }
 
 // KubernetesDetails represents Kubernetes-specific configuration.
@@ -127,6 +172,36 @@ type KubernetesDetails struct {
 	Namespace string `json:"namespace,omitempty" bson:"namespace,omitempty"`
 }
 
+// ProjectQueueDepth represents the number of a Project's Workers and Jobs that
+// are waiting to be scheduled.
+type ProjectQueueDepth struct {
+	// ProjectID is the identifier of the Project.
+	ProjectID string `json:"projectID"`
+	// PendingWorkers is the number of the Project's Workers that are waiting to
+	// be scheduled.
+	PendingWorkers int64 `json:"pendingWorkers"`
+	// PendingJobs is the number of the Project's Jobs that are waiting to be
+	// scheduled.
+	PendingJobs int64 `json:"pendingJobs"`
+}
+
+// MarshalJSON amends ProjectQueueDepth instances with type metadata.
+func (p ProjectQueueDepth) MarshalJSON() ([]byte, error) {
+	type Alias ProjectQueueDepth
+	return json.Marshal(
+		struct {
+			meta.TypeMeta `json:",inline"`
+			Alias         `json:",inline"`
+		}{
+			TypeMeta: meta.TypeMeta{
+				APIVersion: meta.APIVersion,
+				Kind:       "ProjectQueueDepth",
+			},
+			Alias: (Alias)(p),
+		},
+	)
+}
+
 // ProjectsService is the specialized interface for managing Projects. It's
 // decoupled from underlying technology choices (e.g. data store, message bus,
 // etc.) to keep business logic reusable and consistent while the underlying
@@ -153,6 +228,10 @@ type ProjectsService interface {
 	// specified Project does not exist, implementations MUST return a
 	// *meta.ErrNotFound error.
 	Delete(context.Context, string) error
+	// GetQueueDepth returns the number of the specified Project's Workers and
+	// Jobs that are waiting to be scheduled. If the specified Project does not
+	// exist, implementations MUST return a *meta.ErrNotFound error.
+	GetQueueDepth(context.Context, string) (ProjectQueueDepth, error)
 }
 
 type projectsService struct {
@@ -161,7 +240,10 @@ type projectsService struct {
 	projectsStore               ProjectsStore
 	eventsStore                 EventsStore
 	logsStore                   CoolLogsStore
+	artifactsStore              ArtifactsStore
 	projectRoleAssignmentsStore ProjectRoleAssignmentsStore
+	cronStore                   CronStore
+	jobCacheStore               JobCacheStore
 	substrate                   Substrate
 }
 
@@ -172,7 +254,10 @@ func NewProjectsService(
 	projectsStore ProjectsStore,
 	eventsStore EventsStore,
 	logsStore CoolLogsStore,
+	artifactsStore ArtifactsStore,
 	projectRoleAssignmentsStore ProjectRoleAssignmentsStore,
+	cronStore CronStore,
+	jobCacheStore JobCacheStore,
 	substrate Substrate,
 ) ProjectsService {
 	return &projectsService{
@@ -181,7 +266,10 @@ func NewProjectsService(
 		projectsStore:               projectsStore,
 		eventsStore:                 eventsStore,
 		logsStore:                   logsStore,
+		artifactsStore:              artifactsStore,
 		projectRoleAssignmentsStore: projectRoleAssignmentsStore,
+		cronStore:                   cronStore,
+		jobCacheStore:               jobCacheStore,
 		substrate:                   substrate,
 	}
 }
@@ -194,6 +282,40 @@ func (p *projectsService) Create(
 		return project, err
 	}
 
+	if err :=
+		validateEventSubscriptions(project.Spec.EventSubscriptions); err != nil {
+		return project, err
+	}
+
+	if err := validateEventSchedules(project.Spec.Schedules); err != nil {
+		return project, err
+	}
+
+	if err :=
+		validateConcurrencyGroup(project.Spec.WorkerTemplate); err != nil {
+		return project, err
+	}
+
+	if err := validateRetentionPolicy(project.Spec.Retention); err != nil {
+		return project, err
+	}
+
+	if err := validateWebhooks(project.Spec.Webhooks); err != nil {
+		return project, err
+	}
+
+	if err := validateResourcePolicy(
+		project.Spec.Resources,
+		project.Spec.WorkerTemplate,
+	); err != nil {
+		return project, err
+	}
+
+	if err :=
+		validateWorkerHostAndJobPolicies(project.Spec.WorkerTemplate); err != nil {
+		return project, err
+	}
+
 	now := time.Now().UTC()
 	project.Created = &now
 
@@ -365,6 +487,40 @@ func (p *projectsService) Update(
 		return err
 	}
 
+	if err :=
+		validateEventSubscriptions(project.Spec.EventSubscriptions); err != nil {
+		return err
+	}
+
+	if err := validateEventSchedules(project.Spec.Schedules); err != nil {
+		return err
+	}
+
+	if err :=
+		validateConcurrencyGroup(project.Spec.WorkerTemplate); err != nil {
+		return err
+	}
+
+	if err := validateRetentionPolicy(project.Spec.Retention); err != nil {
+		return err
+	}
+
+	if err := validateWebhooks(project.Spec.Webhooks); err != nil {
+		return err
+	}
+
+	if err := validateResourcePolicy(
+		project.Spec.Resources,
+		project.Spec.WorkerTemplate,
+	); err != nil {
+		return err
+	}
+
+	if err :=
+		validateWorkerHostAndJobPolicies(project.Spec.WorkerTemplate); err != nil {
+		return err
+	}
+
 	if err := p.projectsStore.Update(ctx, project); err != nil {
 		return errors.Wrapf(
 			err,
@@ -407,6 +563,35 @@ func (p *projectsService) Delete(ctx context.Context, id string) error {
 		)
 	}
 
+	// Delete all artifacts associated with this project
+	if err := p.artifactsStore.DeleteProjectArtifacts(ctx, id); err != nil {
+		return errors.Wrapf(
+			err,
+			"error deleting all artifacts associated with project %q",
+			id,
+		)
+	}
+
+	// Delete all of this project's cached job results
+	if err := p.jobCacheStore.DeleteByProjectID(ctx, id); err != nil {
+		return errors.Wrapf(
+			err,
+			"error deleting job cache entries associated with project %q",
+			id,
+		)
+	}
+
+	// Delete all records of when this project's schedules last fired. If we
+	// didn't do this and someone, in the future, created a new project with the
+	// same name, that new project's schedules could be skipped.
+	if err := p.cronStore.DeleteByProjectID(ctx, id); err != nil {
+		return errors.Wrapf(
+			err,
+			"error deleting schedule records associated with project %q",
+			id,
+		)
+	}
+
 	// Delete all role assignments associated with this project. If we didn't do
 	// this and someone, in the future, created a new project with the same name,
 	// that new project would begin life with some existing principals having
@@ -434,6 +619,34 @@ func (p *projectsService) Delete(ctx context.Context, id string) error {
 	return nil
 }
 
+func (p *projectsService) GetQueueDepth(
+	ctx context.Context,
+	id string,
+) (ProjectQueueDepth, error) {
+	if err := p.authorize(ctx, RoleReader, ""); err != nil {
+		return ProjectQueueDepth{}, err
+	}
+
+	// Make sure the project exists
+	if _, err := p.projectsStore.Get(ctx, id); err != nil {
+		return ProjectQueueDepth{}, errors.Wrapf(
+			err,
+			"error retrieving project %q from store",
+			id,
+		)
+	}
+
+	depth, err := p.eventsStore.GetQueueDepth(ctx, id)
+	if err != nil {
+		return depth, errors.Wrapf(
+			err,
+			"error retrieving queue depth for project %q from store",
+			id,
+		)
+	}
+	return depth, nil
+}
+
 // ProjectsStore is an interface for components that implement Project
 // persistence concerns.
 type ProjectsStore interface {
@@ -447,10 +660,19 @@ type ProjectsStore interface {
 		context.Context,
 		meta.ListOptions,
 	) (meta.List[Project], error)
+	// ListSubscribers returns a ProjectList containing all CANDIDATE subscribers
+	// to the provided Event-- i.e. Projects having at least one
+	// EventSubscription matching the Event's source and type. Implementations
+	// MAY, but are not required to, apply additional criteria. Callers MUST use
+	// EventSubscription.Matches to determine which candidates are actually
+	// subscribed.
 	ListSubscribers(
 		ctx context.Context,
 		event Event,
 	) (meta.List[Project], error)
+	// ListScheduled returns a ProjectList containing all Projects having at least
+	// one EventSchedule.
+	ListScheduled(context.Context) (meta.List[Project], error)
 	// Get returns a Project having the indicated ID. If no such Project exists,
 	// implementations MUST return a *meta.ErrNotFound error.
 	Get(context.Context, string) (Project, error)
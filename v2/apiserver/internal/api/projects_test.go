@@ -14,11 +14,22 @@ func TestProjectMarshalJSON(t *testing.T) {
 	metaTesting.RequireAPIVersionAndType(t, &Project{}, ProjectKind)
 }
 
+func TestProjectQueueDepthMarshalJSON(t *testing.T) {
+	metaTesting.RequireAPIVersionAndType(
+		t,
+		&ProjectQueueDepth{},
+		"ProjectQueueDepth",
+	)
+}
+
 func TestNewProjectsService(t *testing.T) {
 	projectsStore := &mockProjectsStore{}
 	eventsStore := &mockEventsStore{}
 	logsStore := &mockLogsStore{}
+	artifactsStore := &mockArtifactsStore{}
 	projectRoleAssignmentsStore := &mockProjectRoleAssignmentsStore{}
+	cronStore := &mockCronStore{}
+	jobCacheStore := &mockJobCacheStore{}
 	substrate := &mockSubstrate{}
 	svc, ok := NewProjectsService(
 		alwaysAuthorize,
@@ -26,7 +37,10 @@ func TestNewProjectsService(t *testing.T) {
 		projectsStore,
 		eventsStore,
 		logsStore,
+		artifactsStore,
 		projectRoleAssignmentsStore,
+		cronStore,
+		jobCacheStore,
 		substrate,
 	).(*projectsService)
 	require.True(t, ok)
@@ -34,7 +48,10 @@ func TestNewProjectsService(t *testing.T) {
 	require.NotNil(t, svc.projectAuthorize)
 	require.Same(t, projectsStore, svc.projectsStore)
 	require.Same(t, eventsStore, svc.eventsStore)
+	require.Same(t, artifactsStore, svc.artifactsStore)
 	require.Same(t, projectRoleAssignmentsStore, svc.projectRoleAssignmentsStore)
+	require.Same(t, cronStore, svc.cronStore)
+	require.Same(t, jobCacheStore, svc.jobCacheStore)
 	require.Same(t, substrate, svc.substrate)
 }
 
@@ -557,6 +574,16 @@ func TestProjectServiceDelete(t *testing.T) {
 						return errors.New("error deleting project logs")
 					},
 				},
+				jobCacheStore: &mockJobCacheStore{
+					DeleteByProjectIDFn: func(context.Context, string) error {
+						return nil
+					},
+				},
+				cronStore: &mockCronStore{
+					DeleteByProjectIDFn: func(context.Context, string) error {
+						return nil
+					},
+				},
 				projectRoleAssignmentsStore: &mockProjectRoleAssignmentsStore{
 					RevokeByProjectIDFn: func(context.Context, string) error {
 						return nil
@@ -573,6 +600,138 @@ func TestProjectServiceDelete(t *testing.T) {
 				require.Contains(t, err.Error(), "error deleting project logs")
 			},
 		},
+		{
+			name: "error deleting artifacts associated with project",
+			service: &projectsService{
+				authorize:        alwaysAuthorize,
+				projectAuthorize: alwaysProjectAuthorize,
+				projectsStore: &mockProjectsStore{
+					GetFn: func(context.Context, string) (Project, error) {
+						return Project{}, nil
+					},
+				},
+				eventsStore: &mockEventsStore{
+					DeleteByProjectIDFn: func(context.Context, string) error {
+						return nil
+					},
+				},
+				logsStore: &mockLogsStore{
+					DeleteProjectLogsFn: func(
+						context.Context,
+						string,
+					) error {
+						return nil
+					},
+				},
+				artifactsStore: &mockArtifactsStore{
+					DeleteProjectArtifactsFn: func(context.Context, string) error {
+						return errors.New("something went wrong")
+					},
+				},
+			},
+			assertions: func(err error) {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), "something went wrong")
+				require.Contains(
+					t,
+					err.Error(),
+					"error deleting all artifacts associated with project",
+				)
+			},
+		},
+		{
+			name: "error deleting job cache entries associated with project",
+			service: &projectsService{
+				authorize:        alwaysAuthorize,
+				projectAuthorize: alwaysProjectAuthorize,
+				projectsStore: &mockProjectsStore{
+					GetFn: func(context.Context, string) (Project, error) {
+						return Project{}, nil
+					},
+				},
+				eventsStore: &mockEventsStore{
+					DeleteByProjectIDFn: func(context.Context, string) error {
+						return nil
+					},
+				},
+				logsStore: &mockLogsStore{
+					DeleteProjectLogsFn: func(
+						context.Context,
+						string,
+					) error {
+						return nil
+					},
+				},
+				artifactsStore: &mockArtifactsStore{
+					DeleteProjectArtifactsFn: func(context.Context, string) error {
+						return nil
+					},
+				},
+				jobCacheStore: &mockJobCacheStore{
+					DeleteByProjectIDFn: func(context.Context, string) error {
+						return errors.New("something went wrong")
+					},
+				},
+			},
+			assertions: func(err error) {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), "something went wrong")
+				require.Contains(
+					t,
+					err.Error(),
+					"error deleting job cache entries associated with project",
+				)
+			},
+		},
+		{
+			name: "error deleting schedule records associated with project",
+			service: &projectsService{
+				authorize:        alwaysAuthorize,
+				projectAuthorize: alwaysProjectAuthorize,
+				projectsStore: &mockProjectsStore{
+					GetFn: func(context.Context, string) (Project, error) {
+						return Project{}, nil
+					},
+				},
+				eventsStore: &mockEventsStore{
+					DeleteByProjectIDFn: func(context.Context, string) error {
+						return nil
+					},
+				},
+				logsStore: &mockLogsStore{
+					DeleteProjectLogsFn: func(
+						context.Context,
+						string,
+					) error {
+						return nil
+					},
+				},
+				artifactsStore: &mockArtifactsStore{
+					DeleteProjectArtifactsFn: func(context.Context, string) error {
+						return nil
+					},
+				},
+				jobCacheStore: &mockJobCacheStore{
+					DeleteByProjectIDFn: func(context.Context, string) error {
+						return nil
+					},
+				},
+				cronStore: &mockCronStore{
+					DeleteByProjectIDFn: func(context.Context, string) error {
+						return errors.New("something went wrong")
+					},
+				},
+			},
+			assertions: func(err error) {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), "something went wrong")
+				require.Contains(
+					t,
+					err.Error(),
+					"error deleting schedule records associated with project",
+				)
+			},
+		},
 		{
 			name: "error deleting role assignments associated with project",
 			service: &projectsService{
@@ -599,6 +758,21 @@ func TestProjectServiceDelete(t *testing.T) {
 						return nil
 					},
 				},
+				artifactsStore: &mockArtifactsStore{
+					DeleteProjectArtifactsFn: func(context.Context, string) error {
+						return nil
+					},
+				},
+				jobCacheStore: &mockJobCacheStore{
+					DeleteByProjectIDFn: func(context.Context, string) error {
+						return nil
+					},
+				},
+				cronStore: &mockCronStore{
+					DeleteByProjectIDFn: func(context.Context, string) error {
+						return nil
+					},
+				},
 				projectRoleAssignmentsStore: &mockProjectRoleAssignmentsStore{
 					RevokeByProjectIDFn: func(context.Context, string) error {
 						return errors.New("something went wrong")
@@ -641,6 +815,21 @@ func TestProjectServiceDelete(t *testing.T) {
 						return nil
 					},
 				},
+				artifactsStore: &mockArtifactsStore{
+					DeleteProjectArtifactsFn: func(context.Context, string) error {
+						return nil
+					},
+				},
+				jobCacheStore: &mockJobCacheStore{
+					DeleteByProjectIDFn: func(context.Context, string) error {
+						return nil
+					},
+				},
+				cronStore: &mockCronStore{
+					DeleteByProjectIDFn: func(context.Context, string) error {
+						return nil
+					},
+				},
 				projectRoleAssignmentsStore: &mockProjectRoleAssignmentsStore{
 					RevokeByProjectIDFn: func(context.Context, string) error {
 						return nil
@@ -679,6 +868,21 @@ func TestProjectServiceDelete(t *testing.T) {
 						return nil
 					},
 				},
+				artifactsStore: &mockArtifactsStore{
+					DeleteProjectArtifactsFn: func(context.Context, string) error {
+						return nil
+					},
+				},
+				jobCacheStore: &mockJobCacheStore{
+					DeleteByProjectIDFn: func(context.Context, string) error {
+						return nil
+					},
+				},
+				cronStore: &mockCronStore{
+					DeleteByProjectIDFn: func(context.Context, string) error {
+						return nil
+					},
+				},
 				projectRoleAssignmentsStore: &mockProjectRoleAssignmentsStore{
 					RevokeByProjectIDFn: func(context.Context, string) error {
 						return nil
@@ -722,6 +926,21 @@ func TestProjectServiceDelete(t *testing.T) {
 						return nil
 					},
 				},
+				artifactsStore: &mockArtifactsStore{
+					DeleteProjectArtifactsFn: func(context.Context, string) error {
+						return nil
+					},
+				},
+				jobCacheStore: &mockJobCacheStore{
+					DeleteByProjectIDFn: func(context.Context, string) error {
+						return nil
+					},
+				},
+				cronStore: &mockCronStore{
+					DeleteByProjectIDFn: func(context.Context, string) error {
+						return nil
+					},
+				},
 				projectRoleAssignmentsStore: &mockProjectRoleAssignmentsStore{
 					RevokeByProjectIDFn: func(context.Context, string) error {
 						return nil
@@ -746,6 +965,110 @@ func TestProjectServiceDelete(t *testing.T) {
 	}
 }
 
+func TestProjectServiceGetQueueDepth(t *testing.T) {
+	const testProjectID = "blue-book"
+	testCases := []struct {
+		name       string
+		service    ProjectsService
+		assertions func(ProjectQueueDepth, error)
+	}{
+		{
+			name: "unauthorized",
+			service: &projectsService{
+				authorize: neverAuthorize,
+			},
+			assertions: func(_ ProjectQueueDepth, err error) {
+				require.Error(t, err)
+				require.IsType(t, &meta.ErrAuthorization{}, err)
+			},
+		},
+		{
+			name: "error getting project from store",
+			service: &projectsService{
+				authorize: alwaysAuthorize,
+				projectsStore: &mockProjectsStore{
+					GetFn: func(context.Context, string) (Project, error) {
+						return Project{}, &meta.ErrNotFound{}
+					},
+				},
+			},
+			assertions: func(_ ProjectQueueDepth, err error) {
+				require.Error(t, err)
+				require.IsType(t, &meta.ErrNotFound{}, errors.Cause(err))
+				require.Contains(t, err.Error(), "error retrieving project")
+			},
+		},
+		{
+			name: "error getting queue depth from store",
+			service: &projectsService{
+				authorize: alwaysAuthorize,
+				projectsStore: &mockProjectsStore{
+					GetFn: func(context.Context, string) (Project, error) {
+						return Project{}, nil
+					},
+				},
+				eventsStore: &mockEventsStore{
+					GetQueueDepthFn: func(
+						context.Context,
+						string,
+					) (ProjectQueueDepth, error) {
+						return ProjectQueueDepth{}, errors.New("something went wrong")
+					},
+				},
+			},
+			assertions: func(_ ProjectQueueDepth, err error) {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), "something went wrong")
+				require.Contains(t, err.Error(), "error retrieving queue depth")
+			},
+		},
+		{
+			name: "success",
+			service: &projectsService{
+				authorize: alwaysAuthorize,
+				projectsStore: &mockProjectsStore{
+					GetFn: func(context.Context, string) (Project, error) {
+						return Project{}, nil
+					},
+				},
+				eventsStore: &mockEventsStore{
+					GetQueueDepthFn: func(
+						_ context.Context,
+						projectID string,
+					) (ProjectQueueDepth, error) {
+						return ProjectQueueDepth{
+							ProjectID:      projectID,
+							PendingWorkers: 2,
+							PendingJobs:    5,
+						}, nil
+					},
+				},
+			},
+			assertions: func(depth ProjectQueueDepth, err error) {
+				require.NoError(t, err)
+				require.Equal(
+					t,
+					ProjectQueueDepth{
+						ProjectID:      testProjectID,
+						PendingWorkers: 2,
+						PendingJobs:    5,
+					},
+					depth,
+				)
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			depth, err := testCase.service.GetQueueDepth(
+				context.Background(),
+				testProjectID,
+			)
+			testCase.assertions(depth, err)
+		})
+	}
+}
+
 type mockProjectsStore struct {
 	CreateFn func(context.Context, Project) error
 	ListFn   func(
@@ -753,6 +1076,7 @@ type mockProjectsStore struct {
 		meta.ListOptions,
 	) (meta.List[Project], error)
 	ListSubscribersFn func(context.Context, Event) (meta.List[Project], error)
+	ListScheduledFn   func(context.Context) (meta.List[Project], error)
 	GetFn             func(context.Context, string) (Project, error)
 	UpdateFn          func(context.Context, Project) error
 	DeleteFn          func(context.Context, string) error
@@ -776,6 +1100,12 @@ func (m *mockProjectsStore) ListSubscribers(
 	return m.ListSubscribersFn(ctx, event)
 }
 
+func (m *mockProjectsStore) ListScheduled(
+	ctx context.Context,
+) (meta.List[Project], error) {
+	return m.ListScheduledFn(ctx)
+}
+
 func (m *mockProjectsStore) Get(
 	ctx context.Context,
 	id string,
@@ -0,0 +1,300 @@
+package api
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/brigadecore/brigade/v2/apiserver/internal/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// ResourceQuantities specifies amounts of compute resources.
+type ResourceQuantities struct {
+	// CPU specifies an amount of CPU, expressed in cores (e.g. "2" or "0.5") or
+	// millicores (e.g. "500m").
+	CPU string `json:"cpu,omitempty" bson:"cpu,omitempty"`
+	// Memory specifies an amount of memory. The value can be expressed in bytes
+	// (as a plain integer) or as a fixed-point integer using one of these
+	// suffixes: E, P, T, G, M, K. Power-of-two equivalents may also be used: Ei,
+	// Pi, Ti, Gi, Mi, Ki.
+	Memory string `json:"memory,omitempty" bson:"memory,omitempty"`
+}
+
+// withDefaults returns a copy of the ResourceQuantities with any unspecified
+// quantities set to the corresponding default.
+func (r ResourceQuantities) withDefaults(
+	defaults ResourceQuantities,
+) ResourceQuantities {
+	if r.CPU == "" {
+		r.CPU = defaults.CPU
+	}
+	if r.Memory == "" {
+		r.Memory = defaults.Memory
+	}
+	return r
+}
+
+// ContainerResources specifies the compute resources an OCI container requires
+// and the most it may consume.
+type ContainerResources struct {
+	// Requests specifies compute resources that must be available on a substrate
+	// node for the container to be hosted there.
+	Requests ResourceQuantities `json:"requests,omitempty" bson:"requests,omitempty"` // nolint: lll
+	// Limits specifies the most compute resources the container may consume.
+	Limits ResourceQuantities `json:"limits,omitempty" bson:"limits,omitempty"`
+}
+
+// ResourcePolicy specifies Project-level defaults and maximums for the compute
+// resources requested by and available to the Project's Workers and Jobs.
+type ResourcePolicy struct {
+	// Defaults specifies requests and limits applied to any Worker or Job
+	// container that does not specify its own.
+	Defaults ContainerResources `json:"defaults,omitempty" bson:"defaults,omitempty"` // nolint: lll
+	// Max specifies the most compute resources any single Worker or Job
+	// container may request or be limited to. Containers that specify no limit
+	// (and for which there is no default limit) are limited to this amount.
+	Max ResourceQuantities `json:"max,omitempty" bson:"max,omitempty"`
+}
+
+// ApplyTo returns a copy of the provided ContainerResources with unspecified
+// requests and limits set to the ResourcePolicy's defaults. Limits that remain
+// unspecified are set to the ResourcePolicy's maximums. A defaulted request is
+// never permitted to exceed the container's limit. If the ResourcePolicy is
+// nil, the provided ContainerResources are returned unaltered.
+func (r *ResourcePolicy) ApplyTo(
+	resources *ContainerResources,
+) *ContainerResources {
+	if r == nil {
+		return resources
+	}
+	applied := ContainerResources{}
+	if resources != nil {
+		applied = *resources
+	}
+	applied.Limits = applied.Limits.withDefaults(r.Defaults.Limits).
+		withDefaults(r.Max)
+	requests := applied.Requests.withDefaults(r.Defaults.Requests)
+	if applied.Requests.CPU == "" &&
+		exceeds(requests.CPU, applied.Limits.CPU) {
+		requests.CPU = applied.Limits.CPU
+	}
+	if applied.Requests.Memory == "" &&
+		exceeds(requests.Memory, applied.Limits.Memory) {
+		requests.Memory = applied.Limits.Memory
+	}
+	applied.Requests = requests
+	if applied == (ContainerResources{}) {
+		return nil
+	}
+	return &applied
+}
+
+// exceeds returns a bool indicating whether the quantity a exceeds the
+// quantity b. Empty or unparseable quantities never exceed, nor are exceeded
+// by, anything.
+func exceeds(a, b string) bool {
+	if a == "" || b == "" {
+		return false
+	}
+	aQuantity, err := resource.ParseQuantity(a)
+	if err != nil {
+		return false
+	}
+	bQuantity, err := resource.ParseQuantity(b)
+	if err != nil {
+		return false
+	}
+	return aQuantity.Cmp(bQuantity) > 0
+}
+
+// validateResourceQuantities returns details of any of the provided
+// ResourceQuantities that cannot be parsed. The provided description is used
+// to identify the ResourceQuantities in the details.
+func validateResourceQuantities(
+	description string,
+	quantities ResourceQuantities,
+) []string {
+	details := []string{}
+	if quantities.CPU != "" {
+		if _, err := resource.ParseQuantity(quantities.CPU); err != nil {
+			details = append(
+				details,
+				fmt.Sprintf("%s cpu %q is invalid", description, quantities.CPU),
+			)
+		}
+	}
+	if quantities.Memory != "" {
+		if _, err := resource.ParseQuantity(quantities.Memory); err != nil {
+			details = append(
+				details,
+				fmt.Sprintf(
+					"%s memory %q is invalid",
+					description,
+					quantities.Memory,
+				),
+			)
+		}
+	}
+	return details
+}
+
+// validateContainerResources returns details of any ways in which the
+// provided ContainerResources are invalid-- including requests that exceed
+// limits and requests or limits that exceed the provided maximums. The provided
+// description is used to identify the ContainerResources in the details.
+func validateContainerResources(
+	description string,
+	resources *ContainerResources,
+	max ResourceQuantities,
+) []string {
+	if resources == nil {
+		return nil
+	}
+	details := validateResourceQuantities(
+		fmt.Sprintf("%s request", description),
+		resources.Requests,
+	)
+	details = append(
+		details,
+		validateResourceQuantities(
+			fmt.Sprintf("%s limit", description),
+			resources.Limits,
+		)...,
+	)
+	if len(details) > 0 {
+		return details
+	}
+	for _, q := range []struct {
+		resource string
+		request  string
+		limit    string
+		max      string
+	}{
+		{
+			resource: "cpu",
+			request:  resources.Requests.CPU,
+			limit:    resources.Limits.CPU,
+			max:      max.CPU,
+		},
+		{
+			resource: "memory",
+			request:  resources.Requests.Memory,
+			limit:    resources.Limits.Memory,
+			max:      max.Memory,
+		},
+	} {
+		if exceeds(q.request, q.limit) {
+			details = append(
+				details,
+				fmt.Sprintf(
+					"%s %s request %s exceeds its limit %s",
+					description,
+					q.resource,
+					q.request,
+					q.limit,
+				),
+			)
+		}
+		if exceeds(q.request, q.max) {
+			details = append(
+				details,
+				fmt.Sprintf(
+					"%s %s request %s exceeds the project maximum %s",
+					description,
+					q.resource,
+					q.request,
+					q.max,
+				),
+			)
+		}
+		if exceeds(q.limit, q.max) {
+			details = append(
+				details,
+				fmt.Sprintf(
+					"%s %s limit %s exceeds the project maximum %s",
+					description,
+					q.resource,
+					q.limit,
+					q.max,
+				),
+			)
+		}
+	}
+	return details
+}
+
+// validateResourcePolicy returns a *meta.ErrBadRequest if the provided
+// ResourcePolicy is invalid or if the provided WorkerSpec's container requests
+// or is limited to more compute resources than the ResourcePolicy permits.
+func validateResourcePolicy(
+	policy *ResourcePolicy,
+	workerSpec WorkerSpec,
+) error {
+	details := []string{}
+	var max ResourceQuantities
+	if policy != nil {
+		max = policy.Max
+		details = append(
+			details,
+			validateResourceQuantities("maximum", policy.Max)...,
+		)
+		details = append(
+			details,
+			validateContainerResources("defaults", &policy.Defaults, max)...,
+		)
+	}
+	if len(details) == 0 && workerSpec.Container != nil {
+		details = append(
+			details,
+			validateContainerResources(
+				"worker container",
+				policy.ApplyTo(workerSpec.Container.Resources),
+				max,
+			)...,
+		)
+	}
+	if len(details) > 0 {
+		return &meta.ErrBadRequest{
+			Reason:  "Invalid resources.",
+			Details: details,
+		}
+	}
+	return nil
+}
+
+// validateJobResources returns a *meta.ErrBadRequest if any of the provided
+// JobSpec's containers requests or is limited to more compute resources than
+// the provided ResourcePolicy permits.
+func validateJobResources(spec JobSpec, policy *ResourcePolicy) error {
+	var max ResourceQuantities
+	if policy != nil {
+		max = policy.Max
+	}
+	details := validateContainerResources(
+		"primary container",
+		spec.PrimaryContainer.Resources,
+		max,
+	)
+	sidecarNames := make([]string, 0, len(spec.SidecarContainers))
+	for sidecarName := range spec.SidecarContainers {
+		sidecarNames = append(sidecarNames, sidecarName)
+	}
+	sort.Strings(sidecarNames)
+	for _, sidecarName := range sidecarNames {
+		details = append(
+			details,
+			validateContainerResources(
+				fmt.Sprintf("sidecar container %q", sidecarName),
+				spec.SidecarContainers[sidecarName].Resources,
+				max,
+			)...,
+		)
+	}
+	if len(details) > 0 {
+		return &meta.ErrBadRequest{
+			Reason:  "Job resources exceed what the project permits.",
+			Details: details,
+		}
+	}
+	return nil
+}
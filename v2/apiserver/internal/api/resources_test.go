@@ -0,0 +1,322 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/brigadecore/brigade/v2/apiserver/internal/meta"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResourcePolicyApplyTo(t *testing.T) {
+	testCases := []struct {
+		name       string
+		policy     *ResourcePolicy
+		resources  *ContainerResources
+		assertions func(*ContainerResources)
+	}{
+		{
+			name: "nil policy",
+			resources: &ContainerResources{
+				Requests: ResourceQuantities{
+					CPU: "500m",
+				},
+			},
+			assertions: func(resources *ContainerResources) {
+				require.Equal(
+					t,
+					&ContainerResources{
+						Requests: ResourceQuantities{
+							CPU: "500m",
+						},
+					},
+					resources,
+				)
+			},
+		},
+		{
+			name:   "empty policy and no resources",
+			policy: &ResourcePolicy{},
+			assertions: func(resources *ContainerResources) {
+				require.Nil(t, resources)
+			},
+		},
+		{
+			name: "defaults applied",
+			policy: &ResourcePolicy{
+				Defaults: ContainerResources{
+					Requests: ResourceQuantities{
+						CPU:    "250m",
+						Memory: "256Mi",
+					},
+					Limits: ResourceQuantities{
+						CPU: "1",
+					},
+				},
+				Max: ResourceQuantities{
+					CPU:    "2",
+					Memory: "1Gi",
+				},
+			},
+			resources: &ContainerResources{
+				Requests: ResourceQuantities{
+					Memory: "512Mi",
+				},
+			},
+			assertions: func(resources *ContainerResources) {
+				require.Equal(
+					t,
+					&ContainerResources{
+						Requests: ResourceQuantities{
+							CPU:    "250m",
+							Memory: "512Mi",
+						},
+						Limits: ResourceQuantities{
+							CPU:    "1",
+							Memory: "1Gi",
+						},
+					},
+					resources,
+				)
+			},
+		},
+		{
+			name: "defaulted request lowered to explicit limit",
+			policy: &ResourcePolicy{
+				Defaults: ContainerResources{
+					Requests: ResourceQuantities{
+						CPU: "1",
+					},
+				},
+			},
+			resources: &ContainerResources{
+				Limits: ResourceQuantities{
+					CPU: "500m",
+				},
+			},
+			assertions: func(resources *ContainerResources) {
+				require.Equal(
+					t,
+					&ContainerResources{
+						Requests: ResourceQuantities{
+							CPU: "500m",
+						},
+						Limits: ResourceQuantities{
+							CPU: "500m",
+						},
+					},
+					resources,
+				)
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.assertions(testCase.policy.ApplyTo(testCase.resources))
+		})
+	}
+}
+
+func TestValidateResourcePolicy(t *testing.T) {
+	testCases := []struct {
+		name       string
+		policy     *ResourcePolicy
+		workerSpec WorkerSpec
+		assertions func(error)
+	}{
+		{
+			name: "no policy",
+			assertions: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "invalid quantities",
+			policy: &ResourcePolicy{
+				Defaults: ContainerResources{
+					Requests: ResourceQuantities{
+						CPU: "lots",
+					},
+				},
+				Max: ResourceQuantities{
+					Memory: "plenty",
+				},
+			},
+			assertions: func(err error) {
+				require.Error(t, err)
+				require.IsType(t, &meta.ErrBadRequest{}, err)
+				badReqErr := err.(*meta.ErrBadRequest)
+				require.Equal(t, "Invalid resources.", badReqErr.Reason)
+				require.Len(t, badReqErr.Details, 2)
+			},
+		},
+		{
+			name: "defaults exceed maximum",
+			policy: &ResourcePolicy{
+				Defaults: ContainerResources{
+					Limits: ResourceQuantities{
+						Memory: "2Gi",
+					},
+				},
+				Max: ResourceQuantities{
+					Memory: "1Gi",
+				},
+			},
+			assertions: func(err error) {
+				require.Error(t, err)
+				require.IsType(t, &meta.ErrBadRequest{}, err)
+				require.Equal(
+					t,
+					[]string{
+						"defaults memory limit 2Gi exceeds the project maximum 1Gi",
+					},
+					err.(*meta.ErrBadRequest).Details,
+				)
+			},
+		},
+		{
+			name: "worker container exceeds maximum",
+			policy: &ResourcePolicy{
+				Max: ResourceQuantities{
+					CPU: "1",
+				},
+			},
+			workerSpec: WorkerSpec{
+				Container: &ContainerSpec{
+					Resources: &ContainerResources{
+						Requests: ResourceQuantities{
+							CPU: "2",
+						},
+					},
+				},
+			},
+			assertions: func(err error) {
+				require.Error(t, err)
+				require.IsType(t, &meta.ErrBadRequest{}, err)
+				require.Equal(
+					t,
+					[]string{
+						"worker container cpu request 2 exceeds its limit 1",
+						"worker container cpu request 2 exceeds the project maximum 1",
+					},
+					err.(*meta.ErrBadRequest).Details,
+				)
+			},
+		},
+		{
+			name: "valid policy",
+			policy: &ResourcePolicy{
+				Defaults: ContainerResources{
+					Requests: ResourceQuantities{
+						CPU:    "250m",
+						Memory: "256Mi",
+					},
+				},
+				Max: ResourceQuantities{
+					CPU:    "2",
+					Memory: "4Gi",
+				},
+			},
+			workerSpec: WorkerSpec{
+				Container: &ContainerSpec{
+					Resources: &ContainerResources{
+						Limits: ResourceQuantities{
+							Memory: "1Gi",
+						},
+					},
+				},
+			},
+			assertions: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.assertions(
+				validateResourcePolicy(testCase.policy, testCase.workerSpec),
+			)
+		})
+	}
+}
+
+func TestValidateJobResources(t *testing.T) {
+	testPolicy := &ResourcePolicy{
+		Max: ResourceQuantities{
+			CPU:    "2",
+			Memory: "2Gi",
+		},
+	}
+	testCases := []struct {
+		name       string
+		spec       JobSpec
+		assertions func(error)
+	}{
+		{
+			name: "within maximum",
+			spec: JobSpec{
+				PrimaryContainer: JobContainerSpec{
+					ContainerSpec: ContainerSpec{
+						Resources: &ContainerResources{
+							Limits: ResourceQuantities{
+								CPU:    "2",
+								Memory: "1Gi",
+							},
+						},
+					},
+				},
+			},
+			assertions: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "exceeds maximum",
+			spec: JobSpec{
+				PrimaryContainer: JobContainerSpec{
+					ContainerSpec: ContainerSpec{
+						Resources: &ContainerResources{
+							Limits: ResourceQuantities{
+								CPU: "4",
+							},
+						},
+					},
+				},
+				SidecarContainers: map[string]JobContainerSpec{
+					"db": {
+						ContainerSpec: ContainerSpec{
+							Resources: &ContainerResources{
+								Requests: ResourceQuantities{
+									Memory: "4Gi",
+								},
+							},
+						},
+					},
+				},
+			},
+			assertions: func(err error) {
+				require.Error(t, err)
+				require.IsType(t, &meta.ErrBadRequest{}, err)
+				badReqErr := err.(*meta.ErrBadRequest)
+				require.Equal(
+					t,
+					"Job resources exceed what the project permits.",
+					badReqErr.Reason,
+				)
+				require.Equal(
+					t,
+					[]string{
+						"primary container cpu limit 4 exceeds the project maximum 2",
+						`sidecar container "db" memory request 4Gi exceeds the project ` +
+							"maximum 2Gi",
+					},
+					badReqErr.Details,
+				)
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.assertions(validateJobResources(testCase.spec, testPolicy))
+		})
+	}
+}
@@ -0,0 +1,136 @@
+package rest
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/brigadecore/brigade/v2/apiserver/internal/api"
+	"github.com/brigadecore/brigade/v2/apiserver/internal/lib/restmachinery"
+	"github.com/brigadecore/brigade/v2/apiserver/internal/meta"
+	"github.com/gorilla/mux"
+	"github.com/pkg/errors"
+)
+
+// ArtifactsEndpoints implements restmachinery.Endpoints to provide
+// Artifact-related URL --> action mappings to a restmachinery.Server.
+type ArtifactsEndpoints struct {
+	AuthFilter restmachinery.Filter
+	Service    api.ArtifactsService
+}
+
+// Register is invoked by restmachinery.Server to register Artifact-related URL
+// --> action mappings to a restmachinery.Server.
+func (a *ArtifactsEndpoints) Register(router *mux.Router) {
+	// List artifacts
+	router.HandleFunc(
+		"/v2/events/{eventID}/worker/jobs/{jobName}/artifacts",
+		a.AuthFilter.Decorate(a.list),
+	).Methods(http.MethodGet)
+
+	// Upload artifact
+	router.HandleFunc(
+		"/v2/events/{eventID}/worker/jobs/{jobName}/artifacts/{artifactName}",
+		a.AuthFilter.Decorate(a.upload),
+	).Methods(http.MethodPut)
+
+	// Download artifact
+	router.HandleFunc(
+		"/v2/events/{eventID}/worker/jobs/{jobName}/artifacts/{artifactName}",
+		a.AuthFilter.Decorate(a.download),
+	).Methods(http.MethodGet)
+}
+
+func (a *ArtifactsEndpoints) list(w http.ResponseWriter, r *http.Request) {
+	restmachinery.ServeRequest(
+		restmachinery.InboundRequest{
+			W: w,
+			R: r,
+			EndpointLogic: func() (interface{}, error) {
+				return a.Service.List(
+					r.Context(),
+					mux.Vars(r)["eventID"],
+					mux.Vars(r)["jobName"],
+				)
+			},
+			SuccessCode: http.StatusOK,
+		},
+	)
+}
+
+func (a *ArtifactsEndpoints) upload(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+	restmachinery.ServeRequest(
+		restmachinery.InboundRequest{
+			W: w,
+			R: r,
+			EndpointLogic: func() (interface{}, error) {
+				return a.Service.Upload(
+					r.Context(),
+					mux.Vars(r)["eventID"],
+					mux.Vars(r)["jobName"],
+					mux.Vars(r)["artifactName"],
+					r.Body,
+				)
+			},
+			SuccessCode: http.StatusOK,
+		},
+	)
+}
+
+func (a *ArtifactsEndpoints) download(w http.ResponseWriter, r *http.Request) {
+	eventID := mux.Vars(r)["eventID"]
+	jobName := mux.Vars(r)["jobName"]
+	artifactName := mux.Vars(r)["artifactName"]
+	artifact, content, err :=
+		a.Service.Download(r.Context(), eventID, jobName, artifactName)
+	if err != nil {
+		switch e := errors.Cause(err).(type) {
+		case *meta.ErrAuthentication:
+			restmachinery.WriteAPIResponse(w, http.StatusUnauthorized, e)
+		case *meta.ErrAuthorization:
+			restmachinery.WriteAPIResponse(w, http.StatusForbidden, e)
+		case *meta.ErrBadRequest:
+			restmachinery.WriteAPIResponse(w, http.StatusBadRequest, e)
+		case *meta.ErrNotFound:
+			restmachinery.WriteAPIResponse(w, http.StatusNotFound, e)
+		default:
+			log.Println(
+				errors.Wrapf(
+					err,
+					"error retrieving event %q job %q artifact %q",
+					eventID,
+					jobName,
+					artifactName,
+				),
+			)
+			restmachinery.WriteAPIResponse(
+				w,
+				http.StatusInternalServerError,
+				&meta.ErrInternalServer{},
+			)
+		}
+		return
+	}
+	defer content.Close()
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Header().Set(
+		"Content-Disposition",
+		fmt.Sprintf("attachment; filename=%q", artifact.Name),
+	)
+	w.Header().Set("Content-Length", strconv.FormatInt(artifact.Size, 10))
+	w.WriteHeader(http.StatusOK)
+	if _, err = io.Copy(w, content); err != nil {
+		log.Println(
+			errors.Wrapf(
+				err,
+				"error writing event %q job %q artifact %q",
+				eventID,
+				jobName,
+				artifactName,
+			),
+		)
+	}
+}
@@ -0,0 +1,125 @@
+package rest
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/brigadecore/brigade/v2/apiserver/internal/api"
+	"github.com/brigadecore/brigade/v2/apiserver/internal/meta"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArtifactsEndpointsDownload(t *testing.T) {
+	testCases := []struct {
+		name         string
+		downloadErr  error
+		expectedCode int
+	}{
+		{
+			name:         "unauthenticated",
+			downloadErr:  &meta.ErrAuthentication{},
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			name:         "unauthorized",
+			downloadErr:  &meta.ErrAuthorization{},
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			name:         "invalid artifact name",
+			downloadErr:  &meta.ErrBadRequest{Reason: "Invalid artifact name."},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "artifact not found",
+			downloadErr:  &meta.ErrNotFound{},
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name:         "unanticipated error",
+			downloadErr:  errors.New("something went wrong"),
+			expectedCode: http.StatusInternalServerError,
+		},
+		{
+			name:         "success",
+			expectedCode: http.StatusOK,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			endpoints := &ArtifactsEndpoints{
+				Service: &mockArtifactsService{
+					DownloadFn: func(
+						_ context.Context,
+						_ string,
+						_ string,
+						artifactName string,
+					) (api.Artifact, io.ReadCloser, error) {
+						if testCase.downloadErr != nil {
+							return api.Artifact{}, nil, testCase.downloadErr
+						}
+						return api.Artifact{Name: artifactName, Size: 3},
+							io.NopCloser(strings.NewReader("foo")),
+							nil
+					},
+				},
+			}
+			req := mux.SetURLVars(
+				httptest.NewRequest(http.MethodGet, "/", nil),
+				map[string]string{
+					"eventID":      "123456789",
+					"jobName":      "italian",
+					"artifactName": "report.xml",
+				},
+			)
+			rr := httptest.NewRecorder()
+			endpoints.download(rr, req)
+			require.Equal(t, testCase.expectedCode, rr.Code)
+			if testCase.downloadErr == nil {
+				require.Equal(t, "foo", rr.Body.String())
+			}
+		})
+	}
+}
+
+type mockArtifactsService struct {
+	DownloadFn func(
+		ctx context.Context,
+		eventID string,
+		jobName string,
+		artifactName string,
+	) (api.Artifact, io.ReadCloser, error)
+}
+
+func (m *mockArtifactsService) Upload(
+	context.Context,
+	string,
+	string,
+	string,
+	io.Reader,
+) (api.Artifact, error) {
+	return api.Artifact{}, nil
+}
+
+func (m *mockArtifactsService) List(
+	context.Context,
+	string,
+	string,
+) (meta.List[api.Artifact], error) {
+	return meta.List[api.Artifact]{}, nil
+}
+
+func (m *mockArtifactsService) Download(
+	ctx context.Context,
+	eventID string,
+	jobName string,
+	artifactName string,
+) (api.Artifact, io.ReadCloser, error) {
+	return m.DownloadFn(ctx, eventID, jobName, artifactName)
+}
@@ -65,6 +65,18 @@ func (e *EventsEndpoints) Register(router *mux.Router) {
 		e.AuthFilter.Decorate(e.cancel),
 	).Methods(http.MethodPut)
 
+	// Pin event
+	router.HandleFunc(
+		"/v2/events/{id}/pin",
+		e.AuthFilter.Decorate(e.pin),
+	).Methods(http.MethodPut)
+
+	// Unpin event
+	router.HandleFunc(
+		"/v2/events/{id}/pin",
+		e.AuthFilter.Decorate(e.unpin),
+	).Methods(http.MethodDelete)
+
 	// Cancel a collection of events
 	router.HandleFunc(
 		"/v2/events/cancellations",
@@ -88,6 +100,12 @@ func (e *EventsEndpoints) Register(router *mux.Router) {
 		"/v2/events/{id}/retries",
 		e.AuthFilter.Decorate(e.retry),
 	).Methods(http.MethodPost)
+
+	// Match event to subscribed projects without creating it
+	router.HandleFunc(
+		"/v2/events/match",
+		e.AuthFilter.Decorate(e.match),
+	).Methods(http.MethodPost)
 }
 
 func (e *EventsEndpoints) clone(
@@ -118,7 +136,13 @@ func (e *EventsEndpoints) create(w http.ResponseWriter, r *http.Request) {
 			ReqBodySchemaLoader: e.EventSchemaLoader,
 			ReqBodyObj:          &event,
 			EndpointLogic: func() (interface{}, error) {
-				return e.Service.Create(r.Context(), event)
+				return e.Service.Create(
+					r.Context(),
+					event,
+					api.EventCreateOptions{
+						IdempotencyKey: r.Header.Get("Idempotency-Key"),
+					},
+				)
 			},
 			SuccessCode: http.StatusCreated,
 		},
@@ -240,6 +264,32 @@ func (e *EventsEndpoints) cancel(w http.ResponseWriter, r *http.Request) {
 	)
 }
 
+func (e *EventsEndpoints) pin(w http.ResponseWriter, r *http.Request) {
+	restmachinery.ServeRequest(
+		restmachinery.InboundRequest{
+			W: w,
+			R: r,
+			EndpointLogic: func() (interface{}, error) {
+				return nil, e.Service.Pin(r.Context(), mux.Vars(r)["id"])
+			},
+			SuccessCode: http.StatusOK,
+		},
+	)
+}
+
+func (e *EventsEndpoints) unpin(w http.ResponseWriter, r *http.Request) {
+	restmachinery.ServeRequest(
+		restmachinery.InboundRequest{
+			W: w,
+			R: r,
+			EndpointLogic: func() (interface{}, error) {
+				return nil, e.Service.Unpin(r.Context(), mux.Vars(r)["id"])
+			},
+			SuccessCode: http.StatusOK,
+		},
+	)
+}
+
 func (e *EventsEndpoints) cancelMany(
 	w http.ResponseWriter,
 	r *http.Request,
@@ -317,6 +367,22 @@ func (e *EventsEndpoints) retry(
 	)
 }
 
+func (e *EventsEndpoints) match(w http.ResponseWriter, r *http.Request) {
+	event := api.Event{}
+	restmachinery.ServeRequest(
+		restmachinery.InboundRequest{
+			W:                   w,
+			R:                   r,
+			ReqBodySchemaLoader: e.EventSchemaLoader,
+			ReqBodyObj:          &event,
+			EndpointLogic: func() (interface{}, error) {
+				return e.Service.Match(r.Context(), event)
+			},
+			SuccessCode: http.StatusOK,
+		},
+	)
+}
+
 func eventsSelectorFromURLQuery(
 	queryParams url.Values,
 ) (api.EventsSelector, *meta.ErrBadRequest) {
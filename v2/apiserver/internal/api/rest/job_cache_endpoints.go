@@ -0,0 +1,39 @@
+package rest
+
+import (
+	"net/http"
+
+	"github.com/brigadecore/brigade/v2/apiserver/internal/api"
+	"github.com/brigadecore/brigade/v2/apiserver/internal/lib/restmachinery"
+	"github.com/gorilla/mux"
+)
+
+// JobCacheEndpoints implements restmachinery.Endpoints to provide job
+// cache-related URL --> action mappings to a restmachinery.Server.
+type JobCacheEndpoints struct {
+	AuthFilter restmachinery.Filter
+	Service    api.JobCacheService
+}
+
+// Register is invoked by restmachinery.Server to register job cache-related URL
+// --> action mappings to a restmachinery.Server.
+func (j *JobCacheEndpoints) Register(router *mux.Router) {
+	// Purge a project's job cache
+	router.HandleFunc(
+		"/v2/projects/{projectID}/job-cache",
+		j.AuthFilter.Decorate(j.purge),
+	).Methods(http.MethodDelete)
+}
+
+func (j *JobCacheEndpoints) purge(w http.ResponseWriter, r *http.Request) {
+	restmachinery.ServeRequest(
+		restmachinery.InboundRequest{
+			W: w,
+			R: r,
+			EndpointLogic: func() (interface{}, error) {
+				return nil, j.Service.Purge(r.Context(), mux.Vars(r)["projectID"])
+			},
+			SuccessCode: http.StatusOK,
+		},
+	)
+}
@@ -18,10 +18,11 @@ import (
 // JobsEndpoints implements restmachinery.Endpoints to provide Job-related URL
 // --> action mappings to a restmachinery.Server.
 type JobsEndpoints struct {
-	AuthFilter            restmachinery.Filter
-	JobSchemaLoader       gojsonschema.JSONLoader
-	JobStatusSchemaLoader gojsonschema.JSONLoader
-	Service               api.JobsService
+	AuthFilter             restmachinery.Filter
+	JobSchemaLoader        gojsonschema.JSONLoader
+	JobStatusSchemaLoader  gojsonschema.JSONLoader
+	JobOutputsSchemaLoader gojsonschema.JSONLoader
+	Service                api.JobsService
 }
 
 // Register is invoked by restmachinery.Server to register Job-related URL
@@ -51,6 +52,12 @@ func (j *JobsEndpoints) Register(router *mux.Router) {
 		j.AuthFilter.Decorate(j.updateStatus),
 	).Methods(http.MethodPut)
 
+	// Update job outputs
+	router.HandleFunc(
+		"/v2/events/{eventID}/worker/jobs/{jobName}/outputs",
+		j.AuthFilter.Decorate(j.updateOutputs),
+	).Methods(http.MethodPut)
+
 	// Clean up a job
 	router.HandleFunc(
 		"/v2/events/{eventID}/worker/jobs/{jobName}/cleanup",
@@ -62,6 +69,12 @@ func (j *JobsEndpoints) Register(router *mux.Router) {
 		"/v2/events/{eventID}/worker/jobs/{jobName}/timeout",
 		j.AuthFilter.Decorate(j.timeout),
 	).Methods(http.MethodPut)
+
+	// Abort a job
+	router.HandleFunc(
+		"/v2/events/{eventID}/worker/jobs/{jobName}/abort",
+		j.AuthFilter.Decorate(j.abort),
+	).Methods(http.MethodPut)
 }
 
 func (j *JobsEndpoints) create(w http.ResponseWriter, r *http.Request) {
@@ -199,6 +212,30 @@ func (j *JobsEndpoints) updateStatus(
 	)
 }
 
+func (j *JobsEndpoints) updateOutputs(
+	w http.ResponseWriter,
+	r *http.Request,
+) {
+	outputs := api.JobOutputs{}
+	restmachinery.ServeRequest(
+		restmachinery.InboundRequest{
+			W:                   w,
+			R:                   r,
+			ReqBodySchemaLoader: j.JobOutputsSchemaLoader,
+			ReqBodyObj:          &outputs,
+			EndpointLogic: func() (interface{}, error) {
+				return nil, j.Service.UpdateOutputs(
+					r.Context(),
+					mux.Vars(r)["eventID"],
+					mux.Vars(r)["jobName"],
+					outputs,
+				)
+			},
+			SuccessCode: http.StatusOK,
+		},
+	)
+}
+
 func (j *JobsEndpoints) cleanup(
 	w http.ResponseWriter,
 	r *http.Request,
@@ -238,3 +275,23 @@ func (j *JobsEndpoints) timeout(
 		},
 	)
 }
+
+func (j *JobsEndpoints) abort(
+	w http.ResponseWriter,
+	r *http.Request,
+) {
+	restmachinery.ServeRequest(
+		restmachinery.InboundRequest{
+			W: w,
+			R: r,
+			EndpointLogic: func() (interface{}, error) {
+				return nil, j.Service.Abort(
+					r.Context(),
+					mux.Vars(r)["eventID"],
+					mux.Vars(r)["jobName"],
+				)
+			},
+			SuccessCode: http.StatusOK,
+		},
+	)
+}
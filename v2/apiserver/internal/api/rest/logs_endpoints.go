@@ -3,9 +3,11 @@ package rest
 import (
 	"encoding/json"
 	"fmt"
+	"io"
 	"log"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/brigadecore/brigade/v2/apiserver/internal/api"
 	"github.com/brigadecore/brigade/v2/apiserver/internal/lib/restmachinery"
@@ -29,6 +31,24 @@ func (l *LogsEndpoints) Register(router *mux.Router) {
 		"/v2/events/{id}/logs",
 		l.AuthFilter.Decorate(l.stream),
 	).Methods(http.MethodGet)
+
+	// Download event logs as an archive
+	router.HandleFunc(
+		"/v2/events/{id}/logs/archive",
+		l.AuthFilter.Decorate(l.archive),
+	).Methods(http.MethodGet)
+
+	// Search event logs
+	router.HandleFunc(
+		"/v2/events/{id}/logs/search",
+		l.AuthFilter.Decorate(l.search),
+	).Methods(http.MethodGet)
+
+	// Search project logs
+	router.HandleFunc(
+		"/v2/projects/{id}/logs/search",
+		l.AuthFilter.Decorate(l.searchProject),
+	).Methods(http.MethodGet)
 }
 
 func (l *LogsEndpoints) stream(
@@ -48,9 +68,65 @@ func (l *LogsEndpoints) stream(
 		Job:       r.URL.Query().Get("job"),
 		Container: r.URL.Query().Get("container"),
 	}
+	// nolint: errcheck
+	selector.All, _ = strconv.ParseBool(r.URL.Query().Get("all"))
+	if attemptStr := r.URL.Query().Get("attempt"); attemptStr != "" {
+		var err error
+		if selector.Attempt, err = strconv.Atoi(attemptStr); err != nil {
+			restmachinery.WriteAPIResponse(
+				w,
+				http.StatusBadRequest,
+				&meta.ErrBadRequest{
+					Reason: "Value of attempt was not parseable as an int",
+				},
+			)
+			return
+		}
+	}
 	opts := api.LogStreamOptions{
 		Follow: follow,
 	}
+	if tailLinesStr := r.URL.Query().Get("tailLines"); tailLinesStr != "" {
+		var err error
+		if opts.TailLines, err = strconv.ParseInt(tailLinesStr, 10, 64); err != nil {
+			restmachinery.WriteAPIResponse(
+				w,
+				http.StatusBadRequest,
+				&meta.ErrBadRequest{
+					Reason: "Value of tailLines was not parseable as an int",
+				},
+			)
+			return
+		}
+	}
+	if sinceStr := r.URL.Query().Get("since"); sinceStr != "" {
+		since, err := time.Parse(time.RFC3339, sinceStr)
+		if err != nil {
+			restmachinery.WriteAPIResponse(
+				w,
+				http.StatusBadRequest,
+				&meta.ErrBadRequest{
+					Reason: "Value of since was not parseable as an RFC3339 timestamp",
+				},
+			)
+			return
+		}
+		opts.Since = &since
+	}
+	if untilStr := r.URL.Query().Get("until"); untilStr != "" {
+		until, err := time.Parse(time.RFC3339, untilStr)
+		if err != nil {
+			restmachinery.WriteAPIResponse(
+				w,
+				http.StatusBadRequest,
+				&meta.ErrBadRequest{
+					Reason: "Value of until was not parseable as an RFC3339 timestamp",
+				},
+			)
+			return
+		}
+		opts.Until = &until
+	}
 
 	var lastEventID int64
 	// SSE has support for resuming where you left off after a
@@ -81,9 +157,17 @@ func (l *LogsEndpoints) stream(
 
 	logEntryCh, err := l.Service.Stream(r.Context(), id, selector, opts)
 	if err != nil {
-		if _, ok := errors.Cause(err).(*meta.ErrNotFound); ok {
+		switch errors.Cause(err).(type) {
+		case *meta.ErrNotFound:
 			restmachinery.WriteAPIResponse(w, http.StatusNotFound, errors.Cause(err))
 			return
+		case *meta.ErrBadRequest:
+			restmachinery.WriteAPIResponse(
+				w,
+				http.StatusBadRequest,
+				errors.Cause(err),
+			)
+			return
 		}
 		log.Println(
 			errors.Wrapf(err, "error retrieving log stream for event %q", id),
@@ -134,3 +218,128 @@ func (l *LogsEndpoints) stream(
 		flusher.Flush()
 	}
 }
+
+func (l *LogsEndpoints) archive(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+	content, err := l.Service.Archive(r.Context(), id)
+	if err != nil {
+		switch e := errors.Cause(err).(type) {
+		case *meta.ErrAuthorization:
+			restmachinery.WriteAPIResponse(w, http.StatusForbidden, e)
+		case *meta.ErrNotFound:
+			restmachinery.WriteAPIResponse(w, http.StatusNotFound, e)
+		default:
+			log.Println(
+				errors.Wrapf(err, "error retrieving log archive for event %q", id),
+			)
+			restmachinery.WriteAPIResponse(
+				w,
+				http.StatusInternalServerError,
+				&meta.ErrInternalServer{},
+			)
+		}
+		return
+	}
+	defer content.Close()
+	w.Header().Set("Content-Type", "application/gzip")
+	w.Header().Set(
+		"Content-Disposition",
+		fmt.Sprintf("attachment; filename=%q", fmt.Sprintf("%s-logs.tar.gz", id)),
+	)
+	w.WriteHeader(http.StatusOK)
+	if _, err = io.Copy(w, content); err != nil {
+		log.Println(
+			errors.Wrapf(err, "error writing log archive for event %q", id),
+		)
+	}
+}
+
+func (l *LogsEndpoints) search(w http.ResponseWriter, r *http.Request) {
+	criteria, opts, ok := logSearchCriteriaAndOptions(w, r)
+	if !ok {
+		return
+	}
+	restmachinery.ServeRequest(
+		restmachinery.InboundRequest{
+			W: w,
+			R: r,
+			EndpointLogic: func() (interface{}, error) {
+				return l.Service.Search(
+					r.Context(),
+					mux.Vars(r)["id"],
+					criteria,
+					opts,
+				)
+			},
+			SuccessCode: http.StatusOK,
+		},
+	)
+}
+
+func (l *LogsEndpoints) searchProject(w http.ResponseWriter, r *http.Request) {
+	criteria, opts, ok := logSearchCriteriaAndOptions(w, r)
+	if !ok {
+		return
+	}
+	restmachinery.ServeRequest(
+		restmachinery.InboundRequest{
+			W: w,
+			R: r,
+			EndpointLogic: func() (interface{}, error) {
+				return l.Service.SearchProject(
+					r.Context(),
+					mux.Vars(r)["id"],
+					criteria,
+					opts,
+				)
+			},
+			SuccessCode: http.StatusOK,
+		},
+	)
+}
+
+// logSearchCriteriaAndOptions parses log search criteria and options from the
+// provided request's query parameters. If any of them cannot be parsed, an
+// error response is written and false is returned.
+func logSearchCriteriaAndOptions(
+	w http.ResponseWriter,
+	r *http.Request,
+) (api.LogSearchCriteria, api.LogSearchOptions, bool) {
+	query := r.URL.Query()
+	// nolint: errcheck
+	regex, _ := strconv.ParseBool(query.Get("regex"))
+	// nolint: errcheck
+	ignoreCase, _ := strconv.ParseBool(query.Get("ignoreCase"))
+	criteria := api.LogSearchCriteria{
+		Pattern:    query.Get("pattern"),
+		Regex:      regex,
+		IgnoreCase: ignoreCase,
+	}
+	opts := api.LogSearchOptions{}
+	for param, value := range map[string]*int{
+		"context": &opts.ContextLines,
+		"limit":   &opts.Limit,
+		"events":  &opts.Events,
+	} {
+		valueStr := query.Get(param)
+		if valueStr == "" {
+			continue
+		}
+		var err error
+		if *value, err = strconv.Atoi(valueStr); err != nil {
+			restmachinery.WriteAPIResponse(
+				w,
+				http.StatusBadRequest,
+				&meta.ErrBadRequest{
+					Reason: fmt.Sprintf(
+						"Invalid value %q for %q query parameter",
+						valueStr,
+						param,
+					),
+				},
+			)
+			return criteria, opts, false
+		}
+	}
+	return criteria, opts, true
+}
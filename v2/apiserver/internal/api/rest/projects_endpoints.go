@@ -52,6 +52,12 @@ func (p *ProjectsEndpoints) Register(router *mux.Router) {
 		"/v2/projects/{id}",
 		p.AuthFilter.Decorate(p.delete),
 	).Methods(http.MethodDelete)
+
+	// Get Project queue depth
+	router.HandleFunc(
+		"/v2/projects/{id}/queue-depth",
+		p.AuthFilter.Decorate(p.getQueueDepth),
+	).Methods(http.MethodGet)
 }
 
 func (p *ProjectsEndpoints) create(w http.ResponseWriter, r *http.Request) {
@@ -158,3 +164,19 @@ func (p *ProjectsEndpoints) delete(w http.ResponseWriter, r *http.Request) {
 		},
 	)
 }
+
+func (p *ProjectsEndpoints) getQueueDepth(
+	w http.ResponseWriter,
+	r *http.Request,
+) {
+	restmachinery.ServeRequest(
+		restmachinery.InboundRequest{
+			W: w,
+			R: r,
+			EndpointLogic: func() (interface{}, error) {
+				return p.Service.GetQueueDepth(r.Context(), mux.Vars(r)["id"])
+			},
+			SuccessCode: http.StatusOK,
+		},
+	)
+}
@@ -40,7 +40,12 @@ func (s *SubstrateEndpoints) countRunningWorkers(
 			W: w,
 			R: r,
 			EndpointLogic: func() (interface{}, error) {
-				return s.Service.CountRunningWorkers(r.Context())
+				return s.Service.CountRunningWorkers(
+					r.Context(),
+					api.RunningWorkersSelector{
+						ProjectID: r.URL.Query().Get("projectID"),
+					},
+				)
 			},
 			SuccessCode: http.StatusOK,
 		},
@@ -56,7 +61,12 @@ func (s *SubstrateEndpoints) countRunningJobs(
 			W: w,
 			R: r,
 			EndpointLogic: func() (interface{}, error) {
-				return s.Service.CountRunningJobs(r.Context())
+				return s.Service.CountRunningJobs(
+					r.Context(),
+					api.RunningJobsSelector{
+						ProjectID: r.URL.Query().Get("projectID"),
+					},
+				)
 			},
 			SuccessCode: http.StatusOK,
 		},
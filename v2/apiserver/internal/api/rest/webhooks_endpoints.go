@@ -0,0 +1,66 @@
+package rest
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/brigadecore/brigade/v2/apiserver/internal/api"
+	"github.com/brigadecore/brigade/v2/apiserver/internal/lib/restmachinery"
+	"github.com/brigadecore/brigade/v2/apiserver/internal/meta"
+	"github.com/gorilla/mux"
+)
+
+type WebhooksEndpoints struct {
+	AuthFilter restmachinery.Filter
+	Service    api.WebhooksService
+}
+
+func (w *WebhooksEndpoints) Register(router *mux.Router) {
+	// List WebhookDeliveries
+	router.HandleFunc(
+		"/v2/projects/{projectID}/webhook-deliveries",
+		w.AuthFilter.Decorate(w.listDeliveries),
+	).Methods(http.MethodGet)
+}
+
+func (w *WebhooksEndpoints) listDeliveries(
+	wr http.ResponseWriter,
+	r *http.Request,
+) {
+	selector := api.WebhookDeliveriesSelector{
+		ProjectID: mux.Vars(r)["projectID"],
+		EventID:   r.URL.Query().Get("eventID"),
+		Webhook:   r.URL.Query().Get("webhook"),
+	}
+	opts := meta.ListOptions{
+		Continue: r.URL.Query().Get("continue"),
+	}
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		var err error
+		if opts.Limit, err = strconv.ParseInt(limitStr, 10, 64); err != nil ||
+			opts.Limit < 1 || opts.Limit > 100 {
+			restmachinery.WriteAPIResponse(
+				wr,
+				http.StatusBadRequest,
+				&meta.ErrBadRequest{
+					Reason: fmt.Sprintf(
+						`Invalid value %q for "limit" query parameter`,
+						limitStr,
+					),
+				},
+			)
+			return
+		}
+	}
+	restmachinery.ServeRequest(
+		restmachinery.InboundRequest{
+			W: wr,
+			R: r,
+			EndpointLogic: func() (interface{}, error) {
+				return w.Service.ListDeliveries(r.Context(), selector, opts)
+			},
+			SuccessCode: http.StatusOK,
+		},
+	)
+}
@@ -0,0 +1,368 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/brigadecore/brigade/v2/apiserver/internal/meta"
+	"github.com/pkg/errors"
+)
+
+// RetentionPolicy describes which Events whose Workers have reached a terminal
+// phase should be automatically deleted. Events whose Workers have not reached
+// a terminal phase and pinned Events are never deleted.
+type RetentionPolicy struct {
+	// MaxAge optionally specifies how long after their creation Events are
+	// retained. This duration string is a sequence of decimal numbers, each with
+	// optional fraction and a unit suffix, such as "720h". Valid time units are
+	// "ns", "us" (or "µs"), "ms", "s", "m", "h".
+	MaxAge string `json:"maxAge,omitempty" bson:"maxAge,omitempty"`
+	// PhaseMaxAges optionally overrides MaxAge for Events whose Workers are in
+	// specific terminal phases. This permits, for instance, FAILED Events to be
+	// retained longer than SUCCEEDED ones.
+	PhaseMaxAges map[WorkerPhase]string `json:"phaseMaxAges,omitempty" bson:"phaseMaxAges,omitempty"` // nolint: lll
+	// MaxCount optionally specifies the maximum number of Events retained per
+	// Project. When this is exceeded, the oldest Events are deleted first.
+	MaxCount int `json:"maxCount,omitempty" bson:"maxCount,omitempty"`
+}
+
+// validateRetentionPolicy returns a *meta.ErrBadRequest if the provided
+// RetentionPolicy is invalid.
+func validateRetentionPolicy(policy *RetentionPolicy) error {
+	if policy == nil {
+		return nil
+	}
+	details := []string{}
+	if policy.MaxAge != "" {
+		if maxAge, err := time.ParseDuration(policy.MaxAge); err != nil {
+			details = append(
+				details,
+				fmt.Sprintf("max age %q is invalid: %s", policy.MaxAge, err),
+			)
+		} else if maxAge < 0 {
+			details = append(
+				details,
+				fmt.Sprintf("max age %q is negative", policy.MaxAge),
+			)
+		}
+	}
+	for phase, maxAgeStr := range policy.PhaseMaxAges {
+		if !phase.IsTerminal() {
+			details = append(
+				details,
+				fmt.Sprintf("worker phase %q is not a terminal phase", phase),
+			)
+		}
+		if maxAge, err := time.ParseDuration(maxAgeStr); err != nil {
+			details = append(
+				details,
+				fmt.Sprintf(
+					"max age %q for worker phase %q is invalid: %s",
+					maxAgeStr,
+					phase,
+					err,
+				),
+			)
+		} else if maxAge < 0 {
+			details = append(
+				details,
+				fmt.Sprintf(
+					"max age %q for worker phase %q is negative",
+					maxAgeStr,
+					phase,
+				),
+			)
+		}
+	}
+	if policy.MaxCount < 0 {
+		details = append(
+			details,
+			fmt.Sprintf("max count %d is negative", policy.MaxCount),
+		)
+	}
+	if len(details) > 0 {
+		return &meta.ErrBadRequest{
+			Reason:  "Invalid retention policy.",
+			Details: details,
+		}
+	}
+	return nil
+}
+
+// effectiveRetentionPolicy returns the RetentionPolicy that results from
+// overlaying the provided Project-level RetentionPolicy, if any, on top of the
+// provided system-level RetentionPolicy. Any setting the Project specifies
+// takes precedence over the corresponding system-level setting.
+func effectiveRetentionPolicy(
+	systemPolicy RetentionPolicy,
+	projectPolicy *RetentionPolicy,
+) RetentionPolicy {
+	policy := RetentionPolicy{
+		MaxAge:       systemPolicy.MaxAge,
+		PhaseMaxAges: map[WorkerPhase]string{},
+		MaxCount:     systemPolicy.MaxCount,
+	}
+	for phase, maxAge := range systemPolicy.PhaseMaxAges {
+		policy.PhaseMaxAges[phase] = maxAge
+	}
+	if projectPolicy == nil {
+		return policy
+	}
+	if projectPolicy.MaxAge != "" {
+		policy.MaxAge = projectPolicy.MaxAge
+	}
+	for phase, maxAge := range projectPolicy.PhaseMaxAges {
+		policy.PhaseMaxAges[phase] = maxAge
+	}
+	if projectPolicy.MaxCount != 0 {
+		policy.MaxCount = projectPolicy.MaxCount
+	}
+	return policy
+}
+
+// RetentionServiceConfig encapsulates configuration options for the
+// RetentionService.
+type RetentionServiceConfig struct {
+	// Interval specifies how frequently the RetentionService should apply
+	// retention policies.
+	Interval time.Duration
+	// Policy specifies the system-level RetentionPolicy. Projects may override
+	// any of its settings.
+	Policy RetentionPolicy
+}
+
+// RetentionService is the specialized interface for deleting Events in
+// accordance with system-level and Project-level RetentionPolicies. It's
+// decoupled from underlying technology choices (e.g. data store, message bus,
+// etc.) to keep business logic reusable and consistent while the underlying
+// tech stack remains free to change.
+type RetentionService interface {
+	// Run periodically deletes all Events that should no longer be retained. It
+	// blocks until the provided context is canceled.
+	Run(context.Context)
+}
+
+type retentionService struct {
+	projectsStore  ProjectsStore
+	eventsStore    EventsStore
+	logsStore      CoolLogsStore
+	artifactsStore ArtifactsStore
+	substrate      Substrate
+	config         RetentionServiceConfig
+	// nowFn is overridable for testing purposes
+	nowFn func() time.Time
+}
+
+// NewRetentionService returns a specialized interface for deleting Events in
+// accordance with system-level and Project-level RetentionPolicies.
+func NewRetentionService(
+	projectsStore ProjectsStore,
+	eventsStore EventsStore,
+	logsStore CoolLogsStore,
+	artifactsStore ArtifactsStore,
+	substrate Substrate,
+	config RetentionServiceConfig,
+) RetentionService {
+	return &retentionService{
+		projectsStore:  projectsStore,
+		eventsStore:    eventsStore,
+		logsStore:      logsStore,
+		artifactsStore: artifactsStore,
+		substrate:      substrate,
+		config:         config,
+		nowFn: func() time.Time {
+			return time.Now().UTC()
+		},
+	}
+}
+
+func (r *retentionService) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.config.Interval)
+	defer ticker.Stop()
+	for {
+		if err := r.applyPolicies(ctx); err != nil {
+			log.Println(err)
+		}
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// applyPolicies deletes Events that should no longer be retained from all
+// Projects.
+func (r *retentionService) applyPolicies(ctx context.Context) error {
+	opts := meta.ListOptions{Limit: 100}
+	for {
+		projects, err := r.projectsStore.List(ctx, opts)
+		if err != nil {
+			return errors.Wrap(err, "error retrieving projects from store")
+		}
+		for _, project := range projects.Items {
+			if err := r.applyPolicy(ctx, project); err != nil {
+				log.Println(err)
+			}
+		}
+		if projects.Continue == "" {
+			return nil
+		}
+		opts.Continue = projects.Continue
+	}
+}
+
+// applyPolicy deletes the provided Project's Events that should no longer be
+// retained.
+func (r *retentionService) applyPolicy(
+	ctx context.Context,
+	project Project,
+) error {
+	policy := effectiveRetentionPolicy(r.config.Policy, project.Spec.Retention)
+	now := r.nowFn()
+	terminalPhases := []WorkerPhase{}
+	for _, phase := range WorkerPhasesAll() {
+		if !phase.IsTerminal() {
+			continue
+		}
+		terminalPhases = append(terminalPhases, phase)
+		maxAgeStr, ok := policy.PhaseMaxAges[phase]
+		if !ok {
+			maxAgeStr = policy.MaxAge
+		}
+		if maxAgeStr == "" {
+			continue
+		}
+		maxAge, err := time.ParseDuration(maxAgeStr)
+		if err != nil {
+			return errors.Wrapf(
+				err,
+				"error parsing max age for worker phase %q of project %q",
+				phase,
+				project.ID,
+			)
+		}
+		if maxAge <= 0 {
+			continue
+		}
+		createdBefore := now.Add(-maxAge)
+		if err = r.deleteEvents(
+			ctx,
+			project,
+			EventsSelector{
+				ProjectID:     project.ID,
+				WorkerPhases:  []WorkerPhase{phase},
+				CreatedBefore: &createdBefore,
+				ExcludePinned: true,
+			},
+		); err != nil {
+			return err
+		}
+	}
+	if policy.MaxCount <= 0 {
+		return nil
+	}
+	selector := EventsSelector{
+		ProjectID:     project.ID,
+		WorkerPhases:  terminalPhases,
+		ExcludePinned: true,
+	}
+	cutoff, err := r.getMaxCountCutoff(ctx, selector, policy.MaxCount)
+	if err != nil || cutoff == nil {
+		return err
+	}
+	// Many Events may have been created at the same time, at least as far as
+	// the store's precision is concerned, so Events created at the same time as
+	// the cutoff are distinguished by ID.
+	selector.CreatedBefore = cutoff.Created
+	selector.CreatedBeforeTiebreakerID = cutoff.ID
+	return r.deleteEvents(ctx, project, selector)
+}
+
+// getMaxCountCutoff returns the newest of the Events selected by the provided
+// EventsSelector that are in excess of the newest maxCount. It and every Event
+// ordered after it should be deleted. If no more than maxCount Events are
+// selected, nil is returned.
+func (r *retentionService) getMaxCountCutoff(
+	ctx context.Context,
+	selector EventsSelector,
+	maxCount int,
+) (*Event, error) {
+	opts := meta.ListOptions{Limit: 100}
+	count := 0
+	for {
+		events, err := r.eventsStore.List(ctx, selector, opts)
+		if err != nil {
+			return nil, errors.Wrapf(
+				err,
+				"error retrieving events for project %q from store",
+				selector.ProjectID,
+			)
+		}
+		for _, event := range events.Items {
+			if count++; count > maxCount && event.Created != nil {
+				return &event, nil
+			}
+		}
+		if events.Continue == "" {
+			return nil, nil
+		}
+		opts.Continue = events.Continue
+	}
+}
+
+// deleteEvents deletes the Events selected by the provided EventsSelector
+// along with their logs and anything that remains of their Workers and Jobs on
+// the substrate.
+func (r *retentionService) deleteEvents(
+	ctx context.Context,
+	project Project,
+	selector EventsSelector,
+) error {
+	eventCh, count, err := r.eventsStore.DeleteMany(ctx, selector)
+	if err != nil {
+		return errors.Wrapf(
+			err,
+			"error deleting events for project %q from store",
+			project.ID,
+		)
+	}
+	if count > 0 {
+		log.Printf(
+			"deleting %d event(s) from project %q in accordance with its retention "+
+				"policy",
+			count,
+			project.ID,
+		)
+	}
+	for event := range eventCh {
+		if err := r.substrate.DeleteWorkerAndJobs(
+			ctx,
+			project,
+			event,
+		); err != nil {
+			log.Println(errors.Wrapf(
+				err,
+				"error deleting event %q worker and jobs from the substrate",
+				event.ID,
+			))
+		}
+		if err := r.logsStore.DeleteEventLogs(ctx, event); err != nil {
+			log.Println(errors.Wrapf(
+				err,
+				"error deleting logs for event %q",
+				event.ID,
+			))
+		}
+		if err := r.artifactsStore.DeleteEventArtifacts(ctx, event); err != nil {
+			log.Println(errors.Wrapf(
+				err,
+				"error deleting artifacts for event %q",
+				event.ID,
+			))
+		}
+	}
+	return nil
+}
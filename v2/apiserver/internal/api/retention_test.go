@@ -0,0 +1,443 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/brigadecore/brigade/v2/apiserver/internal/meta"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateRetentionPolicy(t *testing.T) {
+	testCases := []struct {
+		name       string
+		policy     *RetentionPolicy
+		assertions func(error)
+	}{
+		{
+			name: "no policy",
+			assertions: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "invalid policy",
+			policy: &RetentionPolicy{
+				MaxAge: "forever",
+				PhaseMaxAges: map[WorkerPhase]string{
+					WorkerPhaseRunning: "1h",
+					WorkerPhaseFailed:  "-1h",
+				},
+				MaxCount: -1,
+			},
+			assertions: func(err error) {
+				require.Error(t, err)
+				require.IsType(t, &meta.ErrBadRequest{}, err)
+				badReqErr := err.(*meta.ErrBadRequest)
+				require.Equal(t, "Invalid retention policy.", badReqErr.Reason)
+				require.Len(t, badReqErr.Details, 4)
+			},
+		},
+		{
+			name: "valid policy",
+			policy: &RetentionPolicy{
+				MaxAge: "720h",
+				PhaseMaxAges: map[WorkerPhase]string{
+					WorkerPhaseFailed: "2160h",
+				},
+				MaxCount: 1000,
+			},
+			assertions: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.assertions(validateRetentionPolicy(testCase.policy))
+		})
+	}
+}
+
+func TestEffectiveRetentionPolicy(t *testing.T) {
+	systemPolicy := RetentionPolicy{
+		MaxAge: "720h",
+		PhaseMaxAges: map[WorkerPhase]string{
+			WorkerPhaseFailed:   "2160h",
+			WorkerPhaseTimedOut: "2160h",
+		},
+		MaxCount: 1000,
+	}
+	testCases := []struct {
+		name          string
+		projectPolicy *RetentionPolicy
+		expected      RetentionPolicy
+	}{
+		{
+			name:     "no project policy",
+			expected: systemPolicy,
+		},
+		{
+			name: "project policy overrides some settings",
+			projectPolicy: &RetentionPolicy{
+				PhaseMaxAges: map[WorkerPhase]string{
+					WorkerPhaseFailed:    "8760h",
+					WorkerPhaseSucceeded: "24h",
+				},
+				MaxCount: 50,
+			},
+			expected: RetentionPolicy{
+				MaxAge: "720h",
+				PhaseMaxAges: map[WorkerPhase]string{
+					WorkerPhaseFailed:    "8760h",
+					WorkerPhaseSucceeded: "24h",
+					WorkerPhaseTimedOut:  "2160h",
+				},
+				MaxCount: 50,
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			require.Equal(
+				t,
+				testCase.expected,
+				effectiveRetentionPolicy(systemPolicy, testCase.projectPolicy),
+			)
+		})
+	}
+	// The system policy must not have been modified
+	require.Equal(t, "2160h", systemPolicy.PhaseMaxAges[WorkerPhaseFailed])
+}
+
+func TestNewRetentionService(t *testing.T) {
+	projectsStore := &mockProjectsStore{}
+	eventsStore := &mockEventsStore{}
+	logsStore := &mockLogsStore{}
+	artifactsStore := &mockArtifactsStore{}
+	substrate := &mockSubstrate{}
+	config := RetentionServiceConfig{Interval: time.Hour}
+	svc, ok := NewRetentionService(
+		projectsStore,
+		eventsStore,
+		logsStore,
+		artifactsStore,
+		substrate,
+		config,
+	).(*retentionService)
+	require.True(t, ok)
+	require.Same(t, projectsStore, svc.projectsStore)
+	require.Same(t, eventsStore, svc.eventsStore)
+	require.Same(t, logsStore, svc.logsStore)
+	require.Same(t, artifactsStore, svc.artifactsStore)
+	require.Same(t, substrate, svc.substrate)
+	require.Equal(t, config, svc.config)
+	require.NotNil(t, svc.nowFn)
+}
+
+func TestRetentionServiceApplyPolicies(t *testing.T) {
+	testCases := []struct {
+		name       string
+		service    *retentionService
+		assertions func(error)
+	}{
+		{
+			name: "error listing projects",
+			service: &retentionService{
+				projectsStore: &mockProjectsStore{
+					ListFn: func(
+						context.Context,
+						meta.ListOptions,
+					) (meta.List[Project], error) {
+						return meta.List[Project]{}, errors.New("something went wrong")
+					},
+				},
+			},
+			assertions: func(err error) {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), "something went wrong")
+				require.Contains(t, err.Error(), "error retrieving projects")
+			},
+		},
+		{
+			name: "success",
+			service: &retentionService{
+				projectsStore: &mockProjectsStore{
+					ListFn: func(
+						_ context.Context,
+						opts meta.ListOptions,
+					) (meta.List[Project], error) {
+						if opts.Continue == "" {
+							return meta.List[Project]{
+								Items: []Project{
+									{
+										ObjectMeta: meta.ObjectMeta{
+											ID: "blue-book",
+										},
+									},
+								},
+								ListMeta: meta.ListMeta{
+									Continue: "more",
+								},
+							}, nil
+						}
+						return meta.List[Project]{
+							Items: []Project{
+								{
+									ObjectMeta: meta.ObjectMeta{
+										ID: "grudge",
+									},
+									Spec: ProjectSpec{
+										Retention: &RetentionPolicy{
+											MaxAge: "forever", // Errors are logged, not returned
+										},
+									},
+								},
+							},
+						}, nil
+					},
+				},
+				nowFn: time.Now,
+			},
+			assertions: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.assertions(
+				testCase.service.applyPolicies(context.Background()),
+			)
+		})
+	}
+}
+
+func TestRetentionServiceApplyPolicy(t *testing.T) {
+	now := time.Now().UTC()
+	earlier := now.Add(-time.Minute)
+	testProject := Project{
+		ObjectMeta: meta.ObjectMeta{
+			ID: "blue-book",
+		},
+	}
+	testProjectWithPolicy := Project{
+		ObjectMeta: meta.ObjectMeta{
+			ID: "blue-book",
+		},
+		Spec: ProjectSpec{
+			Retention: &RetentionPolicy{
+				PhaseMaxAges: map[WorkerPhase]string{
+					WorkerPhaseFailed: "2160h",
+				},
+			},
+		},
+	}
+	testCases := []struct {
+		name       string
+		policy     RetentionPolicy
+		project    Project
+		service    *retentionService
+		assertions func(error)
+	}{
+		{
+			name:    "no policy",
+			project: testProject,
+			service: &retentionService{
+				// No store calls are expected, so none are mocked
+				eventsStore: &mockEventsStore{},
+			},
+			assertions: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name:    "error deleting events",
+			project: testProject,
+			policy: RetentionPolicy{
+				MaxAge: "720h",
+			},
+			service: &retentionService{
+				eventsStore: &mockEventsStore{
+					DeleteManyFn: func(
+						context.Context,
+						EventsSelector,
+					) (<-chan Event, int64, error) {
+						return nil, 0, errors.New("something went wrong")
+					},
+				},
+			},
+			assertions: func(err error) {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), "something went wrong")
+				require.Contains(t, err.Error(), "error deleting events")
+			},
+		},
+		{
+			name:    "error listing events",
+			project: testProject,
+			policy: RetentionPolicy{
+				MaxCount: 2,
+			},
+			service: &retentionService{
+				eventsStore: &mockEventsStore{
+					ListFn: func(
+						context.Context,
+						EventsSelector,
+						meta.ListOptions,
+					) (meta.List[Event], error) {
+						return meta.List[Event]{}, errors.New("something went wrong")
+					},
+				},
+			},
+			assertions: func(err error) {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), "something went wrong")
+				require.Contains(t, err.Error(), "error retrieving events")
+			},
+		},
+		{
+			name:    "max age",
+			project: testProjectWithPolicy,
+			policy: RetentionPolicy{
+				MaxAge: "720h",
+			},
+			service: &retentionService{
+				eventsStore: &mockEventsStore{
+					DeleteManyFn: func(
+						_ context.Context,
+						selector EventsSelector,
+					) (<-chan Event, int64, error) {
+						require.Equal(t, testProject.ID, selector.ProjectID)
+						require.True(t, selector.ExcludePinned)
+						require.Len(t, selector.WorkerPhases, 1)
+						require.True(t, selector.WorkerPhases[0].IsTerminal())
+						maxAge := 720 * time.Hour
+						if selector.WorkerPhases[0] == WorkerPhaseFailed {
+							maxAge = 2160 * time.Hour
+						}
+						require.Equal(t, now.Add(-maxAge), *selector.CreatedBefore)
+						eventCh := make(chan Event)
+						close(eventCh)
+						return eventCh, 0, nil
+					},
+				},
+			},
+			assertions: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name:    "max count",
+			project: testProjectWithPolicy,
+			policy: RetentionPolicy{
+				MaxCount: 2,
+			},
+			service: &retentionService{
+				eventsStore: &mockEventsStore{
+					ListFn: func(
+						_ context.Context,
+						selector EventsSelector,
+						opts meta.ListOptions,
+					) (meta.List[Event], error) {
+						require.True(t, selector.ExcludePinned)
+						if opts.Continue == "" {
+							return meta.List[Event]{
+								Items: []Event{
+									{
+										ObjectMeta: meta.ObjectMeta{
+											ID:      "zulu",
+											Created: &now,
+										},
+									},
+								},
+								ListMeta: meta.ListMeta{
+									Continue: "more",
+								},
+							}, nil
+						}
+						// These were created at the same time, but only the second is in
+						// excess of the max count
+						return meta.List[Event]{
+							Items: []Event{
+								{
+									ObjectMeta: meta.ObjectMeta{
+										ID:      "alpha",
+										Created: &earlier,
+									},
+								},
+								{
+									ObjectMeta: meta.ObjectMeta{
+										ID:      "bravo",
+										Created: &earlier,
+									},
+								},
+							},
+						}, nil
+					},
+					DeleteManyFn: func(
+						_ context.Context,
+						selector EventsSelector,
+					) (<-chan Event, int64, error) {
+						if len(selector.WorkerPhases) == 1 {
+							// This is the FAILED phase max age from the project's policy
+							require.Equal(t, WorkerPhaseFailed, selector.WorkerPhases[0])
+							eventCh := make(chan Event)
+							close(eventCh)
+							return eventCh, 0, nil
+						}
+						require.True(t, selector.ExcludePinned)
+						require.Equal(t, earlier, *selector.CreatedBefore)
+						require.Equal(t, "bravo", selector.CreatedBeforeTiebreakerID)
+						eventCh := make(chan Event, 1)
+						eventCh <- Event{
+							ObjectMeta: meta.ObjectMeta{
+								ID: "tunguska",
+							},
+						}
+						close(eventCh)
+						return eventCh, 1, nil
+					},
+				},
+				substrate: &mockSubstrate{
+					DeleteWorkerAndJobsFn: func(
+						_ context.Context,
+						_ Project,
+						event Event,
+					) error {
+						require.Equal(t, "tunguska", event.ID)
+						return errors.New("something went wrong") // Only logged
+					},
+				},
+				logsStore: &mockLogsStore{
+					DeleteEventLogsFn: func(_ context.Context, event Event) error {
+						require.Equal(t, "tunguska", event.ID)
+						return nil
+					},
+				},
+				artifactsStore: &mockArtifactsStore{
+					DeleteEventArtifactsFn: func(_ context.Context, event Event) error {
+						require.Equal(t, "tunguska", event.ID)
+						return nil
+					},
+				},
+			},
+			assertions: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.service.config.Policy = testCase.policy
+			testCase.service.nowFn = func() time.Time {
+				return now
+			}
+			testCase.assertions(
+				testCase.service.applyPolicy(context.Background(), testCase.project),
+			)
+		})
+	}
+}
@@ -0,0 +1,408 @@
+package s3
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/brigadecore/brigade/v2/apiserver/internal/api"
+	"github.com/brigadecore/brigade/v2/apiserver/internal/lib/s3"
+	"github.com/pkg/errors"
+)
+
+const (
+	// workerDir is the name of the directory, beneath each Event's directory,
+	// that holds logs from the Event's Worker.
+	workerDir = "worker"
+	// jobsDir is the name of the directory, beneath each Event's directory, that
+	// holds logs from Jobs spawned by the Event's Worker.
+	jobsDir = "jobs"
+	// logObjectExtension is the extension of objects written by this store. The
+	// log aggregator names the objects it writes differently, but all objects in
+	// a container's directory are read regardless of their names.
+	logObjectExtension = "json.gz"
+)
+
+// logsStore is an S3-based implementation of the api.CoolLogsStore interface.
+// Logs are stored in gzipped objects that each contain one JSON document per
+// line of logs. Any number of objects may exist for a given container. They are
+// stored using the layout <project ID>/<event ID>/worker/<container>/<object>
+// for containers belonging to Workers and using the layout
+// <project ID>/<event ID>/jobs/<job>/<attempt>/<container>/<object> for
+// containers belonging to Jobs.
+type logsStore struct {
+	client s3.Client
+}
+
+// NewLogsStore returns an S3-based implementation of the api.CoolLogsStore
+// interface. As with the MongoDB-based implementation, this implementation
+// relies on a log aggregator having forwarded and stored log entries.
+func NewLogsStore(client s3.Client) api.CoolLogsStore {
+	return &logsStore{
+		client: client,
+	}
+}
+
+// NewLogsWriter returns an S3-based implementation of the api.CoolLogsWriter
+// interface that writes logs using the same layout read by the S3-based
+// implementation of the api.CoolLogsStore interface.
+func NewLogsWriter(client s3.Client) api.CoolLogsWriter {
+	return &logsStore{
+		client: client,
+	}
+}
+
+// logRecord represents a line of logs as stored by the log aggregator. Any
+// additional fields written by the log aggregator are ignored.
+type logRecord struct {
+	Time    *time.Time `json:"time,omitempty"`
+	Message string     `json:"log"`
+}
+
+func (l *logsStore) StreamLogs(
+	ctx context.Context,
+	_ api.Project,
+	event api.Event,
+	selector api.LogsSelector,
+	opts api.LogStreamOptions,
+) (<-chan api.LogEntry, error) {
+	logEntries, err := l.readContainerLogs(
+		ctx,
+		containerPrefix(event.ProjectID, event.ID, selector),
+	)
+	if err != nil {
+		return nil, errors.Wrapf(
+			err,
+			"error reading logs for container %q of event %q",
+			selector.Container,
+			event.ID,
+		)
+	}
+	logEntries = filterLogEntries(logEntries, opts)
+	logEntryCh := make(chan api.LogEntry)
+	go func() {
+		defer close(logEntryCh)
+		for _, logEntry := range logEntries {
+			select {
+			case logEntryCh <- logEntry:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return logEntryCh, nil
+}
+
+func (l *logsStore) SearchLogs(
+	ctx context.Context,
+	event api.Event,
+	expression string,
+	opts api.LogSearchOptions,
+) ([]api.LogSearchMatch, error) {
+	matcher, err := regexp.Compile(expression)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error compiling expression %q", expression)
+	}
+	keys, _, err :=
+		l.client.ListObjects(ctx, eventPrefix(event.ProjectID, event.ID), "")
+	if err != nil {
+		return nil, errors.Wrapf(err, "error listing logs for event %q", event.ID)
+	}
+	// Every object's directory identifies the container it belongs to
+	containers := map[string]api.LogSearchMatch{}
+	for _, key := range keys {
+		dir := path.Dir(key)
+		if _, ok := containers[dir]; ok {
+			continue
+		}
+		if match, ok := logSearchMatchForDir(event.ID, dir); ok {
+			containers[dir] = match
+		}
+	}
+	dirs := make([]string, 0, len(containers))
+	for dir := range containers {
+		dirs = append(dirs, dir)
+	}
+	// Search the Worker's logs before those of any Job
+	sort.Slice(dirs, func(i, j int) bool {
+		iJob := containers[dirs[i]].Job != ""
+		jJob := containers[dirs[j]].Job != ""
+		if iJob != jJob {
+			return jJob
+		}
+		return dirs[i] < dirs[j]
+	})
+	matches := []api.LogSearchMatch{}
+	for _, dir := range dirs {
+		logEntries, err := l.readContainerLogs(ctx, fmt.Sprintf("%s/", dir))
+		if err != nil {
+			return nil,
+				errors.Wrapf(err, "error reading logs for event %q", event.ID)
+		}
+		logEntryCh := make(chan api.LogEntry, len(logEntries))
+		for _, logEntry := range logEntries {
+			logEntryCh <- logEntry
+		}
+		close(logEntryCh)
+		matches = append(
+			matches,
+			api.SearchLogEntries(
+				logEntryCh,
+				matcher,
+				opts.ContextLines,
+				opts.Limit-len(matches),
+				containers[dir],
+			)...,
+		)
+		if len(matches) >= opts.Limit {
+			break
+		}
+	}
+	return matches, nil
+}
+
+// DeleteEventLogs deletes all logs associated with the provided event from the
+// underlying bucket.
+func (l *logsStore) DeleteEventLogs(
+	ctx context.Context,
+	event api.Event,
+) error {
+	if err := l.deleteObjects(
+		ctx,
+		eventPrefix(event.ProjectID, event.ID),
+	); err != nil {
+		return errors.Wrapf(err, "error deleting logs for event %q", event.ID)
+	}
+	return nil
+}
+
+// DeleteProjectLogs deletes all logs associated with the provided project from
+// the underlying bucket.
+func (l *logsStore) DeleteProjectLogs(ctx context.Context, id string) error {
+	if err := l.deleteObjects(ctx, fmt.Sprintf("%s/", id)); err != nil {
+		return errors.Wrapf(err, "error deleting logs for project %q", id)
+	}
+	return nil
+}
+
+func (l *logsStore) WriteLogs(
+	ctx context.Context,
+	projectID string,
+	eventID string,
+	selector api.LogsSelector,
+	batchID string,
+	logEntries []api.LogEntry,
+) error {
+	content := &bytes.Buffer{}
+	gzipWriter := gzip.NewWriter(content)
+	encoder := json.NewEncoder(gzipWriter)
+	for _, logEntry := range logEntries {
+		if err := encoder.Encode(
+			logRecord{
+				Time:    logEntry.Time,
+				Message: logEntry.Message,
+			},
+		); err != nil {
+			return errors.Wrapf(err, "error encoding logs for event %q", eventID)
+		}
+	}
+	if err := gzipWriter.Close(); err != nil {
+		return errors.Wrapf(err, "error compressing logs for event %q", eventID)
+	}
+	key := fmt.Sprintf(
+		"%s%s.%s",
+		containerPrefix(projectID, eventID, selector),
+		batchID,
+		logObjectExtension,
+	)
+	if err := l.client.PutObject(ctx, key, content.Bytes()); err != nil {
+		return errors.Wrapf(err, "error writing logs for event %q", eventID)
+	}
+	return nil
+}
+
+// readContainerLogs returns all lines of logs stored in objects beneath the
+// specified prefix, ordered by the time each line was written. Lines without a
+// time are ordered as if written before any others.
+func (l *logsStore) readContainerLogs(
+	ctx context.Context,
+	prefix string,
+) ([]api.LogEntry, error) {
+	keys, _, err := l.client.ListObjects(ctx, prefix, "/")
+	if err != nil {
+		return nil, err
+	}
+	logEntries := []api.LogEntry{}
+	for _, key := range keys {
+		content, err := l.client.GetObject(ctx, key)
+		if err != nil {
+			// The object may have been deleted since it was listed
+			if s3.IsNotFoundError(err) {
+				continue
+			}
+			return nil, err
+		}
+		objectLogEntries, err := decodeLogObject(content)
+		if err != nil {
+			return nil, errors.Wrapf(err, "error decoding object %q", key)
+		}
+		logEntries = append(logEntries, objectLogEntries...)
+	}
+	// Objects are listed in lexical order, which isn't necessarily the order in
+	// which they were written
+	sort.SliceStable(logEntries, func(i, j int) bool {
+		if logEntries[i].Time == nil || logEntries[j].Time == nil {
+			return logEntries[i].Time == nil && logEntries[j].Time != nil
+		}
+		return logEntries[i].Time.Before(*logEntries[j].Time)
+	})
+	return logEntries, nil
+}
+
+// deleteObjects deletes all objects beneath the specified prefix.
+func (l *logsStore) deleteObjects(ctx context.Context, prefix string) error {
+	keys, _, err := l.client.ListObjects(ctx, prefix, "")
+	if err != nil {
+		return err
+	}
+	for _, key := range keys {
+		if err = l.client.DeleteObject(ctx, key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// decodeLogObject returns the lines of logs contained in the provided gzipped
+// object.
+func decodeLogObject(content []byte) ([]api.LogEntry, error) {
+	gzipReader, err := gzip.NewReader(bytes.NewReader(content))
+	if err != nil {
+		return nil, err
+	}
+	defer gzipReader.Close()
+	logEntries := []api.LogEntry{}
+	decoder := json.NewDecoder(gzipReader)
+	for {
+		record := logRecord{}
+		if err = decoder.Decode(&record); err != nil {
+			if err == io.EOF {
+				return logEntries, nil
+			}
+			return nil, err
+		}
+		logEntries = append(
+			logEntries,
+			api.LogEntry{
+				Time:    record.Time,
+				Message: record.Message,
+			},
+		)
+	}
+}
+
+// filterLogEntries returns the provided lines of logs that were written within
+// the time range specified by the provided api.LogStreamOptions, limited to the
+// number of most recent lines specified by the same.
+func filterLogEntries(
+	logEntries []api.LogEntry,
+	opts api.LogStreamOptions,
+) []api.LogEntry {
+	filtered := []api.LogEntry{}
+	for _, logEntry := range logEntries {
+		if logEntry.Time != nil {
+			if opts.Since != nil && logEntry.Time.Before(*opts.Since) {
+				continue
+			}
+			if opts.Until != nil && logEntry.Time.After(*opts.Until) {
+				continue
+			}
+		} else if opts.Since != nil || opts.Until != nil {
+			// There's no telling whether the line falls within the time range
+			continue
+		}
+		filtered = append(filtered, logEntry)
+	}
+	if opts.TailLines > 0 && int64(len(filtered)) > opts.TailLines {
+		filtered = filtered[int64(len(filtered))-opts.TailLines:]
+	}
+	return filtered
+}
+
+// eventPrefix returns the prefix of all objects containing logs for the
+// specified Event.
+func eventPrefix(projectID string, eventID string) string {
+	return fmt.Sprintf("%s/%s/", projectID, eventID)
+}
+
+// containerPrefix returns the prefix of all objects containing logs for the
+// container specified by the provided api.LogsSelector.
+func containerPrefix(
+	projectID string,
+	eventID string,
+	selector api.LogsSelector,
+) string {
+	if selector.Job == "" {
+		return fmt.Sprintf(
+			"%s%s/%s/",
+			eventPrefix(projectID, eventID),
+			workerDir,
+			selector.Container,
+		)
+	}
+	attempt := selector.Attempt
+	if attempt < 1 {
+		attempt = 1
+	}
+	return fmt.Sprintf(
+		"%s%s/%s/%d/%s/",
+		eventPrefix(projectID, eventID),
+		jobsDir,
+		selector.Job,
+		attempt,
+		selector.Container,
+	)
+}
+
+// logSearchMatchForDir returns an api.LogSearchMatch identifying the container
+// whose logs are stored in the specified directory, and true, if the directory
+// is one in which logs for a container are stored. Otherwise, it returns false.
+func logSearchMatchForDir(
+	eventID string,
+	dir string,
+) (api.LogSearchMatch, bool) {
+	// The first two parts of the directory are the Project and Event IDs
+	parts := strings.Split(dir, "/")
+	if len(parts) < 3 {
+		return api.LogSearchMatch{}, false
+	}
+	parts = parts[2:]
+	switch {
+	case len(parts) == 2 && parts[0] == workerDir:
+		return api.LogSearchMatch{
+			EventID:   eventID,
+			Container: parts[1],
+		}, true
+	case len(parts) == 4 && parts[0] == jobsDir:
+		attempt, err := strconv.Atoi(parts[2])
+		if err != nil {
+			return api.LogSearchMatch{}, false
+		}
+		return api.LogSearchMatch{
+			EventID:   eventID,
+			Job:       parts[1],
+			Attempt:   attempt,
+			Container: parts[3],
+		}, true
+	}
+	return api.LogSearchMatch{}, false
+}
@@ -0,0 +1,406 @@
+package s3
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/brigadecore/brigade/v2/apiserver/internal/api"
+	"github.com/brigadecore/brigade/v2/apiserver/internal/lib/s3"
+	s3Testing "github.com/brigadecore/brigade/v2/apiserver/internal/lib/s3/testing" // nolint: lll
+	"github.com/brigadecore/brigade/v2/apiserver/internal/meta"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testBucket    = "brigade"
+	testProjectID = "bluebook"
+	testEventID   = "123456789"
+)
+
+var testEvent = api.Event{
+	ObjectMeta: meta.ObjectMeta{
+		ID: testEventID,
+	},
+	ProjectID: testProjectID,
+}
+
+// newTestLogsStore returns a logsStore backed by an in-memory stand-in for an
+// S3-compatible object storage service. Callers are responsible for closing
+// the returned server.
+func newTestLogsStore(t *testing.T) (*logsStore, *s3Testing.Server) {
+	server := s3Testing.NewServer(testBucket, "minioadmin")
+	client, err := s3.NewClient(
+		s3.ClientConfig{
+			Endpoint:        server.URL,
+			Region:          "us-east-1",
+			Bucket:          testBucket,
+			AccessKeyID:     "minioadmin",
+			SecretAccessKey: "minioadmin",
+			PathStyle:       true,
+		},
+	)
+	require.NoError(t, err)
+	return NewLogsStore(client).(*logsStore), server
+}
+
+// aggregatorObject returns a gzipped object resembling one written by the log
+// aggregator, containing one line of logs for each of the provided messages,
+// written one second apart beginning at the provided time.
+func aggregatorObject(
+	t *testing.T,
+	start time.Time,
+	messages ...string,
+) []byte {
+	content := &bytes.Buffer{}
+	gzipWriter := gzip.NewWriter(content)
+	for i, message := range messages {
+		_, err := fmt.Fprintf(
+			gzipWriter,
+			`{"component":"worker","event":%q,"project":%q,"container":"worker",`+
+				`"log":%q,"time":%q}`+"\n",
+			testEventID,
+			testProjectID,
+			message,
+			start.Add(time.Duration(i)*time.Second).Format(time.RFC3339Nano),
+		)
+		require.NoError(t, err)
+	}
+	require.NoError(t, gzipWriter.Close())
+	return content.Bytes()
+}
+
+func messagesFrom(logEntryCh <-chan api.LogEntry) []string {
+	messages := []string{}
+	for logEntry := range logEntryCh {
+		messages = append(messages, logEntry.Message)
+	}
+	return messages
+}
+
+func TestLogsStoreStreamLogs(t *testing.T) {
+	t0 := time.Date(2021, time.December, 1, 12, 0, 0, 0, time.UTC)
+	store, server := newTestLogsStore(t)
+	defer server.Close()
+	// Lexical order of these objects differs from the order they were written
+	server.PutObject(
+		"bluebook/123456789/worker/worker/b.gz",
+		aggregatorObject(t, t0, "a", "b"),
+	)
+	server.PutObject(
+		"bluebook/123456789/worker/worker/a.gz",
+		aggregatorObject(t, t0.Add(2*time.Second), "c", "d"),
+	)
+	server.PutObject(
+		"bluebook/123456789/jobs/italian/1/italian/a.gz",
+		aggregatorObject(t, t0, "first attempt"),
+	)
+	server.PutObject(
+		"bluebook/123456789/jobs/italian/2/italian/a.gz",
+		aggregatorObject(t, t0, "second attempt"),
+	)
+
+	since := t0.Add(time.Second)
+	until := t0.Add(2 * time.Second)
+	testCases := []struct {
+		name             string
+		selector         api.LogsSelector
+		opts             api.LogStreamOptions
+		expectedMessages []string
+	}{
+		{
+			name: "worker logs",
+			selector: api.LogsSelector{
+				Container: "worker",
+			},
+			expectedMessages: []string{"a", "b", "c", "d"},
+		},
+		{
+			name: "tail lines",
+			selector: api.LogsSelector{
+				Container: "worker",
+			},
+			opts: api.LogStreamOptions{
+				TailLines: 3,
+			},
+			expectedMessages: []string{"b", "c", "d"},
+		},
+		{
+			name: "time range",
+			selector: api.LogsSelector{
+				Container: "worker",
+			},
+			opts: api.LogStreamOptions{
+				Since: &since,
+				Until: &until,
+			},
+			expectedMessages: []string{"b", "c"},
+		},
+		{
+			name: "job logs with unspecified attempt",
+			selector: api.LogsSelector{
+				Job:       "italian",
+				Container: "italian",
+			},
+			expectedMessages: []string{"first attempt"},
+		},
+		{
+			name: "job logs with specified attempt",
+			selector: api.LogsSelector{
+				Job:       "italian",
+				Container: "italian",
+				Attempt:   2,
+			},
+			expectedMessages: []string{"second attempt"},
+		},
+		{
+			name: "no logs",
+			selector: api.LogsSelector{
+				Container: "vcs",
+			},
+			expectedMessages: []string{},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			logEntryCh, err := store.StreamLogs(
+				context.Background(),
+				api.Project{},
+				testEvent,
+				testCase.selector,
+				testCase.opts,
+			)
+			require.NoError(t, err)
+			require.Equal(t, testCase.expectedMessages, messagesFrom(logEntryCh))
+		})
+	}
+
+	t.Run("corrupt object", func(t *testing.T) {
+		server.PutObject(
+			"bluebook/123456789/worker/vcs/a.gz",
+			[]byte("not gzipped"),
+		)
+		_, err := store.StreamLogs(
+			context.Background(),
+			api.Project{},
+			testEvent,
+			api.LogsSelector{Container: "vcs"},
+			api.LogStreamOptions{},
+		)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "error decoding object")
+	})
+}
+
+func TestLogsStoreSearchLogs(t *testing.T) {
+	t0 := time.Date(2021, time.December, 1, 12, 0, 0, 0, time.UTC)
+	store, server := newTestLogsStore(t)
+	defer server.Close()
+	server.PutObject(
+		"bluebook/123456789/worker/worker/a.gz",
+		aggregatorObject(t, t0, "starting", "FAIL", "done"),
+	)
+	server.PutObject(
+		"bluebook/123456789/jobs/italian/2/italian/a.gz",
+		aggregatorObject(t, t0, "fail", "FAIL"),
+	)
+	// This belongs to another Event and should not be searched
+	server.PutObject(
+		"bluebook/987654321/worker/worker/a.gz",
+		aggregatorObject(t, t0, "FAIL"),
+	)
+
+	t.Run("invalid expression", func(t *testing.T) {
+		_, err := store.SearchLogs(
+			context.Background(),
+			testEvent,
+			"(",
+			api.LogSearchOptions{Limit: 10},
+		)
+		require.Error(t, err)
+	})
+
+	t.Run("with context", func(t *testing.T) {
+		matches, err := store.SearchLogs(
+			context.Background(),
+			testEvent,
+			"FAIL",
+			api.LogSearchOptions{
+				Limit:        10,
+				ContextLines: 1,
+			},
+		)
+		require.NoError(t, err)
+		require.Len(t, matches, 2)
+		require.Equal(t, testEventID, matches[0].EventID)
+		require.Empty(t, matches[0].Job)
+		require.Equal(t, "worker", matches[0].Container)
+		require.Equal(t, "FAIL", matches[0].LogEntry.Message)
+		require.Equal(t, "starting", matches[0].Before[0].Message)
+		require.Equal(t, "done", matches[0].After[0].Message)
+		require.Equal(t, "italian", matches[1].Job)
+		require.Equal(t, 2, matches[1].Attempt)
+		require.Equal(t, "italian", matches[1].Container)
+		require.Equal(t, "fail", matches[1].Before[0].Message)
+	})
+
+	t.Run("limited", func(t *testing.T) {
+		matches, err := store.SearchLogs(
+			context.Background(),
+			testEvent,
+			"(?i)fail",
+			api.LogSearchOptions{Limit: 2},
+		)
+		require.NoError(t, err)
+		require.Len(t, matches, 2)
+		require.Equal(t, "worker", matches[0].Container)
+		require.Equal(t, "fail", matches[1].LogEntry.Message)
+	})
+}
+
+func TestLogsStoreDeleteEventLogs(t *testing.T) {
+	store, server := newTestLogsStore(t)
+	defer server.Close()
+	for _, key := range []string{
+		"bluebook/123456789/worker/worker/a.gz",
+		"bluebook/123456789/jobs/italian/1/italian/a.gz",
+		"bluebook/987654321/worker/worker/a.gz",
+		"bluebook/1234567890/worker/worker/a.gz",
+		// Event IDs are unique, so this can't exist in practice, but it
+		// demonstrates that only the Event's own Project's logs are examined
+		"italian/123456789/worker/worker/a.gz",
+	} {
+		server.PutObject(key, []byte{})
+	}
+	err := store.DeleteEventLogs(context.Background(), testEvent)
+	require.NoError(t, err)
+	require.Equal(
+		t,
+		[]string{
+			"bluebook/1234567890/worker/worker/a.gz",
+			"bluebook/987654321/worker/worker/a.gz",
+			"italian/123456789/worker/worker/a.gz",
+		},
+		sortedKeys(server.Objects()),
+	)
+}
+
+func TestLogsStoreDeleteProjectLogs(t *testing.T) {
+	store, server := newTestLogsStore(t)
+	defer server.Close()
+	for _, key := range []string{
+		"bluebook/123456789/worker/worker/a.gz",
+		"bluebook/987654321/jobs/italian/1/italian/a.gz",
+		"bluebooks/123456789/worker/worker/a.gz",
+	} {
+		server.PutObject(key, []byte{})
+	}
+	err := store.DeleteProjectLogs(context.Background(), testProjectID)
+	require.NoError(t, err)
+	require.Equal(
+		t,
+		[]string{"bluebooks/123456789/worker/worker/a.gz"},
+		sortedKeys(server.Objects()),
+	)
+}
+
+func TestLogsStoreWriteLogs(t *testing.T) {
+	t0 := time.Date(2021, time.December, 1, 12, 0, 0, 0, time.UTC)
+	store, server := newTestLogsStore(t)
+	defer server.Close()
+	selector := api.LogsSelector{
+		Job:       "italian",
+		Container: "helper",
+		Attempt:   1,
+	}
+	logEntries := []api.LogEntry{
+		{
+			Time:    &t0,
+			Message: "foo",
+		},
+		{
+			// Lines without a time should survive the round trip
+			Message: "bar",
+		},
+	}
+	err := store.WriteLogs(
+		context.Background(),
+		testProjectID,
+		testEventID,
+		selector,
+		"migrated",
+		logEntries,
+	)
+	require.NoError(t, err)
+	require.Equal(
+		t,
+		[]string{"bluebook/123456789/jobs/italian/1/helper/migrated.json.gz"},
+		sortedKeys(server.Objects()),
+	)
+	logEntryCh, err := store.StreamLogs(
+		context.Background(),
+		api.Project{},
+		testEvent,
+		selector,
+		api.LogStreamOptions{},
+	)
+	require.NoError(t, err)
+	// Lines without a time are ordered first
+	require.Equal(t, []string{"bar", "foo"}, messagesFrom(logEntryCh))
+}
+
+func TestLogSearchMatchForDir(t *testing.T) {
+	testCases := []struct {
+		dir           string
+		expectedMatch api.LogSearchMatch
+		expectedOK    bool
+	}{
+		{
+			dir: "bluebook/123456789/worker/vcs",
+			expectedMatch: api.LogSearchMatch{
+				EventID:   testEventID,
+				Container: "vcs",
+			},
+			expectedOK: true,
+		},
+		{
+			dir: "bluebook/123456789/jobs/italian/3/helper",
+			expectedMatch: api.LogSearchMatch{
+				EventID:   testEventID,
+				Job:       "italian",
+				Attempt:   3,
+				Container: "helper",
+			},
+			expectedOK: true,
+		},
+		{
+			dir: "bluebook/123456789/jobs/italian/latest/helper",
+		},
+		{
+			dir: "bluebook/123456789/worker",
+		},
+		{
+			dir: "bluebook",
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.dir, func(t *testing.T) {
+			match, ok := logSearchMatchForDir(testEventID, testCase.dir)
+			require.Equal(t, testCase.expectedOK, ok)
+			require.Equal(t, testCase.expectedMatch, match)
+		})
+	}
+}
+
+func sortedKeys(objects map[string][]byte) []string {
+	keys := make([]string, 0, len(objects))
+	for key := range objects {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,300 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/brigadecore/brigade/v2/apiserver/internal/meta"
+	"github.com/pkg/errors"
+	"github.com/robfig/cron/v3"
+)
+
+const (
+	// CronEventSource is the source of all Events emitted on behalf of a
+	// Project's EventSchedules.
+	CronEventSource = "brigade.sh/cron"
+	// ScheduleLabelKey is the key of the label applied to Events emitted on
+	// behalf of an EventSchedule. The value of the label is the name of the
+	// EventSchedule.
+	ScheduleLabelKey = "brigade.sh/schedule"
+)
+
+// EventSchedule describes an Event that should be emitted into Brigade's event
+// bus, on behalf of a Project, on a recurring basis.
+type EventSchedule struct {
+	// Name is a name for the EventSchedule that is unique among all of a
+	// Project's EventSchedules.
+	Name string `json:"name" bson:"name"`
+	// Cron is a standard, five field cron expression (or a descriptor such as
+	// "@hourly") that specifies when an Event should be emitted. Unless the
+	// expression is prefixed with "CRON_TZ=<zone>", it is evaluated in UTC.
+	Cron string `json:"cron" bson:"cron"`
+	// Type specifies the type of the Events emitted. All such Events have the
+	// source "brigade.sh/cron".
+	Type string `json:"type" bson:"type"`
+	// Payload optionally specifies a payload for the Events emitted.
+	Payload string `json:"payload,omitempty" bson:"payload,omitempty"`
+	// Labels optionally specifies additional labels for the Events emitted.
+	Labels map[string]string `json:"labels,omitempty" bson:"labels,omitempty"`
+}
+
+// cronParser parses standard, five field cron expressions and descriptors. It
+// is used instead of cron.ParseStandard, which evaluates expressions that lack
+// a time zone in the API server's local time zone.
+var cronParser = cron.NewParser(
+	cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor,
+)
+
+// ParseCron parses the provided cron expression. Expressions that do not
+// explicitly specify a time zone are evaluated in UTC.
+func ParseCron(expr string) (cron.Schedule, error) {
+	expr = strings.TrimSpace(expr)
+	if !strings.HasPrefix(expr, "CRON_TZ=") && !strings.HasPrefix(expr, "TZ=") {
+		expr = "CRON_TZ=UTC " + expr
+	}
+	return cronParser.Parse(expr)
+}
+
+// latestFireTime returns the latest time in the interval (after, until] at
+// which the provided schedule fires. If no such time exists, nil is returned.
+// Intentionally, only the LATEST such time is returned so that any number of
+// missed firings are coalesced into a single one.
+func latestFireTime(
+	schedule cron.Schedule,
+	after time.Time,
+	until time.Time,
+) *time.Time {
+	var latest *time.Time
+	for next := schedule.Next(after.UTC()); !next.After(until); next =
+		schedule.Next(next) {
+		t := next
+		latest = &t
+	}
+	return latest
+}
+
+// validateEventSchedules returns a *meta.ErrBadRequest error if any of the
+// provided EventSchedules has an unparsable cron expression or if any two
+// share the same name.
+func validateEventSchedules(schedules []EventSchedule) error {
+	details := []string{}
+	names := map[string]struct{}{}
+	for _, schedule := range schedules {
+		if _, ok := names[schedule.Name]; ok {
+			details = append(
+				details,
+				fmt.Sprintf("schedule name %q is not unique", schedule.Name),
+			)
+		}
+		names[schedule.Name] = struct{}{}
+		if _, err := ParseCron(schedule.Cron); err != nil {
+			details = append(
+				details,
+				fmt.Sprintf(
+					"schedule %q has invalid cron expression %q: %s",
+					schedule.Name,
+					schedule.Cron,
+					err,
+				),
+			)
+		}
+	}
+	if len(details) > 0 {
+		return &meta.ErrBadRequest{
+			Reason:  "Invalid event schedules.",
+			Details: details,
+		}
+	}
+	return nil
+}
+
+// CronServiceConfig encapsulates configuration options for the CronService.
+type CronServiceConfig struct {
+	// Interval specifies how frequently the CronService should check for
+	// EventSchedules that are due.
+	Interval time.Duration
+}
+
+// CronService is the specialized interface for emitting Events on behalf of
+// Projects' EventSchedules. It's decoupled from underlying technology choices
+// (e.g. data store, message bus, etc.) to keep business logic reusable and
+// consistent while the underlying tech stack remains free to change.
+type CronService interface {
+	// Run periodically emits Events for all EventSchedules that are due. It
+	// blocks until the provided context is canceled.
+	Run(context.Context)
+}
+
+type cronService struct {
+	projectsStore ProjectsStore
+	cronStore     CronStore
+	createEventFn func(
+		context.Context,
+		Event,
+		EventCreateOptions,
+	) (meta.List[Event], error)
+	config CronServiceConfig
+	// nowFn is overridable for testing purposes
+	nowFn func() time.Time
+}
+
+// NewCronService returns a specialized interface for emitting Events on behalf
+// of Projects' EventSchedules. Events are created using the provided function,
+// which will ordinarily be the Create function of an EventsService.
+func NewCronService(
+	projectsStore ProjectsStore,
+	cronStore CronStore,
+	createEventFn func(
+		context.Context,
+		Event,
+		EventCreateOptions,
+	) (meta.List[Event], error),
+	config CronServiceConfig,
+) CronService {
+	return &cronService{
+		projectsStore: projectsStore,
+		cronStore:     cronStore,
+		createEventFn: createEventFn,
+		config:        config,
+		nowFn: func() time.Time {
+			return time.Now().UTC()
+		},
+	}
+}
+
+func (c *cronService) Run(ctx context.Context) {
+	ticker := time.NewTicker(c.config.Interval)
+	defer ticker.Stop()
+	for {
+		if err := c.fireDueSchedules(ctx); err != nil {
+			log.Println(err)
+		}
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// fireDueSchedules emits Events for all EventSchedules that are due.
+func (c *cronService) fireDueSchedules(ctx context.Context) error {
+	projects, err := c.projectsStore.ListScheduled(ctx)
+	if err != nil {
+		return errors.Wrap(err, "error retrieving scheduled projects from store")
+	}
+	for _, project := range projects.Items {
+		for _, schedule := range project.Spec.Schedules {
+			if err := c.fireIfDue(ctx, project.ID, schedule); err != nil {
+				log.Println(err)
+			}
+		}
+	}
+	return nil
+}
+
+// fireIfDue emits an Event for the specified EventSchedule if it is due. Any
+// number of missed firings are coalesced into a single one.
+func (c *cronService) fireIfDue(
+	ctx context.Context,
+	projectID string,
+	schedule EventSchedule,
+) error {
+	cronSchedule, err := ParseCron(schedule.Cron)
+	if err != nil {
+		return errors.Wrapf(
+			err,
+			"error parsing cron expression for schedule %q of project %q",
+			schedule.Name,
+			projectID,
+		)
+	}
+	now := c.nowFn()
+	lastFired, err := c.cronStore.GetLastFired(ctx, projectID, schedule.Name)
+	if err != nil {
+		return errors.Wrapf(
+			err,
+			"error retrieving last fired time for schedule %q of project %q",
+			schedule.Name,
+			projectID,
+		)
+	}
+	// If the schedule has never fired, we only look back as far as one interval
+	// so that a newly added schedule doesn't fire for times that predate it.
+	after := now.Add(-c.config.Interval)
+	if lastFired != nil {
+		after = *lastFired
+	}
+	fireTime := latestFireTime(cronSchedule, after, now)
+	if fireTime == nil {
+		return nil
+	}
+	// Claiming the fire time guarantees that, even with multiple API server
+	// replicas, a given schedule fires only once for a given time.
+	claimed, err :=
+		c.cronStore.Claim(ctx, projectID, schedule.Name, *fireTime)
+	if err != nil {
+		return errors.Wrapf(
+			err,
+			"error claiming fire time for schedule %q of project %q",
+			schedule.Name,
+			projectID,
+		)
+	}
+	if !claimed {
+		return nil
+	}
+	labels := map[string]string{}
+	for k, v := range schedule.Labels {
+		labels[k] = v
+	}
+	labels[ScheduleLabelKey] = schedule.Name
+	if _, err = c.createEventFn(
+		ContextWithPrincipal(ctx, &CronPrincipal{}),
+		Event{
+			ProjectID: projectID,
+			Source:    CronEventSource,
+			Type:      schedule.Type,
+			Labels:    labels,
+			Payload:   schedule.Payload,
+		},
+		EventCreateOptions{},
+	); err != nil {
+		return errors.Wrapf(
+			err,
+			"error creating event for schedule %q of project %q",
+			schedule.Name,
+			projectID,
+		)
+	}
+	return nil
+}
+
+// CronStore is an interface for components that implement persistence
+// concerns for the last fired times of EventSchedules.
+type CronStore interface {
+	// GetLastFired returns the last time at which the specified Project's
+	// specified EventSchedule fired. If the EventSchedule has never fired,
+	// implementations MUST return nil.
+	GetLastFired(
+		ctx context.Context,
+		projectID string,
+		schedule string,
+	) (*time.Time, error)
+	// Claim atomically records the provided time as the last time at which the
+	// specified Project's specified EventSchedule fired, but ONLY if no equal or
+	// later time has already been recorded. The bool return value indicates
+	// whether the time was successfully claimed.
+	Claim(
+		ctx context.Context,
+		projectID string,
+		schedule string,
+		fireTime time.Time,
+	) (bool, error)
+	// DeleteByProjectID deletes all records associated with the specified
+	// Project.
+	DeleteByProjectID(ctx context.Context, projectID string) error
+}
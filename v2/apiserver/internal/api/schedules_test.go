@@ -0,0 +1,528 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/brigadecore/brigade/v2/apiserver/internal/meta"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCron(t *testing.T) {
+	// Whatever the local time zone, expressions without one are evaluated in UTC
+	defer func(local *time.Location) { time.Local = local }(time.Local)
+	time.Local = time.FixedZone("UTC-5", -5*60*60)
+	after := time.Date(2021, time.May, 1, 0, 30, 0, 0, time.UTC)
+	testCases := []struct {
+		name         string
+		expr         string
+		expectedNext time.Time
+	}{
+		{
+			name:         "no time zone",
+			expr:         "0 12 * * *",
+			expectedNext: time.Date(2021, time.May, 1, 12, 0, 0, 0, time.UTC),
+		},
+		{
+			name:         "descriptor",
+			expr:         "@daily",
+			expectedNext: time.Date(2021, time.May, 2, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:         "explicit time zone",
+			expr:         "CRON_TZ=Etc/GMT+5 0 12 * * *",
+			expectedNext: time.Date(2021, time.May, 1, 17, 0, 0, 0, time.UTC),
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			schedule, err := ParseCron(testCase.expr)
+			require.NoError(t, err)
+			require.True(
+				t,
+				testCase.expectedNext.Equal(schedule.Next(after)),
+				"expected %s; got %s",
+				testCase.expectedNext,
+				schedule.Next(after),
+			)
+		})
+	}
+}
+
+func TestLatestFireTime(t *testing.T) {
+	schedule, err := ParseCron("0 * * * *") // Hourly, on the hour
+	require.NoError(t, err)
+	testCases := []struct {
+		name       string
+		after      time.Time
+		until      time.Time
+		assertions func(*time.Time)
+	}{
+		{
+			name:  "not due",
+			after: time.Date(2021, time.May, 1, 0, 0, 0, 0, time.UTC),
+			until: time.Date(2021, time.May, 1, 0, 30, 0, 0, time.UTC),
+			assertions: func(fireTime *time.Time) {
+				require.Nil(t, fireTime)
+			},
+		},
+		{
+			name:  "due once",
+			after: time.Date(2021, time.May, 1, 0, 30, 0, 0, time.UTC),
+			until: time.Date(2021, time.May, 1, 1, 0, 0, 0, time.UTC),
+			assertions: func(fireTime *time.Time) {
+				require.NotNil(t, fireTime)
+				require.Equal(
+					t,
+					time.Date(2021, time.May, 1, 1, 0, 0, 0, time.UTC),
+					*fireTime,
+				)
+			},
+		},
+		{
+			name:  "missed firings are coalesced",
+			after: time.Date(2021, time.May, 1, 0, 0, 0, 0, time.UTC),
+			until: time.Date(2021, time.May, 1, 5, 30, 0, 0, time.UTC),
+			assertions: func(fireTime *time.Time) {
+				require.NotNil(t, fireTime)
+				require.Equal(
+					t,
+					time.Date(2021, time.May, 1, 5, 0, 0, 0, time.UTC),
+					*fireTime,
+				)
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.assertions(
+				latestFireTime(schedule, testCase.after, testCase.until),
+			)
+		})
+	}
+}
+
+func TestValidateEventSchedules(t *testing.T) {
+	testCases := []struct {
+		name       string
+		schedules  []EventSchedule
+		assertions func(error)
+	}{
+		{
+			name: "invalid cron expression",
+			schedules: []EventSchedule{
+				{
+					Name: "nightly",
+					Cron: "every night",
+				},
+			},
+			assertions: func(err error) {
+				require.Error(t, err)
+				require.IsType(t, &meta.ErrBadRequest{}, err)
+				require.Contains(t, err.Error(), "invalid cron expression")
+			},
+		},
+		{
+			name: "duplicate schedule names",
+			schedules: []EventSchedule{
+				{
+					Name: "nightly",
+					Cron: "@daily",
+				},
+				{
+					Name: "nightly",
+					Cron: "0 2 * * *",
+				},
+			},
+			assertions: func(err error) {
+				require.Error(t, err)
+				require.IsType(t, &meta.ErrBadRequest{}, err)
+				require.Contains(t, err.Error(), "is not unique")
+			},
+		},
+		{
+			name: "valid",
+			schedules: []EventSchedule{
+				{
+					Name: "nightly",
+					Cron: "@daily",
+				},
+				{
+					Name: "cleanup",
+					Cron: "CRON_TZ=America/New_York 30 4 * * 1-5",
+				},
+			},
+			assertions: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.assertions(validateEventSchedules(testCase.schedules))
+		})
+	}
+}
+
+func TestNewCronService(t *testing.T) {
+	projectsStore := &mockProjectsStore{}
+	cronStore := &mockCronStore{}
+	svc, ok := NewCronService(
+		projectsStore,
+		cronStore,
+		func(
+			context.Context,
+			Event,
+			EventCreateOptions,
+		) (meta.List[Event], error) {
+			return meta.List[Event]{}, nil
+		},
+		CronServiceConfig{
+			Interval: time.Minute,
+		},
+	).(*cronService)
+	require.True(t, ok)
+	require.Same(t, projectsStore, svc.projectsStore)
+	require.Same(t, cronStore, svc.cronStore)
+	require.NotNil(t, svc.createEventFn)
+	require.Equal(t, time.Minute, svc.config.Interval)
+	require.NotNil(t, svc.nowFn)
+}
+
+func TestCronServiceFireDueSchedules(t *testing.T) {
+	testCases := []struct {
+		name       string
+		service    *cronService
+		assertions func(error)
+	}{
+		{
+			name: "error listing scheduled projects",
+			service: &cronService{
+				projectsStore: &mockProjectsStore{
+					ListScheduledFn: func(context.Context) (meta.List[Project], error) {
+						return meta.List[Project]{}, errors.New("something went wrong")
+					},
+				},
+			},
+			assertions: func(err error) {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), "something went wrong")
+				require.Contains(t, err.Error(), "error retrieving scheduled projects")
+			},
+		},
+		{
+			name: "success",
+			service: &cronService{
+				projectsStore: &mockProjectsStore{
+					ListScheduledFn: func(context.Context) (meta.List[Project], error) {
+						return meta.List[Project]{
+							Items: []Project{
+								{
+									Spec: ProjectSpec{
+										Schedules: []EventSchedule{
+											{
+												Name: "invalid",
+												Cron: "every night",
+											},
+										},
+									},
+								},
+							},
+						}, nil
+					},
+				},
+			},
+			assertions: func(err error) {
+				// Errors pertaining to individual schedules are logged, but aren't
+				// returned.
+				require.NoError(t, err)
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.assertions(
+				testCase.service.fireDueSchedules(context.Background()),
+			)
+		})
+	}
+}
+
+func TestCronServiceFireIfDue(t *testing.T) {
+	const testProjectID = "italian"
+	testNow := time.Date(2021, time.May, 1, 2, 0, 30, 0, time.UTC)
+	testSchedule := EventSchedule{
+		Name:    "nightly",
+		Cron:    "0 2 * * *",
+		Type:    "build",
+		Payload: "foo",
+		Labels: map[string]string{
+			"foo": "bar",
+		},
+	}
+	testCases := []struct {
+		name       string
+		schedule   EventSchedule
+		service    *cronService
+		assertions func(error)
+	}{
+		{
+			name: "error parsing cron expression",
+			schedule: EventSchedule{
+				Name: "nightly",
+				Cron: "every night",
+			},
+			service: &cronService{},
+			assertions: func(err error) {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), "error parsing cron expression")
+			},
+		},
+		{
+			name:     "error retrieving last fired time",
+			schedule: testSchedule,
+			service: &cronService{
+				cronStore: &mockCronStore{
+					GetLastFiredFn: func(
+						context.Context,
+						string,
+						string,
+					) (*time.Time, error) {
+						return nil, errors.New("something went wrong")
+					},
+				},
+			},
+			assertions: func(err error) {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), "something went wrong")
+				require.Contains(t, err.Error(), "error retrieving last fired time")
+			},
+		},
+		{
+			name:     "not due",
+			schedule: testSchedule,
+			service: &cronService{
+				cronStore: &mockCronStore{
+					GetLastFiredFn: func(
+						context.Context,
+						string,
+						string,
+					) (*time.Time, error) {
+						lastFired := time.Date(2021, time.May, 1, 2, 0, 0, 0, time.UTC)
+						return &lastFired, nil
+					},
+				},
+			},
+			assertions: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name:     "error claiming fire time",
+			schedule: testSchedule,
+			service: &cronService{
+				cronStore: &mockCronStore{
+					GetLastFiredFn: func(
+						context.Context,
+						string,
+						string,
+					) (*time.Time, error) {
+						return nil, nil
+					},
+					ClaimFn: func(
+						context.Context,
+						string,
+						string,
+						time.Time,
+					) (bool, error) {
+						return false, errors.New("something went wrong")
+					},
+				},
+			},
+			assertions: func(err error) {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), "something went wrong")
+				require.Contains(t, err.Error(), "error claiming fire time")
+			},
+		},
+		{
+			name:     "fire time already claimed",
+			schedule: testSchedule,
+			service: &cronService{
+				cronStore: &mockCronStore{
+					GetLastFiredFn: func(
+						context.Context,
+						string,
+						string,
+					) (*time.Time, error) {
+						return nil, nil
+					},
+					ClaimFn: func(
+						context.Context,
+						string,
+						string,
+						time.Time,
+					) (bool, error) {
+						return false, nil
+					},
+				},
+				createEventFn: func(
+					context.Context,
+					Event,
+					EventCreateOptions,
+				) (meta.List[Event], error) {
+					require.Fail(t, "no event should have been created")
+					return meta.List[Event]{}, nil
+				},
+			},
+			assertions: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name:     "error creating event",
+			schedule: testSchedule,
+			service: &cronService{
+				cronStore: &mockCronStore{
+					GetLastFiredFn: func(
+						context.Context,
+						string,
+						string,
+					) (*time.Time, error) {
+						return nil, nil
+					},
+					ClaimFn: func(
+						context.Context,
+						string,
+						string,
+						time.Time,
+					) (bool, error) {
+						return true, nil
+					},
+				},
+				createEventFn: func(
+					context.Context,
+					Event,
+					EventCreateOptions,
+				) (meta.List[Event], error) {
+					return meta.List[Event]{}, errors.New("something went wrong")
+				},
+			},
+			assertions: func(err error) {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), "something went wrong")
+				require.Contains(t, err.Error(), "error creating event")
+			},
+		},
+		{
+			name:     "success",
+			schedule: testSchedule,
+			service: &cronService{
+				cronStore: &mockCronStore{
+					GetLastFiredFn: func(
+						context.Context,
+						string,
+						string,
+					) (*time.Time, error) {
+						lastFired := time.Date(2021, time.April, 30, 2, 0, 0, 0, time.UTC)
+						return &lastFired, nil
+					},
+					ClaimFn: func(
+						_ context.Context,
+						projectID string,
+						schedule string,
+						fireTime time.Time,
+					) (bool, error) {
+						require.Equal(t, testProjectID, projectID)
+						require.Equal(t, testSchedule.Name, schedule)
+						require.Equal(
+							t,
+							time.Date(2021, time.May, 1, 2, 0, 0, 0, time.UTC),
+							fireTime,
+						)
+						return true, nil
+					},
+				},
+				createEventFn: func(
+					ctx context.Context,
+					event Event,
+					_ EventCreateOptions,
+				) (meta.List[Event], error) {
+					require.IsType(t, &CronPrincipal{}, PrincipalFromContext(ctx))
+					require.Equal(t, testProjectID, event.ProjectID)
+					require.Equal(t, CronEventSource, event.Source)
+					require.Equal(t, testSchedule.Type, event.Type)
+					require.Equal(t, testSchedule.Payload, event.Payload)
+					require.Equal(
+						t,
+						map[string]string{
+							"foo":            "bar",
+							ScheduleLabelKey: testSchedule.Name,
+						},
+						event.Labels,
+					)
+					return meta.List[Event]{}, nil
+				},
+			},
+			assertions: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.service.config.Interval = time.Minute
+			testCase.service.nowFn = func() time.Time {
+				return testNow
+			}
+			testCase.assertions(
+				testCase.service.fireIfDue(
+					context.Background(),
+					testProjectID,
+					testCase.schedule,
+				),
+			)
+		})
+	}
+}
+
+type mockCronStore struct {
+	GetLastFiredFn func(
+		ctx context.Context,
+		projectID string,
+		schedule string,
+	) (*time.Time, error)
+	ClaimFn func(
+		ctx context.Context,
+		projectID string,
+		schedule string,
+		fireTime time.Time,
+	) (bool, error)
+	DeleteByProjectIDFn func(ctx context.Context, projectID string) error
+}
+
+func (m *mockCronStore) GetLastFired(
+	ctx context.Context,
+	projectID string,
+	schedule string,
+) (*time.Time, error) {
+	return m.GetLastFiredFn(ctx, projectID, schedule)
+}
+
+func (m *mockCronStore) Claim(
+	ctx context.Context,
+	projectID string,
+	schedule string,
+	fireTime time.Time,
+) (bool, error) {
+	return m.ClaimFn(ctx, projectID, schedule, fireTime)
+}
+
+func (m *mockCronStore) DeleteByProjectID(
+	ctx context.Context,
+	projectID string,
+) error {
+	return m.DeleteByProjectIDFn(ctx, projectID)
+}
@@ -187,6 +187,10 @@ type SecretsStore interface {
 		project Project,
 		opts meta.ListOptions,
 	) (meta.List[Secret], error)
+	// Get retrieves the Secret (identified by its Key), including its Value,
+	// associated with the specified Project. If the specified Key does not
+	// exist, implementations MUST return a *meta.ErrNotFound error.
+	Get(ctx context.Context, project Project, key string) (Secret, error)
 	// Set adds or updates the provided Secret associated with the specified
 	// Project.
 	Set(ctx context.Context, project Project, secret Secret) error
@@ -319,6 +319,7 @@ type mockSecretsStore struct {
 		Project,
 		meta.ListOptions,
 	) (meta.List[Secret], error)
+	GetFn   func(context.Context, Project, string) (Secret, error)
 	SetFn   func(context.Context, Project, Secret) error
 	UnsetFn func(context.Context, Project, string) error
 }
@@ -331,6 +332,14 @@ func (m *mockSecretsStore) List(
 	return m.ListFn(ctx, project, opts)
 }
 
+func (m *mockSecretsStore) Get(
+	ctx context.Context,
+	project Project,
+	key string,
+) (Secret, error) {
+	return m.GetFn(ctx, project, key)
+}
+
 func (m *mockSecretsStore) Set(
 	ctx context.Context,
 	project Project,
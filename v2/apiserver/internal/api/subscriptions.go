@@ -0,0 +1,482 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/brigadecore/brigade/v2/apiserver/internal/lib/expressions"
+	"github.com/brigadecore/brigade/v2/apiserver/internal/meta"
+)
+
+// MatchStrategy represents a strategy for matching the values of an
+// EventSubscription's Qualifiers and Labels against the values of an Event's
+// Qualifiers and Labels.
+type MatchStrategy string
+
+const (
+	// MatchStrategyExact represents a strategy wherein values must match
+	// exactly. This is the default.
+	MatchStrategyExact MatchStrategy = "Exact"
+	// MatchStrategyGlob represents a strategy wherein values are treated as
+	// shell-style glob patterns. e.g. "release/*"
+	MatchStrategyGlob MatchStrategy = "Glob"
+	// MatchStrategyRegex represents a strategy wherein values are treated as
+	// regular expressions. Expressions are implicitly anchored; i.e. they must
+	// match an ENTIRE value.
+	MatchStrategyRegex MatchStrategy = "Regex"
+)
+
+// IsSubscribed returns a bool indicating whether ANY of the Project's
+// EventSubscriptions matches the provided Event.
+func (p Project) IsSubscribed(event Event) bool {
+	for _, subscription := range p.Spec.EventSubscriptions {
+		if subscription.Matches(event) {
+			return true
+		}
+	}
+	return false
+}
+
+// SubscriptionCriterion represents one of the criteria an Event must satisfy to
+// match an EventSubscription.
+type SubscriptionCriterion string
+
+const (
+	// SubscriptionCriterionSource represents the EventSubscription's Source.
+	SubscriptionCriterionSource SubscriptionCriterion = "Source"
+	// SubscriptionCriterionType represents the EventSubscription's Types.
+	SubscriptionCriterionType SubscriptionCriterion = "Type"
+	// SubscriptionCriterionQualifier represents one of the EventSubscription's
+	// Qualifiers.
+	SubscriptionCriterionQualifier SubscriptionCriterion = "Qualifier"
+	// SubscriptionCriterionLabel represents one of the EventSubscription's
+	// Labels.
+	SubscriptionCriterionLabel SubscriptionCriterion = "Label"
+	// SubscriptionCriterionExpression represents the EventSubscription's
+	// Expression.
+	SubscriptionCriterionExpression SubscriptionCriterion = "Expression"
+)
+
+// SubscriptionCriterionMismatch describes an EventSubscription criterion that
+// an Event failed to satisfy.
+type SubscriptionCriterionMismatch struct {
+	// Criterion indicates which criterion the Event failed to satisfy.
+	Criterion SubscriptionCriterion `json:"criterion"`
+	// Reason is a human-readable explanation of the mismatch.
+	Reason string `json:"reason"`
+}
+
+// Matches returns a bool indicating whether the provided Event matches ALL of
+// the EventSubscription's criteria.
+func (e EventSubscription) Matches(event Event) bool {
+	return len(e.Mismatches(event)) == 0
+}
+
+// Mismatches returns descriptions of ALL of the EventSubscription's criteria
+// that the provided Event fails to satisfy. If the Event matches the
+// EventSubscription, the result is empty.
+func (e EventSubscription) Mismatches(
+	event Event,
+) []SubscriptionCriterionMismatch {
+	mismatches := []SubscriptionCriterionMismatch{}
+	if e.Source != event.Source {
+		mismatches = append(
+			mismatches,
+			SubscriptionCriterionMismatch{
+				Criterion: SubscriptionCriterionSource,
+				Reason: fmt.Sprintf(
+					"event source %q does not match %q",
+					event.Source,
+					e.Source,
+				),
+			},
+		)
+	}
+	if !e.matchesType(event) {
+		mismatches = append(
+			mismatches,
+			SubscriptionCriterionMismatch{
+				Criterion: SubscriptionCriterionType,
+				Reason: fmt.Sprintf(
+					"event type %q is not among %q",
+					event.Type,
+					e.Types,
+				),
+			},
+		)
+	}
+	mismatches = append(mismatches, e.qualifierMismatches(event)...)
+	mismatches = append(mismatches, e.labelMismatches(event)...)
+	if !e.matchesExpression(event) {
+		mismatches = append(
+			mismatches,
+			SubscriptionCriterionMismatch{
+				Criterion: SubscriptionCriterionExpression,
+				Reason: fmt.Sprintf(
+					"event does not satisfy expression %q",
+					e.Expression,
+				),
+			},
+		)
+	}
+	return mismatches
+}
+
+func (e EventSubscription) matchesType(event Event) bool {
+	for _, t := range e.Types {
+		if t == event.Type || t == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+// qualifierMismatches describes any mismatches between the Event's Qualifiers
+// and the EventSubscription's Qualifiers. Because the Qualifiers field carries
+// "MUST match" semantics, the set of keys must be identical and only the values
+// are subject to the EventSubscription's MatchStrategy.
+func (e EventSubscription) qualifierMismatches(
+	event Event,
+) []SubscriptionCriterionMismatch {
+	mismatches := []SubscriptionCriterionMismatch{}
+	for _, key := range sortedKeys(e.Qualifiers) {
+		pattern := e.Qualifiers[key]
+		value, ok := event.Qualifiers[key]
+		if !ok {
+			mismatches = append(
+				mismatches,
+				SubscriptionCriterionMismatch{
+					Criterion: SubscriptionCriterionQualifier,
+					Reason:    fmt.Sprintf("event is missing qualifier %q", key),
+				},
+			)
+		} else if !e.MatchStrategy.matches(pattern, value) {
+			mismatches = append(
+				mismatches,
+				SubscriptionCriterionMismatch{
+					Criterion: SubscriptionCriterionQualifier,
+					Reason: fmt.Sprintf(
+						"event qualifier %q value %q does not match %q",
+						key,
+						value,
+						pattern,
+					),
+				},
+			)
+		}
+	}
+	for _, key := range sortedKeys(event.Qualifiers) {
+		if _, ok := e.Qualifiers[key]; !ok {
+			mismatches = append(
+				mismatches,
+				SubscriptionCriterionMismatch{
+					Criterion: SubscriptionCriterionQualifier,
+					Reason: fmt.Sprintf(
+						"event qualifier %q is not specified by the subscription",
+						key,
+					),
+				},
+			)
+		}
+	}
+	return mismatches
+}
+
+// labelMismatches describes any of the EventSubscription's Labels that the
+// Event lacks or whose values don't match according to the EventSubscription's
+// MatchStrategy. Additional Event Labels do not preclude a match.
+func (e EventSubscription) labelMismatches(
+	event Event,
+) []SubscriptionCriterionMismatch {
+	mismatches := []SubscriptionCriterionMismatch{}
+	for _, key := range sortedKeys(e.Labels) {
+		pattern := e.Labels[key]
+		value, ok := event.Labels[key]
+		if !ok {
+			mismatches = append(
+				mismatches,
+				SubscriptionCriterionMismatch{
+					Criterion: SubscriptionCriterionLabel,
+					Reason:    fmt.Sprintf("event is missing label %q", key),
+				},
+			)
+		} else if !e.MatchStrategy.matches(pattern, value) {
+			mismatches = append(
+				mismatches,
+				SubscriptionCriterionMismatch{
+					Criterion: SubscriptionCriterionLabel,
+					Reason: fmt.Sprintf(
+						"event label %q value %q does not match %q",
+						key,
+						value,
+						pattern,
+					),
+				},
+			)
+		}
+	}
+	return mismatches
+}
+
+func (e EventSubscription) matchesExpression(event Event) bool {
+	if e.Expression == "" {
+		return true
+	}
+	expr, err := subscriptionMatchers.parseExpression(e.Expression)
+	if err != nil {
+		// This shouldn't happen since expressions are validated when Projects are
+		// created or updated, but if it does, nothing can match.
+		return false
+	}
+	return expr.Evaluate(eventResolver(event))
+}
+
+// matches returns a bool indicating whether the provided value matches the
+// provided pattern according to the MatchStrategy.
+func (m MatchStrategy) matches(pattern, value string) bool {
+	switch m {
+	case MatchStrategyGlob:
+		matched, err := path.Match(pattern, value)
+		return err == nil && matched
+	case MatchStrategyRegex:
+		regex, err := subscriptionMatchers.compileAnchoredRegex(pattern)
+		return err == nil && regex.MatchString(value)
+	default:
+		return pattern == value
+	}
+}
+
+// validate returns an error if the provided pattern is not valid for the
+// MatchStrategy.
+func (m MatchStrategy) validate(pattern string) error {
+	switch m {
+	case MatchStrategyGlob:
+		_, err := path.Match(pattern, "")
+		return err
+	case MatchStrategyRegex:
+		_, err := subscriptionMatchers.compileAnchoredRegex(pattern)
+		return err
+	}
+	return nil
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// maxCachedMatchers bounds the number of compiled expressions and regular
+// expressions retained by a matcherCache. When the bound is reached, the cache
+// is simply emptied. Subscriptions change rarely, so the working set is small
+// and this keeps memory in check without any bookkeeping.
+const maxCachedMatchers = 1000
+
+// subscriptionMatchers caches the compiled forms of EventSubscription
+// Expressions and Regex Qualifier and Label values so that they aren't
+// recompiled for every Event a subscription is evaluated against.
+var subscriptionMatchers = newMatcherCache()
+
+type compiledExpression struct {
+	expr expressions.Expression
+	err  error
+}
+
+type compiledRegex struct {
+	regex *regexp.Regexp
+	err   error
+}
+
+// matcherCache is a concurrency-safe cache of compiled expressions and
+// regular expressions, keyed by their source text. Failures are cached as
+// well, since recompiling an invalid pattern won't make it valid.
+type matcherCache struct {
+	mu          sync.Mutex
+	expressions map[string]compiledExpression
+	regexes     map[string]compiledRegex
+}
+
+func newMatcherCache() *matcherCache {
+	return &matcherCache{
+		expressions: map[string]compiledExpression{},
+		regexes:     map[string]compiledRegex{},
+	}
+}
+
+// parseExpression returns the parsed form of the provided expression, parsing
+// it only if it isn't already cached.
+func (m *matcherCache) parseExpression(
+	expr string,
+) (expressions.Expression, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	compiled, ok := m.expressions[expr]
+	if !ok {
+		compiled.expr, compiled.err = expressions.Parse(expr)
+		if len(m.expressions) >= maxCachedMatchers {
+			m.expressions = map[string]compiledExpression{}
+		}
+		m.expressions[expr] = compiled
+	}
+	return compiled.expr, compiled.err
+}
+
+// compileAnchoredRegex returns the compiled form of the provided pattern,
+// implicitly anchored so that it must match an ENTIRE value, compiling it only
+// if it isn't already cached.
+func (m *matcherCache) compileAnchoredRegex(
+	pattern string,
+) (*regexp.Regexp, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	compiled, ok := m.regexes[pattern]
+	if !ok {
+		compiled.regex, compiled.err =
+			regexp.Compile(fmt.Sprintf("^(?:%s)$", pattern))
+		if len(m.regexes) >= maxCachedMatchers {
+			m.regexes = map[string]compiledRegex{}
+		}
+		m.regexes[pattern] = compiled
+	}
+	return compiled.regex, compiled.err
+}
+
+// eventResolver returns an expressions.Resolver that resolves identifier paths
+// against the provided Event. Supported paths are source, type,
+// labels.<key>, qualifiers.<key>, and payload.<path>, where the latter treats
+// the Event's payload as JSON and numeric path elements as array indices.
+func eventResolver(event Event) expressions.Resolver {
+	var payload interface{}
+	var payloadParsed bool
+	return func(p []string) (string, bool) {
+		switch {
+		case len(p) == 1 && p[0] == "source":
+			return event.Source, true
+		case len(p) == 1 && p[0] == "type":
+			return event.Type, true
+		case len(p) == 2 && p[0] == "labels":
+			val, ok := event.Labels[p[1]]
+			return val, ok
+		case len(p) == 2 && p[0] == "qualifiers":
+			val, ok := event.Qualifiers[p[1]]
+			return val, ok
+		case len(p) >= 1 && p[0] == "payload":
+			if !payloadParsed {
+				payloadParsed = true
+				decoder := json.NewDecoder(bytes.NewBufferString(event.Payload))
+				decoder.UseNumber()
+				if err := decoder.Decode(&payload); err != nil {
+					payload = nil
+				}
+			}
+			if len(p) == 1 {
+				return event.Payload, event.Payload != ""
+			}
+			return resolveJSONPath(payload, p[1:])
+		}
+		return "", false
+	}
+}
+
+func resolveJSONPath(node interface{}, p []string) (string, bool) {
+	for _, element := range p {
+		switch n := node.(type) {
+		case map[string]interface{}:
+			var ok bool
+			if node, ok = n[element]; !ok {
+				return "", false
+			}
+		case []interface{}:
+			i, err := strconv.Atoi(element)
+			if err != nil || i < 0 || i >= len(n) {
+				return "", false
+			}
+			node = n[i]
+		default:
+			return "", false
+		}
+	}
+	switch n := node.(type) {
+	case nil:
+		return "", false
+	case string:
+		return n, true
+	case json.Number:
+		return n.String(), true
+	case bool:
+		return strconv.FormatBool(n), true
+	default:
+		jsonBytes, err := json.Marshal(n)
+		if err != nil {
+			return "", false
+		}
+		return string(jsonBytes), true
+	}
+}
+
+// validateEventSubscriptions returns a *meta.ErrBadRequest error if any of the
+// provided EventSubscriptions has Qualifier or Label values that are invalid
+// for its MatchStrategy or has an invalid Expression.
+func validateEventSubscriptions(subscriptions []EventSubscription) error {
+	details := []string{}
+	for i, subscription := range subscriptions {
+		for key, pattern := range subscription.Qualifiers {
+			if err := subscription.MatchStrategy.validate(pattern); err != nil {
+				details = append(
+					details,
+					fmt.Sprintf(
+						"subscription %d has invalid pattern %q for qualifier %q: %s",
+						i,
+						pattern,
+						key,
+						err,
+					),
+				)
+			}
+		}
+		for key, pattern := range subscription.Labels {
+			if err := subscription.MatchStrategy.validate(pattern); err != nil {
+				details = append(
+					details,
+					fmt.Sprintf(
+						"subscription %d has invalid pattern %q for label %q: %s",
+						i,
+						pattern,
+						key,
+						err,
+					),
+				)
+			}
+		}
+		if subscription.Expression != "" {
+			if _, err :=
+				subscriptionMatchers.parseExpression(subscription.Expression); err != nil {
+				details = append(
+					details,
+					fmt.Sprintf(
+						"subscription %d has invalid expression %q: %s",
+						i,
+						subscription.Expression,
+						err,
+					),
+				)
+			}
+		}
+	}
+	if len(details) > 0 {
+		return &meta.ErrBadRequest{
+			Reason:  "Invalid event subscriptions.",
+			Details: details,
+		}
+	}
+	return nil
+}
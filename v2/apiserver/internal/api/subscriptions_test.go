@@ -0,0 +1,394 @@
+package api
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/brigadecore/brigade/v2/apiserver/internal/meta"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProjectIsSubscribed(t *testing.T) {
+	event := Event{
+		Source: "github.com/brigadecore/brigade-github-gateway",
+		Type:   "push",
+	}
+	project := Project{
+		Spec: ProjectSpec{
+			EventSubscriptions: []EventSubscription{
+				{
+					Source: event.Source,
+					Types:  []string{"pull_request"},
+				},
+			},
+		},
+	}
+	require.False(t, project.IsSubscribed(event))
+	project.Spec.EventSubscriptions = append(
+		project.Spec.EventSubscriptions,
+		EventSubscription{
+			Source: event.Source,
+			Types:  []string{"*"},
+		},
+	)
+	require.True(t, project.IsSubscribed(event))
+}
+
+func TestEventSubscriptionMatches(t *testing.T) {
+	const testSource = "github.com/brigadecore/brigade-github-gateway"
+	testEvent := Event{
+		Source: testSource,
+		Type:   "push",
+		Qualifiers: Qualifiers{
+			"repo": "brigadecore/brigade",
+		},
+		Labels: map[string]string{
+			"branch": "release/v2.0",
+			"env":    "staging",
+		},
+		Payload: `{"ref":"refs/heads/release/v2.0","commits":[{"id":"abc123"}]}`,
+	}
+	testCases := []struct {
+		name         string
+		subscription EventSubscription
+		matches      bool
+	}{
+		{
+			name: "source mismatch",
+			subscription: EventSubscription{
+				Source:     "brigade.sh/cron",
+				Types:      []string{"*"},
+				Qualifiers: testEvent.Qualifiers,
+			},
+			matches: false,
+		},
+		{
+			name: "type mismatch",
+			subscription: EventSubscription{
+				Source:     testSource,
+				Types:      []string{"pull_request"},
+				Qualifiers: testEvent.Qualifiers,
+			},
+			matches: false,
+		},
+		{
+			name: "missing qualifier",
+			subscription: EventSubscription{
+				Source: testSource,
+				Types:  []string{"push"},
+			},
+			matches: false,
+		},
+		{
+			name: "exact qualifier and label match",
+			subscription: EventSubscription{
+				Source:     testSource,
+				Types:      []string{"push"},
+				Qualifiers: testEvent.Qualifiers,
+				Labels: map[string]string{
+					"branch": "release/v2.0",
+				},
+			},
+			matches: true,
+		},
+		{
+			name: "exact label mismatch",
+			subscription: EventSubscription{
+				Source:     testSource,
+				Types:      []string{"push"},
+				Qualifiers: testEvent.Qualifiers,
+				Labels: map[string]string{
+					"branch": "release/*",
+				},
+			},
+			matches: false,
+		},
+		{
+			name: "glob label match",
+			subscription: EventSubscription{
+				Source:     testSource,
+				Types:      []string{"push"},
+				Qualifiers: testEvent.Qualifiers,
+				Labels: map[string]string{
+					"branch": "release/*",
+				},
+				MatchStrategy: MatchStrategyGlob,
+			},
+			matches: true,
+		},
+		{
+			name: "glob qualifier match",
+			subscription: EventSubscription{
+				Source: testSource,
+				Types:  []string{"push"},
+				Qualifiers: Qualifiers{
+					"repo": "brigadecore/*",
+				},
+				MatchStrategy: MatchStrategyGlob,
+			},
+			matches: true,
+		},
+		{
+			name: "glob qualifier keys must still match exactly",
+			subscription: EventSubscription{
+				Source: testSource,
+				Types:  []string{"push"},
+				Qualifiers: Qualifiers{
+					"repo":  "*",
+					"other": "*",
+				},
+				MatchStrategy: MatchStrategyGlob,
+			},
+			matches: false,
+		},
+		{
+			name: "regex label match",
+			subscription: EventSubscription{
+				Source:     testSource,
+				Types:      []string{"push"},
+				Qualifiers: testEvent.Qualifiers,
+				Labels: map[string]string{
+					"branch": `release/v\d+\.\d+`,
+				},
+				MatchStrategy: MatchStrategyRegex,
+			},
+			matches: true,
+		},
+		{
+			name: "regex is anchored",
+			subscription: EventSubscription{
+				Source:     testSource,
+				Types:      []string{"push"},
+				Qualifiers: testEvent.Qualifiers,
+				Labels: map[string]string{
+					"branch": "release",
+				},
+				MatchStrategy: MatchStrategyRegex,
+			},
+			matches: false,
+		},
+		{
+			name: "expression match",
+			subscription: EventSubscription{
+				Source:     testSource,
+				Types:      []string{"push"},
+				Qualifiers: testEvent.Qualifiers,
+				Expression: `labels.env != "prod" && ` +
+					`payload.ref =~ "^refs/heads/release/" && ` +
+					`payload.commits[0].id == "abc123"`,
+			},
+			matches: true,
+		},
+		{
+			name: "expression mismatch",
+			subscription: EventSubscription{
+				Source:     testSource,
+				Types:      []string{"push"},
+				Qualifiers: testEvent.Qualifiers,
+				Expression: `labels.env != "staging"`,
+			},
+			matches: false,
+		},
+		{
+			name: "expression referencing a non-existent payload field",
+			subscription: EventSubscription{
+				Source:     testSource,
+				Types:      []string{"push"},
+				Qualifiers: testEvent.Qualifiers,
+				Expression: `payload.commits[1].id`,
+			},
+			matches: false,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			require.Equal(
+				t,
+				testCase.matches,
+				testCase.subscription.Matches(testEvent),
+			)
+		})
+	}
+}
+
+func TestEventSubscriptionMismatches(t *testing.T) {
+	subscription := EventSubscription{
+		Source: "github.com/brigadecore/brigade-github-gateway",
+		Types:  []string{"push"},
+		Qualifiers: Qualifiers{
+			"repo": "brigadecore/brigade",
+		},
+		Labels: map[string]string{
+			"branch": "main",
+			"env":    "prod",
+		},
+		Expression: `labels.env != "staging"`,
+	}
+	mismatches := subscription.Mismatches(
+		Event{
+			Source: "brigade.sh/cli",
+			Type:   "exec",
+			Qualifiers: Qualifiers{
+				"org": "brigadecore",
+			},
+			Labels: map[string]string{
+				"env": "staging",
+			},
+		},
+	)
+	criteria := make([]SubscriptionCriterion, len(mismatches))
+	for i, mismatch := range mismatches {
+		require.NotEmpty(t, mismatch.Reason)
+		criteria[i] = mismatch.Criterion
+	}
+	require.Equal(
+		t,
+		[]SubscriptionCriterion{
+			SubscriptionCriterionSource,
+			SubscriptionCriterionType,
+			SubscriptionCriterionQualifier, // repo is missing
+			SubscriptionCriterionQualifier, // org is unexpected
+			SubscriptionCriterionLabel,     // branch is missing
+			SubscriptionCriterionLabel,     // env doesn't match
+			SubscriptionCriterionExpression,
+		},
+		criteria,
+	)
+}
+
+func TestEventResolver(t *testing.T) {
+	resolve := eventResolver(
+		Event{
+			Source:  "brigade.sh/cron",
+			Type:    "nightly",
+			Payload: "not json",
+		},
+	)
+	val, ok := resolve([]string{"type"})
+	require.True(t, ok)
+	require.Equal(t, "nightly", val)
+	val, ok = resolve([]string{"payload"})
+	require.True(t, ok)
+	require.Equal(t, "not json", val)
+	_, ok = resolve([]string{"payload", "foo"})
+	require.False(t, ok)
+	_, ok = resolve([]string{"labels", "foo"})
+	require.False(t, ok)
+	_, ok = resolve([]string{"bogus"})
+	require.False(t, ok)
+}
+
+func TestValidateEventSubscriptions(t *testing.T) {
+	testCases := []struct {
+		name          string
+		subscriptions []EventSubscription
+		assertions    func(error)
+	}{
+		{
+			name: "invalid glob pattern",
+			subscriptions: []EventSubscription{
+				{
+					Labels: map[string]string{
+						"branch": "release/[",
+					},
+					MatchStrategy: MatchStrategyGlob,
+				},
+			},
+			assertions: func(err error) {
+				require.Error(t, err)
+				require.IsType(t, &meta.ErrBadRequest{}, err)
+				require.Contains(t, err.Error(), "invalid pattern")
+			},
+		},
+		{
+			name: "invalid regular expression",
+			subscriptions: []EventSubscription{
+				{
+					Qualifiers: Qualifiers{
+						"repo": "brigadecore/(",
+					},
+					MatchStrategy: MatchStrategyRegex,
+				},
+			},
+			assertions: func(err error) {
+				require.Error(t, err)
+				require.IsType(t, &meta.ErrBadRequest{}, err)
+				require.Contains(t, err.Error(), "invalid pattern")
+			},
+		},
+		{
+			name: "invalid expression",
+			subscriptions: []EventSubscription{
+				{
+					Expression: `labels.env ==`,
+				},
+			},
+			assertions: func(err error) {
+				require.Error(t, err)
+				require.IsType(t, &meta.ErrBadRequest{}, err)
+				require.Contains(t, err.Error(), "invalid expression")
+			},
+		},
+		{
+			name: "valid",
+			subscriptions: []EventSubscription{
+				{
+					Labels: map[string]string{
+						// Not a valid regular expression, but exact matching is implied
+						"branch": "release/(",
+					},
+				},
+				{
+					Labels: map[string]string{
+						"branch": "release/*",
+					},
+					MatchStrategy: MatchStrategyGlob,
+					Expression:    `labels.env != "prod"`,
+				},
+			},
+			assertions: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.assertions(validateEventSubscriptions(testCase.subscriptions))
+		})
+	}
+}
+
+func TestMatcherCache(t *testing.T) {
+	cache := newMatcherCache()
+
+	expr, err := cache.parseExpression(`payload.commits.0.id == "abc123"`)
+	require.NoError(t, err)
+	cachedExpr, err := cache.parseExpression(`payload.commits.0.id == "abc123"`)
+	require.NoError(t, err)
+	require.Same(t, expr, cachedExpr)
+
+	_, err = cache.parseExpression(`type ==`)
+	require.Error(t, err)
+	require.Len(t, cache.expressions, 2)
+
+	regex, err := cache.compileAnchoredRegex("release/.*")
+	require.NoError(t, err)
+	require.True(t, regex.MatchString("release/v2"))
+	require.False(t, regex.MatchString("pre-release/v2"))
+	cachedRegex, err := cache.compileAnchoredRegex("release/.*")
+	require.NoError(t, err)
+	require.Same(t, regex, cachedRegex)
+
+	_, err = cache.compileAnchoredRegex("release/(")
+	require.Error(t, err)
+	require.Len(t, cache.regexes, 2)
+
+	// The cache is emptied once it is full
+	for i := 0; len(cache.regexes) < maxCachedMatchers; i++ {
+		_, err = cache.compileAnchoredRegex(strconv.Itoa(i))
+		require.NoError(t, err)
+	}
+	_, err = cache.compileAnchoredRegex("hotfix/.*")
+	require.NoError(t, err)
+	require.Len(t, cache.regexes, 1)
+}
@@ -56,15 +56,41 @@ func (s SubstrateJobCount) MarshalJSON() ([]byte, error) {
 	)
 }
 
+// RunningWorkersSelector represents useful filter criteria when counting
+// Workers currently executing on the substrate.
+type RunningWorkersSelector struct {
+	// ProjectID specifies that only Workers belonging to the indicated Project
+	// should be counted. If left blank, Workers belonging to all Projects are
+	// counted.
+	ProjectID string
+}
+
+// RunningJobsSelector represents useful filter criteria when counting Jobs
+// currently executing on the substrate.
+type RunningJobsSelector struct {
+	// ProjectID specifies that only Jobs belonging to the indicated Project
+	// should be counted. If left blank, Jobs belonging to all Projects are
+	// counted.
+	ProjectID string
+}
+
 // SubstrateService is the specialized interface for monitoring the state of the
 // substrate.
 type SubstrateService interface {
 	// CountRunningWorkers returns a count of Workers currently executing on the
-	// substrate.
-	CountRunningWorkers(context.Context) (SubstrateWorkerCount, error)
+	// substrate. Criteria for which Workers should be counted can be specified
+	// using the provided RunningWorkersSelector.
+	CountRunningWorkers(
+		context.Context,
+		RunningWorkersSelector,
+	) (SubstrateWorkerCount, error)
 	// CountRunningJobs returns a count of Jobs currently executing on the
-	// substrate.
-	CountRunningJobs(context.Context) (SubstrateJobCount, error)
+	// substrate. Criteria for which Jobs should be counted can be specified
+	// using the provided RunningJobsSelector.
+	CountRunningJobs(
+		context.Context,
+		RunningJobsSelector,
+	) (SubstrateJobCount, error)
 }
 
 type substrateService struct {
@@ -85,6 +111,7 @@ func NewSubstrateService(
 
 func (s *substrateService) CountRunningWorkers(
 	ctx context.Context,
+	selector RunningWorkersSelector,
 ) (SubstrateWorkerCount, error) {
 	// At present, only the scheduler ever needs to know how many Workers
 	// are currently executing, but it's very easy to imagine new tooling, like
@@ -95,7 +122,7 @@ func (s *substrateService) CountRunningWorkers(
 		return SubstrateWorkerCount{}, err
 	}
 
-	count, err := s.substrate.CountRunningWorkers(ctx)
+	count, err := s.substrate.CountRunningWorkers(ctx, selector)
 	if err != nil {
 		return count, errors.Wrapf(
 			err,
@@ -107,6 +134,7 @@ func (s *substrateService) CountRunningWorkers(
 
 func (s *substrateService) CountRunningJobs(
 	ctx context.Context,
+	selector RunningJobsSelector,
 ) (SubstrateJobCount, error) {
 	// At present, only the scheduler ever needs to know how many Workers
 	// are currently executing, but it's very easy to imagine new tooling, like
@@ -117,7 +145,7 @@ func (s *substrateService) CountRunningJobs(
 		return SubstrateJobCount{}, err
 	}
 
-	count, err := s.substrate.CountRunningJobs(ctx)
+	count, err := s.substrate.CountRunningJobs(ctx, selector)
 	if err != nil {
 		return count, errors.Wrapf(err, "error counting running jobs on substrate")
 	}
@@ -128,11 +156,19 @@ func (s *substrateService) CountRunningJobs(
 // with Brigade's underlying workload execution substrate, i.e. Kubernetes.
 type Substrate interface {
 	// CountRunningWorkers returns a count of Workers currently executing on the
-	// substrate.
-	CountRunningWorkers(context.Context) (SubstrateWorkerCount, error)
+	// substrate. Criteria for which Workers should be counted can be specified
+	// using the provided RunningWorkersSelector.
+	CountRunningWorkers(
+		context.Context,
+		RunningWorkersSelector,
+	) (SubstrateWorkerCount, error)
 	// CountRunningJobs returns a count of Jobs currently executing on the
-	// substrate.
-	CountRunningJobs(context.Context) (SubstrateJobCount, error)
+	// substrate. Criteria for which Jobs should be counted can be specified
+	// using the provided RunningJobsSelector.
+	CountRunningJobs(
+		context.Context,
+		RunningJobsSelector,
+	) (SubstrateJobCount, error)
 
 	// CreateProject prepares the substrate to host Project workloads. The
 	// provided Project argument may be amended with substrate-specific details
@@ -187,6 +223,17 @@ type Substrate interface {
 		jobName string,
 	) error
 
+	// DeleteEarlierJobAttempts deletes substrate resources pertaining
+	// exclusively to attempts at the specified Job other than the specified,
+	// current attempt. Resources shared by all attempts are left intact.
+	DeleteEarlierJobAttempts(
+		ctx context.Context,
+		project Project,
+		event Event,
+		jobName string,
+		currentAttempt int,
+	) error
+
 	// DeleteWorkerAndJobs deletes all substrate resources pertaining to the
 	// specified Event's Worker and Jobs.
 	DeleteWorkerAndJobs(context.Context, Project, Event) error
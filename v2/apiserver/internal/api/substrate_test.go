@@ -36,6 +36,7 @@ func TestNewSubstrateService(t *testing.T) {
 
 func TestSubstrateServiceCountRunningWorkers(t *testing.T) {
 	const testCount = 5
+	const testProjectID = "blue-book"
 	testCases := []struct {
 		name       string
 		service    SubstrateService
@@ -58,6 +59,7 @@ func TestSubstrateServiceCountRunningWorkers(t *testing.T) {
 				substrate: &mockSubstrate{
 					CountRunningWorkersFn: func(
 						context.Context,
+						RunningWorkersSelector,
 					) (SubstrateWorkerCount, error) {
 						return SubstrateWorkerCount{}, errors.New("something went wrong")
 					},
@@ -79,8 +81,10 @@ func TestSubstrateServiceCountRunningWorkers(t *testing.T) {
 				authorize: alwaysAuthorize,
 				substrate: &mockSubstrate{
 					CountRunningWorkersFn: func(
-						context.Context,
+						_ context.Context,
+						selector RunningWorkersSelector,
 					) (SubstrateWorkerCount, error) {
+						require.Equal(t, testProjectID, selector.ProjectID)
 						return SubstrateWorkerCount{
 							Count: testCount,
 						}, nil
@@ -95,7 +99,12 @@ func TestSubstrateServiceCountRunningWorkers(t *testing.T) {
 	}
 	for _, testCase := range testCases {
 		t.Run(testCase.name, func(t *testing.T) {
-			count, err := testCase.service.CountRunningWorkers(context.Background())
+			count, err := testCase.service.CountRunningWorkers(
+				context.Background(),
+				RunningWorkersSelector{
+					ProjectID: testProjectID,
+				},
+			)
 			testCase.assertions(count, err)
 		})
 	}
@@ -103,6 +112,7 @@ func TestSubstrateServiceCountRunningWorkers(t *testing.T) {
 
 func TestSubstrateServiceCountRunningJobs(t *testing.T) {
 	const testCount = 5
+	const testProjectID = "blue-book"
 	testCases := []struct {
 		name       string
 		service    SubstrateService
@@ -125,6 +135,7 @@ func TestSubstrateServiceCountRunningJobs(t *testing.T) {
 				substrate: &mockSubstrate{
 					CountRunningJobsFn: func(
 						context.Context,
+						RunningJobsSelector,
 					) (SubstrateJobCount, error) {
 						return SubstrateJobCount{}, errors.New("something went wrong")
 					},
@@ -146,8 +157,10 @@ func TestSubstrateServiceCountRunningJobs(t *testing.T) {
 				authorize: alwaysAuthorize,
 				substrate: &mockSubstrate{
 					CountRunningJobsFn: func(
-						context.Context,
+						_ context.Context,
+						selector RunningJobsSelector,
 					) (SubstrateJobCount, error) {
+						require.Equal(t, testProjectID, selector.ProjectID)
 						return SubstrateJobCount{
 							Count: testCount,
 						}, nil
@@ -162,16 +175,27 @@ func TestSubstrateServiceCountRunningJobs(t *testing.T) {
 	}
 	for _, testCase := range testCases {
 		t.Run(testCase.name, func(t *testing.T) {
-			count, err := testCase.service.CountRunningJobs(context.Background())
+			count, err := testCase.service.CountRunningJobs(
+				context.Background(),
+				RunningJobsSelector{
+					ProjectID: testProjectID,
+				},
+			)
 			testCase.assertions(count, err)
 		})
 	}
 }
 
 type mockSubstrate struct {
-	CountRunningWorkersFn func(context.Context) (SubstrateWorkerCount, error)
-	CountRunningJobsFn    func(context.Context) (SubstrateJobCount, error)
-	CreateProjectFn       func(
+	CountRunningWorkersFn func(
+		context.Context,
+		RunningWorkersSelector,
+	) (SubstrateWorkerCount, error)
+	CountRunningJobsFn func(
+		context.Context,
+		RunningJobsSelector,
+	) (SubstrateJobCount, error)
+	CreateProjectFn func(
 		ctx context.Context,
 		project Project,
 	) (Project, error)
@@ -203,19 +227,28 @@ type mockSubstrate struct {
 		event Event,
 		jobName string,
 	) error
+	DeleteEarlierJobAttemptsFn func(
+		ctx context.Context,
+		project Project,
+		event Event,
+		jobName string,
+		currentAttempt int,
+	) error
 	DeleteWorkerAndJobsFn func(context.Context, Project, Event) error
 }
 
 func (m *mockSubstrate) CountRunningWorkers(
 	ctx context.Context,
+	selector RunningWorkersSelector,
 ) (SubstrateWorkerCount, error) {
-	return m.CountRunningWorkersFn(ctx)
+	return m.CountRunningWorkersFn(ctx, selector)
 }
 
 func (m *mockSubstrate) CountRunningJobs(
 	ctx context.Context,
+	selector RunningJobsSelector,
 ) (SubstrateJobCount, error) {
-	return m.CountRunningJobsFn(ctx)
+	return m.CountRunningJobsFn(ctx, selector)
 }
 
 func (m *mockSubstrate) CreateProject(
@@ -285,6 +318,22 @@ func (m *mockSubstrate) DeleteJob(
 	return m.DeleteJobFn(ctx, project, event, jobName)
 }
 
+func (m *mockSubstrate) DeleteEarlierJobAttempts(
+	ctx context.Context,
+	project Project,
+	event Event,
+	jobName string,
+	currentAttempt int,
+) error {
+	return m.DeleteEarlierJobAttemptsFn(
+		ctx,
+		project,
+		event,
+		jobName,
+		currentAttempt,
+	)
+}
+
 func (m *mockSubstrate) DeleteWorkerAndJobs(
 	ctx context.Context,
 	project Project,
@@ -0,0 +1,265 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// WebhookDeliveryServiceConfig encapsulates configuration options for the
+// WebhookDeliveryService.
+type WebhookDeliveryServiceConfig struct {
+	// Interval specifies how frequently the WebhookDeliveryService should check
+	// for WebhookDeliveries that are due to be attempted.
+	Interval time.Duration
+	// Timeout specifies how long to wait for a Webhook to respond before the
+	// attempt is considered failed.
+	Timeout time.Duration
+	// MaxAttempts specifies how many times delivery of a Notification is
+	// attempted before it is considered failed.
+	MaxAttempts int
+	// InitialBackoff specifies how long to wait after the first failed attempt
+	// before trying again. The wait doubles after every subsequent failed
+	// attempt.
+	InitialBackoff time.Duration
+	// MaxBackoff specifies the longest to wait after any failed attempt before
+	// trying again.
+	MaxBackoff time.Duration
+}
+
+// WebhookDeliveryService is the specialized interface for carrying out pending
+// WebhookDeliveries. It's decoupled from underlying technology choices (e.g.
+// data store, message bus, etc.) to keep business logic reusable and
+// consistent while the underlying tech stack remains free to change.
+type WebhookDeliveryService interface {
+	// Run periodically attempts all WebhookDeliveries that are due. It blocks
+	// until the provided context is canceled.
+	Run(context.Context)
+}
+
+type webhookDeliveryService struct {
+	projectsStore          ProjectsStore
+	secretsStore           SecretsStore
+	webhookDeliveriesStore WebhookDeliveriesStore
+	httpClient             *http.Client
+	config                 WebhookDeliveryServiceConfig
+	// nowFn is overridable for testing purposes
+	nowFn func() time.Time
+}
+
+// NewWebhookDeliveryService returns a specialized interface for carrying out
+// pending WebhookDeliveries.
+func NewWebhookDeliveryService(
+	projectsStore ProjectsStore,
+	secretsStore SecretsStore,
+	webhookDeliveriesStore WebhookDeliveriesStore,
+	config WebhookDeliveryServiceConfig,
+) WebhookDeliveryService {
+	return &webhookDeliveryService{
+		projectsStore:          projectsStore,
+		secretsStore:           secretsStore,
+		webhookDeliveriesStore: webhookDeliveriesStore,
+		httpClient: &http.Client{
+			Timeout: config.Timeout,
+			// Deliveries are never proxied because every address connected to must
+			// be vetted. See publicAddressesOnly().
+			Transport: &http.Transport{
+				DialContext: (&net.Dialer{
+					Timeout: config.Timeout,
+					Control: publicAddressesOnly,
+				}).DialContext,
+				TLSHandshakeTimeout: config.Timeout,
+			},
+		},
+		config: config,
+		nowFn: func() time.Time {
+			return time.Now().UTC()
+		},
+	}
+}
+
+func (w *webhookDeliveryService) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.config.Interval)
+	defer ticker.Stop()
+	for {
+		if err := w.deliverAll(ctx); err != nil {
+			log.Println(err)
+		}
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// deliverAll claims and attempts WebhookDeliveries until none remain that are
+// due.
+func (w *webhookDeliveryService) deliverAll(ctx context.Context) error {
+	for {
+		now := w.nowFn()
+		// The lease comfortably outlasts a single attempt, so if this process
+		// dies mid-attempt, the WebhookDelivery is eventually claimed again by
+		// someone else.
+		delivery, err := w.webhookDeliveriesStore.Claim(
+			ctx,
+			now,
+			now.Add(2*w.config.Timeout),
+		)
+		if err != nil {
+			return errors.Wrap(err, "error claiming webhook delivery")
+		}
+		if delivery == nil {
+			return nil
+		}
+		if err = w.deliver(ctx, *delivery); err != nil {
+			log.Println(err)
+		}
+	}
+}
+
+// deliver makes one attempt at the provided WebhookDelivery and records the
+// outcome.
+func (w *webhookDeliveryService) deliver(
+	ctx context.Context,
+	delivery WebhookDelivery,
+) error {
+	attempt := WebhookDeliveryAttempt{
+		Time: w.nowFn(),
+	}
+	var err error
+	if attempt.StatusCode, err = w.send(ctx, delivery); err != nil {
+		attempt.Error = err.Error()
+	}
+	status := delivery.Status
+	status.Attempts = append(status.Attempts, attempt)
+	switch {
+	case attempt.Error == "":
+		status.Phase = WebhookDeliveryPhaseSucceeded
+		status.NextAttempt = nil
+	case len(status.Attempts) >= w.config.MaxAttempts:
+		status.Phase = WebhookDeliveryPhaseFailed
+		status.NextAttempt = nil
+	default:
+		nextAttempt := attempt.Time.Add(w.backoff(len(status.Attempts)))
+		status.NextAttempt = &nextAttempt
+	}
+	return errors.Wrapf(
+		w.webhookDeliveriesStore.UpdateStatus(ctx, delivery.ID, status),
+		"error updating status of webhook delivery %q in store",
+		delivery.ID,
+	)
+}
+
+// backoff returns how long to wait after the specified number of failed
+// attempts before trying again.
+func (w *webhookDeliveryService) backoff(attempts int) time.Duration {
+	backoff := w.config.InitialBackoff
+	for i := 1; i < attempts && backoff < w.config.MaxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > w.config.MaxBackoff {
+		return w.config.MaxBackoff
+	}
+	return backoff
+}
+
+// send POSTs the provided WebhookDelivery's Notification to its Webhook and
+// returns the status code the Webhook responded with, if any. An error is
+// returned if the request could not be made or if the status code is not 2xx.
+func (w *webhookDeliveryService) send(
+	ctx context.Context,
+	delivery WebhookDelivery,
+) (int, error) {
+	body, err := json.Marshal(delivery.Notification)
+	if err != nil {
+		return 0, errors.Wrap(err, "error marshaling notification")
+	}
+	req, err := http.NewRequestWithContext(
+		ctx,
+		http.MethodPost,
+		delivery.URL,
+		bytes.NewBuffer(body),
+	)
+	if err != nil {
+		return 0, errors.Wrap(err, "error creating request")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "Brigade")
+	req.Header.Set("X-Brigade-Delivery", delivery.ID)
+	if delivery.SecretKey != "" {
+		signature, err := w.sign(ctx, delivery, body)
+		if err != nil {
+			return 0, err
+		}
+		req.Header.Set("X-Brigade-Signature-256", signature)
+	}
+	resp, err := w.httpClient.Do(req)
+	if err != nil {
+		return 0, errors.Wrap(err, "error sending request")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode,
+			errors.Errorf("received unexpected status code %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// publicAddressesOnly is used as the Control function of the net.Dialer
+// through which all WebhookDeliveries are made. It refuses any connection to an
+// address that isn't public. Webhook URLs that obviously refer to internal
+// hosts are rejected when a Project is created or updated, but any name can
+// later resolve to an internal address, so the address must be checked again at
+// the moment of connecting.
+func publicAddressesOnly(_, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return errors.Wrapf(err, "error parsing address %q", address)
+	}
+	if ip := net.ParseIP(host); ip == nil || !isPublicIP(ip) {
+		return errors.Errorf("refusing to connect to non-public address %s", host)
+	}
+	return nil
+}
+
+// sign returns a signature for the provided request body that is computed
+// using the value of the Project Secret referenced by the provided
+// WebhookDelivery.
+func (w *webhookDeliveryService) sign(
+	ctx context.Context,
+	delivery WebhookDelivery,
+	body []byte,
+) (string, error) {
+	project, err := w.projectsStore.Get(ctx, delivery.ProjectID)
+	if err != nil {
+		return "", errors.Wrapf(
+			err,
+			"error retrieving project %q from store",
+			delivery.ProjectID,
+		)
+	}
+	secret, err := w.secretsStore.Get(ctx, project, delivery.SecretKey)
+	if err != nil {
+		return "", errors.Wrapf(
+			err,
+			"error retrieving project %q secret %q",
+			delivery.ProjectID,
+			delivery.SecretKey,
+		)
+	}
+	mac := hmac.New(sha256.New, []byte(secret.Value))
+	mac.Write(body) // nolint: errcheck
+	return fmt.Sprintf("sha256=%s", hex.EncodeToString(mac.Sum(nil))), nil
+}
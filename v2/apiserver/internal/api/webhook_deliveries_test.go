@@ -0,0 +1,479 @@
+package api
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/brigadecore/brigade/v2/apiserver/internal/meta"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewWebhookDeliveryService(t *testing.T) {
+	projectsStore := &mockProjectsStore{}
+	secretsStore := &mockSecretsStore{}
+	webhookDeliveriesStore := &mockWebhookDeliveriesStore{}
+	config := WebhookDeliveryServiceConfig{
+		Interval: time.Second,
+		Timeout:  5 * time.Second,
+	}
+	svc, ok := NewWebhookDeliveryService(
+		projectsStore,
+		secretsStore,
+		webhookDeliveriesStore,
+		config,
+	).(*webhookDeliveryService)
+	require.True(t, ok)
+	require.Same(t, projectsStore, svc.projectsStore)
+	require.Same(t, secretsStore, svc.secretsStore)
+	require.Same(t, webhookDeliveriesStore, svc.webhookDeliveriesStore)
+	require.NotNil(t, svc.httpClient)
+	require.Equal(t, config.Timeout, svc.httpClient.Timeout)
+	require.Equal(t, config, svc.config)
+	require.NotNil(t, svc.nowFn)
+
+	// Deliveries to internal addresses must be refused
+	server := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}),
+	)
+	defer server.Close()
+	_, err := svc.httpClient.Get(server.URL)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "refusing to connect")
+}
+
+func TestPublicAddressesOnly(t *testing.T) {
+	testCases := []struct {
+		address       string
+		expectedError string
+	}{
+		{
+			address: "93.184.216.34:443",
+		},
+		{
+			address: "[2606:2800:220:1:248:1893:25c8:1946]:443",
+		},
+		{
+			address:       "127.0.0.1:80",
+			expectedError: "refusing to connect",
+		},
+		{
+			address:       "10.96.0.1:443",
+			expectedError: "refusing to connect",
+		},
+		{
+			address:       "169.254.169.254:80",
+			expectedError: "refusing to connect",
+		},
+		{
+			address:       "[::1]:80",
+			expectedError: "refusing to connect",
+		},
+		{
+			address:       "not an address",
+			expectedError: "error parsing address",
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.address, func(t *testing.T) {
+			err := publicAddressesOnly("tcp", testCase.address, nil)
+			if testCase.expectedError == "" {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), testCase.expectedError)
+			}
+		})
+	}
+}
+
+func TestWebhookDeliveryServiceBackoff(t *testing.T) {
+	svc := &webhookDeliveryService{
+		config: WebhookDeliveryServiceConfig{
+			InitialBackoff: 10 * time.Second,
+			MaxBackoff:     time.Minute,
+		},
+	}
+	require.Equal(t, 10*time.Second, svc.backoff(1))
+	require.Equal(t, 20*time.Second, svc.backoff(2))
+	require.Equal(t, 40*time.Second, svc.backoff(3))
+	require.Equal(t, time.Minute, svc.backoff(4))
+	require.Equal(t, time.Minute, svc.backoff(100))
+}
+
+func TestWebhookDeliveryServiceDeliverAll(t *testing.T) {
+	testCases := []struct {
+		name       string
+		service    *webhookDeliveryService
+		assertions func(error)
+	}{
+		{
+			name: "error claiming webhook delivery",
+			service: &webhookDeliveryService{
+				webhookDeliveriesStore: &mockWebhookDeliveriesStore{
+					ClaimFn: func(
+						context.Context,
+						time.Time,
+						time.Time,
+					) (*WebhookDelivery, error) {
+						return nil, errors.New("something went wrong")
+					},
+				},
+			},
+			assertions: func(err error) {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), "something went wrong")
+				require.Contains(t, err.Error(), "error claiming webhook delivery")
+			},
+		},
+		{
+			name: "success",
+			service: func() *webhookDeliveryService {
+				server := httptest.NewServer(
+					http.HandlerFunc(
+						func(w http.ResponseWriter, _ *http.Request) {
+							w.WriteHeader(http.StatusOK)
+						},
+					),
+				)
+				t.Cleanup(server.Close)
+				claimed := false
+				return &webhookDeliveryService{
+					webhookDeliveriesStore: &mockWebhookDeliveriesStore{
+						ClaimFn: func(
+							_ context.Context,
+							now time.Time,
+							leaseExpiry time.Time,
+						) (*WebhookDelivery, error) {
+							require.Equal(t, now.Add(10*time.Second), leaseExpiry)
+							if claimed {
+								return nil, nil
+							}
+							claimed = true
+							return &WebhookDelivery{
+								ObjectMeta: meta.ObjectMeta{
+									ID: "123456789",
+								},
+								URL: server.URL,
+							}, nil
+						},
+						UpdateStatusFn: func(
+							_ context.Context,
+							id string,
+							status WebhookDeliveryStatus,
+						) error {
+							require.Equal(t, "123456789", id)
+							require.Equal(
+								t,
+								WebhookDeliveryPhaseSucceeded,
+								status.Phase,
+							)
+							return nil
+						},
+					},
+					httpClient: server.Client(),
+					config: WebhookDeliveryServiceConfig{
+						Timeout:     5 * time.Second,
+						MaxAttempts: 3,
+					},
+				}
+			}(),
+			assertions: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.service.nowFn = time.Now
+			testCase.assertions(
+				testCase.service.deliverAll(context.Background()),
+			)
+		})
+	}
+}
+
+func TestWebhookDeliveryServiceDeliver(t *testing.T) {
+	const testSecretValue = "Who knows what evil lurks in the hearts of men?"
+	now := time.Now().UTC()
+	testNotification := Notification{
+		ProjectID:   "blue-book",
+		EventID:     "tunguska",
+		WorkerPhase: WorkerPhaseFailed,
+	}
+	testCases := []struct {
+		name       string
+		handler    http.HandlerFunc
+		delivery   WebhookDelivery
+		service    *webhookDeliveryService
+		assertions func(error)
+	}{
+		{
+			name: "signed delivery succeeds",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				require.Equal(t, http.MethodPost, r.Method)
+				require.Equal(t, "application/json", r.Header.Get("Content-Type"))
+				require.Equal(t, "123456789", r.Header.Get("X-Brigade-Delivery"))
+				body, err := io.ReadAll(r.Body)
+				require.NoError(t, err)
+				mac := hmac.New(sha256.New, []byte(testSecretValue))
+				_, err = mac.Write(body)
+				require.NoError(t, err)
+				require.Equal(
+					t,
+					fmt.Sprintf("sha256=%s", hex.EncodeToString(mac.Sum(nil))),
+					r.Header.Get("X-Brigade-Signature-256"),
+				)
+				notification := Notification{}
+				require.NoError(t, json.Unmarshal(body, &notification))
+				require.Equal(t, testNotification, notification)
+				w.WriteHeader(http.StatusNoContent)
+			},
+			delivery: WebhookDelivery{
+				ObjectMeta: meta.ObjectMeta{
+					ID: "123456789",
+				},
+				ProjectID:    "blue-book",
+				SecretKey:    "slackSecret",
+				Notification: testNotification,
+			},
+			service: &webhookDeliveryService{
+				projectsStore: &mockProjectsStore{
+					GetFn: func(context.Context, string) (Project, error) {
+						return Project{}, nil
+					},
+				},
+				secretsStore: &mockSecretsStore{
+					GetFn: func(
+						_ context.Context,
+						_ Project,
+						key string,
+					) (Secret, error) {
+						require.Equal(t, "slackSecret", key)
+						return Secret{
+							Key:   key,
+							Value: testSecretValue,
+						}, nil
+					},
+				},
+				webhookDeliveriesStore: &mockWebhookDeliveriesStore{
+					UpdateStatusFn: func(
+						_ context.Context,
+						_ string,
+						status WebhookDeliveryStatus,
+					) error {
+						require.Equal(
+							t,
+							WebhookDeliveryPhaseSucceeded,
+							status.Phase,
+						)
+						require.Nil(t, status.NextAttempt)
+						require.Equal(
+							t,
+							[]WebhookDeliveryAttempt{
+								{
+									Time:       now,
+									StatusCode: http.StatusNoContent,
+								},
+							},
+							status.Attempts,
+						)
+						return nil
+					},
+				},
+			},
+			assertions: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "error retrieving secret; retried later",
+			handler: func(http.ResponseWriter, *http.Request) {
+				require.Fail(t, "the webhook should not have been called")
+			},
+			delivery: WebhookDelivery{
+				ObjectMeta: meta.ObjectMeta{
+					ID: "123456789",
+				},
+				SecretKey: "slackSecret",
+				Status: WebhookDeliveryStatus{
+					Phase: WebhookDeliveryPhasePending,
+				},
+			},
+			service: &webhookDeliveryService{
+				projectsStore: &mockProjectsStore{
+					GetFn: func(context.Context, string) (Project, error) {
+						return Project{}, nil
+					},
+				},
+				secretsStore: &mockSecretsStore{
+					GetFn: func(context.Context, Project, string) (Secret, error) {
+						return Secret{}, &meta.ErrNotFound{}
+					},
+				},
+				webhookDeliveriesStore: &mockWebhookDeliveriesStore{
+					UpdateStatusFn: func(
+						_ context.Context,
+						_ string,
+						status WebhookDeliveryStatus,
+					) error {
+						require.Equal(t, WebhookDeliveryPhasePending, status.Phase)
+						require.Len(t, status.Attempts, 1)
+						require.Contains(
+							t,
+							status.Attempts[0].Error,
+							"error retrieving project",
+						)
+						require.Equal(t, now.Add(10*time.Second), *status.NextAttempt)
+						return nil
+					},
+				},
+			},
+			assertions: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "unexpected status code; retried later",
+			handler: func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusBadGateway)
+			},
+			delivery: WebhookDelivery{
+				ObjectMeta: meta.ObjectMeta{
+					ID: "123456789",
+				},
+				Status: WebhookDeliveryStatus{
+					Phase: WebhookDeliveryPhasePending,
+					Attempts: []WebhookDeliveryAttempt{
+						{
+							StatusCode: http.StatusBadGateway,
+						},
+					},
+				},
+			},
+			service: &webhookDeliveryService{
+				webhookDeliveriesStore: &mockWebhookDeliveriesStore{
+					UpdateStatusFn: func(
+						_ context.Context,
+						_ string,
+						status WebhookDeliveryStatus,
+					) error {
+						require.Equal(t, WebhookDeliveryPhasePending, status.Phase)
+						require.Len(t, status.Attempts, 2)
+						require.Equal(
+							t,
+							http.StatusBadGateway,
+							status.Attempts[1].StatusCode,
+						)
+						require.Contains(
+							t,
+							status.Attempts[1].Error,
+							"unexpected status code",
+						)
+						require.Equal(t, now.Add(20*time.Second), *status.NextAttempt)
+						return nil
+					},
+				},
+			},
+			assertions: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "final attempt fails",
+			handler: func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusInternalServerError)
+			},
+			delivery: WebhookDelivery{
+				ObjectMeta: meta.ObjectMeta{
+					ID: "123456789",
+				},
+				Status: WebhookDeliveryStatus{
+					Phase: WebhookDeliveryPhasePending,
+					Attempts: []WebhookDeliveryAttempt{
+						{
+							StatusCode: http.StatusInternalServerError,
+						},
+						{
+							StatusCode: http.StatusInternalServerError,
+						},
+					},
+				},
+			},
+			service: &webhookDeliveryService{
+				webhookDeliveriesStore: &mockWebhookDeliveriesStore{
+					UpdateStatusFn: func(
+						_ context.Context,
+						_ string,
+						status WebhookDeliveryStatus,
+					) error {
+						require.Equal(t, WebhookDeliveryPhaseFailed, status.Phase)
+						require.Len(t, status.Attempts, 3)
+						require.Nil(t, status.NextAttempt)
+						return nil
+					},
+				},
+			},
+			assertions: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "error updating status",
+			handler: func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			},
+			delivery: WebhookDelivery{
+				ObjectMeta: meta.ObjectMeta{
+					ID: "123456789",
+				},
+			},
+			service: &webhookDeliveryService{
+				webhookDeliveriesStore: &mockWebhookDeliveriesStore{
+					UpdateStatusFn: func(
+						context.Context,
+						string,
+						WebhookDeliveryStatus,
+					) error {
+						return errors.New("something went wrong")
+					},
+				},
+			},
+			assertions: func(err error) {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), "something went wrong")
+				require.Contains(t, err.Error(), "error updating status")
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			server := httptest.NewServer(testCase.handler)
+			defer server.Close()
+			testCase.delivery.URL = server.URL
+			testCase.service.httpClient = server.Client()
+			testCase.service.config = WebhookDeliveryServiceConfig{
+				MaxAttempts:    3,
+				InitialBackoff: 10 * time.Second,
+				MaxBackoff:     time.Hour,
+			}
+			testCase.service.nowFn = func() time.Time {
+				return now
+			}
+			testCase.assertions(
+				testCase.service.deliver(context.Background(), testCase.delivery),
+			)
+		})
+	}
+}
@@ -0,0 +1,504 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/brigadecore/brigade/v2/apiserver/internal/meta"
+	"github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+)
+
+// WebhookDeliveryKind represents the canonical WebhookDelivery kind string
+const WebhookDeliveryKind = "WebhookDelivery"
+
+// Webhook describes an HTTP endpoint that should be notified whenever one of
+// a Project's Workers or Jobs enters any phase of interest.
+type Webhook struct {
+	// Name is a unique (per Project) identifier for the Webhook.
+	Name string `json:"name" bson:"name"`
+	// URL is the address to which notifications are POSTed.
+	URL string `json:"url" bson:"url"`
+	// WorkerPhases enumerates the WorkerPhases of interest. The Webhook is
+	// notified whenever one of the Project's Workers enters any of these phases.
+	WorkerPhases []WorkerPhase `json:"workerPhases,omitempty" bson:"workerPhases,omitempty"` // nolint: lll
+	// JobPhases enumerates the JobPhases of interest. The Webhook is notified
+	// whenever one of the Project's Jobs enters any of these phases.
+	JobPhases []JobPhase `json:"jobPhases,omitempty" bson:"jobPhases,omitempty"` // nolint: lll
+	// SecretKey optionally specifies the key of a Project Secret whose value is
+	// used to sign notifications. When specified, every notification bears an
+	// X-Brigade-Signature-256 header containing the hex-encoded HMAC-SHA256 of
+	// the request body.
+	SecretKey string `json:"secretKey,omitempty" bson:"secretKey,omitempty"`
+}
+
+// hasWorkerPhase returns a bool indicating whether the Webhook is interested in
+// the provided WorkerPhase.
+func (w Webhook) hasWorkerPhase(phase WorkerPhase) bool {
+	for _, p := range w.WorkerPhases {
+		if p == phase {
+			return true
+		}
+	}
+	return false
+}
+
+// hasJobPhase returns a bool indicating whether the Webhook is interested in
+// the provided JobPhase.
+func (w Webhook) hasJobPhase(phase JobPhase) bool {
+	for _, p := range w.JobPhases {
+		if p == phase {
+			return true
+		}
+	}
+	return false
+}
+
+// validateWebhooks returns a *meta.ErrBadRequest if any of the provided
+// Webhooks are invalid or if any two of them share a name.
+func validateWebhooks(webhooks []Webhook) error {
+	details := []string{}
+	names := map[string]struct{}{}
+	for _, webhook := range webhooks {
+		if _, ok := names[webhook.Name]; ok {
+			details = append(
+				details,
+				fmt.Sprintf("webhook name %q is not unique", webhook.Name),
+			)
+		}
+		names[webhook.Name] = struct{}{}
+		if u, err := url.Parse(webhook.URL); err != nil ||
+			(u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			details = append(
+				details,
+				fmt.Sprintf(
+					"webhook %q url %q is not a valid http or https url",
+					webhook.Name,
+					webhook.URL,
+				),
+			)
+		} else if isInternalHost(u.Hostname()) {
+			details = append(
+				details,
+				fmt.Sprintf(
+					"webhook %q url %q does not refer to a public address",
+					webhook.Name,
+					webhook.URL,
+				),
+			)
+		}
+		if len(webhook.WorkerPhases) == 0 && len(webhook.JobPhases) == 0 {
+			details = append(
+				details,
+				fmt.Sprintf(
+					"webhook %q does not specify any worker or job phases",
+					webhook.Name,
+				),
+			)
+		}
+	}
+	if len(details) > 0 {
+		return &meta.ErrBadRequest{
+			Reason:  "Invalid webhooks.",
+			Details: details,
+		}
+	}
+	return nil
+}
+
+// internalHostSuffixes are suffixes of host names that are only resolvable
+// within a cluster or a private network.
+var internalHostSuffixes = []string{
+	".local",
+	".localhost",
+	".internal",
+	".svc",
+}
+
+// isInternalHost returns true if the provided host, taken from a Webhook URL,
+// is an IP address that isn't public or is a name that could only be resolved
+// within Brigade's own cluster or network. Such hosts are refused so that
+// Webhooks cannot be used to reach services that are not otherwise exposed.
+// Since names can resolve to anything, this is only a first line of defense.
+// See publicAddressesOnly().
+func isInternalHost(host string) bool {
+	if ip := net.ParseIP(host); ip != nil {
+		return !isPublicIP(ip)
+	}
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	// Names with a single label, like "mongodb", are resolved relative to the
+	// search domains of the cluster
+	if host == "localhost" || !strings.Contains(host, ".") {
+		return true
+	}
+	for _, suffix := range internalHostSuffixes {
+		if strings.HasSuffix(host, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// sharedAddressSpace is the range of addresses reserved for carrier-grade NAT,
+// which some Kubernetes distributions also use for Pods and Services.
+var sharedAddressSpace = &net.IPNet{
+	IP:   net.IPv4(100, 64, 0, 0),
+	Mask: net.CIDRMask(10, 32),
+}
+
+// isPublicIP returns true if the provided IP address is a public, unicast
+// address and false if it is any sort of loopback, private, link-local (which
+// includes cloud providers' metadata services), or otherwise special address.
+func isPublicIP(ip net.IP) bool {
+	return ip.IsGlobalUnicast() &&
+		!ip.IsPrivate() &&
+		!sharedAddressSpace.Contains(ip)
+}
+
+// Notification is the payload delivered to a Webhook when a Worker or Job
+// enters a phase of interest.
+type Notification struct {
+	// ProjectID is the identifier of the Project the Event belongs to.
+	ProjectID string `json:"projectID" bson:"projectID"`
+	// EventID is the identifier of the Event whose Worker or Job changed phase.
+	EventID string `json:"eventID" bson:"eventID"`
+	// Source is the source of the Event.
+	Source string `json:"source" bson:"source"`
+	// Type is the type of the Event.
+	Type string `json:"type" bson:"type"`
+	// WorkerPhase is the phase the Event's Worker entered. It is empty for Job
+	// notifications.
+	WorkerPhase WorkerPhase `json:"workerPhase,omitempty" bson:"workerPhase,omitempty"` // nolint: lll
+	// JobName is the name of the Job that changed phase. It is empty for Worker
+	// notifications.
+	JobName string `json:"jobName,omitempty" bson:"jobName,omitempty"`
+	// JobPhase is the phase the Job entered. It is empty for Worker
+	// notifications.
+	JobPhase JobPhase `json:"jobPhase,omitempty" bson:"jobPhase,omitempty"`
+	// Time is the time at which the phase change was recorded.
+	Time time.Time `json:"time" bson:"time"`
+}
+
+// WebhookDeliveryPhase represents where a WebhookDelivery is within its
+// lifecycle.
+type WebhookDeliveryPhase string
+
+const (
+	// WebhookDeliveryPhasePending represents the state wherein a
+	// WebhookDelivery has not yet succeeded, but will be (re)attempted.
+	WebhookDeliveryPhasePending WebhookDeliveryPhase = "PENDING"
+	// WebhookDeliveryPhaseSucceeded represents the state wherein a
+	// WebhookDelivery's endpoint responded with a 2xx status code.
+	WebhookDeliveryPhaseSucceeded WebhookDeliveryPhase = "SUCCEEDED"
+	// WebhookDeliveryPhaseFailed represents the state wherein every permitted
+	// attempt at a WebhookDelivery has failed and no further attempts will be
+	// made.
+	WebhookDeliveryPhaseFailed WebhookDeliveryPhase = "FAILED"
+)
+
+// WebhookDelivery represents the delivery of a single Notification to a single
+// Webhook, including the history of every attempt at that delivery.
+type WebhookDelivery struct {
+	// ObjectMeta contains WebhookDelivery metadata.
+	meta.ObjectMeta `json:"metadata" bson:",inline"`
+	// ProjectID is the identifier of the Project the Webhook belongs to.
+	ProjectID string `json:"projectID" bson:"projectID"`
+	// Webhook is the name of the Webhook being notified.
+	Webhook string `json:"webhook" bson:"webhook"`
+	// URL is the address to which the Notification is POSTed.
+	URL string `json:"url" bson:"url"`
+	// SecretKey is the key of the Project Secret used to sign the Notification,
+	// if any.
+	SecretKey string `json:"secretKey,omitempty" bson:"secretKey,omitempty"`
+	// Notification is the payload being delivered.
+	Notification Notification `json:"notification" bson:"notification"`
+	// Status contains details of the WebhookDelivery's progress.
+	Status WebhookDeliveryStatus `json:"status" bson:"status"`
+}
+
+// MarshalJSON amends WebhookDelivery instances with type metadata.
+func (w WebhookDelivery) MarshalJSON() ([]byte, error) {
+	type Alias WebhookDelivery
+	return json.Marshal(
+		struct {
+			meta.TypeMeta `json:",inline"`
+			Alias         `json:",inline"`
+		}{
+			TypeMeta: meta.TypeMeta{
+				APIVersion: meta.APIVersion,
+				Kind:       WebhookDeliveryKind,
+			},
+			Alias: (Alias)(w),
+		},
+	)
+}
+
+// WebhookDeliveryStatus represents the status of a WebhookDelivery.
+type WebhookDeliveryStatus struct {
+	// Phase indicates where the WebhookDelivery is in its lifecycle.
+	Phase WebhookDeliveryPhase `json:"phase" bson:"phase"`
+	// Attempts lists every attempt made at the WebhookDelivery, oldest first.
+	Attempts []WebhookDeliveryAttempt `json:"attempts,omitempty" bson:"attempts,omitempty"` // nolint: lll
+	// NextAttempt indicates when the WebhookDelivery will next be attempted. It
+	// is only meaningful while the WebhookDelivery is PENDING.
+	NextAttempt *time.Time `json:"nextAttempt,omitempty" bson:"nextAttempt,omitempty"` // nolint: lll
+}
+
+// WebhookDeliveryAttempt represents a single attempt at a WebhookDelivery.
+type WebhookDeliveryAttempt struct {
+	// Time is the time at which the attempt was made.
+	Time time.Time `json:"time" bson:"time"`
+	// StatusCode is the HTTP status code the endpoint responded with. It is zero
+	// if no response was received.
+	StatusCode int `json:"statusCode,omitempty" bson:"statusCode,omitempty"`
+	// Error describes why the attempt failed, if it did.
+	Error string `json:"error,omitempty" bson:"error,omitempty"`
+}
+
+// WebhookDeliveriesSelector represents useful filter criteria when selecting
+// multiple WebhookDeliveries for API group operations like list.
+type WebhookDeliveriesSelector struct {
+	// ProjectID specifies that only WebhookDeliveries for the specified Project
+	// should be selected.
+	ProjectID string
+	// EventID specifies that only WebhookDeliveries of Notifications concerning
+	// the specified Event should be selected.
+	EventID string
+	// Webhook specifies that only WebhookDeliveries to the Webhook with the
+	// specified name should be selected.
+	Webhook string
+}
+
+// WebhooksService is the specialized interface for inspecting the deliveries
+// of Notifications to Projects' Webhooks. It's decoupled from underlying
+// technology choices (e.g. data store, message bus, etc.) to keep business
+// logic reusable and consistent while the underlying tech stack remains free to
+// change.
+type WebhooksService interface {
+	// ListDeliveries returns a WebhookDeliveryList, with its Items
+	// (WebhookDeliveries) ordered by age, newest first. Criteria for which
+	// WebhookDeliveries should be retrieved can be specified using the
+	// WebhookDeliveriesSelector parameter. If the specified Project does not
+	// exist, implementations MUST return a *meta.ErrNotFound error.
+	ListDeliveries(
+		context.Context,
+		WebhookDeliveriesSelector,
+		meta.ListOptions,
+	) (meta.List[WebhookDelivery], error)
+}
+
+type webhooksService struct {
+	authorize              AuthorizeFn
+	projectsStore          ProjectsStore
+	webhookDeliveriesStore WebhookDeliveriesStore
+}
+
+// NewWebhooksService returns a specialized interface for inspecting the
+// deliveries of Notifications to Projects' Webhooks.
+func NewWebhooksService(
+	authorizeFn AuthorizeFn,
+	projectsStore ProjectsStore,
+	webhookDeliveriesStore WebhookDeliveriesStore,
+) WebhooksService {
+	return &webhooksService{
+		authorize:              authorizeFn,
+		projectsStore:          projectsStore,
+		webhookDeliveriesStore: webhookDeliveriesStore,
+	}
+}
+
+func (w *webhooksService) ListDeliveries(
+	ctx context.Context,
+	selector WebhookDeliveriesSelector,
+	opts meta.ListOptions,
+) (meta.List[WebhookDelivery], error) {
+	if err := w.authorize(ctx, RoleReader, ""); err != nil {
+		return meta.List[WebhookDelivery]{}, err
+	}
+
+	// Make sure the project exists
+	if _, err := w.projectsStore.Get(ctx, selector.ProjectID); err != nil {
+		return meta.List[WebhookDelivery]{}, errors.Wrapf(
+			err,
+			"error retrieving project %q from store",
+			selector.ProjectID,
+		)
+	}
+
+	if opts.Limit == 0 {
+		opts.Limit = 20
+	}
+	deliveries, err := w.webhookDeliveriesStore.List(ctx, selector, opts)
+	if err != nil {
+		return deliveries, errors.Wrapf(
+			err,
+			"error retrieving webhook deliveries for project %q from store",
+			selector.ProjectID,
+		)
+	}
+	return deliveries, nil
+}
+
+// Notifier is the specialized interface for informing interested parties that
+// a Worker or Job has entered a new phase.
+type Notifier interface {
+	// NotifyWorkerPhase informs interested parties that the provided Event's
+	// Worker has entered the provided WorkerPhase.
+	NotifyWorkerPhase(context.Context, Event, WorkerPhase) error
+	// NotifyJobPhase informs interested parties that the provided Event's Job
+	// with the provided name has entered the provided JobPhase.
+	NotifyJobPhase(context.Context, Event, string, JobPhase) error
+}
+
+type webhookNotifier struct {
+	projectsStore          ProjectsStore
+	webhookDeliveriesStore WebhookDeliveriesStore
+	// nowFn is overridable for testing purposes
+	nowFn func() time.Time
+}
+
+// NewWebhookNotifier returns a Notifier that records a pending WebhookDelivery
+// for every one of a Project's Webhooks that is interested in a phase change.
+// The WebhookDeliveries are subsequently carried out asynchronously by the
+// WebhookDeliveryService.
+func NewWebhookNotifier(
+	projectsStore ProjectsStore,
+	webhookDeliveriesStore WebhookDeliveriesStore,
+) Notifier {
+	return &webhookNotifier{
+		projectsStore:          projectsStore,
+		webhookDeliveriesStore: webhookDeliveriesStore,
+		nowFn: func() time.Time {
+			return time.Now().UTC()
+		},
+	}
+}
+
+func (w *webhookNotifier) NotifyWorkerPhase(
+	ctx context.Context,
+	event Event,
+	phase WorkerPhase,
+) error {
+	return w.notify(
+		ctx,
+		event,
+		func(webhook Webhook) bool {
+			return webhook.hasWorkerPhase(phase)
+		},
+		Notification{
+			WorkerPhase: phase,
+		},
+	)
+}
+
+func (w *webhookNotifier) NotifyJobPhase(
+	ctx context.Context,
+	event Event,
+	jobName string,
+	phase JobPhase,
+) error {
+	return w.notify(
+		ctx,
+		event,
+		func(webhook Webhook) bool {
+			return webhook.hasJobPhase(phase)
+		},
+		Notification{
+			JobName:  jobName,
+			JobPhase: phase,
+		},
+	)
+}
+
+// notify records a pending WebhookDelivery of the provided Notification,
+// amended with details of the provided Event, for each of the Event's
+// Project's Webhooks that the provided function selects.
+func (w *webhookNotifier) notify(
+	ctx context.Context,
+	event Event,
+	interested func(Webhook) bool,
+	notification Notification,
+) error {
+	project, err := w.projectsStore.Get(ctx, event.ProjectID)
+	if err != nil {
+		return errors.Wrapf(
+			err,
+			"error retrieving project %q from store",
+			event.ProjectID,
+		)
+	}
+	now := w.nowFn()
+	notification.ProjectID = event.ProjectID
+	notification.EventID = event.ID
+	notification.Source = event.Source
+	notification.Type = event.Type
+	notification.Time = now
+	for _, webhook := range project.Spec.Webhooks {
+		if !interested(webhook) {
+			continue
+		}
+		delivery := WebhookDelivery{
+			ObjectMeta: meta.ObjectMeta{
+				ID:      uuid.NewV4().String(),
+				Created: &now,
+			},
+			ProjectID:    project.ID,
+			Webhook:      webhook.Name,
+			URL:          webhook.URL,
+			SecretKey:    webhook.SecretKey,
+			Notification: notification,
+			Status: WebhookDeliveryStatus{
+				Phase:       WebhookDeliveryPhasePending,
+				NextAttempt: &now,
+			},
+		}
+		if err = w.webhookDeliveriesStore.Create(ctx, delivery); err != nil {
+			return errors.Wrapf(
+				err,
+				"error storing new delivery for project %q webhook %q",
+				project.ID,
+				webhook.Name,
+			)
+		}
+	}
+	return nil
+}
+
+// WebhookDeliveriesStore is an interface for components that implement
+// WebhookDelivery persistence concerns.
+type WebhookDeliveriesStore interface {
+	// Create stores the provided WebhookDelivery.
+	Create(context.Context, WebhookDelivery) error
+	// List returns a WebhookDeliveryList, with its Items (WebhookDeliveries)
+	// ordered by age, newest first. Criteria for which WebhookDeliveries should
+	// be retrieved can be specified using the WebhookDeliveriesSelector
+	// parameter.
+	List(
+		context.Context,
+		WebhookDeliveriesSelector,
+		meta.ListOptions,
+	) (meta.List[WebhookDelivery], error)
+	// Claim retrieves one PENDING WebhookDelivery that is due to be attempted at
+	// the provided time and, in the same atomic operation, postpones its next
+	// attempt until the provided lease expiry so that no one else claims it in
+	// the meantime. If no WebhookDelivery is due, implementations MUST return
+	// nil.
+	Claim(
+		ctx context.Context,
+		now time.Time,
+		leaseExpiry time.Time,
+	) (*WebhookDelivery, error)
+	// UpdateStatus updates the status of the specified WebhookDelivery. If the
+	// specified WebhookDelivery does not exist, implementations MUST return a
+	// *meta.ErrNotFound error.
+	UpdateStatus(
+		ctx context.Context,
+		id string,
+		status WebhookDeliveryStatus,
+	) error
+}
@@ -0,0 +1,496 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/brigadecore/brigade/v2/apiserver/internal/meta"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateWebhooks(t *testing.T) {
+	testCases := []struct {
+		name       string
+		webhooks   []Webhook
+		assertions func(error)
+	}{
+		{
+			name: "no webhooks",
+			assertions: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "invalid webhooks",
+			webhooks: []Webhook{
+				{
+					Name:         "slack",
+					URL:          "ftp://example.com",
+					WorkerPhases: []WorkerPhase{WorkerPhaseFailed},
+				},
+				{
+					Name: "slack",
+					URL:  "https://example.com",
+				},
+				{
+					Name:         "mongodb",
+					URL:          "http://brigade-mongodb.brigade.svc:27017",
+					WorkerPhases: []WorkerPhase{WorkerPhaseFailed},
+				},
+			},
+			assertions: func(err error) {
+				require.Error(t, err)
+				require.IsType(t, &meta.ErrBadRequest{}, err)
+				badReqErr := err.(*meta.ErrBadRequest)
+				require.Equal(t, "Invalid webhooks.", badReqErr.Reason)
+				require.Len(t, badReqErr.Details, 4)
+				require.Contains(t, badReqErr.Details[3], "not refer to a public")
+			},
+		},
+		{
+			name: "valid webhooks",
+			webhooks: []Webhook{
+				{
+					Name:         "slack",
+					URL:          "https://hooks.example.com/brigade",
+					WorkerPhases: []WorkerPhase{WorkerPhaseFailed},
+				},
+				{
+					Name:      "dashboard",
+					URL:       "http://dashboard.example.com/jobs",
+					JobPhases: []JobPhase{JobPhaseSucceeded, JobPhaseFailed},
+				},
+			},
+			assertions: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.assertions(validateWebhooks(testCase.webhooks))
+		})
+	}
+}
+
+func TestIsInternalHost(t *testing.T) {
+	testCases := []struct {
+		host     string
+		internal bool
+	}{
+		{host: "hooks.example.com"},
+		{host: "93.184.216.34"},
+		{host: "2606:2800:220:1:248:1893:25c8:1946"},
+		{host: "localhost", internal: true},
+		{host: "mongodb", internal: true},
+		{host: "brigade-apiserver.brigade.svc", internal: true},
+		{host: "brigade-apiserver.brigade.svc.cluster.local.", internal: true},
+		{host: "metadata.google.internal", internal: true},
+		{host: "127.0.0.1", internal: true},
+		{host: "10.0.0.1", internal: true},
+		{host: "100.64.0.1", internal: true},
+		{host: "169.254.169.254", internal: true},
+		{host: "0.0.0.0", internal: true},
+		{host: "::1", internal: true},
+		{host: "fd00::1", internal: true},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.host, func(t *testing.T) {
+			require.Equal(t, testCase.internal, isInternalHost(testCase.host))
+		})
+	}
+}
+
+func TestNewWebhooksService(t *testing.T) {
+	projectsStore := &mockProjectsStore{}
+	webhookDeliveriesStore := &mockWebhookDeliveriesStore{}
+	svc, ok := NewWebhooksService(
+		alwaysAuthorize,
+		projectsStore,
+		webhookDeliveriesStore,
+	).(*webhooksService)
+	require.True(t, ok)
+	require.NotNil(t, svc.authorize)
+	require.Same(t, projectsStore, svc.projectsStore)
+	require.Same(t, webhookDeliveriesStore, svc.webhookDeliveriesStore)
+}
+
+func TestWebhooksServiceListDeliveries(t *testing.T) {
+	const testProjectID = "blue-book"
+	testCases := []struct {
+		name       string
+		service    WebhooksService
+		assertions func(meta.List[WebhookDelivery], error)
+	}{
+		{
+			name: "unauthorized",
+			service: &webhooksService{
+				authorize: neverAuthorize,
+			},
+			assertions: func(_ meta.List[WebhookDelivery], err error) {
+				require.Error(t, err)
+				require.IsType(t, &meta.ErrAuthorization{}, err)
+			},
+		},
+		{
+			name: "error retrieving project from store",
+			service: &webhooksService{
+				authorize: alwaysAuthorize,
+				projectsStore: &mockProjectsStore{
+					GetFn: func(context.Context, string) (Project, error) {
+						return Project{}, errors.New("something went wrong")
+					},
+				},
+			},
+			assertions: func(_ meta.List[WebhookDelivery], err error) {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), "something went wrong")
+				require.Contains(t, err.Error(), "error retrieving project")
+			},
+		},
+		{
+			name: "error retrieving webhook deliveries from store",
+			service: &webhooksService{
+				authorize: alwaysAuthorize,
+				projectsStore: &mockProjectsStore{
+					GetFn: func(context.Context, string) (Project, error) {
+						return Project{}, nil
+					},
+				},
+				webhookDeliveriesStore: &mockWebhookDeliveriesStore{
+					ListFn: func(
+						context.Context,
+						WebhookDeliveriesSelector,
+						meta.ListOptions,
+					) (meta.List[WebhookDelivery], error) {
+						return meta.List[WebhookDelivery]{},
+							errors.New("something went wrong")
+					},
+				},
+			},
+			assertions: func(_ meta.List[WebhookDelivery], err error) {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), "something went wrong")
+				require.Contains(t, err.Error(), "error retrieving webhook deliveries")
+			},
+		},
+		{
+			name: "success",
+			service: &webhooksService{
+				authorize: alwaysAuthorize,
+				projectsStore: &mockProjectsStore{
+					GetFn: func(context.Context, string) (Project, error) {
+						return Project{}, nil
+					},
+				},
+				webhookDeliveriesStore: &mockWebhookDeliveriesStore{
+					ListFn: func(
+						_ context.Context,
+						selector WebhookDeliveriesSelector,
+						opts meta.ListOptions,
+					) (meta.List[WebhookDelivery], error) {
+						require.Equal(t, testProjectID, selector.ProjectID)
+						require.Equal(t, int64(20), opts.Limit)
+						return meta.List[WebhookDelivery]{
+							Items: []WebhookDelivery{{}},
+						}, nil
+					},
+				},
+			},
+			assertions: func(deliveries meta.List[WebhookDelivery], err error) {
+				require.NoError(t, err)
+				require.Len(t, deliveries.Items, 1)
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			deliveries, err := testCase.service.ListDeliveries(
+				context.Background(),
+				WebhookDeliveriesSelector{
+					ProjectID: testProjectID,
+				},
+				meta.ListOptions{},
+			)
+			testCase.assertions(deliveries, err)
+		})
+	}
+}
+
+func TestNewWebhookNotifier(t *testing.T) {
+	projectsStore := &mockProjectsStore{}
+	webhookDeliveriesStore := &mockWebhookDeliveriesStore{}
+	notifier, ok := NewWebhookNotifier(
+		projectsStore,
+		webhookDeliveriesStore,
+	).(*webhookNotifier)
+	require.True(t, ok)
+	require.Same(t, projectsStore, notifier.projectsStore)
+	require.Same(t, webhookDeliveriesStore, notifier.webhookDeliveriesStore)
+	require.NotNil(t, notifier.nowFn)
+}
+
+func TestWebhookNotifierNotify(t *testing.T) {
+	now := time.Now().UTC()
+	testEvent := Event{
+		ObjectMeta: meta.ObjectMeta{
+			ID: "tunguska",
+		},
+		ProjectID: "blue-book",
+		Source:    "brigade.sh/cli",
+		Type:      "exec",
+	}
+	testProject := Project{
+		ObjectMeta: meta.ObjectMeta{
+			ID: "blue-book",
+		},
+		Spec: ProjectSpec{
+			Webhooks: []Webhook{
+				{
+					Name:         "slack",
+					URL:          "https://hooks.example.com/brigade",
+					WorkerPhases: []WorkerPhase{WorkerPhaseFailed},
+					SecretKey:    "slackSecret",
+				},
+				{
+					Name:      "dashboard",
+					URL:       "https://dashboard.example.com/jobs",
+					JobPhases: []JobPhase{JobPhaseFailed, JobPhaseSucceeded},
+				},
+			},
+		},
+	}
+	testCases := []struct {
+		name       string
+		notify     func(Notifier) error
+		notifier   *webhookNotifier
+		assertions func(error)
+	}{
+		{
+			name: "error retrieving project from store",
+			notify: func(notifier Notifier) error {
+				return notifier.NotifyWorkerPhase(
+					context.Background(),
+					testEvent,
+					WorkerPhaseFailed,
+				)
+			},
+			notifier: &webhookNotifier{
+				projectsStore: &mockProjectsStore{
+					GetFn: func(context.Context, string) (Project, error) {
+						return Project{}, errors.New("something went wrong")
+					},
+				},
+			},
+			assertions: func(err error) {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), "something went wrong")
+				require.Contains(t, err.Error(), "error retrieving project")
+			},
+		},
+		{
+			name: "error storing delivery",
+			notify: func(notifier Notifier) error {
+				return notifier.NotifyWorkerPhase(
+					context.Background(),
+					testEvent,
+					WorkerPhaseFailed,
+				)
+			},
+			notifier: &webhookNotifier{
+				projectsStore: &mockProjectsStore{
+					GetFn: func(context.Context, string) (Project, error) {
+						return testProject, nil
+					},
+				},
+				webhookDeliveriesStore: &mockWebhookDeliveriesStore{
+					CreateFn: func(context.Context, WebhookDelivery) error {
+						return errors.New("something went wrong")
+					},
+				},
+			},
+			assertions: func(err error) {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), "something went wrong")
+				require.Contains(t, err.Error(), "error storing new delivery")
+			},
+		},
+		{
+			name: "no webhook is interested",
+			notify: func(notifier Notifier) error {
+				return notifier.NotifyWorkerPhase(
+					context.Background(),
+					testEvent,
+					WorkerPhaseSucceeded,
+				)
+			},
+			notifier: &webhookNotifier{
+				projectsStore: &mockProjectsStore{
+					GetFn: func(context.Context, string) (Project, error) {
+						return testProject, nil
+					},
+				},
+				// No deliveries are expected, so none are mocked
+				webhookDeliveriesStore: &mockWebhookDeliveriesStore{},
+			},
+			assertions: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "worker phase",
+			notify: func(notifier Notifier) error {
+				return notifier.NotifyWorkerPhase(
+					context.Background(),
+					testEvent,
+					WorkerPhaseFailed,
+				)
+			},
+			notifier: &webhookNotifier{
+				projectsStore: &mockProjectsStore{
+					GetFn: func(context.Context, string) (Project, error) {
+						return testProject, nil
+					},
+				},
+				webhookDeliveriesStore: &mockWebhookDeliveriesStore{
+					CreateFn: func(_ context.Context, delivery WebhookDelivery) error {
+						require.NotEmpty(t, delivery.ID)
+						require.Equal(t, testProject.ID, delivery.ProjectID)
+						require.Equal(t, "slack", delivery.Webhook)
+						require.Equal(t, "slackSecret", delivery.SecretKey)
+						require.Equal(
+							t,
+							Notification{
+								ProjectID:   testEvent.ProjectID,
+								EventID:     testEvent.ID,
+								Source:      testEvent.Source,
+								Type:        testEvent.Type,
+								WorkerPhase: WorkerPhaseFailed,
+								Time:        now,
+							},
+							delivery.Notification,
+						)
+						require.Equal(
+							t,
+							WebhookDeliveryPhasePending,
+							delivery.Status.Phase,
+						)
+						require.Equal(t, now, *delivery.Status.NextAttempt)
+						return nil
+					},
+				},
+			},
+			assertions: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "job phase",
+			notify: func(notifier Notifier) error {
+				return notifier.NotifyJobPhase(
+					context.Background(),
+					testEvent,
+					"italian",
+					JobPhaseSucceeded,
+				)
+			},
+			notifier: &webhookNotifier{
+				projectsStore: &mockProjectsStore{
+					GetFn: func(context.Context, string) (Project, error) {
+						return testProject, nil
+					},
+				},
+				webhookDeliveriesStore: &mockWebhookDeliveriesStore{
+					CreateFn: func(_ context.Context, delivery WebhookDelivery) error {
+						require.Equal(t, "dashboard", delivery.Webhook)
+						require.Equal(t, "italian", delivery.Notification.JobName)
+						require.Equal(
+							t,
+							JobPhaseSucceeded,
+							delivery.Notification.JobPhase,
+						)
+						require.Empty(t, delivery.Notification.WorkerPhase)
+						return nil
+					},
+				},
+			},
+			assertions: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.notifier.nowFn = func() time.Time {
+				return now
+			}
+			testCase.assertions(testCase.notify(testCase.notifier))
+		})
+	}
+}
+
+type mockNotifier struct {
+	NotifyWorkerPhaseFn func(context.Context, Event, WorkerPhase) error
+	NotifyJobPhaseFn    func(context.Context, Event, string, JobPhase) error
+}
+
+func (m *mockNotifier) NotifyWorkerPhase(
+	ctx context.Context,
+	event Event,
+	phase WorkerPhase,
+) error {
+	return m.NotifyWorkerPhaseFn(ctx, event, phase)
+}
+
+func (m *mockNotifier) NotifyJobPhase(
+	ctx context.Context,
+	event Event,
+	jobName string,
+	phase JobPhase,
+) error {
+	return m.NotifyJobPhaseFn(ctx, event, jobName, phase)
+}
+
+type mockWebhookDeliveriesStore struct {
+	CreateFn func(context.Context, WebhookDelivery) error
+	ListFn   func(
+		context.Context,
+		WebhookDeliveriesSelector,
+		meta.ListOptions,
+	) (meta.List[WebhookDelivery], error)
+	ClaimFn        func(context.Context, time.Time, time.Time) (*WebhookDelivery, error) // nolint: lll
+	UpdateStatusFn func(context.Context, string, WebhookDeliveryStatus) error
+}
+
+func (m *mockWebhookDeliveriesStore) Create(
+	ctx context.Context,
+	delivery WebhookDelivery,
+) error {
+	return m.CreateFn(ctx, delivery)
+}
+
+func (m *mockWebhookDeliveriesStore) List(
+	ctx context.Context,
+	selector WebhookDeliveriesSelector,
+	opts meta.ListOptions,
+) (meta.List[WebhookDelivery], error) {
+	return m.ListFn(ctx, selector, opts)
+}
+
+func (m *mockWebhookDeliveriesStore) Claim(
+	ctx context.Context,
+	now time.Time,
+	leaseExpiry time.Time,
+) (*WebhookDelivery, error) {
+	return m.ClaimFn(ctx, now, leaseExpiry)
+}
+
+func (m *mockWebhookDeliveriesStore) UpdateStatus(
+	ctx context.Context,
+	id string,
+	status WebhookDeliveryStatus,
+) error {
+	return m.UpdateStatusFn(ctx, id, status)
+}
@@ -137,10 +137,20 @@ type WorkerSpec struct {
 	// fixed-point integer using one of these suffixes: E, P, T, G, M, K.
 	// Power-of-two equivalents may also be used: Ei, Pi, Ti, Gi, Mi, Ki.
 	WorkspaceSize string `json:"workspaceSize,omitempty" bson:"workspaceSize,omitempty"` // nolint: lll
+	// ConcurrencyGroup is an optional template, evaluated against each new
+	// Event, that determines the concurrency group the Event belongs to. e.g.
+	// {{.Git.Ref}} groups Events by git ref. When a new Event joins a group, any
+	// older Events in the group whose Workers have not yet reached a terminal
+	// phase are canceled (or aborted). Events for which the template evaluates
+	// to an empty string don't belong to any group.
+	ConcurrencyGroup string `json:"concurrencyGroup,omitempty" bson:"concurrencyGroup,omitempty"` // nolint: lll
 	// Git contains git-specific Worker details.
 	Git *GitConfig `json:"git,omitempty"`
 	// Kubernetes contains Kubernetes-specific Worker details.
 	Kubernetes *KubernetesConfig `json:"kubernetes,omitempty" bson:"kubernetes,omitempty"` // nolint: lll
+	// Host specifies criteria for selecting a suitable host (substrate node) for
+	// the Worker.
+	Host *WorkerHost `json:"host,omitempty" bson:"host,omitempty"`
 	// JobPolicies specifies policies for any Jobs spawned by the Worker.
 	JobPolicies *JobPolicies `json:"jobPolicies,omitempty" bson:"jobPolicies,omitempty"` // nolint: lll
 	// LogLevel specifies the desired granularity of Worker log output.
@@ -190,11 +200,44 @@ type KubernetesConfig struct {
 	ImagePullSecrets []string `json:"imagePullSecrets,omitempty" bson:"imagePullSecrets,omitempty"` // nolint: lll
 }
 
+// WorkerHost represents criteria for selecting a suitable host (substrate
+// node) for a Worker.
+type WorkerHost struct {
+	// NodeSelector specifies labels that must be present on the substrate node to
+	// host the Worker.
+	NodeSelector map[string]string `json:"nodeSelector,omitempty" bson:"nodeSelector,omitempty"` // nolint: lll
+	// Tolerations permit the Worker to be hosted on substrate nodes having
+	// matching taints.
+	Tolerations []Toleration `json:"tolerations,omitempty" bson:"tolerations,omitempty"` // nolint: lll
+	// NodeAffinity specifies requirements, beyond NodeSelector, that a substrate
+	// node must or should satisfy to host the Worker.
+	NodeAffinity *NodeAffinity `json:"nodeAffinity,omitempty" bson:"nodeAffinity,omitempty"` // nolint: lll
+	// PriorityClassName specifies the substrate's priority class for the
+	// Worker.
+	PriorityClassName string `json:"priorityClassName,omitempty" bson:"priorityClassName,omitempty"` // nolint: lll
+}
+
 // JobPolicies represents policies for any Jobs spawned by a Worker.
 type JobPolicies struct {
 	// AllowPrivileged specifies whether the Worker is permitted to launch Jobs
 	// that utilize privileged containers.
 	AllowPrivileged bool `json:"allowPrivileged" bson:"allowPrivileged"`
+	// AllowedVolumeTypes enumerates the types of additional volumes that Jobs
+	// launched by the Worker are permitted to mount. If empty, Jobs may not
+	// mount any additional volumes.
+	AllowedVolumeTypes []JobVolumeType `json:"allowedVolumeTypes,omitempty" bson:"allowedVolumeTypes,omitempty"` // nolint: lll
+	// AllowedTolerations enumerates the Tolerations that Jobs launched by the
+	// Worker are permitted to specify. A Job's Toleration is permitted if every
+	// taint it matches is also matched by one of these. If empty, Jobs may not
+	// specify any Tolerations.
+	AllowedTolerations []Toleration `json:"allowedTolerations,omitempty" bson:"allowedTolerations,omitempty"` // nolint: lll
+	// AllowNodeAffinity specifies whether Jobs launched by the Worker are
+	// permitted to specify NodeAffinity.
+	AllowNodeAffinity bool `json:"allowNodeAffinity,omitempty" bson:"allowNodeAffinity,omitempty"` // nolint: lll
+	// AllowedPriorityClassNames enumerates the substrate priority classes that
+	// Jobs launched by the Worker are permitted to use. If empty, Jobs may not
+	// specify a priority class.
+	AllowedPriorityClassNames []string `json:"allowedPriorityClassNames,omitempty" bson:"allowedPriorityClassNames,omitempty"` // nolint: lll
 	// AllowDockerSocketMount specifies whether the Worker is permitted to launch
 	// Jobs that mount the underlying host's Docker socket into its own file
 	// system.
@@ -220,6 +263,16 @@ type WorkerStatus struct {
 	Ended *time.Time `json:"ended,omitempty" bson:"ended,omitempty"`
 	// Phase indicates where the Worker is in its lifecycle.
 	Phase WorkerPhase `json:"phase,omitempty" bson:"phase,omitempty"`
+	// ExitCode is the exit code of the Worker's container, if it has exited.
+	ExitCode *int32 `json:"exitCode,omitempty" bson:"exitCode,omitempty"`
+	// Reason is a brief, machine-readable explanation of why the Worker is in its
+	// current phase, e.g. "OOMKilled", "ImagePullBackOff", or "Unschedulable".
+	Reason string `json:"reason,omitempty" bson:"reason,omitempty"`
+	// Message is a human-readable elaboration on Reason.
+	Message string `json:"message,omitempty" bson:"message,omitempty"`
+	// Containers describes the status of each of the Worker's containers,
+	// including init containers.
+	Containers []ContainerStatus `json:"containers,omitempty" bson:"containers,omitempty"` // nolint: lll
 }
 
 // WorkersService is the specialized interface for managing Workers. It's
@@ -266,6 +319,7 @@ type workersService struct {
 	eventsStore   EventsStore
 	workersStore  WorkersStore
 	substrate     Substrate
+	notifier      Notifier
 }
 
 // NewWorkersService returns a specialized interface for managing Workers.
@@ -275,6 +329,7 @@ func NewWorkersService(
 	eventsStore EventsStore,
 	workersStore WorkersStore,
 	substrate Substrate,
+	notifier Notifier,
 ) WorkersService {
 	return &workersService{
 		authorize:     authorizeFn,
@@ -282,6 +337,7 @@ func NewWorkersService(
 		eventsStore:   eventsStore,
 		workersStore:  workersStore,
 		substrate:     substrate,
+		notifier:      notifier,
 	}
 }
 
@@ -455,6 +511,7 @@ func (w *workersService) Timeout(
 	if err := w.workersStore.Timeout(ctx, eventID); err != nil {
 		return errors.Wrapf(err, "error timing out worker for event %q", eventID)
 	}
+	w.notifyPhase(ctx, event, WorkerPhaseTimedOut)
 
 	return w.cleanup(ctx, event)
 }
@@ -478,15 +535,42 @@ func (w *workersService) updateStatus(
 		}
 	}
 
-	return errors.Wrapf(
-		w.workersStore.UpdateStatus(
-			ctx,
-			event.ID,
-			status,
-		),
-		"error updating status of event %q worker in store",
+	if err := w.workersStore.UpdateStatus(
+		ctx,
 		event.ID,
-	)
+		status,
+	); err != nil {
+		return errors.Wrapf(
+			err,
+			"error updating status of event %q worker in store",
+			event.ID,
+		)
+	}
+
+	if status.Phase != event.Worker.Status.Phase {
+		w.notifyPhase(ctx, event, status.Phase)
+	}
+	return nil
+}
+
+// notifyPhase informs interested parties that the provided Event's Worker has
+// entered the provided phase. A failure to do so is logged, but isn't allowed
+// to fail the operation that changed the Worker's phase.
+func (w *workersService) notifyPhase(
+	ctx context.Context,
+	event Event,
+	phase WorkerPhase,
+) {
+	if err := w.notifier.NotifyWorkerPhase(ctx, event, phase); err != nil {
+		log.Println(
+			errors.Wrapf(
+				err,
+				"error sending notifications of event %q worker phase %q",
+				event.ID,
+				phase,
+			),
+		)
+	}
 }
 
 // cleanup is an internal helper func created so that multiple exported
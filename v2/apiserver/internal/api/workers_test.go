@@ -15,12 +15,14 @@ func TestNewWorkersService(t *testing.T) {
 	eventsStore := &mockEventsStore{}
 	workersStore := &mockWorkersStore{}
 	substrate := &mockSubstrate{}
+	notifier := &mockNotifier{}
 	svc, ok := NewWorkersService(
 		alwaysAuthorize,
 		projectsStore,
 		eventsStore,
 		workersStore,
 		substrate,
+		notifier,
 	).(*workersService)
 	require.True(t, ok)
 	require.NotNil(t, svc.authorize)
@@ -28,6 +30,7 @@ func TestNewWorkersService(t *testing.T) {
 	require.Same(t, eventsStore, svc.eventsStore)
 	require.Same(t, workersStore, svc.workersStore)
 	require.Same(t, substrate, svc.substrate)
+	require.Same(t, notifier, svc.notifier)
 }
 
 func TestWorkersServiceStart(t *testing.T) {
@@ -499,7 +502,16 @@ func TestWorkersServiceUpdateStatus(t *testing.T) {
 				authorize: alwaysAuthorize,
 				eventsStore: &mockEventsStore{
 					GetFn: func(context.Context, string) (Event, error) {
-						return Event{}, nil
+						return Event{
+							ObjectMeta: meta.ObjectMeta{
+								ID: testEventID,
+							},
+							Worker: Worker{
+								Status: WorkerStatus{
+									Phase: WorkerPhaseRunning,
+								},
+							},
+						}, nil
 					},
 				},
 				workersStore: &mockWorkersStore{
@@ -507,6 +519,17 @@ func TestWorkersServiceUpdateStatus(t *testing.T) {
 						return nil
 					},
 				},
+				notifier: &mockNotifier{
+					NotifyWorkerPhaseFn: func(
+						_ context.Context,
+						event Event,
+						_ WorkerPhase,
+					) error {
+						require.Equal(t, testEventID, event.ID)
+						// Errors are logged, but don't fail the status update
+						return errors.New("something went wrong")
+					},
+				},
 			},
 			assertions: func(err error) {
 				require.NoError(t, err)
@@ -717,6 +740,16 @@ func TestWorkersServiceTimeout(t *testing.T) {
 						return testEvent, nil
 					},
 				},
+				notifier: &mockNotifier{
+					NotifyWorkerPhaseFn: func(
+						_ context.Context,
+						_ Event,
+						phase WorkerPhase,
+					) error {
+						require.Equal(t, WorkerPhaseTimedOut, phase)
+						return nil
+					},
+				},
 				workersStore: &mockWorkersStore{
 					TimeoutFn: func(context.Context, string) error {
 						return nil
@@ -748,6 +781,16 @@ func TestWorkersServiceTimeout(t *testing.T) {
 						return testEvent, nil
 					},
 				},
+				notifier: &mockNotifier{
+					NotifyWorkerPhaseFn: func(
+						_ context.Context,
+						_ Event,
+						phase WorkerPhase,
+					) error {
+						require.Equal(t, WorkerPhaseTimedOut, phase)
+						return nil
+					},
+				},
 				workersStore: &mockWorkersStore{
 					TimeoutFn: func(context.Context, string) error {
 						return nil
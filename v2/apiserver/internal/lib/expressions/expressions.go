@@ -0,0 +1,428 @@
+// Package expressions implements a small boolean expression language for
+// filtering structured data. Expressions compare the values found at
+// identifier paths with string literals or with one another. For example:
+//
+//	type == "push" && (labels.branch =~ "^release/.*" || !labels.draft)
+//
+// Supported operators, in order of increasing precedence, are:
+//
+//	||        logical OR
+//	&&        logical AND
+//	!         logical NOT
+//	== !=     string equality / inequality
+//	=~ !~     regular expression match / non-match (the right operand MUST be
+//	          a string literal)
+//
+// Identifier paths consist of dot-separated names and/or bracketed string or
+// integer subscripts, e.g. labels["brigade.sh/schedule"] or payload.commits[0].
+// Numeric path segments may also follow a dot, e.g. payload.commits.0.id.
+// An operand that appears on its own (i.e. not as part of a comparison) is
+// true if its value is present, non-empty, and not "false".
+package expressions
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/pkg/errors"
+)
+
+// Resolver is a function that resolves an identifier path to a value. The bool
+// return value indicates whether any value was found at the specified path.
+type Resolver func(path []string) (string, bool)
+
+// Expression is a parsed boolean expression.
+type Expression interface {
+	// Evaluate evaluates the Expression, using the provided Resolver to resolve
+	// the values of any identifiers.
+	Evaluate(Resolver) bool
+}
+
+// Parse parses the provided string into an Expression. An error is returned if
+// the string is not a valid expression.
+func Parse(expr string) (Expression, error) {
+	tokens, err := tokenize(expr)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens}
+	e, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokenEOF {
+		return nil, errors.Errorf("unexpected %q at position %d", tok.text, tok.pos)
+	}
+	return e, nil
+}
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdentifier
+	tokenString
+	tokenNumber
+	tokenOperator
+	tokenLeftParen
+	tokenRightParen
+	tokenLeftBracket
+	tokenRightBracket
+	tokenDot
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+var operators = []string{"||", "&&", "==", "!=", "=~", "!~", "!"}
+
+func tokenize(expr string) ([]token, error) {
+	tokens := []token{}
+	runes := []rune(expr)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, token{kind: tokenLeftParen, text: "(", pos: i})
+			i++
+		case r == ')':
+			tokens = append(tokens, token{kind: tokenRightParen, text: ")", pos: i})
+			i++
+		case r == '[':
+			tokens =
+				append(tokens, token{kind: tokenLeftBracket, text: "[", pos: i})
+			i++
+		case r == ']':
+			tokens =
+				append(tokens, token{kind: tokenRightBracket, text: "]", pos: i})
+			i++
+		case r == '.':
+			tokens = append(tokens, token{kind: tokenDot, text: ".", pos: i})
+			i++
+		case r == '"':
+			start := i
+			i++
+			for ; i < len(runes) && runes[i] != '"'; i++ {
+				if runes[i] == '\\' {
+					i++
+				}
+			}
+			if i >= len(runes) {
+				return nil, errors.Errorf(
+					"unterminated string literal at position %d",
+					start,
+				)
+			}
+			i++
+			text, err := strconv.Unquote(string(runes[start:i]))
+			if err != nil {
+				return nil, errors.Errorf(
+					"invalid string literal at position %d",
+					start,
+				)
+			}
+			tokens = append(tokens, token{kind: tokenString, text: text, pos: start})
+		case unicode.IsDigit(r) && len(tokens) > 0 &&
+			tokens[len(tokens)-1].kind == tokenDot:
+			// A path segment that follows a dot, e.g. the 0 in payload.commits.0.id,
+			// is a name or index, never a number, so it mustn't consume the next dot.
+			start := i
+			for i < len(runes) && isIdentifierRune(runes[i]) {
+				i++
+			}
+			tokens = append(
+				tokens,
+				token{kind: tokenIdentifier, text: string(runes[start:i]), pos: start},
+			)
+		case unicode.IsDigit(r) || (r == '-' && i+1 < len(runes) &&
+			unicode.IsDigit(runes[i+1])):
+			start := i
+			i++
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.') {
+				i++
+			}
+			tokens = append(
+				tokens,
+				token{kind: tokenNumber, text: string(runes[start:i]), pos: start},
+			)
+		case r == '_' || unicode.IsLetter(r):
+			start := i
+			for i < len(runes) && isIdentifierRune(runes[i]) {
+				i++
+			}
+			tokens = append(
+				tokens,
+				token{kind: tokenIdentifier, text: string(runes[start:i]), pos: start},
+			)
+		default:
+			var matched bool
+			for _, op := range operators {
+				if strings.HasPrefix(string(runes[i:]), op) {
+					tokens =
+						append(tokens, token{kind: tokenOperator, text: op, pos: i})
+					i += len([]rune(op))
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				return nil, errors.Errorf("unexpected %q at position %d", r, i)
+			}
+		}
+	}
+	return append(tokens, token{kind: tokenEOF, pos: len(runes)}), nil
+}
+
+func isIdentifierRune(r rune) bool {
+	return r == '_' || r == '-' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokenEOF {
+		p.pos++
+	}
+	return tok
+}
+
+func (p *parser) isOperator(op string) bool {
+	tok := p.peek()
+	return tok.kind == tokenOperator && tok.text == op
+}
+
+func (p *parser) parseOr() (Expression, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.isOperator("||") {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &or{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (Expression, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.isOperator("&&") {
+		p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &and{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseUnary() (Expression, error) {
+	if p.isOperator("!") {
+		p.next()
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &not{operand: operand}, nil
+	}
+	if p.peek().kind == tokenLeftParen {
+		p.next()
+		e, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if tok := p.next(); tok.kind != tokenRightParen {
+			return nil, errors.Errorf("expected \")\" at position %d", tok.pos)
+		}
+		return e, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *parser) parseComparison() (Expression, error) {
+	left, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	tok := p.peek()
+	if tok.kind != tokenOperator {
+		return &truthy{operand: left}, nil
+	}
+	switch tok.text {
+	case "==", "!=":
+		p.next()
+		right, err := p.parseOperand()
+		if err != nil {
+			return nil, err
+		}
+		return &equals{left: left, right: right, negate: tok.text == "!="}, nil
+	case "=~", "!~":
+		p.next()
+		patternTok := p.next()
+		if patternTok.kind != tokenString {
+			return nil, errors.Errorf(
+				"expected string literal regular expression at position %d",
+				patternTok.pos,
+			)
+		}
+		regex, err := regexp.Compile(patternTok.text)
+		if err != nil {
+			return nil, errors.Wrapf(
+				err,
+				"invalid regular expression at position %d",
+				patternTok.pos,
+			)
+		}
+		return &matches{left: left, regex: regex, negate: tok.text == "!~"}, nil
+	}
+	return &truthy{operand: left}, nil
+}
+
+func (p *parser) parseOperand() (operand, error) {
+	tok := p.next()
+	switch tok.kind {
+	case tokenString, tokenNumber:
+		return &literal{val: tok.text}, nil
+	case tokenIdentifier:
+		if tok.text == "true" || tok.text == "false" {
+			return &literal{val: tok.text}, nil
+		}
+		path := []string{tok.text}
+		for {
+			switch p.peek().kind {
+			case tokenDot:
+				p.next()
+				nameTok := p.next()
+				if nameTok.kind != tokenIdentifier && nameTok.kind != tokenNumber {
+					return nil, errors.Errorf(
+						"expected name at position %d",
+						nameTok.pos,
+					)
+				}
+				path = append(path, nameTok.text)
+			case tokenLeftBracket:
+				p.next()
+				subscriptTok := p.next()
+				if subscriptTok.kind != tokenString &&
+					subscriptTok.kind != tokenNumber {
+					return nil, errors.Errorf(
+						"expected string or integer subscript at position %d",
+						subscriptTok.pos,
+					)
+				}
+				if closeTok := p.next(); closeTok.kind != tokenRightBracket {
+					return nil, errors.Errorf(
+						"expected \"]\" at position %d",
+						closeTok.pos,
+					)
+				}
+				path = append(path, subscriptTok.text)
+			default:
+				return &identifier{path: path}, nil
+			}
+		}
+	case tokenEOF:
+		return nil, errors.New("unexpected end of expression")
+	default:
+		return nil, errors.Errorf("unexpected %q at position %d", tok.text, tok.pos)
+	}
+}
+
+// operand is a value within an Expression-- either a literal or an identifier
+// whose value is resolved at evaluation time.
+type operand interface {
+	value(Resolver) (string, bool)
+}
+
+type literal struct {
+	val string
+}
+
+func (l *literal) value(Resolver) (string, bool) {
+	return l.val, true
+}
+
+type identifier struct {
+	path []string
+}
+
+func (i *identifier) value(resolve Resolver) (string, bool) {
+	return resolve(i.path)
+}
+
+type or struct {
+	left, right Expression
+}
+
+func (o *or) Evaluate(resolve Resolver) bool {
+	return o.left.Evaluate(resolve) || o.right.Evaluate(resolve)
+}
+
+type and struct {
+	left, right Expression
+}
+
+func (a *and) Evaluate(resolve Resolver) bool {
+	return a.left.Evaluate(resolve) && a.right.Evaluate(resolve)
+}
+
+type not struct {
+	operand Expression
+}
+
+func (n *not) Evaluate(resolve Resolver) bool {
+	return !n.operand.Evaluate(resolve)
+}
+
+type truthy struct {
+	operand operand
+}
+
+func (t *truthy) Evaluate(resolve Resolver) bool {
+	val, ok := t.operand.value(resolve)
+	return ok && val != "" && val != "false"
+}
+
+type equals struct {
+	left, right operand
+	negate      bool
+}
+
+func (e *equals) Evaluate(resolve Resolver) bool {
+	leftVal, leftOK := e.left.value(resolve)
+	rightVal, rightOK := e.right.value(resolve)
+	return (leftOK && rightOK && leftVal == rightVal) != e.negate
+}
+
+type matches struct {
+	left   operand
+	regex  *regexp.Regexp
+	negate bool
+}
+
+func (m *matches) Evaluate(resolve Resolver) bool {
+	val, ok := m.left.value(resolve)
+	return (ok && m.regex.MatchString(val)) != m.negate
+}
@@ -0,0 +1,142 @@
+package expressions
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	testCases := []struct {
+		name        string
+		expr        string
+		errContains string
+	}{
+		{
+			name:        "empty expression",
+			expr:        "",
+			errContains: "unexpected end of expression",
+		},
+		{
+			name:        "unterminated string literal",
+			expr:        `type == "push`,
+			errContains: "unterminated string literal",
+		},
+		{
+			name:        "unbalanced parentheses",
+			expr:        `(type == "push"`,
+			errContains: `expected ")"`,
+		},
+		{
+			name:        "trailing tokens",
+			expr:        `type == "push" "pull"`,
+			errContains: "unexpected",
+		},
+		{
+			name:        "unsupported character",
+			expr:        `type = "push"`,
+			errContains: "unexpected",
+		},
+		{
+			name:        "non-literal regular expression",
+			expr:        `type =~ source`,
+			errContains: "expected string literal regular expression",
+		},
+		{
+			name:        "invalid regular expression",
+			expr:        `type =~ "("`,
+			errContains: "invalid regular expression",
+		},
+		{
+			name:        "unterminated subscript",
+			expr:        `labels["foo"`,
+			errContains: `expected "]"`,
+		},
+		{
+			name: "numeric path segment",
+			expr: `payload.commits.0.id == "abc123"`,
+		},
+		{
+			name: "valid expression",
+			expr: `type == "push" && ` +
+				`(labels["brigade.sh/branch"] =~ "^release/.*" || !labels.draft)`,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			expr, err := Parse(testCase.expr)
+			if testCase.errContains != "" {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), testCase.errContains)
+				require.Nil(t, expr)
+				return
+			}
+			require.NoError(t, err)
+			require.NotNil(t, expr)
+		})
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	values := map[string]string{
+		"source":               "github.com/brigadecore/brigade-github-gateway",
+		"type":                 "push",
+		"labels.branch":        "release/v2.0",
+		"labels.draft":         "false",
+		"labels.brigade.sh/id": "42",
+		"payload.commits.0.id": "abc123",
+		"payload.count":        "3",
+	}
+	resolve := func(path []string) (string, bool) {
+		val, ok := values[strings.Join(path, ".")]
+		return val, ok
+	}
+	testCases := []struct {
+		expr     string
+		expected bool
+	}{
+		{expr: `type == "push"`, expected: true},
+		{expr: `type == "pull_request"`, expected: false},
+		{expr: `type != "pull_request"`, expected: true},
+		{expr: `"push" == type`, expected: true},
+		{expr: `labels.missing == ""`, expected: false},
+		{expr: `labels.missing != "foo"`, expected: true},
+		{expr: `labels.branch =~ "^release/"`, expected: true},
+		{expr: `labels.branch !~ "^release/"`, expected: false},
+		{expr: `labels.missing =~ ".*"`, expected: false},
+		{expr: `labels.missing !~ "foo"`, expected: true},
+		{expr: `labels.branch`, expected: true},
+		{expr: `labels.draft`, expected: false},
+		{expr: `labels.missing`, expected: false},
+		{expr: `!labels.draft`, expected: true},
+		{expr: `labels["brigade.sh/id"] == "42"`, expected: true},
+		{expr: `labels["brigade.sh/id"] == 42`, expected: true},
+		{expr: `payload.commits[0].id == "abc123"`, expected: true},
+		{expr: `payload.commits[1].id == "abc123"`, expected: false},
+		{expr: `payload.commits.0.id == "abc123"`, expected: true},
+		{expr: `payload.commits.1.id == "abc123"`, expected: false},
+		{expr: `payload.count == 3`, expected: true},
+		{expr: `type == "push" && labels.draft == false`, expected: true},
+		{expr: `type == "pull_request" || labels.draft == false`, expected: true},
+		{expr: `type == "pull_request" || labels.draft == true`, expected: false},
+		{
+			// && binds more tightly than ||
+			expr:     `type == "push" || type == "x" && type == "y"`,
+			expected: true,
+		},
+		{
+			expr:     `(type == "push" || type == "x") && type == "y"`,
+			expected: false,
+		},
+		{expr: `!(type == "push")`, expected: false},
+		{expr: `!!(type == "push")`, expected: true},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.expr, func(t *testing.T) {
+			expr, err := Parse(testCase.expr)
+			require.NoError(t, err)
+			require.Equal(t, testCase.expected, expr.Evaluate(resolve))
+		})
+	}
+}
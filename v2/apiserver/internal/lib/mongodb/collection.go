@@ -56,6 +56,15 @@ type Collection interface {
 		replacement interface{},
 		opts ...*options.FindOneAndReplaceOptions,
 	) *mongo.SingleResult
+	// FindOneAndUpdate executes a findAndModify command to update at most one
+	// document in the collection and returns the document as it appeared before
+	// updating, unless options specify otherwise.
+	FindOneAndUpdate(
+		ctx context.Context,
+		filter interface{},
+		update interface{},
+		opts ...*options.FindOneAndUpdateOptions,
+	) *mongo.SingleResult
 	// InsertOne executes an insert command to insert a single document into the
 	// collection.
 	InsertOne(
@@ -51,6 +51,13 @@ type MockCollection struct {
 		opts ...*options.FindOneAndReplaceOptions,
 	) *mongo.SingleResult
 
+	FindOneAndUpdateFn func(
+		ctx context.Context,
+		filter interface{},
+		update interface{},
+		opts ...*options.FindOneAndUpdateOptions,
+	) *mongo.SingleResult
+
 	InsertOneFn func(
 		ctx context.Context,
 		document interface{},
@@ -121,6 +128,15 @@ func (m *MockCollection) FindOneAndReplace(
 	return m.FindOneAndReplaceFn(ctx, filter, replacement, opts...)
 }
 
+func (m *MockCollection) FindOneAndUpdate(
+	ctx context.Context,
+	filter interface{},
+	update interface{},
+	opts ...*options.FindOneAndUpdateOptions,
+) *mongo.SingleResult {
+	return m.FindOneAndUpdateFn(ctx, filter, update, opts...)
+}
+
 func (m *MockCollection) InsertOne(
 	ctx context.Context,
 	document interface{},
@@ -166,7 +166,8 @@ func (w *writer) Write(
 	}
 	msg := &amqp.Message{
 		Header: &amqp.MessageHeader{
-			Durable: opts.Durable,
+			Durable:  opts.Durable,
+			Priority: opts.Priority,
 		},
 		Data: [][]byte{
 			[]byte(message),
@@ -172,6 +172,9 @@ func TestWriterWrite_MessageOpts(t *testing.T) {
 					if msg.Header.Durable != true {
 						return errors.New("message persistence not as expected")
 					}
+					if msg.Header.Priority != 5 {
+						return errors.New("message priority not as expected")
+					}
 					return nil
 				},
 			},
@@ -179,7 +182,10 @@ func TestWriterWrite_MessageOpts(t *testing.T) {
 		err := writer.Write(
 			context.Background(),
 			"message in a bottle",
-			&queue.MessageOptions{Durable: true},
+			&queue.MessageOptions{
+				Durable:  true,
+				Priority: 5,
+			},
 		)
 		require.NoError(t, err)
 	})
@@ -35,4 +35,8 @@ type MessageOptions struct {
 	// Durable indicates whether or not the message should be durable/persisted
 	// (true) or not durable/persisted (false, default)
 	Durable bool
+	// Priority indicates the relative urgency of the message. Messaging systems
+	// that support it will deliver messages of higher priority ahead of those
+	// of lower priority. The default is zero.
+	Priority uint8
 }
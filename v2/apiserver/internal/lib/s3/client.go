@@ -0,0 +1,288 @@
+// Package s3 implements a minimal client for Amazon S3 and S3-compatible object
+// storage services, such as MinIO. Rather than depending on the AWS SDK or the
+// MinIO client, this package implements only what Brigade needs: putting,
+// getting, listing, and deleting objects in a single bucket, with requests
+// signed using AWS Signature Version 4. Both SDKs are large, fast-moving
+// dependency trees, and either would have been used for no more than these four
+// operations. Signing is verified against the examples Amazon publishes for
+// S3, and the client against an in-memory stand-in for a real service, so
+// support for additional operations should be added here only as needed.
+package s3
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// ClientConfig encapsulates details required for connecting to a bucket in
+// Amazon S3 or in any S3-compatible object storage service, such as MinIO.
+type ClientConfig struct {
+	// Endpoint is the base URL of the object storage service. For Amazon S3, this
+	// is of the form https://s3.<region>.amazonaws.com.
+	Endpoint string
+	// Region is the region in which the bucket resides. Object storage services
+	// that are not region-aware typically accept any value.
+	Region string
+	// Bucket is the name of the bucket.
+	Bucket string
+	// AccessKeyID is the access key ID used to sign requests.
+	AccessKeyID string
+	// SecretAccessKey is the secret access key used to sign requests.
+	SecretAccessKey string
+	// PathStyle indicates whether the bucket should be addressed as part of each
+	// request's path instead of as part of its host name. This is required by
+	// many S3-compatible object storage services, including MinIO.
+	PathStyle bool
+}
+
+// Client is an interface for the subset of S3 operations that we actually use.
+// All keys are relative to the bucket specified by the ClientConfig used to
+// create the Client.
+type Client interface {
+	// PutObject stores the provided content under the specified key, replacing
+	// any object already stored under that key.
+	PutObject(ctx context.Context, key string, content []byte) error
+	// GetObject returns the content stored under the specified key. If no such
+	// object exists, an error satisfying IsNotFoundError is returned.
+	GetObject(ctx context.Context, key string) ([]byte, error)
+	// ListObjects returns, in lexical order, the keys of all objects whose keys
+	// begin with the specified prefix. If a delimiter is specified, keys
+	// containing the delimiter after the prefix are not returned. Instead, each
+	// distinct portion of such keys, up to and including the first occurrence of
+	// the delimiter, is returned as a common prefix.
+	ListObjects(
+		ctx context.Context,
+		prefix string,
+		delimiter string,
+	) (keys []string, commonPrefixes []string, err error)
+	// DeleteObject deletes the object stored under the specified key. Deleting
+	// an object that does not exist is not an error.
+	DeleteObject(ctx context.Context, key string) error
+}
+
+// client is a lightweight implementation of the Client interface that speaks
+// directly to the S3 REST API.
+type client struct {
+	config     ClientConfig
+	endpoint   *url.URL
+	httpClient *http.Client
+	nowFn      func() time.Time
+}
+
+// NewClient returns a lightweight implementation of the Client interface that
+// speaks directly to the S3 REST API of the object storage service specified
+// by the provided ClientConfig.
+func NewClient(config ClientConfig) (Client, error) {
+	endpoint, err := url.Parse(config.Endpoint)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error parsing endpoint %q", config.Endpoint)
+	}
+	if endpoint.Scheme == "" || endpoint.Host == "" {
+		return nil, errors.Errorf("endpoint %q is not an absolute URL", endpoint)
+	}
+	if config.Bucket == "" {
+		return nil, errors.New("no bucket was specified")
+	}
+	return &client{
+		config:     config,
+		endpoint:   endpoint,
+		httpClient: &http.Client{},
+		nowFn:      time.Now,
+	}, nil
+}
+
+func (c *client) PutObject(
+	ctx context.Context,
+	key string,
+	content []byte,
+) error {
+	resp, err := c.do(ctx, http.MethodPut, key, nil, content)
+	if err != nil {
+		return errors.Wrapf(err, "error putting object %q", key)
+	}
+	resp.Body.Close()
+	return nil
+}
+
+func (c *client) GetObject(ctx context.Context, key string) ([]byte, error) {
+	resp, err := c.do(ctx, http.MethodGet, key, nil, nil)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error getting object %q", key)
+	}
+	defer resp.Body.Close()
+	content, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error reading object %q", key)
+	}
+	return content, nil
+}
+
+// listBucketResult represents the response to a ListObjectsV2 request.
+type listBucketResult struct {
+	Contents []struct {
+		Key string `xml:"Key"`
+	} `xml:"Contents"`
+	CommonPrefixes []struct {
+		Prefix string `xml:"Prefix"`
+	} `xml:"CommonPrefixes"`
+	IsTruncated           bool   `xml:"IsTruncated"`
+	NextContinuationToken string `xml:"NextContinuationToken"`
+}
+
+func (c *client) ListObjects(
+	ctx context.Context,
+	prefix string,
+	delimiter string,
+) ([]string, []string, error) {
+	keys := []string{}
+	commonPrefixes := []string{}
+	query := map[string]string{
+		"list-type": "2",
+		"prefix":    prefix,
+	}
+	if delimiter != "" {
+		query["delimiter"] = delimiter
+	}
+	for {
+		resp, err := c.do(ctx, http.MethodGet, "", query, nil)
+		if err != nil {
+			return nil, nil,
+				errors.Wrapf(err, "error listing objects with prefix %q", prefix)
+		}
+		result := listBucketResult{}
+		err = xml.NewDecoder(resp.Body).Decode(&result)
+		resp.Body.Close()
+		if err != nil {
+			return nil, nil, errors.Wrapf(
+				err,
+				"error decoding list of objects with prefix %q",
+				prefix,
+			)
+		}
+		for _, content := range result.Contents {
+			keys = append(keys, content.Key)
+		}
+		for _, commonPrefix := range result.CommonPrefixes {
+			commonPrefixes = append(commonPrefixes, commonPrefix.Prefix)
+		}
+		if !result.IsTruncated {
+			return keys, commonPrefixes, nil
+		}
+		query["continuation-token"] = result.NextContinuationToken
+	}
+}
+
+func (c *client) DeleteObject(ctx context.Context, key string) error {
+	resp, err := c.do(ctx, http.MethodDelete, key, nil, nil)
+	if err != nil {
+		return errors.Wrapf(err, "error deleting object %q", key)
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// errorResponse represents the body of an unsuccessful response from the S3
+// REST API.
+type errorResponse struct {
+	Code    string `xml:"Code"`
+	Message string `xml:"Message"`
+}
+
+// notFoundError is returned when a requested object does not exist.
+type notFoundError struct {
+	key string
+}
+
+func (n *notFoundError) Error() string {
+	return fmt.Sprintf("object %q not found", n.key)
+}
+
+// IsNotFoundError returns a bool indicating whether the provided error
+// indicates that a requested object does not exist.
+func IsNotFoundError(err error) bool {
+	_, ok := errors.Cause(err).(*notFoundError)
+	return ok
+}
+
+// do sends a signed request to the S3 REST API and returns the response if it
+// was successful. The request is for the object stored under the specified key
+// or, if the key is empty, for the bucket itself. Callers are responsible for
+// closing the body of the response.
+func (c *client) do(
+	ctx context.Context,
+	method string,
+	key string,
+	query map[string]string,
+	body []byte,
+) (*http.Response, error) {
+	u := *c.endpoint
+	objectPath := strings.TrimSuffix(u.Path, "/")
+	if c.config.PathStyle {
+		objectPath = fmt.Sprintf("%s/%s", objectPath, c.config.Bucket)
+		if key != "" {
+			objectPath = fmt.Sprintf("%s/%s", objectPath, key)
+		}
+	} else {
+		u.Host = fmt.Sprintf("%s.%s", c.config.Bucket, u.Host)
+		objectPath = fmt.Sprintf("%s/%s", objectPath, key)
+	}
+	u.Path = objectPath
+	u.RawPath = uriEncode(objectPath, false)
+	values := url.Values{}
+	for name, value := range query {
+		values.Set(name, value)
+	}
+	u.RawQuery = canonicalQuery(&url.URL{RawQuery: values.Encode()})
+
+	req, err := http.NewRequestWithContext(
+		ctx,
+		method,
+		u.String(),
+		bytes.NewReader(body),
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "error creating request")
+	}
+	signRequest(
+		req,
+		hashHex(body),
+		c.config.AccessKeyID,
+		c.config.SecretAccessKey,
+		c.config.Region,
+		c.nowFn(),
+	)
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "error sending request")
+	}
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return resp, nil
+	}
+
+	defer resp.Body.Close()
+	errResp := errorResponse{}
+	// If the body can't be decoded, we'll settle for reporting the status code
+	_ = xml.NewDecoder(resp.Body).Decode(&errResp)
+	if resp.StatusCode == http.StatusNotFound && key != "" &&
+		(errResp.Code == "" || errResp.Code == "NoSuchKey") {
+		return nil, &notFoundError{key: key}
+	}
+	if errResp.Code == "" {
+		return nil, errors.Errorf("received %d from object storage", resp.StatusCode)
+	}
+	return nil, errors.Errorf(
+		"received %d from object storage: %s: %s",
+		resp.StatusCode,
+		errResp.Code,
+		errResp.Message,
+	)
+}
@@ -0,0 +1,188 @@
+package s3
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	s3Testing "github.com/brigadecore/brigade/v2/apiserver/internal/lib/s3/testing" // nolint: lll
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testBucket      = "brigade"
+	testAccessKeyID = "minioadmin"
+)
+
+func TestNewClient(t *testing.T) {
+	testCases := []struct {
+		name       string
+		config     ClientConfig
+		assertions func(Client, error)
+	}{
+		{
+			name: "endpoint is not an absolute URL",
+			config: ClientConfig{
+				Endpoint: "minio:9000",
+				Bucket:   testBucket,
+			},
+			assertions: func(_ Client, err error) {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), "not an absolute URL")
+			},
+		},
+		{
+			name: "no bucket specified",
+			config: ClientConfig{
+				Endpoint: "http://minio:9000",
+			},
+			assertions: func(_ Client, err error) {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), "no bucket")
+			},
+		},
+		{
+			name: "success",
+			config: ClientConfig{
+				Endpoint: "http://minio:9000",
+				Bucket:   testBucket,
+			},
+			assertions: func(c Client, err error) {
+				require.NoError(t, err)
+				require.IsType(t, &client{}, c)
+				require.Equal(t, "minio:9000", c.(*client).endpoint.Host)
+				require.NotNil(t, c.(*client).httpClient)
+				require.NotNil(t, c.(*client).nowFn)
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.assertions(NewClient(testCase.config))
+		})
+	}
+}
+
+func TestClient(t *testing.T) {
+	server := s3Testing.NewServer(testBucket, testAccessKeyID)
+	defer server.Close()
+	server.MaxKeys = 2
+	c, err := NewClient(
+		ClientConfig{
+			Endpoint:        server.URL,
+			Region:          "us-east-1",
+			Bucket:          testBucket,
+			AccessKeyID:     testAccessKeyID,
+			SecretAccessKey: "minioadmin",
+			PathStyle:       true,
+		},
+	)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	t.Run("put and get", func(t *testing.T) {
+		err := c.PutObject(ctx, "italian/job (1).log", []byte("foo"))
+		require.NoError(t, err)
+		content, err := c.GetObject(ctx, "italian/job (1).log")
+		require.NoError(t, err)
+		require.Equal(t, "foo", string(content))
+	})
+
+	t.Run("get nonexistent object", func(t *testing.T) {
+		_, err := c.GetObject(ctx, "nonexistent")
+		require.Error(t, err)
+		require.True(t, IsNotFoundError(err))
+	})
+
+	t.Run("list", func(t *testing.T) {
+		for _, key := range []string{
+			"list/a/1",
+			"list/a/2",
+			"list/b/1",
+			"list/c",
+			"list/d",
+			"list/e/1",
+		} {
+			require.NoError(t, c.PutObject(ctx, key, []byte(key)))
+		}
+		// Pages of two force several continuations
+		keys, commonPrefixes, err := c.ListObjects(ctx, "list/", "")
+		require.NoError(t, err)
+		require.Equal(
+			t,
+			[]string{
+				"list/a/1",
+				"list/a/2",
+				"list/b/1",
+				"list/c",
+				"list/d",
+				"list/e/1",
+			},
+			keys,
+		)
+		require.Empty(t, commonPrefixes)
+		keys, commonPrefixes, err = c.ListObjects(ctx, "list/", "/")
+		require.NoError(t, err)
+		require.Equal(t, []string{"list/c", "list/d"}, keys)
+		require.Equal(
+			t,
+			[]string{"list/a/", "list/b/", "list/e/"},
+			commonPrefixes,
+		)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		require.NoError(t, c.PutObject(ctx, "doomed", []byte("foo")))
+		require.NoError(t, c.DeleteObject(ctx, "doomed"))
+		_, err := c.GetObject(ctx, "doomed")
+		require.True(t, IsNotFoundError(err))
+		// Deleting again is not an error
+		require.NoError(t, c.DeleteObject(ctx, "doomed"))
+	})
+
+	t.Run("wrong credentials", func(t *testing.T) {
+		badClient, err := NewClient(
+			ClientConfig{
+				Endpoint:    server.URL,
+				Bucket:      testBucket,
+				AccessKeyID: "nobody",
+				PathStyle:   true,
+			},
+		)
+		require.NoError(t, err)
+		_, err = badClient.GetObject(ctx, "italian/job (1).log")
+		require.Error(t, err)
+		require.False(t, IsNotFoundError(err))
+		require.Contains(t, err.Error(), "AccessDenied")
+	})
+}
+
+func TestClientVirtualHostedStyle(t *testing.T) {
+	var requestedHost, requestedPath string
+	c, err := NewClient(
+		ClientConfig{
+			Endpoint: "https://s3.us-east-1.amazonaws.com",
+			Region:   "us-east-1",
+			Bucket:   testBucket,
+		},
+	)
+	require.NoError(t, err)
+	c.(*client).httpClient = &http.Client{
+		Transport: roundTripperFn(
+			func(req *http.Request) (*http.Response, error) {
+				requestedHost = req.URL.Host
+				requestedPath = req.URL.EscapedPath()
+				return nil, context.Canceled
+			},
+		),
+	}
+	_ = c.PutObject(context.Background(), "my project/foo", nil)
+	require.Equal(t, "brigade.s3.us-east-1.amazonaws.com", requestedHost)
+	require.Equal(t, "/my%20project/foo", requestedPath)
+}
+
+type roundTripperFn func(*http.Request) (*http.Response, error)
+
+func (r roundTripperFn) RoundTrip(req *http.Request) (*http.Response, error) {
+	return r(req)
+}
@@ -0,0 +1,171 @@
+package s3
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// signingAlgorithm identifies AWS Signature Version 4, which is understood
+	// by Amazon S3 and by every S3-compatible object storage service of note.
+	signingAlgorithm = "AWS4-HMAC-SHA256"
+	// amzDateFormat is the format of the timestamp included in every signed
+	// request.
+	amzDateFormat = "20060102T150405Z"
+	// amzDayFormat is the format of the date included in the scope of every
+	// request signature.
+	amzDayFormat = "20060102"
+)
+
+// signRequest signs the provided request using AWS Signature Version 4. The
+// request's X-Amz-Date and X-Amz-Content-Sha256 headers are set, after which
+// the request's host and every header the request carries are signed. Headers
+// added later, including any added by the HTTP transport, are not signed.
+func signRequest(
+	req *http.Request,
+	payloadHash string,
+	accessKeyID string,
+	secretAccessKey string,
+	region string,
+	now time.Time,
+) {
+	now = now.UTC()
+	amzDate := now.Format(amzDateFormat)
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	host := req.Host
+	if host == "" {
+		host = req.URL.Host
+	}
+	headers := map[string]string{
+		"host": host,
+	}
+	for name, values := range req.Header {
+		headers[strings.ToLower(name)] =
+			strings.TrimSpace(strings.Join(values, ","))
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	canonicalHeaders := &strings.Builder{}
+	for _, name := range names {
+		fmt.Fprintf(canonicalHeaders, "%s:%s\n", name, headers[name])
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join(
+		[]string{
+			req.Method,
+			uriEncode(req.URL.Path, false),
+			canonicalQuery(req.URL),
+			canonicalHeaders.String(),
+			signedHeaders,
+			payloadHash,
+		},
+		"\n",
+	)
+	scope := fmt.Sprintf(
+		"%s/%s/s3/aws4_request",
+		now.Format(amzDayFormat),
+		region,
+	)
+	stringToSign := strings.Join(
+		[]string{
+			signingAlgorithm,
+			amzDate,
+			scope,
+			hashHex([]byte(canonicalRequest)),
+		},
+		"\n",
+	)
+
+	signingKey := hmacSHA256(
+		[]byte(fmt.Sprintf("AWS4%s", secretAccessKey)),
+		now.Format(amzDayFormat),
+	)
+	for _, scopePart := range []string{region, "s3", "aws4_request"} {
+		signingKey = hmacSHA256(signingKey, scopePart)
+	}
+	signature := hex.EncodeToString(hmacSHA256(signingKey, stringToSign))
+
+	req.Header.Set(
+		"Authorization",
+		fmt.Sprintf(
+			"%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+			signingAlgorithm,
+			accessKeyID,
+			scope,
+			signedHeaders,
+			signature,
+		),
+	)
+}
+
+// canonicalQuery returns the provided URL's query string in the canonical form
+// required by AWS Signature Version 4-- sorted by parameter name, with names
+// and values both URI encoded.
+func canonicalQuery(u *url.URL) string {
+	query := u.Query()
+	names := make([]string, 0, len(query))
+	for name := range query {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	params := []string{}
+	for _, name := range names {
+		values := query[name]
+		sort.Strings(values)
+		for _, value := range values {
+			params = append(
+				params,
+				fmt.Sprintf("%s=%s", uriEncode(name, true), uriEncode(value, true)),
+			)
+		}
+	}
+	return strings.Join(params, "&")
+}
+
+// uriEncode encodes every byte of the provided string other than unreserved
+// characters and, unless encodeSlash is true, forward slashes. This differs
+// from the encoding performed by the net/url package, which leaves a number of
+// reserved characters unencoded.
+func uriEncode(s string, encodeSlash bool) string {
+	encoded := &strings.Builder{}
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if (c >= 'A' && c <= 'Z') ||
+			(c >= 'a' && c <= 'z') ||
+			(c >= '0' && c <= '9') ||
+			c == '-' || c == '_' || c == '.' || c == '~' ||
+			(c == '/' && !encodeSlash) {
+			encoded.WriteByte(c)
+			continue
+		}
+		fmt.Fprintf(encoded, "%%%02X", c)
+	}
+	return encoded.String()
+}
+
+// hashHex returns the hex-encoded SHA-256 hash of the provided bytes.
+func hashHex(b []byte) string {
+	hash := sha256.Sum256(b)
+	return hex.EncodeToString(hash[:])
+}
+
+// hmacSHA256 returns the HMAC-SHA256 of the provided data using the provided
+// key.
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data)) // nolint: errcheck
+	return mac.Sum(nil)
+}
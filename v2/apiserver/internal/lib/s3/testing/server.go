@@ -0,0 +1,261 @@
+package testing
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Server is an in-memory stand-in for a MinIO-style, S3-compatible object
+// storage service, suitable for use in tests. It hosts a single bucket,
+// supports only path-style addressing, and implements only the subset of the
+// S3 REST API used by the s3.Client interface. Requests are checked for the
+// expected access key ID and payload hash, but signatures are not verified.
+type Server struct {
+	*httptest.Server
+	// MaxKeys is the most keys and common prefixes returned per page when
+	// listing objects. If not set, it defaults to 1000.
+	MaxKeys     int
+	bucket      string
+	accessKeyID string
+	objects     map[string][]byte
+	objectsMu   sync.Mutex
+}
+
+// NewServer starts and returns a Server hosting the specified bucket and
+// accepting requests signed using the specified access key ID. Callers are
+// responsible for closing the Server.
+func NewServer(bucket string, accessKeyID string) *Server {
+	s := &Server{
+		bucket:      bucket,
+		accessKeyID: accessKeyID,
+		objects:     map[string][]byte{},
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// Objects returns a copy of all objects currently stored in the Server's
+// bucket, indexed by key.
+func (s *Server) Objects() map[string][]byte {
+	s.objectsMu.Lock()
+	defer s.objectsMu.Unlock()
+	objects := make(map[string][]byte, len(s.objects))
+	for key, content := range s.objects {
+		objects[key] = content
+	}
+	return objects
+}
+
+// PutObject stores the provided content under the specified key in the
+// Server's bucket.
+func (s *Server) PutObject(key string, content []byte) {
+	s.objectsMu.Lock()
+	defer s.objectsMu.Unlock()
+	s.objects[key] = content
+}
+
+type errorResponse struct {
+	XMLName xml.Name `xml:"Error"`
+	Code    string   `xml:"Code"`
+	Message string   `xml:"Message"`
+}
+
+type listBucketResult struct {
+	XMLName               xml.Name       `xml:"ListBucketResult"`
+	Name                  string         `xml:"Name"`
+	Prefix                string         `xml:"Prefix"`
+	Delimiter             string         `xml:"Delimiter,omitempty"`
+	KeyCount              int            `xml:"KeyCount"`
+	MaxKeys               int            `xml:"MaxKeys"`
+	IsTruncated           bool           `xml:"IsTruncated"`
+	Contents              []contents     `xml:"Contents"`
+	CommonPrefixes        []commonPrefix `xml:"CommonPrefixes"`
+	NextContinuationToken string         `xml:"NextContinuationToken,omitempty"`
+}
+
+type contents struct {
+	Key  string `xml:"Key"`
+	Size int    `xml:"Size"`
+}
+
+type commonPrefix struct {
+	Prefix string `xml:"Prefix"`
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	if !strings.HasPrefix(
+		r.Header.Get("Authorization"),
+		fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/", s.accessKeyID),
+	) {
+		writeError(w, http.StatusForbidden, "AccessDenied", "Access Denied.")
+		return
+	}
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "IncompleteBody", err.Error())
+		return
+	}
+	hash := sha256.Sum256(body)
+	if r.Header.Get("X-Amz-Content-Sha256") != hex.EncodeToString(hash[:]) {
+		writeError(
+			w,
+			http.StatusBadRequest,
+			"XAmzContentSHA256Mismatch",
+			"The provided 'x-amz-content-sha256' header does not match what was "+
+				"computed.",
+		)
+		return
+	}
+
+	bucketPath := fmt.Sprintf("/%s", s.bucket)
+	if r.URL.Path != bucketPath &&
+		!strings.HasPrefix(r.URL.Path, fmt.Sprintf("%s/", bucketPath)) {
+		writeError(
+			w,
+			http.StatusNotFound,
+			"NoSuchBucket",
+			"The specified bucket does not exist",
+		)
+		return
+	}
+	key := strings.TrimPrefix(
+		strings.TrimPrefix(r.URL.Path, bucketPath),
+		"/",
+	)
+
+	s.objectsMu.Lock()
+	defer s.objectsMu.Unlock()
+	switch {
+	case key == "" && r.Method == http.MethodGet:
+		s.listObjects(w, r)
+	case key == "":
+		writeError(
+			w,
+			http.StatusMethodNotAllowed,
+			"MethodNotAllowed",
+			"The specified method is not allowed against this resource.",
+		)
+	case r.Method == http.MethodGet:
+		content, ok := s.objects[key]
+		if !ok {
+			writeError(
+				w,
+				http.StatusNotFound,
+				"NoSuchKey",
+				"The specified key does not exist.",
+			)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write(content) // nolint: errcheck
+	case r.Method == http.MethodPut:
+		s.objects[key] = body
+		w.WriteHeader(http.StatusOK)
+	case r.Method == http.MethodDelete:
+		delete(s.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeError(
+			w,
+			http.StatusMethodNotAllowed,
+			"MethodNotAllowed",
+			"The specified method is not allowed against this resource.",
+		)
+	}
+}
+
+func (s *Server) listObjects(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	if query.Get("list-type") != "2" {
+		writeError(
+			w,
+			http.StatusNotImplemented,
+			"NotImplemented",
+			"Only ListObjectsV2 is implemented.",
+		)
+		return
+	}
+	prefix := query.Get("prefix")
+	delimiter := query.Get("delimiter")
+	// The continuation token is simply the last key accounted for by the
+	// previous page
+	continuationToken := query.Get("continuation-token")
+	maxKeys := s.MaxKeys
+	if maxKeys == 0 {
+		maxKeys = 1000
+	}
+
+	keys := []string{}
+	for key := range s.objects {
+		if strings.HasPrefix(key, prefix) && key > continuationToken {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	result := listBucketResult{
+		Name:      s.bucket,
+		Prefix:    prefix,
+		Delimiter: delimiter,
+		MaxKeys:   maxKeys,
+	}
+	seenPrefixes := map[string]struct{}{}
+	var lastKey string
+	for _, key := range keys {
+		var keyPrefix string
+		if delimiter != "" {
+			if i := strings.Index(key[len(prefix):], delimiter); i >= 0 {
+				keyPrefix = key[:len(prefix)+i+len(delimiter)]
+			}
+		}
+		if _, seen := seenPrefixes[keyPrefix]; seen && keyPrefix != "" {
+			lastKey = key
+			continue
+		}
+		if result.KeyCount == maxKeys {
+			result.IsTruncated = true
+			result.NextContinuationToken = lastKey
+			break
+		}
+		if keyPrefix != "" {
+			seenPrefixes[keyPrefix] = struct{}{}
+			result.CommonPrefixes = append(
+				result.CommonPrefixes,
+				commonPrefix{Prefix: keyPrefix},
+			)
+		} else {
+			result.Contents = append(
+				result.Contents,
+				contents{Key: key, Size: len(s.objects[key])},
+			)
+		}
+		result.KeyCount++
+		lastKey = key
+	}
+	writeXML(w, http.StatusOK, result)
+}
+
+func writeError(w http.ResponseWriter, statusCode int, code, message string) {
+	writeXML(w, statusCode, errorResponse{Code: code, Message: message})
+}
+
+func writeXML(w http.ResponseWriter, statusCode int, obj interface{}) {
+	bodyBytes, err := xml.Marshal(obj)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/xml")
+	w.Header().Set("Content-Length", strconv.Itoa(len(bodyBytes)))
+	w.WriteHeader(statusCode)
+	w.Write(bodyBytes) // nolint: errcheck
+}
@@ -4,6 +4,7 @@ package main
 import (
 	"context"
 	"log"
+	"os"
 	"time"
 
 	"github.com/brigadecore/brigade-foundations/retries"
@@ -54,8 +55,20 @@ func main() {
 		}
 	}
 
+	// When invoked with the migrate-logs argument, the API server moves logs out
+	// of MongoDB and exits instead of serving requests.
+	if len(os.Args) > 1 && os.Args[1] == migrateLogsCommand {
+		if err = migrateLogs(ctx, database); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	var artifactsStore api.ArtifactsStore
 	var coolLogsStore api.CoolLogsStore
+	var cronStore api.CronStore
 	var eventsStore api.EventsStore
+	var jobCacheStore api.JobCacheStore
 	var jobsStore api.JobsStore
 	var projectsStore api.ProjectsStore
 	var projectRoleAssignmentsStore api.ProjectRoleAssignmentsStore
@@ -65,13 +78,29 @@ func main() {
 	var sessionsStore api.SessionsStore
 	var usersStore api.UsersStore
 	var warmLogsStore api.LogsStore
+	var webhookDeliveriesStore api.WebhookDeliveriesStore
 	var workersStore api.WorkersStore
 	{
-		coolLogsStore = mongodb.NewLogsStore(database)
+		artifactsStore, err = newArtifactsStore(database)
+		if err != nil {
+			log.Fatal(err)
+		}
+		coolLogsStore, err = newCoolLogsStore(database)
+		if err != nil {
+			log.Fatal(err)
+		}
+		cronStore, err = mongodb.NewCronStore(database)
+		if err != nil {
+			log.Fatal(err)
+		}
 		eventsStore, err = mongodb.NewEventsStore(database)
 		if err != nil {
 			log.Fatal(err)
 		}
+		jobCacheStore, err = mongodb.NewJobCacheStore(database)
+		if err != nil {
+			log.Fatal(err)
+		}
 		jobsStore, err = mongodb.NewJobsStore(database)
 		if err != nil {
 			log.Fatal(err)
@@ -97,6 +126,10 @@ func main() {
 			log.Fatal(err)
 		}
 		warmLogsStore = apiKubernetes.NewLogsStore(kubeClient)
+		webhookDeliveriesStore, err = mongodb.NewWebhookDeliveriesStore(database)
+		if err != nil {
+			log.Fatal(err)
+		}
 		workersStore, err = mongodb.NewWorkersStore(database)
 		if err != nil {
 			log.Fatal(err)
@@ -141,23 +174,125 @@ func main() {
 	authorizer := api.NewAuthorizer(roleAssignmentsStore)
 	projectAuthorizer := api.NewProjectAuthorizer(projectRoleAssignmentsStore)
 
+	// Artifacts service
+	var artifactsService api.ArtifactsService
+	{
+		config, err := artifactsServiceConfig()
+		if err != nil {
+			log.Fatal(err)
+		}
+		artifactsService = api.NewArtifactsService(
+			authorizer.Authorize,
+			projectAuthorizer.Authorize,
+			eventsStore,
+			artifactsStore,
+			config,
+		)
+	}
+
+	// Notifier
+	notifier := api.NewWebhookNotifier(projectsStore, webhookDeliveriesStore)
+
 	// Events service
-	eventsService := api.NewEventsService(
-		authorizer.Authorize,
-		projectAuthorizer.Authorize,
-		projectsStore,
-		eventsStore,
-		coolLogsStore,
-		substrate,
-	)
+	var eventsService api.EventsService
+	{
+		config, err := eventsServiceConfig()
+		if err != nil {
+			log.Fatal(err)
+		}
+		eventsService = api.NewEventsService(
+			authorizer.Authorize,
+			projectAuthorizer.Authorize,
+			projectsStore,
+			eventsStore,
+			coolLogsStore,
+			artifactsStore,
+			substrate,
+			notifier,
+			config,
+		)
+	}
+
+	// Cron service
+	var cronService api.CronService
+	{
+		config, err := cronServiceConfig()
+		if err != nil {
+			log.Fatal(err)
+		}
+		cronService = api.NewCronService(
+			projectsStore,
+			cronStore,
+			eventsService.Create,
+			config,
+		)
+	}
+
+	// Retention service
+	var retentionService api.RetentionService
+	{
+		config, err := retentionServiceConfig()
+		if err != nil {
+			log.Fatal(err)
+		}
+		retentionService = api.NewRetentionService(
+			projectsStore,
+			eventsStore,
+			coolLogsStore,
+			artifactsStore,
+			substrate,
+			config,
+		)
+	}
+
+	// Webhook delivery service
+	var webhookDeliveryService api.WebhookDeliveryService
+	{
+		config, err := webhookDeliveryServiceConfig()
+		if err != nil {
+			log.Fatal(err)
+		}
+		webhookDeliveryService = api.NewWebhookDeliveryService(
+			projectsStore,
+			secretsStore,
+			webhookDeliveriesStore,
+			config,
+		)
+	}
+
+	// Job retry service
+	var jobRetryService api.JobRetryService
+	{
+		config, err := jobRetryServiceConfig()
+		if err != nil {
+			log.Fatal(err)
+		}
+		jobRetryService = api.NewJobRetryService(
+			projectsStore,
+			jobsStore,
+			substrate,
+			config,
+		)
+	}
 
 	// Jobs service
 	jobsService := api.NewJobsService(
 		authorizer.Authorize,
+		projectAuthorizer.Authorize,
 		projectsStore,
 		eventsStore,
 		jobsStore,
+		jobCacheStore,
+		secretsStore,
 		substrate,
+		notifier,
+	)
+
+	// Job cache service
+	jobCacheService := api.NewJobCacheService(
+		projectAuthorizer.Authorize,
+		projectsStore,
+		jobCacheStore,
 	)
 
 	// Logs service
@@ -180,7 +315,10 @@ func main() {
 		projectsStore,
 		eventsStore,
 		coolLogsStore,
+		artifactsStore,
 		projectRoleAssignmentsStore,
+		cronStore,
+		jobCacheStore,
 		substrate,
 	)
 
@@ -251,6 +389,13 @@ func main() {
 		usersServiceConfig(),
 	)
 
+	// Webhooks service
+	webhooksService := api.NewWebhooksService(
+		authorizer.Authorize,
+		projectsStore,
+		webhookDeliveriesStore,
+	)
+
 	// Workers service
 	workersService := api.NewWorkersService(
 		authorizer.Authorize,
@@ -258,6 +403,7 @@ func main() {
 		eventsStore,
 		workersStore,
 		substrate,
+		notifier,
 	)
 
 	// Server
@@ -279,6 +425,10 @@ func main() {
 		}
 		apiServer = restmachinery.NewServer(
 			[]restmachinery.Endpoints{
+				&rest.ArtifactsEndpoints{
+					AuthFilter: authFilter,
+					Service:    artifactsService,
+				},
 				&rest.AuthnEndpoints{
 					AuthFilter: authFilter,
 					Service:    principalsService,
@@ -296,6 +446,10 @@ func main() {
 					),
 					Service: eventsService,
 				},
+				&rest.JobCacheEndpoints{
+					AuthFilter: authFilter,
+					Service:    jobCacheService,
+				},
 				&rest.JobsEndpoints{
 					AuthFilter: authFilter,
 					JobSchemaLoader: gojsonschema.NewReferenceLoader(
@@ -304,6 +458,9 @@ func main() {
 					JobStatusSchemaLoader: gojsonschema.NewReferenceLoader(
 						"file:///brigade/schemas/job-status.json",
 					),
+					JobOutputsSchemaLoader: gojsonschema.NewReferenceLoader(
+						"file:///brigade/schemas/job-outputs.json",
+					),
 					Service: jobsService,
 				},
 				&rest.LogsEndpoints{
@@ -357,6 +514,10 @@ func main() {
 					AuthFilter: authFilter,
 					Service:    usersService,
 				},
+				&rest.WebhooksEndpoints{
+					AuthFilter: authFilter,
+					Service:    webhooksService,
+				},
 				&rest.WorkersEndpoints{
 					AuthFilter: authFilter,
 					WorkerStatusSchemaLoader: gojsonschema.NewReferenceLoader(
@@ -375,6 +536,10 @@ func main() {
 	}
 
 	// Run it!
+	go cronService.Run(ctx)
+	go retentionService.Run(ctx)
+	go webhookDeliveryService.Run(ctx)
+	go jobRetryService.Run(ctx)
 	log.Println(apiServer.ListenAndServe(ctx))
 }
 
@@ -0,0 +1,27 @@
+package main
+
+import (
+	"context"
+	"log"
+
+	"github.com/brigadecore/brigade/v2/apiserver/internal/api/mongodb"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// migrateLogsCommand is the argument that causes the API server to move logs
+// from MongoDB to the configured cool logs store and then exit instead of
+// serving requests.
+const migrateLogsCommand = "migrate-logs"
+
+// migrateLogs moves all logs stored in MongoDB to the cool logs store specified
+// by configuration obtained from environment variables.
+func migrateLogs(ctx context.Context, database *mongo.Database) error {
+	writer, err := newCoolLogsWriter()
+	if err != nil {
+		return err
+	}
+	log.Println("Migrating logs from MongoDB")
+	migrated, err := mongodb.MigrateLogs(ctx, database, writer)
+	log.Printf("Migrated %d lines of logs from MongoDB", migrated)
+	return err
+}
@@ -6,6 +6,14 @@ import { core } from "@brigadecore/brigade-sdk"
 
 import { logger } from "./logger"
 
+// JobStatus amends core.JobStatus with the outputs reported by the job.
+type JobStatus = core.JobStatus & { outputs?: { [key: string]: string } }
+
+// jobCreations tracks the creation of every job run by this worker. A job can
+// only depend on jobs that already exist, so the creation of a job waits for
+// the creation of any of its dependencies that is still in progress.
+const jobCreations: { [jobName: string]: Promise<void> } = {}
+
 export class Job extends BrigadierJob {
   logger: Logger
 
@@ -16,6 +24,16 @@ export class Job extends BrigadierJob {
 
   async run(): Promise<void> {
     this.logger.info(`Creating job ${this.name}`)
+    const creation = this.create()
+    jobCreations[this.name] = creation.catch(() => undefined)
+    await creation
+    return this.wait()
+  }
+
+  private async create(): Promise<void> {
+    await Promise.all(
+      this.dependsOn.map(dependency => jobCreations[dependency])
+    )
     try {
       const jobsClient = new core.JobsClient(
         this.event.worker.apiAddress,
@@ -30,14 +48,16 @@ export class Job extends BrigadierJob {
           sidecarContainers: this.sidecarContainers,
           timeoutDuration: this.timeoutSeconds + "s",
           host: this.host,
-          fallible: this.fallible
+          fallible: this.fallible,
+          retryPolicy: this.retryPolicy,
+          dependsOn: this.dependsOn,
+          cache: this.cache
         }
       }
       await jobsClient.create(this.event.id, sdkJob)
     } catch (e) {
       throw new Error(`Error creating job "${this.name}": ${e.message}`)
     }
-    return this.wait()
   }
 
   private async wait(): Promise<void> {
@@ -49,8 +69,9 @@ export class Job extends BrigadierJob {
       )
 
       const statusStream = jobsClient.watchStatus(this.event.id, this.name)
-      statusStream.onData((status: core.JobStatus) => {
+      statusStream.onData((status: JobStatus) => {
         this.logger.debug(`Current job phase is ${status.phase}`)
+        this.outputs = status.outputs || {}
         if (!this.fallible) {
           switch (status.phase) {
             case core.JobPhase.Aborted:
@@ -33,6 +33,8 @@ describe("jobs", () => {
         assert.deepEqual(job.sidecarContainers, {})
         assert.equal(job.timeoutSeconds, 60 * 15)
         assert.deepEqual(job.host, new JobHost())
+        assert.deepEqual(job.dependsOn, [])
+        assert.deepEqual(job.outputs, {})
         assert.isDefined(job.logger)
       })
     })
@@ -1,6 +1,19 @@
 export { Event, EventHandler, EventRegistry, events } from "./events"
 export { ConcurrentGroup, SerialGroup } from "./groups"
-export { Container, ImagePullPolicy, Job, JobHost } from "./jobs"
+export {
+  Container,
+  ContainerResources,
+  ImagePullPolicy,
+  Job,
+  JobCachePolicy,
+  JobHost,
+  JobRetryPolicy,
+  NodeAffinity,
+  NodeSelectorRequirement,
+  ResourceQuantities,
+  Toleration,
+  Volume
+} from "./jobs"
 export { Logger, logger } from "./logger"
 export { Project } from "./projects"
 export { Runnable } from "./runnables"
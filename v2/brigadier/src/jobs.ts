@@ -47,6 +47,33 @@ export class Job implements Runnable {
    */
   public fallible = false
 
+  /**
+   * Specifies whether and how Brigade should automatically attempt the job
+   * again if it does not succeed. If not set, the job is attempted only once.
+   */
+  public retryPolicy?: JobRetryPolicy
+
+  /**
+   * The names of other jobs, created earlier by the same event, that must
+   * succeed before Brigade will start this job. If any of them does not
+   * succeed, this job is canceled without ever being started.
+   */
+  public dependsOn: string[] = []
+
+  /**
+   * Specifies whether and how Brigade may reuse the results of an earlier,
+   * equivalent job from another event for the same project instead of running
+   * this job. If not set, the job is always run.
+   */
+  public cache?: JobCachePolicy
+
+  /**
+   * Key/value pairs reported by the job itself via the Brigade API. These are
+   * populated once the job has run and can be used to pass values, such as the
+   * digest of a built image, to subsequent jobs.
+   */
+  public outputs: { [key: string]: string } = {}
+
   /** The event that triggered the job. */
   protected event: Event
 
@@ -263,6 +290,28 @@ export class Container {
    * For more details, see https://github.com/brigadecore/brigade/issues/1666
    */
   // public useHostDockerSocket = false
+  /**
+   * The compute resources the container requires and the most it may consume.
+   * Where these are unspecified, Brigade project configuration may supply
+   * defaults. A job whose containers request or are limited to more than
+   * Brigade project configuration permits is rejected.
+   */
+  public resources?: ContainerResources
+  /**
+   * Additional volumes to mount into the container's file system, such as
+   * selected project secrets, config files, scratch space, or caches that
+   * persist across events.
+   *
+   * The types of volumes that may be mounted are subject to Brigade project
+   * configuration. A job whose containers mount volumes of any other type is
+   * rejected.
+   *
+   * @example
+   * job.primaryContainer.volumes = [
+   *   { mountPath: "/root/.npm", cache: { name: "npm" } }
+   * ]
+   */
+  public volumes: Volume[] = []
 
   /**
    * Constructs a new Container.
@@ -273,6 +322,140 @@ export class Container {
   }
 }
 
+/**
+ * An additional volume to be mounted into a Container's file system. Exactly
+ * one of secrets, configFiles, tmpfs, or cache must be specified.
+ */
+export interface Volume {
+  /**
+   * The path in the container's file system where the volume should be
+   * mounted.
+   */
+  mountPath: string
+  /**
+   * Whether the volume should be mounted read-only. Volumes containing secrets
+   * or config files are always mounted read-only.
+   */
+  readOnly?: boolean
+  /**
+   * Selected project secrets, each represented by a file named after its key.
+   */
+  secrets?: {
+    /** The keys of the project secrets to include in the volume. */
+    keys: string[]
+  }
+  /**
+   * Files whose content is specified inline. Like the values of environment
+   * variables, file contents are treated as secrets.
+   */
+  configFiles?: {
+    /** A map of file names to file contents. */
+    files: { [fileName: string]: string }
+  }
+  /**
+   * Memory-backed scratch space.
+   */
+  tmpfs?: {
+    /** The most memory the volume may consume, e.g. "64Mi". */
+    sizeLimit?: string
+  }
+  /**
+   * A named, project-wide volume whose contents persist across events. Jobs
+   * that mount a cache volume having the same name share the same underlying
+   * storage.
+   */
+  cache?: {
+    /** The cache volume's name. */
+    name: string
+    /**
+     * The size of the cache volume, e.g. "5Gi". This is only honored when the
+     * cache volume is first used. Defaults to "1G".
+     */
+    size?: string
+  }
+}
+
+/**
+ * The compute resources a Container requires and the most it may consume.
+ */
+export interface ContainerResources {
+  /**
+   * Compute resources that must be available on a substrate node for the
+   * container to be hosted there.
+   */
+  requests?: ResourceQuantities
+  /**
+   * The most compute resources the container may consume.
+   */
+  limits?: ResourceQuantities
+}
+
+/**
+ * Amounts of compute resources.
+ */
+export interface ResourceQuantities {
+  /**
+   * An amount of CPU, expressed in cores (e.g. "2" or "0.5") or millicores
+   * (e.g. "500m").
+   */
+  cpu?: string
+  /**
+   * An amount of memory, expressed in bytes or with a suffix such as "Mi" or
+   * "Gi" (e.g. "512Mi").
+   */
+  memory?: string
+}
+
+/**
+ * Specifies whether and how a Job should be automatically attempted again if it
+ * does not succeed.
+ */
+export interface JobRetryPolicy {
+  /**
+   * How many times, in total, the Job may be attempted. Must be at least 1.
+   */
+  maxAttempts: number
+  /**
+   * How long to wait after the first unsuccessful attempt before attempting the
+   * Job again, expressed as a duration string such as "30s" or "1m30s". The
+   * wait doubles after every subsequent unsuccessful attempt. If not set, the
+   * Job is attempted again right away.
+   */
+  backoffDuration?: string
+  /**
+   * The phases in which an attempt may conclude and still be followed by
+   * another. Valid values are "FAILED" and "TIMED_OUT". If not set, both are
+   * retryable.
+   */
+  retryablePhases?: string[]
+  /**
+   * Exit codes of the primary container. If set, a failed attempt is only
+   * followed by another if the primary container exited with one of these
+   * codes. Attempts that timed out are unaffected by this setting.
+   */
+  retryableExitCodes?: number[]
+}
+
+/**
+ * Specifies whether and how the results of a Job may be reused by equivalent
+ * Jobs of subsequent events for the same project.
+ */
+export interface JobCachePolicy {
+  /**
+   * An arbitrary value that, along with the Job's name and specification,
+   * identifies the Job's results. If not set, the git commit the event's worker
+   * is checked out at is used instead. If neither is available, the Job's
+   * results are not cached.
+   */
+  key?: string
+  /**
+   * How long the Job's results remain eligible for reuse, expressed as a
+   * duration string such as "24h". Must not exceed "720h". If not set, this
+   * defaults to "168h" (seven days).
+   */
+  ttlDuration?: string
+}
+
 /**
  * The execution environment required by a Job.
  */
@@ -288,4 +471,83 @@ export class JobHost {
    * such as specific hardware like an SSD or GPU.
    */
   public nodeSelector: { [key: string]: string } = {}
+  /**
+   * Permit the Job to be hosted on substrate nodes having matching taints.
+   * Note the tolerations a Job may specify are subject to project-level
+   * configuration.
+   */
+  public tolerations: Toleration[] = []
+  /**
+   * Requirements, beyond nodeSelector, that a substrate node must or should
+   * satisfy to host the Job. Note whether a Job may specify node affinity is
+   * subject to project-level configuration.
+   */
+  public nodeAffinity?: NodeAffinity
+  /**
+   * The substrate's priority class for the Job. Note the priority classes a
+   * Job may use are subject to project-level configuration.
+   */
+  public priorityClassName?: string
+}
+
+/**
+ * Permits a Job to be hosted on substrate nodes having a matching taint.
+ */
+export interface Toleration {
+  /**
+   * The taint key the toleration applies to. An empty key with the "Exists"
+   * operator matches all taints.
+   */
+  key?: string
+  /**
+   * How the toleration's value is compared to that of a taint. Defaults to
+   * "Equal".
+   */
+  operator?: "Equal" | "Exists"
+  /**
+   * The taint value the toleration matches. Must be empty if the operator is
+   * "Exists".
+   */
+  value?: string
+  /**
+   * The taint effect the toleration matches. When empty, the toleration
+   * matches all taint effects.
+   */
+  effect?: "NoSchedule" | "PreferNoSchedule" | "NoExecute"
+}
+
+/**
+ * A requirement placed on the value of a substrate node's label.
+ */
+export interface NodeSelectorRequirement {
+  /** The label key the requirement applies to. */
+  key: string
+  /**
+   * How the label's value is compared to values. Anti-affinity is expressed
+   * using the "NotIn" and "DoesNotExist" operators.
+   */
+  operator: "In" | "NotIn" | "Exists" | "DoesNotExist" | "Gt" | "Lt"
+  /**
+   * Must be non-empty if the operator is "In" or "NotIn", must be empty if the
+   * operator is "Exists" or "DoesNotExist", and must contain exactly one
+   * integer if the operator is "Gt" or "Lt".
+   */
+  values?: string[]
+}
+
+/**
+ * Requirements, beyond simple node selectors, that a substrate node must or
+ * should satisfy to host a Job.
+ */
+export interface NodeAffinity {
+  /**
+   * Requirements that a substrate node must satisfy, in their entirety, to
+   * host the Job.
+   */
+  required?: NodeSelectorRequirement[]
+  /**
+   * Requirements that substrate nodes should preferably satisfy to host the
+   * Job. Each is weighted, from 1 to 100, relative to the others.
+   */
+  preferred?: (NodeSelectorRequirement & { weight: number })[]
 }
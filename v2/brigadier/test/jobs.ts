@@ -33,6 +33,8 @@ describe("jobs", () => {
         assert.deepEqual(job.sidecarContainers, {})
         assert.equal(job.timeoutSeconds, 60 * 15)
         assert.deepEqual(job.host, new JobHost())
+        assert.deepEqual(job.dependsOn, [])
+        assert.deepEqual(job.outputs, {})
       })
     })
   })
@@ -49,6 +51,7 @@ describe("jobs", () => {
         assert.isEmpty(container.workspaceMountPath)
         assert.isEmpty(container.sourceMountPath)
         assert.isFalse(container.privileged)
+        assert.deepEqual(container.volumes, [])
         // assert.isFalse(container.useHostDockerSocket)
       })
     })
@@ -61,6 +64,9 @@ describe("jobs", () => {
         assert.isUndefined(jobHost.os)
         assert.isDefined(jobHost.nodeSelector)
         assert.equal(Object.keys(jobHost.nodeSelector).length, 0)
+        assert.deepEqual(jobHost.tolerations, [])
+        assert.isUndefined(jobHost.nodeAffinity)
+        assert.isUndefined(jobHost.priorityClassName)
       })
     })
   })
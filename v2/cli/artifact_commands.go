@@ -0,0 +1,192 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ghodss/yaml"
+	"github.com/gosuri/uitable"
+	"github.com/pkg/errors"
+	"github.com/urfave/cli/v2"
+	"k8s.io/apimachinery/pkg/util/duration"
+)
+
+var artifactsCommand = &cli.Command{
+	Name:    "artifact",
+	Aliases: []string{"artifacts"},
+	Usage:   "Manage job artifacts",
+	Subcommands: []*cli.Command{
+		{
+			Name:  "get",
+			Usage: "Download an artifact",
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:    flagFile,
+					Aliases: []string{"f"},
+					Usage: "Write the artifact to the specified file; if not set, " +
+						"writes to a file of the same name as the artifact in the " +
+						"current directory; use - to write to stdout",
+				},
+				&cli.StringFlag{
+					Name:     flagID,
+					Aliases:  []string{"i", flagEvent, "e"},
+					Usage:    "Download an artifact from the specified event (required)",
+					Required: true,
+				},
+				&cli.StringFlag{
+					Name:     flagJob,
+					Aliases:  []string{"j"},
+					Usage:    "Download an artifact from the specified job (required)",
+					Required: true,
+				},
+				&cli.StringFlag{
+					Name:     flagName,
+					Aliases:  []string{"n"},
+					Usage:    "Download the specified artifact (required)",
+					Required: true,
+				},
+			},
+			Action: artifactGet,
+		},
+		{
+			Name:    "list",
+			Aliases: []string{"ls"},
+			Usage:   "List a job's artifacts",
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:     flagID,
+					Aliases:  []string{"i", flagEvent, "e"},
+					Usage:    "List artifacts from the specified event (required)",
+					Required: true,
+				},
+				&cli.StringFlag{
+					Name:     flagJob,
+					Aliases:  []string{"j"},
+					Usage:    "List artifacts from the specified job (required)",
+					Required: true,
+				},
+				cliFlagOutput,
+			},
+			Action: artifactList,
+		},
+	},
+}
+
+func artifactList(c *cli.Context) error {
+	eventID := c.String(flagID)
+	jobName := c.String(flagJob)
+	output := c.String(flagOutput)
+
+	if err := validateOutputFormat(output); err != nil {
+		return err
+	}
+
+	client, err := getClient(false)
+	if err != nil {
+		return err
+	}
+
+	artifacts, err := client.Core().Events().Workers().Jobs().Artifacts().List(
+		c.Context,
+		eventID,
+		jobName,
+		nil,
+	)
+	if err != nil {
+		return err
+	}
+
+	switch strings.ToLower(output) {
+	case flagOutputTable:
+		if len(artifacts.Items) == 0 {
+			fmt.Println("No artifacts found.")
+			return nil
+		}
+		table := uitable.New()
+		table.AddRow("NAME", "SIZE", "AGE")
+		for _, artifact := range artifacts.Items {
+			var age string
+			if artifact.Created != nil {
+				age = duration.ShortHumanDuration(time.Since(*artifact.Created))
+			}
+			table.AddRow(artifact.Name, artifact.Size, age)
+		}
+		fmt.Println(table)
+
+	case flagOutputYAML:
+		yamlBytes, err := yaml.Marshal(artifacts)
+		if err != nil {
+			return errors.Wrap(
+				err,
+				"error formatting output from list artifacts operation",
+			)
+		}
+		fmt.Println(string(yamlBytes))
+
+	case flagOutputJSON:
+		prettyJSON, err := json.MarshalIndent(artifacts, "", "  ")
+		if err != nil {
+			return errors.Wrap(
+				err,
+				"error formatting output from list artifacts operation",
+			)
+		}
+		fmt.Println(string(prettyJSON))
+	}
+
+	return nil
+}
+
+func artifactGet(c *cli.Context) error {
+	eventID := c.String(flagID)
+	jobName := c.String(flagJob)
+	artifactName := c.String(flagName)
+	filename := c.String(flagFile)
+	if filename == "" {
+		filename = artifactName
+	}
+
+	client, err := getClient(false)
+	if err != nil {
+		return err
+	}
+
+	content, err := client.Core().Events().Workers().Jobs().Artifacts().Download(
+		c.Context,
+		eventID,
+		jobName,
+		artifactName,
+		nil,
+	)
+	if err != nil {
+		return err
+	}
+	defer content.Close()
+
+	if filename == "-" {
+		_, err = io.Copy(os.Stdout, content)
+		return errors.Wrapf(err, "error writing artifact %q", artifactName)
+	}
+
+	file, err := os.Create(filename)
+	if err != nil {
+		return errors.Wrapf(err, "error creating file %s", filename)
+	}
+	defer file.Close()
+	if _, err = io.Copy(file, content); err != nil {
+		return errors.Wrapf(
+			err,
+			"error writing artifact %q to %s",
+			artifactName,
+			filename,
+		)
+	}
+
+	fmt.Printf("Artifact %q written to %s.\n", artifactName, filename)
+
+	return nil
+}
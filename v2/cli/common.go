@@ -3,6 +3,7 @@ package main
 import (
 	"fmt"
 	"os"
+	"strconv"
 	"strings"
 
 	"github.com/AlecAivazis/survey/v2"
@@ -89,3 +90,12 @@ func shouldContinue(
 	}
 	return shouldContinue, nil
 }
+
+// formatExitCode formats an optional exit code for display, returning an empty
+// string if no exit code is available.
+func formatExitCode(exitCode *int32) string {
+	if exitCode == nil {
+		return ""
+	}
+	return strconv.Itoa(int(*exitCode))
+}
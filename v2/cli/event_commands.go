@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
+	"sort"
 	"strings"
 	"time"
 
@@ -22,6 +23,7 @@ var eventCommand = &cli.Command{
 	Aliases: []string{"events"},
 	Usage:   "Manage events",
 	Subcommands: []*cli.Command{
+		artifactsCommand,
 		{
 			Name:  "cancel",
 			Usage: "Cancel a single event without deleting it",
@@ -126,6 +128,11 @@ var eventCommand = &cli.Command{
 					Usage:    "Create an event for the specified project (required)",
 					Required: true,
 				},
+				&cli.IntFlag{
+					Name: flagPriority,
+					Usage: "The event's priority, from 0 (default) to 9; capped by " +
+						"the project's maximum event priority",
+				},
 				&cli.StringFlag{
 					Name:    flagSource,
 					Aliases: []string{"s"},
@@ -355,6 +362,41 @@ var eventCommand = &cli.Command{
 			},
 			Action: eventList,
 		},
+		{
+			Name:  "match",
+			Usage: "Determine which projects would receive an event",
+			Description: "Without creating the event described by the specified " +
+				"file, determines which projects would receive it and, for each " +
+				"project that would not, which event subscription criteria the " +
+				"event failed to satisfy",
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:    flagFile,
+					Aliases: []string{"f"},
+					Usage: "A YAML or JSON file that describes the event " +
+						"(required)",
+					Required:  true,
+					TakesFile: true,
+				},
+				cliFlagOutput,
+			},
+			Action: eventMatch,
+		},
+		{
+			Name:  "pin",
+			Usage: "Pin an event",
+			Description: "Exempts an event from deletion by any event retention " +
+				"policy",
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:     flagID,
+					Aliases:  []string{"i", flagEvent, "e"},
+					Usage:    "Pin the specified event (required)",
+					Required: true,
+				},
+			},
+			Action: eventPin,
+		},
 		{
 			Name:  "retry",
 			Usage: "Retry an event",
@@ -380,6 +422,21 @@ var eventCommand = &cli.Command{
 			},
 			Action: eventRetry,
 		},
+		{
+			Name:  "unpin",
+			Usage: "Unpin an event",
+			Description: "Makes a pinned event subject to deletion by event " +
+				"retention policies again",
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:     flagID,
+					Aliases:  []string{"i", flagEvent, "e"},
+					Usage:    "Unpin the specified event (required)",
+					Required: true,
+				},
+			},
+			Action: eventUnpin,
+		},
 		logsCommand,
 	},
 }
@@ -388,6 +445,7 @@ func eventCreate(c *cli.Context) error {
 	follow := c.Bool(flagFollow)
 	payload := c.String(flagPayload)
 	payloadFile := c.String(flagPayloadFile)
+	priority := c.Int(flagPriority)
 	projectID := c.String(flagProject)
 	source := c.String(flagSource)
 	eventType := c.String(flagType)
@@ -423,6 +481,7 @@ func eventCreate(c *cli.Context) error {
 		ProjectID: projectID,
 		Source:    source,
 		Type:      eventType,
+		Priority:  priority,
 		Payload:   payload,
 	}
 
@@ -576,13 +635,22 @@ func eventList(c *cli.Context) error {
 		switch strings.ToLower(output) {
 		case flagOutputTable:
 			table := uitable.New()
-			table.AddRow("ID", "PROJECT", "SOURCE", "TYPE", "AGE", "WORKER PHASE")
+			table.AddRow(
+				"ID",
+				"PROJECT",
+				"SOURCE",
+				"TYPE",
+				"PRIORITY",
+				"AGE",
+				"WORKER PHASE",
+			)
 			for _, event := range events.Items {
 				table.AddRow(
 					event.ID,
 					event.ProjectID,
 					event.Source,
 					event.Type,
+					event.Priority,
 					duration.ShortHumanDuration(time.Since(*event.Created)),
 					event.Worker.Status.Phase,
 				)
@@ -648,7 +716,15 @@ func eventGet(c *cli.Context) error {
 	switch strings.ToLower(output) {
 	case flagOutputTable:
 		table := uitable.New()
-		table.AddRow("ID", "PROJECT", "SOURCE", "TYPE", "AGE", "WORKER PHASE")
+		table.AddRow(
+			"ID",
+			"PROJECT",
+			"SOURCE",
+			"TYPE",
+			"PRIORITY",
+			"AGE",
+			"WORKER PHASE",
+		)
 		var age string
 		if event.Created != nil {
 			age = duration.ShortHumanDuration(time.Since(*event.Created))
@@ -658,15 +734,39 @@ func eventGet(c *cli.Context) error {
 			event.ProjectID,
 			event.Source,
 			event.Type,
+			event.Priority,
 			age,
 			event.Worker.Status.Phase,
 		)
 		fmt.Println(table)
 
+		// Only bother displaying the Worker's exit code and reason if there's
+		// something noteworthy to display
+		if workerStatus := event.Worker.Status; workerStatus.Reason != "" ||
+			(workerStatus.ExitCode != nil && *workerStatus.ExitCode != 0) {
+			fmt.Println()
+			table = uitable.New()
+			table.AddRow("WORKER EXIT CODE", "REASON", "MESSAGE")
+			table.AddRow(
+				formatExitCode(workerStatus.ExitCode),
+				workerStatus.Reason,
+				workerStatus.Message,
+			)
+			fmt.Println(table)
+		}
+
 		if len(event.Worker.Jobs) > 0 {
 			fmt.Printf("\nEvent %q jobs:\n\n", event.ID)
 			table = uitable.New()
-			table.AddRow("NAME", "STARTED", "ENDED", "PHASE")
+			table.AddRow(
+				"NAME",
+				"STARTED",
+				"ENDED",
+				"PHASE",
+				"EXIT CODE",
+				"REASON",
+				"DEPENDS ON",
+			)
 			for _, job := range event.Worker.Jobs {
 				jobStatus := job.Status
 				var started, ended string
@@ -683,11 +783,19 @@ func eventGet(c *cli.Context) error {
 					started,
 					ended,
 					jobStatus.Phase,
+					formatExitCode(jobStatus.ExitCode),
+					jobStatus.Reason,
+					strings.Join(job.Spec.DependsOn, ", "),
 				)
 			}
 			fmt.Println(table)
 		}
 
+		if outputsTable := jobOutputsTable(event.Worker.Jobs); outputsTable != nil {
+			fmt.Printf("\nEvent %q job outputs:\n\n", event.ID)
+			fmt.Println(outputsTable)
+		}
+
 	case flagOutputYAML:
 		yamlBytes, err := yaml.Marshal(event)
 		if err != nil {
@@ -712,6 +820,30 @@ func eventGet(c *cli.Context) error {
 	return nil
 }
 
+// jobOutputsTable returns a table of the outputs reported by the provided Jobs,
+// or nil if none of them reported any.
+func jobOutputsTable(jobs []sdk.Job) *uitable.Table {
+	var table *uitable.Table
+	for _, job := range jobs {
+		if job.Status == nil || len(job.Status.Outputs) == 0 {
+			continue
+		}
+		if table == nil {
+			table = uitable.New()
+			table.AddRow("JOB", "KEY", "VALUE")
+		}
+		keys := make([]string, 0, len(job.Status.Outputs))
+		for key := range job.Status.Outputs {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			table.AddRow(job.Name, key, job.Status.Outputs[key])
+		}
+	}
+	return table
+}
+
 func eventCancel(c *cli.Context) error {
 	id := c.String(flagID)
 
@@ -914,6 +1046,135 @@ func eventClone(c *cli.Context) error {
 	)
 }
 
+func eventMatch(c *cli.Context) error {
+	filename := c.String(flagFile)
+	output := c.String(flagOutput)
+
+	if err := validateOutputFormat(output); err != nil {
+		return err
+	}
+
+	// Read and parse the file
+	eventBytes, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return errors.Wrapf(err, "error reading event file %s", filename)
+	}
+
+	if strings.HasSuffix(filename, ".yaml") ||
+		strings.HasSuffix(filename, ".yml") {
+		if eventBytes, err = yaml.YAMLToJSON(eventBytes); err != nil {
+			return errors.Wrapf(err, "error converting file %s to JSON", filename)
+		}
+	}
+
+	event := sdk.Event{}
+	if err = json.Unmarshal(eventBytes, &event); err != nil {
+		return errors.Wrapf(err, "error unmarshaling event file %s", filename)
+	}
+
+	client, err := getClient(false)
+	if err != nil {
+		return err
+	}
+
+	result, err := client.Core().Events().Match(c.Context, event, nil)
+	if err != nil {
+		return err
+	}
+
+	switch strings.ToLower(output) {
+	case flagOutputTable:
+		if len(result.Subscribers) == 0 {
+			fmt.Println("No projects would receive this event.")
+		} else {
+			table := uitable.New()
+			table.AddRow("SUBSCRIBED PROJECT")
+			for _, projectID := range result.Subscribers {
+				table.AddRow(projectID)
+			}
+			fmt.Println(table)
+		}
+
+		if len(result.NonSubscribers) > 0 {
+			fmt.Print("\nProjects that would not receive this event:\n\n")
+			table := uitable.New()
+			table.Wrap = true
+			table.AddRow("PROJECT", "SUBSCRIPTION", "CRITERION", "REASON")
+			for _, project := range result.NonSubscribers {
+				if len(project.Subscriptions) == 0 {
+					table.AddRow(project.ProjectID, "", "", "no event subscriptions")
+					continue
+				}
+				for _, subscription := range project.Subscriptions {
+					for _, mismatch := range subscription.Mismatches {
+						table.AddRow(
+							project.ProjectID,
+							fmt.Sprintf("%d (%s)", subscription.Index, subscription.Source),
+							mismatch.Criterion,
+							mismatch.Reason,
+						)
+					}
+				}
+			}
+			fmt.Println(table)
+		}
+
+	case flagOutputYAML:
+		yamlBytes, err := yaml.Marshal(result)
+		if err != nil {
+			return errors.Wrap(
+				err,
+				"error formatting output from match event operation",
+			)
+		}
+		fmt.Println(string(yamlBytes))
+
+	case flagOutputJSON:
+		prettyJSON, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return errors.Wrap(
+				err,
+				"error formatting output from match event operation",
+			)
+		}
+		fmt.Println(string(prettyJSON))
+	}
+
+	return nil
+}
+
+func eventPin(c *cli.Context) error {
+	id := c.String(flagID)
+
+	client, err := getClient(false)
+	if err != nil {
+		return err
+	}
+
+	if err = client.Core().Events().Pin(c.Context, id, nil); err != nil {
+		return err
+	}
+	fmt.Printf("Event %q pinned.\n", id)
+
+	return nil
+}
+
+func eventUnpin(c *cli.Context) error {
+	id := c.String(flagID)
+
+	client, err := getClient(false)
+	if err != nil {
+		return err
+	}
+
+	if err = client.Core().Events().Unpin(c.Context, id, nil); err != nil {
+		return err
+	}
+	fmt.Printf("Event %q unpinned.\n", id)
+
+	return nil
+}
+
 func eventRetry(c *cli.Context) error {
 	id := c.String(flagID)
 	follow := c.Bool(flagFollow)
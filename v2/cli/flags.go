@@ -4,48 +4,62 @@ import "github.com/urfave/cli/v2"
 
 const (
 	flagAborted        = "aborted"
+	flagAll            = "all"
 	flagAnyPhase       = "any-phase"
+	flagAttempt        = "attempt"
 	flagBrowse         = "browse"
 	flagCanceled       = "canceled"
 	flagClient         = "client"
 	flagContainer      = "container"
+	flagContext        = "context"
 	flagContinue       = "continue"
 	flagCreate         = "create"
 	flagDescription    = "description"
 	flagEvent          = "event"
+	flagEvents         = "events"
 	flagFailed         = "failed"
 	flagFile           = "file"
 	flagFollow         = "follow"
 	flagGit            = "git"
 	flagID             = "id"
+	flagIgnoreCase     = "ignore-case"
 	flagInsecure       = "insecure"
 	flagJob            = "job"
 	flagLabel          = "label"
 	flagLanguage       = "language"
+	flagLimit          = "limit"
+	flagName           = "name"
 	flagNonInteractive = "non-interactive"
 	flagNonTerminal    = "non-terminal"
 	flagOutput         = "output"
 	flagPassword       = "password"
+	flagPattern        = "pattern"
 	flagPayload        = "payload"
 	flagPayloadFile    = "payload-file"
 	flagPending        = "pending"
+	flagPriority       = "priority"
 	flagProject        = "project"
 	flagQualifier      = "qualifier"
+	flagRegex          = "regex"
 	flagRole           = "role"
 	flagRoot           = "root"
 	flagRunning        = "running"
 	flagServer         = "server"
 	flagServiceAccount = "service-account"
 	flagSet            = "set"
+	flagSince          = "since"
 	flagSource         = "source"
 	flagStarting       = "starting"
 	flagSucceeded      = "succeeded"
+	flagTail           = "tail"
 	flagTerminal       = "terminal"
 	flagTimedOut       = "timedout"
 	flagType           = "type"
 	flagUnknown        = "unknown"
 	flagUnset          = "unset"
+	flagUntil          = "until"
 	flagUser           = "user"
+	flagWebhook        = "webhook"
 	flagYes            = "yes"
 )
 
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/urfave/cli/v2"
+)
+
+var jobCacheCommand = &cli.Command{
+	Name:  "cache",
+	Usage: "Manage cached job results",
+	Subcommands: []*cli.Command{
+		{
+			Name:  "purge",
+			Usage: "Purge a project's cached job results",
+			Description: "Discards all of the project's cached job results; " +
+				"subsequent cacheable jobs will run even if equivalent jobs have " +
+				"succeeded before",
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:     flagID,
+					Aliases:  []string{"i", flagProject, "p"},
+					Usage:    "Purge the specified project's job cache (required)",
+					Required: true,
+				},
+				nonInteractiveFlag,
+				&cli.BoolFlag{
+					Name:    flagYes,
+					Aliases: []string{"y"},
+					Usage:   "Non-interactively confirm purge",
+				},
+			},
+			Action: jobCachePurge,
+		},
+	},
+}
+
+func jobCachePurge(c *cli.Context) error {
+	projectID := c.String(flagID)
+
+	confirmed, err := confirmed(c)
+	if err != nil {
+		return err
+	}
+	if !confirmed {
+		return nil
+	}
+
+	client, err := getClient(false)
+	if err != nil {
+		return err
+	}
+
+	if err = client.Core().Projects().JobCache().Purge(
+		c.Context,
+		projectID,
+		nil,
+	); err != nil {
+		return err
+	}
+	fmt.Printf("Project %q job cache purged.\n", projectID)
+
+	return nil
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/urfave/cli/v2"
+)
+
+var jobCommand = &cli.Command{
+	Name:    "job",
+	Aliases: []string{"jobs"},
+	Usage:   "Manage jobs",
+	Subcommands: []*cli.Command{
+		{
+			Name:  "abort",
+			Usage: "Abort a single job",
+			Description: "Unconditionally aborts a single job that is in a " +
+				"non-terminal phase; the event's worker and other jobs are " +
+				"unaffected",
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:     flagID,
+					Aliases:  []string{"i", flagEvent, "e"},
+					Usage:    "Abort a job belonging to the specified event (required)",
+					Required: true,
+				},
+				&cli.StringFlag{
+					Name:     flagJob,
+					Aliases:  []string{"j"},
+					Usage:    "Abort the specified job (required)",
+					Required: true,
+				},
+				nonInteractiveFlag,
+				&cli.BoolFlag{
+					Name:    flagYes,
+					Aliases: []string{"y"},
+					Usage:   "Non-interactively confirm abortion",
+				},
+			},
+			Action: jobAbort,
+		},
+	},
+}
+
+func jobAbort(c *cli.Context) error {
+	eventID := c.String(flagID)
+	jobName := c.String(flagJob)
+
+	confirmed, err := confirmed(c)
+	if err != nil {
+		return err
+	}
+	if !confirmed {
+		return nil
+	}
+
+	client, err := getClient(false)
+	if err != nil {
+		return err
+	}
+
+	if err = client.Core().Events().Workers().Jobs().Abort(
+		c.Context,
+		eventID,
+		jobName,
+		nil,
+	); err != nil {
+		return err
+	}
+	fmt.Printf("Event %q job %q aborted.\n", eventID, jobName)
+
+	return nil
+}
@@ -3,9 +3,15 @@ package main
 import (
 	"context"
 	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
 
 	"github.com/brigadecore/brigade/sdk/v3"
+	"github.com/pkg/errors"
 	"github.com/urfave/cli/v2"
+	"golang.org/x/crypto/ssh/terminal"
 )
 
 var logsCommand = &cli.Command{
@@ -13,6 +19,19 @@ var logsCommand = &cli.Command{
 	Aliases: []string{"logs"},
 	Usage:   "View worker or job logs",
 	Flags: []cli.Flag{
+		&cli.BoolFlag{
+			Name: flagAll,
+			Usage: "View logs from the worker and from every container of every " +
+				"job, interleaved and prefixed with the job and container each " +
+				"line belongs to; mutually exclusive with --job, --container, and " +
+				"--attempt",
+		},
+		&cli.IntFlag{
+			Name:    flagAttempt,
+			Aliases: []string{"a"},
+			Usage: "View logs from the specified attempt at the job; if not set, " +
+				"displays logs from the job's current or most recent attempt",
+		},
 		&cli.StringFlag{
 			Name:    flagContainer,
 			Aliases: []string{"c"},
@@ -20,10 +39,9 @@ var logsCommand = &cli.Command{
 				"logs from the worker or job's \"primary\" container",
 		},
 		&cli.StringFlag{
-			Name:     flagID,
-			Aliases:  []string{"i", flagEvent, "e"},
-			Usage:    "View logs from the specified event",
-			Required: true,
+			Name:    flagID,
+			Aliases: []string{"i", flagEvent, "e"},
+			Usage:   "View logs from the specified event (required)",
 		},
 		&cli.BoolFlag{
 			Name:    flagFollow,
@@ -36,20 +54,142 @@ var logsCommand = &cli.Command{
 			Usage: "View logs from the specified job; if not set, displays " +
 				"worker logs",
 		},
+		&cli.StringFlag{
+			Name: flagSince,
+			Usage: "View only logs written since the specified time, expressed " +
+				"as an RFC3339 timestamp or as a duration relative to now " +
+				"(e.g. 10m or 2h)",
+		},
+		&cli.Int64Flag{
+			Name:    flagTail,
+			Aliases: []string{"t"},
+			Usage: "Begin by displaying only the specified number of most " +
+				"recent lines of logs",
+		},
+		&cli.StringFlag{
+			Name: flagUntil,
+			Usage: "View only logs written until the specified time, expressed " +
+				"as an RFC3339 timestamp or as a duration relative to now " +
+				"(e.g. 10m or 2h)",
+		},
 	},
 	Action: logs,
+	Subcommands: []*cli.Command{
+		{
+			Name:  "download",
+			Usage: "Download an archive of all of an event's logs",
+			Description: "Downloads a gzipped tarball containing logs from every " +
+				"container of an event's worker and jobs, along with a manifest " +
+				"describing them",
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:    flagFile,
+					Aliases: []string{"f"},
+					Usage: "Write the archive to the specified file; if not set, " +
+						"writes to <event ID>-logs.tar.gz in the current directory; use " +
+						"- to write to stdout",
+				},
+				&cli.StringFlag{
+					Name:     flagID,
+					Aliases:  []string{"i", flagEvent, "e"},
+					Usage:    "Download logs from the specified event",
+					Required: true,
+				},
+			},
+			Action: logDownload,
+		},
+		{
+			Name:  "search",
+			Usage: "Search worker and job logs",
+			Description: "Searches the logs of the specified event or of the " +
+				"specified project's most recent events",
+			Flags: []cli.Flag{
+				&cli.IntFlag{
+					Name:    flagContext,
+					Aliases: []string{"C"},
+					Usage: "Display the specified number of lines of logs before and " +
+						"after each matching line",
+				},
+				&cli.IntFlag{
+					Name: flagEvents,
+					Usage: "Search the logs of the specified number of the project's " +
+						"most recent events; only applies when searching a project",
+				},
+				&cli.StringFlag{
+					Name:    flagID,
+					Aliases: []string{"i", flagEvent, "e"},
+					Usage: "Search logs from the specified event; mutually exclusive " +
+						"with --project",
+				},
+				&cli.BoolFlag{
+					Name: flagIgnoreCase,
+					Usage: "If set, the pattern will be matched without regard to " +
+						"case",
+				},
+				&cli.IntFlag{
+					Name: flagLimit,
+					Usage: "Display at most the specified number of matching lines " +
+						"of logs",
+				},
+				&cli.StringFlag{
+					Name:     flagPattern,
+					Usage:    "Search for lines of logs containing the specified text",
+					Required: true,
+				},
+				&cli.StringFlag{
+					Name:    flagProject,
+					Aliases: []string{"p"},
+					Usage: "Search logs from the specified project's most recent " +
+						"events; mutually exclusive with --id",
+				},
+				&cli.BoolFlag{
+					Name:    flagRegex,
+					Aliases: []string{"r"},
+					Usage: "If set, the pattern will be treated as a regular " +
+						"expression",
+				},
+			},
+			Action: logSearch,
+		},
+	},
 }
 
 func logs(c *cli.Context) error {
 	eventID := c.String(flagID)
 	follow := c.Bool(flagFollow)
 
+	// The --id flag cannot be marked as required because that would also
+	// require it to be set when using the search subcommand.
+	if eventID == "" {
+		return errors.Errorf("required flag %q not set", flagID)
+	}
+
 	selector := &sdk.LogsSelector{
 		Job:       c.String(flagJob),
 		Container: c.String(flagContainer),
+		Attempt:   c.Int(flagAttempt),
+		All:       c.Bool(flagAll),
+	}
+	if selector.All &&
+		(selector.Job != "" || selector.Container != "" || selector.Attempt != 0) {
+		return errors.Errorf(
+			"--%s is mutually exclusive with --%s, --%s, and --%s",
+			flagAll,
+			flagJob,
+			flagContainer,
+			flagAttempt,
+		)
 	}
 	opts := &sdk.LogStreamOptions{
-		Follow: follow,
+		Follow:    follow,
+		TailLines: c.Int64(flagTail),
+	}
+	var err error
+	if opts.Since, err = parseLogTime(c.String(flagSince)); err != nil {
+		return errors.Wrapf(err, "error parsing value of --%s", flagSince)
+	}
+	if opts.Until, err = parseLogTime(c.String(flagUntil)); err != nil {
+		return errors.Wrapf(err, "error parsing value of --%s", flagUntil)
 	}
 
 	client, err := getClient(false)
@@ -66,6 +206,153 @@ func logs(c *cli.Context) error {
 	)
 }
 
+func logDownload(c *cli.Context) error {
+	eventID := c.String(flagID)
+	filename := c.String(flagFile)
+	if filename == "" {
+		filename = fmt.Sprintf("%s-logs.tar.gz", eventID)
+	}
+
+	client, err := getClient(false)
+	if err != nil {
+		return err
+	}
+
+	content, err := client.Core().Events().Logs().DownloadArchive(
+		c.Context,
+		eventID,
+		nil,
+	)
+	if err != nil {
+		return err
+	}
+	defer content.Close()
+
+	if filename == "-" {
+		_, err = io.Copy(os.Stdout, content)
+		return errors.Wrapf(err, "error writing event %q logs", eventID)
+	}
+
+	file, err := os.Create(filename)
+	if err != nil {
+		return errors.Wrapf(err, "error creating file %s", filename)
+	}
+	defer file.Close()
+	if _, err = io.Copy(file, content); err != nil {
+		return errors.Wrapf(
+			err,
+			"error writing event %q logs to %s",
+			eventID,
+			filename,
+		)
+	}
+
+	fmt.Printf("Event %q logs written to %s.\n", eventID, filename)
+
+	return nil
+}
+
+func logSearch(c *cli.Context) error {
+	eventID := c.String(flagID)
+	projectID := c.String(flagProject)
+
+	if (eventID == "") == (projectID == "") {
+		return errors.Errorf(
+			"exactly one of --%s or --%s must be set",
+			flagID,
+			flagProject,
+		)
+	}
+
+	criteria := sdk.LogSearchCriteria{
+		Pattern:    c.String(flagPattern),
+		Regex:      c.Bool(flagRegex),
+		IgnoreCase: c.Bool(flagIgnoreCase),
+	}
+	opts := &sdk.LogSearchOptions{
+		ContextLines: c.Int(flagContext),
+		Limit:        c.Int(flagLimit),
+		Events:       c.Int(flagEvents),
+	}
+
+	client, err := getClient(false)
+	if err != nil {
+		return err
+	}
+
+	logsClient := client.Core().Events().Logs()
+	var matches sdk.LogSearchMatchList
+	if eventID != "" {
+		matches, err = logsClient.Search(c.Context, eventID, criteria, opts)
+	} else {
+		matches, err =
+			logsClient.SearchProject(c.Context, projectID, criteria, opts)
+	}
+	if err != nil {
+		return err
+	}
+
+	if len(matches.Items) == 0 {
+		fmt.Println("No matching logs found.")
+		return nil
+	}
+
+	for i, match := range matches.Items {
+		if i > 0 && opts.ContextLines > 0 {
+			fmt.Println("--")
+		}
+		fmt.Print(formatLogSearchMatch(match))
+	}
+	return nil
+}
+
+// formatLogSearchMatch formats the provided LogSearchMatch for display,
+// prefixing each line of logs with the location it was found in. Matching
+// lines are distinguished from surrounding lines by a colon following the
+// location, where surrounding lines have a hyphen.
+func formatLogSearchMatch(match sdk.LogSearchMatch) string {
+	location := match.EventID
+	if match.Job == "" {
+		location = fmt.Sprintf("%s/worker", location)
+	} else {
+		location = fmt.Sprintf("%s/%s", location, match.Job)
+		if match.Attempt > 1 {
+			location = fmt.Sprintf("%s#%d", location, match.Attempt)
+		}
+	}
+	location = fmt.Sprintf("%s/%s", location, match.Container)
+	sb := strings.Builder{}
+	for _, logEntry := range match.Before {
+		sb.WriteString(fmt.Sprintf("%s- %s\n", location, logEntry.Message))
+	}
+	sb.WriteString(fmt.Sprintf("%s: %s\n", location, match.LogEntry.Message))
+	for _, logEntry := range match.After {
+		sb.WriteString(fmt.Sprintf("%s- %s\n", location, logEntry.Message))
+	}
+	return sb.String()
+}
+
+// parseLogTime parses the provided value, expressed either as an RFC3339
+// timestamp or as a duration relative to now, into a time. It returns nil if
+// the provided value is empty.
+func parseLogTime(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	if duration, err := time.ParseDuration(value); err == nil {
+		t := time.Now().Add(-duration)
+		return &t, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, errors.Errorf(
+			"%q is neither an RFC3339 timestamp nor a duration",
+			value,
+		)
+	}
+	return &t, nil
+}
+
 func streamLogs(
 	ctx context.Context,
 	logsClient sdk.LogsClient,
@@ -77,11 +364,12 @@ func streamLogs(
 	if err != nil {
 		return err
 	}
+	prefixer := newLogPrefixer(terminal.IsTerminal(int(os.Stdout.Fd())))
 	for {
 		select {
 		case logEntry, ok := <-logEntryCh:
 			if ok {
-				fmt.Println(logEntry.Message)
+				fmt.Printf("%s%s\n", prefixer.prefix(logEntry), logEntry.Message)
 			} else {
 				// logEntryCh was closed, but want to keep looping through this select
 				// in case there are pending errors on the errCh still. nil channels are
@@ -105,3 +393,54 @@ func streamLogs(
 		}
 	}
 }
+
+// logPrefixColors are the ANSI colors used, in turn, to distinguish lines of
+// logs belonging to different containers.
+var logPrefixColors = []int{36, 32, 33, 35, 34, 96, 92, 93, 95, 94}
+
+// logPrefixer formats prefixes identifying the job and container that lines
+// of logs belong to when logs from all of an event's containers are streamed
+// together.
+type logPrefixer struct {
+	colorize bool
+	colors   map[string]int
+}
+
+// newLogPrefixer returns a logPrefixer. If colorize is true, each distinct
+// prefix is rendered in its own color.
+func newLogPrefixer(colorize bool) *logPrefixer {
+	return &logPrefixer{
+		colorize: colorize,
+		colors:   map[string]int{},
+	}
+}
+
+// prefix returns a prefix identifying the job and container the provided
+// line of logs belongs to. It returns an empty string if the line is not
+// tagged with a container.
+func (l *logPrefixer) prefix(logEntry sdk.LogEntry) string {
+	if logEntry.Container == "" {
+		return ""
+	}
+	var source string
+	if logEntry.Job == "" {
+		source = "worker"
+		if logEntry.Container != source {
+			source = fmt.Sprintf("%s/%s", source, logEntry.Container)
+		}
+	} else {
+		source = logEntry.Job
+		if logEntry.Container != logEntry.Job {
+			source = fmt.Sprintf("%s/%s", source, logEntry.Container)
+		}
+	}
+	if !l.colorize {
+		return fmt.Sprintf("[%s] ", source)
+	}
+	color, ok := l.colors[source]
+	if !ok {
+		color = logPrefixColors[len(l.colors)%len(logPrefixColors)]
+		l.colors[source] = color
+	}
+	return fmt.Sprintf("\x1b[%dm[%s]\x1b[0m ", color, source)
+}
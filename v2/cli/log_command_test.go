@@ -0,0 +1,179 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/brigadecore/brigade/sdk/v3"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLogTime(t *testing.T) {
+	testCases := []struct {
+		name       string
+		value      string
+		assertions func(*time.Time, error)
+	}{
+		{
+			name:  "empty value",
+			value: "",
+			assertions: func(logTime *time.Time, err error) {
+				require.NoError(t, err)
+				require.Nil(t, logTime)
+			},
+		},
+		{
+			name:  "duration",
+			value: "1h",
+			assertions: func(logTime *time.Time, err error) {
+				require.NoError(t, err)
+				require.NotNil(t, logTime)
+				require.WithinDuration(
+					t,
+					time.Now().Add(-time.Hour),
+					*logTime,
+					time.Minute,
+				)
+			},
+		},
+		{
+			name:  "timestamp",
+			value: "2021-12-01T12:00:00Z",
+			assertions: func(logTime *time.Time, err error) {
+				require.NoError(t, err)
+				require.NotNil(t, logTime)
+				require.Equal(
+					t,
+					time.Date(2021, time.December, 1, 12, 0, 0, 0, time.UTC),
+					*logTime,
+				)
+			},
+		},
+		{
+			name:  "invalid value",
+			value: "yesterday",
+			assertions: func(_ *time.Time, err error) {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), "neither an RFC3339 timestamp")
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.assertions(parseLogTime(testCase.value))
+		})
+	}
+}
+
+func TestFormatLogSearchMatch(t *testing.T) {
+	testCases := []struct {
+		name     string
+		match    sdk.LogSearchMatch
+		expected string
+	}{
+		{
+			name: "worker logs",
+			match: sdk.LogSearchMatch{
+				EventID:   "123456789",
+				Container: "worker",
+				LogEntry: sdk.LogEntry{
+					Message: "error!",
+				},
+			},
+			expected: "123456789/worker/worker: error!\n",
+		},
+		{
+			name: "job logs with context",
+			match: sdk.LogSearchMatch{
+				EventID:   "123456789",
+				Job:       "italian",
+				Attempt:   2,
+				Container: "helper",
+				LogEntry: sdk.LogEntry{
+					Message: "error!",
+				},
+				Before: []sdk.LogEntry{
+					{
+						Message: "before",
+					},
+				},
+				After: []sdk.LogEntry{
+					{
+						Message: "after",
+					},
+				},
+			},
+			expected: "123456789/italian#2/helper- before\n" +
+				"123456789/italian#2/helper: error!\n" +
+				"123456789/italian#2/helper- after\n",
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			require.Equal(
+				t,
+				testCase.expected,
+				formatLogSearchMatch(testCase.match),
+			)
+		})
+	}
+}
+
+func TestLogPrefixer(t *testing.T) {
+	testCases := []struct {
+		name     string
+		logEntry sdk.LogEntry
+		expected string
+	}{
+		{
+			name:     "untagged",
+			logEntry: sdk.LogEntry{},
+			expected: "",
+		},
+		{
+			name: "worker container",
+			logEntry: sdk.LogEntry{
+				Container: "worker",
+			},
+			expected: "[worker] ",
+		},
+		{
+			name: "worker vcs container",
+			logEntry: sdk.LogEntry{
+				Container: "vcs",
+			},
+			expected: "[worker/vcs] ",
+		},
+		{
+			name: "job primary container",
+			logEntry: sdk.LogEntry{
+				Job:       "italian",
+				Container: "italian",
+			},
+			expected: "[italian] ",
+		},
+		{
+			name: "job sidecar container",
+			logEntry: sdk.LogEntry{
+				Job:       "italian",
+				Container: "helper",
+			},
+			expected: "[italian/helper] ",
+		},
+	}
+	prefixer := newLogPrefixer(false)
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			require.Equal(t, testCase.expected, prefixer.prefix(testCase.logEntry))
+		})
+	}
+	t.Run("colorized", func(t *testing.T) {
+		prefixer := newLogPrefixer(true)
+		worker := sdk.LogEntry{Container: "worker"}
+		job := sdk.LogEntry{Job: "italian", Container: "italian"}
+		require.Equal(t, "\x1b[36m[worker]\x1b[0m ", prefixer.prefix(worker))
+		require.Equal(t, "\x1b[32m[italian]\x1b[0m ", prefixer.prefix(job))
+		// The same source should always be rendered in the same color
+		require.Equal(t, "\x1b[36m[worker]\x1b[0m ", prefixer.prefix(worker))
+	})
+}
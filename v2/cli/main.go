@@ -16,6 +16,7 @@ func main() {
 	app.Commands = []*cli.Command{
 		eventCommand,
 		initCommand,
+		jobCommand,
 		loginCommand,
 		logoutCommand,
 		projectCommand,
@@ -12,6 +12,7 @@ import (
 	"github.com/ghodss/yaml"
 	"github.com/gosuri/uitable"
 	"github.com/pkg/errors"
+	"github.com/robfig/cron/v3"
 	"github.com/urfave/cli/v2"
 	"k8s.io/apimachinery/pkg/util/duration"
 )
@@ -21,6 +22,7 @@ var projectCommand = &cli.Command{
 	Usage:   "Manage projects",
 	Aliases: []string{"projects"},
 	Subcommands: []*cli.Command{
+		jobCacheCommand,
 		{
 			Name:  "create",
 			Usage: "Create a new project",
@@ -85,6 +87,23 @@ var projectCommand = &cli.Command{
 			},
 			Action: projectList,
 		},
+		{
+			Name:  "queue",
+			Usage: "Show how many workers and jobs are waiting to be scheduled",
+			Description: "Shows, for each project (or only the specified " +
+				"project), how many workers and jobs are waiting to be scheduled " +
+				"and the project's scheduling weight",
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:    flagID,
+					Aliases: []string{"i", flagProject, "p"},
+					Usage: "Show only the specified project; if not specified, all " +
+						"projects are shown",
+				},
+				cliFlagOutput,
+			},
+			Action: projectQueue,
+		},
 		projectRolesCommands,
 		secretsCommand,
 		{
@@ -108,6 +127,7 @@ var projectCommand = &cli.Command{
 			},
 			Action: projectUpdate,
 		},
+		webhooksCommand,
 	},
 }
 
@@ -267,6 +287,31 @@ func projectGet(c *cli.Context) error {
 		)
 		fmt.Println(table)
 
+		if len(project.Spec.Schedules) > 0 {
+			fmt.Printf("\nProject %q schedules:\n\n", project.ID)
+			table = uitable.New()
+			table.AddRow("NAME", "CRON", "TYPE", "NEXT", "IN")
+			now := time.Now().UTC()
+			for _, schedule := range project.Spec.Schedules {
+				var next, in string
+				if cronSchedule, err := parseCron(schedule.Cron); err != nil {
+					next = "<invalid>"
+				} else {
+					nextTime := cronSchedule.Next(now)
+					next = nextTime.UTC().Format(time.RFC3339)
+					in = duration.ShortHumanDuration(nextTime.Sub(now))
+				}
+				table.AddRow(
+					schedule.Name,
+					schedule.Cron,
+					schedule.Type,
+					next,
+					in,
+				)
+			}
+			fmt.Println(table)
+		}
+
 	case flagOutputYAML:
 		yamlBytes, err := yaml.Marshal(project)
 		if err != nil {
@@ -291,6 +336,100 @@ func projectGet(c *cli.Context) error {
 	return nil
 }
 
+func projectQueue(c *cli.Context) error {
+	id := c.String(flagID)
+	output := c.String(flagOutput)
+
+	if err := validateOutputFormat(output); err != nil {
+		return err
+	}
+
+	client, err := getClient(false)
+	if err != nil {
+		return err
+	}
+
+	projects := []sdk.Project{}
+	if id != "" {
+		project, err := client.Core().Projects().Get(c.Context, id, nil)
+		if err != nil {
+			return err
+		}
+		projects = append(projects, project)
+	} else {
+		opts := meta.ListOptions{}
+		for {
+			projectList, err :=
+				client.Core().Projects().List(c.Context, nil, &opts)
+			if err != nil {
+				return err
+			}
+			projects = append(projects, projectList.Items...)
+			if projectList.Continue == "" {
+				break
+			}
+			opts.Continue = projectList.Continue
+		}
+	}
+
+	if len(projects) == 0 {
+		fmt.Println("No projects found.")
+		return nil
+	}
+
+	depths := make([]sdk.ProjectQueueDepth, len(projects))
+	for i, project := range projects {
+		if depths[i], err = client.Core().Projects().GetQueueDepth(
+			c.Context,
+			project.ID,
+			nil,
+		); err != nil {
+			return err
+		}
+	}
+
+	switch strings.ToLower(output) {
+	case flagOutputTable:
+		table := uitable.New()
+		table.AddRow("PROJECT", "WEIGHT", "PENDING WORKERS", "PENDING JOBS")
+		for i, project := range projects {
+			weight := project.Spec.SchedulingWeight
+			if weight < 1 {
+				weight = 1
+			}
+			table.AddRow(
+				project.ID,
+				weight,
+				depths[i].PendingWorkers,
+				depths[i].PendingJobs,
+			)
+		}
+		fmt.Println(table)
+
+	case flagOutputYAML:
+		yamlBytes, err := yaml.Marshal(depths)
+		if err != nil {
+			return errors.Wrap(
+				err,
+				"error formatting output from get project queue depth operation",
+			)
+		}
+		fmt.Println(string(yamlBytes))
+
+	case flagOutputJSON:
+		prettyJSON, err := json.MarshalIndent(depths, "", "  ")
+		if err != nil {
+			return errors.Wrap(
+				err,
+				"error formatting output from get project queue depth operation",
+			)
+		}
+		fmt.Println(string(prettyJSON))
+	}
+
+	return nil
+}
+
 func projectUpdate(c *cli.Context) error {
 	filename := c.String(flagFile)
 	create := c.Bool(flagCreate)
@@ -372,3 +511,19 @@ func projectDelete(c *cli.Context) error {
 
 	return nil
 }
+
+// cronParser parses standard, five field cron expressions and descriptors.
+var cronParser = cron.NewParser(
+	cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor,
+)
+
+// parseCron parses the provided cron expression the same way the API server
+// does-- expressions that do not explicitly specify a time zone are evaluated
+// in UTC.
+func parseCron(expr string) (cron.Schedule, error) {
+	expr = strings.TrimSpace(expr)
+	if !strings.HasPrefix(expr, "CRON_TZ=") && !strings.HasPrefix(expr, "TZ=") {
+		expr = "CRON_TZ=UTC " + expr
+	}
+	return cronParser.Parse(expr)
+}
@@ -3,6 +3,7 @@ package term
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/brigadecore/brigade/sdk/v3"
@@ -132,10 +133,12 @@ func (e *eventPage) fillEventInfo(project sdk.Project, event sdk.Event) {
 	infoText := fmt.Sprintf(
 		`[grey]Project: [white]%s
 [grey]Source: [white]%s
-[grey]Type: [white]%s`,
+[grey]Type: [white]%s
+[grey]Priority: [white]%d`,
 		event.ProjectID,
 		event.Source,
 		event.Type,
+		event.Priority,
 	)
 	if len(event.Qualifiers) > 0 {
 		infoText = fmt.Sprintf("%s\n[grey]Qualifiers:", infoText)
@@ -217,6 +220,27 @@ func (e *eventPage) fillWorkerInfo(event sdk.Event) {
 		getTextColorFromWorkerPhase(event.Worker.Status.Phase),
 		event.Worker.Status.Phase,
 	)
+	if event.Worker.Status.ExitCode != nil {
+		infoText = fmt.Sprintf(
+			"%s\n[grey]Exit Code: [white]%s",
+			infoText,
+			formatExitCode(event.Worker.Status.ExitCode),
+		)
+	}
+	if event.Worker.Status.Reason != "" {
+		infoText = fmt.Sprintf(
+			"%s\n[grey]Reason: [white]%s",
+			infoText,
+			event.Worker.Status.Reason,
+		)
+	}
+	if event.Worker.Status.Message != "" {
+		infoText = fmt.Sprintf(
+			"%s\n[grey]Message: [white]%s",
+			infoText,
+			tview.Escape(event.Worker.Status.Message),
+		)
+	}
 	e.workerInfo.SetText(infoText)
 }
 
@@ -229,6 +253,9 @@ func (e *eventPage) fillJobsTable(event sdk.Event) {
 		startedCol
 		endedCol
 		durationCol
+		exitCodeCol
+		reasonCol
+		dependsOnCol
 	)
 	e.jobsTable.Clear()
 	e.jobsTable.SetCell(
@@ -285,6 +312,30 @@ func (e *eventPage) fillJobsTable(event sdk.Event) {
 			Align: tview.AlignCenter,
 			Color: tcell.ColorYellow,
 		},
+	).SetCell(
+		0,
+		exitCodeCol,
+		&tview.TableCell{
+			Text:  "Exit Code",
+			Align: tview.AlignCenter,
+			Color: tcell.ColorYellow,
+		},
+	).SetCell(
+		0,
+		reasonCol,
+		&tview.TableCell{
+			Text:  "Reason",
+			Align: tview.AlignCenter,
+			Color: tcell.ColorYellow,
+		},
+	).SetCell(
+		0,
+		dependsOnCol,
+		&tview.TableCell{
+			Text:  "Depends On",
+			Align: tview.AlignCenter,
+			Color: tcell.ColorYellow,
+		},
 	)
 	for r, job := range event.Worker.Jobs {
 		row := r + 1
@@ -365,6 +416,31 @@ func (e *eventPage) fillJobsTable(event sdk.Event) {
 				},
 			)
 		}
+		e.jobsTable.SetCell(
+			row,
+			exitCodeCol,
+			&tview.TableCell{
+				Text:  formatExitCode(job.Status.ExitCode),
+				Align: tview.AlignLeft,
+				Color: color,
+			},
+		).SetCell(
+			row,
+			reasonCol,
+			&tview.TableCell{
+				Text:  job.Status.Reason,
+				Align: tview.AlignLeft,
+				Color: color,
+			},
+		).SetCell(
+			row,
+			dependsOnCol,
+			&tview.TableCell{
+				Text:  strings.Join(job.Spec.DependsOn, ", "),
+				Align: tview.AlignLeft,
+				Color: color,
+			},
+		)
 	}
 	e.jobsTable.SetSelectedFunc(func(row, _ int) {
 		if row > 0 { // Header row cells aren't selectable
@@ -1,6 +1,7 @@
 package term
 
 import (
+	"strconv"
 	"time"
 
 	"github.com/brigadecore/brigade/sdk/v3"
@@ -123,3 +124,27 @@ func formatDateTimeToString(time *time.Time) string {
 	}
 	return time.UTC().Format("2006-01-02 15:04:05")
 }
+
+// formatExitCode formats an optional exit code as a string, returning an empty
+// string if no exit code is available.
+func formatExitCode(exitCode *int32) string {
+	if exitCode == nil {
+		return ""
+	}
+	return strconv.Itoa(int(*exitCode))
+}
+
+// getContainerStatus returns the status of the named container from the
+// provided slice of container statuses, and a boolean indicating whether it
+// was found.
+func getContainerStatus(
+	statuses []sdk.ContainerStatus,
+	name string,
+) (sdk.ContainerStatus, bool) {
+	for _, status := range statuses {
+		if status.Name == name {
+			return status, true
+		}
+	}
+	return sdk.ContainerStatus{}, false
+}
@@ -129,6 +129,27 @@ func (j *jobPage) fillJobInfo(eventID string, job sdk.Job) {
 			job.Status.Ended.Sub(*job.Status.Started),
 		)
 	}
+	if job.Status.ExitCode != nil {
+		infoText = fmt.Sprintf(
+			"%s\n[grey]Exit Code: [white]%s",
+			infoText,
+			formatExitCode(job.Status.ExitCode),
+		)
+	}
+	if job.Status.Reason != "" {
+		infoText = fmt.Sprintf(
+			"%s\n[grey]Reason: [white]%s",
+			infoText,
+			job.Status.Reason,
+		)
+	}
+	if job.Status.Message != "" {
+		infoText = fmt.Sprintf(
+			"%s\n[grey]Message: [white]%s",
+			infoText,
+			tview.Escape(job.Status.Message),
+		)
+	}
 	j.jobInfo.SetText(infoText)
 }
 
@@ -137,6 +158,8 @@ func (j *jobPage) fillContainersTable(job sdk.Job) {
 		statusCol int = iota
 		nameCol
 		imageCol
+		exitCodeCol
+		reasonCol
 	)
 
 	j.containersTable.Clear()
@@ -163,6 +186,22 @@ func (j *jobPage) fillContainersTable(job sdk.Job) {
 			Align: tview.AlignCenter,
 			Color: tcell.ColorYellow,
 		},
+	).SetCell(
+		0,
+		exitCodeCol,
+		&tview.TableCell{
+			Text:  "Exit Code",
+			Align: tview.AlignCenter,
+			Color: tcell.ColorYellow,
+		},
+	).SetCell(
+		0,
+		reasonCol,
+		&tview.TableCell{
+			Text:  "Reason",
+			Align: tview.AlignCenter,
+			Color: tcell.ColorYellow,
+		},
 	)
 
 	row := 1
@@ -194,6 +233,7 @@ func (j *jobPage) fillContainersTable(job sdk.Job) {
 			Color: color,
 		},
 	)
+	j.setContainerStatusCells(row, exitCodeCol, reasonCol, job, job.Name, color)
 
 	for k, v := range job.Spec.SidecarContainers {
 		row++
@@ -214,5 +254,46 @@ func (j *jobPage) fillContainersTable(job sdk.Job) {
 				Color: tcell.ColorWhite,
 			},
 		)
+		j.setContainerStatusCells(
+			row,
+			exitCodeCol,
+			reasonCol,
+			job,
+			k,
+			tcell.ColorWhite,
+		)
 	}
 }
+
+// setContainerStatusCells fills the specified row's exit code and reason cells
+// using the status, if any, of the Job's named container.
+func (j *jobPage) setContainerStatusCells(
+	row int,
+	exitCodeCol int,
+	reasonCol int,
+	job sdk.Job,
+	containerName string,
+	color tcell.Color,
+) {
+	status, found := getContainerStatus(job.Status.Containers, containerName)
+	if !found {
+		return
+	}
+	j.containersTable.SetCell(
+		row,
+		exitCodeCol,
+		&tview.TableCell{
+			Text:  formatExitCode(status.ExitCode),
+			Align: tview.AlignLeft,
+			Color: color,
+		},
+	).SetCell(
+		row,
+		reasonCol,
+		&tview.TableCell{
+			Text:  status.Reason,
+			Align: tview.AlignLeft,
+			Color: color,
+		},
+	)
+}
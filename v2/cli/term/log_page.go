@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/armon/circbuf"
@@ -16,9 +18,13 @@ const logPageName = "log"
 
 type logPage struct {
 	*page
-	logText  *tview.TextView
-	logBuf   *circbuf.Buffer
-	maxBytes int64
+	logText     *tview.TextView
+	searchField *tview.InputField
+	logBuf      *circbuf.Buffer
+	maxBytes    int64
+	// searchResults, when non-empty, is displayed in place of streamed logs.
+	searchResults    string
+	searchResultsMux sync.Mutex
 }
 
 func newLogPage(
@@ -29,10 +35,12 @@ func newLogPage(
 	l := &logPage{
 		page:    newPage(apiClient, app, router),
 		logText: tview.NewTextView().SetDynamicColors(true),
+		searchField: tview.NewInputField().
+			SetLabel("Search (Enter) Find (Esc) Clear: "),
 	}
 
 	l.maxBytes = 65535
-	l.logText.SetBorder(true).SetTitle("Logs (<-/Del) Quit")
+	l.logText.SetBorder(true).SetTitle("Logs (<-/Del) Quit (/) Search")
 
 	// Returns a new primitive which puts the provided primitive in the center and
 	// sets its size to the given width and height.
@@ -58,6 +66,12 @@ func newLogPage(
 					0,
 					false,
 				).
+				AddItem(
+					l.searchField,
+					1, // Fixed width
+					0,
+					false,
+				).
 				AddItem(
 					nil, // Spacer to help create the illusion of a floating window
 					0,
@@ -81,6 +95,8 @@ func newLogPage(
 func (l *logPage) load(ctx context.Context, eventID string, jobID string) {
 
 	l.logText.Clear()
+	l.searchField.SetText("")
+	l.setSearchResults("")
 	l.app.SetFocus(l.logText)
 	l.logText.SetInputCapture(func(evt *tcell.EventKey) *tcell.EventKey {
 		switch evt.Key() {
@@ -95,9 +111,28 @@ func (l *logPage) load(ctx context.Context, eventID string, jobID string) {
 			} else {
 				l.router.loadJobPage(eventID, jobID)
 			}
+		case tcell.KeyRune:
+			if evt.Rune() == '/' { // Search
+				l.app.SetFocus(l.searchField)
+				return nil
+			}
 		}
 		return evt
 	})
+	l.searchField.SetDoneFunc(func(key tcell.Key) {
+		switch key {
+		case tcell.KeyEnter:
+			if l.searchField.GetText() == "" {
+				l.setSearchResults("")
+			} else {
+				go l.search(ctx, eventID, jobID, l.searchField.GetText())
+			}
+		case tcell.KeyEscape:
+			l.searchField.SetText("")
+			l.setSearchResults("")
+		}
+		l.app.SetFocus(l.logText)
+	})
 
 	var err error
 	l.logBuf, err = circbuf.NewBuffer(l.maxBytes)
@@ -170,13 +205,94 @@ func (l *logPage) streamLogsToBuffer(ctx context.Context, eventID string, jobID
 	}
 }
 
+// search searches the Event's logs-- or, if a Job is specified, that Job's
+// logs-- for lines containing the provided text and displays the results in
+// place of streamed logs.
+func (l *logPage) search(
+	ctx context.Context,
+	eventID string,
+	jobID string,
+	text string,
+) {
+	matches, err := l.apiClient.Core().Events().Logs().Search(
+		ctx,
+		eventID,
+		sdk.LogSearchCriteria{
+			Pattern:    text,
+			IgnoreCase: true,
+		},
+		&sdk.LogSearchOptions{
+			ContextLines: 2,
+		},
+	)
+	if err != nil {
+		l.setSearchResults(err.Error())
+		return
+	}
+	sb := strings.Builder{}
+	for _, match := range matches.Items {
+		if jobID != "" && match.Job != jobID {
+			continue
+		}
+		if sb.Len() > 0 {
+			sb.WriteString("[gray]--[white]\n")
+		}
+		location := match.Container
+		if match.Job != "" {
+			location = fmt.Sprintf("%s/%s", match.Job, match.Container)
+		}
+		for _, logEntry := range match.Before {
+			sb.WriteString(
+				fmt.Sprintf(
+					"[gray]%s:[white] %s\n",
+					location,
+					tview.Escape(logEntry.Message),
+				),
+			)
+		}
+		sb.WriteString(
+			fmt.Sprintf(
+				"[yellow]%s:[white] %s\n",
+				location,
+				tview.Escape(match.LogEntry.Message),
+			),
+		)
+		for _, logEntry := range match.After {
+			sb.WriteString(
+				fmt.Sprintf(
+					"[gray]%s:[white] %s\n",
+					location,
+					tview.Escape(logEntry.Message),
+				),
+			)
+		}
+	}
+	if sb.Len() == 0 {
+		sb.WriteString(fmt.Sprintf("No logs matching %q found.", text))
+	}
+	l.setSearchResults(sb.String())
+}
+
+// setSearchResults sets search results to be displayed in place of streamed
+// logs. Setting empty search results resumes the display of streamed logs.
+func (l *logPage) setSearchResults(results string) {
+	l.searchResultsMux.Lock()
+	defer l.searchResultsMux.Unlock()
+	l.searchResults = results
+}
+
 func (l *logPage) writeLogs(ctx context.Context) {
 	ticker := time.NewTicker(time.Second)
 	defer ticker.Stop()
 	for {
 		select {
 		case <-ticker.C:
-			if l.logBuf.TotalWritten() > l.maxBytes {
+			l.searchResultsMux.Lock()
+			searchResults := l.searchResults
+			l.searchResultsMux.Unlock()
+			if searchResults != "" {
+				l.logText.SetText(searchResults)
+			} else if l.logBuf.TotalWritten() > l.maxBytes {
 				l.logText.SetText(
 					fmt.Sprintf("(Previous text omitted)\n %s", l.logBuf.String()),
 				)
@@ -197,6 +197,7 @@ func (p *projectPage) fillEventsTable(events sdk.EventList) {
 		idCol
 		sourceCol
 		typeCol
+		priorityCol
 		ageCol
 		startedCol
 		endedCol
@@ -234,6 +235,14 @@ func (p *projectPage) fillEventsTable(events sdk.EventList) {
 			Align: tview.AlignCenter,
 			Color: tcell.ColorYellow,
 		},
+	).SetCell(
+		0,
+		priorityCol,
+		&tview.TableCell{
+			Text:  "Priority",
+			Align: tview.AlignCenter,
+			Color: tcell.ColorYellow,
+		},
 	).SetCell(
 		0,
 		ageCol,
@@ -303,6 +312,14 @@ func (p *projectPage) fillEventsTable(events sdk.EventList) {
 				Align: tview.AlignLeft,
 				Color: color,
 			},
+		).SetCell(
+			row,
+			priorityCol,
+			&tview.TableCell{
+				Text:  fmt.Sprintf("%d", event.Priority),
+				Align: tview.AlignLeft,
+				Color: color,
+			},
 		)
 		age := time.Since(*event.Created).Truncate(time.Second)
 		p.eventsTable.SetCell(
@@ -0,0 +1,192 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/brigadecore/brigade/sdk/v3"
+	"github.com/brigadecore/brigade/sdk/v3/meta"
+	"github.com/ghodss/yaml"
+	"github.com/gosuri/uitable"
+	"github.com/pkg/errors"
+	"github.com/urfave/cli/v2"
+	"k8s.io/apimachinery/pkg/util/duration"
+)
+
+var webhooksCommand = &cli.Command{
+	Name:    "webhook",
+	Aliases: []string{"webhooks"},
+	Usage:   "Inspect project webhooks",
+	Subcommands: []*cli.Command{
+		{
+			Name:  "deliveries",
+			Usage: "List deliveries of notifications to project webhooks",
+			Description: "Lists deliveries of notifications to the project's " +
+				"webhooks, newest first, including the outcome of the most recent " +
+				"attempt at each",
+			Flags: []cli.Flag{
+				cliFlagOutput,
+				&cli.StringFlag{
+					Name: flagContinue,
+					Usage: "Advanced-- passes an opaque value obtained from a " +
+						"previous command back to the server to access the next page " +
+						"of results",
+				},
+				&cli.StringFlag{
+					Name:    flagEvent,
+					Aliases: []string{"e"},
+					Usage: "Retrieve only deliveries of notifications concerning " +
+						"the specified event",
+				},
+				&cli.StringFlag{
+					Name:     flagID,
+					Aliases:  []string{"i", flagProject, "p"},
+					Usage:    "Retrieve deliveries for the specified project (required)",
+					Required: true,
+				},
+				&cli.StringFlag{
+					Name:    flagWebhook,
+					Aliases: []string{"w"},
+					Usage:   "Retrieve only deliveries to the specified webhook",
+				},
+				nonInteractiveFlag,
+			},
+			Action: webhookDeliveriesList,
+		},
+	},
+}
+
+func webhookDeliveriesList(c *cli.Context) error {
+	output := c.String(flagOutput)
+	projectID := c.String(flagID)
+
+	if err := validateOutputFormat(output); err != nil {
+		return err
+	}
+
+	selector := sdk.WebhookDeliveriesSelector{
+		EventID: c.String(flagEvent),
+		Webhook: c.String(flagWebhook),
+	}
+	opts := meta.ListOptions{
+		Continue: c.String(flagContinue),
+	}
+
+	client, err := getClient(false)
+	if err != nil {
+		return err
+	}
+
+	for {
+		deliveries, err := client.Core().Projects().Webhooks().ListDeliveries(
+			c.Context,
+			projectID,
+			&selector,
+			&opts,
+		)
+		if err != nil {
+			return err
+		}
+
+		if len(deliveries.Items) == 0 {
+			fmt.Println("No webhook deliveries found.")
+			return nil
+		}
+
+		switch strings.ToLower(output) {
+		case flagOutputTable:
+			table := uitable.New()
+			table.AddRow(
+				"ID",
+				"WEBHOOK",
+				"EVENT",
+				"PHASE CHANGE",
+				"AGE",
+				"ATTEMPTS",
+				"STATUS",
+				"LAST RESULT",
+			)
+			for _, delivery := range deliveries.Items {
+				var age string
+				if delivery.Created != nil {
+					age = duration.ShortHumanDuration(time.Since(*delivery.Created))
+				}
+				table.AddRow(
+					delivery.ID,
+					delivery.Webhook,
+					delivery.Notification.EventID,
+					notificationPhaseChange(delivery.Notification),
+					age,
+					len(delivery.Status.Attempts),
+					delivery.Status.Phase,
+					lastWebhookDeliveryResult(delivery.Status),
+				)
+			}
+			fmt.Println(table)
+
+		case flagOutputYAML:
+			yamlBytes, err := yaml.Marshal(deliveries)
+			if err != nil {
+				return errors.Wrap(
+					err,
+					"error formatting output from list webhook deliveries operation",
+				)
+			}
+			fmt.Println(string(yamlBytes))
+
+		case flagOutputJSON:
+			prettyJSON, err := json.MarshalIndent(deliveries, "", "  ")
+			if err != nil {
+				return errors.Wrap(
+					err,
+					"error formatting output from list webhook deliveries operation",
+				)
+			}
+			fmt.Println(string(prettyJSON))
+		}
+
+		if shouldContinue, err :=
+			shouldContinue(
+				c,
+				deliveries.RemainingItemCount,
+				deliveries.Continue,
+			); err != nil {
+			return err
+		} else if !shouldContinue {
+			break
+		}
+
+		opts.Continue = deliveries.Continue
+	}
+
+	return nil
+}
+
+// notificationPhaseChange returns a short description of the phase change
+// described by the provided Notification, e.g. "worker FAILED" or
+// "job build SUCCEEDED".
+func notificationPhaseChange(notification sdk.Notification) string {
+	if notification.JobName != "" {
+		return fmt.Sprintf(
+			"job %s %s",
+			notification.JobName,
+			notification.JobPhase,
+		)
+	}
+	return fmt.Sprintf("worker %s", notification.WorkerPhase)
+}
+
+// lastWebhookDeliveryResult returns a short description of the outcome of the
+// most recent attempt at a WebhookDelivery.
+func lastWebhookDeliveryResult(status sdk.WebhookDeliveryStatus) string {
+	if len(status.Attempts) == 0 {
+		return ""
+	}
+	attempt := status.Attempts[len(status.Attempts)-1]
+	if attempt.Error != "" {
+		return attempt.Error
+	}
+	return fmt.Sprintf("%d", attempt.StatusCode)
+}
@@ -9,17 +9,19 @@ import (
 const (
 	AnnotationTimeoutDuration = "brigade.sh/timeoutDuration"
 
-	LabelBrigadeID = "brigade.sh/id"
-	LabelComponent = "brigade.sh/component"
-	LabelEvent     = "brigade.sh/event"
-	LabelJob       = "brigade.sh/job"
-	LabelProject   = "brigade.sh/project"
+	LabelBrigadeID  = "brigade.sh/id"
+	LabelComponent  = "brigade.sh/component"
+	LabelEvent      = "brigade.sh/event"
+	LabelJob        = "brigade.sh/job"
+	LabelJobAttempt = "brigade.sh/job-attempt"
+	LabelProject    = "brigade.sh/project"
 
 	LabelKeyWorker         = "worker"
 	LabelKeyJob            = "job"
 	LabelKeyEvent          = "event"
 	LabelKeyWorkspace      = "workspace"
 	LabelKeyProjectSecrets = "project-secrets"
+	LabelKeyCacheVolume    = "cache-volume"
 
 	SecretTypeProjectSecrets = "brigade.sh/project-secrets" // nolint: gosec
 	SecretTypeEvent          = "brigade.sh/event"           // nolint: gosec
@@ -34,6 +36,12 @@ func WorkspacePVCName(eventID string) string {
 	return eventID
 }
 
+// CacheVolumePVCName returns the name of the PVC backing the specified
+// Project-wide cache volume.
+func CacheVolumePVCName(cacheName string) string {
+	return fmt.Sprintf("cache-%s", cacheName)
+}
+
 func WorkerPodName(eventID string) string {
 	return eventID
 }
@@ -47,6 +55,19 @@ func WorkerPodsSelector(brigadeID string) string {
 	).AsSelector().String()
 }
 
+func ProjectWorkerPodsSelector(brigadeID, projectID string) string {
+	if projectID == "" {
+		return WorkerPodsSelector(brigadeID)
+	}
+	return labels.Set(
+		map[string]string{
+			LabelBrigadeID: brigadeID,
+			LabelComponent: LabelKeyWorker,
+			LabelProject:   projectID,
+		},
+	).AsSelector().String()
+}
+
 func JobSecretName(eventID, jobName string) string {
 	return fmt.Sprintf("%s-%s", eventID, jobName)
 }
@@ -55,6 +76,19 @@ func JobPodName(eventID, jobName string) string {
 	return fmt.Sprintf("%s-%s", eventID, jobName)
 }
 
+// JobAttemptPodName returns the name of the pod for the specified attempt at a
+// Job. The first attempt's pod is named exactly as JobPodName would name it.
+// Subsequent attempts are distinguished by a suffix that begins with a dot.
+// Job names cannot contain dots, so no attempt's pod can be named the same as
+// any attempt at another Job's pod (e.g. attempt 2 at Job "build" and attempt
+// 1 at Job "build-2").
+func JobAttemptPodName(eventID, jobName string, attempt int) string {
+	if attempt <= 1 {
+		return JobPodName(eventID, jobName)
+	}
+	return fmt.Sprintf("%s.%d", JobPodName(eventID, jobName), attempt)
+}
+
 func JobPodsSelector(brigadeID string) string {
 	return labels.Set(
 		map[string]string{
@@ -63,3 +97,16 @@ func JobPodsSelector(brigadeID string) string {
 		},
 	).AsSelector().String()
 }
+
+func ProjectJobPodsSelector(brigadeID, projectID string) string {
+	if projectID == "" {
+		return JobPodsSelector(brigadeID)
+	}
+	return labels.Set(
+		map[string]string{
+			LabelBrigadeID: brigadeID,
+			LabelComponent: LabelKeyJob,
+			LabelProject:   projectID,
+		},
+	).AsSelector().String()
+}
@@ -0,0 +1,28 @@
+package kubernetes
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestJobAttemptPodName(t *testing.T) {
+	const testEventID = "123456789"
+	require.Equal(
+		t,
+		JobPodName(testEventID, "build"),
+		JobAttemptPodName(testEventID, "build", 1),
+	)
+	require.Equal(
+		t,
+		"123456789-build.2",
+		JobAttemptPodName(testEventID, "build", 2),
+	)
+	// Job names may end in a dash and digits, so retries of one Job mustn't be
+	// named like the pods of another
+	require.NotEqual(
+		t,
+		JobAttemptPodName(testEventID, "build-2", 1),
+		JobAttemptPodName(testEventID, "build", 2),
+	)
+}
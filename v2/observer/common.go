@@ -1,11 +1,16 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"time"
 
+	"github.com/brigadecore/brigade/sdk/v3"
 	myk8s "github.com/brigadecore/brigade/v2/internal/kubernetes"
+	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
 )
 
 const apiRequestTimeout = 30 * time.Second
@@ -49,3 +54,147 @@ func (o *observer) getPodTimeoutDuration(
 	}
 	return timeout
 }
+
+// getContainerStatuses returns the status of every init container and
+// container belonging to the provided pod.
+func getContainerStatuses(pod *corev1.Pod) []sdk.ContainerStatus {
+	k8sStatuses := append(
+		append(
+			[]corev1.ContainerStatus{},
+			pod.Status.InitContainerStatuses...,
+		),
+		pod.Status.ContainerStatuses...,
+	)
+	if len(k8sStatuses) == 0 {
+		return nil
+	}
+	statuses := make([]sdk.ContainerStatus, len(k8sStatuses))
+	for i, k8sStatus := range k8sStatuses {
+		statuses[i] = getContainerStatus(k8sStatus)
+	}
+	return statuses
+}
+
+// getContainerStatus maps the provided Kubernetes container status to an
+// sdk.ContainerStatus.
+func getContainerStatus(k8sStatus corev1.ContainerStatus) sdk.ContainerStatus {
+	status := sdk.ContainerStatus{
+		Name: k8sStatus.Name,
+	}
+	if terminated := k8sStatus.State.Terminated; terminated != nil {
+		exitCode := terminated.ExitCode
+		status.ExitCode = &exitCode
+		status.Reason = terminated.Reason
+		status.Message = terminated.Message
+	} else if waiting := k8sStatus.State.Waiting; waiting != nil {
+		status.Reason = waiting.Reason
+		status.Message = waiting.Message
+	}
+	return status
+}
+
+// getPodReason returns a brief, machine-readable reason and a human-readable
+// message explaining the current state of the provided pod, whose primary
+// container has the specified name. Empty strings are returned if nothing
+// noteworthy can be determined from the pod itself.
+func getPodReason(
+	pod *corev1.Pod,
+	primaryContainerName string,
+) (string, string) {
+	// Pod-level reasons, like eviction, trump anything else
+	if pod.Status.Reason != "" {
+		return pod.Status.Reason, pod.Status.Message
+	}
+	// An init container that failed, or can't start, prevents the primary
+	// container from ever running
+	for _, k8sStatus := range pod.Status.InitContainerStatuses {
+		status := getContainerStatus(k8sStatus)
+		if isNoteworthyContainerStatus(status) {
+			return status.Reason, fmt.Sprintf(
+				"init container %q: %s",
+				status.Name,
+				status.Message,
+			)
+		}
+	}
+	for _, k8sStatus := range pod.Status.ContainerStatuses {
+		if k8sStatus.Name == primaryContainerName {
+			status := getContainerStatus(k8sStatus)
+			if isNoteworthyContainerStatus(status) {
+				return status.Reason, status.Message
+			}
+			break
+		}
+	}
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodScheduled &&
+			condition.Status == corev1.ConditionFalse {
+			return condition.Reason, condition.Message
+		}
+	}
+	return "", ""
+}
+
+// isNoteworthyContainerStatus returns true if the provided container status
+// reflects an unsuccessful exit or an inability to start, and false otherwise.
+func isNoteworthyContainerStatus(status sdk.ContainerStatus) bool {
+	if status.ExitCode != nil {
+		return *status.ExitCode != 0
+	}
+	switch status.Reason {
+	case "", "ContainerCreating", "PodInitializing":
+		return false
+	}
+	return true
+}
+
+// getPodWarning returns the reason and message of the most recent Warning
+// event involving the provided pod. Events capture many conditions, like
+// volumes that cannot be mounted, that are not otherwise reflected in a pod's
+// status. Empty strings are returned if no such event exists.
+func (o *observer) getPodWarning(
+	ctx context.Context,
+	pod *corev1.Pod,
+) (string, string) {
+	ctx, cancel := context.WithTimeout(ctx, apiRequestTimeout)
+	defer cancel()
+	events, err := o.kubeClient.CoreV1().Events(pod.Namespace).List(
+		ctx,
+		metav1.ListOptions{
+			FieldSelector: fields.Set{
+				"involvedObject.uid": string(pod.UID),
+				"type":               corev1.EventTypeWarning,
+			}.String(),
+		},
+	)
+	if err != nil {
+		o.errFn(
+			errors.Wrapf(
+				err,
+				"error listing events for pod %q in namespace %q",
+				pod.Name,
+				pod.Namespace,
+			),
+		)
+		return "", ""
+	}
+	var latest *corev1.Event
+	for i, event := range events.Items {
+		if latest == nil || eventTime(event).After(eventTime(*latest)) {
+			latest = &events.Items[i]
+		}
+	}
+	if latest == nil {
+		return "", ""
+	}
+	return latest.Reason, latest.Message
+}
+
+// eventTime returns the time at which the provided Kubernetes event most
+// recently occurred.
+func eventTime(event corev1.Event) time.Time {
+	if !event.LastTimestamp.IsZero() {
+		return event.LastTimestamp.Time
+	}
+	return event.EventTime.Time
+}
@@ -1,13 +1,16 @@
 package main
 
 import (
+	"context"
 	"testing"
 	"time"
 
+	"github.com/brigadecore/brigade/sdk/v3"
 	myk8s "github.com/brigadecore/brigade/v2/internal/kubernetes"
 	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
 )
 
 func TestGetPodTimeoutDuration(t *testing.T) {
@@ -101,3 +104,269 @@ func TestGetPodTimeoutDuration(t *testing.T) {
 		})
 	}
 }
+
+func TestGetContainerStatuses(t *testing.T) {
+	exitCode := int32(1)
+	testCases := []struct {
+		name     string
+		pod      *corev1.Pod
+		expected []sdk.ContainerStatus
+	}{
+		{
+			name:     "no container statuses",
+			pod:      &corev1.Pod{},
+			expected: nil,
+		},
+		{
+			name: "init and regular container statuses",
+			pod: &corev1.Pod{
+				Status: corev1.PodStatus{
+					InitContainerStatuses: []corev1.ContainerStatus{
+						{
+							Name: "vcs",
+							State: corev1.ContainerState{
+								Terminated: &corev1.ContainerStateTerminated{
+									ExitCode: 1,
+									Reason:   "Error",
+									Message:  "authentication failed",
+								},
+							},
+						},
+					},
+					ContainerStatuses: []corev1.ContainerStatus{
+						{
+							Name: "job",
+							State: corev1.ContainerState{
+								Waiting: &corev1.ContainerStateWaiting{
+									Reason: "PodInitializing",
+								},
+							},
+						},
+					},
+				},
+			},
+			expected: []sdk.ContainerStatus{
+				{
+					Name:     "vcs",
+					ExitCode: &exitCode,
+					Reason:   "Error",
+					Message:  "authentication failed",
+				},
+				{
+					Name:   "job",
+					Reason: "PodInitializing",
+				},
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			require.Equal(t, testCase.expected, getContainerStatuses(testCase.pod))
+		})
+	}
+}
+
+func TestGetPodReason(t *testing.T) {
+	testCases := []struct {
+		name            string
+		pod             *corev1.Pod
+		expectedReason  string
+		expectedMessage string
+	}{
+		{
+			name: "nothing noteworthy",
+			pod: &corev1.Pod{
+				Status: corev1.PodStatus{
+					ContainerStatuses: []corev1.ContainerStatus{
+						{
+							Name: "job",
+							State: corev1.ContainerState{
+								Terminated: &corev1.ContainerStateTerminated{
+									ExitCode: 0,
+									Reason:   "Completed",
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "pod was evicted",
+			pod: &corev1.Pod{
+				Status: corev1.PodStatus{
+					Reason:  "Evicted",
+					Message: "The node was low on resource: memory.",
+				},
+			},
+			expectedReason:  "Evicted",
+			expectedMessage: "The node was low on resource: memory.",
+		},
+		{
+			name: "init container failed",
+			pod: &corev1.Pod{
+				Status: corev1.PodStatus{
+					InitContainerStatuses: []corev1.ContainerStatus{
+						{
+							Name: "vcs",
+							State: corev1.ContainerState{
+								Terminated: &corev1.ContainerStateTerminated{
+									ExitCode: 1,
+									Reason:   "Error",
+									Message:  "authentication failed",
+								},
+							},
+						},
+					},
+				},
+			},
+			expectedReason:  "Error",
+			expectedMessage: `init container "vcs": authentication failed`,
+		},
+		{
+			name: "primary container was OOMKilled",
+			pod: &corev1.Pod{
+				Status: corev1.PodStatus{
+					ContainerStatuses: []corev1.ContainerStatus{
+						{
+							Name: "sidecar",
+							State: corev1.ContainerState{
+								Terminated: &corev1.ContainerStateTerminated{
+									ExitCode: 2,
+									Reason:   "Error",
+								},
+							},
+						},
+						{
+							Name: "job",
+							State: corev1.ContainerState{
+								Terminated: &corev1.ContainerStateTerminated{
+									ExitCode: 137,
+									Reason:   "OOMKilled",
+								},
+							},
+						},
+					},
+				},
+			},
+			expectedReason: "OOMKilled",
+		},
+		{
+			name: "primary container image cannot be pulled",
+			pod: &corev1.Pod{
+				Status: corev1.PodStatus{
+					ContainerStatuses: []corev1.ContainerStatus{
+						{
+							Name: "job",
+							State: corev1.ContainerState{
+								Waiting: &corev1.ContainerStateWaiting{
+									Reason:  "ImagePullBackOff",
+									Message: `Back-off pulling image "debian:nope"`,
+								},
+							},
+						},
+					},
+				},
+			},
+			expectedReason:  "ImagePullBackOff",
+			expectedMessage: `Back-off pulling image "debian:nope"`,
+		},
+		{
+			name: "pod is unschedulable",
+			pod: &corev1.Pod{
+				Status: corev1.PodStatus{
+					Conditions: []corev1.PodCondition{
+						{
+							Type:    corev1.PodScheduled,
+							Status:  corev1.ConditionFalse,
+							Reason:  corev1.PodReasonUnschedulable,
+							Message: "0/3 nodes are available: 3 Insufficient cpu.",
+						},
+					},
+				},
+			},
+			expectedReason:  corev1.PodReasonUnschedulable,
+			expectedMessage: "0/3 nodes are available: 3 Insufficient cpu.",
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			reason, message := getPodReason(testCase.pod, "job")
+			require.Equal(t, testCase.expectedReason, reason)
+			require.Equal(t, testCase.expectedMessage, message)
+		})
+	}
+}
+
+func TestGetPodWarning(t *testing.T) {
+	const testNamespace = "foo"
+	pod := &corev1.Pod{
+		ObjectMeta: v1.ObjectMeta{
+			Namespace: testNamespace,
+			Name:      "bar",
+			UID:       "abcdef",
+		},
+	}
+	testCases := []struct {
+		name            string
+		events          []corev1.Event
+		expectedReason  string
+		expectedMessage string
+	}{
+		{
+			name: "no events",
+		},
+		{
+			name: "most recent event is returned",
+			events: []corev1.Event{
+				{
+					ObjectMeta: v1.ObjectMeta{
+						Namespace: testNamespace,
+						Name:      "bar.1",
+					},
+					Type:          corev1.EventTypeWarning,
+					Reason:        "FailedScheduling",
+					Message:       "0/3 nodes are available",
+					LastTimestamp: v1.NewTime(time.Now().Add(-time.Minute)),
+				},
+				{
+					ObjectMeta: v1.ObjectMeta{
+						Namespace: testNamespace,
+						Name:      "bar.2",
+					},
+					Type:          corev1.EventTypeWarning,
+					Reason:        "FailedMount",
+					Message:       "Unable to attach or mount volumes",
+					LastTimestamp: v1.NewTime(time.Now()),
+				},
+			},
+			expectedReason:  "FailedMount",
+			expectedMessage: "Unable to attach or mount volumes",
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			kubeClient := fake.NewSimpleClientset()
+			for i := range testCase.events {
+				_, err := kubeClient.CoreV1().Events(testNamespace).Create(
+					context.Background(),
+					&testCase.events[i],
+					v1.CreateOptions{},
+				)
+				require.NoError(t, err)
+			}
+			observer := &observer{
+				kubeClient: kubeClient,
+				errFn: func(i ...interface{}) {
+					require.Fail(
+						t,
+						"errFn should not have been called, but was",
+					)
+				},
+			}
+			reason, message := observer.getPodWarning(context.Background(), pod)
+			require.Equal(t, testCase.expectedReason, reason)
+			require.Equal(t, testCase.expectedMessage, message)
+		})
+	}
+}
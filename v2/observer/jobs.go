@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/brigadecore/brigade/sdk/v3"
@@ -49,6 +50,13 @@ func (o *observer) syncJobPod(obj interface{}) {
 	pod := obj.(*corev1.Pod) // nolint: forcetypeassert
 	// Map pod status to job status
 	status := o.getJobStatusFromPod(pod)
+	// If the pod itself doesn't explain why it's still pending, Kubernetes
+	// events might
+	if status.Reason == "" &&
+		pod.Status.Phase == corev1.PodPending &&
+		!status.Phase.IsTerminal() {
+		status.Reason, status.Message = o.getPodWarningFn(ctx, pod)
+	}
 	// Manage the timeout clock
 	o.manageJobTimeoutFn(ctx, pod, status.Phase)
 	// Use the API to update Job status
@@ -154,15 +162,30 @@ func (o *observer) getJobStatusFromPod(pod *corev1.Pod) sdk.JobStatus {
 	if pod.Status.StartTime != nil {
 		status.Started = &pod.Status.StartTime.Time
 	}
-	// Determine the job's end time based on container[0] end time
+	// Determine the job's end time and exit code based on container[0]
 	for _, containerStatus := range pod.Status.ContainerStatuses {
 		if containerStatus.Name == pod.Spec.Containers[0].Name {
 			if containerStatus.State.Terminated != nil {
 				status.Ended = &containerStatus.State.Terminated.FinishedAt.Time
+				exitCode := containerStatus.State.Terminated.ExitCode
+				status.ExitCode = &exitCode
 			}
 			break
 		}
 	}
+	// Explain the job's phase and capture details of every container, including
+	// sidecars
+	if len(pod.Spec.Containers) > 0 {
+		status.Reason, status.Message =
+			getPodReason(pod, pod.Spec.Containers[0].Name)
+	}
+	status.Containers = getContainerStatuses(pod)
+	// Determine which attempt at the job the pod represents. Pods that predate
+	// job retries bear no such label and represent the first attempt.
+	if attempt, err :=
+		strconv.Atoi(pod.Labels[myk8s.LabelJobAttempt]); err == nil {
+		status.Attempt = attempt
+	}
 	return status
 }
 
@@ -144,9 +144,18 @@ func TestSyncJobPod(t *testing.T) {
 						_ *sdk.JobStatusUpdateOptions,
 					) error {
 						require.Equal(t, sdk.JobPhaseRunning, status.Phase)
+						require.Equal(t, "FailedMount", status.Reason)
+						require.Equal(
+							t,
+							"Unable to attach or mount volumes",
+							status.Message,
+						)
 						return nil
 					},
 				},
+				getPodWarningFn: func(context.Context, *corev1.Pod) (string, string) {
+					return "FailedMount", "Unable to attach or mount volumes"
+				},
 				cleanupJobFn: func(_, _ string) {
 					require.Fail(
 						t,
@@ -183,6 +192,9 @@ func TestSyncJobPod(t *testing.T) {
 						return nil
 					},
 				},
+				getPodWarningFn: func(context.Context, *corev1.Pod) (string, string) {
+					return "", ""
+				},
 				cleanupJobFn: func(_, _ string) {
 					require.Fail(
 						t,
@@ -271,6 +283,9 @@ func TestSyncJobPod(t *testing.T) {
 						return errors.New("something went wrong")
 					},
 				},
+				getPodWarningFn: func(context.Context, *corev1.Pod) (string, string) {
+					return "", ""
+				},
 				errFn: func(i ...interface{}) {
 					require.Len(t, i, 1)
 					str, ok := i[0].(string)
@@ -286,6 +301,9 @@ func TestSyncJobPod(t *testing.T) {
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      "nombre",
 					Namespace: "ns",
+					Labels: map[string]string{
+						myk8s.LabelJobAttempt: "2",
+					},
 				},
 				Spec: corev1.PodSpec{
 					Containers: []corev1.Container{{Name: "foo"}},
@@ -328,6 +346,9 @@ func TestSyncJobPod(t *testing.T) {
 						require.Equal(t, sdk.JobPhaseFailed, status.Phase)
 						require.NotNil(t, status.Ended)
 						require.Equal(t, now, *status.Ended)
+						require.NotNil(t, status.ExitCode)
+						require.Equal(t, int32(1), *status.ExitCode)
+						require.Equal(t, 2, status.Attempt)
 						return nil
 					},
 				},
@@ -68,6 +68,7 @@ type observer struct {
 	manageJobTimeoutFn    func(context.Context, *corev1.Pod, sdk.JobPhase)
 	runJobTimerFn         func(context.Context, *corev1.Pod)
 	cleanupJobFn          func(eventID, jobName string)
+	getPodWarningFn       func(context.Context, *corev1.Pod) (string, string)
 	errFn                 func(...interface{})
 	checkK8sAPIServer     func(context.Context) ([]byte, error)
 }
@@ -98,6 +99,7 @@ func newObserver(
 	o.manageJobTimeoutFn = o.manageJobTimeout
 	o.runJobTimerFn = o.runJobTimer
 	o.cleanupJobFn = o.cleanupJob
+	o.getPodWarningFn = o.getPodWarning
 	o.errFn = log.Println
 
 	// TODO: remove this type assertion once we figure out how to fake/mock
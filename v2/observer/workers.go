@@ -49,6 +49,13 @@ func (o *observer) syncWorkerPod(obj interface{}) {
 	pod := obj.(*corev1.Pod) // nolint: forcetypeassert
 	// Map pod status to worker status
 	status := o.getWorkerStatusFromPod(pod)
+	// If the pod itself doesn't explain why it's still pending, Kubernetes
+	// events might
+	if status.Reason == "" &&
+		pod.Status.Phase == corev1.PodPending &&
+		!status.Phase.IsTerminal() {
+		status.Reason, status.Message = o.getPodWarningFn(ctx, pod)
+	}
 	// Manage the timeout clock
 	o.manageWorkerTimeoutFn(ctx, pod, status.Phase)
 	// Use the API to update Worker status
@@ -134,16 +141,25 @@ func (o *observer) getWorkerStatusFromPod(pod *corev1.Pod) sdk.WorkerStatus {
 	if pod.Status.StartTime != nil {
 		status.Started = &pod.Status.StartTime.Time
 	}
-	// Determine the worker's end time based on container[0] end time
+	// Determine the worker's end time and exit code based on container[0]
 	for _, containerStatus := range pod.Status.ContainerStatuses {
 		if containerStatus.Name == pod.Spec.Containers[0].Name {
 			if containerStatus.State.Terminated != nil {
 				status.Ended =
 					&pod.Status.ContainerStatuses[0].State.Terminated.FinishedAt.Time
+				exitCode := containerStatus.State.Terminated.ExitCode
+				status.ExitCode = &exitCode
 			}
 			break
 		}
 	}
+	// Explain the worker's phase and capture details of every container,
+	// including init containers
+	if len(pod.Spec.Containers) > 0 {
+		status.Reason, status.Message =
+			getPodReason(pod, pod.Spec.Containers[0].Name)
+	}
+	status.Containers = getContainerStatuses(pod)
 	return status
 }
 
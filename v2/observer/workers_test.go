@@ -142,9 +142,18 @@ func TestSyncWorkerPod(t *testing.T) {
 						_ *sdk.WorkerStatusUpdateOptions,
 					) error {
 						require.Equal(t, sdk.WorkerPhaseRunning, status.Phase)
+						require.Equal(t, "FailedMount", status.Reason)
+						require.Equal(
+							t,
+							"Unable to attach or mount volumes",
+							status.Message,
+						)
 						return nil
 					},
 				},
+				getPodWarningFn: func(context.Context, *corev1.Pod) (string, string) {
+					return "FailedMount", "Unable to attach or mount volumes"
+				},
 				cleanupWorkerFn: func(string) {
 					require.Fail(
 						t,
@@ -0,0 +1,197 @@
+package main
+
+import (
+	"context"
+	"sync"
+)
+
+// strideUnit is the amount of "pass" a Project with a weight of one accrues
+// each time it is granted capacity. Projects with greater weights accrue
+// proportionally less.
+const strideUnit = 1 << 20
+
+// dispatcher arbitrates between Projects that are competing for the same,
+// limited capacity (e.g. the capacity to run Workers). Capacity is granted
+// using stride scheduling. Each time a Project is granted capacity, it accrues
+// "pass" in inverse proportion to its weight. Whenever capacity becomes
+// available, it is granted to the waiting Project with the lowest pass. Over
+// time, this grants capacity to Projects under contention in proportion to
+// their weights. When all weights are equal, this reduces to round-robin.
+// Priority trumps all of this, however. A Project waiting on behalf of a higher
+// priority Event is always granted capacity ahead of Projects waiting on behalf
+// of lower priority Events.
+type dispatcher struct {
+	mu sync.Mutex
+	// weights indexes Project weights by Project ID. Projects with no known
+	// weight are assumed to have a weight of one.
+	weights map[string]int
+	// passes indexes each Project's accrued pass by Project ID.
+	passes map[string]uint64
+	// waiters indexes the Projects currently waiting for capacity by Project
+	// ID.
+	waiters map[string]*waiter
+	// virtualTime is the pass of the Project most recently granted capacity.
+	// Projects that were idle are caught up to this when they begin waiting so
+	// they cannot claim capacity in excess of their share on the basis of pass
+	// they didn't accrue while they weren't competing for capacity.
+	virtualTime uint64
+	// seq is used to break ties between waiting Projects with equal pass in
+	// favor of the one that has been waiting longest.
+	seq uint64
+	// waitingCh is signaled whenever a new waiter arrives.
+	waitingCh chan struct{}
+}
+
+type waiter struct {
+	seq        uint64
+	priority   int
+	grantedCh  chan struct{}
+	releasedCh chan struct{}
+}
+
+func newDispatcher() *dispatcher {
+	return &dispatcher{
+		weights:   map[string]int{},
+		passes:    map[string]uint64{},
+		waiters:   map[string]*waiter{},
+		waitingCh: make(chan struct{}, 1),
+	}
+}
+
+// setWeight sets the weight of the specified Project. Weights less than one
+// are treated as one.
+func (d *dispatcher) setWeight(projectID string, weight int) {
+	if weight < 1 {
+		weight = 1
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.weights[projectID] = weight
+}
+
+// forget discards everything known about the specified Project.
+func (d *dispatcher) forget(projectID string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	delete(d.weights, projectID)
+	delete(d.passes, projectID)
+}
+
+// acquire blocks until the specified Project has been granted capacity on
+// behalf of an Event of the specified priority or the provided context is
+// canceled. When capacity is granted, the caller MUST invoke the returned
+// function after it has done whatever it was going to do with the capacity. If
+// the context is canceled first, an error is returned.
+func (d *dispatcher) acquire(
+	ctx context.Context,
+	projectID string,
+	priority int,
+) (func(), error) {
+	w := &waiter{
+		priority:   priority,
+		grantedCh:  make(chan struct{}),
+		releasedCh: make(chan struct{}),
+	}
+	d.mu.Lock()
+	d.seq++
+	w.seq = d.seq
+	if d.passes[projectID] < d.virtualTime {
+		d.passes[projectID] = d.virtualTime
+	}
+	d.waiters[projectID] = w
+	d.mu.Unlock()
+
+	// Nudge the dispatcher in case it's waiting for someone to arrive
+	select {
+	case d.waitingCh <- struct{}{}:
+	default:
+	}
+
+	var once sync.Once
+	release := func() {
+		once.Do(func() { close(w.releasedCh) })
+	}
+	select {
+	case <-w.grantedCh:
+		return release, nil
+	case <-ctx.Done():
+		d.mu.Lock()
+		if d.waiters[projectID] == w {
+			delete(d.waiters, projectID)
+			d.mu.Unlock()
+			return nil, ctx.Err()
+		}
+		d.mu.Unlock()
+		// Capacity was granted to us concurrently. Give it back.
+		release()
+		return nil, ctx.Err()
+	}
+}
+
+// dispatch blocks until at least one Project is waiting for capacity, grants
+// capacity to the most deserving of them, and then waits for that Project to
+// release it. It returns false if the provided context is canceled first.
+func (d *dispatcher) dispatch(ctx context.Context) bool {
+	for {
+		if w := d.grant(); w != nil {
+			select {
+			case <-w.releasedCh:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+		select {
+		case <-d.waitingCh:
+		case <-ctx.Done():
+			return false
+		}
+	}
+}
+
+// grant grants capacity to the waiting Project with the highest priority and,
+// among those, the lowest pass, if any, and returns the corresponding waiter.
+// If no Project is waiting, nil is returned.
+func (d *dispatcher) grant() *waiter {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	var nextProjectID string
+	var next *waiter
+	for projectID, w := range d.waiters {
+		if next == nil || d.isAhead(projectID, w, nextProjectID, next) {
+			nextProjectID = projectID
+			next = w
+		}
+	}
+	if next == nil {
+		return nil
+	}
+	delete(d.waiters, nextProjectID)
+	d.virtualTime = d.passes[nextProjectID]
+	weight := d.weights[nextProjectID]
+	if weight < 1 {
+		weight = 1
+	}
+	d.passes[nextProjectID] += strideUnit / uint64(weight)
+	// Closing this while still holding the lock guarantees that a waiter whose
+	// context is canceled concurrently will see that it was granted capacity.
+	close(next.grantedCh)
+	return next
+}
+
+// isAhead returns true if the first of two waiting Projects should be granted
+// capacity ahead of the second. The caller MUST hold the lock.
+func (d *dispatcher) isAhead(
+	projectID string,
+	w *waiter,
+	otherProjectID string,
+	other *waiter,
+) bool {
+	if w.priority != other.priority {
+		return w.priority > other.priority
+	}
+	if d.passes[projectID] != d.passes[otherProjectID] {
+		return d.passes[projectID] < d.passes[otherProjectID]
+	}
+	return w.seq < other.seq
+}
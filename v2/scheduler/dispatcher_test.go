@@ -0,0 +1,194 @@
+package main
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDispatcherAcquire(t *testing.T) {
+	t.Run("context canceled while waiting", func(t *testing.T) {
+		d := newDispatcher()
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		release, err := d.acquire(ctx, "foo", 0)
+		require.Error(t, err)
+		require.Nil(t, release)
+		require.Empty(t, d.waiters)
+	})
+	t.Run("capacity granted", func(t *testing.T) {
+		d := newDispatcher()
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		dispatchedCh := make(chan bool)
+		go func() {
+			dispatchedCh <- d.dispatch(ctx)
+		}()
+		release, err := d.acquire(ctx, "foo", 0)
+		require.NoError(t, err)
+		release()
+		require.True(t, <-dispatchedCh)
+	})
+}
+
+func TestDispatcherDispatch(t *testing.T) {
+	testCases := []struct {
+		name     string
+		weights  map[string]int
+		grants   int
+		expected map[string]int
+	}{
+		{
+			name: "equal weights",
+			weights: map[string]int{
+				"foo": 1,
+				"bar": 0, // Treated as 1
+				"bat": 1,
+			},
+			grants: 30,
+			expected: map[string]int{
+				"foo": 10,
+				"bar": 10,
+				"bat": 10,
+			},
+		},
+		{
+			name: "unequal weights",
+			weights: map[string]int{
+				"foo": 3,
+				"bar": 1,
+			},
+			grants: 40,
+			expected: map[string]int{
+				"foo": 30,
+				"bar": 10,
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			d := newDispatcher()
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			mu := sync.Mutex{}
+			grants := map[string]int{}
+			for projectID, weight := range testCase.weights {
+				d.setWeight(projectID, weight)
+				go func(projectID string) {
+					for {
+						release, err := d.acquire(ctx, projectID, 0)
+						if err != nil {
+							return
+						}
+						mu.Lock()
+						grants[projectID]++
+						mu.Unlock()
+						release()
+					}
+				}(projectID)
+			}
+			for i := 0; i < testCase.grants; i++ {
+				// Wait until every Project is waiting so that the outcome doesn't
+				// depend on goroutine scheduling
+				requireWaiters(t, d, len(testCase.weights))
+				require.True(t, d.dispatch(ctx))
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			require.Equal(t, testCase.expected, grants)
+		})
+	}
+}
+
+func TestDispatcherPriority(t *testing.T) {
+	d := newDispatcher()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	// foo has a much greater weight, but bar is waiting on behalf of a higher
+	// priority Event
+	d.setWeight("foo", 100)
+	d.setWeight("bar", 1)
+	grantedCh := make(chan string)
+	for projectID, priority := range map[string]int{"foo": 0, "bar": 5} {
+		go func(projectID string, priority int) {
+			for {
+				release, err := d.acquire(ctx, projectID, priority)
+				if err != nil {
+					return
+				}
+				grantedCh <- projectID
+				release()
+			}
+		}(projectID, priority)
+	}
+	for i := 0; i < 3; i++ {
+		requireWaiters(t, d, 2)
+		go d.dispatch(ctx)
+		require.Equal(t, "bar", <-grantedCh)
+	}
+}
+
+func TestDispatcherIdleProjectsDoNotAccrueCredit(t *testing.T) {
+	d := newDispatcher()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	// Grant foo capacity many times while bar is idle
+	go func() {
+		for {
+			release, err := d.acquire(ctx, "foo", 0)
+			if err != nil {
+				return
+			}
+			release()
+		}
+	}()
+	for i := 0; i < 10; i++ {
+		requireWaiters(t, d, 1)
+		require.True(t, d.dispatch(ctx))
+	}
+	// Now bar begins competing. It should be granted capacity first, but not
+	// ten times in a row.
+	grantedCh := make(chan string)
+	go func() {
+		for {
+			release, err := d.acquire(ctx, "bar", 0)
+			if err != nil {
+				return
+			}
+			grantedCh <- "bar"
+			release()
+		}
+	}()
+	requireWaiters(t, d, 2)
+	go d.dispatch(ctx)
+	require.Equal(t, "bar", <-grantedCh)
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	require.LessOrEqual(t, d.passes["bar"]-d.passes["foo"], uint64(strideUnit))
+}
+
+func TestDispatcherForget(t *testing.T) {
+	d := newDispatcher()
+	d.setWeight("foo", 2)
+	d.passes["foo"] = 42
+	d.forget("foo")
+	require.NotContains(t, d.weights, "foo")
+	require.NotContains(t, d.passes, "foo")
+}
+
+func requireWaiters(t *testing.T, d *dispatcher, count int) {
+	require.Eventually(
+		t,
+		func() bool {
+			d.mu.Lock()
+			defer d.mu.Unlock()
+			return len(d.waiters) == count
+		},
+		time.Second,
+		time.Millisecond,
+	)
+}
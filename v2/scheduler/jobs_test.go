@@ -18,7 +18,7 @@ func TestManageJobsCapacity(t *testing.T) {
 		scheduler  *scheduler
 		assertions func(
 			ctx context.Context,
-			jobAvailabilityCh chan struct{},
+			acquiredCh <-chan struct{},
 			errCh chan error,
 		)
 	}{
@@ -36,16 +36,16 @@ func TestManageJobsCapacity(t *testing.T) {
 						return sdk.SubstrateJobCount{}, errors.New("something went wrong")
 					},
 				},
-				jobAvailabilityCh: make(chan struct{}),
-				errCh:             make(chan error),
+				jobDispatcher: newDispatcher(),
+				errCh:         make(chan error),
 			},
 			assertions: func(
 				ctx context.Context,
-				jobAvailabilityCh chan struct{},
+				acquiredCh <-chan struct{},
 				errCh chan error,
 			) {
 				select {
-				case <-jobAvailabilityCh:
+				case <-acquiredCh:
 					require.Fail(
 						t,
 						"notified of available capacity when we should have received "+
@@ -75,16 +75,16 @@ func TestManageJobsCapacity(t *testing.T) {
 						}, nil
 					},
 				},
-				jobAvailabilityCh: make(chan struct{}),
-				errCh:             make(chan error),
+				jobDispatcher: newDispatcher(),
+				errCh:         make(chan error),
 			},
 			assertions: func(
 				ctx context.Context,
-				jobAvailabilityCh chan struct{},
+				acquiredCh <-chan struct{},
 				errCh chan error,
 			) {
 				select {
-				case <-jobAvailabilityCh:
+				case <-acquiredCh:
 					require.Fail(t, "notified of available capacity when none existed")
 				case <-errCh:
 					require.Fail(t, "received unexpected error")
@@ -108,18 +108,16 @@ func TestManageJobsCapacity(t *testing.T) {
 						}, nil
 					},
 				},
-				jobAvailabilityCh: make(chan struct{}),
-				errCh:             make(chan error),
+				jobDispatcher: newDispatcher(),
+				errCh:         make(chan error),
 			},
 			assertions: func(
 				ctx context.Context,
-				jobAvailabilityCh chan struct{},
+				acquiredCh <-chan struct{},
 				errCh chan error,
 			) {
 				select {
-				case <-jobAvailabilityCh:
-					// Signal back to the capacity manager
-					jobAvailabilityCh <- struct{}{}
+				case <-acquiredCh:
 				case <-errCh:
 					require.Fail(t, "received unexpected error")
 				case <-ctx.Done():
@@ -132,9 +130,18 @@ func TestManageJobsCapacity(t *testing.T) {
 		t.Run(testCase.name, func(t *testing.T) {
 			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 			go testCase.scheduler.manageJobCapacity(ctx)
+			dispatcher := testCase.scheduler.jobDispatcher
+			acquiredCh := make(chan struct{})
+			go func() {
+				release, err := dispatcher.acquire(ctx, "foo", 0)
+				if err == nil {
+					close(acquiredCh)
+					release()
+				}
+			}()
 			testCase.assertions(
 				ctx,
-				testCase.scheduler.jobAvailabilityCh,
+				acquiredCh,
 				testCase.scheduler.errCh,
 			)
 			cancel()
@@ -391,20 +398,139 @@ func TestRunJobLoop(t *testing.T) {
 			},
 		},
 
+		{
+			name: "job dependencies have not succeeded",
+			setup: func(_ context.Context, cancelFn func()) *scheduler {
+				return &scheduler{
+					queueReaderFactory: &mockQueueReaderFactory{
+						NewReaderFn: func(queueName string) (queue.Reader, error) {
+							return &mockQueueReader{
+								ReadFn: func(c context.Context) (*queue.Message, error) {
+									return &queue.Message{
+										Message: "foo:bar",
+										Ack: func(context.Context) error {
+											return nil
+										},
+									}, nil
+								},
+								CloseFn: func(c context.Context) error {
+									return nil
+								},
+							}, nil
+						},
+					},
+					eventsClient: &coreTesting.MockEventsClient{
+						GetFn: func(
+							context.Context,
+							string,
+							*sdk.EventGetOptions,
+						) (sdk.Event, error) {
+							cancelFn()
+							return sdk.Event{
+								Worker: &sdk.Worker{
+									Jobs: []sdk.Job{
+										{
+											Name: "foo",
+											Status: &sdk.JobStatus{
+												Phase: sdk.JobPhaseRunning,
+											},
+										},
+										{
+											Name: "bar",
+											Spec: sdk.JobSpec{
+												DependsOn: []string{"foo"},
+											},
+											Status: &sdk.JobStatus{
+												Phase: sdk.JobPhasePending,
+											},
+										},
+									},
+								},
+							}, nil
+						},
+					},
+					jobLoopErrFn: func(i ...interface{}) {
+						require.Fail(
+							t,
+							"error logging function should not have been called",
+						)
+						cancelFn()
+					},
+				}
+			},
+			assertions: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+
+		{
+			name: "error checking project capacity",
+			setup: func(_ context.Context, cancelFn func()) *scheduler {
+				return &scheduler{
+					queueReaderFactory: &mockQueueReaderFactory{
+						NewReaderFn: func(queueName string) (queue.Reader, error) {
+							return &mockQueueReader{
+								ReadFn: func(c context.Context) (*queue.Message, error) {
+									return &queue.Message{
+										Message: "foo:bar",
+										Ack: func(context.Context) error {
+											return nil
+										},
+									}, nil
+								},
+								CloseFn: func(c context.Context) error {
+									return nil
+								},
+							}, nil
+						},
+					},
+					eventsClient: &coreTesting.MockEventsClient{
+						GetFn: func(
+							context.Context,
+							string,
+							*sdk.EventGetOptions,
+						) (sdk.Event, error) {
+							return sdk.Event{
+								Worker: &sdk.Worker{
+									Jobs: []sdk.Job{
+										{
+											Name: "bar",
+											Status: &sdk.JobStatus{
+												Phase: sdk.JobPhasePending,
+											},
+										},
+									},
+								},
+							}, nil
+						},
+					},
+					projectsClient: &coreTesting.MockProjectsClient{
+						GetFn: func(
+							context.Context,
+							string,
+							*sdk.ProjectGetOptions,
+						) (sdk.Project, error) {
+							return sdk.Project{}, errors.New("something went wrong")
+						},
+					},
+					jobLoopErrFn: func(i ...interface{}) {
+						err, ok := i[0].(error)
+						require.True(t, ok)
+						require.Equal(t, err.Error(), "something went wrong")
+						cancelFn()
+					},
+				}
+			},
+			assertions: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+
 		{
 			name: "error starting job",
 			setup: func(ctx context.Context, cancelFn func()) *scheduler {
-				jobAvailabilityCh := make(chan struct{})
-				go func() {
-					select {
-					case jobAvailabilityCh <- struct{}{}:
-					case <-ctx.Done():
-					}
-					select {
-					case <-jobAvailabilityCh:
-					case <-ctx.Done():
-					}
-				}()
+				jobDispatcher := newDispatcher()
+				go jobDispatcher.dispatch(ctx)
 				return &scheduler{
 					queueReaderFactory: &mockQueueReaderFactory{
 						NewReaderFn: func(queueName string) (queue.Reader, error) {
@@ -443,7 +569,16 @@ func TestRunJobLoop(t *testing.T) {
 							}, nil
 						},
 					},
-					jobAvailabilityCh: jobAvailabilityCh,
+					projectsClient: &coreTesting.MockProjectsClient{
+						GetFn: func(
+							context.Context,
+							string,
+							*sdk.ProjectGetOptions,
+						) (sdk.Project, error) {
+							return sdk.Project{}, nil // No project-level limit
+						},
+					},
+					jobDispatcher: jobDispatcher,
 					jobsClient: &coreTesting.MockJobsClient{
 						StartFn: func(
 							context.Context,
@@ -470,17 +605,8 @@ func TestRunJobLoop(t *testing.T) {
 		{
 			name: "success",
 			setup: func(ctx context.Context, cancelFn func()) *scheduler {
-				jobAvailabilityCh := make(chan struct{})
-				go func() {
-					select {
-					case jobAvailabilityCh <- struct{}{}:
-					case <-ctx.Done():
-					}
-					select {
-					case <-jobAvailabilityCh:
-					case <-ctx.Done():
-					}
-				}()
+				jobDispatcher := newDispatcher()
+				go jobDispatcher.dispatch(ctx)
 				return &scheduler{
 					queueReaderFactory: &mockQueueReaderFactory{
 						NewReaderFn: func(queueName string) (queue.Reader, error) {
@@ -519,7 +645,16 @@ func TestRunJobLoop(t *testing.T) {
 							}, nil
 						},
 					},
-					jobAvailabilityCh: jobAvailabilityCh,
+					projectsClient: &coreTesting.MockProjectsClient{
+						GetFn: func(
+							context.Context,
+							string,
+							*sdk.ProjectGetOptions,
+						) (sdk.Project, error) {
+							return sdk.Project{}, nil // No project-level limit
+						},
+					},
+					jobDispatcher: jobDispatcher,
 					jobsClient: &coreTesting.MockJobsClient{
 						StartFn: func(
 							context.Context,
@@ -563,3 +698,161 @@ func TestRunJobLoop(t *testing.T) {
 		})
 	}
 }
+
+func TestWaitForProjectJobCapacity(t *testing.T) {
+	const testProject = "manhattan"
+	testCases := []struct {
+		name       string
+		scheduler  *scheduler
+		assertions func(error)
+	}{
+		{
+			name: "error getting project",
+			scheduler: &scheduler{
+				projectsClient: &coreTesting.MockProjectsClient{
+					GetFn: func(
+						context.Context,
+						string,
+						*sdk.ProjectGetOptions,
+					) (sdk.Project, error) {
+						return sdk.Project{}, errors.New("something went wrong")
+					},
+				},
+			},
+			assertions: func(err error) {
+				require.Error(t, err)
+				require.Equal(t, "something went wrong", err.Error())
+			},
+		},
+		{
+			name: "project has no limit",
+			scheduler: &scheduler{
+				projectsClient: &coreTesting.MockProjectsClient{
+					GetFn: func(
+						context.Context,
+						string,
+						*sdk.ProjectGetOptions,
+					) (sdk.Project, error) {
+						return sdk.Project{}, nil
+					},
+				},
+				substrateClient: &coreTesting.MockSubstrateClient{
+					CountRunningJobsFn: func(
+						context.Context,
+						*sdk.RunningJobCountOptions,
+					) (sdk.SubstrateJobCount, error) {
+						require.Fail(t, "running jobs should not have been counted")
+						return sdk.SubstrateJobCount{}, nil
+					},
+				},
+			},
+			assertions: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "error counting running jobs",
+			scheduler: &scheduler{
+				projectsClient: &coreTesting.MockProjectsClient{
+					GetFn: func(
+						context.Context,
+						string,
+						*sdk.ProjectGetOptions,
+					) (sdk.Project, error) {
+						return sdk.Project{
+							Spec: sdk.ProjectSpec{
+								MaxConcurrentJobs: 2,
+							},
+						}, nil
+					},
+				},
+				substrateClient: &coreTesting.MockSubstrateClient{
+					CountRunningJobsFn: func(
+						context.Context,
+						*sdk.RunningJobCountOptions,
+					) (sdk.SubstrateJobCount, error) {
+						return sdk.SubstrateJobCount{}, errors.New("something went wrong")
+					},
+				},
+			},
+			assertions: func(err error) {
+				require.Error(t, err)
+				require.Equal(t, "something went wrong", err.Error())
+			},
+		},
+		{
+			name: "capacity available",
+			scheduler: &scheduler{
+				projectsClient: &coreTesting.MockProjectsClient{
+					GetFn: func(
+						context.Context,
+						string,
+						*sdk.ProjectGetOptions,
+					) (sdk.Project, error) {
+						return sdk.Project{
+							Spec: sdk.ProjectSpec{
+								MaxConcurrentJobs: 2,
+							},
+						}, nil
+					},
+				},
+				substrateClient: &coreTesting.MockSubstrateClient{
+					CountRunningJobsFn: func(
+						_ context.Context,
+						opts *sdk.RunningJobCountOptions,
+					) (sdk.SubstrateJobCount, error) {
+						require.Equal(t, testProject, opts.ProjectID)
+						return sdk.SubstrateJobCount{
+							Count: 1,
+						}, nil
+					},
+				},
+			},
+			assertions: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "no capacity available",
+			scheduler: &scheduler{
+				projectsClient: &coreTesting.MockProjectsClient{
+					GetFn: func(
+						context.Context,
+						string,
+						*sdk.ProjectGetOptions,
+					) (sdk.Project, error) {
+						return sdk.Project{
+							Spec: sdk.ProjectSpec{
+								MaxConcurrentJobs: 2,
+							},
+						}, nil
+					},
+				},
+				substrateClient: &coreTesting.MockSubstrateClient{
+					CountRunningJobsFn: func(
+						context.Context,
+						*sdk.RunningJobCountOptions,
+					) (sdk.SubstrateJobCount, error) {
+						return sdk.SubstrateJobCount{
+							Count: 2,
+						}, nil
+					},
+				},
+			},
+			assertions: func(err error) {
+				// We should have kept waiting until the context timed out
+				require.Error(t, err)
+				require.Equal(t, context.DeadlineExceeded, err)
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+			testCase.assertions(
+				testCase.scheduler.waitForProjectJobCapacity(ctx, testProject),
+			)
+		})
+	}
+}
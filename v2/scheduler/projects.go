@@ -33,6 +33,9 @@ func (s *scheduler) manageProjects(ctx context.Context) {
 			}
 			for _, project := range projects.Items {
 				currentProjects[project.ID] = struct{}{}
+				// Keep scheduling weights current
+				s.workerDispatcher.setWeight(project.ID, project.Spec.SchedulingWeight)
+				s.jobDispatcher.setWeight(project.ID, project.Spec.SchedulingWeight)
 			}
 			if projects.RemainingItemCount > 0 {
 				listOpts.Continue = projects.Continue
@@ -53,6 +56,8 @@ func (s *scheduler) manageProjects(ctx context.Context) {
 				)
 				cancelFn()
 				delete(loopCancelFns, projectID)
+				s.workerDispatcher.forget(projectID)
+				s.jobDispatcher.forget(projectID)
 			}
 		}
 
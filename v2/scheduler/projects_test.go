@@ -25,6 +25,8 @@ func TestManageProjects(t *testing.T) {
 					config: schedulerConfig{
 						addAndRemoveProjectsInterval: time.Second,
 					},
+					workerDispatcher: newDispatcher(),
+					jobDispatcher:    newDispatcher(),
 					projectsClient: &coreTesting.MockProjectsClient{
 						ListFn: func(
 							context.Context,
@@ -49,6 +51,8 @@ func TestManageProjects(t *testing.T) {
 					config: schedulerConfig{
 						addAndRemoveProjectsInterval: time.Second,
 					},
+					workerDispatcher: newDispatcher(),
+					jobDispatcher:    newDispatcher(),
 					projectsClient: &coreTesting.MockProjectsClient{
 						ListFn: func(
 							context.Context,
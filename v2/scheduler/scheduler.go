@@ -44,15 +44,16 @@ func getSchedulerConfig() (schedulerConfig, error) {
 }
 
 type scheduler struct {
-	queueReaderFactory   queue.ReaderFactory
-	projectsClient       sdk.ProjectsClient
-	substrateClient      sdk.SubstrateClient
-	eventsClient         sdk.EventsClient
-	workersClient        sdk.WorkersClient
-	jobsClient           sdk.JobsClient
-	config               schedulerConfig
-	workerAvailabilityCh chan struct{}
-	jobAvailabilityCh    chan struct{}
+	queueReaderFactory queue.ReaderFactory
+	projectsClient     sdk.ProjectsClient
+	substrateClient    sdk.SubstrateClient
+	eventsClient       sdk.EventsClient
+	workersClient      sdk.WorkersClient
+	jobsClient         sdk.JobsClient
+	config             schedulerConfig
+	// These arbitrate between Projects competing for available capacity
+	workerDispatcher *dispatcher
+	jobDispatcher    *dispatcher
 	// All of the scheduler's goroutines will send fatal errors here
 	errCh chan error
 	// All of these internal functions are overridable for testing purposes
@@ -72,16 +73,16 @@ func newScheduler(
 	config schedulerConfig,
 ) *scheduler {
 	s := &scheduler{
-		queueReaderFactory:   queueReaderFactory,
-		config:               config,
-		projectsClient:       coreClient.Projects(),
-		substrateClient:      coreClient.Substrate(),
-		eventsClient:         coreClient.Events(),
-		workersClient:        coreClient.Events().Workers(),
-		jobsClient:           coreClient.Events().Workers().Jobs(),
-		workerAvailabilityCh: make(chan struct{}),
-		jobAvailabilityCh:    make(chan struct{}),
-		errCh:                make(chan error),
+		queueReaderFactory: queueReaderFactory,
+		config:             config,
+		projectsClient:     coreClient.Projects(),
+		substrateClient:    coreClient.Substrate(),
+		eventsClient:       coreClient.Events(),
+		workersClient:      coreClient.Events().Workers(),
+		jobsClient:         coreClient.Events().Workers().Jobs(),
+		workerDispatcher:   newDispatcher(),
+		jobDispatcher:      newDispatcher(),
+		errCh:              make(chan error),
 	}
 	s.manageWorkerCapacityFn = s.manageWorkerCapacity
 	s.workerLoopErrFn = log.Println
@@ -103,8 +103,8 @@ func TestNewScheduler(t *testing.T) {
 	require.NotNil(t, scheduler.workersClient)
 	require.NotNil(t, scheduler.jobsClient)
 	require.Equal(t, config, scheduler.config)
-	require.NotNil(t, scheduler.workerAvailabilityCh)
-	require.NotNil(t, scheduler.jobAvailabilityCh)
+	require.NotNil(t, scheduler.workerDispatcher)
+	require.NotNil(t, scheduler.jobDispatcher)
 	require.NotNil(t, scheduler.errCh)
 }
 
@@ -47,25 +47,60 @@ func (s *scheduler) manageWorkerCapacity(ctx context.Context) {
 			return
 		}
 
-		// Offer capacity to anyone who's ready for it
-		select {
-		case s.workerAvailabilityCh <- struct{}{}:
-		case <-ctx.Done():
-			return
-		}
-
-		// Wait to hear that whoever received the capacity has done whatever they
-		// were going to do. This helps prevent a race where we loop around and
-		// start looking for capacity and maybe find some that someone else is about
-		// to claim.
-		select {
-		case <-s.workerAvailabilityCh:
-		case <-ctx.Done():
+		// Grant the capacity to whichever waiting Project is most deserving of it
+		// and wait to hear that it has done whatever it was going to do with it.
+		// This helps prevent a race where we loop around and start looking for
+		// capacity and maybe find some that someone else is about to claim.
+		if !s.workerDispatcher.dispatch(ctx) {
 			return
 		}
 	}
 }
 
+// waitForProjectWorkerCapacity blocks until the number of the specified
+// Project's Workers currently running on the substrate is below the Project's
+// own limit. It returns immediately if the Project doesn't specify a limit.
+func (s *scheduler) waitForProjectWorkerCapacity(
+	ctx context.Context,
+	projectID string,
+) error {
+	// Use a progressive backoff, capped at 10 seconds between retries.
+	return retries.ManageRetries(
+		ctx,
+		fmt.Sprintf("find worker capacity for project %q", projectID),
+		0,              // Infinite retries
+		10*time.Second, // Max backoff
+		func() (bool, error) {
+			select {
+			case <-ctx.Done():
+				return false, nil // Stop looking
+			default:
+			}
+			// Re-retrieve the Project each time in case its limit has changed
+			project, err := s.projectsClient.Get(ctx, projectID, nil)
+			if err != nil {
+				return false, err // Real error; stop retrying
+			}
+			if project.Spec.MaxConcurrentWorkers <= 0 {
+				return false, nil // No project-level limit; stop looking
+			}
+			count, err := s.substrateClient.CountRunningWorkers(
+				ctx,
+				&sdk.RunningWorkerCountOptions{
+					ProjectID: projectID,
+				},
+			)
+			if err != nil {
+				return false, err // Real error; stop retrying
+			}
+			if count.Count < project.Spec.MaxConcurrentWorkers {
+				return false, nil // Found capacity; stop looking
+			}
+			return true, nil // Keep looking
+		},
+	)
+}
+
 // nolint: gocyclo
 func (s *scheduler) runWorkerLoop(ctx context.Context, projectID string) {
 
@@ -149,16 +184,25 @@ outerLoop:
 				continue // Next message
 			}
 
-			// Wait for capacity
-			select {
-			case <-s.workerAvailabilityCh:
-			case <-ctx.Done():
-				continue outerLoop // This will do cleanup before returning
+			// Wait for PROJECT capacity. We do this BEFORE claiming any of the global
+			// capacity so that a Project that's at its own limit doesn't tie up
+			// capacity that other Projects could be using.
+			if err := s.waitForProjectWorkerCapacity(ctx, projectID); err != nil {
+				if ctx.Err() == nil { // Don't report errors caused by shutting down
+					s.workerLoopErrFn(err)
+				}
+				// Don't ack the message. It will be redelivered to the new reader.
+				continue outerLoop
 			}
 
-			// TODO: In the future, it would be nice to be able to block on PROJECT
-			// capacity as well. i.e. Max of n concurrent workers per project. I
-			// (krancour) see this as a post-2.0 enhancement.
+			// Wait for global capacity. When Projects are competing for it, it is
+			// granted to those with higher priority Events first and is otherwise
+			// shared between them in proportion to their scheduling weights.
+			release, err :=
+				s.workerDispatcher.acquire(ctx, projectID, event.Priority)
+			if err != nil {
+				continue outerLoop // This will do cleanup before returning
+			}
 
 			// Now use the API to start the Worker...
 
@@ -171,11 +215,7 @@ outerLoop:
 			}
 
 			// Tell the capacity manager we used the capacity it gave us
-			select {
-			case s.workerAvailabilityCh <- struct{}{}:
-			case <-ctx.Done():
-				continue outerLoop // This will do cleanup before returning
-			}
+			release()
 		}
 
 	}
@@ -18,7 +18,7 @@ func TestManageWorkerCapacity(t *testing.T) {
 		scheduler  *scheduler
 		assertions func(
 			ctx context.Context,
-			workerAvailabilityCh chan struct{},
+			acquiredCh <-chan struct{},
 			errCh chan error,
 		)
 	}{
@@ -37,16 +37,16 @@ func TestManageWorkerCapacity(t *testing.T) {
 							errors.New("something went wrong")
 					},
 				},
-				workerAvailabilityCh: make(chan struct{}),
-				errCh:                make(chan error),
+				workerDispatcher: newDispatcher(),
+				errCh:            make(chan error),
 			},
 			assertions: func(
 				ctx context.Context,
-				workerAvailabilityCh chan struct{},
+				acquiredCh <-chan struct{},
 				errCh chan error,
 			) {
 				select {
-				case <-workerAvailabilityCh:
+				case <-acquiredCh:
 					require.Fail(
 						t,
 						"notified of available capacity when we should have received "+
@@ -76,16 +76,16 @@ func TestManageWorkerCapacity(t *testing.T) {
 						}, nil
 					},
 				},
-				workerAvailabilityCh: make(chan struct{}),
-				errCh:                make(chan error),
+				workerDispatcher: newDispatcher(),
+				errCh:            make(chan error),
 			},
 			assertions: func(
 				ctx context.Context,
-				workerAvailabilityCh chan struct{},
+				acquiredCh <-chan struct{},
 				errCh chan error,
 			) {
 				select {
-				case <-workerAvailabilityCh:
+				case <-acquiredCh:
 					require.Fail(t, "notified of available capacity when none existed")
 				case <-errCh:
 					require.Fail(t, "received unexpected error")
@@ -109,18 +109,16 @@ func TestManageWorkerCapacity(t *testing.T) {
 						}, nil
 					},
 				},
-				workerAvailabilityCh: make(chan struct{}),
-				errCh:                make(chan error),
+				workerDispatcher: newDispatcher(),
+				errCh:            make(chan error),
 			},
 			assertions: func(
 				ctx context.Context,
-				workerAvailabilityCh chan struct{},
+				acquiredCh <-chan struct{},
 				errCh chan error,
 			) {
 				select {
-				case <-workerAvailabilityCh:
-					// Signal back to the capacity manager
-					workerAvailabilityCh <- struct{}{}
+				case <-acquiredCh:
 				case <-errCh:
 					require.Fail(t, "received unexpected error")
 				case <-ctx.Done():
@@ -133,9 +131,18 @@ func TestManageWorkerCapacity(t *testing.T) {
 		t.Run(testCase.name, func(t *testing.T) {
 			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 			go testCase.scheduler.manageWorkerCapacity(ctx)
+			dispatcher := testCase.scheduler.workerDispatcher
+			acquiredCh := make(chan struct{})
+			go func() {
+				release, err := dispatcher.acquire(ctx, "foo", 0)
+				if err == nil {
+					close(acquiredCh)
+					release()
+				}
+			}()
 			testCase.assertions(
 				ctx,
-				testCase.scheduler.workerAvailabilityCh,
+				acquiredCh,
 				testCase.scheduler.errCh,
 			)
 			cancel()
@@ -304,20 +311,68 @@ func TestRunWorkerLoop(t *testing.T) {
 			},
 		},
 
+		{
+			name: "error checking project capacity",
+			setup: func(_ context.Context, cancelFn func()) *scheduler {
+				return &scheduler{
+					queueReaderFactory: &mockQueueReaderFactory{
+						NewReaderFn: func(queueName string) (queue.Reader, error) {
+							return &mockQueueReader{
+								ReadFn: func(c context.Context) (*queue.Message, error) {
+									return &queue.Message{
+										Ack: func(context.Context) error {
+											return nil
+										},
+									}, nil
+								},
+								CloseFn: func(c context.Context) error {
+									return nil
+								},
+							}, nil
+						},
+					},
+					eventsClient: &coreTesting.MockEventsClient{
+						GetFn: func(
+							context.Context,
+							string,
+							*sdk.EventGetOptions,
+						) (sdk.Event, error) {
+							return sdk.Event{
+								Worker: &sdk.Worker{
+									Status: sdk.WorkerStatus{
+										Phase: sdk.WorkerPhasePending,
+									},
+								},
+							}, nil
+						},
+					},
+					projectsClient: &coreTesting.MockProjectsClient{
+						GetFn: func(
+							context.Context,
+							string,
+							*sdk.ProjectGetOptions,
+						) (sdk.Project, error) {
+							return sdk.Project{}, errors.New("something went wrong")
+						},
+					},
+					workerLoopErrFn: func(i ...interface{}) {
+						err, ok := i[0].(error)
+						require.True(t, ok)
+						require.Equal(t, err.Error(), "something went wrong")
+						cancelFn()
+					},
+				}
+			},
+			assertions: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+
 		{
 			name: "error starting worker",
 			setup: func(ctx context.Context, cancelFn func()) *scheduler {
-				workerAvailabilityCh := make(chan struct{})
-				go func() {
-					select {
-					case workerAvailabilityCh <- struct{}{}:
-					case <-ctx.Done():
-					}
-					select {
-					case <-workerAvailabilityCh:
-					case <-ctx.Done():
-					}
-				}()
+				workerDispatcher := newDispatcher()
+				go workerDispatcher.dispatch(ctx)
 				return &scheduler{
 					queueReaderFactory: &mockQueueReaderFactory{
 						NewReaderFn: func(queueName string) (queue.Reader, error) {
@@ -350,7 +405,16 @@ func TestRunWorkerLoop(t *testing.T) {
 							}, nil
 						},
 					},
-					workerAvailabilityCh: workerAvailabilityCh,
+					projectsClient: &coreTesting.MockProjectsClient{
+						GetFn: func(
+							context.Context,
+							string,
+							*sdk.ProjectGetOptions,
+						) (sdk.Project, error) {
+							return sdk.Project{}, nil // No project-level limit
+						},
+					},
+					workerDispatcher: workerDispatcher,
 					workersClient: &coreTesting.MockWorkersClient{
 						StartFn: func(
 							context.Context,
@@ -376,17 +440,8 @@ func TestRunWorkerLoop(t *testing.T) {
 		{
 			name: "success",
 			setup: func(ctx context.Context, cancelFn func()) *scheduler {
-				workerAvailabilityCh := make(chan struct{})
-				go func() {
-					select {
-					case workerAvailabilityCh <- struct{}{}:
-					case <-ctx.Done():
-					}
-					select {
-					case <-workerAvailabilityCh:
-					case <-ctx.Done():
-					}
-				}()
+				workerDispatcher := newDispatcher()
+				go workerDispatcher.dispatch(ctx)
 				return &scheduler{
 					queueReaderFactory: &mockQueueReaderFactory{
 						NewReaderFn: func(queueName string) (queue.Reader, error) {
@@ -419,7 +474,16 @@ func TestRunWorkerLoop(t *testing.T) {
 							}, nil
 						},
 					},
-					workerAvailabilityCh: workerAvailabilityCh,
+					projectsClient: &coreTesting.MockProjectsClient{
+						GetFn: func(
+							context.Context,
+							string,
+							*sdk.ProjectGetOptions,
+						) (sdk.Project, error) {
+							return sdk.Project{}, nil // No project-level limit
+						},
+					},
+					workerDispatcher: workerDispatcher,
 					workersClient: &coreTesting.MockWorkersClient{
 						StartFn: func(
 							context.Context,
@@ -462,3 +526,161 @@ func TestRunWorkerLoop(t *testing.T) {
 		})
 	}
 }
+
+func TestWaitForProjectWorkerCapacity(t *testing.T) {
+	const testProject = "manhattan"
+	testCases := []struct {
+		name       string
+		scheduler  *scheduler
+		assertions func(error)
+	}{
+		{
+			name: "error getting project",
+			scheduler: &scheduler{
+				projectsClient: &coreTesting.MockProjectsClient{
+					GetFn: func(
+						context.Context,
+						string,
+						*sdk.ProjectGetOptions,
+					) (sdk.Project, error) {
+						return sdk.Project{}, errors.New("something went wrong")
+					},
+				},
+			},
+			assertions: func(err error) {
+				require.Error(t, err)
+				require.Equal(t, "something went wrong", err.Error())
+			},
+		},
+		{
+			name: "project has no limit",
+			scheduler: &scheduler{
+				projectsClient: &coreTesting.MockProjectsClient{
+					GetFn: func(
+						context.Context,
+						string,
+						*sdk.ProjectGetOptions,
+					) (sdk.Project, error) {
+						return sdk.Project{}, nil
+					},
+				},
+				substrateClient: &coreTesting.MockSubstrateClient{
+					CountRunningWorkersFn: func(
+						context.Context,
+						*sdk.RunningWorkerCountOptions,
+					) (sdk.SubstrateWorkerCount, error) {
+						require.Fail(t, "running workers should not have been counted")
+						return sdk.SubstrateWorkerCount{}, nil
+					},
+				},
+			},
+			assertions: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "error counting running workers",
+			scheduler: &scheduler{
+				projectsClient: &coreTesting.MockProjectsClient{
+					GetFn: func(
+						context.Context,
+						string,
+						*sdk.ProjectGetOptions,
+					) (sdk.Project, error) {
+						return sdk.Project{
+							Spec: sdk.ProjectSpec{
+								MaxConcurrentWorkers: 2,
+							},
+						}, nil
+					},
+				},
+				substrateClient: &coreTesting.MockSubstrateClient{
+					CountRunningWorkersFn: func(
+						context.Context,
+						*sdk.RunningWorkerCountOptions,
+					) (sdk.SubstrateWorkerCount, error) {
+						return sdk.SubstrateWorkerCount{}, errors.New("something went wrong")
+					},
+				},
+			},
+			assertions: func(err error) {
+				require.Error(t, err)
+				require.Equal(t, "something went wrong", err.Error())
+			},
+		},
+		{
+			name: "capacity available",
+			scheduler: &scheduler{
+				projectsClient: &coreTesting.MockProjectsClient{
+					GetFn: func(
+						context.Context,
+						string,
+						*sdk.ProjectGetOptions,
+					) (sdk.Project, error) {
+						return sdk.Project{
+							Spec: sdk.ProjectSpec{
+								MaxConcurrentWorkers: 2,
+							},
+						}, nil
+					},
+				},
+				substrateClient: &coreTesting.MockSubstrateClient{
+					CountRunningWorkersFn: func(
+						_ context.Context,
+						opts *sdk.RunningWorkerCountOptions,
+					) (sdk.SubstrateWorkerCount, error) {
+						require.Equal(t, testProject, opts.ProjectID)
+						return sdk.SubstrateWorkerCount{
+							Count: 1,
+						}, nil
+					},
+				},
+			},
+			assertions: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "no capacity available",
+			scheduler: &scheduler{
+				projectsClient: &coreTesting.MockProjectsClient{
+					GetFn: func(
+						context.Context,
+						string,
+						*sdk.ProjectGetOptions,
+					) (sdk.Project, error) {
+						return sdk.Project{
+							Spec: sdk.ProjectSpec{
+								MaxConcurrentWorkers: 2,
+							},
+						}, nil
+					},
+				},
+				substrateClient: &coreTesting.MockSubstrateClient{
+					CountRunningWorkersFn: func(
+						context.Context,
+						*sdk.RunningWorkerCountOptions,
+					) (sdk.SubstrateWorkerCount, error) {
+						return sdk.SubstrateWorkerCount{
+							Count: 2,
+						}, nil
+					},
+				},
+			},
+			assertions: func(err error) {
+				// We should have kept waiting until the context timed out
+				require.Error(t, err)
+				require.Equal(t, context.DeadlineExceeded, err)
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+			testCase.assertions(
+				testCase.scheduler.waitForProjectWorkerCapacity(ctx, testProject),
+			)
+		})
+	}
+}